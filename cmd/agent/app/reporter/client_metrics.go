@@ -58,6 +58,39 @@ type clientMetrics struct {
 	FailedToEmitSpans metrics.Counter `metric:"spans_dropped" tags:"cause=send-failure"`
 }
 
+// RetryBufferMetrics are maintained by the reporters which buffer the batches until the collector accepts them.
+type RetryBufferMetrics struct {
+	BufferedBatches metrics.Gauge `metric:"buffered_batches" help:"Number of batches held in memory until the collector accepts them"`
+	BufferedSpans   metrics.Gauge `metric:"buffered_spans" help:"Number of spans held in memory until the collector accepts them"`
+
+	// Total count of batches sent again after the collector failed to accept them.
+	BatchesRetried metrics.Counter `metric:"batches_retried" help:"Total count of batches sent again after a failure"`
+
+	// Total count of batches written to disk because the memory buffer was full.
+	BatchesSpilled metrics.Counter `metric:"batches_spilled" help:"Total count of batches written to disk because the memory buffer was full"`
+
+	// Total count of requests sent to the collector, each holding one or more coalesced batches.
+	RequestsSent metrics.Counter `metric:"requests_sent" help:"Total count of requests sent to the collector"`
+
+	// NB: as for spans_dropped above, only the first of the batches_dropped metrics has a "help" struct tag.
+
+	// Total count of batches dropped because they were not accepted by the collector before expiring.
+	ExpiredBatches metrics.Counter `metric:"batches_dropped" tags:"cause=expired" help:"Total count of batches dropped by the retry buffer"`
+
+	// Total count of batches dropped because the buffer was full.
+	FullBufferDroppedBatches metrics.Counter `metric:"batches_dropped" tags:"cause=full-buffer"`
+
+	// Total count of batches dropped because the collector rejected them with a non-transient error.
+	RejectedBatches metrics.Counter `metric:"batches_dropped" tags:"cause=rejected"`
+}
+
+// NewRetryBufferMetrics creates the metrics of a retry buffer.
+func NewRetryBufferMetrics(factory metrics.Factory) *RetryBufferMetrics {
+	m := new(RetryBufferMetrics)
+	metrics.MustInit(m, factory.Namespace(metrics.NSOptions{Name: "retry_buffer"}), nil)
+	return m
+}
+
 type lastReceivedClientStats struct {
 	lock        sync.Mutex
 	lastUpdated time.Time
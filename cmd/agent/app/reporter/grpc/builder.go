@@ -44,6 +44,7 @@ type ConnBuilder struct {
 	TLS      tlscfg.Options
 
 	DiscoveryMinPeers int
+	RetryBuffer       RetryBufferOptions
 	Notifier          discovery.Notifier
 	Discoverer        discovery.Discoverer
 }
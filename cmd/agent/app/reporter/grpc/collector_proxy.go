@@ -30,6 +30,7 @@ import (
 // ProxyBuilder holds objects communicating with collector
 type ProxyBuilder struct {
 	reporter  *reporter.ClientMetricsReporter
+	sender    *Reporter
 	manager   configmanager.ClientConfigManager
 	conn      *grpc.ClientConn
 	tlsCloser io.Closer
@@ -42,7 +43,16 @@ func NewCollectorProxy(builder *ConnBuilder, agentTags map[string]string, mFacto
 		return nil, err
 	}
 	grpcMetrics := mFactory.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"protocol": "grpc"}})
-	r1 := NewReporter(conn, agentTags, logger)
+	var r1 *Reporter
+	if builder.RetryBuffer.Enabled {
+		r1, err = NewBufferedReporter(conn, agentTags, builder.RetryBuffer, grpcMetrics, logger)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	} else {
+		r1 = NewReporter(conn, agentTags, logger)
+	}
 	r2 := reporter.WrapWithMetrics(r1, grpcMetrics)
 	r3 := reporter.WrapWithClientMetrics(reporter.ClientMetricsReporterParams{
 		Reporter:       r2,
@@ -52,6 +62,7 @@ func NewCollectorProxy(builder *ConnBuilder, agentTags map[string]string, mFacto
 	return &ProxyBuilder{
 		conn:      conn,
 		reporter:  r3,
+		sender:    r1,
 		manager:   configmanager.WrapWithMetrics(grpcManager.NewConfigManager(conn), grpcMetrics),
 		tlsCloser: &builder.TLS,
 	}, nil
@@ -74,5 +85,5 @@ func (b ProxyBuilder) GetManager() configmanager.ClientConfigManager {
 
 // Close closes connections used by proxy.
 func (b ProxyBuilder) Close() error {
-	return multicloser.Wrap(b.reporter, b.tlsCloser, b.GetConn()).Close()
+	return multicloser.Wrap(b.reporter, b.sender, b.tlsCloser, b.GetConn()).Close()
 }
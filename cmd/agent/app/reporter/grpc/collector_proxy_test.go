@@ -17,7 +17,9 @@ package grpc
 import (
 	"context"
 	"io"
+	"io/ioutil"
 	"net"
+	"os"
 	"testing"
 	"time"
 
@@ -71,6 +73,32 @@ func TestMultipleCollectors(t *testing.T) {
 	require.Nil(t, proxy.Close())
 }
 
+func TestCollectorProxyWithRetryBuffer(t *testing.T) {
+	spanHandler := &mockSpanHandler{}
+	s, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		api_v2.RegisterCollectorServiceServer(s, spanHandler)
+	})
+	defer s.Stop()
+
+	builder := &ConnBuilder{CollectorHostPorts: []string{addr.String()}, RetryBuffer: RetryBufferOptions{Enabled: true}}
+	proxy, err := NewCollectorProxy(builder, nil, metricstest.NewFactory(time.Hour), zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, proxy.sender.buffer)
+
+	err = proxy.GetReporter().EmitBatch(context.Background(), &jaeger.Batch{Spans: []*jaeger.Span{{OperationName: "op"}}, Process: &jaeger.Process{ServiceName: "service"}})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(spanHandler.getRequests()) == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, proxy.Close())
+
+	f, err := ioutil.TempFile("", "retry-buffer")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, f.Close())
+	builder.RetryBuffer.DiskDirectory = f.Name()
+	_, err = NewCollectorProxy(builder, nil, metricstest.NewFactory(time.Hour), zap.NewNop())
+	assert.Error(t, err)
+}
+
 func initializeGRPCTestServer(t *testing.T, beforeServe func(server *grpc.Server), opts ...grpc.ServerOption) (*grpc.Server, net.Addr) {
 	server := grpc.NewServer(opts...)
 	lis, err := net.Listen("tcp", "localhost:0")
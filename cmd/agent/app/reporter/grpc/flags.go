@@ -29,6 +29,18 @@ const (
 	retry             = gRPCPrefix + ".retry.max"
 	defaultMaxRetry   = 3
 	discoveryMinPeers = gRPCPrefix + ".discovery.min-peers"
+
+	retryBufferPrefix           = gRPCPrefix + ".retry-buffer"
+	retryBufferEnabled          = retryBufferPrefix + ".enabled"
+	retryBufferMaxSpans         = retryBufferPrefix + ".max-spans"
+	retryBufferMaxAge           = retryBufferPrefix + ".max-age"
+	retryBufferInitialBackoff   = retryBufferPrefix + ".initial-backoff"
+	retryBufferMaxBackoff       = retryBufferPrefix + ".max-backoff"
+	retryBufferCoalesceInterval = retryBufferPrefix + ".coalesce-interval"
+	retryBufferCoalesceMaxSpans = retryBufferPrefix + ".coalesce-max-spans"
+	retryBufferCoalesceMaxSize  = retryBufferPrefix + ".coalesce-max-size"
+	retryBufferDiskDirectory    = retryBufferPrefix + ".disk.directory"
+	retryBufferDiskMaxSize      = retryBufferPrefix + ".disk.max-size"
 )
 
 var tlsFlagsConfig = tlscfg.ClientFlagsConfig{
@@ -40,6 +52,16 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.Uint(retry, defaultMaxRetry, "Sets the maximum number of retries for a call")
 	flags.Int(discoveryMinPeers, 3, "Max number of collectors to which the agent will try to connect at any given time")
 	flags.String(collectorHostPort, "", "Comma-separated string representing host:port of a static list of collectors to connect to directly")
+	flags.Bool(retryBufferEnabled, false, "Buffers the batches until the collector accepts them, instead of dropping the batches which cannot be sent")
+	flags.Int(retryBufferMaxSpans, defaultRetryBufferMaxSpans, "The maximum number of spans buffered in memory, beyond which the batches are written to disk or dropped")
+	flags.Duration(retryBufferMaxAge, defaultRetryBufferMaxAge, "The time after which the buffered batches not accepted by the collector are dropped")
+	flags.Duration(retryBufferInitialBackoff, defaultRetryBufferInitialBackoff, "The delay before sending the buffered batches again after a failure, doubled after each failure")
+	flags.Duration(retryBufferMaxBackoff, defaultRetryBufferMaxBackoff, "The maximum delay before sending the buffered batches again after a failure")
+	flags.Duration(retryBufferCoalesceInterval, 0, "The time waited for more batches to send them in a single request; the batches received while a request is in flight are always coalesced")
+	flags.Int(retryBufferCoalesceMaxSpans, defaultRetryBufferCoalesceMaxSpans, "The maximum number of spans of the batches coalesced in a single request")
+	flags.Int(retryBufferCoalesceMaxSize, defaultRetryBufferCoalesceMaxSize/1024, "The maximum size in KiB of the batches coalesced in a single request, which must stay below the maximum message size accepted by the collector (4 MiB by default)")
+	flags.String(retryBufferDiskDirectory, "", "The directory holding the batches which do not fit in memory, and the batches not sent when the agent stops; none are written to disk if empty")
+	flags.Int(retryBufferDiskMaxSize, defaultRetryBufferDiskMaxSize/1024/1024, "The maximum size in MiB of the batches written to disk")
 	tlsFlagsConfig.AddFlags(flags)
 }
 
@@ -52,5 +74,17 @@ func (b *ConnBuilder) InitFromViper(v *viper.Viper) *ConnBuilder {
 	b.MaxRetry = uint(v.GetInt(retry))
 	b.TLS = tlsFlagsConfig.InitFromViper(v)
 	b.DiscoveryMinPeers = v.GetInt(discoveryMinPeers)
+	b.RetryBuffer = RetryBufferOptions{
+		Enabled:          v.GetBool(retryBufferEnabled),
+		MaxSpans:         v.GetInt(retryBufferMaxSpans),
+		MaxAge:           v.GetDuration(retryBufferMaxAge),
+		InitialBackoff:   v.GetDuration(retryBufferInitialBackoff),
+		MaxBackoff:       v.GetDuration(retryBufferMaxBackoff),
+		CoalesceInterval: v.GetDuration(retryBufferCoalesceInterval),
+		CoalesceMaxSpans: v.GetInt(retryBufferCoalesceMaxSpans),
+		CoalesceMaxSize:  v.GetInt(retryBufferCoalesceMaxSize) * 1024,
+		DiskDirectory:    v.GetString(retryBufferDiskDirectory),
+		DiskMaxSize:      int64(v.GetInt(retryBufferDiskMaxSize)) * 1024 * 1024,
+	}
 	return b
 }
@@ -17,6 +17,7 @@ package grpc
 import (
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -25,16 +26,51 @@ import (
 )
 
 func TestBindFlags(t *testing.T) {
+	defaultRetryBuffer := RetryBufferOptions{
+		MaxSpans:         defaultRetryBufferMaxSpans,
+		MaxAge:           defaultRetryBufferMaxAge,
+		InitialBackoff:   defaultRetryBufferInitialBackoff,
+		MaxBackoff:       defaultRetryBufferMaxBackoff,
+		CoalesceMaxSpans: defaultRetryBufferCoalesceMaxSpans,
+		CoalesceMaxSize:  defaultRetryBufferCoalesceMaxSize,
+		DiskMaxSize:      defaultRetryBufferDiskMaxSize,
+	}
 	tests := []struct {
 		cOpts    []string
 		expected *ConnBuilder
 	}{
 		{cOpts: []string{"--reporter.grpc.host-port=localhost:1111", "--reporter.grpc.retry.max=15"},
-			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111"}, MaxRetry: 15, DiscoveryMinPeers: 3}},
+			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111"}, MaxRetry: 15, DiscoveryMinPeers: 3, RetryBuffer: defaultRetryBuffer}},
 		{cOpts: []string{"--reporter.grpc.host-port=localhost:1111,localhost:2222"},
-			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111", "localhost:2222"}, MaxRetry: defaultMaxRetry, DiscoveryMinPeers: 3}},
+			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111", "localhost:2222"}, MaxRetry: defaultMaxRetry, DiscoveryMinPeers: 3, RetryBuffer: defaultRetryBuffer}},
 		{cOpts: []string{"--reporter.grpc.host-port=localhost:1111,localhost:2222", "--reporter.grpc.discovery.min-peers=5"},
-			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111", "localhost:2222"}, MaxRetry: defaultMaxRetry, DiscoveryMinPeers: 5}},
+			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111", "localhost:2222"}, MaxRetry: defaultMaxRetry, DiscoveryMinPeers: 5, RetryBuffer: defaultRetryBuffer}},
+		{cOpts: []string{
+			"--reporter.grpc.host-port=localhost:1111",
+			"--reporter.grpc.retry-buffer.enabled=true",
+			"--reporter.grpc.retry-buffer.max-spans=1000",
+			"--reporter.grpc.retry-buffer.max-age=1m",
+			"--reporter.grpc.retry-buffer.initial-backoff=1s",
+			"--reporter.grpc.retry-buffer.max-backoff=10s",
+			"--reporter.grpc.retry-buffer.coalesce-interval=50ms",
+			"--reporter.grpc.retry-buffer.coalesce-max-spans=100",
+			"--reporter.grpc.retry-buffer.coalesce-max-size=512",
+			"--reporter.grpc.retry-buffer.disk.directory=/var/lib/jaeger-agent",
+			"--reporter.grpc.retry-buffer.disk.max-size=64",
+		},
+			expected: &ConnBuilder{CollectorHostPorts: []string{"localhost:1111"}, MaxRetry: defaultMaxRetry, DiscoveryMinPeers: 3,
+				RetryBuffer: RetryBufferOptions{
+					Enabled:          true,
+					MaxSpans:         1000,
+					MaxAge:           time.Minute,
+					InitialBackoff:   time.Second,
+					MaxBackoff:       10 * time.Second,
+					CoalesceInterval: 50 * time.Millisecond,
+					CoalesceMaxSpans: 100,
+					CoalesceMaxSize:  512 * 1024,
+					DiskDirectory:    "/var/lib/jaeger-agent",
+					DiskMaxSize:      64 * 1024 * 1024,
+				}}},
 	}
 	for _, test := range tests {
 		v := viper.New()
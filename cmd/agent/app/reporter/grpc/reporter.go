@@ -18,6 +18,7 @@ package grpc
 import (
 	"context"
 
+	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
@@ -36,6 +37,7 @@ type Reporter struct {
 	agentTags []model.KeyValue
 	logger    *zap.Logger
 	sanitizer zipkin2.Sanitizer
+	buffer    *retryBuffer // nil if the batches are sent directly
 }
 
 // NewReporter creates gRPC reporter.
@@ -48,6 +50,17 @@ func NewReporter(conn *grpc.ClientConn, agentTags map[string]string, logger *zap
 	}
 }
 
+// NewBufferedReporter creates gRPC reporter which buffers the batches until the collector accepts them.
+func NewBufferedReporter(conn *grpc.ClientConn, agentTags map[string]string, opts RetryBufferOptions, mFactory metrics.Factory, logger *zap.Logger) (*Reporter, error) {
+	r := NewReporter(conn, agentTags, logger)
+	buffer, err := newRetryBuffer(opts, r.post, mFactory, logger)
+	if err != nil {
+		return nil, err
+	}
+	r.buffer = buffer
+	return r, nil
+}
+
 // EmitBatch implements EmitBatch() of Reporter
 func (r *Reporter) EmitBatch(ctx context.Context, b *thrift.Batch) error {
 	return r.send(ctx, jConverter.ToDomain(b.Spans, nil), jConverter.ToDomainProcess(b.Process))
@@ -68,14 +81,29 @@ func (r *Reporter) EmitZipkinBatch(ctx context.Context, zSpans []*zipkincore.Spa
 func (r *Reporter) send(ctx context.Context, spans []*model.Span, process *model.Process) error {
 	spans, process = addProcessTags(spans, process, r.agentTags)
 	batch := model.Batch{Spans: spans, Process: process}
-	req := &api_v2.PostSpansRequest{Batch: batch}
-	_, err := r.collector.PostSpans(ctx, req)
+	if r.buffer != nil {
+		return r.buffer.add(batch)
+	}
+	err := r.post(ctx, batch)
 	if err != nil {
 		r.logger.Error("Could not send spans over gRPC", zap.Error(err))
 	}
 	return err
 }
 
+func (r *Reporter) post(ctx context.Context, batch model.Batch) error {
+	_, err := r.collector.PostSpans(ctx, &api_v2.PostSpansRequest{Batch: batch})
+	return err
+}
+
+// Close stops the retry buffer, if any.
+func (r *Reporter) Close() error {
+	if r.buffer != nil {
+		return r.buffer.close()
+	}
+	return nil
+}
+
 // addTags appends jaeger tags for the agent to every span it sends to the collector.
 func addProcessTags(spans []*model.Span, process *model.Process, agentTags []model.KeyValue) ([]*model.Span, *model.Process) {
 	if len(agentTags) == 0 {
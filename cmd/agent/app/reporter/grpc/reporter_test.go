@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
@@ -128,6 +129,46 @@ func TestReporter_SendFailure(t *testing.T) {
 	assert.Contains(t, err.Error(), "transport: Error while dialing dial tcp: missing address")
 }
 
+func TestReporter_BufferedSendFailure(t *testing.T) {
+	conn, err := grpc.Dial("", grpc.WithInsecure())
+	require.NoError(t, err)
+	//nolint:staticcheck // don't care about errors
+	defer conn.Close()
+	mf := metricstest.NewFactory(time.Hour)
+	rep, err := NewBufferedReporter(conn, nil, RetryBufferOptions{InitialBackoff: time.Hour}, mf, zap.NewNop())
+	require.NoError(t, err)
+
+	err = rep.EmitBatch(context.Background(), &jThrift.Batch{Process: &jThrift.Process{ServiceName: "node"}, Spans: []*jThrift.Span{{OperationName: "foo"}}})
+	require.NoError(t, err, "the batch is buffered until the collector accepts it")
+	assert.Eventually(t, func() bool {
+		c, _ := mf.Snapshot()
+		return c["retry_buffer.requests_sent"] == 1
+	}, time.Second, time.Millisecond)
+	_, g := mf.Snapshot()
+	assert.EqualValues(t, 1, g["retry_buffer.buffered_batches"])
+	require.NoError(t, rep.Close())
+}
+
+func TestReporter_Buffered(t *testing.T) {
+	handler := &mockSpanHandler{}
+	s, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		api_v2.RegisterCollectorServiceServer(s, handler)
+	})
+	defer s.Stop()
+	conn, err := grpc.Dial(addr.String(), grpc.WithInsecure())
+	//nolint:staticcheck // don't care about errors
+	defer conn.Close()
+	require.NoError(t, err)
+	rep, err := NewBufferedReporter(conn, nil, RetryBufferOptions{}, metricstest.NewFactory(time.Hour), zap.NewNop())
+	require.NoError(t, err)
+	defer rep.Close()
+
+	err = rep.EmitBatch(context.Background(), &jThrift.Batch{Process: &jThrift.Process{ServiceName: "node"}, Spans: []*jThrift.Span{{OperationName: "foo"}}})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(handler.getRequests()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, "node", handler.getRequests()[0].GetBatch().Process.ServiceName)
+}
+
 func TestReporter_AddProcessTags_EmptyTags(t *testing.T) {
 	tags := map[string]string{}
 	spans := []*model.Span{{TraceID: model.NewTraceID(0, 1), SpanID: model.NewSpanID(2), OperationName: "jonatan"}}
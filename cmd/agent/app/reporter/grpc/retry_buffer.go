@@ -0,0 +1,487 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/agent/app/reporter"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+)
+
+const (
+	defaultRetryBufferMaxSpans         = 100000
+	defaultRetryBufferMaxAge           = 5 * time.Minute
+	defaultRetryBufferInitialBackoff   = 100 * time.Millisecond
+	defaultRetryBufferMaxBackoff       = 30 * time.Second
+	defaultRetryBufferCoalesceMaxSpans = 1000
+	defaultRetryBufferCoalesceMaxSize  = 2 * 1024 * 1024
+	defaultRetryBufferDiskMaxSize      = 512 * 1024 * 1024
+)
+
+var (
+	errRetryBufferFull   = errors.New("the retry buffer is full")
+	errRetryBufferClosed = errors.New("the retry buffer is closed")
+)
+
+// RetryBufferOptions configures the buffering of the batches until the collector accepts them.
+type RetryBufferOptions struct {
+	Enabled bool
+	// MaxSpans is the number of spans held in memory, beyond which the batches are written to disk or dropped
+	MaxSpans int
+	// MaxAge is the time after which the batches not accepted by the collector are dropped
+	MaxAge time.Duration
+	// InitialBackoff is the delay before sending again the batches after a failure, doubled up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// CoalesceInterval is the time waited for more batches before sending a request,
+	// the batches received while a request is in flight being coalesced regardless
+	CoalesceInterval time.Duration
+	// CoalesceMaxSpans is the number of spans above which the batches are not coalesced in a single request
+	CoalesceMaxSpans int
+	// CoalesceMaxSize is the size in bytes above which the batches are not coalesced in a single request
+	CoalesceMaxSize int
+	// DiskDirectory holds the batches which do not fit in memory, none are written to disk if empty
+	DiskDirectory string
+	// DiskMaxSize is the maximum size of the batches on disk, in bytes
+	DiskMaxSize int64
+}
+
+type bufferedBatch struct {
+	batch    model.Batch
+	received time.Time
+	attempts int
+	size     int // in a coalesced request
+}
+
+// retryBuffer holds the batches in memory, spilling them to disk when the memory is full, and sends them
+// to the collector from a single goroutine, coalescing the batches and backing off after the failures.
+type retryBuffer struct {
+	opts     RetryBufferOptions
+	send     func(ctx context.Context, batch model.Batch) error
+	metrics  *reporter.RetryBufferMetrics
+	logger   *zap.Logger
+	disk     *queue.PersistentQueue // nil if the batches are not written to disk
+	diskOpts queue.PersistentQueueOptions
+
+	lock    sync.Mutex
+	cond    *sync.Cond
+	batches []*bufferedBatch
+	spans   int
+	closed  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newRetryBuffer(
+	opts RetryBufferOptions,
+	send func(ctx context.Context, batch model.Batch) error,
+	metricsFactory metrics.Factory,
+	logger *zap.Logger,
+) (*retryBuffer, error) {
+	if opts.MaxSpans <= 0 {
+		opts.MaxSpans = defaultRetryBufferMaxSpans
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultRetryBufferMaxAge
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultRetryBufferInitialBackoff
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+	if opts.CoalesceMaxSpans <= 0 {
+		opts.CoalesceMaxSpans = defaultRetryBufferCoalesceMaxSpans
+	}
+	if opts.CoalesceMaxSize <= 0 {
+		opts.CoalesceMaxSize = defaultRetryBufferCoalesceMaxSize
+	}
+	b := &retryBuffer{
+		opts:    opts,
+		send:    send,
+		metrics: reporter.NewRetryBufferMetrics(metricsFactory),
+		logger:  logger,
+		done:    make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.lock)
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	if opts.DiskDirectory != "" {
+		if opts.DiskMaxSize <= 0 {
+			opts.DiskMaxSize = defaultRetryBufferDiskMaxSize
+		}
+		segmentSize := opts.DiskMaxSize / 8
+		if segmentSize > queue.DefaultSegmentSize {
+			segmentSize = queue.DefaultSegmentSize
+		}
+		b.diskOpts = queue.PersistentQueueOptions{
+			Directory:      opts.DiskDirectory,
+			SegmentSize:    segmentSize,
+			MaxSize:        opts.DiskMaxSize,
+			Encode:         encodeBufferedBatch,
+			Decode:         decodeBufferedBatch,
+			MetricsFactory: metricsFactory.Namespace(metrics.NSOptions{Name: "retry_buffer_disk"}),
+			Logger:         logger,
+		}
+		disk, err := queue.NewPersistentQueue(b.diskOpts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open the retry buffer directory: %w", err)
+		}
+		b.disk = disk
+		disk.StartConsumers(1, func(item interface{}) {
+			b.restore(item.(*bufferedBatch))
+		})
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b, nil
+}
+
+// add buffers the batch, writing it to disk if the memory is full.
+func (b *retryBuffer) add(batch model.Batch) error {
+	bb := &bufferedBatch{batch: batch, received: time.Now()}
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return errRetryBufferClosed
+	}
+	if !b.full(len(batch.Spans)) {
+		b.push(bb)
+		b.lock.Unlock()
+		return nil
+	}
+	b.lock.Unlock()
+	if b.disk != nil && b.disk.Produce(bb) {
+		b.metrics.BatchesSpilled.Inc(1)
+		return nil
+	}
+	b.metrics.FullBufferDroppedBatches.Inc(1)
+	return errRetryBufferFull
+}
+
+// restore moves a batch from disk to memory, waiting for the memory to have room for it.
+func (b *retryBuffer) restore(bb *bufferedBatch) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for !b.closed && b.full(len(bb.batch.Spans)) {
+		b.cond.Wait()
+	}
+	b.push(bb)
+}
+
+// full returns true if the memory has no room for the spans, a batch always fitting in an empty buffer.
+func (b *retryBuffer) full(spans int) bool {
+	return b.spans > 0 && b.spans+spans > b.opts.MaxSpans
+}
+
+func (b *retryBuffer) push(bb *bufferedBatch) {
+	bb.size = coalescedSize(bb.batch)
+	b.batches = append(b.batches, bb)
+	b.spans += len(bb.batch.Spans)
+	b.updateGauges()
+	b.cond.Broadcast()
+}
+
+// pop removes the first n batches.
+func (b *retryBuffer) pop(n int) {
+	for _, bb := range b.batches[:n] {
+		b.spans -= len(bb.batch.Spans)
+	}
+	copy(b.batches, b.batches[n:])
+	for i := len(b.batches) - n; i < len(b.batches); i++ {
+		b.batches[i] = nil
+	}
+	b.batches = b.batches[:len(b.batches)-n]
+	b.updateGauges()
+	b.cond.Broadcast()
+}
+
+func (b *retryBuffer) updateGauges() {
+	b.metrics.BufferedBatches.Update(int64(len(b.batches)))
+	b.metrics.BufferedSpans.Update(int64(b.spans))
+}
+
+func (b *retryBuffer) run() {
+	defer b.wg.Done()
+	backoff := b.opts.InitialBackoff
+	failing := false
+	// number of batches still to send alone, after the collector rejected them coalesced
+	alone := 0
+	for {
+		batches, ok := b.next(failing, alone > 0)
+		if !ok {
+			return
+		}
+		for _, bb := range batches {
+			if bb.attempts > 0 {
+				b.metrics.BatchesRetried.Inc(1)
+			}
+		}
+		b.metrics.RequestsSent.Inc(1)
+		err := b.send(b.ctx, coalesce(batches))
+		if err == nil {
+			if failing {
+				b.logger.Info("The collector accepts the spans again, sending the buffered batches")
+				failing = false
+			}
+			backoff = b.opts.InitialBackoff
+			b.lock.Lock()
+			b.pop(len(batches))
+			b.lock.Unlock()
+			if alone > 0 {
+				alone--
+			}
+			continue
+		}
+		if b.ctx.Err() == nil && !retryable(err) {
+			if len(batches) > 1 {
+				b.logger.Warn("The collector rejected coalesced batches, sending them one by one", zap.Int("batches", len(batches)), zap.Error(err))
+				alone = len(batches)
+				continue
+			}
+			b.logger.Error("The collector rejected the batch, dropping it", zap.Int("spans", len(batches[0].batch.Spans)), zap.Error(err))
+			b.metrics.RejectedBatches.Inc(1)
+			b.lock.Lock()
+			b.pop(1)
+			b.lock.Unlock()
+			if alone > 0 {
+				alone--
+			}
+			continue
+		}
+		if !failing {
+			b.logger.Warn("Could not send spans over gRPC, buffering the batches until the collector accepts them", zap.Error(err))
+			failing = true
+		}
+		b.lock.Lock()
+		for _, bb := range batches {
+			bb.attempts++
+		}
+		b.lock.Unlock()
+		select {
+		case <-time.After(backoff):
+		case <-b.done:
+			return
+		}
+		if backoff *= 2; backoff > b.opts.MaxBackoff {
+			backoff = b.opts.MaxBackoff
+		}
+	}
+}
+
+// retryable returns true if the collector may accept the batches later: it is unreachable, too slow, or busy.
+// The other errors, e.g. an invalid tenant or a request above the maximum message size, are permanent.
+func retryable(err error) bool {
+	s := status.Convert(err)
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	case codes.ResourceExhausted:
+		// the collector queue is full, unlike a request too large for the gRPC transport
+		return s.Message() == processor.ErrBusy.Error()
+	}
+	return false
+}
+
+// next waits for batches to send, and returns the first ones up to the coalescing limits after
+// dropping the expired ones, or only the first one if alone. It returns false once the buffer is closed.
+func (b *retryBuffer) next(retrying, alone bool) ([]*bufferedBatch, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for {
+		for !b.closed && len(b.batches) == 0 {
+			b.cond.Wait()
+		}
+		if b.closed {
+			return nil, false
+		}
+		if !retrying && !alone && b.opts.CoalesceInterval > 0 && b.spans < b.opts.CoalesceMaxSpans {
+			b.lock.Unlock()
+			select {
+			case <-time.After(b.opts.CoalesceInterval):
+			case <-b.done:
+			}
+			b.lock.Lock()
+			if b.closed {
+				return nil, false
+			}
+		}
+		b.expire(time.Now())
+		if len(b.batches) > 0 {
+			break
+		}
+	}
+
+	n, spans, size := 0, 0, 0
+	for n < len(b.batches) && (n == 0 || !alone &&
+		spans+len(b.batches[n].batch.Spans) <= b.opts.CoalesceMaxSpans &&
+		size+b.batches[n].size <= b.opts.CoalesceMaxSize) {
+		spans += len(b.batches[n].batch.Spans)
+		size += b.batches[n].size
+		n++
+	}
+	batches := make([]*bufferedBatch, n)
+	copy(batches, b.batches[:n])
+	return batches, true
+}
+
+// expire drops the batches received before MaxAge. They are usually the first ones, but the
+// batches moved from disk to memory may be older than the ones received meanwhile.
+func (b *retryBuffer) expire(now time.Time) {
+	kept := b.batches[:0]
+	for _, bb := range b.batches {
+		if now.Sub(bb.received) > b.opts.MaxAge {
+			b.spans -= len(bb.batch.Spans)
+			continue
+		}
+		kept = append(kept, bb)
+	}
+	expired := len(b.batches) - len(kept)
+	if expired == 0 {
+		return
+	}
+	for i := len(kept); i < len(b.batches); i++ {
+		b.batches[i] = nil
+	}
+	b.batches = kept
+	b.metrics.ExpiredBatches.Inc(int64(expired))
+	b.logger.Warn("Dropping the batches not accepted by the collector in time", zap.Int("batches", expired))
+	b.updateGauges()
+	b.cond.Broadcast()
+}
+
+// coalesce merges the batches in a single one, the spans holding the process of their batch.
+func coalesce(batches []*bufferedBatch) model.Batch {
+	if len(batches) == 1 {
+		return batches[0].batch
+	}
+	var spans []*model.Span
+	for _, bb := range batches {
+		for _, span := range bb.batch.Spans {
+			if span.Process == nil {
+				span.Process = bb.batch.Process
+			}
+			spans = append(spans, span)
+		}
+	}
+	return model.Batch{Spans: spans}
+}
+
+// coalescedSize returns the encoded size of the spans of the batch in a coalesced request,
+// each span holding the process of the batch.
+func coalescedSize(batch model.Batch) int {
+	processSize := 0
+	if batch.Process != nil {
+		processSize = fieldSize(batch.Process.Size())
+	}
+	size := 0
+	for _, span := range batch.Spans {
+		spanSize := span.Size()
+		if span.Process == nil {
+			spanSize += processSize
+		}
+		size += fieldSize(spanSize)
+	}
+	return size
+}
+
+// fieldSize returns the size of a protobuf field holding a message of the given size: its tag and length prefix.
+func fieldSize(n int) int {
+	return 1 + (bits.Len64(uint64(n)|1)+6)/7 + n
+}
+
+// close stops sending the batches. With a disk directory, the batches still in memory are written
+// to disk to be sent after a restart, otherwise they are dropped.
+func (b *retryBuffer) close() error {
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.cond.Broadcast()
+	b.lock.Unlock()
+	close(b.done)
+	b.cancel()
+	b.wg.Wait()
+
+	if b.disk == nil {
+		if len(b.batches) > 0 {
+			b.logger.Warn("Dropping the batches not sent to the collector", zap.Int("batches", len(b.batches)))
+		}
+		return nil
+	}
+	// the batches being moved from disk to memory are pushed without waiting once closed
+	b.disk.Stop()
+	if len(b.batches) == 0 {
+		return nil
+	}
+	disk, err := queue.NewPersistentQueue(b.diskOpts)
+	if err != nil {
+		return fmt.Errorf("cannot save the batches not sent to the collector: %w", err)
+	}
+	dropped := 0
+	for _, bb := range b.batches {
+		if !disk.Produce(bb) {
+			dropped++
+		}
+	}
+	disk.Stop()
+	if dropped > 0 {
+		b.metrics.FullBufferDroppedBatches.Inc(int64(dropped))
+		return fmt.Errorf("cannot save %d batches not sent to the collector", dropped)
+	}
+	return nil
+}
+
+// encodeBufferedBatch encodes the reception time of the batch followed by the batch.
+func encodeBufferedBatch(item interface{}) ([]byte, error) {
+	bb := item.(*bufferedBatch)
+	data, err := bb.batch.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(buf, uint64(bb.received.UnixNano()))
+	copy(buf[8:], data)
+	return buf, nil
+}
+
+func decodeBufferedBatch(data []byte) (interface{}, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("buffered batch too short: %d bytes", len(data))
+	}
+	bb := &bufferedBatch{received: time.Unix(0, int64(binary.BigEndian.Uint64(data)))}
+	if err := bb.batch.Unmarshal(data[8:]); err != nil {
+		return nil, err
+	}
+	return bb, nil
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	errCollectorUnavailable = status.Error(codes.Unavailable, "collector unavailable")
+	errInvalidBatch         = status.Error(codes.InvalidArgument, "invalid batch")
+)
+
+// fakeCollector fails the first failures requests, rejects the requests holding spans of the "invalid"
+// service, and blocks the requests while blocked is locked.
+type fakeCollector struct {
+	blocked sync.RWMutex
+
+	lock     sync.Mutex
+	failures int
+	requests []model.Batch
+}
+
+func (c *fakeCollector) send(ctx context.Context, batch model.Batch) error {
+	c.blocked.RLock()
+	defer c.blocked.RUnlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return errCollectorUnavailable
+	}
+	for _, span := range batch.Spans {
+		if process := span.Process; process == nil && batch.Process.ServiceName == "invalid" || process != nil && process.ServiceName == "invalid" {
+			return errInvalidBatch
+		}
+	}
+	c.requests = append(c.requests, batch)
+	return nil
+}
+
+func (c *fakeCollector) getRequests() []model.Batch {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]model.Batch(nil), c.requests...)
+}
+
+func (c *fakeCollector) sentSpans() int {
+	spans := 0
+	for _, batch := range c.getRequests() {
+		spans += len(batch.Spans)
+	}
+	return spans
+}
+
+func makeBatch(service string, spans int) model.Batch {
+	batch := model.Batch{Process: &model.Process{ServiceName: service}}
+	for i := 0; i < spans; i++ {
+		batch.Spans = append(batch.Spans, &model.Span{SpanID: model.NewSpanID(uint64(i + 1)), OperationName: "op"})
+	}
+	return batch
+}
+
+func newTestRetryBuffer(t *testing.T, opts RetryBufferOptions, collector *fakeCollector) (*retryBuffer, *metricstest.Factory) {
+	mf := metricstest.NewFactory(time.Hour)
+	b, err := newRetryBuffer(opts, collector.send, mf, zap.NewNop())
+	require.NoError(t, err)
+	return b, mf
+}
+
+func TestRetryBufferCoalescesBatches(t *testing.T) {
+	collector := &fakeCollector{}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{}, collector)
+	defer b.close()
+
+	collector.blocked.Lock()
+	require.NoError(t, b.add(makeBatch("first", 1)))
+	assert.Eventually(t, func() bool {
+		c, _ := mf.Snapshot()
+		return c["retry_buffer.requests_sent"] == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, b.add(makeBatch("a", 2)))
+	require.NoError(t, b.add(makeBatch("b", 3)))
+	collector.blocked.Unlock()
+
+	assert.Eventually(t, func() bool { return len(collector.getRequests()) == 2 }, time.Second, time.Millisecond)
+	requests := collector.getRequests()
+	assert.Equal(t, makeBatch("first", 1), requests[0], "a single batch is sent as is")
+	assert.Nil(t, requests[1].Process)
+	require.Len(t, requests[1].Spans, 5)
+	assert.Equal(t, "a", requests[1].Spans[0].Process.ServiceName)
+	assert.Equal(t, "b", requests[1].Spans[4].Process.ServiceName)
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "retry_buffer.requests_sent", Value: 2})
+}
+
+func TestRetryBufferCoalesceLimits(t *testing.T) {
+	collector := &fakeCollector{}
+	b, _ := newTestRetryBuffer(t, RetryBufferOptions{CoalesceInterval: 10 * time.Millisecond, CoalesceMaxSpans: 4}, collector)
+	defer b.close()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.add(makeBatch("svc", 2)))
+	}
+	require.NoError(t, b.add(makeBatch("large", 10)))
+
+	assert.Eventually(t, func() bool { return collector.sentSpans() == 16 }, time.Second, time.Millisecond)
+	for _, request := range collector.getRequests() {
+		if len(request.Spans) > 4 {
+			assert.Equal(t, "large", request.Process.ServiceName, "a batch above the limit is sent alone")
+		}
+	}
+	assert.GreaterOrEqual(t, len(collector.getRequests()), 3)
+}
+
+func TestRetryBufferCoalesceMaxSize(t *testing.T) {
+	collector := &fakeCollector{}
+	b, _ := newTestRetryBuffer(t, RetryBufferOptions{CoalesceMaxSize: 2 * coalescedSize(makeBatch("svc", 2))}, collector)
+	defer b.close()
+
+	collector.blocked.Lock()
+	require.NoError(t, b.add(makeBatch("first", 1)))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.add(makeBatch("svc", 2)))
+	}
+	collector.blocked.Unlock()
+
+	assert.Eventually(t, func() bool { return collector.sentSpans() == 7 }, time.Second, time.Millisecond)
+	for _, request := range collector.getRequests() {
+		assert.LessOrEqual(t, len(request.Spans), 4)
+	}
+}
+
+func TestRetryBufferDropsRejectedBatches(t *testing.T) {
+	collector := &fakeCollector{}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{InitialBackoff: time.Hour}, collector)
+	defer b.close()
+
+	collector.blocked.Lock()
+	require.NoError(t, b.add(makeBatch("first", 1)))
+	assert.Eventually(t, func() bool {
+		c, _ := mf.Snapshot()
+		return c["retry_buffer.requests_sent"] == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, b.add(makeBatch("a", 2)))
+	require.NoError(t, b.add(makeBatch("invalid", 3)))
+	require.NoError(t, b.add(makeBatch("b", 4)))
+	collector.blocked.Unlock()
+
+	assert.Eventually(t, func() bool {
+		_, g := mf.Snapshot()
+		return g["retry_buffer.buffered_batches"] == 0
+	}, time.Second, time.Millisecond, "the rejected batches are not retried")
+	requests := collector.getRequests()
+	require.Len(t, requests, 3, "the batches rejected coalesced are sent one by one")
+	assert.Equal(t, "a", requests[1].Spans[0].Process.ServiceName)
+	assert.Equal(t, "b", requests[2].Spans[0].Process.ServiceName)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "retry_buffer.batches_dropped|cause=rejected", Value: 1},
+		metricstest.ExpectedMetric{Name: "retry_buffer.batches_retried", Value: 0},
+		metricstest.ExpectedMetric{Name: "retry_buffer.requests_sent", Value: 5},
+	)
+}
+
+func TestRetryableErrors(t *testing.T) {
+	assert.True(t, retryable(errCollectorUnavailable))
+	assert.True(t, retryable(status.Error(codes.DeadlineExceeded, "timeout")))
+	assert.True(t, retryable(status.Error(codes.ResourceExhausted, "server busy")))
+	assert.False(t, retryable(status.Error(codes.ResourceExhausted, "grpc: received message larger than max (5000000 vs. 4194304)")))
+	assert.False(t, retryable(errInvalidBatch))
+	assert.False(t, retryable(status.Error(codes.PermissionDenied, "unknown tenant")))
+	assert.False(t, retryable(errors.New("unknown error")))
+}
+
+func TestRetryBufferRetries(t *testing.T) {
+	collector := &fakeCollector{failures: 2}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{InitialBackoff: time.Millisecond}, collector)
+	defer b.close()
+
+	require.NoError(t, b.add(makeBatch("svc", 1)))
+	assert.Eventually(t, func() bool { return len(collector.getRequests()) == 1 }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		_, g := mf.Snapshot()
+		return g["retry_buffer.buffered_batches"] == 0
+	}, time.Second, time.Millisecond)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "retry_buffer.batches_retried", Value: 2},
+		metricstest.ExpectedMetric{Name: "retry_buffer.requests_sent", Value: 3},
+	)
+}
+
+func TestRetryBufferExpiresBatches(t *testing.T) {
+	collector := &fakeCollector{failures: 1 << 30}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{MaxAge: 10 * time.Millisecond, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}, collector)
+	defer b.close()
+
+	require.NoError(t, b.add(makeBatch("svc", 1)))
+	require.NoError(t, b.add(makeBatch("svc", 1)))
+	assert.Eventually(t, func() bool {
+		c, _ := mf.Snapshot()
+		return c["retry_buffer.batches_dropped|cause=expired"] == 2
+	}, time.Second, time.Millisecond)
+	_, g := mf.Snapshot()
+	assert.Zero(t, g["retry_buffer.buffered_spans"])
+}
+
+func TestRetryBufferFull(t *testing.T) {
+	collector := &fakeCollector{}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{MaxSpans: 3}, collector)
+
+	collector.blocked.Lock()
+	require.NoError(t, b.add(makeBatch("svc", 5)), "a batch always fits in an empty buffer")
+	assert.Equal(t, errRetryBufferFull, b.add(makeBatch("svc", 1)))
+	collector.blocked.Unlock()
+
+	assert.Eventually(t, func() bool { return collector.sentSpans() == 5 }, time.Second, time.Millisecond)
+	require.NoError(t, b.add(makeBatch("svc", 1)))
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "retry_buffer.batches_dropped|cause=full-buffer", Value: 1})
+
+	require.NoError(t, b.close())
+	assert.Equal(t, errRetryBufferClosed, b.add(makeBatch("svc", 1)))
+}
+
+func TestRetryBufferSpillsToDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "retry-buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	collector := &fakeCollector{}
+	b, mf := newTestRetryBuffer(t, RetryBufferOptions{MaxSpans: 2, DiskDirectory: dir}, collector)
+	defer b.close()
+
+	collector.blocked.Lock()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, b.add(makeBatch("svc", 1)))
+	}
+	c, _ := mf.Snapshot()
+	assert.GreaterOrEqual(t, c["retry_buffer.batches_spilled"], int64(7))
+	collector.blocked.Unlock()
+
+	assert.Eventually(t, func() bool { return collector.sentSpans() == 10 }, time.Second, time.Millisecond)
+}
+
+func TestRetryBufferSavesBatchesOnClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "retry-buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	unavailable := &fakeCollector{failures: 1 << 30}
+	opts := RetryBufferOptions{MaxSpans: 3, DiskDirectory: dir, InitialBackoff: time.Hour}
+	b, _ := newTestRetryBuffer(t, opts, unavailable)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, b.add(makeBatch("svc", 1)))
+	}
+	require.NoError(t, b.close())
+	assert.Empty(t, unavailable.getRequests())
+
+	collector := &fakeCollector{}
+	b, _ = newTestRetryBuffer(t, opts, collector)
+	defer b.close()
+	assert.Eventually(t, func() bool { return collector.sentSpans() == 5 }, time.Second, time.Millisecond,
+		"the batches in memory and on disk are sent after a restart")
+}
+
+func TestRetryBufferInvalidDirectory(t *testing.T) {
+	f, err := ioutil.TempFile("", "retry-buffer")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, f.Close())
+
+	_, err = newRetryBuffer(RetryBufferOptions{DiskDirectory: f.Name()}, (&fakeCollector{}).send, metricstest.NewFactory(time.Hour), zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestBufferedBatchEncoding(t *testing.T) {
+	bb := &bufferedBatch{batch: makeBatch("svc", 2), received: time.Unix(0, 1234567890)}
+	data, err := encodeBufferedBatch(bb)
+	require.NoError(t, err)
+	decoded, err := decodeBufferedBatch(data)
+	require.NoError(t, err)
+	assert.Equal(t, bb.batch, decoded.(*bufferedBatch).batch)
+	assert.True(t, bb.received.Equal(decoded.(*bufferedBatch).received))
+
+	_, err = decodeBufferedBatch(data[:4])
+	assert.EqualError(t, err, "buffered batch too short: 4 bytes")
+	_, err = decodeBufferedBatch(append(data[:8:8], 0xff))
+	assert.Error(t, err)
+}
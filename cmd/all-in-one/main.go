@@ -44,12 +44,14 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/cmd/status"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	metricsPlugin "github.com/jaegertracing/jaeger/plugin/metrics"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
@@ -115,14 +117,27 @@ by default uses only in-memory database.`,
 			if err != nil {
 				logger.Fatal("Failed to create span writer", zap.Error(err))
 			}
+			if h := storageFactory.FanoutStatusHandler(); h != nil {
+				logger.Info("Mounting span storage fan-out status handler on admin server", zap.String("route", storage.FanoutStatusRoute))
+				svc.Admin.Handle(storage.FanoutStatusRoute, h)
+			}
 			dependencyReader, err := storageFactory.CreateDependencyReader()
 			if err != nil {
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
 
-			metricsQueryService, err := createMetricsQueryService(metricsReaderFactory, v, logger)
+			// the dependencies computed by the collector are written to the storage they are read from
+			dependencyWriter, _ := dependencyReader.(dependencystore.Writer)
+
+			smFactory, err := storageFactory.CreateSpanMetricsStoreFactory()
 			if err != nil {
-				logger.Fatal("Failed to create metrics reader", zap.Error(err))
+				logger.Fatal("Failed to create span metrics store factory", zap.Error(err))
+			}
+			var spanMetricsWriter metricsstore.SpanMetricsWriter
+			if smFactory != nil {
+				if spanMetricsWriter, err = smFactory.CreateSpanMetricsWriter(); err != nil {
+					logger.Fatal("Failed to create span metrics writer", zap.Error(err))
+				}
 			}
 
 			ssFactory, err := storageFactory.CreateSamplingStoreFactory()
@@ -144,21 +159,51 @@ by default uses only in-memory database.`,
 			grpcBuilder := agentGrpcRep.NewConnBuilder().InitFromViper(v)
 			cOpts := new(collectorApp.CollectorOptions).InitFromViper(v)
 			qOpts := new(queryApp.QueryOptions).InitFromViper(v, logger)
+			if cOpts.Tenancy.Enabled {
+				if err := storageFactory.ValidateTenantIsolation(); err != nil {
+					logger.Fatal("Failed to enable multi-tenancy", zap.Error(err))
+				}
+			}
 
 			// collector
 			c := collectorApp.New(&collectorApp.CollectorParams{
-				ServiceName:    "jaeger-collector",
-				Logger:         logger,
-				MetricsFactory: metricsFactory,
-				SpanWriter:     spanWriter,
-				StrategyStore:  strategyStore,
-				Aggregator:     aggregator,
-				HealthCheck:    svc.HC(),
+				ServiceName:       "jaeger-collector",
+				Logger:            logger,
+				MetricsFactory:    metricsFactory,
+				SpanWriter:        spanWriter,
+				StrategyStore:     strategyStore,
+				Aggregator:        aggregator,
+				HealthCheck:       svc.HC(),
+				SpanMetricsWriter: spanMetricsWriter,
+				DependencyWriter:  dependencyWriter,
+				TenancyMgr:        tenancy.NewManager(&cOpts.Tenancy),
 			})
 			if err := c.Start(cOpts); err != nil {
 				log.Fatal(err)
 			}
 
+			// the span metrics aggregated in this process are more recent than the persisted ones
+			if spanMetricsReader := c.SpanMetricsReader(); spanMetricsReader != nil {
+				metricsReaderFactory.SetSpanMetricsReader(spanMetricsReader)
+			} else if smFactory != nil {
+				spanMetricsReader, err := smFactory.CreateSpanMetricsReader()
+				if err != nil {
+					logger.Fatal("Failed to create span metrics reader", zap.Error(err))
+				}
+				metricsReaderFactory.SetSpanMetricsReader(spanMetricsReader)
+			}
+			storageMetricsFactory, err := storageFactory.CreateMetricsFactory()
+			if err != nil {
+				logger.Fatal("Failed to create storage metrics factory", zap.Error(err))
+			}
+			if storageMetricsFactory != nil {
+				metricsReaderFactory.SetStorageMetricsFactory(storageMetricsFactory)
+			}
+			metricsQueryService, err := createMetricsQueryService(metricsReaderFactory, v, logger)
+			if err != nil {
+				logger.Fatal("Failed to create metrics reader", zap.Error(err))
+			}
+
 			// agent
 			// if the agent reporter grpc host:port was not explicitly set then use whatever the collector is listening on
 			if len(grpcBuilder.CollectorHostPorts) == 0 {
@@ -221,6 +266,7 @@ by default uses only in-memory database.`,
 		queryApp.AddFlags,
 		strategyStoreFactory.AddFlags,
 		metricsReaderFactory.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
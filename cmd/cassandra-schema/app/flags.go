@@ -0,0 +1,77 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/schema"
+)
+
+const (
+	mode              = "mode"
+	datacenter        = "datacenter"
+	replicationFactor = "replication-factor"
+	traceTTL          = "trace-ttl"
+	dependenciesTTL   = "dependencies-ttl"
+	dryRun            = "dry-run"
+
+	defaultTraceTTL = 48 * time.Hour
+)
+
+// Config holds the configuration of the keyspace created or migrated by the migrate command.
+type Config struct {
+	Mode              string
+	Datacenter        string
+	ReplicationFactor int
+	TraceTTL          time.Duration
+	DependenciesTTL   time.Duration
+	DryRun            bool
+}
+
+// AddFlags adds the flags of the migrate command to the FlagSet.
+func (c *Config) AddFlags(flags *flag.FlagSet) {
+	flags.String(mode, "", "prod or test. Test keyspace is usable on a single node cluster (no replication)")
+	flags.String(datacenter, "", "Datacenter name for network topology used in prod mode")
+	flags.Int(replicationFactor, 0, "Replication factor of the keyspace (default 2 in prod mode, 1 in test mode)")
+	flags.Duration(traceTTL, defaultTraceTTL, "Time to live for trace data")
+	flags.Duration(dependenciesTTL, 0, "Time to live for dependencies data, 0 for no TTL")
+	flags.Bool(dryRun, false, "Print the statements of the migration without executing them")
+}
+
+// InitFromViper initializes config from viper.Viper.
+func (c *Config) InitFromViper(v *viper.Viper) {
+	c.Mode = v.GetString(mode)
+	c.Datacenter = v.GetString(datacenter)
+	c.ReplicationFactor = v.GetInt(replicationFactor)
+	c.TraceTTL = v.GetDuration(traceTTL)
+	c.DependenciesTTL = v.GetDuration(dependenciesTTL)
+	c.DryRun = v.GetBool(dryRun)
+}
+
+// Params returns the parameters of the keyspace.
+func (c *Config) Params(keyspace string) schema.Params {
+	return schema.Params{
+		Keyspace:          keyspace,
+		Mode:              c.Mode,
+		Datacenter:        c.Datacenter,
+		ReplicationFactor: c.ReplicationFactor,
+		TraceTTL:          c.TraceTTL,
+		DependenciesTTL:   c.DependenciesTTL,
+	}
+}
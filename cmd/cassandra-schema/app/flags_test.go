@@ -0,0 +1,57 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/schema"
+)
+
+func TestBindFlags(t *testing.T) {
+	c := &Config{}
+	v, command := config.Viperize(c.AddFlags)
+	err := command.ParseFlags([]string{
+		"--mode=prod",
+		"--datacenter=dc1",
+		"--replication-factor=3",
+		"--trace-ttl=72h",
+		"--dependencies-ttl=720h",
+		"--dry-run=true",
+	})
+	assert.NoError(t, err)
+
+	c.InitFromViper(v)
+	assert.Equal(t, schema.Params{
+		Keyspace:          "jaeger_v1_dc1",
+		Mode:              schema.ModeProd,
+		Datacenter:        "dc1",
+		ReplicationFactor: 3,
+		TraceTTL:          72 * time.Hour,
+		DependenciesTTL:   720 * time.Hour,
+	}, c.Params("jaeger_v1_dc1"))
+	assert.True(t, c.DryRun)
+}
+
+func TestDefaultFlags(t *testing.T) {
+	c := &Config{}
+	v, _ := config.Viperize(c.AddFlags)
+	c.InitFromViper(v)
+	assert.Equal(t, &Config{TraceTTL: defaultTraceTTL}, c)
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/cassandra-schema/app"
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	cassandraStorage "github.com/jaegertracing/jaeger/plugin/storage/cassandra"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/schema"
+)
+
+func main() {
+	v := viper.New()
+	logger, _ := zap.NewProduction()
+	options := cassandraStorage.NewOptions("cassandra")
+
+	var rootCmd = &cobra.Command{
+		Use:   "jaeger-cassandra-schema",
+		Short: "Jaeger cassandra-schema manages the schema of the Jaeger keyspace",
+		Long:  "Jaeger cassandra-schema manages the schema of the Jaeger keyspace",
+	}
+
+	versionCommand := &cobra.Command{
+		Use:          "version",
+		Short:        "prints the schema version of the keyspace",
+		Long:         "prints the schema version of the keyspace",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			session, keyspace, err := newSession(v, options, logger)
+			if err != nil {
+				return err
+			}
+			defer session.Close()
+			version, err := schema.DetectVersion(session, keyspace)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("keyspace %s: schema version %d, latest version %d\n", keyspace, version, schema.LatestVersion)
+			return nil
+		},
+	}
+
+	validateCommand := &cobra.Command{
+		Use:          "validate",
+		Short:        "compares the schema of the keyspace with the one expected by Jaeger",
+		Long:         "compares the tables, columns and compaction strategies of the keyspace with the ones expected by Jaeger",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			session, keyspace, err := newSession(v, options, logger)
+			if err != nil {
+				return err
+			}
+			defer session.Close()
+			report, err := schema.Validate(session, keyspace)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("keyspace %s: schema version %d, latest version %d\n", keyspace, report.Version, schema.LatestVersion)
+			if len(report.Mismatches) > 0 {
+				fmt.Printf("the schema does not match the expected schema:\n  %s\n", strings.Join(report.Mismatches, "\n  "))
+				return errors.New("schema mismatch")
+			}
+			fmt.Println("the schema matches the expected schema")
+			return nil
+		},
+	}
+
+	migrateCfg := &app.Config{}
+	migrateCommand := &cobra.Command{
+		Use:   "migrate",
+		Short: "creates the keyspace or migrates its schema to the latest version",
+		Long: "creates the keyspace if it does not contain any table, or migrates its schema to the latest version. " +
+			"The statements are printed, and only printed in dry-run mode. Migrations can be applied again if interrupted",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrateCfg.InitFromViper(v)
+			session, keyspace, err := newSession(v, options, logger)
+			if err != nil {
+				return err
+			}
+			defer session.Close()
+			migrator := schema.NewMigrator(session, migrateCfg.Params(keyspace), migrateCfg.DryRun, os.Stdout)
+			from, to, err := migrator.Migrate()
+			if err != nil {
+				return err
+			}
+			if from == to {
+				logger.Info("The schema is up to date", zap.String("keyspace", keyspace), zap.Int("version", to))
+			} else {
+				logger.Info("Migrated the schema", zap.String("keyspace", keyspace),
+					zap.Int("from", from), zap.Int("to", to), zap.Bool("dry-run", migrateCfg.DryRun))
+			}
+			return nil
+		},
+	}
+
+	addPersistentFlags(v, rootCmd, options.AddFlags)
+	rootCmd.AddCommand(versionCommand, validateCommand)
+	addSubCommand(v, rootCmd, migrateCommand, migrateCfg.AddFlags)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newSession creates a session which is not bound to the keyspace, since the keyspace may not exist yet.
+// The statements are qualified with the keyspace instead.
+func newSession(v *viper.Viper, options *cassandraStorage.Options, logger *zap.Logger) (cassandra.Session, string, error) {
+	options.InitFromViper(v)
+	cfg := *options.GetPrimary()
+	keyspace := cfg.Keyspace
+	cfg.Keyspace = ""
+	session, err := cfg.NewSession(logger)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, keyspace, nil
+}
+
+func addSubCommand(v *viper.Viper, rootCmd, cmd *cobra.Command, addFlags func(*flag.FlagSet)) {
+	rootCmd.AddCommand(cmd)
+	config.AddFlags(
+		v,
+		cmd,
+		addFlags,
+	)
+}
+
+func addPersistentFlags(v *viper.Viper, rootCmd *cobra.Command, inits ...func(*flag.FlagSet)) {
+	flagSet := new(flag.FlagSet)
+	for i := range inits {
+		inits[i](flagSet)
+	}
+	rootCmd.PersistentFlags().AddGoFlagSet(flagSet)
+	v.BindPFlags(rootCmd.PersistentFlags())
+}
@@ -17,25 +17,58 @@ package app
 
 import (
 	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/rules"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/ports"
 )
 
+const (
+	// QueueTypeMemory keeps the queued spans in memory
+	QueueTypeMemory = "memory"
+	// QueueTypeDisk writes the queued spans to disk, so that they survive storage outages and restarts
+	QueueTypeDisk = "disk"
+)
+
 const (
 	collectorDynQueueSizeMemory          = "collector.queue-size-memory"
 	collectorGRPCHostPort                = "collector.grpc-server.host-port"
 	collectorHTTPHostPort                = "collector.http-server.host-port"
 	collectorNumWorkers                  = "collector.num-workers"
 	collectorQueueSize                   = "collector.queue-size"
+	collectorQueueType                   = "collector.queue-type"
+	collectorDiskQueueDirectory          = "collector.queue.disk.directory"
+	collectorDiskQueueMaxSize            = "collector.queue.disk.max-size"
+	collectorDiskQueueSegmentSize        = "collector.queue.disk.segment-size"
+	collectorDiskQueueFsync              = "collector.queue.disk.fsync"
+	collectorDiskQueueFsyncInterval      = "collector.queue.disk.fsync-interval"
 	collectorTags                        = "collector.tags"
 	collectorZipkinAllowedHeaders        = "collector.zipkin.allowed-headers"
 	collectorZipkinAllowedOrigins        = "collector.zipkin.allowed-origins"
 	collectorZipkinHTTPHostPort          = "collector.zipkin.host-port"
 	collectorGRPCMaxReceiveMessageLength = "collector.grpc-server.max-message-size"
+	collectorOTLPEnabled                 = "collector.otlp.enabled"
+	collectorRateLimitServiceSpans       = "collector.rate-limit.service-spans-per-second"
+	collectorRateLimitServices           = "collector.rate-limit.services"
+	collectorRateLimitTenantSpans        = "collector.rate-limit.tenant-spans-per-second"
+	collectorRateLimitBurst              = "collector.rate-limit.burst"
+	collectorFairQueuingEnabled          = "collector.fair-queuing.enabled"
+	collectorFairQueuingWeights          = "collector.fair-queuing.weights"
+	collectorOTLPGRPCHostPort            = "collector.otlp.grpc.host-port"
+	collectorOTLPHTTPHostPort            = "collector.otlp.http.host-port"
 )
 
 var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
@@ -46,12 +79,26 @@ var tlsHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
 	Prefix: "collector.http",
 }
 
+var tlsOTLPGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
+	Prefix: "collector.otlp.grpc",
+}
+
+var tlsOTLPHTTPFlagsConfig = tlscfg.ServerFlagsConfig{
+	Prefix: "collector.otlp.http",
+}
+
 // CollectorOptions holds configuration for collector
 type CollectorOptions struct {
 	// DynQueueSizeMemory determines how much memory to use for the queue
 	DynQueueSizeMemory uint
 	// QueueSize is the size of collector's queue
 	QueueSize int
+	// Throttling configures the per-service and per-tenant admission of spans
+	Throttling ThrottlingOptions
+	// QueueType is the implementation of the collector's queue, memory or disk
+	QueueType string
+	// DiskQueue configures the disk queue
+	DiskQueue DiskQueueOptions
 	// NumWorkers is the number of internal workers in a collector
 	NumWorkers int
 	// CollectorHTTPHostPort is the host:port address that the collector service listens in on for http requests
@@ -72,6 +119,65 @@ type CollectorOptions struct {
 	CollectorZipkinAllowedHeaders string
 	// CollectorGRPCMaxReceiveMessageLength is the maximum message size receivable by the gRPC Collector.
 	CollectorGRPCMaxReceiveMessageLength int
+	// OTLP configures the receivers for OpenTelemetry OTLP spans
+	OTLP OTLPOptions
+	// SanitizerRules configures the optional rule based sanitizer applied to every span
+	SanitizerRules rules.Flags
+	// TailSampling configures the optional tail-based sampling stage in front of the span writer
+	TailSampling tailsampling.Flags
+	// SpanMetrics configures the optional aggregation of RED metrics from the received spans
+	SpanMetrics spanmetrics.Flags
+	// Dependencies configures the optional streaming computation of the service dependency links
+	Dependencies dependencies.Flags
+	// Tenancy configures the isolation of the received spans per tenant
+	Tenancy tenancy.Options
+}
+
+// DefaultThrottlingBurst is the default duration of spans accepted at once by the rate limits
+const DefaultThrottlingBurst = time.Second
+
+// ThrottlingOptions holds configuration for the per-service and per-tenant admission of spans
+type ThrottlingOptions struct {
+	// ServiceSpansPerSecond is the rate limit of each service, 0 for no limit
+	ServiceSpansPerSecond float64
+	// ServiceSpansPerSecondOverrides are the rate limits of specific services
+	ServiceSpansPerSecondOverrides map[string]float64
+	// TenantSpansPerSecond is the rate limit of each tenant, 0 for no limit
+	TenantSpansPerSecond float64
+	// Burst is the duration of spans at the limited rate accepted at once
+	Burst time.Duration
+	// FairQueuing limits each service to a weighted share of the queue when several services have spans queued
+	FairQueuing bool
+	// FairQueuingWeights are the weights of specific services, the default weight is 1
+	FairQueuingWeights map[string]float64
+}
+
+// DiskQueueOptions holds configuration for the disk queue
+type DiskQueueOptions struct {
+	// Directory holds the segment files of the queue
+	Directory string
+	// MaxSize is the maximum size in bytes of the queue on disk
+	MaxSize int64
+	// SegmentSize is the size in bytes of the segment files
+	SegmentSize int64
+	// SyncPolicy is when the queued spans are flushed to disk: always, interval or never
+	SyncPolicy string
+	// SyncInterval is how often the queued spans are flushed to disk with the interval policy
+	SyncInterval time.Duration
+}
+
+// OTLPOptions holds configuration for the OTLP receivers
+type OTLPOptions struct {
+	// Enabled turns on the OTLP/gRPC and OTLP/HTTP receivers
+	Enabled bool
+	// GRPCHostPort is the host:port address that the collector listens in on for OTLP/gRPC requests
+	GRPCHostPort string
+	// HTTPHostPort is the host:port address that the collector listens in on for OTLP/HTTP requests
+	HTTPHostPort string
+	// TLSGRPC configures secure transport for the OTLP/gRPC endpoint
+	TLSGRPC tlscfg.Options
+	// TLSHTTP configures secure transport for the OTLP/HTTP endpoint
+	TLSHTTP tlscfg.Options
 }
 
 // AddFlags adds flags for CollectorOptions
@@ -86,9 +192,30 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.String(collectorZipkinAllowedOrigins, "*", "Comma separated list of allowed origins for the Zipkin collector service, default accepts all")
 	flags.String(collectorZipkinHTTPHostPort, "", "The host:port (e.g. 127.0.0.1:9411 or :9411) of the collector's Zipkin server (disabled by default)")
 	flags.Uint(collectorDynQueueSizeMemory, 0, "(experimental) The max memory size in MiB to use for the dynamic queue.")
+	flags.Float64(collectorRateLimitServiceSpans, 0, "The maximum number of spans per second accepted from each service, 0 for no limit. Batches exceeding the limit are rejected with a retryable busy error")
+	flags.String(collectorRateLimitServices, "", "The span rate limits of specific services, overriding the default one. Ex: frontend=1000,batch-job=50")
+	flags.Float64(collectorRateLimitTenantSpans, 0, "The maximum number of spans per second accepted from each tenant, 0 for no limit")
+	flags.Duration(collectorRateLimitBurst, DefaultThrottlingBurst, "The duration of spans at the limited rate which can be accepted at once")
+	flags.Bool(collectorFairQueuingEnabled, false, "Limits each service to a weighted share of the queue capacity while other services have spans queued")
+	flags.String(collectorFairQueuingWeights, "", "The fair queuing weights of specific services, the default weight is 1. Ex: frontend=2,batch-job=0.5")
+	flags.String(collectorQueueType, QueueTypeMemory, fmt.Sprintf("(experimental) The implementation of the queue, %s or %s. The disk queue survives storage outages and collector restarts", QueueTypeMemory, QueueTypeDisk))
+	flags.String(collectorDiskQueueDirectory, "", "The directory of the disk queue")
+	flags.Uint(collectorDiskQueueMaxSize, queue.DefaultMaxSize/1024/1024, "The max size in MiB of the disk queue, spans are dropped when it is full")
+	flags.Uint(collectorDiskQueueSegmentSize, queue.DefaultSegmentSize/1024/1024, "The size in MiB of the segment files of the disk queue")
+	flags.String(collectorDiskQueueFsync, queue.SyncInterval, fmt.Sprintf("When the spans of the disk queue are flushed to disk: %s, %s or %s", queue.SyncAlways, queue.SyncInterval, queue.SyncNever))
+	flags.Duration(collectorDiskQueueFsyncInterval, queue.DefaultSyncInterval, "How often the spans of the disk queue are flushed to disk with the interval fsync policy")
+	flags.Bool(collectorOTLPEnabled, false, "Enables OpenTelemetry OTLP receivers on dedicated gRPC and HTTP ports")
+	flags.String(collectorOTLPGRPCHostPort, ports.PortToHostPort(ports.CollectorOTLPGRPC), "The host:port (e.g. 127.0.0.1:4317 or :4317) of the collector's OTLP/gRPC server")
+	flags.String(collectorOTLPHTTPHostPort, ports.PortToHostPort(ports.CollectorOTLPHTTP), "The host:port (e.g. 127.0.0.1:4318 or :4318) of the collector's OTLP/HTTP server")
 
 	tlsGRPCFlagsConfig.AddFlags(flags)
 	tlsHTTPFlagsConfig.AddFlags(flags)
+	tlsOTLPGRPCFlagsConfig.AddFlags(flags)
+	tlsOTLPHTTPFlagsConfig.AddFlags(flags)
+	rules.AddFlags(flags)
+	tailsampling.AddFlags(flags)
+	spanmetrics.AddFlags(flags)
+	dependencies.AddFlags(flags)
 }
 
 // InitFromViper initializes CollectorOptions with properties from viper
@@ -102,9 +229,51 @@ func (cOpts *CollectorOptions) InitFromViper(v *viper.Viper) *CollectorOptions {
 	cOpts.DynQueueSizeMemory = v.GetUint(collectorDynQueueSizeMemory) * 1024 * 1024 // we receive in MiB and store in bytes
 	cOpts.NumWorkers = v.GetInt(collectorNumWorkers)
 	cOpts.QueueSize = v.GetInt(collectorQueueSize)
+	cOpts.QueueType = v.GetString(collectorQueueType)
+	cOpts.Throttling.ServiceSpansPerSecond = v.GetFloat64(collectorRateLimitServiceSpans)
+	cOpts.Throttling.ServiceSpansPerSecondOverrides = parseServiceValues(v.GetString(collectorRateLimitServices))
+	cOpts.Throttling.TenantSpansPerSecond = v.GetFloat64(collectorRateLimitTenantSpans)
+	cOpts.Throttling.Burst = v.GetDuration(collectorRateLimitBurst)
+	cOpts.Throttling.FairQueuing = v.GetBool(collectorFairQueuingEnabled)
+	cOpts.Throttling.FairQueuingWeights = parseServiceValues(v.GetString(collectorFairQueuingWeights))
+	cOpts.DiskQueue.Directory = v.GetString(collectorDiskQueueDirectory)
+	cOpts.DiskQueue.MaxSize = int64(v.GetUint(collectorDiskQueueMaxSize)) * 1024 * 1024
+	cOpts.DiskQueue.SegmentSize = int64(v.GetUint(collectorDiskQueueSegmentSize)) * 1024 * 1024
+	cOpts.DiskQueue.SyncPolicy = v.GetString(collectorDiskQueueFsync)
+	cOpts.DiskQueue.SyncInterval = v.GetDuration(collectorDiskQueueFsyncInterval)
 	cOpts.TLSGRPC = tlsGRPCFlagsConfig.InitFromViper(v)
 	cOpts.TLSHTTP = tlsHTTPFlagsConfig.InitFromViper(v)
 	cOpts.CollectorGRPCMaxReceiveMessageLength = v.GetInt(collectorGRPCMaxReceiveMessageLength)
+	cOpts.OTLP.Enabled = v.GetBool(collectorOTLPEnabled)
+	cOpts.OTLP.GRPCHostPort = ports.FormatHostPort(v.GetString(collectorOTLPGRPCHostPort))
+	cOpts.OTLP.HTTPHostPort = ports.FormatHostPort(v.GetString(collectorOTLPHTTPHostPort))
+	cOpts.OTLP.TLSGRPC = tlsOTLPGRPCFlagsConfig.InitFromViper(v)
+	cOpts.OTLP.TLSHTTP = tlsOTLPHTTPFlagsConfig.InitFromViper(v)
+	cOpts.SanitizerRules.InitFromViper(v)
+	cOpts.TailSampling.InitFromViper(v)
+	cOpts.SpanMetrics.InitFromViper(v)
+	cOpts.Dependencies.InitFromViper(v)
+	cOpts.Tenancy = tenancy.InitFromViper(v)
 
 	return cOpts
 }
+
+// parseServiceValues parses a comma separated list of service=number pairs. The values which
+// are not numbers are set to NaN, to be reported when the options are validated.
+func parseServiceValues(s string) map[string]float64 {
+	if s == "" {
+		return nil
+	}
+	values := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		value := math.NaN()
+		if len(kv) == 2 {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+				value = v
+			}
+		}
+		values[strings.TrimSpace(kv[0])] = value
+	}
+	return values
+}
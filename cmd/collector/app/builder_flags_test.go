@@ -15,7 +15,9 @@
 package app
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -62,3 +64,76 @@ func TestCollectorOptionsWithFlags_CheckMaxReceiveMessageLength(t *testing.T) {
 
 	assert.Equal(t, 8388608, c.CollectorGRPCMaxReceiveMessageLength)
 }
+
+func TestCollectorOptionsWithFlags_CheckOTLP(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.otlp.enabled=true",
+		"--collector.otlp.grpc.host-port=1234",
+		"--collector.otlp.http.host-port=127.0.0.1:5678",
+	})
+	c.InitFromViper(v)
+
+	assert.True(t, c.OTLP.Enabled)
+	assert.Equal(t, ":1234", c.OTLP.GRPCHostPort)
+	assert.Equal(t, "127.0.0.1:5678", c.OTLP.HTTPHostPort)
+}
+
+func TestCollectorOptionsWithFlags_CheckDiskQueue(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.queue-type=disk",
+		"--collector.queue.disk.directory=/var/lib/jaeger/queue",
+		"--collector.queue.disk.max-size=100",
+		"--collector.queue.disk.segment-size=10",
+		"--collector.queue.disk.fsync=always",
+		"--collector.queue.disk.fsync-interval=5s",
+	})
+	c.InitFromViper(v)
+
+	assert.Equal(t, QueueTypeDisk, c.QueueType)
+	assert.Equal(t, DiskQueueOptions{
+		Directory:    "/var/lib/jaeger/queue",
+		MaxSize:      100 * 1024 * 1024,
+		SegmentSize:  10 * 1024 * 1024,
+		SyncPolicy:   "always",
+		SyncInterval: 5 * time.Second,
+	}, c.DiskQueue)
+}
+
+func TestCollectorOptionsWithFlags_CheckThrottling(t *testing.T) {
+	c := &CollectorOptions{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.rate-limit.service-spans-per-second=100",
+		"--collector.rate-limit.services=frontend=1000, batch-job=0.5,broken=x",
+		"--collector.rate-limit.tenant-spans-per-second=5000",
+		"--collector.rate-limit.burst=5s",
+		"--collector.fair-queuing.enabled=true",
+		"--collector.fair-queuing.weights=frontend=2",
+	})
+	c.InitFromViper(v)
+
+	assert.Equal(t, 100.0, c.Throttling.ServiceSpansPerSecond)
+	assert.Len(t, c.Throttling.ServiceSpansPerSecondOverrides, 3)
+	assert.Equal(t, 1000.0, c.Throttling.ServiceSpansPerSecondOverrides["frontend"])
+	assert.Equal(t, 0.5, c.Throttling.ServiceSpansPerSecondOverrides["batch-job"])
+	assert.True(t, math.IsNaN(c.Throttling.ServiceSpansPerSecondOverrides["broken"]))
+	assert.Equal(t, 5000.0, c.Throttling.TenantSpansPerSecond)
+	assert.Equal(t, 5*time.Second, c.Throttling.Burst)
+	assert.True(t, c.Throttling.FairQueuing)
+	assert.Equal(t, map[string]float64{"frontend": 2}, c.Throttling.FairQueuingWeights)
+	assert.Error(t, c.Throttling.Validate())
+}
+
+func TestCollectorOptionsWithFlags_CheckThrottlingDefaults(t *testing.T) {
+	c := &CollectorOptions{}
+	v, _ := config.Viperize(AddFlags)
+	c.InitFromViper(v)
+
+	assert.False(t, c.Throttling.Enabled())
+	assert.Equal(t, DefaultThrottlingBurst, c.Throttling.Burst)
+	assert.Nil(t, c.Throttling.ServiceSpansPerSecondOverrides)
+}
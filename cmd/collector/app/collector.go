@@ -19,16 +19,25 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/rules"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/server"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -44,13 +53,24 @@ type Collector struct {
 	hCheck         *healthcheck.HealthCheck
 	spanProcessor  processor.SpanProcessor
 	spanHandlers   *SpanHandlers
+	ruleSanitizer  *rules.Sanitizer
+	tailSampler    *tailsampling.Writer
+	spanMetrics    *spanmetrics.Aggregator
+	metricsWriter  metricsstore.SpanMetricsWriter
+	depWriter      dependencystore.Writer
+	dependencies   *dependencies.Aggregator
+	tenancyMgr     *tenancy.Manager
 
 	// state, read only
-	hServer                  *http.Server
-	zkServer                 *http.Server
-	grpcServer               *grpc.Server
-	tlsGRPCCertWatcherCloser io.Closer
-	tlsHTTPCertWatcherCloser io.Closer
+	hServer                      *http.Server
+	zkServer                     *http.Server
+	grpcServer                   *grpc.Server
+	otlpGRPCServer               *grpc.Server
+	otlpHTTPServer               *http.Server
+	tlsGRPCCertWatcherCloser     io.Closer
+	tlsHTTPCertWatcherCloser     io.Closer
+	tlsOTLPGRPCCertWatcherCloser io.Closer
+	tlsOTLPHTTPCertWatcherCloser io.Closer
 }
 
 // CollectorParams to construct a new Jaeger Collector.
@@ -62,10 +82,20 @@ type CollectorParams struct {
 	StrategyStore  strategystore.StrategyStore
 	Aggregator     strategystore.Aggregator
 	HealthCheck    *healthcheck.HealthCheck
+	// SpanMetricsWriter, when set, persists the aggregated span metrics if enabled by the collector options
+	SpanMetricsWriter metricsstore.SpanMetricsWriter
+	// DependencyWriter, when set, receives the dependency links computed from the spans if enabled by the collector options
+	DependencyWriter dependencystore.Writer
+	// TenancyMgr validates the tenants of the received spans, multi-tenancy is disabled if nil
+	TenancyMgr *tenancy.Manager
 }
 
 // New constructs a new collector component, ready to be started
 func New(params *CollectorParams) *Collector {
+	tenancyMgr := params.TenancyMgr
+	if tenancyMgr == nil {
+		tenancyMgr = &tenancy.Manager{}
+	}
 	return &Collector{
 		serviceName:    params.ServiceName,
 		logger:         params.Logger,
@@ -74,24 +104,69 @@ func New(params *CollectorParams) *Collector {
 		strategyStore:  params.StrategyStore,
 		aggregator:     params.Aggregator,
 		hCheck:         params.HealthCheck,
+		metricsWriter:  params.SpanMetricsWriter,
+		depWriter:      params.DependencyWriter,
+		tenancyMgr:     tenancyMgr,
 	}
 }
 
 // Start the component and underlying dependencies
 func (c *Collector) Start(builderOpts *CollectorOptions) error {
+	spanWriter := c.spanWriter
+	if builderOpts.TailSampling.Enabled {
+		tailSampler, err := c.createTailSampler(&builderOpts.TailSampling)
+		if err != nil {
+			return err
+		}
+		c.tailSampler = tailSampler
+		spanWriter = tailSampler
+	}
+
 	handlerBuilder := &SpanHandlerBuilder{
-		SpanWriter:     c.spanWriter,
+		SpanWriter:     spanWriter,
 		CollectorOpts:  *builderOpts,
 		Logger:         c.logger,
 		MetricsFactory: c.metricsFactory,
+		TenancyMgr:     c.tenancyMgr,
+	}
+	if builderOpts.SanitizerRules.RulesFile != "" {
+		ruleSanitizer, err := rules.New(rules.Params{
+			Path:           builderOpts.SanitizerRules.RulesFile,
+			MetricsFactory: c.metricsFactory.Namespace(metrics.NSOptions{Name: "sanitizer"}),
+			Logger:         c.logger,
+		})
+		if err != nil {
+			return fmt.Errorf("could not load the sanitizer rules %w", err)
+		}
+		c.ruleSanitizer = ruleSanitizer
+		handlerBuilder.Sanitizer = ruleSanitizer.Sanitize
 	}
 
 	var additionalProcessors []ProcessSpan
 	if c.aggregator != nil {
 		additionalProcessors = append(additionalProcessors, handleRootSpan(c.aggregator, c.logger))
 	}
+	if observer, ok := c.strategyStore.(strategystore.SpanObserver); ok {
+		additionalProcessors = append(additionalProcessors, ignoreTenant(observer.ObserveSpan))
+	}
+	if builderOpts.SpanMetrics.Enabled {
+		c.spanMetrics = c.createSpanMetricsAggregator(&builderOpts.SpanMetrics)
+		additionalProcessors = append(additionalProcessors, c.spanMetrics.ProcessSpan)
+	}
+	if builderOpts.Dependencies.Enabled {
+		depAggregator, err := c.createDependenciesAggregator(&builderOpts.Dependencies)
+		if err != nil {
+			return err
+		}
+		c.dependencies = depAggregator
+		additionalProcessors = append(additionalProcessors, c.dependencies.ProcessSpan)
+	}
 
-	c.spanProcessor = handlerBuilder.BuildSpanProcessor(additionalProcessors...)
+	spanProcessor, err := handlerBuilder.BuildSpanProcessor(additionalProcessors...)
+	if err != nil {
+		return fmt.Errorf("could not create the span processor %w", err)
+	}
+	c.spanProcessor = spanProcessor
 	c.spanHandlers = handlerBuilder.BuildHandlers(c.spanProcessor)
 
 	grpcServer, err := server.StartGRPCServer(&server.GRPCServerParams{
@@ -115,6 +190,7 @@ func (c *Collector) Start(builderOpts *CollectorOptions) error {
 		MetricsFactory: c.metricsFactory,
 		SamplingStore:  c.strategyStore,
 		Logger:         c.logger,
+		TenancyMgr:     c.tenancyMgr,
 	})
 	if err != nil {
 		return fmt.Errorf("could not start the HTTP server %w", err)
@@ -131,17 +207,127 @@ func (c *Collector) Start(builderOpts *CollectorOptions) error {
 		AllowedOrigins: builderOpts.CollectorZipkinAllowedOrigins,
 		Logger:         c.logger,
 		MetricsFactory: c.metricsFactory,
+		TenancyMgr:     c.tenancyMgr,
 	})
 	if err != nil {
 		return fmt.Errorf("could not start the Zipkin server %w", err)
 	}
 	c.zkServer = zkServer
 
+	if builderOpts.OTLP.Enabled {
+		if err := c.startOTLPServers(builderOpts); err != nil {
+			return err
+		}
+	}
+
 	c.publishOpts(builderOpts)
 
 	return nil
 }
 
+// ignoreTenant adapts the span consumers which observe the spans of all tenants together
+func ignoreTenant(processSpan func(span *model.Span)) ProcessSpan {
+	return func(span *model.Span, _ string) {
+		processSpan(span)
+	}
+}
+
+func (c *Collector) createTailSampler(opts *tailsampling.Flags) (*tailsampling.Writer, error) {
+	if opts.PoliciesFile == "" {
+		return nil, fmt.Errorf("tail sampling is enabled but no policies file is configured")
+	}
+	policies, err := tailsampling.LoadPolicies(opts.PoliciesFile)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info("Tail sampling enabled",
+		zap.Duration("decision-wait", opts.DecisionWait),
+		zap.Int("max-traces", opts.MaxTraces),
+		zap.Int("policies", len(policies)))
+	return tailsampling.NewWriter(c.spanWriter, tailsampling.Options{
+		DecisionWait:   opts.DecisionWait,
+		MaxTraces:      opts.MaxTraces,
+		Policies:       policies,
+		MetricsFactory: c.metricsFactory.Namespace(metrics.NSOptions{Name: "tail_sampling"}),
+		Logger:         c.logger,
+	}), nil
+}
+
+func (c *Collector) createSpanMetricsAggregator(opts *spanmetrics.Flags) *spanmetrics.Aggregator {
+	var writer metricsstore.SpanMetricsWriter
+	if opts.Persist {
+		if c.metricsWriter == nil {
+			c.logger.Warn("Span metrics persistence is enabled but the span storage does not support it")
+		}
+		writer = c.metricsWriter
+	}
+	hostname, _ := os.Hostname()
+	c.logger.Info("Span metrics aggregation enabled",
+		zap.Duration("bucket-interval", opts.BucketInterval),
+		zap.Duration("retention", opts.Retention),
+		zap.Int("max-series", opts.MaxSeries),
+		zap.Bool("persist", writer != nil))
+	return spanmetrics.NewAggregator(spanmetrics.Options{
+		BucketInterval: opts.BucketInterval,
+		Retention:      opts.Retention,
+		MaxSeries:      opts.MaxSeries,
+		Source:         hostname,
+		Writer:         writer,
+		MetricsFactory: c.metricsFactory.Namespace(metrics.NSOptions{Name: "span_metrics"}),
+		Logger:         c.logger,
+	})
+}
+
+func (c *Collector) createDependenciesAggregator(opts *dependencies.Flags) (*dependencies.Aggregator, error) {
+	if c.depWriter == nil {
+		return nil, fmt.Errorf("streaming dependencies are enabled but the dependencies storage does not support writing")
+	}
+	if _, ok := c.depWriter.(dependencystore.TenantWriter); c.tenancyMgr.Enabled && !ok {
+		return nil, fmt.Errorf("streaming dependencies cannot be used with multi-tenancy, the dependencies storage does not isolate the tenants")
+	}
+	c.logger.Info("Streaming dependencies enabled",
+		zap.Duration("flush-interval", opts.FlushInterval),
+		zap.Duration("span-cache-ttl", opts.SpanCacheTTL),
+		zap.Int("max-cached-spans", opts.MaxCachedSpans))
+	return dependencies.NewAggregator(c.depWriter, dependencies.Options{
+		FlushInterval:  opts.FlushInterval,
+		SpanCacheTTL:   opts.SpanCacheTTL,
+		MaxCachedSpans: opts.MaxCachedSpans,
+		MetricsFactory: c.metricsFactory.Namespace(metrics.NSOptions{Name: "dependencies"}),
+		Logger:         c.logger,
+	}), nil
+}
+
+func (c *Collector) startOTLPServers(builderOpts *CollectorOptions) error {
+	otlpGRPCServer, err := server.StartOTLPGRPCServer(&server.OTLPGRPCServerParams{
+		HostPort:                builderOpts.OTLP.GRPCHostPort,
+		Handler:                 c.spanHandlers.OTLPHandler,
+		TLSConfig:               builderOpts.OTLP.TLSGRPC,
+		Logger:                  c.logger,
+		MaxReceiveMessageLength: builderOpts.CollectorGRPCMaxReceiveMessageLength,
+	})
+	if err != nil {
+		return fmt.Errorf("could not start the OTLP gRPC server %w", err)
+	}
+	c.otlpGRPCServer = otlpGRPCServer
+	c.tlsOTLPGRPCCertWatcherCloser = &builderOpts.OTLP.TLSGRPC
+
+	otlpHTTPServer, err := server.StartOTLPHTTPServer(&server.OTLPHTTPServerParams{
+		HostPort:       builderOpts.OTLP.HTTPHostPort,
+		Handler:        c.spanHandlers.OTLPHandler,
+		TLSConfig:      builderOpts.OTLP.TLSHTTP,
+		HealthCheck:    c.hCheck,
+		MetricsFactory: c.metricsFactory,
+		Logger:         c.logger,
+	})
+	if err != nil {
+		return fmt.Errorf("could not start the OTLP HTTP server %w", err)
+	}
+	c.otlpHTTPServer = otlpHTTPServer
+	c.tlsOTLPHTTPCertWatcherCloser = &builderOpts.OTLP.TLSHTTP
+	return nil
+}
+
 func (c *Collector) publishOpts(cOpts *CollectorOptions) {
 	internalFactory := c.metricsFactory.Namespace(metrics.NSOptions{Name: "internal"})
 	internalFactory.Gauge(metrics.Options{Name: collectorNumWorkers}).Update(int64(cOpts.NumWorkers))
@@ -173,10 +359,49 @@ func (c *Collector) Close() error {
 		defer cancel()
 	}
 
+	// OTLP servers
+	if c.otlpGRPCServer != nil {
+		c.otlpGRPCServer.GracefulStop()
+	}
+	if c.otlpHTTPServer != nil {
+		timeout, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := c.otlpHTTPServer.Shutdown(timeout); err != nil {
+			c.logger.Fatal("failed to stop the OTLP HTTP server", zap.Error(err))
+		}
+		defer cancel()
+	}
+
 	if err := c.spanProcessor.Close(); err != nil {
 		c.logger.Error("failed to close span processor.", zap.Error(err))
 	}
 
+	// the tail sampler must be closed after the span processor, to decide on all buffered traces
+	if c.tailSampler != nil {
+		if err := c.tailSampler.Close(); err != nil {
+			c.logger.Error("failed to close tail sampler.", zap.Error(err))
+		}
+	}
+
+	// the span metrics aggregator is closed after the span processor, to write the metrics of all processed spans
+	if c.spanMetrics != nil {
+		if err := c.spanMetrics.Close(); err != nil {
+			c.logger.Error("failed to close span metrics aggregator.", zap.Error(err))
+		}
+	}
+
+	// the dependencies aggregator is closed after the span processor, to write the links of all processed spans
+	if c.dependencies != nil {
+		if err := c.dependencies.Close(); err != nil {
+			c.logger.Error("failed to close dependencies aggregator.", zap.Error(err))
+		}
+	}
+
+	if c.ruleSanitizer != nil {
+		if err := c.ruleSanitizer.Close(); err != nil {
+			c.logger.Error("failed to close sanitizer rules watcher.", zap.Error(err))
+		}
+	}
+
 	// aggregator does not exist for all strategy stores. only Close() if exists.
 	if c.aggregator != nil {
 		if err := c.aggregator.Close(); err != nil {
@@ -187,10 +412,24 @@ func (c *Collector) Close() error {
 	// watchers actually never return errors from Close
 	_ = c.tlsGRPCCertWatcherCloser.Close()
 	_ = c.tlsHTTPCertWatcherCloser.Close()
+	if c.tlsOTLPGRPCCertWatcherCloser != nil {
+		_ = c.tlsOTLPGRPCCertWatcherCloser.Close()
+	}
+	if c.tlsOTLPHTTPCertWatcherCloser != nil {
+		_ = c.tlsOTLPHTTPCertWatcherCloser.Close()
+	}
 
 	return nil
 }
 
+// SpanMetricsReader returns the span metrics aggregated by the Collector, or nil if the aggregation is not enabled.
+func (c *Collector) SpanMetricsReader() metricsstore.SpanMetricsReader {
+	if c.spanMetrics == nil {
+		return nil
+	}
+	return c.spanMetrics
+}
+
 // SpanHandlers returns span handlers used by the Collector.
 func (c *Collector) SpanHandlers() *SpanHandlers {
 	return c.spanHandlers
@@ -17,17 +17,28 @@ package app
 import (
 	"context"
 	"io"
+	"io/ioutil"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics/fork"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/dependencies"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/rules"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/spanmetrics"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/tailsampling"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -58,6 +69,204 @@ func TestNewCollector(t *testing.T) {
 	assert.NoError(t, c.Close())
 }
 
+func TestNewCollectorWithOTLP(t *testing.T) {
+	hc := healthcheck.New()
+	logger := zap.NewNop()
+	baseMetrics := metricstest.NewFactory(time.Hour)
+	spanWriter := &fakeSpanWriter{}
+	strategyStore := &mockStrategyStore{}
+
+	c := New(&CollectorParams{
+		ServiceName:    "collector",
+		Logger:         logger,
+		MetricsFactory: baseMetrics,
+		SpanWriter:     spanWriter,
+		StrategyStore:  strategyStore,
+		HealthCheck:    hc,
+	})
+	collectorOpts := &CollectorOptions{
+		OTLP: OTLPOptions{
+			Enabled:      true,
+			GRPCHostPort: ":0",
+			HTTPHostPort: ":0",
+		},
+	}
+
+	assert.NoError(t, c.Start(collectorOpts))
+	assert.NotNil(t, c.otlpGRPCServer)
+	assert.NotNil(t, c.otlpHTTPServer)
+	assert.NoError(t, c.Close())
+}
+
+func TestNewCollectorWithTailSampling(t *testing.T) {
+	policiesFile, err := ioutil.TempFile("", "policies*.json")
+	require.NoError(t, err)
+	defer os.Remove(policiesFile.Name())
+	_, err = policiesFile.WriteString(`{"policies": [{"type": "error"}]}`)
+	require.NoError(t, err)
+	require.NoError(t, policiesFile.Close())
+
+	newCollector := func() *Collector {
+		return New(&CollectorParams{
+			ServiceName:    "collector",
+			Logger:         zap.NewNop(),
+			MetricsFactory: metricstest.NewFactory(time.Hour),
+			SpanWriter:     &fakeSpanWriter{},
+			StrategyStore:  &mockStrategyStore{},
+			HealthCheck:    healthcheck.New(),
+		})
+	}
+
+	c := newCollector()
+	err = c.Start(&CollectorOptions{TailSampling: tailsampling.Flags{Enabled: true}})
+	assert.EqualError(t, err, "tail sampling is enabled but no policies file is configured")
+
+	c = newCollector()
+	err = c.Start(&CollectorOptions{TailSampling: tailsampling.Flags{Enabled: true, PoliciesFile: "/does/not/exist"}})
+	assert.Error(t, err)
+
+	c = newCollector()
+	require.NoError(t, c.Start(&CollectorOptions{TailSampling: tailsampling.Flags{Enabled: true, PoliciesFile: policiesFile.Name()}}))
+	assert.NotNil(t, c.tailSampler)
+	assert.NoError(t, c.Close())
+}
+
+func TestNewCollectorWithSanitizerRules(t *testing.T) {
+	rulesFile, err := ioutil.TempFile("", "rules*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(rulesFile.Name())
+	_, err = rulesFile.WriteString(`rules: [{name: drop-secret, action: drop, tag: secret}]`)
+	require.NoError(t, err)
+	require.NoError(t, rulesFile.Close())
+
+	newCollector := func() *Collector {
+		return New(&CollectorParams{
+			ServiceName:    "collector",
+			Logger:         zap.NewNop(),
+			MetricsFactory: metricstest.NewFactory(time.Hour),
+			SpanWriter:     &fakeSpanWriter{},
+			StrategyStore:  &mockStrategyStore{},
+			HealthCheck:    healthcheck.New(),
+		})
+	}
+
+	c := newCollector()
+	err = c.Start(&CollectorOptions{SanitizerRules: rules.Flags{RulesFile: "/does/not/exist"}})
+	assert.Contains(t, err.Error(), "could not load the sanitizer rules")
+
+	c = newCollector()
+	require.NoError(t, c.Start(&CollectorOptions{SanitizerRules: rules.Flags{RulesFile: rulesFile.Name()}}))
+	assert.NotNil(t, c.ruleSanitizer)
+	assert.NoError(t, c.Close())
+}
+
+func TestNewCollectorWithInvalidDiskQueue(t *testing.T) {
+	c := New(&CollectorParams{
+		ServiceName:    "collector",
+		Logger:         zap.NewNop(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		SpanWriter:     &fakeSpanWriter{},
+		StrategyStore:  &mockStrategyStore{},
+		HealthCheck:    healthcheck.New(),
+	})
+	err := c.Start(&CollectorOptions{QueueType: QueueTypeDisk})
+	assert.EqualError(t, err, "could not create the span processor the disk queue requires a directory")
+}
+
+func TestNewCollectorWithSpanMetrics(t *testing.T) {
+	metricsStore := memory.NewSpanMetricsStore()
+	c := New(&CollectorParams{
+		ServiceName:       "collector",
+		Logger:            zap.NewNop(),
+		MetricsFactory:    metricstest.NewFactory(time.Hour),
+		SpanWriter:        &fakeSpanWriter{},
+		StrategyStore:     &mockStrategyStore{},
+		HealthCheck:       healthcheck.New(),
+		SpanMetricsWriter: metricsStore,
+	})
+	require.NoError(t, c.Start(&CollectorOptions{QueueSize: 10, NumWorkers: 1, SpanMetrics: spanmetrics.Flags{Enabled: true, Persist: true}}))
+	require.NotNil(t, c.SpanMetricsReader())
+
+	span := &model.Span{OperationName: "y", Process: &model.Process{ServiceName: "x"}}
+	_, err := c.spanProcessor.ProcessSpans([]*model.Span{span}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	end := time.Now().Add(time.Hour)
+	assert.Eventually(t, func() bool {
+		aggregated, err := c.SpanMetricsReader().GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: time.Time{}, EndTime: end})
+		return err == nil && len(aggregated) == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.Close())
+
+	persisted, err := metricsStore.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: time.Time{}, EndTime: end})
+	require.NoError(t, err)
+	require.Len(t, persisted, 1)
+	assert.Equal(t, "x", persisted[0].ServiceName)
+	assert.Equal(t, int64(1), persisted[0].Calls)
+
+	c = New(&CollectorParams{
+		ServiceName:    "collector",
+		Logger:         zap.NewNop(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		SpanWriter:     &fakeSpanWriter{},
+		StrategyStore:  &mockStrategyStore{},
+		HealthCheck:    healthcheck.New(),
+	})
+	require.NoError(t, c.Start(&CollectorOptions{}))
+	assert.Nil(t, c.SpanMetricsReader())
+	assert.NoError(t, c.Close())
+}
+
+type fakeDependencyWriter struct {
+	mux   sync.Mutex
+	links []model.DependencyLink
+}
+
+func (w *fakeDependencyWriter) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.links = append(w.links, dependencies...)
+	return nil
+}
+
+func TestNewCollectorWithDependencies(t *testing.T) {
+	params := &CollectorParams{
+		ServiceName:    "collector",
+		Logger:         zap.NewNop(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		SpanWriter:     &fakeSpanWriter{},
+		StrategyStore:  &mockStrategyStore{},
+		HealthCheck:    healthcheck.New(),
+	}
+	opts := &CollectorOptions{QueueSize: 10, NumWorkers: 1, Dependencies: dependencies.Flags{Enabled: true, FlushInterval: time.Millisecond}}
+	err := New(params).Start(opts)
+	require.EqualError(t, err, "streaming dependencies are enabled but the dependencies storage does not support writing")
+
+	depWriter := &fakeDependencyWriter{}
+	params.DependencyWriter = depWriter
+	params.TenancyMgr = tenancy.NewManager(&tenancy.Options{Enabled: true})
+	err = New(params).Start(opts)
+	require.EqualError(t, err, "streaming dependencies cannot be used with multi-tenancy, the dependencies storage does not isolate the tenants")
+
+	params.TenancyMgr = nil
+	c := New(params)
+	require.NoError(t, c.Start(opts))
+
+	traceID := model.NewTraceID(0, 1)
+	spans := []*model.Span{
+		{TraceID: traceID, SpanID: 1, Process: &model.Process{ServiceName: "x"}},
+		{TraceID: traceID, SpanID: 2, Process: &model.Process{ServiceName: "y"}, References: []model.SpanRef{model.NewChildOfRef(traceID, 1)}},
+	}
+	_, err = c.spanProcessor.ProcessSpans(spans, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		depWriter.mux.Lock()
+		defer depWriter.mux.Unlock()
+		return len(depWriter.links) == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.Close())
+	assert.Equal(t, []model.DependencyLink{{Parent: "x", Child: "y", CallCount: 1}}, depWriter.links)
+}
+
 type mockStrategyStore struct {
 }
 
@@ -157,3 +366,45 @@ func TestAggregator(t *testing.T) {
 	// assert that aggregator close was called
 	assert.Equal(t, 1, agg.closeCount)
 }
+
+type mockObservingStrategyStore struct {
+	mockStrategyStore
+	mux   sync.Mutex
+	spans []*model.Span
+}
+
+func (m *mockObservingStrategyStore) ObserveSpan(span *model.Span) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.spans = append(m.spans, span)
+}
+
+func TestSpanObserver(t *testing.T) {
+	strategyStore := &mockObservingStrategyStore{}
+	c := New(&CollectorParams{
+		ServiceName:    "collector",
+		Logger:         zap.NewNop(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		SpanWriter:     &fakeSpanWriter{},
+		StrategyStore:  strategyStore,
+		HealthCheck:    healthcheck.New(),
+	})
+	require.NoError(t, c.Start(&CollectorOptions{
+		QueueSize:  10,
+		NumWorkers: 10,
+	}))
+
+	_, err := c.spanProcessor.ProcessSpans([]*model.Span{
+		{
+			OperationName: "y",
+			Process:       &model.Process{ServiceName: "x"},
+		},
+	}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+
+	strategyStore.mux.Lock()
+	defer strategyStore.mux.Unlock()
+	require.Len(t, strategyStore.spans, 1)
+	assert.Equal(t, "y", strategyStore.spans[0].OperationName)
+}
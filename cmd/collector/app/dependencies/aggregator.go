@@ -0,0 +1,415 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// ErrTenantsNotIsolated is returned when the links of a tenant cannot be written apart from the other tenants.
+var ErrTenantsNotIsolated = errors.New("the dependencies storage does not isolate the tenants")
+
+const (
+	// DefaultFlushInterval is the default interval between writes of the dependency links
+	DefaultFlushInterval = time.Minute
+	// DefaultSpanCacheTTL is the default time spans are remembered to be linked with spans received later
+	DefaultSpanCacheTTL = 30 * time.Second
+	// DefaultMaxCachedSpans is the default maximum number of spans remembered
+	DefaultMaxCachedSpans = 100000
+)
+
+type aggregatorMetrics struct {
+	// SpansProcessed counts the spans inspected for dependency links
+	SpansProcessed metrics.Counter `metric:"spans_processed"`
+	// LateParents counts the children linked when their parent was received after them
+	LateParents metrics.Counter `metric:"late_parents"`
+	// Orphans counts the children whose parent was not received within the span cache TTL
+	Orphans metrics.Counter `metric:"orphans"`
+	// CachedSpans is the number of spans remembered to be linked with their children
+	CachedSpans metrics.Gauge `metric:"cached_spans"`
+	// PendingParents is the number of parents waited for by children received before them
+	PendingParents metrics.Gauge `metric:"pending_parents"`
+	// FlushedLinks counts the dependency links written to storage
+	FlushedLinks metrics.Counter `metric:"flushed_links"`
+	// FlushedOperationLinks counts the dependency links between operations written to storage
+	FlushedOperationLinks metrics.Counter `metric:"flushed_operation_links"`
+	// FlushErrors counts the failed writes to storage, the links are retried on the next flush
+	FlushErrors metrics.Counter `metric:"flush_errors"`
+	// FlushLatency measures the time to write the dependency links to storage
+	FlushLatency metrics.Timer `metric:"flush_latency"`
+	// FlushLag is the time in milliseconds since the last successful flush
+	FlushLag metrics.Gauge `metric:"flush_lag"`
+}
+
+// Options configures the dependencies Aggregator.
+type Options struct {
+	// FlushInterval is how often the dependency links are written to storage
+	FlushInterval time.Duration
+	// SpanCacheTTL is how long spans are remembered to be linked with spans received later
+	SpanCacheTTL time.Duration
+	// MaxCachedSpans bounds the number of spans remembered, and of parents waited for
+	MaxCachedSpans int
+	// MetricsFactory is used to report the aggregator metrics
+	MetricsFactory metrics.Factory
+	// Logger is used to report write errors
+	Logger *zap.Logger
+}
+
+// spanKey identifies a span of a tenant, the spans of different tenants are never linked.
+type spanKey struct {
+	tenant  string
+	traceID model.TraceID
+	spanID  model.SpanID
+}
+
+func (k spanKey) String() string {
+	return k.tenant + ":" + k.traceID.String() + ":" + k.spanID.String()
+}
+
+type linkKey struct {
+	tenant string
+	parent string
+	child  string
+}
+
+// spanInfo is what is remembered of a span to link it with its children.
+type spanInfo struct {
+	service   string
+	operation string
+}
+
+// childSpan is what is remembered of a span to link it with its parent.
+type childSpan struct {
+	spanInfo
+	duration time.Duration
+	isError  bool
+}
+
+// pendingParent holds the children received before their parent.
+type pendingParent struct {
+	key      spanKey
+	arrival  time.Time
+	children []childSpan
+}
+
+// Aggregator derives the parent to child service links from the spans as they are received by the collector,
+// and periodically writes the call counts accumulated since the previous write to the dependencies storage,
+// apart for each tenant.
+// When the storage supports them, the links between the operations of the services are written as well,
+// with the error count and latency of the calls.
+// Recently received spans are remembered for a short time, so that children received before their parent are
+// linked once the parent arrives.
+type Aggregator struct {
+	writer          dependencystore.Writer
+	tenantWriter    dependencystore.TenantWriter    // nil if the storage does not isolate the tenants
+	operationWriter dependencystore.OperationWriter // nil if the storage does not support operation links
+	flushInterval   time.Duration
+	spanCacheTTL    time.Duration
+	maxCachedSpans  int
+	logger          *zap.Logger
+	metrics         aggregatorMetrics
+	timeNow         func() time.Time
+
+	mux            sync.Mutex
+	spans          *cache.LRU // spanInfo by span key
+	pending        map[spanKey]*list.Element
+	order          *list.List // of *pendingParent, oldest first
+	links          map[linkKey]uint64
+	operationLinks map[string]*dependencystore.OperationLinks // by tenant
+	lastFlush      time.Time
+	stopCh         chan struct{}
+	stopped        sync.WaitGroup
+	closeOnce      sync.Once
+}
+
+// NewAggregator creates a dependencies Aggregator and starts its background flush loop.
+func NewAggregator(writer dependencystore.Writer, opts Options) *Aggregator {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.SpanCacheTTL <= 0 {
+		opts.SpanCacheTTL = DefaultSpanCacheTTL
+	}
+	if opts.MaxCachedSpans <= 0 {
+		opts.MaxCachedSpans = DefaultMaxCachedSpans
+	}
+	if opts.MetricsFactory == nil {
+		opts.MetricsFactory = metrics.NullFactory
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	aggMetrics := aggregatorMetrics{}
+	metrics.Init(&aggMetrics, opts.MetricsFactory, nil)
+	tenantWriter, _ := writer.(dependencystore.TenantWriter)
+	operationWriter, _ := writer.(dependencystore.OperationWriter)
+	a := &Aggregator{
+		writer:          writer,
+		tenantWriter:    tenantWriter,
+		operationWriter: operationWriter,
+		flushInterval:   opts.FlushInterval,
+		spanCacheTTL:    opts.SpanCacheTTL,
+		maxCachedSpans:  opts.MaxCachedSpans,
+		logger:          opts.Logger,
+		metrics:         aggMetrics,
+		timeNow:         time.Now,
+		pending:         make(map[spanKey]*list.Element),
+		order:           list.New(),
+		links:           make(map[linkKey]uint64),
+		operationLinks:  make(map[string]*dependencystore.OperationLinks),
+		stopCh:          make(chan struct{}),
+	}
+	a.spans = cache.NewLRUWithOptions(opts.MaxCachedSpans, &cache.Options{
+		TTL:     opts.SpanCacheTTL,
+		TimeNow: func() time.Time { return a.timeNow() },
+	})
+	a.lastFlush = a.timeNow()
+	a.stopped.Add(1)
+	go a.flushLoop()
+	return a
+}
+
+// ProcessSpan links the span with its parent and with its children received before it, within its tenant.
+func (a *Aggregator) ProcessSpan(span *model.Span, tenant string) {
+	if span.Process == nil {
+		return
+	}
+	info := spanInfo{service: span.Process.ServiceName, operation: span.OperationName}
+	key := spanKey{tenant: tenant, traceID: span.TraceID, spanID: span.SpanID}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.metrics.SpansProcessed.Inc(1)
+	a.spans.Put(key.String(), info)
+	if parentID := span.ParentSpanID(); parentID != 0 {
+		parentKey := spanKey{tenant: tenant, traceID: span.TraceID, spanID: parentID}
+		child := childSpan{spanInfo: info, duration: span.Duration, isError: spanstore.IsErrorSpan(span)}
+		if parent, ok := a.spans.Get(parentKey.String()).(spanInfo); ok {
+			a.addLink(tenant, parent, child)
+		} else {
+			a.waitForParent(parentKey, child)
+		}
+	}
+	if elem, ok := a.pending[key]; ok {
+		p := a.removePending(elem)
+		for _, child := range p.children {
+			a.addLink(tenant, info, child)
+		}
+		a.metrics.LateParents.Inc(int64(len(p.children)))
+	}
+}
+
+// Close stops the flush loop and writes the dependency links not yet written.
+func (a *Aggregator) Close() error {
+	a.closeOnce.Do(func() {
+		close(a.stopCh)
+		a.stopped.Wait()
+		a.flush()
+	})
+	return nil
+}
+
+// addLink counts a call between two services, and between their operations if the storage supports it.
+// Calls within the same service are not dependencies. Must be called with the lock held.
+func (a *Aggregator) addLink(tenant string, parent spanInfo, child childSpan) {
+	if parent.service == child.service {
+		return
+	}
+	a.links[linkKey{tenant: tenant, parent: parent.service, child: child.service}]++
+	if a.operationWriter == nil {
+		return
+	}
+	link := model.OperationDependencyLink{
+		Parent:          parent.service,
+		ParentOperation: parent.operation,
+		Child:           child.service,
+		ChildOperation:  child.operation,
+	}
+	link.Observe(child.duration, child.isError)
+	a.tenantOperationLinks(tenant).Add(link)
+}
+
+// tenantOperationLinks must be called with the lock held.
+func (a *Aggregator) tenantOperationLinks(tenant string) *dependencystore.OperationLinks {
+	links, ok := a.operationLinks[tenant]
+	if !ok {
+		links = dependencystore.NewOperationLinks()
+		a.operationLinks[tenant] = links
+	}
+	return links
+}
+
+// waitForParent remembers the child until its parent is received, evicting the oldest
+// waited for parent when the limit is reached. Must be called with the lock held.
+func (a *Aggregator) waitForParent(parentKey spanKey, child childSpan) {
+	if elem, ok := a.pending[parentKey]; ok {
+		p := elem.Value.(*pendingParent)
+		p.children = append(p.children, child)
+		return
+	}
+	if len(a.pending) >= a.maxCachedSpans {
+		p := a.removePending(a.order.Front())
+		a.metrics.Orphans.Inc(int64(len(p.children)))
+	}
+	a.pending[parentKey] = a.order.PushBack(&pendingParent{
+		key:      parentKey,
+		arrival:  a.timeNow(),
+		children: []childSpan{child},
+	})
+}
+
+// removePending must be called with the lock held.
+func (a *Aggregator) removePending(elem *list.Element) *pendingParent {
+	p := a.order.Remove(elem).(*pendingParent)
+	delete(a.pending, p.key)
+	return p
+}
+
+func (a *Aggregator) flushLoop() {
+	defer a.stopped.Done()
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stopCh:
+			return
+		}
+	}
+}
+
+// flush gives up on the parents waited for longer than the span cache TTL, and writes
+// the dependency links counted since the previous successful flush.
+func (a *Aggregator) flush() {
+	now := a.timeNow()
+	a.mux.Lock()
+	expired := now.Add(-a.spanCacheTTL)
+	for elem := a.order.Front(); elem != nil && elem.Value.(*pendingParent).arrival.Before(expired); elem = a.order.Front() {
+		p := a.removePending(elem)
+		a.metrics.Orphans.Inc(int64(len(p.children)))
+	}
+	links := linksByTenant(a.links)
+	a.links = make(map[linkKey]uint64)
+	operationLinks := make(map[string][]model.OperationDependencyLink, len(a.operationLinks))
+	for tenant, tenantLinks := range a.operationLinks {
+		operationLinks[tenant] = tenantLinks.Links()
+	}
+	a.operationLinks = make(map[string]*dependencystore.OperationLinks)
+	a.metrics.PendingParents.Update(int64(len(a.pending)))
+	a.metrics.CachedSpans.Update(int64(a.spans.Size()))
+	a.mux.Unlock()
+
+	flushed := true
+	for tenant, tenantLinks := range links {
+		if err := a.writeLinks(tenant, now, tenantLinks); err != nil {
+			flushed = false
+			a.metrics.FlushErrors.Inc(1)
+			a.logger.Error("Failed to write dependency links", zap.String("tenant", tenant), zap.Error(err))
+			// keep the counts to write them with the next flush
+			a.mux.Lock()
+			for k, count := range tenantLinks {
+				a.links[k] += count
+			}
+			a.mux.Unlock()
+		} else {
+			a.metrics.FlushedLinks.Inc(int64(len(tenantLinks)))
+		}
+	}
+	for tenant, tenantLinks := range operationLinks {
+		if err := a.writeOperationLinks(tenant, now, tenantLinks); err != nil {
+			flushed = false
+			a.metrics.FlushErrors.Inc(1)
+			a.logger.Error("Failed to write operation dependency links", zap.String("tenant", tenant), zap.Error(err))
+			a.mux.Lock()
+			for _, link := range tenantLinks {
+				a.tenantOperationLinks(tenant).Add(link)
+			}
+			a.mux.Unlock()
+		} else {
+			a.metrics.FlushedOperationLinks.Inc(int64(len(tenantLinks)))
+		}
+	}
+	if flushed {
+		a.lastFlush = now
+	}
+	a.metrics.FlushLag.Update(now.Sub(a.lastFlush).Milliseconds())
+}
+
+// linksByTenant splits the call counts per tenant.
+func linksByTenant(links map[linkKey]uint64) map[string]map[linkKey]uint64 {
+	byTenant := make(map[string]map[linkKey]uint64)
+	for k, count := range links {
+		tenantLinks, ok := byTenant[k.tenant]
+		if !ok {
+			tenantLinks = make(map[linkKey]uint64)
+			byTenant[k.tenant] = tenantLinks
+		}
+		tenantLinks[k] = count
+	}
+	return byTenant
+}
+
+func (a *Aggregator) writeLinks(tenant string, ts time.Time, links map[linkKey]uint64) error {
+	if len(links) == 0 {
+		return nil
+	}
+	dependencies := make([]model.DependencyLink, 0, len(links))
+	for k, count := range links {
+		dependencies = append(dependencies, model.DependencyLink{
+			Parent:    k.parent,
+			Child:     k.child,
+			CallCount: count,
+		})
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Parent != dependencies[j].Parent {
+			return dependencies[i].Parent < dependencies[j].Parent
+		}
+		return dependencies[i].Child < dependencies[j].Child
+	})
+	start := a.timeNow()
+	defer func() { a.metrics.FlushLatency.Record(a.timeNow().Sub(start)) }()
+	if a.tenantWriter != nil {
+		return a.tenantWriter.WriteTenantDependencies(tenancy.WithTenant(context.Background(), tenant), ts, dependencies)
+	}
+	if tenant != "" {
+		return ErrTenantsNotIsolated
+	}
+	return a.writer.WriteDependencies(ts, dependencies)
+}
+
+func (a *Aggregator) writeOperationLinks(tenant string, ts time.Time, links []model.OperationDependencyLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	start := a.timeNow()
+	defer func() { a.metrics.FlushLatency.Record(a.timeNow().Sub(start)) }()
+	return a.operationWriter.WriteOperationDependencies(tenancy.WithTenant(context.Background(), tenant), ts, links)
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+type fakeDependencyWriter struct {
+	mux    sync.Mutex
+	writes [][]model.DependencyLink
+	err    error
+}
+
+func (w *fakeDependencyWriter) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.writes = append(w.writes, dependencies)
+	return nil
+}
+
+func (w *fakeDependencyWriter) getWrites() [][]model.DependencyLink {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.writes
+}
+
+type fakeOperationDependencyWriter struct {
+	fakeDependencyWriter
+	operationWrites [][]model.OperationDependencyLink
+	operationErr    error
+}
+
+func (w *fakeOperationDependencyWriter) WriteOperationDependencies(ctx context.Context, ts time.Time, dependencies []model.OperationDependencyLink) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.operationErr != nil {
+		return w.operationErr
+	}
+	w.operationWrites = append(w.operationWrites, dependencies)
+	return nil
+}
+
+func (w *fakeOperationDependencyWriter) getOperationWrites() [][]model.OperationDependencyLink {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.operationWrites
+}
+
+// fakeTenantDependencyWriter records the tenant of every write.
+type fakeTenantDependencyWriter struct {
+	fakeOperationDependencyWriter
+	tenants []string
+}
+
+func (w *fakeTenantDependencyWriter) WriteTenantDependencies(ctx context.Context, ts time.Time, dependencies []model.DependencyLink) error {
+	w.mux.Lock()
+	w.tenants = append(w.tenants, tenancy.GetTenant(ctx))
+	w.mux.Unlock()
+	return w.WriteDependencies(ts, dependencies)
+}
+
+func (w *fakeTenantDependencyWriter) WriteOperationDependencies(ctx context.Context, ts time.Time, dependencies []model.OperationDependencyLink) error {
+	w.mux.Lock()
+	w.tenants = append(w.tenants, tenancy.GetTenant(ctx))
+	w.mux.Unlock()
+	return w.fakeOperationDependencyWriter.WriteOperationDependencies(ctx, ts, dependencies)
+}
+
+type fakeClock struct {
+	mux sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) timeNow() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newSpan(service string, spanID, parentID uint64) *model.Span {
+	span := &model.Span{
+		TraceID: model.NewTraceID(0, 1),
+		SpanID:  model.NewSpanID(spanID),
+		Process: model.NewProcess(service, nil),
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+func newOperationSpan(service, operation string, spanID, parentID uint64, duration time.Duration, isError bool) *model.Span {
+	span := newSpan(service, spanID, parentID)
+	span.OperationName = operation
+	span.Duration = duration
+	if isError {
+		span.Tags = model.KeyValues{model.Bool("error", true)}
+	}
+	return span
+}
+
+func newTestAggregator(writer dependencystore.Writer, maxCachedSpans int) (*Aggregator, *fakeClock, *metricstest.Factory) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	metricsFactory := metricstest.NewFactory(0)
+	a := NewAggregator(writer, Options{
+		FlushInterval:  time.Hour,
+		SpanCacheTTL:   time.Minute,
+		MaxCachedSpans: maxCachedSpans,
+		MetricsFactory: metricsFactory,
+	})
+	a.timeNow = clock.timeNow
+	return a, clock, metricsFactory
+}
+
+func TestAggregatorLinksServices(t *testing.T) {
+	writer := &fakeDependencyWriter{}
+	a, _, metricsFactory := newTestAggregator(writer, 100)
+
+	a.ProcessSpan(newSpan("frontend", 1, 0), "")
+	a.ProcessSpan(newSpan("frontend", 2, 1), "") // same service, not a dependency
+	a.ProcessSpan(newSpan("backend", 3, 2), "")
+	a.ProcessSpan(newSpan("backend", 4, 2), "")
+	a.ProcessSpan(newSpan("mysql", 6, 5), "") // parent received after the child
+	a.ProcessSpan(newSpan("backend", 5, 4), "")
+	a.ProcessSpan(&model.Span{SpanID: model.NewSpanID(7)}, "")
+	require.NoError(t, a.Close())
+
+	require.Len(t, writer.getWrites(), 1)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "backend", Child: "mysql", CallCount: 1},
+		{Parent: "frontend", Child: "backend", CallCount: 2},
+	}, writer.getWrites()[0])
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_processed", Value: 6},
+		metricstest.ExpectedMetric{Name: "late_parents", Value: 1},
+		metricstest.ExpectedMetric{Name: "flushed_links", Value: 2},
+	)
+}
+
+func TestAggregatorSeparatesTenants(t *testing.T) {
+	writer := &fakeTenantDependencyWriter{}
+	a, _, metricsFactory := newTestAggregator(writer, 100)
+
+	a.ProcessSpan(newOperationSpan("frontend", "GET /", 1, 0, 0, false), "acme")
+	a.ProcessSpan(newOperationSpan("backend", "get", 2, 1, time.Millisecond, false), "acme")
+	// the parent belongs to another tenant
+	a.ProcessSpan(newOperationSpan("backend", "get", 3, 1, time.Millisecond, false), "")
+	require.NoError(t, a.Close())
+
+	assert.Equal(t, [][]model.DependencyLink{{{Parent: "frontend", Child: "backend", CallCount: 1}}}, writer.getWrites())
+	require.Len(t, writer.getOperationWrites(), 1)
+	assert.Equal(t, []string{"acme", "acme"}, writer.tenants)
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "pending_parents", Value: 1})
+}
+
+func TestAggregatorRejectsTenantsWithoutTenantWriter(t *testing.T) {
+	writer := &fakeDependencyWriter{}
+	a, _, metricsFactory := newTestAggregator(writer, 100)
+	defer a.Close()
+
+	a.ProcessSpan(newSpan("frontend", 1, 0), "acme")
+	a.ProcessSpan(newSpan("backend", 2, 1), "acme")
+	a.flush()
+
+	assert.Empty(t, writer.getWrites())
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "flush_errors", Value: 1})
+}
+
+func TestAggregatorFlushesIncrementalCounts(t *testing.T) {
+	writer := &fakeDependencyWriter{}
+	a, _, _ := newTestAggregator(writer, 100)
+	defer a.Close()
+
+	a.ProcessSpan(newSpan("frontend", 1, 0), "")
+	a.ProcessSpan(newSpan("backend", 2, 1), "")
+	a.flush()
+	a.flush() // nothing new to write
+	a.ProcessSpan(newSpan("backend", 3, 1), "")
+	a.flush()
+
+	assert.Equal(t, [][]model.DependencyLink{
+		{{Parent: "frontend", Child: "backend", CallCount: 1}},
+		{{Parent: "frontend", Child: "backend", CallCount: 1}},
+	}, writer.getWrites())
+}
+
+func TestAggregatorOrphans(t *testing.T) {
+	writer := &fakeDependencyWriter{}
+	a, clock, metricsFactory := newTestAggregator(writer, 2)
+	defer a.Close()
+
+	// the parent arrives after the span cache TTL
+	a.ProcessSpan(newSpan("backend", 2, 1), "")
+	clock.advance(2 * time.Minute)
+	a.flush()
+	a.ProcessSpan(newSpan("frontend", 1, 0), "")
+
+	// the oldest waited for parent is evicted when the limit is reached
+	a.ProcessSpan(newSpan("backend", 11, 10), "")
+	a.ProcessSpan(newSpan("backend", 21, 20), "")
+	a.ProcessSpan(newSpan("backend", 31, 30), "")
+	a.ProcessSpan(newSpan("frontend", 10, 0), "")
+	a.ProcessSpan(newSpan("frontend", 20, 0), "")
+	a.flush()
+
+	require.Len(t, writer.getWrites(), 1)
+	assert.Equal(t, []model.DependencyLink{{Parent: "frontend", Child: "backend", CallCount: 1}}, writer.getWrites()[0])
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "orphans", Value: 2},
+		metricstest.ExpectedMetric{Name: "late_parents", Value: 1},
+	)
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "pending_parents", Value: 1})
+}
+
+func TestAggregatorRetriesFailedWrites(t *testing.T) {
+	writer := &fakeDependencyWriter{err: errors.New("write failed")}
+	a, clock, metricsFactory := newTestAggregator(writer, 100)
+	a.lastFlush = clock.timeNow()
+
+	a.ProcessSpan(newSpan("frontend", 1, 0), "")
+	a.ProcessSpan(newSpan("backend", 2, 1), "")
+	clock.advance(time.Minute)
+	a.flush()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "flush_errors", Value: 1})
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "flush_lag", Value: 60000})
+
+	writer.mux.Lock()
+	writer.err = nil
+	writer.mux.Unlock()
+	a.ProcessSpan(newSpan("backend", 3, 1), "")
+	require.NoError(t, a.Close())
+
+	assert.Equal(t, [][]model.DependencyLink{
+		{{Parent: "frontend", Child: "backend", CallCount: 2}},
+	}, writer.getWrites())
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "flush_lag", Value: 0})
+}
+
+func TestAggregatorLinksOperations(t *testing.T) {
+	writer := &fakeOperationDependencyWriter{}
+	a, _, metricsFactory := newTestAggregator(writer, 100)
+
+	a.ProcessSpan(newOperationSpan("frontend", "GET /dispatch", 1, 0, 10*time.Millisecond, false), "")
+	a.ProcessSpan(newOperationSpan("backend", "FindNearest", 2, 1, 2*time.Millisecond, false), "")
+	a.ProcessSpan(newOperationSpan("backend", "FindNearest", 3, 1, 4*time.Millisecond, true), "")
+	a.ProcessSpan(newOperationSpan("mysql", "SQL SELECT", 5, 4, 3*time.Millisecond, false), "") // parent received after the child
+	a.ProcessSpan(newOperationSpan("backend", "GetDriver", 4, 1, 6*time.Millisecond, false), "")
+	require.NoError(t, a.Close())
+
+	require.Len(t, writer.getWrites(), 1)
+	assert.Equal(t, []model.DependencyLink{
+		{Parent: "backend", Child: "mysql", CallCount: 1},
+		{Parent: "frontend", Child: "backend", CallCount: 3},
+	}, writer.getWrites()[0])
+	require.Len(t, writer.getOperationWrites(), 1)
+	assert.Equal(t, []model.OperationDependencyLink{
+		{
+			Parent: "backend", ParentOperation: "GetDriver", Child: "mysql", ChildOperation: "SQL SELECT",
+			CallCount: 1,
+			Latency:   model.LatencySummary{Min: 3 * time.Millisecond, Max: 3 * time.Millisecond, Total: 3 * time.Millisecond},
+		},
+		{
+			Parent: "frontend", ParentOperation: "GET /dispatch", Child: "backend", ChildOperation: "FindNearest",
+			CallCount:  2,
+			ErrorCount: 1,
+			Latency:    model.LatencySummary{Min: 2 * time.Millisecond, Max: 4 * time.Millisecond, Total: 6 * time.Millisecond},
+		},
+		{
+			Parent: "frontend", ParentOperation: "GET /dispatch", Child: "backend", ChildOperation: "GetDriver",
+			CallCount: 1,
+			Latency:   model.LatencySummary{Min: 6 * time.Millisecond, Max: 6 * time.Millisecond, Total: 6 * time.Millisecond},
+		},
+	}, writer.getOperationWrites()[0])
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "flushed_links", Value: 2},
+		metricstest.ExpectedMetric{Name: "flushed_operation_links", Value: 3},
+	)
+}
+
+func TestAggregatorRetriesFailedOperationWrites(t *testing.T) {
+	writer := &fakeOperationDependencyWriter{operationErr: errors.New("write failed")}
+	a, clock, metricsFactory := newTestAggregator(writer, 100)
+	a.lastFlush = clock.timeNow()
+
+	a.ProcessSpan(newOperationSpan("frontend", "GET", 1, 0, time.Millisecond, false), "")
+	a.ProcessSpan(newOperationSpan("backend", "Find", 2, 1, time.Millisecond, false), "")
+	clock.advance(time.Minute)
+	a.flush()
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "flush_errors", Value: 1})
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "flush_lag", Value: 60000})
+
+	writer.mux.Lock()
+	writer.operationErr = nil
+	writer.mux.Unlock()
+	a.ProcessSpan(newOperationSpan("backend", "Find", 3, 1, 3*time.Millisecond, false), "")
+	require.NoError(t, a.Close())
+
+	// the service links were written by both flushes, the operation links only by the last one
+	assert.Len(t, writer.getWrites(), 2)
+	assert.Equal(t, [][]model.OperationDependencyLink{
+		{{
+			Parent: "frontend", ParentOperation: "GET", Child: "backend", ChildOperation: "Find",
+			CallCount: 2,
+			Latency:   model.LatencySummary{Min: time.Millisecond, Max: 3 * time.Millisecond, Total: 4 * time.Millisecond},
+		}},
+	}, writer.getOperationWrites())
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "flush_lag", Value: 0})
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	dependenciesEnabled       = "collector.dependencies.enabled"
+	dependenciesFlushInterval = "collector.dependencies.flush-interval"
+	dependenciesSpanCacheTTL  = "collector.dependencies.span-cache-ttl"
+	dependenciesMaxSpans      = "collector.dependencies.max-cached-spans"
+)
+
+// Flags holds the command line configuration of the streaming dependencies aggregator
+type Flags struct {
+	// Enabled turns on the computation of the service dependencies from the received spans
+	Enabled bool
+	// FlushInterval is how often the dependency links are written to storage
+	FlushInterval time.Duration
+	// SpanCacheTTL is how long spans are remembered to link them with parents or children received later
+	SpanCacheTTL time.Duration
+	// MaxCachedSpans is the maximum number of spans remembered
+	MaxCachedSpans int
+}
+
+// AddFlags adds flags for the streaming dependencies aggregator
+func AddFlags(flags *flag.FlagSet) {
+	flags.Bool(dependenciesEnabled, false, "(experimental) Computes the service dependencies from the received spans and writes them to the dependencies storage, replacing the Spark job. The dependencies between operations are written as well when the storage supports them")
+	flags.Duration(dependenciesFlushInterval, DefaultFlushInterval, "How often the dependency links are written to the dependencies storage")
+	flags.Duration(dependenciesSpanCacheTTL, DefaultSpanCacheTTL, "How long spans are remembered to be linked with their parent or children received later")
+	flags.Int(dependenciesMaxSpans, DefaultMaxCachedSpans, "The maximum number of spans remembered to be linked with their parent or children received later")
+}
+
+// InitFromViper initializes Flags with properties from viper
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.Enabled = v.GetBool(dependenciesEnabled)
+	f.FlushInterval = v.GetDuration(dependenciesFlushInterval)
+	f.SpanCacheTTL = v.GetDuration(dependenciesSpanCacheTTL)
+	f.MaxCachedSpans = v.GetInt(dependenciesMaxSpans)
+	return f
+}
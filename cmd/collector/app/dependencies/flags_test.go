@@ -0,0 +1,41 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.dependencies.enabled=true",
+		"--collector.dependencies.flush-interval=5m",
+		"--collector.dependencies.span-cache-ttl=1m",
+		"--collector.dependencies.max-cached-spans=1000",
+	})
+	f := new(Flags).InitFromViper(v)
+	assert.Equal(t, &Flags{
+		Enabled:        true,
+		FlushInterval:  5 * time.Minute,
+		SpanCacheTTL:   time.Minute,
+		MaxCachedSpans: 1000,
+	}, f)
+}
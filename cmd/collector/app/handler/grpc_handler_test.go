@@ -25,9 +25,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 )
 
@@ -35,12 +39,16 @@ type mockSpanProcessor struct {
 	expectedError error
 	mux           sync.Mutex
 	spans         []*model.Span
+	tenants       []string
+	transports    []processor.InboundTransport
 }
 
 func (p *mockSpanProcessor) ProcessSpans(spans []*model.Span, opts processor.SpansOptions) ([]bool, error) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	p.spans = append(p.spans, spans...)
+	p.tenants = append(p.tenants, opts.Tenant)
+	p.transports = append(p.transports, opts.InboundTransport)
 	oks := make([]bool, len(spans))
 	return oks, p.expectedError
 }
@@ -51,10 +59,24 @@ func (p *mockSpanProcessor) getSpans() []*model.Span {
 	return p.spans
 }
 
+func (p *mockSpanProcessor) getTenants() []string {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.tenants
+}
+
+func (p *mockSpanProcessor) getTransports() []processor.InboundTransport {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.transports
+}
+
 func (p *mockSpanProcessor) reset() {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	p.spans = nil
+	p.tenants = nil
+	p.transports = nil
 }
 
 func (p *mockSpanProcessor) Close() error {
@@ -82,7 +104,7 @@ func newClient(t *testing.T, addr net.Addr) (api_v2.CollectorServiceClient, *grp
 func TestPostSpans(t *testing.T) {
 	processor := &mockSpanProcessor{}
 	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
-		handler := NewGRPCHandler(zap.NewNop(), processor)
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
 		api_v2.RegisterCollectorServiceServer(s, handler)
 	})
 	defer server.Stop()
@@ -110,10 +132,44 @@ func TestPostSpans(t *testing.T) {
 	}
 }
 
+func TestPostSpansWithTenant(t *testing.T) {
+	processor := &mockSpanProcessor{}
+	tenancyMgr := tenancy.NewManager(&tenancy.Options{Enabled: true, Required: true, Tenants: []string{"acme"}})
+	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		api_v2.RegisterCollectorServiceServer(s, NewGRPCHandler(zap.NewNop(), processor, tenancyMgr))
+	})
+	defer server.Stop()
+	client, conn := newClient(t, addr)
+	defer conn.Close()
+
+	request := &api_v2.PostSpansRequest{
+		Batch: model.Batch{Spans: []*model.Span{{OperationName: "test-op"}}},
+	}
+	tests := []struct {
+		name    string
+		md      metadata.MD
+		errCode codes.Code
+		tenants []string
+	}{
+		{name: "valid tenant", md: metadata.Pairs("x-tenant", "acme"), errCode: codes.OK, tenants: []string{"acme"}},
+		{name: "missing tenant", md: metadata.MD{}, errCode: codes.Unauthenticated},
+		{name: "unknown tenant", md: metadata.Pairs("x-tenant", "globex"), errCode: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), test.md)
+			_, err := client.PostSpans(ctx, request)
+			assert.Equal(t, test.errCode, status.Code(err))
+			assert.Equal(t, test.tenants, processor.getTenants())
+			processor.reset()
+		})
+	}
+}
+
 func TestGRPCCompressionEnabled(t *testing.T) {
 	processor := &mockSpanProcessor{}
 	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
-		handler := NewGRPCHandler(zap.NewNop(), processor)
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
 		api_v2.RegisterCollectorServiceServer(s, handler)
 	})
 	defer server.Stop()
@@ -131,7 +187,7 @@ func TestPostSpansWithError(t *testing.T) {
 	expectedError := errors.New("test-error")
 	processor := &mockSpanProcessor{expectedError: expectedError}
 	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
-		handler := NewGRPCHandler(zap.NewNop(), processor)
+		handler := NewGRPCHandler(zap.NewNop(), processor, &tenancy.Manager{})
 		api_v2.RegisterCollectorServiceServer(s, handler)
 	})
 	defer server.Stop()
@@ -26,6 +26,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	tJaeger "github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 )
 
@@ -44,14 +45,17 @@ var (
 // APIHandler handles all HTTP calls to the collector
 type APIHandler struct {
 	jaegerBatchesHandler JaegerBatchesHandler
+	tenancyMgr           *tenancy.Manager
 }
 
 // NewAPIHandler returns a new APIHandler
 func NewAPIHandler(
 	jaegerBatchesHandler JaegerBatchesHandler,
+	tenancyMgr *tenancy.Manager,
 ) *APIHandler {
 	return &APIHandler{
 		jaegerBatchesHandler: jaegerBatchesHandler,
+		tenancyMgr:           tenancyMgr,
 	}
 }
 
@@ -62,6 +66,12 @@ func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
 
 // SaveSpan submits the span provided in the request body to the JaegerBatchesHandler
 func (aH *APIHandler) SaveSpan(w http.ResponseWriter, r *http.Request) {
+	tenant, err := aH.tenancyMgr.TenantFromHTTP(r)
+	if err != nil {
+		http.Error(w, err.Error(), tenancy.HTTPStatusCode(err))
+		return
+	}
+
 	bodyBytes, err := ioutil.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
@@ -88,9 +98,13 @@ func (aH *APIHandler) SaveSpan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	batches := []*tJaeger.Batch{batch}
-	opts := SubmitBatchOptions{InboundTransport: processor.HTTPTransport}
+	opts := SubmitBatchOptions{InboundTransport: processor.HTTPTransport, Tenant: tenant}
 	if _, err = aH.jaegerBatchesHandler.SubmitBatches(batches, opts); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if err == processor.ErrBusy {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, fmt.Sprintf("Cannot submit Jaeger batch: %v", err), status)
 		return
 	}
 
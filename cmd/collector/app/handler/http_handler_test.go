@@ -29,9 +29,12 @@ import (
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	jaegerClient "github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/transport"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 )
 
@@ -41,12 +44,14 @@ type mockJaegerHandler struct {
 	err     error
 	mux     sync.Mutex
 	batches []*jaeger.Batch
+	tenant  string
 }
 
-func (p *mockJaegerHandler) SubmitBatches(batches []*jaeger.Batch, _ SubmitBatchOptions) ([]*jaeger.BatchSubmitResponse, error) {
+func (p *mockJaegerHandler) SubmitBatches(batches []*jaeger.Batch, opts SubmitBatchOptions) ([]*jaeger.BatchSubmitResponse, error) {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 	p.batches = append(p.batches, batches...)
+	p.tenant = opts.Tenant
 	return nil, p.err
 }
 
@@ -58,7 +63,7 @@ func (p *mockJaegerHandler) getBatches() []*jaeger.Batch {
 
 func initializeTestServer(err error) (*httptest.Server, *APIHandler) {
 	r := mux.NewRouter()
-	handler := NewAPIHandler(&mockJaegerHandler{err: err})
+	handler := NewAPIHandler(&mockJaegerHandler{err: err}, &tenancy.Manager{})
 	handler.RegisterRoutes(r)
 	return httptest.NewServer(r), handler
 }
@@ -94,6 +99,50 @@ func TestThriftFormat(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, statusCode)
 	assert.EqualValues(t, "Cannot submit Jaeger batch: Bad times ahead\n", resBodyStr)
+
+	handler.jaegerBatchesHandler.(*mockJaegerHandler).err = processor.ErrBusy
+	statusCode, resBodyStr, err = postBytes("application/x-thrift", server.URL+`/api/traces`, someBytes)
+	assert.NoError(t, err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, statusCode)
+	assert.EqualValues(t, "Cannot submit Jaeger batch: server busy\n", resBodyStr)
+}
+
+func TestThriftFormatWithTenant(t *testing.T) {
+	batch := jaeger.Batch{Process: &jaeger.Process{ServiceName: "serviceName"}}
+	someBytes, err := thrift.NewTSerializer().Write(context.Background(), &batch)
+	require.NoError(t, err)
+
+	jaegerHandler := &mockJaegerHandler{}
+	tenancyMgr := tenancy.NewManager(&tenancy.Options{Enabled: true, Required: true, Tenants: []string{"acme"}})
+	r := mux.NewRouter()
+	NewAPIHandler(jaegerHandler, tenancyMgr).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		tenant   string
+		expected int
+	}{
+		{name: "valid tenant", tenant: "acme", expected: http.StatusAccepted},
+		{name: "missing tenant", expected: http.StatusUnauthorized},
+		{name: "unknown tenant", tenant: "globex", expected: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/api/traces", bytes.NewReader(someBytes))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-thrift")
+			if test.tenant != "" {
+				req.Header.Set("x-tenant", test.tenant)
+			}
+			res, err := httpClient.Do(req)
+			require.NoError(t, err)
+			res.Body.Close()
+			assert.Equal(t, test.expected, res.StatusCode)
+		})
+	}
+	assert.Equal(t, "acme", jaegerHandler.tenant)
 }
 
 func TestViaClient(t *testing.T) {
@@ -159,7 +208,7 @@ func TestMalformedFormat(t *testing.T) {
 }
 
 func TestCannotReadBodyFromRequest(t *testing.T) {
-	handler := NewAPIHandler(&mockJaegerHandler{})
+	handler := NewAPIHandler(&mockJaegerHandler{}, &tenancy.Manager{})
 	req, err := http.NewRequest(http.MethodPost, "whatever", &errReader{})
 	assert.NoError(t, err)
 	rw := dummyResponseWriter{}
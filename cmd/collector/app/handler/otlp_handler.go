@@ -0,0 +1,166 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+const (
+	// OTLPTracesPath is the standard OTLP/HTTP path for exporting traces
+	OTLPTracesPath = "/v1/traces"
+
+	otlpProtobufContentType = "application/x-protobuf"
+	otlpJSONContentType     = "application/json"
+)
+
+var (
+	otlpProtobufUnmarshaler = otlp.NewProtobufTracesUnmarshaler()
+	otlpJSONUnmarshaler     = otlp.NewJSONTracesUnmarshaler()
+)
+
+// OTLPHandler receives OTLP traces over gRPC and HTTP and passes them to the span processor.
+type OTLPHandler struct {
+	logger        *zap.Logger
+	spanProcessor processor.SpanProcessor
+	tenancyMgr    *tenancy.Manager
+}
+
+// NewOTLPHandler creates a handler for OTLP traces.
+func NewOTLPHandler(logger *zap.Logger, spanProcessor processor.SpanProcessor, tenancyMgr *tenancy.Manager) *OTLPHandler {
+	return &OTLPHandler{
+		logger:        logger,
+		spanProcessor: spanProcessor,
+		tenancyMgr:    tenancyMgr,
+	}
+}
+
+// Export implements otlpgrpc.TracesServer.
+func (h *OTLPHandler) Export(ctx context.Context, req otlpgrpc.TracesRequest) (otlpgrpc.TracesResponse, error) {
+	tenant, err := tenancy.GetValidTenant(ctx, h.tenancyMgr)
+	if err != nil {
+		return otlpgrpc.NewTracesResponse(), err
+	}
+	if err := h.processTraces(req.Traces(), processor.OTLPGRPCTransport, tenant); err != nil {
+		if err == processor.ErrBusy {
+			return otlpgrpc.NewTracesResponse(), status.Errorf(codes.ResourceExhausted, err.Error())
+		}
+		h.logger.Error("cannot process OTLP spans", zap.Error(err))
+		return otlpgrpc.NewTracesResponse(), err
+	}
+	return otlpgrpc.NewTracesResponse(), nil
+}
+
+// RegisterRoutes registers the OTLP/HTTP routes on the given router.
+func (h *OTLPHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc(OTLPTracesPath, h.SaveSpans).Methods(http.MethodPost)
+}
+
+// SaveSpans accepts an OTLP ExportTraceServiceRequest encoded as protobuf or JSON.
+func (h *OTLPHandler) SaveSpans(w http.ResponseWriter, r *http.Request) {
+	tenant, err := h.tenancyMgr.TenantFromHTTP(r)
+	if err != nil {
+		http.Error(w, err.Error(), tenancy.HTTPStatusCode(err))
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Cannot parse content type: %v", err), http.StatusBadRequest)
+		return
+	}
+	var unmarshaler pdata.TracesUnmarshaler
+	switch contentType {
+	case otlpProtobufContentType:
+		unmarshaler = otlpProtobufUnmarshaler
+	case otlpJSONContentType:
+		unmarshaler = otlpJSONUnmarshaler
+	default:
+		http.Error(w, fmt.Sprintf("Unsupported content type: %v", html.EscapeString(contentType)), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	bodyBytes, err := readOTLPBody(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusBadRequest)
+		return
+	}
+	td, err := unmarshaler.UnmarshalTraces(bodyBytes)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(UnableToReadBodyErrFormat, err), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.processTraces(td, processor.OTLPHTTPTransport, tenant); err != nil {
+		if err == processor.ErrBusy {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Cannot submit OTLP spans: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// ExportTraceServiceResponse has no fields, so its protobuf encoding is empty
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if contentType == otlpJSONContentType {
+		w.Write([]byte("{}"))
+	}
+}
+
+func (h *OTLPHandler) processTraces(td pdata.Traces, transport processor.InboundTransport, tenant string) error {
+	for _, batch := range otlpToJaegerBatches(td) {
+		_, err := h.spanProcessor.ProcessSpans(batch.Spans, processor.SpansOptions{
+			InboundTransport: transport,
+			SpanFormat:       processor.OTLPSpanFormat,
+			Tenant:           tenant,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readOTLPBody(r *http.Request) ([]byte, error) {
+	defer r.Body.Close()
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	return ioutil.ReadAll(body)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func TestOTLPExport(t *testing.T) {
+	spanProcessor := &mockSpanProcessor{}
+	server, addr := initializeGRPCTestServer(t, func(s *grpc.Server) {
+		otlpgrpc.RegisterTracesServer(s, NewOTLPHandler(zap.NewNop(), spanProcessor, &tenancy.Manager{}))
+	})
+	defer server.Stop()
+	conn, err := grpc.Dial(addr.String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	client := otlpgrpc.NewTracesClient(conn)
+
+	req := otlpgrpc.NewTracesRequest()
+	req.SetTraces(makeOTLPTraces())
+	_, err = client.Export(context.Background(), req)
+	require.NoError(t, err)
+	spans := spanProcessor.getSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /api", spans[0].OperationName)
+	assert.Equal(t, "frontend", spans[0].Process.ServiceName)
+	assert.Equal(t, []processor.InboundTransport{processor.OTLPGRPCTransport}, spanProcessor.getTransports())
+}
+
+func TestOTLPExportErrors(t *testing.T) {
+	tests := []struct {
+		processorErr error
+		expectedCode codes.Code
+	}{
+		{processorErr: processor.ErrBusy, expectedCode: codes.ResourceExhausted},
+		{processorErr: errors.New("doh"), expectedCode: codes.Unknown},
+	}
+	for _, test := range tests {
+		handler := NewOTLPHandler(zap.NewNop(), &mockSpanProcessor{expectedError: test.processorErr}, &tenancy.Manager{})
+		req := otlpgrpc.NewTracesRequest()
+		req.SetTraces(makeOTLPTraces())
+		_, err := handler.Export(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, test.expectedCode, status.Code(err))
+	}
+}
+
+func TestOTLPHTTPSaveSpans(t *testing.T) {
+	pbBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(makeOTLPTraces())
+	require.NoError(t, err)
+	jsonBytes, err := otlp.NewJSONTracesMarshaler().MarshalTraces(makeOTLPTraces())
+	require.NoError(t, err)
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err = gz.Write(pbBytes)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentType     string
+		contentEncoding string
+		processorErr    error
+		expectedStatus  int
+		expectedSpans   int
+	}{
+		{name: "protobuf", body: pbBytes, contentType: "application/x-protobuf", expectedStatus: http.StatusOK, expectedSpans: 1},
+		{name: "json", body: jsonBytes, contentType: "application/json", expectedStatus: http.StatusOK, expectedSpans: 1},
+		{name: "gzip", body: gzipped.Bytes(), contentType: "application/x-protobuf", contentEncoding: "gzip", expectedStatus: http.StatusOK, expectedSpans: 1},
+		{name: "bad gzip", body: pbBytes, contentType: "application/x-protobuf", contentEncoding: "gzip", expectedStatus: http.StatusBadRequest},
+		{name: "bad body", body: []byte("{"), contentType: "application/json", expectedStatus: http.StatusBadRequest},
+		{name: "bad content type", body: pbBytes, contentType: "text/plain", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "unparsable content type", body: pbBytes, contentType: "", expectedStatus: http.StatusBadRequest},
+		{name: "busy", body: pbBytes, contentType: "application/x-protobuf", processorErr: processor.ErrBusy, expectedStatus: http.StatusServiceUnavailable, expectedSpans: 1},
+		{name: "processor error", body: pbBytes, contentType: "application/x-protobuf", processorErr: errors.New("doh"), expectedStatus: http.StatusInternalServerError, expectedSpans: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processor := &mockSpanProcessor{expectedError: test.processorErr}
+			router := mux.NewRouter()
+			NewOTLPHandler(zap.NewNop(), processor, &tenancy.Manager{}).RegisterRoutes(router)
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+OTLPTracesPath, bytes.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", test.contentType)
+			if test.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Len(t, processor.getSpans(), test.expectedSpans)
+			for _, transport := range processor.getTransports() {
+				assert.Equal(t, "otlp-http", string(transport))
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/binary"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// The tag names below mirror the ones used by the query service when translating
+// Jaeger spans back into OTLP (cmd/query/app/apiv3), so that the round trip is lossless.
+const (
+	tagStatusCode    = "status.code"
+	tagStatusMsg     = "status.message"
+	tagSpanKind      = "span.kind"
+	tagError         = "error"
+	tagMessage       = "message"
+	tagW3CTraceState = "w3c.tracestate"
+
+	noServiceName = "OTLPResourceNoServiceName"
+)
+
+// otlpToJaegerBatches converts OTLP traces into Jaeger batches, one per ResourceSpans.
+func otlpToJaegerBatches(td pdata.Traces) []*model.Batch {
+	resourceSpans := td.ResourceSpans()
+	batches := make([]*model.Batch, 0, resourceSpans.Len())
+	for i := 0; i < resourceSpans.Len(); i++ {
+		if batch := resourceSpansToJaegerBatch(resourceSpans.At(i)); batch != nil {
+			batches = append(batches, batch)
+		}
+	}
+	return batches
+}
+
+func resourceSpansToJaegerBatch(rs pdata.ResourceSpans) *model.Batch {
+	libSpans := rs.InstrumentationLibrarySpans()
+	process := resourceToJaegerProcess(rs.Resource())
+	batch := &model.Batch{Process: process}
+	for i := 0; i < libSpans.Len(); i++ {
+		ils := libSpans.At(i)
+		spans := ils.Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spanToJaegerSpan(spans.At(j), ils.InstrumentationLibrary())
+			span.Process = process
+			batch.Spans = append(batch.Spans, span)
+		}
+	}
+	if len(batch.Spans) == 0 {
+		return nil
+	}
+	return batch
+}
+
+func resourceToJaegerProcess(resource pdata.Resource) *model.Process {
+	process := &model.Process{ServiceName: noServiceName}
+	attrs := resource.Attributes()
+	if attrs.Len() == 0 {
+		return process
+	}
+	tags := make([]model.KeyValue, 0, attrs.Len())
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		if k == semconv.AttributeServiceName {
+			process.ServiceName = v.AsString()
+			return true
+		}
+		tags = append(tags, attributeToJaegerTag(k, v))
+		return true
+	})
+	if len(tags) > 0 {
+		process.Tags = tags
+	}
+	return process
+}
+
+func spanToJaegerSpan(span pdata.Span, library pdata.InstrumentationLibrary) *model.Span {
+	traceID := traceIDToJaeger(span.TraceID())
+	startTime := span.StartTimestamp().AsTime()
+	return &model.Span{
+		TraceID:       traceID,
+		SpanID:        spanIDToJaeger(span.SpanID()),
+		OperationName: span.Name(),
+		References:    spanRefsToJaeger(traceID, span.ParentSpanID(), span.Links()),
+		StartTime:     startTime,
+		Duration:      span.EndTimestamp().AsTime().Sub(startTime),
+		Tags:          spanTagsToJaeger(span, library),
+		Logs:          spanEventsToJaegerLogs(span.Events()),
+	}
+}
+
+func spanTagsToJaeger(span pdata.Span, library pdata.InstrumentationLibrary) []model.KeyValue {
+	attrs := span.Attributes()
+	tags := make([]model.KeyValue, 0, attrs.Len()+6)
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		tags = append(tags, attributeToJaegerTag(k, v))
+		return true
+	})
+	if name := library.Name(); name != "" {
+		tags = append(tags, model.String(semconv.InstrumentationLibraryName, name))
+		if version := library.Version(); version != "" {
+			tags = append(tags, model.String(semconv.InstrumentationLibraryVersion, version))
+		}
+	}
+	if kind := spanKindToJaeger(span.Kind()); kind != "" {
+		tags = append(tags, model.String(tagSpanKind, kind))
+	}
+	if traceState := string(span.TraceState()); traceState != "" {
+		tags = append(tags, model.String(tagW3CTraceState, traceState))
+	}
+	status := span.Status()
+	if status.Code() != pdata.StatusCodeUnset {
+		tags = append(tags, model.Int64(tagStatusCode, int64(status.Code())))
+		if status.Message() != "" {
+			tags = append(tags, model.String(tagStatusMsg, status.Message()))
+		}
+		if status.Code() == pdata.StatusCodeError {
+			tags = append(tags, model.Bool(tagError, true))
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+func spanRefsToJaeger(traceID model.TraceID, parentID pdata.SpanID, links pdata.SpanLinkSlice) []model.SpanRef {
+	var refs []model.SpanRef
+	if !parentID.IsEmpty() {
+		refs = append(refs, model.NewChildOfRef(traceID, spanIDToJaeger(parentID)))
+	}
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		refs = append(refs, model.NewFollowsFromRef(traceIDToJaeger(link.TraceID()), spanIDToJaeger(link.SpanID())))
+	}
+	return refs
+}
+
+func spanEventsToJaegerLogs(events pdata.SpanEventSlice) []model.Log {
+	if events.Len() == 0 {
+		return nil
+	}
+	logs := make([]model.Log, 0, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		attrs := event.Attributes()
+		fields := make([]model.KeyValue, 0, attrs.Len()+1)
+		if event.Name() != "" {
+			fields = append(fields, model.String(tagMessage, event.Name()))
+		}
+		attrs.Range(func(k string, v pdata.AttributeValue) bool {
+			fields = append(fields, attributeToJaegerTag(k, v))
+			return true
+		})
+		logs = append(logs, model.Log{
+			Timestamp: event.Timestamp().AsTime(),
+			Fields:    fields,
+		})
+	}
+	return logs
+}
+
+func attributeToJaegerTag(key string, value pdata.AttributeValue) model.KeyValue {
+	switch value.Type() {
+	case pdata.AttributeValueTypeString:
+		return model.String(key, value.StringVal())
+	case pdata.AttributeValueTypeInt:
+		return model.Int64(key, value.IntVal())
+	case pdata.AttributeValueTypeDouble:
+		return model.Float64(key, value.DoubleVal())
+	case pdata.AttributeValueTypeBool:
+		return model.Bool(key, value.BoolVal())
+	case pdata.AttributeValueTypeBytes:
+		return model.Binary(key, value.BytesVal())
+	default:
+		// maps and arrays have no Jaeger equivalent, keep their JSON-like representation
+		return model.String(key, value.AsString())
+	}
+}
+
+func spanKindToJaeger(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindClient:
+		return "client"
+	case pdata.SpanKindServer:
+		return "server"
+	case pdata.SpanKindProducer:
+		return "producer"
+	case pdata.SpanKindConsumer:
+		return "consumer"
+	case pdata.SpanKindInternal:
+		return "internal"
+	}
+	return ""
+}
+
+func traceIDToJaeger(traceID pdata.TraceID) model.TraceID {
+	b := traceID.Bytes()
+	return model.TraceID{
+		High: binary.BigEndian.Uint64(b[:8]),
+		Low:  binary.BigEndian.Uint64(b[8:]),
+	}
+}
+
+func spanIDToJaeger(spanID pdata.SpanID) model.SpanID {
+	b := spanID.Bytes()
+	return model.SpanID(binary.BigEndian.Uint64(b[:]))
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	testStartTime = time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	testTraceID   = pdata.NewTraceID([16]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2})
+	testSpanID    = pdata.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 3})
+	testParentID  = pdata.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 4})
+)
+
+func makeOTLPTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "frontend")
+	rs.Resource().Attributes().InsertString("host.name", "host-1")
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	ils.InstrumentationLibrary().SetName("lib")
+	ils.InstrumentationLibrary().SetVersion("1.0")
+	span := ils.Spans().AppendEmpty()
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+	span.SetParentSpanID(testParentID)
+	span.SetName("GET /api")
+	span.SetKind(pdata.SpanKindServer)
+	span.SetStartTimestamp(pdata.NewTimestampFromTime(testStartTime))
+	span.SetEndTimestamp(pdata.NewTimestampFromTime(testStartTime.Add(time.Second)))
+	span.Attributes().InsertInt("http.status_code", 500)
+	span.Attributes().InsertBool("cache.hit", false)
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Status().SetMessage("boom")
+	event := span.Events().AppendEmpty()
+	event.SetName("exception")
+	event.SetTimestamp(pdata.NewTimestampFromTime(testStartTime))
+	event.Attributes().InsertDouble("retry.delay", 1.5)
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(testTraceID)
+	link.SetSpanID(pdata.NewSpanID([8]byte{0, 0, 0, 0, 0, 0, 0, 5}))
+	return td
+}
+
+func TestOTLPToJaegerBatches(t *testing.T) {
+	batches := otlpToJaegerBatches(makeOTLPTraces())
+	require.Len(t, batches, 1)
+	process := &model.Process{
+		ServiceName: "frontend",
+		Tags:        []model.KeyValue{model.String("host.name", "host-1")},
+	}
+	assert.Equal(t, process, batches[0].Process)
+	require.Len(t, batches[0].Spans, 1)
+
+	traceID := model.NewTraceID(1, 2)
+	expected := &model.Span{
+		TraceID:       traceID,
+		SpanID:        model.NewSpanID(3),
+		OperationName: "GET /api",
+		References: []model.SpanRef{
+			model.NewChildOfRef(traceID, model.NewSpanID(4)),
+			model.NewFollowsFromRef(traceID, model.NewSpanID(5)),
+		},
+		StartTime: testStartTime,
+		Duration:  time.Second,
+		Tags: []model.KeyValue{
+			model.Int64("http.status_code", 500),
+			model.Bool("cache.hit", false),
+			model.String("otel.library.name", "lib"),
+			model.String("otel.library.version", "1.0"),
+			model.String(tagSpanKind, "server"),
+			model.Int64(tagStatusCode, int64(pdata.StatusCodeError)),
+			model.String(tagStatusMsg, "boom"),
+			model.Bool(tagError, true),
+		},
+		Logs: []model.Log{
+			{
+				Timestamp: testStartTime,
+				Fields: []model.KeyValue{
+					model.String(tagMessage, "exception"),
+					model.Float64("retry.delay", 1.5),
+				},
+			},
+		},
+		Process: process,
+	}
+	assert.Equal(t, expected, batches[0].Spans[0])
+}
+
+func TestOTLPToJaegerBatchesNoServiceName(t *testing.T) {
+	td := pdata.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+
+	batches := otlpToJaegerBatches(td)
+	require.Len(t, batches, 1)
+	assert.Equal(t, noServiceName, batches[0].Process.ServiceName)
+	assert.Nil(t, batches[0].Spans[0].References)
+	assert.Nil(t, batches[0].Spans[0].Tags)
+}
+
+func TestOTLPToJaegerBatchesSkipsEmptyResources(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty()
+	assert.Empty(t, otlpToJaegerBatches(td))
+}
+
+func TestAttributeToJaegerTag(t *testing.T) {
+	arr := pdata.NewAttributeValueArray()
+	arr.ArrayVal().AppendEmpty().SetStringVal("a")
+	tests := []struct {
+		value    pdata.AttributeValue
+		expected model.KeyValue
+	}{
+		{value: pdata.NewAttributeValueString("s"), expected: model.String("k", "s")},
+		{value: pdata.NewAttributeValueInt(1), expected: model.Int64("k", 1)},
+		{value: pdata.NewAttributeValueDouble(1.5), expected: model.Float64("k", 1.5)},
+		{value: pdata.NewAttributeValueBool(true), expected: model.Bool("k", true)},
+		{value: pdata.NewAttributeValueBytes([]byte{1}), expected: model.Binary("k", []byte{1})},
+		{value: arr, expected: model.String("k", `["a"]`)},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, attributeToJaegerTag("k", test.value))
+	}
+}
+
+func TestSpanKindToJaeger(t *testing.T) {
+	assert.Equal(t, "client", spanKindToJaeger(pdata.SpanKindClient))
+	assert.Equal(t, "server", spanKindToJaeger(pdata.SpanKindServer))
+	assert.Equal(t, "producer", spanKindToJaeger(pdata.SpanKindProducer))
+	assert.Equal(t, "consumer", spanKindToJaeger(pdata.SpanKindConsumer))
+	assert.Equal(t, "internal", spanKindToJaeger(pdata.SpanKindInternal))
+	assert.Equal(t, "", spanKindToJaeger(pdata.SpanKindUnspecified))
+}
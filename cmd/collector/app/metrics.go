@@ -69,10 +69,15 @@ type SpanProcessorMetrics struct {
 	// QueueLength measures the current number of elements in the internal span queue
 	QueueLength metrics.Gauge
 	// SavedOkBySvc contains span and trace counts by service
-	SavedOkBySvc  metricsBySvc  // spans actually saved
-	SavedErrBySvc metricsBySvc  // spans failed to save
-	serviceNames  metrics.Gauge // total number of unique service name metrics reported by this collector
-	spanCounts    SpanCountsByFormat
+	SavedOkBySvc  metricsBySvc // spans actually saved
+	SavedErrBySvc metricsBySvc // spans failed to save
+	// RateLimitedBySvc, TenantRateLimitedBySvc and FairShareExceededBySvc contain the counts of
+	// spans rejected by the service rate limits, the tenant rate limits and the fair queuing
+	RateLimitedBySvc       metricsBySvc
+	TenantRateLimitedBySvc metricsBySvc
+	FairShareExceededBySvc metricsBySvc
+	serviceNames           metrics.Gauge // total number of unique service name metrics reported by this collector
+	spanCounts             SpanCountsByFormat
 }
 
 type countsBySvc struct {
@@ -118,23 +123,27 @@ func NewSpanProcessorMetrics(serviceMetrics metrics.Factory, hostMetrics metrics
 		processor.ZipkinSpanFormat:  newCountsByTransport(serviceMetrics, processor.ZipkinSpanFormat),
 		processor.JaegerSpanFormat:  newCountsByTransport(serviceMetrics, processor.JaegerSpanFormat),
 		processor.ProtoSpanFormat:   newCountsByTransport(serviceMetrics, processor.ProtoSpanFormat),
+		processor.OTLPSpanFormat:    newCountsByTransport(serviceMetrics, processor.OTLPSpanFormat),
 		processor.UnknownSpanFormat: newCountsByTransport(serviceMetrics, processor.UnknownSpanFormat),
 	}
 	for _, otherFormatType := range otherFormatTypes {
 		spanCounts[otherFormatType] = newCountsByTransport(serviceMetrics, otherFormatType)
 	}
 	m := &SpanProcessorMetrics{
-		SaveLatency:    hostMetrics.Timer(metrics.TimerOptions{Name: "save-latency", Tags: nil}),
-		InQueueLatency: hostMetrics.Timer(metrics.TimerOptions{Name: "in-queue-latency", Tags: nil}),
-		SpansDropped:   hostMetrics.Counter(metrics.Options{Name: "spans.dropped", Tags: nil}),
-		BatchSize:      hostMetrics.Gauge(metrics.Options{Name: "batch-size", Tags: nil}),
-		QueueCapacity:  hostMetrics.Gauge(metrics.Options{Name: "queue-capacity", Tags: nil}),
-		QueueLength:    hostMetrics.Gauge(metrics.Options{Name: "queue-length", Tags: nil}),
-		SpansBytes:     hostMetrics.Gauge(metrics.Options{Name: "spans.bytes", Tags: nil}),
-		SavedOkBySvc:   newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "ok"}}), "saved-by-svc"),
-		SavedErrBySvc:  newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "err"}}), "saved-by-svc"),
-		spanCounts:     spanCounts,
-		serviceNames:   hostMetrics.Gauge(metrics.Options{Name: "spans.serviceNames", Tags: nil}),
+		SaveLatency:            hostMetrics.Timer(metrics.TimerOptions{Name: "save-latency", Tags: nil}),
+		InQueueLatency:         hostMetrics.Timer(metrics.TimerOptions{Name: "in-queue-latency", Tags: nil}),
+		SpansDropped:           hostMetrics.Counter(metrics.Options{Name: "spans.dropped", Tags: nil}),
+		BatchSize:              hostMetrics.Gauge(metrics.Options{Name: "batch-size", Tags: nil}),
+		QueueCapacity:          hostMetrics.Gauge(metrics.Options{Name: "queue-capacity", Tags: nil}),
+		QueueLength:            hostMetrics.Gauge(metrics.Options{Name: "queue-length", Tags: nil}),
+		SpansBytes:             hostMetrics.Gauge(metrics.Options{Name: "spans.bytes", Tags: nil}),
+		SavedOkBySvc:           newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "ok"}}), "saved-by-svc"),
+		SavedErrBySvc:          newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"result": "err"}}), "saved-by-svc"),
+		RateLimitedBySvc:       newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"reason": "service-rate-limit"}}), "throttled-by-svc"),
+		TenantRateLimitedBySvc: newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"reason": "tenant-rate-limit"}}), "throttled-by-svc"),
+		FairShareExceededBySvc: newMetricsBySvc(serviceMetrics.Namespace(metrics.NSOptions{Name: "", Tags: map[string]string{"reason": "fair-share"}}), "throttled-by-svc"),
+		spanCounts:             spanCounts,
+		serviceNames:           hostMetrics.Gauge(metrics.Options{Name: "spans.serviceNames", Tags: nil}),
 	}
 
 	return m
@@ -194,9 +203,11 @@ func newSpanCountsBySvc(factory metrics.Factory, category string, maxServiceName
 func newCountsByTransport(factory metrics.Factory, format processor.SpanFormat) SpanCountsByTransport {
 	factory = factory.Namespace(metrics.NSOptions{Tags: map[string]string{"format": string(format)}})
 	return SpanCountsByTransport{
-		processor.HTTPTransport:    newCounts(factory, processor.HTTPTransport),
-		processor.GRPCTransport:    newCounts(factory, processor.GRPCTransport),
-		processor.UnknownTransport: newCounts(factory, processor.UnknownTransport),
+		processor.HTTPTransport:     newCounts(factory, processor.HTTPTransport),
+		processor.GRPCTransport:     newCounts(factory, processor.GRPCTransport),
+		processor.OTLPGRPCTransport: newCounts(factory, processor.OTLPGRPCTransport),
+		processor.OTLPHTTPTransport: newCounts(factory, processor.OTLPHTTPTransport),
+		processor.UnknownTransport:  newCounts(factory, processor.UnknownTransport),
 	}
 }
 
@@ -52,12 +52,14 @@ func TestProcessorMetrics(t *testing.T) {
 	grpcChannelFormat.ReceivedBySvc.ReportServiceNameForSpan(&mSpan)
 	mSpan.ReplaceParentID(1234)
 	grpcChannelFormat.ReceivedBySvc.ReportServiceNameForSpan(&mSpan)
+	spm.GetCountsForFormat(processor.OTLPSpanFormat, processor.OTLPHTTPTransport).ReceivedBySvc.ReportServiceNameForSpan(&mSpan)
 	counters, gauges := baseMetrics.Backend.Snapshot()
 
 	assert.EqualValues(t, 1, counters["service.spans.received|debug=false|format=jaeger|svc=fry|transport=grpc"])
 	assert.EqualValues(t, 2, counters["service.spans.received|debug=true|format=jaeger|svc=fry|transport=grpc"])
 	assert.EqualValues(t, 1, counters["service.traces.received|debug=false|format=jaeger|sampler_type=unknown|svc=fry|transport=grpc"])
 	assert.EqualValues(t, 1, counters["service.traces.received|debug=true|format=jaeger|sampler_type=unknown|svc=fry|transport=grpc"])
+	assert.EqualValues(t, 1, counters["service.spans.received|debug=true|format=otlp|svc=fry|transport=otlp-http"])
 	assert.Empty(t, gauges)
 }
 
@@ -19,8 +19,8 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 )
 
-// ProcessSpan processes a Domain Model Span
-type ProcessSpan func(span *model.Span)
+// ProcessSpan processes a Domain Model Span owned by the given tenant
+type ProcessSpan func(span *model.Span, tenant string)
 
 // ProcessSpans processes a batch of Domain Model Spans
 type ProcessSpans func(spans []*model.Span)
@@ -30,9 +30,9 @@ type FilterSpan func(span *model.Span) bool
 
 // ChainedProcessSpan chains spanProcessors as a single ProcessSpan call
 func ChainedProcessSpan(spanProcessors ...ProcessSpan) ProcessSpan {
-	return func(span *model.Span) {
+	return func(span *model.Span, tenant string) {
 		for _, processor := range spanProcessors {
-			processor(span)
+			processor(span, tenant)
 		}
 	}
 }
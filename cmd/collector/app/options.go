@@ -22,6 +22,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
 )
 
 const (
@@ -44,6 +45,8 @@ type options struct {
 	numWorkers         int
 	blockingSubmit     bool
 	queueSize          int
+	queue              queue.Queue
+	throttling         ThrottlingOptions
 	dynQueueSizeWarmup uint
 	dynQueueSizeMemory uint
 	reportBusy         bool
@@ -127,6 +130,20 @@ func (options) QueueSize(queueSize int) Option {
 	}
 }
 
+// Queue creates an Option that replaces the in-memory queue of the processor, e.g. with a persistent queue
+func (options) Queue(queue queue.Queue) Option {
+	return func(b *options) {
+		b.queue = queue
+	}
+}
+
+// Throttling creates an Option that initializes the per-service and per-tenant admission of spans
+func (options) Throttling(throttling ThrottlingOptions) Option {
+	return func(b *options) {
+		b.throttling = throttling
+	}
+}
+
 // DynQueueSizeWarmup creates an Option that initializes the dynamic queue size
 func (options) DynQueueSizeWarmup(dynQueueSizeWarmup uint) Option {
 	return func(b *options) {
@@ -183,7 +200,7 @@ func (o options) apply(opts ...Option) options {
 		ret.sanitizer = func(span *model.Span) *model.Span { return span }
 	}
 	if ret.preSave == nil {
-		ret.preSave = func(span *model.Span) {}
+		ret.preSave = func(span *model.Span, tenant string) {}
 	}
 	if ret.spanFilter == nil {
 		ret.spanFilter = func(span *model.Span) bool { return true }
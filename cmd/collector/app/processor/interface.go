@@ -28,6 +28,8 @@ var ErrBusy = errors.New("server busy")
 type SpansOptions struct {
 	SpanFormat       SpanFormat
 	InboundTransport InboundTransport
+	// Tenant is the tenant owning the spans, empty for the default tenant
+	Tenant string
 }
 
 // SpanProcessor handles model spans
@@ -45,6 +47,10 @@ const (
 	GRPCTransport InboundTransport = "grpc"
 	// HTTPTransport indicates spans received over HTTP.
 	HTTPTransport InboundTransport = "http"
+	// OTLPGRPCTransport indicates spans received by the OTLP receiver over gRPC.
+	OTLPGRPCTransport InboundTransport = "otlp-grpc"
+	// OTLPHTTPTransport indicates spans received by the OTLP receiver over HTTP.
+	OTLPHTTPTransport InboundTransport = "otlp-http"
 	// UnknownTransport is the fallback/catch-all category.
 	UnknownTransport InboundTransport = "unknown"
 )
@@ -59,6 +65,8 @@ const (
 	ZipkinSpanFormat SpanFormat = "zipkin"
 	// ProtoSpanFormat is for Jaeger protobuf Spans.
 	ProtoSpanFormat SpanFormat = "proto"
+	// OTLPSpanFormat is for OpenTelemetry OTLP Spans.
+	OTLPSpanFormat SpanFormat = "otlp"
 	// UnknownSpanFormat is the fallback/catch-all category.
 	UnknownSpanFormat SpanFormat = "unknown"
 )
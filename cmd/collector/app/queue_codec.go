@@ -0,0 +1,61 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// encodeQueueItem serializes a queue item for the persistent queue as the varint encoded time
+// the span was queued at, the length-prefixed tenant, and the protobuf encoded span.
+func encodeQueueItem(item interface{}) ([]byte, error) {
+	qi := item.(*queueItem)
+	span, err := qi.span.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 2*binary.MaxVarintLen64, 2*binary.MaxVarintLen64+len(qi.tenant)+len(span))
+	n := binary.PutVarint(buf, qi.queuedTime.UnixNano())
+	n += binary.PutUvarint(buf[n:], uint64(len(qi.tenant)))
+	buf = append(buf[:n], qi.tenant...)
+	return append(buf, span...), nil
+}
+
+func decodeQueueItem(data []byte) (interface{}, error) {
+	queuedTime, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid queued time")
+	}
+	data = data[n:]
+	tenantLen, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < tenantLen {
+		return nil, fmt.Errorf("invalid tenant")
+	}
+	data = data[n:]
+	tenant := string(data[:tenantLen])
+	span := &model.Span{}
+	if err := span.Unmarshal(data[tenantLen:]); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the span: %w", err)
+	}
+	return &queueItem{
+		queuedTime: time.Unix(0, queuedTime),
+		span:       span,
+		tenant:     tenant,
+	}, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestQueueItemCodec(t *testing.T) {
+	item := &queueItem{
+		queuedTime: time.Unix(0, 1234567890),
+		span: &model.Span{
+			TraceID:       model.NewTraceID(1, 2),
+			SpanID:        model.NewSpanID(3),
+			OperationName: "op",
+			Process:       &model.Process{ServiceName: "svc"},
+			Tags:          []model.KeyValue{model.String("k", "v")},
+		},
+		tenant: "acme",
+	}
+	data, err := encodeQueueItem(item)
+	require.NoError(t, err)
+	decoded, err := decodeQueueItem(data)
+	require.NoError(t, err)
+	assert.Equal(t, item, decoded)
+}
+
+func TestQueueItemCodecErrors(t *testing.T) {
+	_, err := decodeQueueItem(nil)
+	assert.EqualError(t, err, "invalid queued time")
+	_, err = decodeQueueItem([]byte{2, 10, 'a'})
+	assert.EqualError(t, err, "invalid tenant")
+	_, err = decodeQueueItem([]byte{2, 1, 'a', 0xff})
+	assert.Contains(t, err.Error(), "failed to unmarshal the span")
+}
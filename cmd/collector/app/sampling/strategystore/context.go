@@ -0,0 +1,33 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategystore
+
+import "context"
+
+type processTagsKeyType string
+
+const processTagsKey = processTagsKeyType("process-tags")
+
+// WithProcessTags creates a context carrying the process tags reported by the client
+// which requests a sampling strategy.
+func WithProcessTags(ctx context.Context, tags map[string]string) context.Context {
+	return context.WithValue(ctx, processTagsKey, tags)
+}
+
+// GetProcessTags returns the process tags carried by the context, or nil.
+func GetProcessTags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(processTagsKey).(map[string]string)
+	return tags
+}
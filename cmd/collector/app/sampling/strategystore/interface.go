@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -27,6 +28,53 @@ type StrategyStore interface {
 	GetSamplingStrategy(ctx context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error)
 }
 
+// SpanObserver is implemented by strategy stores which select the sampling strategy of a service
+// based on what the service reports along with its spans, such as its process tags.
+type SpanObserver interface {
+	// ObserveSpan is called for every span received by the collector.
+	ObserveSpan(span *model.Span)
+}
+
+// DryRunner is implemented by strategy stores which can explain how they select sampling strategies.
+type DryRunner interface {
+	// DryRun returns the sampling strategy for the given service and process tags
+	// along with the rules of the store that selected it.
+	DryRun(ctx context.Context, query DryRunQuery) (*DryRunResult, error)
+}
+
+// DryRunQuery describes the client for which a strategy selection is dry-run.
+type DryRunQuery struct {
+	ServiceName string
+	// Operation is optional, when set the result explains the strategy of this operation too.
+	Operation string
+	// ProcessTags are optional, when empty the store uses the process tags it observed for the service.
+	ProcessTags map[string]string
+}
+
+// DryRunResult explains which rules of a strategy store selected a sampling strategy.
+type DryRunResult struct {
+	ServiceName string `json:"service"`
+	Operation   string `json:"operation,omitempty"`
+	// ProcessTags are the values of the process tags that the rules were matched against.
+	ProcessTags map[string][]string `json:"processTags,omitempty"`
+	// ServiceRule is the rule that selected the strategy of the service.
+	ServiceRule *MatchedRule `json:"serviceRule"`
+	// OperationRule is the rule that selected the strategy of the operation, if any.
+	OperationRule *MatchedRule                       `json:"operationRule,omitempty"`
+	Strategy      *sampling.SamplingStrategyResponse `json:"strategy"`
+	// Warnings describe the effects of the rules that the strategy does not make obvious.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// MatchedRule identifies a rule of a strategy store.
+type MatchedRule struct {
+	// Path locates the rule in the store configuration, e.g. "rules[2]" or "default_strategy".
+	Path string `json:"path"`
+	Name string `json:"name,omitempty"`
+	// Source is the file and line of the rule, if known.
+	Source string `json:"source,omitempty"`
+}
+
 // Aggregator defines an interface used to aggregate operation throughput.
 type Aggregator interface {
 	// Close() from io.Closer stops the aggregator from aggregating throughput.
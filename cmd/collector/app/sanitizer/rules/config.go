@@ -0,0 +1,225 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	actionDrop            = "drop"
+	actionRename          = "rename"
+	actionHash            = "hash"
+	actionTruncate        = "truncate"
+	actionRedact          = "redact"
+	actionRenameOperation = "rename-operation"
+	actionAddTag          = "add-tag"
+
+	targetTags    = "tags"
+	targetLogs    = "logs"
+	targetProcess = "process"
+
+	defaultRedactReplacement = "[REDACTED]"
+)
+
+// rulesConfig is the representation of the rules file, in YAML or JSON, e.g.
+//
+//	rules:
+//	  - name: hash-user-email
+//	    action: hash
+//	    tag: user.email
+//	  - name: redact-credit-cards
+//	    action: redact
+//	    tag: "*"
+//	    pattern: '\b(?:\d[ -]?){13,16}\b'
+//	  - name: user-id-operations
+//	    service: frontend
+//	    action: rename-operation
+//	    pattern: '^/users/\d+$'
+//	    replacement: /users/{id}
+//	  - name: region
+//	    action: add-tag
+//	    tag: region
+//	    value: eu-west-1
+type rulesConfig struct {
+	Rules []*ruleConfig `yaml:"rules"`
+}
+
+type ruleConfig struct {
+	// Name identifies the rule in the errors and metrics.
+	Name string `yaml:"name"`
+	// Service and Operation are globs restricting the spans the rule applies to.
+	Service   string `yaml:"service"`
+	Operation string `yaml:"operation"`
+	Action    string `yaml:"action"`
+	// Tag is a glob of the keys the rule applies to, or the key of the added tag.
+	Tag string `yaml:"tag"`
+	// Targets lists where the keys are looked up: tags, logs and process. Defaults to tags and logs.
+	Targets     []string `yaml:"targets"`
+	Rename      string   `yaml:"rename"`
+	Length      int      `yaml:"length"`
+	Pattern     string   `yaml:"pattern"`
+	Replacement *string  `yaml:"replacement"`
+	Value       string   `yaml:"value"`
+}
+
+// LoadRules reads the ordered list of rules from a YAML or JSON file.
+func LoadRules(path string) ([]*Rule, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sanitizer rules file: %w", err)
+	}
+	return parseRules(b)
+}
+
+func parseRules(b []byte) ([]*Rule, error) {
+	var cfg rulesConfig
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sanitizer rules: %w", err)
+	}
+	names := make(map[string]bool, len(cfg.Rules))
+	rules := make([]*Rule, 0, len(cfg.Rules))
+	for i, rc := range cfg.Rules {
+		if rc == nil {
+			return nil, fmt.Errorf("invalid sanitizer rule #%d: empty rule", i)
+		}
+		r, err := rc.compile()
+		if err != nil {
+			return nil, fmt.Errorf("invalid sanitizer rule #%d %q: %w", i, rc.Name, err)
+		}
+		if names[r.name] {
+			return nil, fmt.Errorf("invalid sanitizer rule #%d: duplicate name %q", i, r.name)
+		}
+		names[r.name] = true
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func (rc *ruleConfig) compile() (*Rule, error) {
+	if rc.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	r := &Rule{
+		name:      rc.Name,
+		action:    rc.Action,
+		service:   compileGlob(rc.Service),
+		operation: compileGlob(rc.Operation),
+		rename:    rc.Rename,
+		length:    rc.Length,
+		value:     rc.Value,
+	}
+	targets, err := parseTargets(rc.Action, rc.Targets)
+	if err != nil {
+		return nil, err
+	}
+	r.tags, r.logs, r.process = targets[targetTags], targets[targetLogs], targets[targetProcess]
+
+	switch rc.Action {
+	case actionDrop, actionHash:
+	case actionRename:
+		if rc.Rename == "" {
+			return nil, fmt.Errorf("rename is required by the %s action", rc.Action)
+		}
+	case actionTruncate:
+		if rc.Length <= 0 {
+			return nil, fmt.Errorf("length must be positive for the %s action", rc.Action)
+		}
+	case actionRedact:
+		r.replacement = defaultRedactReplacement
+		if rc.Replacement != nil {
+			r.replacement = *rc.Replacement
+		}
+	case actionRenameOperation:
+		if rc.Replacement == nil {
+			return nil, fmt.Errorf("replacement is required by the %s action", rc.Action)
+		}
+		r.replacement = *rc.Replacement
+	case actionAddTag:
+		if rc.Tag == "" || isGlob(rc.Tag) {
+			return nil, fmt.Errorf("tag must be a single key for the %s action", rc.Action)
+		}
+		r.key = rc.Tag
+		return r, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q, must be one of %s", rc.Action, strings.Join([]string{
+			actionDrop, actionRename, actionHash, actionTruncate, actionRedact, actionRenameOperation, actionAddTag,
+		}, ", "))
+	}
+
+	if rc.Action == actionRedact || rc.Action == actionRenameOperation {
+		if rc.Pattern == "" {
+			return nil, fmt.Errorf("pattern is required by the %s action", rc.Action)
+		}
+		if r.pattern, err = regexp.Compile(rc.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if rc.Action != actionRenameOperation {
+		if rc.Tag == "" {
+			return nil, fmt.Errorf("tag is required by the %s action", rc.Action)
+		}
+		r.keys = compileGlob(rc.Tag)
+	}
+	return r, nil
+}
+
+func parseTargets(action string, targets []string) (map[string]bool, error) {
+	if len(targets) == 0 {
+		if action == actionAddTag {
+			return map[string]bool{targetTags: true}, nil
+		}
+		return map[string]bool{targetTags: true, targetLogs: true}, nil
+	}
+	parsed := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		switch t {
+		case targetTags, targetProcess:
+		case targetLogs:
+			if action == actionAddTag {
+				return nil, fmt.Errorf("the %s action does not support the %s target", action, t)
+			}
+		default:
+			return nil, fmt.Errorf("unknown target %q, must be one of %s, %s, %s", t, targetTags, targetLogs, targetProcess)
+		}
+		parsed[t] = true
+	}
+	return parsed, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?")
+}
+
+// compileGlob turns a glob, where '*' matches any sequence of characters and '?' a single
+// character, into an anchored regular expression. An empty glob matches everything and yields nil.
+func compileGlob(pattern string) *regexp.Regexp {
+	if pattern == "" || pattern == "*" {
+		return nil
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
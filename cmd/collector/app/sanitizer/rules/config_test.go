@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules([]byte(`
+rules:
+  - name: hash-email
+    service: front*
+    action: hash
+    tag: user.email
+    targets: [tags, process]
+  - name: redact
+    action: redact
+    tag: "*"
+    pattern: '\d{4}'
+  - name: users
+    action: rename-operation
+    pattern: '^/users/\d+$'
+    replacement: /users/{id}
+  - name: region
+    action: add-tag
+    tag: region
+    value: eu
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+
+	assert.Equal(t, "hash-email", rules[0].Name())
+	assert.True(t, rules[0].tags)
+	assert.False(t, rules[0].logs)
+	assert.True(t, rules[0].process)
+	assert.True(t, rules[0].service.MatchString("frontend"))
+	assert.Nil(t, rules[0].operation)
+
+	assert.Nil(t, rules[1].keys)
+	assert.True(t, rules[1].tags)
+	assert.True(t, rules[1].logs)
+	assert.Equal(t, defaultRedactReplacement, rules[1].replacement)
+
+	assert.Equal(t, "/users/{id}", rules[2].replacement)
+
+	assert.Equal(t, "region", rules[3].key)
+	assert.True(t, rules[3].tags)
+	assert.False(t, rules[3].logs)
+}
+
+func TestParseRulesJSON(t *testing.T) {
+	rules, err := parseRules([]byte(`{"rules": [{"name": "drop", "action": "drop", "tag": "secret"}]}`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, actionDrop, rules[0].action)
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := []struct {
+		rules string
+		err   string
+	}{
+		{`rules: [{name: a, action: drop, tag: x, unknown: 1}]`, "failed to unmarshal sanitizer rules"},
+		{`rules: [null]`, "invalid sanitizer rule #0: empty rule"},
+		{`rules: [{action: drop, tag: x}]`, "name is required"},
+		{`rules: [{name: a, action: drop, tag: x}, {name: a, action: hash, tag: y}]`, `invalid sanitizer rule #1: duplicate name "a"`},
+		{`rules: [{name: a, action: encrypt, tag: x}]`, `unknown action "encrypt"`},
+		{`rules: [{name: a, action: drop}]`, "tag is required by the drop action"},
+		{`rules: [{name: a, action: rename, tag: x}]`, "rename is required by the rename action"},
+		{`rules: [{name: a, action: truncate, tag: x}]`, "length must be positive for the truncate action"},
+		{`rules: [{name: a, action: redact, tag: x}]`, "pattern is required by the redact action"},
+		{`rules: [{name: a, action: redact, tag: x, pattern: "("}]`, "invalid pattern"},
+		{`rules: [{name: a, action: rename-operation, pattern: x}]`, "replacement is required by the rename-operation action"},
+		{`rules: [{name: a, action: add-tag, tag: "x*"}]`, "tag must be a single key for the add-tag action"},
+		{`rules: [{name: a, action: add-tag, tag: x, targets: [logs]}]`, "the add-tag action does not support the logs target"},
+		{`rules: [{name: a, action: drop, tag: x, targets: [refs]}]`, `unknown target "refs"`},
+	}
+	for _, test := range tests {
+		t.Run(test.err, func(t *testing.T) {
+			_, err := parseRules([]byte(test.rules))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	_, err := LoadRules("/does/not/exist")
+	assert.Contains(t, err.Error(), "failed to read sanitizer rules file")
+
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("rules: [{name: a, action: drop, tag: x}]"), 0600))
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+)
+
+const sanitizerRulesFile = "collector.sanitizer.rules-file"
+
+// Flags holds the command line configuration of the rule based sanitizer
+type Flags struct {
+	// RulesFile is the path to the YAML or JSON file with the sanitizer rules
+	RulesFile string
+}
+
+// AddFlags adds flags for the rule based sanitizer
+func AddFlags(flags *flag.FlagSet) {
+	flags.String(sanitizerRulesFile, "", "The path for the span sanitizer rules file in YAML or JSON format; the file is reloaded when it changes")
+}
+
+// InitFromViper initializes Flags with properties from viper
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.RulesFile = v.GetString(sanitizerRulesFile)
+	return f
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Rule is a validated sanitizer rule applied to the spans of the services and operations it is scoped to.
+type Rule struct {
+	name      string
+	action    string
+	service   *regexp.Regexp // nil matches any service
+	operation *regexp.Regexp // nil matches any operation
+	// keys selects the tags and log fields transformed by the rule, nil selects all of them.
+	keys    *regexp.Regexp
+	tags    bool
+	logs    bool
+	process bool
+
+	key         string
+	value       string
+	rename      string
+	length      int
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Name returns the name of the rule.
+func (r *Rule) Name() string {
+	return r.name
+}
+
+func matchGlob(re *regexp.Regexp, s string) bool {
+	return re == nil || re.MatchString(s)
+}
+
+// Apply transforms the span according to the rule and returns true if the span was modified.
+// The span process may be shared with other spans of the same batch, so it is copied rather
+// than modified in place.
+func (r *Rule) Apply(span *model.Span) bool {
+	service := ""
+	if span.Process != nil {
+		service = span.Process.ServiceName
+	}
+	if !matchGlob(r.service, service) || !matchGlob(r.operation, span.OperationName) {
+		return false
+	}
+	switch r.action {
+	case actionRenameOperation:
+		if !r.pattern.MatchString(span.OperationName) {
+			return false
+		}
+		operation := r.pattern.ReplaceAllString(span.OperationName, r.replacement)
+		if operation == span.OperationName {
+			return false
+		}
+		span.OperationName = operation
+		return true
+	case actionAddTag:
+		return r.applyToTargets(span, r.addTag)
+	default:
+		return r.applyToTargets(span, r.transform)
+	}
+}
+
+func (r *Rule) applyToTargets(span *model.Span, fn func([]model.KeyValue) ([]model.KeyValue, bool)) bool {
+	modified := false
+	if r.tags {
+		if tags, ok := fn(span.Tags); ok {
+			span.Tags = tags
+			modified = true
+		}
+	}
+	if r.logs {
+		for i := range span.Logs {
+			if fields, ok := fn(span.Logs[i].Fields); ok {
+				span.Logs[i].Fields = fields
+				modified = true
+			}
+		}
+	}
+	if r.process && span.Process != nil {
+		if tags, ok := fn(span.Process.Tags); ok {
+			process := *span.Process
+			process.Tags = tags
+			span.Process = &process
+			modified = true
+		}
+	}
+	return modified
+}
+
+// addTag sets the tag of the rule, replacing its value if the key is already present.
+func (r *Rule) addTag(kvs []model.KeyValue) ([]model.KeyValue, bool) {
+	tag := model.String(r.key, r.value)
+	for i, kv := range kvs {
+		if kv.Key != r.key {
+			continue
+		}
+		if kv.Equal(&tag) {
+			return kvs, false
+		}
+		out := append([]model.KeyValue(nil), kvs...)
+		out[i] = tag
+		return out, true
+	}
+	out := make([]model.KeyValue, 0, len(kvs)+1)
+	return append(append(out, kvs...), tag), true
+}
+
+// transform returns a copy of the key-values with the rule applied to the selected keys,
+// or the key-values themselves if none of them changed.
+func (r *Rule) transform(kvs []model.KeyValue) ([]model.KeyValue, bool) {
+	var out []model.KeyValue
+	for i, kv := range kvs {
+		if !matchGlob(r.keys, kv.Key) {
+			if out != nil {
+				out = append(out, kv)
+			}
+			continue
+		}
+		transformed, keep := r.transformValue(kv)
+		if out == nil {
+			if keep && transformed.Equal(&kv) {
+				continue
+			}
+			out = append(make([]model.KeyValue, 0, len(kvs)), kvs[:i]...)
+		}
+		if keep {
+			out = append(out, transformed)
+		}
+	}
+	if out == nil {
+		return kvs, false
+	}
+	return out, true
+}
+
+// transformValue applies the rule to a key-value, returning false if it must be dropped.
+func (r *Rule) transformValue(kv model.KeyValue) (model.KeyValue, bool) {
+	switch r.action {
+	case actionDrop:
+		return kv, false
+	case actionRename:
+		kv.Key = r.rename
+	case actionHash:
+		var sum [sha256.Size]byte
+		if kv.VType == model.BinaryType {
+			sum = sha256.Sum256(kv.VBinary)
+		} else {
+			sum = sha256.Sum256([]byte(kv.AsString()))
+		}
+		return model.String(kv.Key, hex.EncodeToString(sum[:])), true
+	case actionTruncate:
+		switch kv.VType {
+		case model.StringType:
+			kv.VStr = truncateString(kv.VStr, r.length)
+		case model.BinaryType:
+			if len(kv.VBinary) > r.length {
+				kv.VBinary = kv.VBinary[:r.length]
+			}
+		}
+	case actionRedact:
+		if kv.VType == model.StringType {
+			kv.VStr = r.pattern.ReplaceAllString(kv.VStr, r.replacement)
+		}
+	}
+	return kv, true
+}
+
+// truncateString cuts the string to at most n bytes without splitting a UTF-8 sequence.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func mustParseRule(t *testing.T, rule string) *Rule {
+	rules, err := parseRules([]byte("rules: [" + rule + "]"))
+	require.NoError(t, err)
+	return rules[0]
+}
+
+func newSpan() *model.Span {
+	return &model.Span{
+		OperationName: "/users/123",
+		Process: &model.Process{
+			ServiceName: "frontend",
+			Tags:        []model.KeyValue{model.String("user.email", "jane@example.com")},
+		},
+		Tags: []model.KeyValue{
+			model.String("user.email", "jane@example.com"),
+			model.String("card", "4111 1111 1111 1111"),
+			model.Int64("http.status_code", 200),
+		},
+		Logs: []model.Log{
+			{Fields: []model.KeyValue{model.String("user.email", "john@example.com")}},
+		},
+	}
+}
+
+func TestRuleActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		check func(t *testing.T, span *model.Span)
+	}{
+		{
+			name: "drop",
+			rule: "{name: r, action: drop, tag: user.*}",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Len(t, span.Tags, 2)
+				assert.Equal(t, "card", span.Tags[0].Key)
+				assert.Empty(t, span.Logs[0].Fields)
+				assert.Len(t, span.Process.Tags, 1)
+			},
+		},
+		{
+			name: "rename",
+			rule: "{name: r, action: rename, tag: user.email, rename: email, targets: [tags]}",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "email", span.Tags[0].Key)
+				assert.Equal(t, "user.email", span.Logs[0].Fields[0].Key)
+			},
+		},
+		{
+			name: "hash",
+			rule: "{name: r, action: hash, tag: http.status_code}",
+			check: func(t *testing.T, span *model.Span) {
+				// sha256("200")
+				assert.Equal(t, model.String("http.status_code",
+					"27badc983df1780b60c2b3fa9d3a19a00e46aac798451f0febdca52920faaddf"), span.Tags[2])
+			},
+		},
+		{
+			name: "truncate",
+			rule: "{name: r, action: truncate, tag: card, length: 4}",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "4111", span.Tags[1].VStr)
+			},
+		},
+		{
+			name: "redact",
+			rule: `{name: r, action: redact, tag: "*", pattern: '\b(?:\d[ -]?){13,16}\b'}`,
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "[REDACTED]", span.Tags[1].VStr)
+				assert.Equal(t, "jane@example.com", span.Tags[0].VStr)
+				assert.Equal(t, int64(200), span.Tags[2].Int64())
+			},
+		},
+		{
+			name: "rename operation",
+			rule: `{name: r, action: rename-operation, pattern: '^/users/\d+$', replacement: "/users/{id}"}`,
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, "/users/{id}", span.OperationName)
+			},
+		},
+		{
+			name: "add tag",
+			rule: "{name: r, action: add-tag, tag: region, value: eu}",
+			check: func(t *testing.T, span *model.Span) {
+				assert.Equal(t, model.String("region", "eu"), span.Tags[3])
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			span := newSpan()
+			assert.True(t, mustParseRule(t, test.rule).Apply(span))
+			test.check(t, span)
+		})
+	}
+}
+
+func TestRuleScope(t *testing.T) {
+	rule := mustParseRule(t, "{name: r, service: backend, action: drop, tag: user.email}")
+	span := newSpan()
+	assert.False(t, rule.Apply(span))
+	assert.Len(t, span.Tags, 3)
+
+	rule = mustParseRule(t, "{name: r, operation: /orders/*, action: drop, tag: user.email}")
+	assert.False(t, rule.Apply(span))
+
+	rule = mustParseRule(t, "{name: r, service: front*, operation: /users/*, action: drop, tag: user.email}")
+	assert.True(t, rule.Apply(span))
+	assert.Len(t, span.Tags, 2)
+}
+
+func TestRuleNotModified(t *testing.T) {
+	span := newSpan()
+	assert.False(t, mustParseRule(t, "{name: r, action: drop, tag: missing}").Apply(span))
+	assert.False(t, mustParseRule(t, "{name: r, action: truncate, tag: card, length: 100}").Apply(span))
+	assert.False(t, mustParseRule(t, "{name: r, action: redact, tag: card, pattern: secret}").Apply(span))
+	assert.False(t, mustParseRule(t, "{name: r, action: rename-operation, pattern: '^/orders', replacement: x}").Apply(span))
+	assert.False(t, mustParseRule(t, "{name: r, action: rename-operation, pattern: '^/users/123$', replacement: /users/123}").Apply(span))
+
+	rule := mustParseRule(t, "{name: r, action: add-tag, tag: region, value: eu}")
+	assert.True(t, rule.Apply(span))
+	assert.False(t, rule.Apply(span))
+	assert.True(t, mustParseRule(t, "{name: r, action: add-tag, tag: region, value: us}").Apply(span))
+	assert.Len(t, span.Tags, 4)
+	assert.Equal(t, "us", span.Tags[3].VStr)
+}
+
+func TestRuleSharedProcess(t *testing.T) {
+	rule := mustParseRule(t, "{name: r, action: hash, tag: user.email, targets: [process]}")
+	span1, span2 := newSpan(), newSpan()
+	span2.Process = span1.Process
+
+	assert.True(t, rule.Apply(span1))
+	assert.True(t, rule.Apply(span2))
+	assert.Equal(t, span1.Process.Tags, span2.Process.Tags)
+	assert.NotSame(t, span1.Process, span2.Process)
+	assert.Equal(t, "jane@example.com", span1.Tags[0].VStr)
+}
+
+func TestRuleTruncateBinaryAndUnicode(t *testing.T) {
+	rule := mustParseRule(t, "{name: r, action: truncate, tag: v, length: 2}")
+	span := &model.Span{Tags: []model.KeyValue{model.Binary("v", []byte{1, 2, 3})}}
+	assert.True(t, rule.Apply(span))
+	assert.Equal(t, []byte{1, 2}, span.Tags[0].VBinary)
+
+	assert.Equal(t, "a", truncateString("aé", 2))
+	assert.Equal(t, "aé", truncateString("aé", 3))
+}
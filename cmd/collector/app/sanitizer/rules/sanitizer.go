@@ -0,0 +1,162 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+)
+
+// Params are the dependencies of the rule based sanitizer.
+type Params struct {
+	// Path is the path of the rules file.
+	Path string
+	// MetricsFactory creates the per-rule hit counters and the reload counters.
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+	// NewWatcher creates the watcher of the rules file, fswatcher.NewWatcher by default.
+	NewWatcher func() (fswatcher.Watcher, error)
+}
+
+// Sanitizer applies the rules of a file to the spans, in the order of the file.
+// The rules are reloaded when the file changes; if the new rules are invalid,
+// the last valid ones remain in use.
+type Sanitizer struct {
+	path    string
+	logger  *zap.Logger
+	factory metrics.Factory
+	rules   atomic.Value // *ruleSet
+	watcher fswatcher.Watcher
+	done    sync.WaitGroup
+
+	reloadSuccess metrics.Counter
+	reloadFailure metrics.Counter
+}
+
+type ruleSet struct {
+	rules []*Rule
+	hits  []metrics.Counter
+}
+
+// New loads the rules file and starts watching it for changes.
+func New(params Params) (*Sanitizer, error) {
+	if params.NewWatcher == nil {
+		params.NewWatcher = fswatcher.NewWatcher
+	}
+	factory := params.MetricsFactory
+	s := &Sanitizer{
+		path:          params.Path,
+		logger:        params.Logger,
+		factory:       factory,
+		reloadSuccess: factory.Counter(metrics.Options{Name: "rules-reloads", Tags: map[string]string{"result": "ok"}}),
+		reloadFailure: factory.Counter(metrics.Options{Name: "rules-reloads", Tags: map[string]string{"result": "err"}}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.watch(params.NewWatcher); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Sanitizer) load() error {
+	rules, err := LoadRules(s.path)
+	if err != nil {
+		return err
+	}
+	set := &ruleSet{rules: rules, hits: make([]metrics.Counter, len(rules))}
+	for i, r := range rules {
+		set.hits[i] = s.factory.Counter(metrics.Options{Name: "rule-hits", Tags: map[string]string{"rule": r.name}})
+	}
+	s.rules.Store(set)
+	return nil
+}
+
+// Sanitize applies the rules to the span. It implements sanitizer.SanitizeSpan.
+func (s *Sanitizer) Sanitize(span *model.Span) *model.Span {
+	set := s.rules.Load().(*ruleSet)
+	for i, r := range set.rules {
+		if r.Apply(span) {
+			set.hits[i].Inc(1)
+		}
+	}
+	return span
+}
+
+func (s *Sanitizer) watch(newWatcher func() (fswatcher.Watcher, error)) error {
+	watcher, err := newWatcher()
+	if err != nil {
+		return err
+	}
+	// Watching the directory as well catches the file being replaced, e.g. by a ConfigMap update.
+	for _, name := range []string{s.path, filepath.Dir(s.path)} {
+		if err := watcher.Add(name); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+	s.watcher = watcher
+	s.done.Add(1)
+	go s.listen()
+	s.logger.Info("watching sanitizer rules file", zap.String("file", s.path))
+	return nil
+}
+
+func (s *Sanitizer) listen() {
+	defer s.done.Done()
+	for {
+		select {
+		case event, ok := <-s.watcher.Events():
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != filepath.Base(s.path) || event.Op&fsnotify.Chmod == fsnotify.Chmod {
+				continue
+			}
+			if event.Op&fsnotify.Remove == fsnotify.Remove {
+				s.logger.Warn("the sanitizer rules file has been removed, using the last known rules")
+				continue
+			}
+			if err := s.load(); err != nil {
+				s.reloadFailure.Inc(1)
+				s.logger.Error("failed to reload the sanitizer rules, using the last known rules", zap.Error(err))
+				continue
+			}
+			s.reloadSuccess.Inc(1)
+			s.logger.Info("reloaded sanitizer rules", zap.String("file", s.path))
+		case err, ok := <-s.watcher.Errors():
+			if !ok {
+				return
+			}
+			s.logger.Error("sanitizer rules file watcher error", zap.Error(err))
+		}
+	}
+}
+
+// Close stops watching the rules file.
+func (s *Sanitizer) Close() error {
+	err := s.watcher.Close()
+	s.done.Wait()
+	return err
+}
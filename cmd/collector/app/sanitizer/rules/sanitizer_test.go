@@ -0,0 +1,121 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/fswatcher"
+)
+
+func writeRules(t *testing.T, path, rules string) {
+	// write to a temporary file and rename it, so that the watcher never reads a partial file
+	tmp := path + ".tmp"
+	require.NoError(t, ioutil.WriteFile(tmp, []byte(rules), 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func TestSanitizer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+	writeRules(t, path, `
+rules:
+  - name: hash-email
+    action: hash
+    tag: user.email
+  - name: drop-card
+    service: backend
+    action: drop
+    tag: card
+`)
+
+	mf := metricstest.NewFactory(time.Hour)
+	s, err := New(Params{Path: path, MetricsFactory: mf, Logger: zap.NewNop()})
+	require.NoError(t, err)
+	defer s.Close()
+
+	span := s.Sanitize(newSpan())
+	assert.Len(t, span.Tags, 3)
+	assert.NotEqual(t, "jane@example.com", span.Tags[0].VStr)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "rule-hits", Tags: map[string]string{"rule": "hash-email"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "rule-hits", Tags: map[string]string{"rule": "drop-card"}, Value: 0},
+	)
+
+	// invalid rules are ignored
+	writeRules(t, path, `rules: [{name: broken, action: drop}]`)
+	assert.Eventually(t, func() bool {
+		counters, _ := mf.Snapshot()
+		return counters["rules-reloads|result=err"] > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	span = s.Sanitize(newSpan())
+	assert.NotEqual(t, "jane@example.com", span.Tags[0].VStr)
+
+	writeRules(t, path, `rules: [{name: drop-card, action: drop, tag: card}]`)
+	assert.Eventually(t, func() bool {
+		span := s.Sanitize(newSpan())
+		return len(span.Tags) == 2 && span.Tags[0].VStr == "jane@example.com"
+	}, 5*time.Second, 10*time.Millisecond)
+	counters, _ := mf.Snapshot()
+	assert.Greater(t, counters["rules-reloads|result=ok"], int64(0))
+	assert.Greater(t, counters["rule-hits|rule=drop-card"], int64(0))
+
+	assert.NoError(t, s.Close())
+}
+
+func TestSanitizerErrors(t *testing.T) {
+	params := Params{Path: "/does/not/exist", MetricsFactory: metricstest.NewFactory(time.Hour), Logger: zap.NewNop()}
+	_, err := New(params)
+	assert.Contains(t, err.Error(), "failed to read sanitizer rules file")
+
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	params.Path = filepath.Join(dir, "rules.yaml")
+	writeRules(t, params.Path, `rules: []`)
+
+	params.NewWatcher = func() (fswatcher.Watcher, error) {
+		return nil, errors.New("no watcher")
+	}
+	_, err = New(params)
+	assert.EqualError(t, err, "no watcher")
+}
+
+func TestSanitizerNoRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+	writeRules(t, path, `rules: []`)
+
+	s, err := New(Params{Path: path, MetricsFactory: metricstest.NewFactory(time.Hour), Logger: zap.NewNop()})
+	require.NoError(t, err)
+	defer s.Close()
+	span := &model.Span{OperationName: "op"}
+	assert.Same(t, span, s.Sanitize(span))
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/httpmetrics"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 // HTTPServerParams to construct a new Jaeger Collector HTTP Server
@@ -41,6 +42,7 @@ type HTTPServerParams struct {
 	MetricsFactory metrics.Factory
 	HealthCheck    *healthcheck.HealthCheck
 	Logger         *zap.Logger
+	TenancyMgr     *tenancy.Manager
 }
 
 // StartHTTPServer based on the given parameters
@@ -72,10 +74,10 @@ func StartHTTPServer(params *HTTPServerParams) (*http.Server, error) {
 
 func serveHTTP(server *http.Server, listener net.Listener, params *HTTPServerParams) {
 	r := mux.NewRouter()
-	apiHandler := handler.NewAPIHandler(params.Handler)
+	apiHandler := handler.NewAPIHandler(params.Handler, params.TenancyMgr)
 	apiHandler.RegisterRoutes(r)
 
-	cfgHandler := clientcfgHandler.NewHTTPHandler(clientcfgHandler.HTTPHandlerParams{
+	cfgHandlerParams := clientcfgHandler.HTTPHandlerParams{
 		ConfigManager: &clientcfgHandler.ConfigManager{
 			SamplingStrategyStore: params.SamplingStore,
 			// TODO provide baggage manager
@@ -83,7 +85,11 @@ func serveHTTP(server *http.Server, listener net.Listener, params *HTTPServerPar
 		MetricsFactory:         params.MetricsFactory,
 		BasePath:               "/api",
 		LegacySamplingEndpoint: false,
-	})
+	}
+	if dryRunner, ok := params.SamplingStore.(strategystore.DryRunner); ok {
+		cfgHandlerParams.SamplingDryRunner = dryRunner
+	}
+	cfgHandler := clientcfgHandler.NewHTTPHandler(cfgHandlerParams)
 	cfgHandler.RegisterRoutes(r)
 
 	recoveryHandler := recoveryhandler.NewRecoveryHandler(params.Logger, true)
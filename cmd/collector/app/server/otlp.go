@@ -0,0 +1,141 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/httpmetrics"
+	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+)
+
+// OTLPGRPCServerParams to construct a new OTLP/gRPC receiver
+type OTLPGRPCServerParams struct {
+	TLSConfig               tlscfg.Options
+	HostPort                string
+	Handler                 *handler.OTLPHandler
+	Logger                  *zap.Logger
+	OnError                 func(error)
+	MaxReceiveMessageLength int
+}
+
+// StartOTLPGRPCServer based on the given parameters
+func StartOTLPGRPCServer(params *OTLPGRPCServerParams) (*grpc.Server, error) {
+	var grpcOpts []grpc.ServerOption
+	if params.MaxReceiveMessageLength > 0 {
+		grpcOpts = append(grpcOpts, grpc.MaxRecvMsgSize(params.MaxReceiveMessageLength))
+	}
+	if params.TLSConfig.Enabled {
+		tlsCfg, err := params.TLSConfig.Config(params.Logger)
+		if err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+	server := grpc.NewServer(grpcOpts...)
+
+	listener, err := net.Listen("tcp", params.HostPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on OTLP gRPC port: %w", err)
+	}
+	serveOTLPGRPC(server, listener, params)
+
+	return server, nil
+}
+
+func serveOTLPGRPC(server *grpc.Server, listener net.Listener, params *OTLPGRPCServerParams) {
+	otlpgrpc.RegisterTracesServer(server, params.Handler)
+
+	params.Logger.Info("Starting jaeger-collector OTLP gRPC server", zap.String("otlp.grpc.host-port", params.HostPort))
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			params.Logger.Error("Could not launch OTLP gRPC service", zap.Error(err))
+			if params.OnError != nil {
+				params.OnError(err)
+			}
+		}
+	}()
+}
+
+// OTLPHTTPServerParams to construct a new OTLP/HTTP receiver
+type OTLPHTTPServerParams struct {
+	TLSConfig      tlscfg.Options
+	HostPort       string
+	Handler        *handler.OTLPHandler
+	MetricsFactory metrics.Factory
+	HealthCheck    *healthcheck.HealthCheck
+	Logger         *zap.Logger
+}
+
+// StartOTLPHTTPServer based on the given parameters
+func StartOTLPHTTPServer(params *OTLPHTTPServerParams) (*http.Server, error) {
+	params.Logger.Info("Starting jaeger-collector OTLP HTTP server", zap.String("otlp.http.host-port", params.HostPort))
+
+	errorLog, _ := zap.NewStdLogAt(params.Logger, zapcore.ErrorLevel)
+	server := &http.Server{
+		Addr:     params.HostPort,
+		ErrorLog: errorLog,
+	}
+	if params.TLSConfig.Enabled {
+		tlsCfg, err := params.TLSConfig.Config(params.Logger)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = tlsCfg
+	}
+
+	listener, err := net.Listen("tcp", params.HostPort)
+	if err != nil {
+		return nil, err
+	}
+	serveOTLPHTTP(server, listener, params)
+
+	return server, nil
+}
+
+func serveOTLPHTTP(server *http.Server, listener net.Listener, params *OTLPHTTPServerParams) {
+	r := mux.NewRouter()
+	params.Handler.RegisterRoutes(r)
+
+	recoveryHandler := recoveryhandler.NewRecoveryHandler(params.Logger, true)
+	server.Handler = httpmetrics.Wrap(recoveryHandler(r), params.MetricsFactory)
+	go func() {
+		var err error
+		if params.TLSConfig.Enabled {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil {
+			if err != http.ErrServerClosed {
+				params.Logger.Error("Could not start OTLP HTTP server", zap.Error(err))
+			}
+		}
+		params.HealthCheck.Set(healthcheck.Unavailable)
+	}()
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func TestOTLPGRPCFailToListen(t *testing.T) {
+	logger := zap.NewNop()
+	server, err := StartOTLPGRPCServer(&OTLPGRPCServerParams{
+		HostPort: ":-1",
+		Handler:  handler.NewOTLPHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		Logger:   logger,
+	})
+	assert.Nil(t, server)
+	assert.EqualError(t, err, "failed to listen on OTLP gRPC port: listen tcp: address -1: invalid port")
+}
+
+func TestOTLPGRPCServer(t *testing.T) {
+	logger := zap.NewNop()
+	server := grpc.NewServer()
+	defer server.Stop()
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serveOTLPGRPC(server, listener, &OTLPGRPCServerParams{
+		Handler: handler.NewOTLPHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		Logger:  logger,
+	})
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = otlpgrpc.NewTracesClient(conn).Export(context.Background(), otlpgrpc.NewTracesRequest())
+	require.NoError(t, err)
+}
+
+func TestOTLPHTTPFailToListen(t *testing.T) {
+	logger := zap.NewNop()
+	server, err := StartOTLPHTTPServer(&OTLPHTTPServerParams{
+		HostPort: ":-1",
+		Handler:  handler.NewOTLPHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		Logger:   logger,
+	})
+	assert.Nil(t, server)
+	assert.Error(t, err)
+}
+
+func TestOTLPHTTPServer(t *testing.T) {
+	logger := zap.NewNop()
+	server, err := StartOTLPHTTPServer(&OTLPHTTPServerParams{
+		HostPort:       "localhost:0",
+		Handler:        handler.NewOTLPHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		HealthCheck:    healthcheck.New(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		Logger:         logger,
+	})
+	require.NoError(t, err)
+	defer server.Close()
+}
+
+func TestOTLPHTTPServeRequest(t *testing.T) {
+	logger := zap.NewNop()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := &http.Server{}
+	defer server.Close()
+	serveOTLPHTTP(server, listener, &OTLPHTTPServerParams{
+		Handler:        handler.NewOTLPHandler(logger, &mockSpanProcessor{}, &tenancy.Manager{}),
+		HealthCheck:    healthcheck.New(),
+		MetricsFactory: metricstest.NewFactory(time.Hour),
+		Logger:         logger,
+	})
+
+	resp, err := http.Post("http://"+listener.Addr().String()+handler.OTLPTracesPath, "application/json", bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
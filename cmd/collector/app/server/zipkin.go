@@ -30,6 +30,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"github.com/jaegertracing/jaeger/pkg/httpmetrics"
 	"github.com/jaegertracing/jaeger/pkg/recoveryhandler"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 // ZipkinServerParams to construct a new Jaeger Collector Zipkin Server
@@ -41,6 +42,7 @@ type ZipkinServerParams struct {
 	HealthCheck    *healthcheck.HealthCheck
 	Logger         *zap.Logger
 	MetricsFactory metrics.Factory
+	TenancyMgr     *tenancy.Manager
 }
 
 // StartZipkinServer based on the given parameters
@@ -69,7 +71,7 @@ func StartZipkinServer(params *ZipkinServerParams) (*http.Server, error) {
 
 func serveZipkin(server *http.Server, listener net.Listener, params *ZipkinServerParams) {
 	r := mux.NewRouter()
-	zHandler := zipkin.NewAPIHandler(params.Handler)
+	zHandler := zipkin.NewAPIHandler(params.Handler, params.TenancyMgr)
 	zHandler.RegisterRoutes(r)
 
 	origins := strings.Split(strings.ReplaceAll(params.AllowedOrigins, " ", ""), ",")
@@ -24,8 +24,11 @@ import (
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	zs "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -35,6 +38,9 @@ type SpanHandlerBuilder struct {
 	CollectorOpts  CollectorOptions
 	Logger         *zap.Logger
 	MetricsFactory metrics.Factory
+	TenancyMgr     *tenancy.Manager
+	// Sanitizer, when set, is applied to every span before it is processed
+	Sanitizer sanitizer.SanitizeSpan
 }
 
 // SpanHandlers holds instances to the span handlers built by the SpanHandlerBuilder
@@ -42,19 +48,18 @@ type SpanHandlers struct {
 	ZipkinSpansHandler   handler.ZipkinSpansHandler
 	JaegerBatchesHandler handler.JaegerBatchesHandler
 	GRPCHandler          *handler.GRPCHandler
+	OTLPHandler          *handler.OTLPHandler
 }
 
 // BuildSpanProcessor builds the span processor to be used with the handlers
-func (b *SpanHandlerBuilder) BuildSpanProcessor(additional ...ProcessSpan) processor.SpanProcessor {
+func (b *SpanHandlerBuilder) BuildSpanProcessor(additional ...ProcessSpan) (processor.SpanProcessor, error) {
 	hostname, _ := os.Hostname()
 	svcMetrics := b.metricsFactory()
 	hostMetrics := svcMetrics.Namespace(metrics.NSOptions{Tags: map[string]string{"host": hostname}})
 	logger := b.logger()
 	preprocessor := &Preprocessor{Logger: logger}
 
-	return NewSpanProcessor(
-		b.SpanWriter,
-		additional,
+	options := []Option{
 		Options.ServiceMetrics(svcMetrics),
 		Options.HostMetrics(hostMetrics),
 		Options.Logger(logger),
@@ -65,15 +70,63 @@ func (b *SpanHandlerBuilder) BuildSpanProcessor(additional ...ProcessSpan) proce
 		Options.DynQueueSizeWarmup(uint(b.CollectorOpts.QueueSize)), // same as queue size for now
 		Options.DynQueueSizeMemory(b.CollectorOpts.DynQueueSizeMemory),
 		Options.PreProcessSpans(preprocessor.ProcessSpans),
-	)
+	}
+	if b.Sanitizer != nil {
+		options = append(options, Options.Sanitizer(b.Sanitizer))
+	}
+	if err := b.CollectorOpts.Throttling.Validate(); err != nil {
+		return nil, err
+	}
+	options = append(options, Options.Throttling(b.CollectorOpts.Throttling))
+	switch b.CollectorOpts.QueueType {
+	case "", QueueTypeMemory:
+	case QueueTypeDisk:
+		diskQueue, err := b.buildDiskQueue(svcMetrics.Namespace(metrics.NSOptions{Name: "disk_queue"}), logger)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, Options.Queue(diskQueue))
+	default:
+		return nil, fmt.Errorf("unknown queue type %q, must be %s or %s", b.CollectorOpts.QueueType, QueueTypeMemory, QueueTypeDisk)
+	}
+	return NewSpanProcessor(b.SpanWriter, additional, options...), nil
+}
+
+func (b *SpanHandlerBuilder) buildDiskQueue(metricsFactory metrics.Factory, logger *zap.Logger) (*queue.PersistentQueue, error) {
+	opts := b.CollectorOpts.DiskQueue
+	if opts.Directory == "" {
+		return nil, fmt.Errorf("the disk queue requires a directory")
+	}
+	if b.CollectorOpts.DynQueueSizeMemory > 0 {
+		logger.Warn("The dynamic queue size is ignored with the disk queue")
+	}
+	if b.CollectorOpts.Throttling.FairQueuing {
+		logger.Warn("Fair queuing is ignored with the disk queue, which has no fixed capacity")
+	}
+	logger.Info("Using the disk queue",
+		zap.String("directory", opts.Directory),
+		zap.Int64("max-size", opts.MaxSize),
+		zap.String("fsync", opts.SyncPolicy))
+	return queue.NewPersistentQueue(queue.PersistentQueueOptions{
+		Directory:      opts.Directory,
+		SegmentSize:    opts.SegmentSize,
+		MaxSize:        opts.MaxSize,
+		SyncPolicy:     opts.SyncPolicy,
+		SyncInterval:   opts.SyncInterval,
+		Encode:         encodeQueueItem,
+		Decode:         decodeQueueItem,
+		MetricsFactory: metricsFactory,
+		Logger:         logger,
+	})
 }
 
-// BuildHandlers builds span handlers (Zipkin, Jaeger)
+// BuildHandlers builds span handlers (Zipkin, Jaeger, OTLP)
 func (b *SpanHandlerBuilder) BuildHandlers(spanProcessor processor.SpanProcessor) *SpanHandlers {
 	return &SpanHandlers{
 		handler.NewZipkinSpanHandler(b.Logger, spanProcessor, zs.NewChainedSanitizer(zs.StandardSanitizers...)),
 		handler.NewJaegerSpanHandler(b.Logger, spanProcessor),
-		handler.NewGRPCHandler(b.Logger, spanProcessor),
+		handler.NewGRPCHandler(b.Logger, spanProcessor, b.TenancyMgr),
+		handler.NewOTLPHandler(b.Logger, spanProcessor, b.TenancyMgr),
 	}
 }
 
@@ -16,6 +16,9 @@
 package app
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -24,6 +27,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
@@ -52,7 +56,8 @@ func TestNewSpanHandlerBuilder(t *testing.T) {
 		MetricsFactory: metrics.NullFactory,
 	}
 
-	spanProcessor := builder.BuildSpanProcessor()
+	spanProcessor, err := builder.BuildSpanProcessor()
+	require.NoError(t, err)
 	spanHandlers := builder.BuildHandlers(spanProcessor)
 	assert.NotNil(t, spanHandlers.ZipkinSpansHandler)
 	assert.NotNil(t, spanHandlers.JaegerBatchesHandler)
@@ -60,6 +65,54 @@ func TestNewSpanHandlerBuilder(t *testing.T) {
 	assert.NotNil(t, spanProcessor)
 }
 
+func TestBuildSpanProcessorWithDiskQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "queue")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	spanWriter := memory.NewStore()
+	builder := &SpanHandlerBuilder{
+		SpanWriter: spanWriter,
+		CollectorOpts: CollectorOptions{
+			QueueType: QueueTypeDisk,
+			DiskQueue: DiskQueueOptions{Directory: dir},
+		},
+	}
+	spanProcessor, err := builder.BuildSpanProcessor()
+	require.NoError(t, err)
+	_, err = spanProcessor.ProcessSpans([]*model.Span{{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "op",
+		Process:       &model.Process{ServiceName: "svc"},
+	}}, processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		trace, err := spanWriter.GetTrace(context.Background(), model.NewTraceID(0, 1))
+		return err == nil && len(trace.Spans) == 1
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, spanProcessor.Close())
+
+	builder.CollectorOpts.DiskQueue.Directory = ""
+	_, err = builder.BuildSpanProcessor()
+	assert.EqualError(t, err, "the disk queue requires a directory")
+
+	builder.CollectorOpts.QueueType = "cloud"
+	_, err = builder.BuildSpanProcessor()
+	assert.EqualError(t, err, `unknown queue type "cloud", must be memory or disk`)
+}
+
+func TestBuildSpanProcessorWithInvalidThrottling(t *testing.T) {
+	builder := &SpanHandlerBuilder{
+		SpanWriter: memory.NewStore(),
+		CollectorOpts: CollectorOptions{
+			Throttling: ThrottlingOptions{ServiceSpansPerSecond: -1},
+		},
+	}
+	_, err := builder.BuildSpanProcessor()
+	assert.EqualError(t, err, "rate limits must be positive or 0 to disable them")
+}
+
 func TestDefaultSpanFilter(t *testing.T) {
 	assert.True(t, defaultSpanFilter(nil))
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/queue"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -39,8 +40,9 @@ const (
 )
 
 type spanProcessor struct {
-	queue              *queue.BoundedQueue
+	queue              queue.Queue
 	queueResizeMu      sync.Mutex
+	throttler          *throttler // nil if neither rate limits nor fair queuing are configured
 	metrics            *SpanProcessorMetrics
 	preProcessSpans    ProcessSpans
 	filterSpan         FilterSpan             // filter is called before the sanitizer but after preProcessSpans
@@ -61,6 +63,7 @@ type spanProcessor struct {
 type queueItem struct {
 	queuedTime time.Time
 	span       *model.Span
+	tenant     string
 }
 
 // NewSpanProcessor returns a SpanProcessor that preProcesses, filters, queues, sanitizes, and processes spans
@@ -91,13 +94,14 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 		options.serviceMetrics,
 		options.hostMetrics,
 		options.extraFormatTypes)
-	droppedItemHandler := func(item interface{}) {
-		handlerMetrics.SpansDropped.Inc(1)
+	spanQueue := options.queue
+	if spanQueue == nil {
+		// the dropped items are counted by enqueueSpan, for all the queue implementations
+		spanQueue = queue.NewBoundedQueue(options.queueSize, func(item interface{}) {})
 	}
-	boundedQueue := queue.NewBoundedQueue(options.queueSize, droppedItemHandler)
 
 	sp := spanProcessor{
-		queue:              boundedQueue,
+		queue:              spanQueue,
 		metrics:            handlerMetrics,
 		logger:             options.logger,
 		preProcessSpans:    options.preProcessSpans,
@@ -114,6 +118,10 @@ func newSpanProcessor(spanWriter spanstore.Writer, additional []ProcessSpan, opt
 		spansProcessed:     atomic.NewUint64(0),
 	}
 
+	if options.throttling.Enabled() {
+		sp.throttler = newThrottler(options.throttling, spanQueue, handlerMetrics)
+	}
+
 	processSpanFuncs := []ProcessSpan{options.preSave, sp.saveSpan}
 	if options.dynQueueSizeMemory > 0 {
 		// add to processSpanFuncs
@@ -136,7 +144,7 @@ func (sp *spanProcessor) Close() error {
 	return nil
 }
 
-func (sp *spanProcessor) saveSpan(span *model.Span) {
+func (sp *spanProcessor) saveSpan(span *model.Span, tenant string) {
 	if nil == span.Process {
 		sp.logger.Error("process is empty for the span")
 		sp.metrics.SavedErrBySvc.ReportServiceNameForSpan(span)
@@ -145,7 +153,8 @@ func (sp *spanProcessor) saveSpan(span *model.Span) {
 
 	startTime := time.Now()
 	// TODO context should be propagated from upstream components
-	if err := sp.spanWriter.WriteSpan(context.TODO(), span); err != nil {
+	ctx := tenancy.WithTenant(context.TODO(), tenant)
+	if err := sp.spanWriter.WriteSpan(ctx, span); err != nil {
 		sp.logger.Error("Failed to save span", zap.Error(err))
 		sp.metrics.SavedErrBySvc.ReportServiceNameForSpan(span)
 	} else {
@@ -156,7 +165,7 @@ func (sp *spanProcessor) saveSpan(span *model.Span) {
 	sp.metrics.SaveLatency.Record(time.Since(startTime))
 }
 
-func (sp *spanProcessor) countSpan(span *model.Span) {
+func (sp *spanProcessor) countSpan(span *model.Span, tenant string) {
 	sp.bytesProcessed.Add(uint64(span.Size()))
 	sp.spansProcessed.Inc()
 }
@@ -164,9 +173,12 @@ func (sp *spanProcessor) countSpan(span *model.Span) {
 func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.SpansOptions) ([]bool, error) {
 	sp.preProcessSpans(mSpans)
 	sp.metrics.BatchSize.Update(int64(len(mSpans)))
+	if sp.throttler != nil && !sp.throttler.admit(mSpans, options.Tenant) {
+		return nil, processor.ErrBusy
+	}
 	retMe := make([]bool, len(mSpans))
 	for i, mSpan := range mSpans {
-		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport)
+		ok := sp.enqueueSpan(mSpan, options.SpanFormat, options.InboundTransport, options.Tenant)
 		if !ok && sp.reportBusy {
 			return nil, processor.ErrBusy
 		}
@@ -176,7 +188,11 @@ func (sp *spanProcessor) ProcessSpans(mSpans []*model.Span, options processor.Sp
 }
 
 func (sp *spanProcessor) processItemFromQueue(item *queueItem) {
-	sp.processSpan(sp.sanitizer(item.span))
+	if sp.throttler != nil {
+		// before the sanitizer, which may change the service name
+		sp.throttler.dequeued(item.span)
+	}
+	sp.processSpan(sp.sanitizer(item.span), item.tenant)
 	sp.metrics.InQueueLatency.Record(time.Since(item.queuedTime))
 }
 
@@ -201,7 +217,7 @@ func (sp *spanProcessor) addCollectorTags(span *model.Span) {
 	typedTags.Sort()
 }
 
-func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat processor.SpanFormat, transport processor.InboundTransport) bool {
+func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat processor.SpanFormat, transport processor.InboundTransport, tenant string) bool {
 	spanCounts := sp.metrics.GetCountsForFormat(originalFormat, transport)
 	spanCounts.ReceivedBySvc.ReportServiceNameForSpan(span)
 
@@ -219,8 +235,20 @@ func (sp *spanProcessor) enqueueSpan(span *model.Span, originalFormat processor.
 	item := &queueItem{
 		queuedTime: time.Now(),
 		span:       span,
+		tenant:     tenant,
+	}
+	if sp.throttler != nil {
+		// counted before producing, as a consumer may dequeue the span right away
+		sp.throttler.enqueued(span)
 	}
-	return sp.queue.Produce(item)
+	if !sp.queue.Produce(item) {
+		if sp.throttler != nil {
+			sp.throttler.dequeued(span)
+		}
+		sp.metrics.SpansDropped.Inc(1)
+		return false
+	}
+	return true
 }
 
 func (sp *spanProcessor) background(reportPeriod time.Duration, callback func()) {
@@ -251,6 +279,12 @@ func (sp *spanProcessor) updateQueueSize() {
 		return
 	}
 
+	// only the in-memory queue is bounded by a number of items
+	boundedQueue, ok := sp.queue.(*queue.BoundedQueue)
+	if !ok {
+		return
+	}
+
 	sp.queueResizeMu.Lock()
 	defer sp.queueResizeMu.Unlock()
 
@@ -266,7 +300,7 @@ func (sp *spanProcessor) updateQueueSize() {
 	}
 
 	var diff float64
-	current := float64(sp.queue.Capacity())
+	current := float64(boundedQueue.Capacity())
 	if idealQueueSize > current {
 		diff = idealQueueSize / current
 	} else {
@@ -277,7 +311,7 @@ func (sp *spanProcessor) updateQueueSize() {
 	if diff > minRequiredChange {
 		s := int(idealQueueSize)
 		sp.logger.Info("Resizing the internal span queue", zap.Int("new-size", s), zap.Uint64("average-span-size-bytes", average))
-		sp.queue.Resize(s)
+		boundedQueue.Resize(s)
 	}
 }
 
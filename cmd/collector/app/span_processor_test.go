@@ -33,6 +33,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	zipkinSanitizer "github.com/jaegertracing/jaeger/cmd/collector/app/sanitizer/zipkin"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	zc "github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
@@ -165,10 +166,12 @@ func isSpanAllowed(span *model.Span) bool {
 }
 
 type fakeSpanWriter struct {
-	err error
+	err     error
+	tenants sync.Map // of span ID to the tenant of the write context
 }
 
 func (n *fakeSpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	n.tenants.Store(span.SpanID, tenancy.GetTenant(ctx))
 	return n.err
 }
 
@@ -327,7 +330,7 @@ func TestSpanProcessorWithNilProcess(t *testing.T) {
 	p := NewSpanProcessor(w, nil, Options.ServiceMetrics(serviceMetrics)).(*spanProcessor)
 	defer assert.NoError(t, p.Close())
 
-	p.saveSpan(&model.Span{})
+	p.saveSpan(&model.Span{}, "")
 
 	expected := []metricstest.ExpectedMetric{{
 		Name: "service.spans.saved-by-svc|debug=false|result=err|svc=__unknown", Value: 1,
@@ -391,7 +394,7 @@ func TestSpanProcessorCountSpan(t *testing.T) {
 	p := NewSpanProcessor(w, nil, Options.HostMetrics(m), Options.DynQueueSizeMemory(1000)).(*spanProcessor)
 	p.background(10*time.Millisecond, p.updateGauges)
 
-	p.processSpan(&model.Span{})
+	p.processSpan(&model.Span{}, "")
 	assert.NotEqual(t, uint64(0), p.bytesProcessed)
 
 	for i := 0; i < 15; i++ {
@@ -546,7 +549,7 @@ func TestAdditionalProcessors(t *testing.T) {
 
 	// additional processor is called
 	count := 0
-	f := func(s *model.Span) {
+	f := func(s *model.Span, tenant string) {
 		count++
 	}
 	p = NewSpanProcessor(w, []ProcessSpan{f}, Options.QueueSize(1))
@@ -562,3 +565,26 @@ func TestAdditionalProcessors(t *testing.T) {
 	assert.NoError(t, p.Close())
 	assert.Equal(t, 1, count)
 }
+
+func TestSpanProcessorPropagatesTenant(t *testing.T) {
+	w := &fakeSpanWriter{}
+	var processedTenant string
+	f := func(s *model.Span, tenant string) {
+		processedTenant = tenant
+	}
+	p := NewSpanProcessor(w, []ProcessSpan{f}, Options.QueueSize(1))
+	res, err := p.ProcessSpans([]*model.Span{
+		{
+			SpanID:  model.NewSpanID(1),
+			Process: &model.Process{ServiceName: "x"},
+		},
+	}, processor.SpansOptions{SpanFormat: processor.ProtoSpanFormat, Tenant: "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true}, res)
+	assert.NoError(t, p.Close())
+
+	tenant, ok := w.tenants.Load(model.NewSpanID(1))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, "acme", processedTenant)
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// DefaultBucketInterval is the default time resolution of the aggregated metrics
+	DefaultBucketInterval = 15 * time.Second
+	// DefaultRetention is the default time the aggregated metrics are kept in memory
+	DefaultRetention = time.Hour
+	// DefaultMaxSeries is the default number of series held per time bucket
+	DefaultMaxSeries = 10000
+
+	// otherServices and otherOperations name the series shared by the spans beyond MaxSeries.
+	otherServices   = "other-services"
+	otherOperations = "other-operations"
+)
+
+// otelSpanKinds maps the Jaeger span kinds to the OTEL representation used by the metrics query API.
+var otelSpanKinds = map[string]string{
+	"internal": "SPAN_KIND_INTERNAL",
+	"server":   "SPAN_KIND_SERVER",
+	"client":   "SPAN_KIND_CLIENT",
+	"producer": "SPAN_KIND_PRODUCER",
+	"consumer": "SPAN_KIND_CONSUMER",
+}
+
+const otelSpanKindUnspecified = "SPAN_KIND_UNSPECIFIED"
+
+type aggregatorMetrics struct {
+	// SpansAggregated counts the spans added to the metrics
+	SpansAggregated metrics.Counter `metric:"spans_aggregated"`
+	// Series is the number of tenant, service, operation and span kind combinations currently held in memory
+	Series metrics.Gauge `metric:"series"`
+	// SeriesDropped counts the spans added to the overflow series of their tenant because their own series
+	// would exceed the maximum number of series of the time bucket
+	SeriesDropped metrics.Counter `metric:"series_dropped"`
+	// BucketsWritten counts the time buckets written to the span metrics storage
+	BucketsWritten metrics.Counter `metric:"buckets_written"`
+	// WriteErrors counts the failed writes to the span metrics storage
+	WriteErrors metrics.Counter `metric:"write_errors"`
+}
+
+// Options configures the span metrics Aggregator.
+type Options struct {
+	// BucketInterval is the time resolution of the aggregated metrics
+	BucketInterval time.Duration
+	// Retention is how long the aggregated metrics are kept in memory
+	Retention time.Duration
+	// LatencyBounds are the upper bounds of the latency histogram buckets
+	LatencyBounds []time.Duration
+	// MaxSeries bounds the number of tenant, service, operation and span kind combinations per time bucket,
+	// the spans of the combinations beyond it are aggregated in a single series per tenant and span kind
+	MaxSeries int
+	// Source identifies this collector in the persisted metrics
+	Source string
+	// Writer, when set, receives the metrics of every time bucket once it is complete
+	Writer metricsstore.SpanMetricsWriter
+	// MetricsFactory is used to report the aggregator metrics
+	MetricsFactory metrics.Factory
+	// Logger is used to report write errors
+	Logger *zap.Logger
+}
+
+type seriesKey struct {
+	tenant        string
+	serviceName   string
+	operationName string
+	spanKind      string
+}
+
+// Aggregator computes RED (rate, errors, duration) metrics per tenant, service, operation and span kind from the
+// spans received by the collector, in time buckets of a fixed interval. It implements metricsstore.SpanMetricsReader
+// over the buckets held in memory.
+type Aggregator struct {
+	bucketInterval time.Duration
+	retention      time.Duration
+	latencyBounds  []time.Duration
+	maxSeries      int
+	source         string
+	writer         metricsstore.SpanMetricsWriter
+	logger         *zap.Logger
+	metrics        aggregatorMetrics
+	timeNow        func() time.Time
+
+	mux         sync.RWMutex
+	buckets     map[int64]map[seriesKey]*metricsstore.SpanMetrics // by bucket start in unix nanoseconds
+	lastFlushed int64                                             // start of the last bucket given to the writer
+	stopCh      chan struct{}
+	stopped     sync.WaitGroup
+	closeOnce   sync.Once
+}
+
+// NewAggregator creates a span metrics Aggregator and starts its background loop
+// that writes the complete buckets and discards the expired ones.
+func NewAggregator(opts Options) *Aggregator {
+	if opts.BucketInterval <= 0 {
+		opts.BucketInterval = DefaultBucketInterval
+	}
+	if opts.Retention < opts.BucketInterval {
+		opts.Retention = DefaultRetention
+	}
+	if len(opts.LatencyBounds) == 0 {
+		opts.LatencyBounds = metricsstore.DefaultLatencyBounds
+	}
+	if opts.MaxSeries <= 0 {
+		opts.MaxSeries = DefaultMaxSeries
+	}
+	if opts.MetricsFactory == nil {
+		opts.MetricsFactory = metrics.NullFactory
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	aggMetrics := aggregatorMetrics{}
+	metrics.Init(&aggMetrics, opts.MetricsFactory, nil)
+	a := &Aggregator{
+		bucketInterval: opts.BucketInterval,
+		retention:      opts.Retention,
+		latencyBounds:  opts.LatencyBounds,
+		maxSeries:      opts.MaxSeries,
+		source:         opts.Source,
+		writer:         opts.Writer,
+		logger:         opts.Logger,
+		metrics:        aggMetrics,
+		timeNow:        time.Now,
+		buckets:        make(map[int64]map[seriesKey]*metricsstore.SpanMetrics),
+		stopCh:         make(chan struct{}),
+	}
+	a.stopped.Add(1)
+	go a.flushLoop()
+	return a
+}
+
+// ProcessSpan adds the span to the metrics of its tenant in the current time bucket. Once the bucket
+// holds MaxSeries series, the spans of new series are added to the overflow series of their tenant.
+func (a *Aggregator) ProcessSpan(span *model.Span, tenant string) {
+	if span.Process == nil {
+		return
+	}
+	key := seriesKey{
+		tenant:        tenant,
+		serviceName:   span.Process.ServiceName,
+		operationName: span.OperationName,
+		spanKind:      otelSpanKind(span),
+	}
+	bucketStart := a.timeNow().Truncate(a.bucketInterval)
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	bucket, ok := a.buckets[bucketStart.UnixNano()]
+	if !ok {
+		bucket = make(map[seriesKey]*metricsstore.SpanMetrics)
+		a.buckets[bucketStart.UnixNano()] = bucket
+	}
+	sm, ok := bucket[key]
+	if !ok && len(bucket) >= a.maxSeries {
+		key.serviceName = otherServices
+		key.operationName = otherOperations
+		sm, ok = bucket[key]
+		a.metrics.SeriesDropped.Inc(1)
+	}
+	if !ok {
+		sm = &metricsstore.SpanMetrics{
+			ServiceName:   key.serviceName,
+			OperationName: key.operationName,
+			SpanKind:      key.spanKind,
+			Source:        a.source,
+			Timestamp:     bucketStart,
+			LatencyBounds: a.latencyBounds,
+			LatencyCounts: make([]int64, len(a.latencyBounds)+1),
+		}
+		bucket[key] = sm
+	}
+	sm.Observe(span.Duration, spanstore.IsErrorSpan(span))
+	a.metrics.SpansAggregated.Inc(1)
+}
+
+// GetSpanMetrics implements metricsstore.SpanMetricsReader, for the tenant carried by the context.
+// The metrics of the current time bucket are included, even though more spans can still be added to them.
+func (a *Aggregator) GetSpanMetrics(ctx context.Context, query *metricsstore.SpanMetricsQuery) ([]*metricsstore.SpanMetrics, error) {
+	tenant := tenancy.GetTenant(ctx)
+	a.mux.RLock()
+	defer a.mux.RUnlock()
+	var result []*metricsstore.SpanMetrics
+	for start, bucket := range a.buckets {
+		if start < query.StartTime.UnixNano() || start >= query.EndTime.UnixNano() {
+			continue
+		}
+		for key, sm := range bucket {
+			if key.tenant != tenant || !query.Matches(sm) {
+				continue
+			}
+			cp := *sm
+			cp.LatencyCounts = append([]int64(nil), sm.LatencyCounts...)
+			result = append(result, &cp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+// Close stops the background loop and writes all the buckets not yet written, including the current one.
+func (a *Aggregator) Close() error {
+	a.closeOnce.Do(func() {
+		close(a.stopCh)
+		a.stopped.Wait()
+		a.flush(a.timeNow().Truncate(a.bucketInterval).Add(a.bucketInterval))
+	})
+	return nil
+}
+
+func (a *Aggregator) flushLoop() {
+	defer a.stopped.Done()
+	ticker := time.NewTicker(a.bucketInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.flush(a.timeNow().Truncate(a.bucketInterval))
+		case <-a.stopCh:
+			return
+		}
+	}
+}
+
+// flush writes the buckets starting before the given time that were not written yet,
+// and discards the buckets older than the retention.
+func (a *Aggregator) flush(before time.Time) {
+	toWrite := make(map[string][]*metricsstore.SpanMetrics) // by tenant
+	a.mux.Lock()
+	expired := before.Add(-a.retention).UnixNano()
+	var series int
+	for start, bucket := range a.buckets {
+		if start < expired {
+			delete(a.buckets, start)
+			continue
+		}
+		series += len(bucket)
+		if a.writer == nil || start <= a.lastFlushed || start >= before.UnixNano() {
+			continue
+		}
+		for key, sm := range bucket {
+			cp := *sm
+			cp.LatencyCounts = append([]int64(nil), sm.LatencyCounts...)
+			toWrite[key.tenant] = append(toWrite[key.tenant], &cp)
+		}
+		a.metrics.BucketsWritten.Inc(1)
+	}
+	if before.UnixNano()-a.bucketInterval.Nanoseconds() > a.lastFlushed {
+		a.lastFlushed = before.UnixNano() - a.bucketInterval.Nanoseconds()
+	}
+	a.metrics.Series.Update(int64(series))
+	a.mux.Unlock()
+
+	for tenant, spanMetrics := range toWrite {
+		ctx := tenancy.WithTenant(context.Background(), tenant)
+		if err := a.writer.WriteSpanMetrics(ctx, spanMetrics); err != nil {
+			a.metrics.WriteErrors.Inc(1)
+			a.logger.Error("Failed to write span metrics", zap.String("tenant", tenant), zap.Error(err))
+		}
+	}
+}
+
+func otelSpanKind(span *model.Span) string {
+	kind, _ := span.GetSpanKind()
+	if otelKind, ok := otelSpanKinds[kind]; ok {
+		return otelKind
+	}
+	return otelSpanKindUnspecified
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+type fakeSpanMetricsWriter struct {
+	mux     sync.Mutex
+	metrics []*metricsstore.SpanMetrics
+	tenants []string // of each written metric
+	err     error
+}
+
+func (w *fakeSpanMetricsWriter) WriteSpanMetrics(ctx context.Context, spanMetrics []*metricsstore.SpanMetrics) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.metrics = append(w.metrics, spanMetrics...)
+	for range spanMetrics {
+		w.tenants = append(w.tenants, tenancy.GetTenant(ctx))
+	}
+	return w.err
+}
+
+func (w *fakeSpanMetricsWriter) getMetrics() []*metricsstore.SpanMetrics {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.metrics
+}
+
+type fakeClock struct {
+	mux sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) timeNow() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newSpan(service, operation, kind string, duration time.Duration, isError bool) *model.Span {
+	span := &model.Span{
+		OperationName: operation,
+		Duration:      duration,
+		Process:       model.NewProcess(service, nil),
+	}
+	if kind != "" {
+		span.Tags = append(span.Tags, model.String("span.kind", kind))
+	}
+	if isError {
+		span.Tags = append(span.Tags, model.Bool("error", true))
+	}
+	return span
+}
+
+func newTestAggregator(writer metricsstore.SpanMetricsWriter, metricsFactory *metricstest.Factory) (*Aggregator, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	a := NewAggregator(Options{
+		BucketInterval: time.Minute,
+		Retention:      time.Hour,
+		LatencyBounds:  []time.Duration{time.Millisecond, 10 * time.Millisecond},
+		Source:         "collector-1",
+		Writer:         writer,
+		MetricsFactory: metricsFactory,
+	})
+	a.timeNow = clock.timeNow
+	return a, clock
+}
+
+func TestAggregatorProcessSpan(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	a, clock := newTestAggregator(nil, metricsFactory)
+	defer a.Close()
+
+	a.ProcessSpan(newSpan("service", "get", "server", 500*time.Microsecond, false), "")
+	a.ProcessSpan(newSpan("service", "get", "server", 5*time.Millisecond, true), "")
+	a.ProcessSpan(newSpan("service", "get", "client", time.Second, false), "")
+	a.ProcessSpan(newSpan("service", "put", "", time.Second, false), "")
+	a.ProcessSpan(&model.Span{OperationName: "no-process"}, "")
+	clock.advance(time.Minute)
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+
+	bucket1 := time.Unix(960, 0)
+	bucket2 := time.Unix(1020, 0)
+	result, err := a.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: bucket1, EndTime: bucket2})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*metricsstore.SpanMetrics{
+		{
+			ServiceName: "service", OperationName: "get", SpanKind: "SPAN_KIND_SERVER", Source: "collector-1", Timestamp: bucket1,
+			Calls: 2, Errors: 1, LatencyBounds: a.latencyBounds, LatencyCounts: []int64{1, 1, 0},
+		},
+		{
+			ServiceName: "service", OperationName: "get", SpanKind: "SPAN_KIND_CLIENT", Source: "collector-1", Timestamp: bucket1,
+			Calls: 1, LatencyBounds: a.latencyBounds, LatencyCounts: []int64{0, 0, 1},
+		},
+		{
+			ServiceName: "service", OperationName: "put", SpanKind: "SPAN_KIND_UNSPECIFIED", Source: "collector-1", Timestamp: bucket1,
+			Calls: 1, LatencyBounds: a.latencyBounds, LatencyCounts: []int64{0, 0, 1},
+		},
+	}, result)
+
+	result, err = a.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: bucket1, EndTime: bucket2.Add(time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+	assert.Equal(t, bucket2, result[3].Timestamp)
+	assert.Equal(t, []int64{1, 0, 0}, result[3].LatencyCounts)
+
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "spans_aggregated", Value: 5})
+}
+
+func TestAggregatorMaxSeries(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	a, clock := newTestAggregator(nil, metricsFactory)
+	defer a.Close()
+	a.maxSeries = 2
+
+	a.ProcessSpan(newSpan("service", "get", "server", time.Second, false), "")
+	a.ProcessSpan(newSpan("service", "put", "server", time.Second, false), "")
+	a.ProcessSpan(newSpan("service", "get", "server", time.Second, false), "")
+	a.ProcessSpan(newSpan("service", "delete", "server", time.Second, true), "")
+	a.ProcessSpan(newSpan("other", "get", "server", time.Second, false), "")
+	a.ProcessSpan(newSpan("other", "get", "client", time.Second, false), "")
+	clock.advance(time.Minute)
+	a.ProcessSpan(newSpan("other", "get", "server", time.Second, false), "")
+
+	result, err := a.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: time.Unix(960, 0), EndTime: time.Unix(1080, 0)})
+	require.NoError(t, err)
+	calls := make(map[string]int64)
+	for _, sm := range result {
+		calls[sm.Timestamp.Format("15:04")+" "+sm.ServiceName+" "+sm.OperationName+" "+sm.SpanKind] = sm.Calls
+	}
+	bucket1, bucket2 := time.Unix(960, 0).Format("15:04"), time.Unix(1020, 0).Format("15:04")
+	assert.Equal(t, map[string]int64{
+		bucket1 + " service get SPAN_KIND_SERVER":                     2,
+		bucket1 + " service put SPAN_KIND_SERVER":                     1,
+		bucket1 + " other-services other-operations SPAN_KIND_SERVER": 2,
+		bucket1 + " other-services other-operations SPAN_KIND_CLIENT": 1,
+		bucket2 + " other get SPAN_KIND_SERVER":                       1,
+	}, calls)
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_aggregated", Value: 7},
+		metricstest.ExpectedMetric{Name: "series_dropped", Value: 3},
+	)
+}
+
+func TestAggregatorWritesCompleteBuckets(t *testing.T) {
+	writer := &fakeSpanMetricsWriter{}
+	metricsFactory := metricstest.NewFactory(0)
+	a, clock := newTestAggregator(writer, metricsFactory)
+
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	a.flush(clock.timeNow().Truncate(time.Minute))
+	assert.Empty(t, writer.getMetrics(), "the current bucket is not complete")
+
+	clock.advance(time.Minute)
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	a.flush(clock.timeNow().Truncate(time.Minute))
+	require.Len(t, writer.getMetrics(), 1)
+	assert.Equal(t, time.Unix(960, 0), writer.getMetrics()[0].Timestamp)
+
+	// the same bucket is not written twice
+	a.flush(clock.timeNow().Truncate(time.Minute))
+	require.Len(t, writer.getMetrics(), 1)
+
+	// the current bucket is written on close
+	require.NoError(t, a.Close())
+	require.Len(t, writer.getMetrics(), 2)
+	assert.Equal(t, time.Unix(1020, 0), writer.getMetrics()[1].Timestamp)
+
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "buckets_written", Value: 2})
+	metricsFactory.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "series", Value: 2})
+}
+
+func TestAggregatorDiscardsExpiredBuckets(t *testing.T) {
+	a, clock := newTestAggregator(nil, metricstest.NewFactory(0))
+	defer a.Close()
+
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	clock.advance(2 * time.Hour)
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	a.flush(clock.timeNow().Truncate(time.Minute))
+
+	result, err := a.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: time.Unix(0, 0), EndTime: clock.timeNow().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, clock.timeNow().Truncate(time.Minute), result[0].Timestamp)
+}
+
+func TestAggregatorSeparatesTenants(t *testing.T) {
+	writer := &fakeSpanMetricsWriter{}
+	a, _ := newTestAggregator(writer, metricstest.NewFactory(0))
+
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "acme")
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "acme")
+
+	result, err := a.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: time.Unix(0, 0), EndTime: time.Unix(2000, 0)})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.EqualValues(t, 1, result[0].Calls)
+
+	result, err = a.GetSpanMetrics(tenancy.WithTenant(context.Background(), "acme"), &metricsstore.SpanMetricsQuery{StartTime: time.Unix(0, 0), EndTime: time.Unix(2000, 0)})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.EqualValues(t, 2, result[0].Calls)
+
+	require.NoError(t, a.Close())
+	assert.ElementsMatch(t, []string{"", "acme"}, writer.tenants)
+}
+
+func TestAggregatorWriteErrors(t *testing.T) {
+	writer := &fakeSpanMetricsWriter{err: errors.New("write failed")}
+	metricsFactory := metricstest.NewFactory(0)
+	a, _ := newTestAggregator(writer, metricsFactory)
+
+	a.ProcessSpan(newSpan("service", "get", "server", time.Millisecond, false), "")
+	require.NoError(t, a.Close())
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "write_errors", Value: 1})
+}
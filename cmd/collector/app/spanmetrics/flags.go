@@ -0,0 +1,63 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	spanMetricsEnabled        = "collector.span-metrics.enabled"
+	spanMetricsBucketInterval = "collector.span-metrics.bucket-interval"
+	spanMetricsRetention      = "collector.span-metrics.retention"
+	spanMetricsPersist        = "collector.span-metrics.persist"
+	spanMetricsMaxSeries      = "collector.span-metrics.max-series"
+)
+
+// Flags holds the command line configuration of the span metrics aggregator
+type Flags struct {
+	// Enabled turns on the aggregation of RED metrics from the received spans
+	Enabled bool
+	// BucketInterval is the time resolution of the aggregated metrics
+	BucketInterval time.Duration
+	// Retention is how long the aggregated metrics are kept in memory
+	Retention time.Duration
+	// Persist turns on writing the aggregated metrics to the span storage backend, if it supports it
+	Persist bool
+	// MaxSeries bounds the number of service, operation and span kind combinations per time bucket
+	MaxSeries int
+}
+
+// AddFlags adds flags for the span metrics aggregator
+func AddFlags(flags *flag.FlagSet) {
+	flags.Bool(spanMetricsEnabled, false, "(experimental) Aggregates call counts, error counts and latency histograms per service, operation and span kind from the received spans")
+	flags.Duration(spanMetricsBucketInterval, DefaultBucketInterval, "The time resolution of the aggregated span metrics")
+	flags.Duration(spanMetricsRetention, DefaultRetention, "How long the aggregated span metrics are kept in memory")
+	flags.Bool(spanMetricsPersist, false, "Writes the aggregated span metrics to the span storage backend, if the backend supports it")
+	flags.Int(spanMetricsMaxSeries, DefaultMaxSeries, "The maximum number of service, operation and span kind combinations per time bucket, the spans of the combinations beyond it are aggregated under the service "+otherServices)
+}
+
+// InitFromViper initializes Flags with properties from viper
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.Enabled = v.GetBool(spanMetricsEnabled)
+	f.BucketInterval = v.GetDuration(spanMetricsBucketInterval)
+	f.Retention = v.GetDuration(spanMetricsRetention)
+	f.Persist = v.GetBool(spanMetricsPersist)
+	f.MaxSeries = v.GetInt(spanMetricsMaxSeries)
+	return f
+}
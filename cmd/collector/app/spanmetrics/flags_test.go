@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.span-metrics.enabled=true",
+		"--collector.span-metrics.bucket-interval=5s",
+		"--collector.span-metrics.retention=10m",
+		"--collector.span-metrics.persist=true",
+		"--collector.span-metrics.max-series=100",
+	})
+	f := new(Flags).InitFromViper(v)
+	assert.Equal(t, &Flags{
+		Enabled:        true,
+		BucketInterval: 5 * time.Second,
+		Retention:      10 * time.Minute,
+		Persist:        true,
+		MaxSeries:      100,
+	}, f)
+}
+
+func TestDefaultFlags(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	f := new(Flags).InitFromViper(v)
+	assert.Equal(t, &Flags{
+		BucketInterval: DefaultBucketInterval,
+		Retention:      DefaultRetention,
+		MaxSeries:      DefaultMaxSeries,
+	}, f)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+)
+
+const (
+	policyTypeError         = "error"
+	policyTypeLatency       = "latency"
+	policyTypeAttribute     = "attribute"
+	policyTypeProbabilistic = "probabilistic"
+	policyTypeRateLimiting  = "rate-limiting"
+)
+
+// policiesConfig is the JSON representation of the policies file, e.g.
+//
+//	{"policies": [
+//	  {"type": "error"},
+//	  {"type": "latency", "threshold": "2s"},
+//	  {"type": "attribute", "service": "checkout", "tag_key": "http.status_code", "tag_value": "500"},
+//	  {"type": "rate-limiting", "traces_per_second": 10},
+//	  {"type": "probabilistic", "sampling_rate": 0.01}
+//	]}
+type policiesConfig struct {
+	Policies []policyConfig `json:"policies"`
+}
+
+type policyConfig struct {
+	Type            string  `json:"type"`
+	Threshold       string  `json:"threshold"`
+	Service         string  `json:"service"`
+	Operation       string  `json:"operation"`
+	TagKey          string  `json:"tag_key"`
+	TagValue        string  `json:"tag_value"`
+	SamplingRate    float64 `json:"sampling_rate"`
+	HashSalt        string  `json:"hash_salt"`
+	TracesPerSecond float64 `json:"traces_per_second"`
+}
+
+// LoadPolicies reads the ordered list of policies from a JSON file.
+func LoadPolicies(path string) ([]Policy, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tail sampling policies file: %w", err)
+	}
+	return parsePolicies(b)
+}
+
+func parsePolicies(b []byte) ([]Policy, error) {
+	var cfg policiesConfig
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tail sampling policies: %w", err)
+	}
+	policies := make([]Policy, 0, len(cfg.Policies))
+	for i, pc := range cfg.Policies {
+		p, err := pc.toPolicy()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tail sampling policy #%d: %w", i, err)
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (pc policyConfig) toPolicy() (Policy, error) {
+	switch pc.Type {
+	case policyTypeError:
+		return NewErrorPolicy(), nil
+	case policyTypeLatency:
+		threshold, err := time.ParseDuration(pc.Threshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latency threshold: %w", err)
+		}
+		return NewLatencyPolicy(threshold), nil
+	case policyTypeAttribute:
+		if pc.Service == "" && pc.Operation == "" && pc.TagKey == "" {
+			return nil, fmt.Errorf("attribute policy requires at least one of service, operation or tag_key")
+		}
+		return NewAttributePolicy(pc.Service, pc.Operation, pc.TagKey, pc.TagValue), nil
+	case policyTypeProbabilistic:
+		if pc.SamplingRate < 0 || pc.SamplingRate > 1 {
+			return nil, fmt.Errorf("sampling_rate must be between 0 and 1, got %v", pc.SamplingRate)
+		}
+		return NewProbabilisticPolicy(pc.SamplingRate, pc.HashSalt), nil
+	case policyTypeRateLimiting:
+		if pc.TracesPerSecond <= 0 {
+			return nil, fmt.Errorf("traces_per_second must be positive, got %v", pc.TracesPerSecond)
+		}
+		return NewRateLimitingPolicy(pc.TracesPerSecond), nil
+	}
+	return nil, fmt.Errorf("unknown policy type %q", pc.Type)
+}
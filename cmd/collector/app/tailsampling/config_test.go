@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicies(t *testing.T) {
+	f, err := ioutil.TempFile("", "policies*.json")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{"policies": [
+		{"type": "error"},
+		{"type": "latency", "threshold": "2s"},
+		{"type": "attribute", "service": "checkout", "tag_key": "http.status_code", "tag_value": "500"},
+		{"type": "rate-limiting", "traces_per_second": 10},
+		{"type": "probabilistic", "sampling_rate": 0.01}
+	]}`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	policies, err := LoadPolicies(f.Name())
+	require.NoError(t, err)
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.Name())
+	}
+	assert.Equal(t, []string{"error", "latency", "attribute", "rate-limiting", "probabilistic"}, names)
+
+	_, err = LoadPolicies("/does/not/exist.json")
+	assert.Error(t, err)
+}
+
+func TestParsePoliciesErrors(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected string
+	}{
+		{json: `{`, expected: "failed to unmarshal tail sampling policies: unexpected EOF"},
+		{json: `{"policies": [{"type": "error", "foo": 1}]}`, expected: `failed to unmarshal tail sampling policies: json: unknown field "foo"`},
+		{json: `{"policies": [{"type": "magic"}]}`, expected: `invalid tail sampling policy #0: unknown policy type "magic"`},
+		{json: `{"policies": [{"type": "error"}, {"type": "latency", "threshold": "x"}]}`, expected: `invalid tail sampling policy #1: invalid latency threshold: time: invalid duration "x"`},
+		{json: `{"policies": [{"type": "attribute"}]}`, expected: "invalid tail sampling policy #0: attribute policy requires at least one of service, operation or tag_key"},
+		{json: `{"policies": [{"type": "probabilistic", "sampling_rate": 2}]}`, expected: "invalid tail sampling policy #0: sampling_rate must be between 0 and 1, got 2"},
+		{json: `{"policies": [{"type": "rate-limiting"}]}`, expected: "invalid tail sampling policy #0: traces_per_second must be positive, got 0"},
+	}
+	for _, test := range tests {
+		_, err := parsePolicies([]byte(test.json))
+		assert.EqualError(t, err, test.expected)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	tailSamplingEnabled      = "collector.tail-sampling.enabled"
+	tailSamplingDecisionWait = "collector.tail-sampling.decision-wait"
+	tailSamplingMaxTraces    = "collector.tail-sampling.max-traces"
+	tailSamplingPoliciesFile = "collector.tail-sampling.policies-file"
+)
+
+// Flags holds the command line configuration of the tail sampling stage
+type Flags struct {
+	// Enabled turns on buffering of spans by trace before writing them to storage
+	Enabled bool
+	// DecisionWait is how long to wait for the spans of a trace before making a decision
+	DecisionWait time.Duration
+	// MaxTraces is the maximum number of traces held in memory
+	MaxTraces int
+	// PoliciesFile is the path to the JSON file with the sampling policies
+	PoliciesFile string
+}
+
+// AddFlags adds flags for the tail sampling stage
+func AddFlags(flags *flag.FlagSet) {
+	flags.Bool(tailSamplingEnabled, false, "(experimental) Buffers spans by trace and only writes the traces selected by the tail sampling policies")
+	flags.Duration(tailSamplingDecisionWait, DefaultDecisionWait, "How long to wait after the first span of a trace before making the tail sampling decision")
+	flags.Int(tailSamplingMaxTraces, DefaultMaxTraces, "The maximum number of traces held in memory; when exceeded the oldest trace is decided early")
+	flags.String(tailSamplingPoliciesFile, "", "The path for the tail sampling policies file in JSON format")
+}
+
+// InitFromViper initializes Flags with properties from viper
+func (f *Flags) InitFromViper(v *viper.Viper) *Flags {
+	f.Enabled = v.GetBool(tailSamplingEnabled)
+	f.DecisionWait = v.GetDuration(tailSamplingDecisionWait)
+	f.MaxTraces = v.GetInt(tailSamplingMaxTraces)
+	f.PoliciesFile = v.GetString(tailSamplingPoliciesFile)
+	return f
+}
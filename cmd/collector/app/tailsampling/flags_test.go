@@ -0,0 +1,41 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--collector.tail-sampling.enabled=true",
+		"--collector.tail-sampling.decision-wait=5s",
+		"--collector.tail-sampling.max-traces=100",
+		"--collector.tail-sampling.policies-file=/etc/policies.json",
+	})
+	f := new(Flags).InitFromViper(v)
+	assert.Equal(t, &Flags{
+		Enabled:      true,
+		DecisionWait: 5 * time.Second,
+		MaxTraces:    100,
+		PoliciesFile: "/etc/policies.json",
+	}, f)
+}
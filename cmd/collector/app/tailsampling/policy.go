@@ -0,0 +1,227 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-client-go/utils"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// maxServiceNames bounds the number of per-service limiters of the rate limiting policy,
+	// the same value as the limit on the service names of the collector metrics.
+	maxServiceNames = 4000
+	// otherServices is the key of the limiter shared by the services beyond maxServiceNames.
+	otherServices = "other-services"
+)
+
+// Policy decides whether a complete (or evicted partial) trace should be kept.
+type Policy interface {
+	// Name identifies the policy in logs and metrics
+	Name() string
+	// ShouldSample returns true if the trace must be written to storage
+	ShouldSample(trace *model.Trace) bool
+}
+
+type errorPolicy struct{}
+
+// NewErrorPolicy creates a policy that keeps traces containing at least one span tagged with error=true.
+func NewErrorPolicy() Policy {
+	return errorPolicy{}
+}
+
+func (errorPolicy) Name() string {
+	return "error"
+}
+
+func (errorPolicy) ShouldSample(trace *model.Trace) bool {
+	for _, span := range trace.Spans {
+		// the tag may be reported as a bool or as a string, depending on the client
+		if tag, ok := model.KeyValues(span.Tags).FindByKey("error"); ok && tag.AsString() == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+type latencyPolicy struct {
+	threshold time.Duration
+}
+
+// NewLatencyPolicy creates a policy that keeps traces whose root span lasted at least threshold.
+// When the root span has not been received, the time envelope of all received spans is used instead.
+func NewLatencyPolicy(threshold time.Duration) Policy {
+	return latencyPolicy{threshold: threshold}
+}
+
+func (latencyPolicy) Name() string {
+	return "latency"
+}
+
+func (p latencyPolicy) ShouldSample(trace *model.Trace) bool {
+	return traceDuration(trace) >= p.threshold
+}
+
+func traceDuration(trace *model.Trace) time.Duration {
+	var start, end time.Time
+	for _, span := range trace.Spans {
+		if span.ParentSpanID() == 0 {
+			return span.Duration
+		}
+		if start.IsZero() || span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if spanEnd := span.StartTime.Add(span.Duration); spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return end.Sub(start)
+}
+
+type attributePolicy struct {
+	serviceName   string
+	operationName string
+	tagKey        string
+	tagValue      string
+}
+
+// NewAttributePolicy creates a policy that keeps traces with a span matching all of the non-empty
+// criteria: service name, operation name and tag (an empty tagValue matches any value of tagKey).
+func NewAttributePolicy(serviceName, operationName, tagKey, tagValue string) Policy {
+	return attributePolicy{
+		serviceName:   serviceName,
+		operationName: operationName,
+		tagKey:        tagKey,
+		tagValue:      tagValue,
+	}
+}
+
+func (attributePolicy) Name() string {
+	return "attribute"
+}
+
+func (p attributePolicy) ShouldSample(trace *model.Trace) bool {
+	for _, span := range trace.Spans {
+		if p.matches(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p attributePolicy) matches(span *model.Span) bool {
+	if p.serviceName != "" && (span.Process == nil || span.Process.ServiceName != p.serviceName) {
+		return false
+	}
+	if p.operationName != "" && span.OperationName != p.operationName {
+		return false
+	}
+	if p.tagKey != "" {
+		tag, ok := model.KeyValues(span.Tags).FindByKey(p.tagKey)
+		if !ok {
+			return false
+		}
+		if p.tagValue != "" && tag.AsString() != p.tagValue {
+			return false
+		}
+	}
+	return true
+}
+
+type probabilisticPolicy struct {
+	sampler *spanstore.Sampler
+}
+
+// NewProbabilisticPolicy creates a policy that keeps the given ratio of traces, based on a hash of the trace ID.
+func NewProbabilisticPolicy(ratio float64, hashSalt string) Policy {
+	return probabilisticPolicy{sampler: spanstore.NewSampler(ratio, hashSalt)}
+}
+
+func (probabilisticPolicy) Name() string {
+	return "probabilistic"
+}
+
+func (p probabilisticPolicy) ShouldSample(trace *model.Trace) bool {
+	if len(trace.Spans) == 0 {
+		return false
+	}
+	return p.sampler.ShouldSample(trace.Spans[0])
+}
+
+type rateLimitingPolicy struct {
+	tracesPerSecond float64
+	maxServiceNames int
+	mux             sync.Mutex
+	limiters        map[string]utils.RateLimiter
+}
+
+// NewRateLimitingPolicy creates a policy that keeps up to tracesPerSecond traces for each root service.
+// The services seen after the first maxServiceNames share a single budget.
+func NewRateLimitingPolicy(tracesPerSecond float64) Policy {
+	return newRateLimitingPolicy(tracesPerSecond, maxServiceNames)
+}
+
+func newRateLimitingPolicy(tracesPerSecond float64, maxServiceNames int) *rateLimitingPolicy {
+	return &rateLimitingPolicy{
+		tracesPerSecond: tracesPerSecond,
+		maxServiceNames: maxServiceNames,
+		limiters:        make(map[string]utils.RateLimiter),
+	}
+}
+
+func (*rateLimitingPolicy) Name() string {
+	return "rate-limiting"
+}
+
+func (p *rateLimitingPolicy) ShouldSample(trace *model.Trace) bool {
+	service := rootServiceName(trace)
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	limiter, ok := p.limiters[service]
+	if !ok && len(p.limiters) >= p.maxServiceNames {
+		service = otherServices
+		limiter, ok = p.limiters[service]
+	}
+	if !ok {
+		maxBalance := p.tracesPerSecond
+		if maxBalance < 1 {
+			maxBalance = 1
+		}
+		limiter = utils.NewRateLimiter(p.tracesPerSecond, maxBalance)
+		p.limiters[service] = limiter
+	}
+	return limiter.CheckCredit(1)
+}
+
+func rootServiceName(trace *model.Trace) string {
+	var service string
+	for _, span := range trace.Spans {
+		if span.Process == nil {
+			continue
+		}
+		if span.ParentSpanID() == 0 {
+			return span.Process.ServiceName
+		}
+		if service == "" {
+			service = span.Process.ServiceName
+		}
+	}
+	return service
+}
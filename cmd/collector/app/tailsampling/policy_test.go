@@ -0,0 +1,137 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func makeSpan(traceID uint64, spanID uint64, parentID uint64, service, operation string, duration time.Duration, tags ...model.KeyValue) *model.Span {
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, traceID),
+		SpanID:        model.NewSpanID(spanID),
+		OperationName: operation,
+		StartTime:     time.Unix(100, 0),
+		Duration:      duration,
+		Tags:          tags,
+		Process:       &model.Process{ServiceName: service},
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(parentID))}
+	}
+	return span
+}
+
+func TestErrorPolicy(t *testing.T) {
+	p := NewErrorPolicy()
+	assert.Equal(t, "error", p.Name())
+	assert.False(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "svc", "op", time.Second)}}))
+	assert.True(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{
+		makeSpan(1, 1, 0, "svc", "op", time.Second),
+		makeSpan(1, 2, 1, "svc", "op", time.Second, model.Bool("error", true)),
+	}}))
+	assert.True(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "svc", "op", time.Second, model.String("error", "true"))}}))
+	assert.False(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "svc", "op", time.Second, model.Bool("error", false))}}))
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	p := NewLatencyPolicy(2 * time.Second)
+	assert.Equal(t, "latency", p.Name())
+	assert.True(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{
+		makeSpan(1, 2, 1, "svc", "child", time.Millisecond),
+		makeSpan(1, 1, 0, "svc", "root", 3*time.Second),
+	}}))
+	assert.False(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{
+		makeSpan(1, 2, 1, "svc", "child", 3*time.Second),
+		makeSpan(1, 1, 0, "svc", "root", time.Second),
+	}}))
+
+	// without a root span the envelope of all spans is used
+	late := makeSpan(1, 3, 1, "svc", "child", time.Second)
+	late.StartTime = late.StartTime.Add(1500 * time.Millisecond)
+	assert.True(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 2, 1, "svc", "child", time.Second), late}}))
+}
+
+func TestAttributePolicy(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{
+		makeSpan(1, 1, 0, "frontend", "GET /", time.Second),
+		makeSpan(1, 2, 1, "checkout", "pay", time.Second, model.Int64("http.status_code", 500)),
+	}}
+	tests := []struct {
+		policy   Policy
+		expected bool
+	}{
+		{policy: NewAttributePolicy("checkout", "", "", ""), expected: true},
+		{policy: NewAttributePolicy("checkout", "pay", "", ""), expected: true},
+		{policy: NewAttributePolicy("checkout", "GET /", "", ""), expected: false},
+		{policy: NewAttributePolicy("", "", "http.status_code", "500"), expected: true},
+		{policy: NewAttributePolicy("", "", "http.status_code", ""), expected: true},
+		{policy: NewAttributePolicy("", "", "http.status_code", "200"), expected: false},
+		{policy: NewAttributePolicy("frontend", "", "http.status_code", ""), expected: false},
+		{policy: NewAttributePolicy("billing", "", "", ""), expected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, "attribute", test.policy.Name())
+		assert.Equal(t, test.expected, test.policy.ShouldSample(trace), "%+v", test.policy)
+	}
+}
+
+func TestProbabilisticPolicy(t *testing.T) {
+	trace := &model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "svc", "op", time.Second)}}
+	always := NewProbabilisticPolicy(1, "")
+	assert.Equal(t, "probabilistic", always.Name())
+	assert.True(t, always.ShouldSample(trace))
+	assert.False(t, always.ShouldSample(&model.Trace{}))
+	assert.False(t, NewProbabilisticPolicy(0, "").ShouldSample(trace))
+}
+
+func TestRateLimitingPolicy(t *testing.T) {
+	p := NewRateLimitingPolicy(1)
+	assert.Equal(t, "rate-limiting", p.Name())
+	frontend := &model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "frontend", "op", time.Second)}}
+	backend := &model.Trace{Spans: []*model.Span{makeSpan(2, 2, 1, "backend", "op", time.Second)}}
+	assert.True(t, p.ShouldSample(frontend))
+	assert.False(t, p.ShouldSample(frontend))
+	// each service has its own budget
+	assert.True(t, p.ShouldSample(backend))
+	assert.False(t, p.ShouldSample(backend))
+}
+
+func TestRateLimitingPolicyMaxServiceNames(t *testing.T) {
+	p := newRateLimitingPolicy(1, 2)
+	for _, service := range []string{"frontend", "backend", "db", "cache"} {
+		p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, service, "op", time.Second)}})
+	}
+	assert.Len(t, p.limiters, 3)
+	assert.Contains(t, p.limiters, "frontend")
+	assert.Contains(t, p.limiters, "backend")
+	assert.Contains(t, p.limiters, otherServices)
+	// db already consumed the budget shared with cache
+	assert.False(t, p.ShouldSample(&model.Trace{Spans: []*model.Span{makeSpan(1, 1, 0, "cache", "op", time.Second)}}))
+}
+
+func TestRootServiceName(t *testing.T) {
+	assert.Equal(t, "", rootServiceName(&model.Trace{Spans: []*model.Span{{}}}))
+	assert.Equal(t, "child", rootServiceName(&model.Trace{Spans: []*model.Span{makeSpan(1, 2, 1, "child", "op", 0)}}))
+	assert.Equal(t, "root", rootServiceName(&model.Trace{Spans: []*model.Span{
+		makeSpan(1, 2, 1, "child", "op", 0),
+		makeSpan(1, 1, 0, "root", "op", 0),
+	}}))
+}
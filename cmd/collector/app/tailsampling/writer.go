@@ -0,0 +1,282 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/cache"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// DefaultDecisionWait is the default time spans of a trace are buffered before the sampling decision
+	DefaultDecisionWait = 10 * time.Second
+	// DefaultMaxTraces is the default maximum number of traces held in memory
+	DefaultMaxTraces = 50000
+
+	maxTickInterval = time.Second
+)
+
+type writerMetrics struct {
+	// TracesBuffered is the number of traces currently waiting for a decision
+	TracesBuffered metrics.Gauge `metric:"traces_buffered"`
+	// SpansBuffered is the number of spans currently waiting for a decision
+	SpansBuffered metrics.Gauge `metric:"spans_buffered"`
+	// TracesDecided counts traces for which a sampling decision was made
+	TracesDecided metrics.Counter `metric:"traces_decided"`
+	// TracesKept counts traces handed to the span writer
+	TracesKept metrics.Counter `metric:"traces" tags:"result=kept"`
+	// TracesDropped counts traces rejected by all policies
+	TracesDropped metrics.Counter `metric:"traces" tags:"result=dropped"`
+	// TracesEvicted counts traces decided before the end of the decision window because the buffer was full
+	TracesEvicted metrics.Counter `metric:"traces_evicted"`
+	// LateSpansKept counts spans that arrived after their trace was kept
+	LateSpansKept metrics.Counter `metric:"late_spans" tags:"result=kept"`
+	// LateSpansDropped counts spans that arrived after their trace was dropped
+	LateSpansDropped metrics.Counter `metric:"late_spans" tags:"result=dropped"`
+	// WriteErrors counts spans that the underlying span writer failed to save
+	WriteErrors metrics.Counter `metric:"write_errors"`
+}
+
+// Options configures the tail sampling Writer.
+type Options struct {
+	// DecisionWait is how long spans of a trace are buffered, counting from the first span received
+	DecisionWait time.Duration
+	// MaxTraces bounds the number of traces in the buffer; the oldest trace is decided early on overflow
+	MaxTraces int
+	// Policies are evaluated in order, a trace is kept if any of them samples it
+	Policies []Policy
+	// MetricsFactory is used to report the writer metrics
+	MetricsFactory metrics.Factory
+	// Logger is used to report write errors
+	Logger *zap.Logger
+}
+
+// traceKey identifies a trace, the same trace ID being possibly used by several tenants
+type traceKey struct {
+	tenant  string
+	traceID model.TraceID
+}
+
+func (k traceKey) String() string {
+	return k.tenant + "/" + k.traceID.String()
+}
+
+type bufferedTrace struct {
+	key     traceKey
+	arrival time.Time
+	spans   []*model.Span
+}
+
+type decidedTrace struct {
+	tenant string
+	spans  []*model.Span
+	keep   bool
+}
+
+// Writer is a span Writer that groups spans by trace ID, waits for a decision window to elapse,
+// and only then evaluates the sampling policies and forwards the entire trace to the wrapped writer.
+type Writer struct {
+	spanWriter   spanstore.Writer
+	policies     []Policy
+	decisionWait time.Duration
+	maxTraces    int
+	logger       *zap.Logger
+	metrics      writerMetrics
+	keptByPolicy map[string]metrics.Counter
+	timeNow      func() time.Time
+
+	mux       sync.Mutex
+	traces    map[traceKey]*list.Element
+	order     *list.List // of *bufferedTrace, oldest first
+	spanCount int
+	decisions cache.Cache // decisions for recently decided traces, to route late spans
+	stopCh    chan struct{}
+	stopped   sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewWriter creates a tail sampling Writer and starts its background decision loop.
+func NewWriter(spanWriter spanstore.Writer, opts Options) *Writer {
+	if opts.DecisionWait <= 0 {
+		opts.DecisionWait = DefaultDecisionWait
+	}
+	if opts.MaxTraces <= 0 {
+		opts.MaxTraces = DefaultMaxTraces
+	}
+	if opts.MetricsFactory == nil {
+		opts.MetricsFactory = metrics.NullFactory
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+	writerMetrics := writerMetrics{}
+	metrics.Init(&writerMetrics, opts.MetricsFactory, nil)
+	keptByPolicy := make(map[string]metrics.Counter, len(opts.Policies))
+	for _, p := range opts.Policies {
+		keptByPolicy[p.Name()] = opts.MetricsFactory.Counter(metrics.Options{
+			Name: "traces_kept_by_policy",
+			Tags: map[string]string{"policy": p.Name()},
+		})
+	}
+	w := &Writer{
+		spanWriter:   spanWriter,
+		policies:     opts.Policies,
+		decisionWait: opts.DecisionWait,
+		maxTraces:    opts.MaxTraces,
+		logger:       opts.Logger,
+		metrics:      writerMetrics,
+		keptByPolicy: keptByPolicy,
+		timeNow:      time.Now,
+		traces:       make(map[traceKey]*list.Element),
+		order:        list.New(),
+		decisions:    cache.NewLRU(opts.MaxTraces),
+		stopCh:       make(chan struct{}),
+	}
+	tickInterval := w.decisionWait
+	if tickInterval > maxTickInterval {
+		tickInterval = maxTickInterval
+	}
+	w.stopped.Add(1)
+	go w.decisionLoop(tickInterval)
+	return w
+}
+
+// WriteSpan buffers the span until the sampling decision for its trace is made.
+// Spans of already decided traces are written or dropped immediately according to that decision.
+// The traces are buffered per tenant and written with the tenant of the context.
+func (w *Writer) WriteSpan(ctx context.Context, span *model.Span) error {
+	key := traceKey{tenant: tenancy.GetTenant(ctx), traceID: span.TraceID}
+	w.mux.Lock()
+	if keep, ok := w.decisions.Get(key.String()).(bool); ok {
+		w.mux.Unlock()
+		if !keep {
+			w.metrics.LateSpansDropped.Inc(1)
+			return nil
+		}
+		w.metrics.LateSpansKept.Inc(1)
+		return w.spanWriter.WriteSpan(ctx, span)
+	}
+
+	var evicted []decidedTrace
+	elem, ok := w.traces[key]
+	if !ok {
+		if len(w.traces) >= w.maxTraces {
+			evicted = append(evicted, w.decide(w.order.Front()))
+			w.metrics.TracesEvicted.Inc(1)
+		}
+		elem = w.order.PushBack(&bufferedTrace{key: key, arrival: w.timeNow()})
+		w.traces[key] = elem
+	}
+	bt := elem.Value.(*bufferedTrace)
+	bt.spans = append(bt.spans, span)
+	w.spanCount++
+	w.mux.Unlock()
+
+	w.writeDecided(evicted)
+	return nil
+}
+
+// Close stops the decision loop and makes a decision for all traces still in the buffer.
+func (w *Writer) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stopCh)
+		w.stopped.Wait()
+		w.flush(func(*bufferedTrace) bool { return true })
+	})
+	return nil
+}
+
+func (w *Writer) decisionLoop(tickInterval time.Duration) {
+	defer w.stopped.Done()
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deadline := w.timeNow().Add(-w.decisionWait)
+			w.flush(func(bt *bufferedTrace) bool { return !bt.arrival.After(deadline) })
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// flush decides the oldest traces for as long as isDue returns true for them.
+func (w *Writer) flush(isDue func(*bufferedTrace) bool) {
+	var decided []decidedTrace
+	w.mux.Lock()
+	for elem := w.order.Front(); elem != nil && isDue(elem.Value.(*bufferedTrace)); elem = w.order.Front() {
+		decided = append(decided, w.decide(elem))
+	}
+	w.metrics.TracesBuffered.Update(int64(len(w.traces)))
+	w.metrics.SpansBuffered.Update(int64(w.spanCount))
+	w.mux.Unlock()
+
+	w.writeDecided(decided)
+}
+
+// decide removes the trace from the buffer and evaluates the policies. Must be called with the lock held,
+// so that the decision is recorded before any late span of the same trace can be buffered again.
+func (w *Writer) decide(elem *list.Element) decidedTrace {
+	bt := w.order.Remove(elem).(*bufferedTrace)
+	delete(w.traces, bt.key)
+	w.spanCount -= len(bt.spans)
+
+	keep := w.evaluate(&model.Trace{Spans: bt.spans})
+	w.decisions.Put(bt.key.String(), keep)
+	w.metrics.TracesDecided.Inc(1)
+	if keep {
+		w.metrics.TracesKept.Inc(1)
+	} else {
+		w.metrics.TracesDropped.Inc(1)
+	}
+	return decidedTrace{tenant: bt.key.tenant, spans: bt.spans, keep: keep}
+}
+
+func (w *Writer) evaluate(trace *model.Trace) bool {
+	for _, p := range w.policies {
+		if p.ShouldSample(trace) {
+			w.keptByPolicy[p.Name()].Inc(1)
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Writer) writeDecided(traces []decidedTrace) {
+	for _, t := range traces {
+		if !t.keep {
+			continue
+		}
+		ctx := tenancy.WithTenant(context.Background(), t.tenant)
+		for _, span := range t.spans {
+			if err := w.spanWriter.WriteSpan(ctx, span); err != nil {
+				w.metrics.WriteErrors.Inc(1)
+				w.logger.Error("Failed to save tail-sampled span", zap.Error(err),
+					zap.Stringer("trace-id", span.TraceID), zap.Stringer("span-id", span.SpanID))
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsampling
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+type fakeSpanWriter struct {
+	mux     sync.Mutex
+	spans   []*model.Span
+	tenants []string
+	err     error
+}
+
+func (w *fakeSpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.spans = append(w.spans, span)
+	w.tenants = append(w.tenants, tenancy.GetTenant(ctx))
+	return w.err
+}
+
+func (w *fakeSpanWriter) getSpans() []*model.Span {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.spans
+}
+
+func TestWriterKeepsOnlySampledTraces(t *testing.T) {
+	spanWriter := &fakeSpanWriter{}
+	mf := metricstest.NewFactory(time.Hour)
+	w := NewWriter(spanWriter, Options{
+		DecisionWait:   time.Hour,
+		Policies:       []Policy{NewErrorPolicy()},
+		MetricsFactory: mf,
+	})
+
+	ctx := context.Background()
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(1, 1, 0, "svc", "root", time.Second)))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(1, 2, 1, "svc", "child", time.Second, model.Bool("error", true))))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(2, 3, 0, "svc", "root", time.Second)))
+	assert.Empty(t, spanWriter.getSpans())
+
+	require.NoError(t, w.Close())
+	spans := spanWriter.getSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, model.NewTraceID(0, 1), spans[0].TraceID)
+	assert.Equal(t, model.NewTraceID(0, 1), spans[1].TraceID)
+
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "traces_decided", Value: 2},
+		metricstest.ExpectedMetric{Name: "traces", Tags: map[string]string{"result": "kept"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "traces", Tags: map[string]string{"result": "dropped"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "traces_kept_by_policy", Tags: map[string]string{"policy": "error"}, Value: 1},
+	)
+	mf.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "traces_buffered", Value: 0},
+		metricstest.ExpectedMetric{Name: "spans_buffered", Value: 0},
+	)
+	// closing twice is a no-op
+	require.NoError(t, w.Close())
+}
+
+func TestWriterDecidesAfterDecisionWait(t *testing.T) {
+	spanWriter := &fakeSpanWriter{}
+	w := NewWriter(spanWriter, Options{
+		DecisionWait: 10 * time.Millisecond,
+		Policies:     []Policy{NewLatencyPolicy(time.Second)},
+	})
+	defer w.Close()
+
+	require.NoError(t, w.WriteSpan(context.Background(), makeSpan(1, 1, 0, "svc", "root", 2*time.Second)))
+	assert.Eventually(t, func() bool {
+		return len(spanWriter.getSpans()) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestWriterLateSpans(t *testing.T) {
+	spanWriter := &fakeSpanWriter{}
+	mf := metricstest.NewFactory(time.Hour)
+	w := NewWriter(spanWriter, Options{
+		Policies:       []Policy{NewAttributePolicy("keep", "", "", "")},
+		MetricsFactory: mf,
+	})
+	ctx := context.Background()
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(1, 1, 0, "keep", "root", time.Second)))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(2, 2, 0, "drop", "root", time.Second)))
+	w.flush(func(*bufferedTrace) bool { return true })
+	require.Len(t, spanWriter.getSpans(), 1)
+
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(1, 3, 1, "drop", "late", time.Second)))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(2, 4, 2, "keep", "late", time.Second)))
+	require.NoError(t, w.Close())
+
+	spans := spanWriter.getSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, model.NewSpanID(3), spans[1].SpanID)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "late_spans", Tags: map[string]string{"result": "kept"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "late_spans", Tags: map[string]string{"result": "dropped"}, Value: 1},
+	)
+}
+
+func TestWriterIsolatesTenants(t *testing.T) {
+	spanWriter := &fakeSpanWriter{}
+	w := NewWriter(spanWriter, Options{
+		DecisionWait: time.Hour,
+		Policies:     []Policy{NewErrorPolicy()},
+	})
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	globex := tenancy.WithTenant(context.Background(), "globex")
+	// the same trace ID is used by both tenants, only the trace of acme has an error
+	require.NoError(t, w.WriteSpan(acme, makeSpan(1, 1, 0, "svc", "root", time.Second, model.Bool("error", true))))
+	require.NoError(t, w.WriteSpan(globex, makeSpan(1, 2, 0, "svc", "root", time.Second)))
+	w.flush(func(*bufferedTrace) bool { return true })
+
+	// late spans follow the decision of their own tenant
+	require.NoError(t, w.WriteSpan(acme, makeSpan(1, 3, 1, "svc", "late", time.Second)))
+	require.NoError(t, w.WriteSpan(globex, makeSpan(1, 4, 2, "svc", "late", time.Second)))
+	require.NoError(t, w.Close())
+
+	spans := spanWriter.getSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, model.NewSpanID(1), spans[0].SpanID)
+	assert.Equal(t, model.NewSpanID(3), spans[1].SpanID)
+	assert.Equal(t, []string{"acme", "acme"}, spanWriter.tenants)
+}
+
+func TestWriterEvictsOldestTrace(t *testing.T) {
+	spanWriter := &fakeSpanWriter{}
+	mf := metricstest.NewFactory(time.Hour)
+	w := NewWriter(spanWriter, Options{
+		DecisionWait:   time.Hour,
+		MaxTraces:      2,
+		Policies:       []Policy{NewProbabilisticPolicy(1, "")},
+		MetricsFactory: mf,
+	})
+	defer w.Close()
+
+	ctx := context.Background()
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(1, 1, 0, "svc", "op", time.Second)))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(2, 2, 0, "svc", "op", time.Second)))
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(2, 3, 2, "svc", "op", time.Second)))
+	assert.Empty(t, spanWriter.getSpans())
+
+	require.NoError(t, w.WriteSpan(ctx, makeSpan(3, 4, 0, "svc", "op", time.Second)))
+	spans := spanWriter.getSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, model.NewTraceID(0, 1), spans[0].TraceID)
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "traces_evicted", Value: 1})
+}
+
+func TestWriterWriteErrors(t *testing.T) {
+	spanWriter := &fakeSpanWriter{err: errors.New("storage down")}
+	mf := metricstest.NewFactory(time.Hour)
+	w := NewWriter(spanWriter, Options{
+		Policies:       []Policy{NewProbabilisticPolicy(1, "")},
+		MetricsFactory: mf,
+	})
+	require.NoError(t, w.WriteSpan(context.Background(), makeSpan(1, 1, 0, "svc", "op", time.Second)))
+	require.NoError(t, w.Close())
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "write_errors", Value: 1})
+}
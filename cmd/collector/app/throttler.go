@@ -0,0 +1,226 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/uber/jaeger-lib/utils"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+)
+
+// throttler rejects the batches of spans from the services and the tenants exceeding their rate
+// limits, and, with fair queuing, from the services holding more than their share of the queue.
+// The share of a service is the queue capacity weighted by the service weight over the sum of the
+// weights of the services which have spans in the queue, so a single service may fill the queue
+// as long as no other service needs it.
+type throttler struct {
+	opts    ThrottlingOptions
+	queue   queue.Queue
+	metrics *SpanProcessorMetrics
+
+	lock            sync.Mutex
+	serviceLimiters map[string]utils.RateLimiter
+	tenantLimiters  map[string]utils.RateLimiter
+	// inQueue is the number of spans in the queue per service, and queuedWeight the sum of their weights
+	inQueue      map[string]int
+	queuedWeight float64
+}
+
+func newThrottler(opts ThrottlingOptions, q queue.Queue, m *SpanProcessorMetrics) *throttler {
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultThrottlingBurst
+	}
+	return &throttler{
+		opts:            opts,
+		queue:           q,
+		metrics:         m,
+		serviceLimiters: make(map[string]utils.RateLimiter),
+		tenantLimiters:  make(map[string]utils.RateLimiter),
+		inQueue:         make(map[string]int),
+	}
+}
+
+// Enabled returns true if any of the rate limits or fair queuing is configured.
+func (o ThrottlingOptions) Enabled() bool {
+	return o.ServiceSpansPerSecond > 0 || len(o.ServiceSpansPerSecondOverrides) > 0 ||
+		o.TenantSpansPerSecond > 0 || o.FairQueuing
+}
+
+// Validate returns an error if a rate limit or a weight is invalid.
+func (o ThrottlingOptions) Validate() error {
+	if !(o.ServiceSpansPerSecond >= 0) || !(o.TenantSpansPerSecond >= 0) {
+		return fmt.Errorf("rate limits must be positive or 0 to disable them")
+	}
+	for service, limit := range o.ServiceSpansPerSecondOverrides {
+		if !(limit >= 0) {
+			return fmt.Errorf("invalid rate limit for service %q, it must be a positive number or 0", service)
+		}
+	}
+	for service, weight := range o.FairQueuingWeights {
+		if !(weight > 0) {
+			return fmt.Errorf("invalid fair queuing weight for service %q, it must be a positive number", service)
+		}
+	}
+	return nil
+}
+
+// admit returns false if the spans must be rejected, in which case none of them is enqueued.
+// The spans of a batch usually come from a single service; when they don't, the rate limit
+// credits of the services checked before the rejected one are consumed nonetheless.
+func (t *throttler) admit(spans []*model.Span, tenant string) bool {
+	counts := make(map[string]int)
+	for _, span := range spans {
+		counts[spanServiceName(span)]++
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if tenant != "" && t.opts.TenantSpansPerSecond > 0 {
+		limiter := t.limiter(t.tenantLimiters, tenant, t.opts.TenantSpansPerSecond)
+		if !t.checkCredit(limiter, t.opts.TenantSpansPerSecond, len(spans)) {
+			t.reject(spans, t.metrics.TenantRateLimitedBySvc)
+			return false
+		}
+	}
+	for service, n := range counts {
+		if limit := t.serviceLimit(service); limit > 0 {
+			if !t.checkCredit(t.limiter(t.serviceLimiters, service, limit), limit, n) {
+				t.reject(spans, t.metrics.RateLimitedBySvc)
+				return false
+			}
+		}
+		if !t.withinFairShare(service, n) {
+			t.reject(spans, t.metrics.FairShareExceededBySvc)
+			return false
+		}
+	}
+	return true
+}
+
+func (t *throttler) serviceLimit(service string) float64 {
+	if limit, ok := t.opts.ServiceSpansPerSecondOverrides[service]; ok {
+		return limit
+	}
+	return t.opts.ServiceSpansPerSecond
+}
+
+func (t *throttler) weight(service string) float64 {
+	if weight, ok := t.opts.FairQueuingWeights[service]; ok {
+		return weight
+	}
+	return 1
+}
+
+// limiter returns the limiter of the key, all the keys beyond maxServiceNames sharing the same limiter.
+func (t *throttler) limiter(limiters map[string]utils.RateLimiter, key string, spansPerSecond float64) utils.RateLimiter {
+	limiter, ok := limiters[key]
+	if ok {
+		return limiter
+	}
+	if len(limiters) >= maxServiceNames {
+		key = otherServices
+		if limiter, ok := limiters[key]; ok {
+			return limiter
+		}
+	}
+	limiter = utils.NewRateLimiter(spansPerSecond, t.maxBalance(spansPerSecond))
+	limiters[key] = limiter
+	return limiter
+}
+
+func (t *throttler) maxBalance(spansPerSecond float64) float64 {
+	maxBalance := spansPerSecond * t.opts.Burst.Seconds()
+	if maxBalance < 1 {
+		maxBalance = 1
+	}
+	return maxBalance
+}
+
+// checkCredit takes the credits for the spans, the batches larger than the burst being admitted
+// when the full burst is available.
+func (t *throttler) checkCredit(limiter utils.RateLimiter, spansPerSecond float64, spans int) bool {
+	cost := float64(spans)
+	if maxBalance := t.maxBalance(spansPerSecond); cost > maxBalance {
+		cost = maxBalance
+	}
+	return limiter.CheckCredit(cost)
+}
+
+// withinFairShare returns true if the service may add n spans to the queue. A service
+// without spans in the queue is always admitted, so that it cannot be starved.
+func (t *throttler) withinFairShare(service string, n int) bool {
+	capacity := t.queue.Capacity()
+	if !t.opts.FairQueuing || capacity <= 0 {
+		return true
+	}
+	queued, ok := t.inQueue[service]
+	if !ok {
+		return true
+	}
+	share := float64(capacity) * t.weight(service) / t.queuedWeight
+	return float64(queued+n) <= share
+}
+
+func (t *throttler) reject(spans []*model.Span, counts metricsBySvc) {
+	for _, span := range spans {
+		counts.ReportServiceNameForSpan(span)
+	}
+}
+
+// enqueued records that a span of the service was added to the queue.
+func (t *throttler) enqueued(span *model.Span) {
+	if !t.opts.FairQueuing {
+		return
+	}
+	service := spanServiceName(span)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.inQueue[service] == 0 {
+		t.queuedWeight += t.weight(service)
+	}
+	t.inQueue[service]++
+}
+
+// dequeued records that a span of the service was removed from the queue. The spans replayed
+// by a persistent queue after a restart were never recorded, so they are ignored.
+func (t *throttler) dequeued(span *model.Span) {
+	if !t.opts.FairQueuing {
+		return
+	}
+	service := spanServiceName(span)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	queued, ok := t.inQueue[service]
+	if !ok {
+		return
+	}
+	if queued > 1 {
+		t.inQueue[service] = queued - 1
+		return
+	}
+	delete(t.inQueue, service)
+	t.queuedWeight -= t.weight(service)
+}
+
+func spanServiceName(span *model.Span) string {
+	if span.Process == nil {
+		return ""
+	}
+	return span.Process.ServiceName
+}
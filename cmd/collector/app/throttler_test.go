@@ -0,0 +1,253 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+)
+
+type fixedCapacityQueue struct {
+	queue.Queue
+	capacity int
+}
+
+func (q *fixedCapacityQueue) Capacity() int {
+	return q.capacity
+}
+
+func newTestThrottler(opts ThrottlingOptions, capacity int) (*throttler, *metricstest.Factory) {
+	mb := metricstest.NewFactory(time.Hour)
+	m := NewSpanProcessorMetrics(
+		mb.Namespace(metrics.NSOptions{Name: "service"}),
+		mb.Namespace(metrics.NSOptions{Name: "host"}),
+		nil)
+	return newThrottler(opts, &fixedCapacityQueue{capacity: capacity}, m), mb
+}
+
+func makeServiceSpans(service string, n int) []*model.Span {
+	spans := make([]*model.Span, n)
+	for i := range spans {
+		spans[i] = &model.Span{Process: &model.Process{ServiceName: service}}
+	}
+	return spans
+}
+
+func TestThrottlingOptionsEnabled(t *testing.T) {
+	assert.False(t, ThrottlingOptions{}.Enabled())
+	assert.False(t, ThrottlingOptions{FairQueuingWeights: map[string]float64{"a": 2}}.Enabled())
+	assert.True(t, ThrottlingOptions{ServiceSpansPerSecond: 1}.Enabled())
+	assert.True(t, ThrottlingOptions{ServiceSpansPerSecondOverrides: map[string]float64{"a": 1}}.Enabled())
+	assert.True(t, ThrottlingOptions{TenantSpansPerSecond: 1}.Enabled())
+	assert.True(t, ThrottlingOptions{FairQueuing: true}.Enabled())
+}
+
+func TestThrottlingOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ThrottlingOptions
+		err  string
+	}{
+		{name: "empty", opts: ThrottlingOptions{}},
+		{
+			name: "valid",
+			opts: ThrottlingOptions{
+				ServiceSpansPerSecond:          10,
+				ServiceSpansPerSecondOverrides: map[string]float64{"a": 0, "b": 100},
+				TenantSpansPerSecond:           1000,
+				FairQueuingWeights:             map[string]float64{"a": 0.5},
+			},
+		},
+		{name: "negative service limit", opts: ThrottlingOptions{ServiceSpansPerSecond: -1}, err: "rate limits must be positive"},
+		{name: "NaN tenant limit", opts: ThrottlingOptions{TenantSpansPerSecond: math.NaN()}, err: "rate limits must be positive"},
+		{
+			name: "invalid override",
+			opts: ThrottlingOptions{ServiceSpansPerSecondOverrides: map[string]float64{"a": math.NaN()}},
+			err:  `invalid rate limit for service "a"`,
+		},
+		{
+			name: "zero weight",
+			opts: ThrottlingOptions{FairQueuingWeights: map[string]float64{"a": 0}},
+			err:  `invalid fair queuing weight for service "a"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.opts.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestThrottlerServiceRateLimit(t *testing.T) {
+	th, mb := newTestThrottler(ThrottlingOptions{
+		ServiceSpansPerSecond:          1,
+		ServiceSpansPerSecondOverrides: map[string]float64{"unlimited": 0, "fast": 1000},
+		Burst:                          10 * time.Second,
+	}, 100)
+
+	assert.True(t, th.admit(makeServiceSpans("slow", 10), ""))
+	assert.False(t, th.admit(makeServiceSpans("slow", 1), ""), "the burst of the service is used up")
+	assert.True(t, th.admit(makeServiceSpans("other", 1), ""), "the other services are not limited")
+	assert.True(t, th.admit(makeServiceSpans("fast", 100), ""))
+	for i := 0; i < 10; i++ {
+		assert.True(t, th.admit(makeServiceSpans("unlimited", 100), ""))
+	}
+
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "service.spans.throttled-by-svc|debug=false|reason=service-rate-limit|svc=slow", Value: 1,
+	})
+}
+
+func TestThrottlerLargeBatch(t *testing.T) {
+	th, _ := newTestThrottler(ThrottlingOptions{ServiceSpansPerSecond: 5}, 100)
+
+	assert.True(t, th.admit(makeServiceSpans("a", 50), ""), "a batch larger than the burst is admitted once")
+	assert.False(t, th.admit(makeServiceSpans("a", 1), ""))
+}
+
+func TestThrottlerTenantRateLimit(t *testing.T) {
+	th, mb := newTestThrottler(ThrottlingOptions{TenantSpansPerSecond: 2, Burst: time.Second}, 100)
+
+	assert.True(t, th.admit(makeServiceSpans("a", 2), "acme"))
+	assert.False(t, th.admit(makeServiceSpans("b", 1), "acme"), "the limit applies to all services of the tenant")
+	assert.True(t, th.admit(makeServiceSpans("a", 2), "other"))
+	assert.True(t, th.admit(makeServiceSpans("a", 10), ""), "spans without a tenant are not limited")
+
+	mb.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name: "service.spans.throttled-by-svc|debug=false|reason=tenant-rate-limit|svc=b", Value: 1,
+	})
+}
+
+func TestThrottlerServiceNamesLimit(t *testing.T) {
+	th, _ := newTestThrottler(ThrottlingOptions{ServiceSpansPerSecond: 1}, 100)
+	for i := 0; i < maxServiceNames; i++ {
+		th.limiter(th.serviceLimiters, fmt.Sprintf("service-%d", i), 1)
+	}
+
+	assert.True(t, th.admit(makeServiceSpans("extra-1", 1), ""))
+	assert.False(t, th.admit(makeServiceSpans("extra-2", 1), ""), "the services beyond the limit share a rate limiter")
+	assert.Len(t, th.serviceLimiters, maxServiceNames+1)
+}
+
+func TestThrottlerFairQueuing(t *testing.T) {
+	th, mb := newTestThrottler(ThrottlingOptions{
+		FairQueuing:        true,
+		FairQueuingWeights: map[string]float64{"heavy": 3},
+	}, 8)
+	enqueue := func(service string, n int) bool {
+		spans := makeServiceSpans(service, n)
+		if !th.admit(spans, "") {
+			return false
+		}
+		for _, span := range spans {
+			th.enqueued(span)
+		}
+		return true
+	}
+
+	assert.True(t, enqueue("a", 8), "a single service may fill the queue")
+	assert.True(t, enqueue("b", 1), "a service without queued spans is always admitted")
+	assert.False(t, enqueue("a", 1), "the share of a is now half the queue")
+	assert.True(t, enqueue("b", 3))
+	assert.False(t, enqueue("b", 1))
+
+	for _, span := range makeServiceSpans("a", 8) {
+		th.dequeued(span)
+	}
+	assert.NotContains(t, th.inQueue, "a")
+	assert.Equal(t, 1.0, th.queuedWeight)
+	th.dequeued(makeServiceSpans("unknown", 1)[0])
+	assert.Equal(t, 1.0, th.queuedWeight)
+
+	assert.True(t, enqueue("heavy", 1))
+	assert.True(t, enqueue("heavy", 5), "the share of heavy is 3/4 of the queue")
+	assert.False(t, enqueue("heavy", 1))
+
+	mb.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "service.spans.throttled-by-svc|debug=false|reason=fair-share|svc=a", Value: 1},
+		metricstest.ExpectedMetric{Name: "service.spans.throttled-by-svc|debug=false|reason=fair-share|svc=b", Value: 1},
+		metricstest.ExpectedMetric{Name: "service.spans.throttled-by-svc|debug=false|reason=fair-share|svc=heavy", Value: 1},
+	)
+}
+
+func TestThrottlerFairQueuingUnboundedQueue(t *testing.T) {
+	th, _ := newTestThrottler(ThrottlingOptions{FairQueuing: true}, 0)
+	for _, span := range makeServiceSpans("a", 10) {
+		th.enqueued(span)
+	}
+	assert.True(t, th.admit(makeServiceSpans("a", 10), ""))
+}
+
+func TestSpanProcessorThrottled(t *testing.T) {
+	w := &blockingWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.NumWorkers(1),
+		Options.QueueSize(10),
+		Options.Throttling(ThrottlingOptions{ServiceSpansPerSecond: 2, Burst: time.Second}),
+	).(*spanProcessor)
+	defer func() { assert.NoError(t, p.Close()) }()
+	require.NotNil(t, p.throttler)
+
+	opts := processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat}
+	res, err := p.ProcessSpans(makeServiceSpans("x", 2), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true}, res)
+
+	res, err = p.ProcessSpans(makeServiceSpans("x", 1), opts)
+	assert.Equal(t, processor.ErrBusy, err)
+	assert.Nil(t, res)
+
+	res, err = p.ProcessSpans(makeServiceSpans("y", 1), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, res)
+}
+
+func TestSpanProcessorFairQueuingCounts(t *testing.T) {
+	w := &blockingWriter{}
+	p := NewSpanProcessor(w,
+		nil,
+		Options.NumWorkers(1),
+		Options.QueueSize(10),
+		Options.Throttling(ThrottlingOptions{FairQueuing: true}),
+	).(*spanProcessor)
+	defer func() { assert.NoError(t, p.Close()) }()
+
+	_, err := p.ProcessSpans(makeServiceSpans("x", 5), processor.SpansOptions{SpanFormat: processor.JaegerSpanFormat})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		p.throttler.lock.Lock()
+		defer p.throttler.lock.Unlock()
+		return len(p.throttler.inQueue) == 0 && p.throttler.queuedWeight == 0
+	}, time.Second, time.Millisecond, "the spans are no longer counted once processed")
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	"github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	zipkinProto "github.com/jaegertracing/jaeger/proto-gen/zipkin"
 	"github.com/jaegertracing/jaeger/swagger-gen/models"
 	"github.com/jaegertracing/jaeger/swagger-gen/restapi"
@@ -45,16 +46,19 @@ import (
 type APIHandler struct {
 	zipkinSpansHandler handler.ZipkinSpansHandler
 	zipkinV2Formats    strfmt.Registry
+	tenancyMgr         *tenancy.Manager
 }
 
 // NewAPIHandler returns a new APIHandler
 func NewAPIHandler(
 	zipkinSpansHandler handler.ZipkinSpansHandler,
+	tenancyMgr *tenancy.Manager,
 ) *APIHandler {
 	swaggerSpec, _ := loads.Analyzed(restapi.SwaggerJSON, "")
 	return &APIHandler{
 		zipkinSpansHandler: zipkinSpansHandler,
 		zipkinV2Formats:    operations.NewZipkinAPI(swaggerSpec).Formats(),
+		tenancyMgr:         tenancyMgr,
 	}
 }
 
@@ -65,6 +69,12 @@ func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
 }
 
 func (aH *APIHandler) saveSpans(w http.ResponseWriter, r *http.Request) {
+	tenant, err := aH.tenancyMgr.TenantFromHTTP(r)
+	if err != nil {
+		http.Error(w, err.Error(), tenancy.HTTPStatusCode(err))
+		return
+	}
+
 	bRead := r.Body
 	defer r.Body.Close()
 	if strings.Contains(r.Header.Get("Content-Encoding"), "gzip") {
@@ -106,8 +116,8 @@ func (aH *APIHandler) saveSpans(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := aH.saveThriftSpans(tSpans); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), http.StatusInternalServerError)
+	if err := aH.saveThriftSpans(tSpans, tenant); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), submitErrorStatus(err))
 		return
 	}
 
@@ -115,6 +125,12 @@ func (aH *APIHandler) saveSpans(w http.ResponseWriter, r *http.Request) {
 }
 
 func (aH *APIHandler) saveSpansV2(w http.ResponseWriter, r *http.Request) {
+	tenant, err := aH.tenancyMgr.TenantFromHTTP(r)
+	if err != nil {
+		http.Error(w, err.Error(), tenancy.HTTPStatusCode(err))
+		return
+	}
+
 	bRead := r.Body
 	defer r.Body.Close()
 	if strings.Contains(r.Header.Get("Content-Encoding"), "gzip") {
@@ -156,8 +172,8 @@ func (aH *APIHandler) saveSpansV2(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = aH.saveThriftSpans(tSpans); err != nil {
-		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), http.StatusInternalServerError)
+	if err = aH.saveThriftSpans(tSpans, tenant); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot submit Zipkin batch: %v", err), submitErrorStatus(err))
 		return
 	}
 
@@ -201,12 +217,21 @@ func gunzip(r io.ReadCloser) (*gzip.Reader, error) {
 	return gz, nil
 }
 
-func (aH *APIHandler) saveThriftSpans(tSpans []*zipkincore.Span) error {
+func (aH *APIHandler) saveThriftSpans(tSpans []*zipkincore.Span, tenant string) error {
 	if len(tSpans) > 0 {
-		opts := handler.SubmitBatchOptions{InboundTransport: processor.HTTPTransport}
+		opts := handler.SubmitBatchOptions{InboundTransport: processor.HTTPTransport, Tenant: tenant}
 		if _, err := aH.zipkinSpansHandler.SubmitZipkinBatch(tSpans, opts); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// submitErrorStatus returns the HTTP status for an error of the span processor,
+// signaling the clients to retry later when the collector is busy.
+func submitErrorStatus(err error) int {
+	if err == processor.ErrBusy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
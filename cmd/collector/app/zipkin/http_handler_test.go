@@ -35,7 +35,9 @@ import (
 	zipkinTransport "github.com/uber/jaeger-client-go/transport/zipkin"
 
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/processor"
 	zipkinTrift "github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	zipkinProto "github.com/jaegertracing/jaeger/proto-gen/zipkin"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 )
@@ -63,7 +65,7 @@ func (p *mockZipkinHandler) getSpans() []*zipkincore.Span {
 
 func initializeTestServer(err error) (*httptest.Server, *APIHandler) {
 	r := mux.NewRouter()
-	handler := NewAPIHandler(&mockZipkinHandler{err: err})
+	handler := NewAPIHandler(&mockZipkinHandler{err: err}, &tenancy.Manager{})
 	handler.RegisterRoutes(r)
 	return httptest.NewServer(r), handler
 }
@@ -229,7 +231,7 @@ func TestFormatBadBody(t *testing.T) {
 }
 
 func TestCannotReadBodyFromRequest(t *testing.T) {
-	handler := NewAPIHandler(&mockZipkinHandler{})
+	handler := NewAPIHandler(&mockZipkinHandler{}, &tenancy.Manager{})
 	req, err := http.NewRequest(http.MethodPost, "whatever", &errReader{})
 	assert.NoError(t, err)
 	rw := dummyResponseWriter{}
@@ -281,6 +283,12 @@ func TestSaveSpansV2(t *testing.T) {
 	require.NoError(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, statusCode)
 	assert.EqualValues(t, "Cannot submit Zipkin batch: Bad times ahead\n", resBody)
+
+	handler.zipkinSpansHandler.(*mockZipkinHandler).err = processor.ErrBusy
+	statusCode, resBody, err = postBytes(server.URL+`/api/v2/spans`, []byte(`[{"id":"1111111111111111", "traceId":"1111111111111111"}]`), createHeader("application/json"))
+	require.NoError(t, err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, statusCode)
+	assert.EqualValues(t, "Cannot submit Zipkin batch: server busy\n", resBody)
 }
 
 func TestSaveProtoSpansV2(t *testing.T) {
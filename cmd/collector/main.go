@@ -35,10 +35,13 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/status"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	ss "github.com/jaegertracing/jaeger/plugin/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 )
 
 const serviceName = "jaeger-collector"
@@ -83,6 +86,28 @@ func main() {
 			if err != nil {
 				logger.Fatal("Failed to create span writer", zap.Error(err))
 			}
+			if h := storageFactory.FanoutStatusHandler(); h != nil {
+				logger.Info("Mounting span storage fan-out status handler on admin server", zap.String("route", storage.FanoutStatusRoute))
+				svc.Admin.Handle(storage.FanoutStatusRoute, h)
+			}
+
+			spanMetricsWriter, err := createSpanMetricsWriter(storageFactory)
+			if err != nil {
+				logger.Fatal("Failed to create span metrics writer", zap.Error(err))
+			}
+
+			collectorOpts := new(app.CollectorOptions).InitFromViper(v)
+			if collectorOpts.Tenancy.Enabled {
+				if err := storageFactory.ValidateTenantIsolation(); err != nil {
+					logger.Fatal("Failed to enable multi-tenancy", zap.Error(err))
+				}
+			}
+			var dependencyWriter dependencystore.Writer
+			if collectorOpts.Dependencies.Enabled {
+				if dependencyWriter, err = createDependencyWriter(storageFactory); err != nil {
+					logger.Fatal("Failed to create dependency writer", zap.Error(err))
+				}
+			}
 
 			ssFactory, err := storageFactory.CreateSamplingStoreFactory()
 			if err != nil {
@@ -98,15 +123,17 @@ func main() {
 				logger.Fatal("Failed to create sampling strategy store", zap.Error(err))
 			}
 			c := app.New(&app.CollectorParams{
-				ServiceName:    serviceName,
-				Logger:         logger,
-				MetricsFactory: metricsFactory,
-				SpanWriter:     spanWriter,
-				StrategyStore:  strategyStore,
-				Aggregator:     aggregator,
-				HealthCheck:    svc.HC(),
+				ServiceName:       serviceName,
+				Logger:            logger,
+				MetricsFactory:    metricsFactory,
+				SpanWriter:        spanWriter,
+				StrategyStore:     strategyStore,
+				Aggregator:        aggregator,
+				HealthCheck:       svc.HC(),
+				SpanMetricsWriter: spanMetricsWriter,
+				DependencyWriter:  dependencyWriter,
+				TenancyMgr:        tenancy.NewManager(&collectorOpts.Tenancy),
 			})
-			collectorOpts := new(app.CollectorOptions).InitFromViper(v)
 			if err := c.Start(collectorOpts); err != nil {
 				logger.Fatal("Failed to start collector", zap.Error(err))
 			}
@@ -142,6 +169,7 @@ func main() {
 		app.AddFlags,
 		storageFactory.AddPipelineFlags,
 		strategyStoreFactory.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -149,3 +177,21 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func createSpanMetricsWriter(storageFactory *storage.Factory) (metricsstore.SpanMetricsWriter, error) {
+	smFactory, err := storageFactory.CreateSpanMetricsStoreFactory()
+	if err != nil || smFactory == nil {
+		return nil, err
+	}
+	return smFactory.CreateSpanMetricsWriter()
+}
+
+// createDependencyWriter returns the writer of the dependencies storage, or nil if it does not support writing.
+func createDependencyWriter(storageFactory *storage.Factory) (dependencystore.Writer, error) {
+	dependencyReader, err := storageFactory.CreateDependencyReader()
+	if err != nil {
+		return nil, err
+	}
+	dependencyWriter, _ := dependencyReader.(dependencystore.Writer)
+	return dependencyWriter, nil
+}
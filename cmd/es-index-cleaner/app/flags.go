@@ -16,6 +16,7 @@ package app
 
 import (
 	"flag"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -28,6 +29,7 @@ const (
 	indexDateSeparator = "index-date-separator"
 	username           = "es.username"
 	password           = "es.password"
+	tenants            = "tenants"
 )
 
 // Config holds configuration for index cleaner binary.
@@ -40,6 +42,7 @@ type Config struct {
 	Username                 string
 	Password                 string
 	TLSEnabled               bool
+	Tenants                  []string
 }
 
 // AddFlags adds flags for TLS to the FlagSet.
@@ -51,6 +54,7 @@ func (c *Config) AddFlags(flags *flag.FlagSet) {
 	flags.String(indexDateSeparator, "-", "Index date separator")
 	flags.String(username, "", "The username required by storage")
 	flags.String(password, "", "The password required by storage")
+	flags.String(tenants, "", "Comma separated list of tenants whose indices, prefixed with the tenant, are also removed")
 }
 
 // InitFromViper initializes config from viper.Viper.
@@ -66,4 +70,20 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.IndexDateSeparator = v.GetString(indexDateSeparator)
 	c.Username = v.GetString(username)
 	c.Password = v.GetString(password)
+	c.Tenants = nil
+	for _, tenant := range strings.Split(v.GetString(tenants), ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			c.Tenants = append(c.Tenants, tenant)
+		}
+	}
+}
+
+// IndexPrefixes returns the index prefix followed by the index prefix of each tenant,
+// as the indices of a tenant are named after the tenant, a dash and the index.
+func (c *Config) IndexPrefixes() []string {
+	prefixes := []string{c.IndexPrefix}
+	for _, tenant := range c.Tenants {
+		prefixes = append(prefixes, tenant+"-"+c.IndexPrefix)
+	}
+	return prefixes
 }
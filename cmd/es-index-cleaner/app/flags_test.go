@@ -41,6 +41,7 @@ func TestBindFlags(t *testing.T) {
 		"--index-date-separator=@",
 		"--es.username=admin",
 		"--es.password=admin",
+		"--tenants=acme, globex",
 	})
 	require.NoError(t, err)
 
@@ -52,4 +53,11 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, "@", c.IndexDateSeparator)
 	assert.Equal(t, "admin", c.Username)
 	assert.Equal(t, "admin", c.Password)
+	assert.Equal(t, []string{"acme", "globex"}, c.Tenants)
+	assert.Equal(t, []string{"tenant1-", "acme-tenant1-", "globex-tenant1-"}, c.IndexPrefixes())
+}
+
+func TestIndexPrefixesWithoutIndexPrefix(t *testing.T) {
+	c := &Config{Tenants: []string{"acme"}}
+	assert.Equal(t, []string{"", "acme-"}, c.IndexPrefixes())
 }
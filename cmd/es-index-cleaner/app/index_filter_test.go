@@ -64,6 +64,21 @@ func testIndexFilter(t *testing.T, prefix string) {
 			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
 			Aliases:      map[string]bool{},
 		},
+		{
+			Index:        prefix + "jaeger-sampling-2020-08-05",
+			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
+		{
+			Index:        prefix + "jaeger-spanmetrics-2020-08-05",
+			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
+		{
+			Index:        prefix + "jaeger-locks",
+			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+			Aliases:      map[string]bool{},
+		},
 		{
 			Index:        prefix + "jaeger-span-archive",
 			CreationTime: time.Date(2020, time.August, 0, 15, 0, 0, 0, time.UTC),
@@ -99,6 +114,36 @@ func testIndexFilter(t *testing.T, prefix string) {
 				prefix + "jaeger-service-write": true,
 			},
 		},
+		{
+			Index:        prefix + "jaeger-sampling-000001",
+			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+			Aliases: map[string]bool{
+				prefix + "jaeger-sampling-read": true,
+			},
+		},
+		{
+			Index:        prefix + "jaeger-sampling-000002",
+			CreationTime: time.Date(2020, time.August, 06, 15, 0, 0, 0, time.UTC),
+			Aliases: map[string]bool{
+				prefix + "jaeger-sampling-read":  true,
+				prefix + "jaeger-sampling-write": true,
+			},
+		},
+		{
+			Index:        prefix + "jaeger-spanmetrics-000001",
+			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+			Aliases: map[string]bool{
+				prefix + "jaeger-spanmetrics-read": true,
+			},
+		},
+		{
+			Index:        prefix + "jaeger-spanmetrics-000002",
+			CreationTime: time.Date(2020, time.August, 06, 15, 0, 0, 0, time.UTC),
+			Aliases: map[string]bool{
+				prefix + "jaeger-spanmetrics-read":  true,
+				prefix + "jaeger-spanmetrics-write": true,
+			},
+		},
 		{
 			Index:        prefix + "jaeger-span-archive-000001",
 			CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
@@ -186,6 +231,16 @@ func testIndexFilter(t *testing.T, prefix string) {
 					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
 					Aliases:      map[string]bool{},
 				},
+				{
+					Index:        prefix + "jaeger-sampling-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
+				{
+					Index:        prefix + "jaeger-spanmetrics-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
 			},
 		},
 		{
@@ -228,6 +283,16 @@ func testIndexFilter(t *testing.T, prefix string) {
 					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
 					Aliases:      map[string]bool{},
 				},
+				{
+					Index:        prefix + "jaeger-sampling-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
+				{
+					Index:        prefix + "jaeger-spanmetrics-2020-08-05",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases:      map[string]bool{},
+				},
 			},
 		},
 		{
@@ -273,6 +338,20 @@ func testIndexFilter(t *testing.T, prefix string) {
 						prefix + "jaeger-service-read": true,
 					},
 				},
+				{
+					Index:        prefix + "jaeger-sampling-000001",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases: map[string]bool{
+						prefix + "jaeger-sampling-read": true,
+					},
+				},
+				{
+					Index:        prefix + "jaeger-spanmetrics-000001",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases: map[string]bool{
+						prefix + "jaeger-spanmetrics-read": true,
+					},
+				},
 			},
 		},
 		{
@@ -299,6 +378,20 @@ func testIndexFilter(t *testing.T, prefix string) {
 						prefix + "jaeger-service-read": true,
 					},
 				},
+				{
+					Index:        prefix + "jaeger-sampling-000001",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases: map[string]bool{
+						prefix + "jaeger-sampling-read": true,
+					},
+				},
+				{
+					Index:        prefix + "jaeger-spanmetrics-000001",
+					CreationTime: time.Date(2020, time.August, 05, 15, 0, 0, 0, time.UTC),
+					Aliases: map[string]bool{
+						prefix + "jaeger-spanmetrics-read": true,
+					},
+				},
 			},
 		},
 		{
@@ -75,25 +75,27 @@ func main() {
 				MasterTimeoutSeconds: cfg.MasterNodeTimeoutSeconds,
 			}
 
-			indices, err := i.GetJaegerIndices(cfg.IndexPrefix)
-			if err != nil {
-				return err
-			}
-
 			year, month, day := time.Now().UTC().Date()
 			tomorrowMidnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
 			deleteIndicesBefore := tomorrowMidnight.Add(-time.Hour * 24 * time.Duration(numOfDays))
 			logger.Info("Indices before this date will be deleted", zap.String("date", deleteIndicesBefore.Format(time.RFC3339)))
 
-			filter := &app.IndexFilter{
-				IndexPrefix:          cfg.IndexPrefix,
-				IndexDateSeparator:   cfg.IndexDateSeparator,
-				Archive:              cfg.Archive,
-				Rollover:             cfg.Rollover,
-				DeleteBeforeThisDate: deleteIndicesBefore,
+			var indices []client.Index
+			for _, indexPrefix := range cfg.IndexPrefixes() {
+				prefixIndices, err := i.GetJaegerIndices(indexPrefix)
+				if err != nil {
+					return err
+				}
+				filter := &app.IndexFilter{
+					IndexPrefix:          indexPrefix,
+					IndexDateSeparator:   cfg.IndexDateSeparator,
+					Archive:              cfg.Archive,
+					Rollover:             cfg.Rollover,
+					DeleteBeforeThisDate: deleteIndicesBefore,
+				}
+				logger.Info("Queried indices", zap.Any("indices", prefixIndices))
+				indices = append(indices, filter.Filter(prefixIndices)...)
 			}
-			logger.Info("Queried indices", zap.Any("indices", indices))
-			indices = filter.Filter(indices)
 
 			if len(indices) == 0 {
 				logger.Info("No indices to delete")
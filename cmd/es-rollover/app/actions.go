@@ -69,6 +69,12 @@ func ExecuteAction(opts ActionExecuteOptions, createAction ActionCreatorFunction
 	defer tlsOpts.Close()
 
 	esClient := newESClient(opts.Args[0], &cfg, tlsCfg)
-	action := createAction(esClient, cfg)
-	return action.Do()
+	for _, indexPrefix := range cfg.IndexPrefixes() {
+		prefixCfg := cfg
+		prefixCfg.IndexPrefix = indexPrefix
+		if err := createAction(esClient, prefixCfg).Do(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
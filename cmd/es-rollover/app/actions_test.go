@@ -122,3 +122,36 @@ func TestExecuteAction(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteActionPerTenant(t *testing.T) {
+	v := viper.New()
+	tlsFlags := tlscfg.ClientFlagsConfig{Prefix: "es"}
+	command := cobra.Command{}
+	flags := &flag.FlagSet{}
+	tlsFlags.AddFlags(flags)
+	AddFlags(flags)
+	command.PersistentFlags().AddGoFlagSet(flags)
+	v.BindPFlags(command.PersistentFlags())
+	err := command.ParseFlags([]string{
+		"--index-prefix=prod",
+		"--tenants=acme,globex",
+	})
+	require.NoError(t, err)
+
+	var indexPrefixes []string
+	err = ExecuteAction(ActionExecuteOptions{
+		Args:     []string{"http://localhost:9200"},
+		Viper:    v,
+		Logger:   zap.NewNop(),
+		TLSFlags: tlsFlags,
+	}, func(c client.Client, cfg Config) Action {
+		return &dummyAction{
+			TestFn: func() error {
+				indexPrefixes = append(indexPrefixes, cfg.IndexPrefix)
+				return nil
+			},
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "acme-prod", "globex-prod"}, indexPrefixes)
+}
@@ -16,6 +16,7 @@ package app
 
 import (
 	"flag"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -28,6 +29,7 @@ const (
 	useILM        = "es.use-ilm"
 	ilmPolicyName = "es.ilm-policy-name"
 	timeout       = "timeout"
+	tenants       = "tenants"
 )
 
 // Config holds the global configurations for the es rollover, common to all actions
@@ -40,6 +42,7 @@ type Config struct {
 	ILMPolicyName string
 	UseILM        bool
 	Timeout       int
+	Tenants       []string
 }
 
 // AddFlags adds flags
@@ -51,6 +54,7 @@ func AddFlags(flags *flag.FlagSet) {
 	flags.Bool(useILM, false, "Use ILM to manage jaeger indices")
 	flags.String(ilmPolicyName, "jaeger-ilm-policy", "The name of the ILM policy to use if ILM is active")
 	flags.Int(timeout, 120, "Number of seconds to wait for master node response")
+	flags.String(tenants, "", "Comma separated list of tenants whose indices, prefixed with the tenant, are also handled")
 }
 
 // InitFromViper initializes config from viper.Viper.
@@ -62,4 +66,20 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.ILMPolicyName = v.GetString(ilmPolicyName)
 	c.UseILM = v.GetBool(useILM)
 	c.Timeout = v.GetInt(timeout)
+	c.Tenants = nil
+	for _, tenant := range strings.Split(v.GetString(tenants), ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			c.Tenants = append(c.Tenants, tenant)
+		}
+	}
+}
+
+// IndexPrefixes returns the index prefix followed by the index prefix of each tenant,
+// as the indices of a tenant are named after the tenant, a dash and the index.
+func (c *Config) IndexPrefixes() []string {
+	prefixes := []string{c.IndexPrefix}
+	for _, tenant := range c.Tenants {
+		prefixes = append(prefixes, strings.TrimSuffix(tenant+"-"+c.IndexPrefix, "-"))
+	}
+	return prefixes
 }
@@ -41,6 +41,7 @@ func TestBindFlags(t *testing.T) {
 		"--es.password=qwerty123",
 		"--es.use-ilm=true",
 		"--es.ilm-policy-name=jaeger-ilm",
+		"--tenants=acme, globex",
 	})
 	require.NoError(t, err)
 
@@ -51,4 +52,11 @@ func TestBindFlags(t *testing.T) {
 	assert.Equal(t, "admin", c.Username)
 	assert.Equal(t, "qwerty123", c.Password)
 	assert.Equal(t, "jaeger-ilm", c.ILMPolicyName)
+	assert.Equal(t, []string{"acme", "globex"}, c.Tenants)
+	assert.Equal(t, []string{"tenant1", "acme-tenant1", "globex-tenant1"}, c.IndexPrefixes())
+}
+
+func TestIndexPrefixesWithoutIndexPrefix(t *testing.T) {
+	c := &Config{Tenants: []string{"acme"}}
+	assert.Equal(t, []string{"", "acme"}, c.IndexPrefixes())
 }
@@ -52,6 +52,16 @@ func RolloverIndices(archive bool, prefix string) []IndexOption {
 			Mapping:   "jaeger-service",
 			indexType: "jaeger-service",
 		},
+		{
+			prefix:    prefix,
+			Mapping:   "jaeger-sampling",
+			indexType: "jaeger-sampling",
+		},
+		{
+			prefix:    prefix,
+			Mapping:   "jaeger-spanmetrics",
+			indexType: "jaeger-spanmetrics",
+		},
 	}
 }
 
@@ -52,6 +52,20 @@ func TestRolloverIndices(t *testing.T) {
 					writeAliasName:       "jaeger-service-write",
 					initialRolloverIndex: "jaeger-service-000001",
 				},
+				{
+					mapping:              "jaeger-sampling",
+					templateName:         "jaeger-sampling",
+					readAliasName:        "jaeger-sampling-read",
+					writeAliasName:       "jaeger-sampling-write",
+					initialRolloverIndex: "jaeger-sampling-000001",
+				},
+				{
+					mapping:              "jaeger-spanmetrics",
+					templateName:         "jaeger-spanmetrics",
+					readAliasName:        "jaeger-spanmetrics-read",
+					writeAliasName:       "jaeger-spanmetrics-write",
+					initialRolloverIndex: "jaeger-spanmetrics-000001",
+				},
 			},
 		},
 		{
@@ -99,6 +113,20 @@ func TestRolloverIndices(t *testing.T) {
 					writeAliasName:       "mytenant-jaeger-service-write",
 					initialRolloverIndex: "mytenant-jaeger-service-000001",
 				},
+				{
+					mapping:              "jaeger-sampling",
+					templateName:         "mytenant-jaeger-sampling",
+					readAliasName:        "mytenant-jaeger-sampling-read",
+					writeAliasName:       "mytenant-jaeger-sampling-write",
+					initialRolloverIndex: "mytenant-jaeger-sampling-000001",
+				},
+				{
+					mapping:              "jaeger-spanmetrics",
+					templateName:         "mytenant-jaeger-spanmetrics",
+					readAliasName:        "mytenant-jaeger-spanmetrics-read",
+					writeAliasName:       "mytenant-jaeger-spanmetrics-write",
+					initialRolloverIndex: "mytenant-jaeger-spanmetrics-000001",
+				},
 			},
 		},
 	}
@@ -106,6 +134,7 @@ func TestRolloverIndices(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			result := RolloverIndices(test.archive, test.prefix)
+			assert.Len(t, result, len(test.expected))
 			for i, r := range result {
 				assert.Equal(t, test.expected[i].templateName, r.TemplateName())
 				assert.Equal(t, test.expected[i].mapping, r.Mapping)
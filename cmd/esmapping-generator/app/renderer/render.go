@@ -23,8 +23,10 @@ import (
 )
 
 var supportedMappings = map[string]struct{}{
-	"jaeger-span":    {},
-	"jaeger-service": {},
+	"jaeger-span":        {},
+	"jaeger-service":     {},
+	"jaeger-sampling":    {},
+	"jaeger-spanmetrics": {},
 }
 
 // GetMappingAsString returns rendered index templates as string
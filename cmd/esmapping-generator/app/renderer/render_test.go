@@ -33,6 +33,8 @@ func TestIsValidOption(t *testing.T) {
 		expectedValue bool
 	}{{name: "span mapping", arg: "jaeger-span", expectedValue: true},
 		{name: "service mapping", arg: "jaeger-service", expectedValue: true},
+		{name: "sampling mapping", arg: "jaeger-sampling", expectedValue: true},
+		{name: "span metrics mapping", arg: "jaeger-spanmetrics", expectedValue: true},
 		{name: "Invalid mapping", arg: "dependency-service", expectedValue: false},
 	}
 	for _, test := range tests {
@@ -15,44 +15,45 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/Shopify/sarama"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/replay"
 	kafkaConsumer "github.com/jaegertracing/jaeger/pkg/kafka/consumer"
+	kafkaProducer "github.com/jaegertracing/jaeger/pkg/kafka/producer"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 // CreateConsumer creates a new span consumer for the ingester
 func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWriter spanstore.Writer, options app.Options) (*consumer.Consumer, error) {
-	var unmarshaller kafka.Unmarshaller
-	switch options.Encoding {
-	case kafka.EncodingJSON:
-		unmarshaller = kafka.NewJSONUnmarshaller()
-	case kafka.EncodingProto:
-		unmarshaller = kafka.NewProtobufUnmarshaller()
-	case kafka.EncodingZipkinThrift:
-		unmarshaller = kafka.NewZipkinThriftUnmarshaller()
-	default:
-		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
-			options.Encoding, strings.Join(kafka.AllEncodings, "\", \""))
+	unmarshaller, err := newUnmarshaller(options.Encoding)
+	if err != nil {
+		return nil, err
 	}
 
 	spParams := processor.SpanProcessorParams{
 		Writer:       spanWriter,
 		Unmarshaller: unmarshaller,
+		TenancyMgr:   tenancy.NewManager(&options.Tenancy),
 	}
 	spanProcessor := processor.NewSpanProcessor(spParams)
 
 	consumerConfig := kafkaConsumer.Configuration{
 		Brokers:              options.Brokers,
 		Topic:                options.Topic,
+		Topics:               options.Topics,
+		TopicRegex:           options.TopicRegex,
 		GroupID:              options.GroupID,
 		ClientID:             options.ClientID,
 		ProtocolVersion:      options.ProtocolVersion,
@@ -64,12 +65,27 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 	}
 
 	factoryParams := consumer.ProcessorFactoryParams{
-		Topic:          options.Topic,
 		Parallelism:    options.Parallelism,
 		SaramaConsumer: saramaConsumer,
 		BaseProcessor:  spanProcessor,
 		Logger:         logger,
 		Factory:        metricsFactory,
+		RetryOptions:   retryOptions(options),
+	}
+	if options.DLQTopic != "" {
+		producerConfig := kafkaProducer.Configuration{
+			Brokers:              options.Brokers,
+			RequiredAcks:         sarama.WaitForAll,
+			ProtocolVersion:      options.ProtocolVersion,
+			AuthenticationConfig: options.AuthenticationConfig,
+		}
+		deadLetterProducer, err := producerConfig.NewSyncProducer(logger)
+		if err != nil {
+			saramaConsumer.Close()
+			return nil, fmt.Errorf("cannot create the dead-letter queue producer: %w", err)
+		}
+		factoryParams.DeadLetterProducer = deadLetterProducer
+		factoryParams.DeadLetterTopic = options.DLQTopic
 	}
 	processorFactory, err := consumer.NewProcessorFactory(factoryParams)
 	if err != nil {
@@ -85,3 +101,78 @@ func CreateConsumer(logger *zap.Logger, metricsFactory metrics.Factory, spanWrit
 	}
 	return consumer.New(consumerParams)
 }
+
+// CreateReplayer creates a new replayer writing the spans published to the topic within the time range
+// of the replay options
+func CreateReplayer(logger *zap.Logger, metricsFactory metrics.Factory, spanWriter spanstore.Writer, options app.Options, replayOptions replay.Options) (*replay.Replayer, error) {
+	start, end, err := replayOptions.TimeRange()
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Topics) > 1 || options.TopicRegex != "" {
+		return nil, errors.New("the replay supports a single topic, it cannot be used with a list of topics or a topic regex")
+	}
+	unmarshaller, err := newUnmarshaller(options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	spanProcessor := processor.NewSpanProcessor(processor.SpanProcessorParams{
+		Writer:       spanWriter,
+		Unmarshaller: unmarshaller,
+		TenancyMgr:   tenancy.NewManager(&options.Tenancy),
+	})
+	// The replay stops on the messages that cannot be written to storage, so that it can be resumed
+	// once the issue is fixed.
+	retryProcessor := decorator.NewRetryingProcessor(metricsFactory, spanProcessor,
+		append(retryOptions(options), decorator.PropagateError(true))...)
+
+	client, err := options.NewClient(logger)
+	if err != nil {
+		return nil, err
+	}
+	saramaConsumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	offsetManager, err := sarama.NewOffsetManagerFromClient(replayOptions.GroupID, client)
+	if err != nil {
+		saramaConsumer.Close()
+		client.Close()
+		return nil, err
+	}
+	return replay.New(replay.Params{
+		Topic:          options.Topic,
+		Start:          start,
+		End:            end,
+		Parallelism:    options.Parallelism,
+		Processor:      retryProcessor,
+		Client:         client,
+		Consumer:       saramaConsumer,
+		OffsetManager:  offsetManager,
+		MetricsFactory: metricsFactory,
+		Logger:         logger,
+	}), nil
+}
+
+func newUnmarshaller(encoding string) (kafka.Unmarshaller, error) {
+	switch encoding {
+	case kafka.EncodingJSON:
+		return kafka.NewJSONUnmarshaller(), nil
+	case kafka.EncodingProto:
+		return kafka.NewProtobufUnmarshaller(), nil
+	case kafka.EncodingZipkinThrift:
+		return kafka.NewZipkinThriftUnmarshaller(), nil
+	default:
+		return nil, fmt.Errorf(`encoding '%s' not recognised, use one of ("%s")`,
+			encoding, strings.Join(kafka.AllEncodings, "\", \""))
+	}
+}
+
+func retryOptions(options app.Options) []decorator.RetryOption {
+	return []decorator.RetryOption{
+		decorator.MaxAttempts(options.RetryMaxAttempts),
+		decorator.MinBackoffInterval(options.RetryMinBackoff),
+		decorator.MaxBackoffInterval(options.RetryMaxBackoff),
+	}
+}
@@ -46,7 +46,7 @@ type Consumer struct {
 
 	deadlockDetector deadlockDetector
 
-	partitionIDToState  map[int32]*consumerState
+	partitionIDToState  map[topicPartition]*consumerState
 	partitionMapLock    sync.Mutex
 	partitionsHeld      int64
 	partitionsHeldGauge metrics.Gauge
@@ -54,6 +54,12 @@ type Consumer struct {
 	doneWg sync.WaitGroup
 }
 
+// topicPartition identifies a partition of one of the topics consumed from.
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
 type consumerState struct {
 	partitionConsumer sc.PartitionConsumer
 }
@@ -67,7 +73,7 @@ func New(params Params) (*Consumer, error) {
 		internalConsumer:    params.InternalConsumer,
 		processorFactory:    params.ProcessorFactory,
 		deadlockDetector:    deadlockDetector,
-		partitionIDToState:  make(map[int32]*consumerState),
+		partitionIDToState:  make(map[topicPartition]*consumerState),
 		partitionsHeldGauge: partitionsHeldGauge(params.MetricsFactory),
 	}, nil
 }
@@ -79,13 +85,13 @@ func (c *Consumer) Start() {
 		c.logger.Info("Starting main loop")
 		for pc := range c.internalConsumer.Partitions() {
 			c.partitionMapLock.Lock()
-			c.partitionIDToState[pc.Partition()] = &consumerState{partitionConsumer: pc}
+			c.partitionIDToState[topicPartition{topic: pc.Topic(), partition: pc.Partition()}] = &consumerState{partitionConsumer: pc}
 			c.partitionMapLock.Unlock()
-			c.partitionMetrics(pc.Partition()).startCounter.Inc(1)
+			c.partitionMetrics(pc.Topic(), pc.Partition()).startCounter.Inc(1)
 
 			c.doneWg.Add(2)
 			go c.handleMessages(pc)
-			go c.handleErrors(pc.Partition(), pc.Errors())
+			go c.handleErrors(pc.Topic(), pc.Partition(), pc.Errors())
 		}
 	}()
 }
@@ -102,12 +108,15 @@ func (c *Consumer) Close() error {
 	c.logger.Debug("Waiting for messages and errors to be handled")
 	c.doneWg.Wait()
 
+	if closeErr := c.processorFactory.close(); err == nil {
+		err = closeErr
+	}
 	return err
 }
 
 // handleMessages handles incoming Kafka messages on a channel
 func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
-	c.logger.Info("Starting message handler", zap.Int32("partition", pc.Partition()))
+	c.logger.Info("Starting message handler", zap.String("topic", pc.Topic()), zap.Int32("partition", pc.Partition()))
 	c.partitionMapLock.Lock()
 	c.partitionsHeld++
 	c.partitionsHeldGauge.Update(c.partitionsHeld)
@@ -121,7 +130,7 @@ func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 		c.doneWg.Done()
 	}()
 
-	msgMetrics := c.newMsgMetrics(pc.Partition())
+	msgMetrics := c.newMsgMetrics(pc.Topic(), pc.Partition())
 
 	var msgProcessor processor.SpanProcessor
 
@@ -142,7 +151,7 @@ func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 			deadlockDetector.incrementMsgCount()
 
 			if msgProcessor == nil {
-				msgProcessor = c.processorFactory.new(pc.Partition(), msg.Offset-1)
+				msgProcessor = c.processorFactory.new(pc.Topic(), pc.Partition(), msg.Offset-1)
 				defer msgProcessor.Close()
 			}
 
@@ -158,16 +167,16 @@ func (c *Consumer) handleMessages(pc sc.PartitionConsumer) {
 func (c *Consumer) closePartition(partitionConsumer sc.PartitionConsumer) {
 	c.logger.Info("Closing partition consumer", zap.Int32("partition", partitionConsumer.Partition()))
 	partitionConsumer.Close() // blocks until messages channel is drained
-	c.partitionMetrics(partitionConsumer.Partition()).closeCounter.Inc(1)
+	c.partitionMetrics(partitionConsumer.Topic(), partitionConsumer.Partition()).closeCounter.Inc(1)
 	c.logger.Info("Closed partition consumer", zap.Int32("partition", partitionConsumer.Partition()))
 }
 
 // handleErrors handles incoming Kafka consumer errors on a channel
-func (c *Consumer) handleErrors(partition int32, errChan <-chan *sarama.ConsumerError) {
-	c.logger.Info("Starting error handler", zap.Int32("partition", partition))
+func (c *Consumer) handleErrors(topic string, partition int32, errChan <-chan *sarama.ConsumerError) {
+	c.logger.Info("Starting error handler", zap.String("topic", topic), zap.Int32("partition", partition))
 	defer c.doneWg.Done()
 
-	errMetrics := c.newErrMetrics(partition)
+	errMetrics := c.newErrMetrics(topic, partition)
 	for err := range errChan {
 		errMetrics.errCounter.Inc(1)
 		c.logger.Error("Error consuming from Kafka", zap.Error(err))
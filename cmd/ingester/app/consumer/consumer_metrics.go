@@ -37,12 +37,12 @@ type partitionMetrics struct {
 	closeCounter metrics.Counter
 }
 
-func (c *Consumer) namespace(partition int32) metrics.Factory {
-	return c.metricsFactory.Namespace(metrics.NSOptions{Name: consumerNamespace, Tags: map[string]string{"partition": strconv.Itoa(int(partition))}})
+func (c *Consumer) namespace(topic string, partition int32) metrics.Factory {
+	return c.metricsFactory.Namespace(metrics.NSOptions{Name: consumerNamespace, Tags: map[string]string{"topic": topic, "partition": strconv.Itoa(int(partition))}})
 }
 
-func (c *Consumer) newMsgMetrics(partition int32) msgMetrics {
-	f := c.namespace(partition)
+func (c *Consumer) newMsgMetrics(topic string, partition int32) msgMetrics {
+	f := c.namespace(topic, partition)
 	return msgMetrics{
 		counter:     f.Counter(metrics.Options{Name: "messages", Tags: nil}),
 		offsetGauge: f.Gauge(metrics.Options{Name: "current-offset", Tags: nil}),
@@ -50,12 +50,12 @@ func (c *Consumer) newMsgMetrics(partition int32) msgMetrics {
 	}
 }
 
-func (c *Consumer) newErrMetrics(partition int32) errMetrics {
-	return errMetrics{errCounter: c.namespace(partition).Counter(metrics.Options{Name: "errors", Tags: nil})}
+func (c *Consumer) newErrMetrics(topic string, partition int32) errMetrics {
+	return errMetrics{errCounter: c.namespace(topic, partition).Counter(metrics.Options{Name: "errors", Tags: nil})}
 }
 
-func (c *Consumer) partitionMetrics(partition int32) partitionMetrics {
-	f := c.namespace(partition)
+func (c *Consumer) partitionMetrics(topic string, partition int32) partitionMetrics {
+	f := c.namespace(topic, partition)
 	return partitionMetrics{
 		closeCounter: f.Counter(metrics.Options{Name: "partition-close", Tags: nil}),
 		startCounter: f.Counter(metrics.Options{Name: "partition-start", Tags: nil})}
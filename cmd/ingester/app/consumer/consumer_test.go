@@ -87,7 +87,6 @@ func newSaramaClusterConsumer(saramaPartitionConsumer sarama.PartitionConsumer,
 func newConsumer(
 	t *testing.T,
 	metricsFactory metrics.Factory,
-	topic string,
 	processor processor.SpanProcessor,
 	consumer consumer.Consumer) *Consumer {
 
@@ -97,7 +96,6 @@ func newConsumer(
 		Logger:           logger,
 		InternalConsumer: consumer,
 		ProcessorFactory: ProcessorFactory{
-			topic:          topic,
 			consumer:       consumer,
 			metricsFactory: metricsFactory,
 			logger:         logger,
@@ -139,10 +137,10 @@ func TestSaramaConsumerWrapper_start_Messages(t *testing.T) {
 	saramaPartitionConsumer, e := saramaConsumer.ConsumePartition(topic, partition, msgOffset)
 	require.NoError(t, e)
 
-	undertest := newConsumer(t, localFactory, topic, mp, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
+	undertest := newConsumer(t, localFactory, mp, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
 
-	undertest.partitionIDToState = map[int32]*consumerState{
-		partition: {
+	undertest.partitionIDToState = map[topicPartition]*consumerState{
+		{topic: topic, partition: partition}: {
 			partitionConsumer: &partitionConsumerWrapper{
 				topic:             topic,
 				partition:         partition,
@@ -164,7 +162,7 @@ func TestSaramaConsumerWrapper_start_Messages(t *testing.T) {
 	mp.AssertExpectations(t)
 	// Ensure that the partition consumer was updated in the map
 	assert.Equal(t, saramaPartitionConsumer.HighWaterMarkOffset(),
-		undertest.partitionIDToState[partition].partitionConsumer.HighWaterMarkOffset())
+		undertest.partitionIDToState[topicPartition{topic: topic, partition: partition}].partitionConsumer.HighWaterMarkOffset())
 	undertest.Close()
 
 	localFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
@@ -172,7 +170,7 @@ func TestSaramaConsumerWrapper_start_Messages(t *testing.T) {
 		Value: 0,
 	})
 
-	partitionTag := map[string]string{"partition": fmt.Sprint(partition)}
+	partitionTag := map[string]string{"topic": topic, "partition": fmt.Sprint(partition)}
 	localFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
 		Name:  "sarama-consumer.messages",
 		Tags:  partitionTag,
@@ -205,7 +203,7 @@ func TestSaramaConsumerWrapper_start_Errors(t *testing.T) {
 	saramaPartitionConsumer, e := saramaConsumer.ConsumePartition(topic, partition, msgOffset)
 	require.NoError(t, e)
 
-	undertest := newConsumer(t, localFactory, topic, &pmocks.SpanProcessor{}, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
+	undertest := newConsumer(t, localFactory, &pmocks.SpanProcessor{}, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
 
 	undertest.Start()
 	mc.YieldError(errors.New("Daisy, Daisy"))
@@ -218,7 +216,7 @@ func TestSaramaConsumerWrapper_start_Errors(t *testing.T) {
 			continue
 		}
 
-		partitionTag := map[string]string{"partition": fmt.Sprint(partition)}
+		partitionTag := map[string]string{"topic": topic, "partition": fmt.Sprint(partition)}
 		localFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
 			Name:  "sarama-consumer.errors",
 			Tags:  partitionTag,
@@ -241,7 +239,7 @@ func TestHandleClosePartition(t *testing.T) {
 	saramaPartitionConsumer, e := saramaConsumer.ConsumePartition(topic, partition, msgOffset)
 	require.NoError(t, e)
 
-	undertest := newConsumer(t, metricsFactory, topic, mp, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
+	undertest := newConsumer(t, metricsFactory, mp, newSaramaClusterConsumer(saramaPartitionConsumer, mc))
 	undertest.deadlockDetector = newDeadlockDetector(metricsFactory, undertest.logger, 200*time.Millisecond)
 	undertest.Start()
 	defer undertest.Close()
@@ -250,7 +248,7 @@ func TestHandleClosePartition(t *testing.T) {
 		undertest.deadlockDetector.allPartitionsDeadlockDetector.incrementMsgCount() // Don't trigger panic on all partitions detector
 		time.Sleep(100 * time.Millisecond)
 		c, _ := metricsFactory.Snapshot()
-		if c["sarama-consumer.partition-close|partition=316"] == 1 {
+		if c["sarama-consumer.partition-close|partition=316|topic="+topic] == 1 {
 			return
 		}
 	}
@@ -25,6 +25,7 @@ type Message interface {
 	Topic() string
 	Partition() int32
 	Offset() int64
+	Headers() []*sarama.RecordHeader
 }
 
 type saramaMessageWrapper struct {
@@ -50,3 +51,7 @@ func (m saramaMessageWrapper) Partition() int32 {
 func (m saramaMessageWrapper) Offset() int64 {
 	return m.ConsumerMessage.Offset
 }
+
+func (m saramaMessageWrapper) Headers() []*sarama.RecordHeader {
+	return m.ConsumerMessage.Headers
+}
@@ -14,13 +14,32 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	sarama "github.com/Shopify/sarama"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Message is an autogenerated mock type for the Message type
 type Message struct {
 	mock.Mock
 }
 
+// Headers provides a mock function with given fields:
+func (_m *Message) Headers() []*sarama.RecordHeader {
+	ret := _m.Called()
+
+	var r0 []*sarama.RecordHeader
+	if rf, ok := ret.Get(0).(func() []*sarama.RecordHeader); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*sarama.RecordHeader)
+		}
+	}
+
+	return r0
+}
+
 // Key provides a mock function with given fields:
 func (_m *Message) Key() []byte {
 	ret := _m.Called()
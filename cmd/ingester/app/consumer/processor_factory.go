@@ -17,10 +17,12 @@ package consumer
 import (
 	"io"
 
+	"github.com/Shopify/sarama"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/offset"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/dlq"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
 	"github.com/jaegertracing/jaeger/pkg/kafka/consumer"
@@ -29,48 +31,70 @@ import (
 // ProcessorFactoryParams are the parameters of a ProcessorFactory
 type ProcessorFactoryParams struct {
 	Parallelism    int
-	Topic          string
 	BaseProcessor  processor.SpanProcessor
 	SaramaConsumer consumer.Consumer
 	Factory        metrics.Factory
 	Logger         *zap.Logger
 	RetryOptions   []decorator.RetryOption
+	// DeadLetterProducer publishes the messages that cannot be processed to the DeadLetterTopic.
+	// It is closed with the factory. If nil, such messages are dropped.
+	DeadLetterProducer sarama.SyncProducer
+	DeadLetterTopic    string
 }
 
 // ProcessorFactory is a factory for creating startedProcessors
 type ProcessorFactory struct {
-	topic          string
 	consumer       consumer.Consumer
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 	baseProcessor  processor.SpanProcessor
 	parallelism    int
 	retryOptions   []decorator.RetryOption
+
+	deadLetterProducer sarama.SyncProducer
+	deadLetterTopic    string
 }
 
 // NewProcessorFactory constructs a new ProcessorFactory
 func NewProcessorFactory(params ProcessorFactoryParams) (*ProcessorFactory, error) {
+	retryOptions := params.RetryOptions
+	if params.DeadLetterProducer != nil {
+		// The dead-letter processor needs the errors of the messages that cannot be processed.
+		retryOptions = append(append([]decorator.RetryOption{}, retryOptions...), decorator.PropagateError(true))
+	}
 	return &ProcessorFactory{
-		topic:          params.Topic,
 		consumer:       params.SaramaConsumer,
 		metricsFactory: params.Factory,
 		logger:         params.Logger,
 		baseProcessor:  params.BaseProcessor,
 		parallelism:    params.Parallelism,
-		retryOptions:   params.RetryOptions,
+		retryOptions:   retryOptions,
+
+		deadLetterProducer: params.DeadLetterProducer,
+		deadLetterTopic:    params.DeadLetterTopic,
 	}, nil
 }
 
-func (c *ProcessorFactory) new(partition int32, minOffset int64) processor.SpanProcessor {
-	c.logger.Info("Creating new processors", zap.Int32("partition", partition))
+func (c *ProcessorFactory) new(topic string, partition int32, minOffset int64) processor.SpanProcessor {
+	c.logger.Info("Creating new processors", zap.String("topic", topic), zap.Int32("partition", partition))
 
 	markOffset := func(offset int64) {
-		c.consumer.MarkPartitionOffset(c.topic, partition, offset, "")
+		c.consumer.MarkPartitionOffset(topic, partition, offset, "")
 	}
 
-	om := offset.NewManager(minOffset, markOffset, partition, c.metricsFactory)
+	om := offset.NewManager(minOffset, markOffset, partition,
+		c.metricsFactory.Namespace(metrics.NSOptions{Tags: map[string]string{"topic": topic}}))
 
 	retryProcessor := decorator.NewRetryingProcessor(c.metricsFactory, c.baseProcessor, c.retryOptions...)
+	if c.deadLetterProducer != nil {
+		retryProcessor = dlq.NewProcessor(dlq.ProcessorParams{
+			Processor:      retryProcessor,
+			Producer:       c.deadLetterProducer,
+			Topic:          c.deadLetterTopic,
+			MetricsFactory: c.metricsFactory,
+			Logger:         c.logger,
+		})
+	}
 	cp := NewCommittingProcessor(retryProcessor, om)
 	spanProcessor := processor.NewDecoratedProcessor(c.metricsFactory, cp)
 	pp := processor.NewParallelProcessor(spanProcessor, c.parallelism, c.logger)
@@ -78,6 +102,14 @@ func (c *ProcessorFactory) new(partition int32, minOffset int64) processor.SpanP
 	return newStartedProcessor(pp, om)
 }
 
+// close releases the resources shared by the processors of all the partitions.
+func (c *ProcessorFactory) close() error {
+	if c.deadLetterProducer != nil {
+		return c.deadLetterProducer.Close()
+	}
+	return nil
+}
+
 type service interface {
 	Start()
 	io.Closer
@@ -15,15 +15,22 @@
 package consumer
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/Shopify/sarama"
+	saramaMocks "github.com/Shopify/sarama/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
 	"go.uber.org/zap"
 
 	kmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/dlq"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
 )
 
@@ -47,7 +54,6 @@ func Test_new(t *testing.T) {
 	sp.On("Process", mock.Anything).Return(nil)
 
 	pf := ProcessorFactory{
-		topic:          topic,
 		consumer:       mockConsumer,
 		metricsFactory: metrics.NullFactory,
 		logger:         zap.NewNop(),
@@ -55,7 +61,7 @@ func Test_new(t *testing.T) {
 		parallelism:    1,
 	}
 
-	processor := pf.new(partition, offset)
+	processor := pf.new(topic, partition, offset)
 	msg := &kmocks.Message{}
 	msg.On("Offset").Return(offset + 1)
 	processor.Process(msg)
@@ -66,6 +72,81 @@ func Test_new(t *testing.T) {
 	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
 }
 
+func Test_newWithDeadLetterQueue(t *testing.T) {
+	mockConsumer := &kmocks.Consumer{}
+	mockConsumer.On("MarkPartitionOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	topic := "coelacanth"
+	partition := int32(21)
+	offset := int64(555)
+
+	sp := &mocks.SpanProcessor{}
+	sp.On("Process", mock.Anything).Return(errors.New("storage down"))
+
+	producer := &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)}
+	producer.SyncProducer.(*saramaMocks.SyncProducer).ExpectSendMessageAndSucceed()
+
+	metricsFactory := metricstest.NewFactory(0)
+	pf, err := NewProcessorFactory(ProcessorFactoryParams{
+		SaramaConsumer:     mockConsumer,
+		Factory:            metricsFactory,
+		Logger:             zap.NewNop(),
+		BaseProcessor:      sp,
+		Parallelism:        1,
+		RetryOptions:       []decorator.RetryOption{decorator.MaxAttempts(0)},
+		DeadLetterProducer: producer,
+		DeadLetterTopic:    "coelacanth-dlq",
+	})
+	require.NoError(t, err)
+
+	processor := pf.new(topic, partition, offset)
+	msg := &kmocks.Message{}
+	msg.On("Key").Return([]byte("key"))
+	msg.On("Value").Return([]byte("value"))
+	msg.On("Topic").Return(topic)
+	msg.On("Partition").Return(partition)
+	msg.On("Offset").Return(offset + 1)
+	msg.On("Headers").Return(nil)
+	processor.Process(msg)
+
+	// The offset of the dead-lettered message is committed.
+	time.Sleep(150 * time.Millisecond)
+	mockConsumer.AssertCalled(t, "MarkPartitionOffset", topic, partition, offset+1, "")
+	processor.Close()
+
+	require.NotNil(t, producer.published)
+	assert.Equal(t, "coelacanth-dlq", producer.published.Topic)
+	assert.Equal(t, sarama.ByteEncoder("value"), producer.published.Value)
+	assert.Contains(t, producer.published.Headers, sarama.RecordHeader{
+		Key:   []byte(dlq.HeaderAttempts),
+		Value: []byte("1"),
+	})
+	metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+		Name:  "dlq.messages",
+		Tags:  map[string]string{"reason": "retries-exhausted"},
+		Value: 1,
+	})
+
+	assert.NoError(t, pf.close())
+	assert.True(t, producer.closed)
+}
+
+type recordingProducer struct {
+	sarama.SyncProducer
+	published *sarama.ProducerMessage
+	closed    bool
+}
+
+func (p *recordingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.published = msg
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func (p *recordingProducer) Close() error {
+	p.closed = true
+	return p.SyncProducer.Close()
+}
+
 type fakeService struct {
 	startCalled bool
 	closeCalled bool
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	kafkaProducer "github.com/jaegertracing/jaeger/pkg/kafka/producer"
+)
+
+// ReplayCommand creates the command replaying the dead-letter topic of the ingester. It accepts
+// the kafka flags of the ingester, so that it can be run with the same configuration.
+func ReplayCommand() *cobra.Command {
+	v := viper.New()
+	command := &cobra.Command{
+		Use:   "replay-dlq",
+		Short: "Replays the messages of the dead-letter topic.",
+		Long: `Replays the messages that the ingester failed to process, once the issue is fixed, by publishing them ` +
+			`from the dead-letter topic back to the topic they were consumed from. Each run replays the messages ` +
+			`published to the dead-letter topic since the previous one.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger, err := zap.NewProduction()
+			if err != nil {
+				return err
+			}
+			options := app.Options{}
+			options.InitFromViper(v)
+			replayOptions := ReplayOptions{}
+			replayOptions.InitFromViper(v)
+			return replay(logger, options, replayOptions)
+		},
+	}
+	config.AddFlags(v, command, app.AddFlags, AddReplayFlags)
+	return command
+}
+
+func replay(logger *zap.Logger, options app.Options, replayOptions ReplayOptions) error {
+	if options.DLQTopic == "" {
+		return errors.New("the dead-letter topic is required, use --" + app.ConfigPrefix + app.SuffixDLQTopic)
+	}
+	client, err := options.NewClient(logger)
+	if err != nil {
+		return fmt.Errorf("cannot connect to kafka: %w", err)
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+	offsetManager, err := sarama.NewOffsetManagerFromClient(replayOptions.GroupID, client)
+	if err != nil {
+		return err
+	}
+	// Closing the offset manager commits the replayed offsets.
+	defer offsetManager.Close()
+
+	producerConfig := kafkaProducer.Configuration{
+		Brokers:              options.Brokers,
+		RequiredAcks:         sarama.WaitForAll,
+		ProtocolVersion:      options.ProtocolVersion,
+		AuthenticationConfig: options.AuthenticationConfig,
+	}
+	producer, err := producerConfig.NewSyncProducer(logger)
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	replayer := NewReplayer(ReplayerParams{
+		Topic:         options.DLQTopic,
+		TargetTopic:   replayOptions.TargetTopic,
+		Client:        client,
+		Consumer:      consumer,
+		OffsetManager: offsetManager,
+		Producer:      producer,
+		Logger:        logger,
+	})
+	replayed, err := replayer.Replay()
+	logger.Info("Replayed the dead-letter topic", zap.String("topic", options.DLQTopic), zap.Int("messages", replayed))
+	return err
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	replayConfigPrefix = "replay-dlq"
+	suffixGroupID      = ".group-id"
+	suffixTargetTopic  = ".target-topic"
+
+	// DefaultReplayGroupID is the default consumer group recording the progress of the replays
+	DefaultReplayGroupID = "jaeger-ingester-dlq-replay"
+)
+
+// ReplayOptions stores the configuration options of the dead-letter queue replay
+type ReplayOptions struct {
+	GroupID     string `mapstructure:"group_id"`
+	TargetTopic string `mapstructure:"target_topic"`
+}
+
+// AddReplayFlags adds flags for ReplayOptions
+func AddReplayFlags(flagSet *flag.FlagSet) {
+	flagSet.String(
+		replayConfigPrefix+suffixGroupID,
+		DefaultReplayGroupID,
+		"The Consumer Group recording the offsets up to which the dead-letter topic has been replayed")
+	flagSet.String(
+		replayConfigPrefix+suffixTargetTopic,
+		"",
+		"The name of the kafka topic to replay the messages to. If empty, the messages are replayed to the topic they were consumed from")
+}
+
+// InitFromViper initializes ReplayOptions with properties from viper
+func (o *ReplayOptions) InitFromViper(v *viper.Viper) {
+	o.GroupID = v.GetString(replayConfigPrefix + suffixGroupID)
+	o.TargetTopic = v.GetString(replayConfigPrefix + suffixTargetTopic)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestReplayOptionsWithFlags(t *testing.T) {
+	o := &ReplayOptions{}
+	v, command := config.Viperize(AddReplayFlags)
+	command.ParseFlags([]string{
+		"--replay-dlq.group-id=group1",
+		"--replay-dlq.target-topic=topic1",
+	})
+	o.InitFromViper(v)
+
+	assert.Equal(t, "group1", o.GroupID)
+	assert.Equal(t, "topic1", o.TargetTopic)
+}
+
+func TestReplayFlagDefaults(t *testing.T) {
+	o := &ReplayOptions{}
+	v, command := config.Viperize(AddReplayFlags)
+	command.ParseFlags([]string{})
+	o.InitFromViper(v)
+
+	assert.Equal(t, DefaultReplayGroupID, o.GroupID)
+	assert.Empty(t, o.TargetTopic)
+}
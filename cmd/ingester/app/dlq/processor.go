@@ -0,0 +1,162 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
+)
+
+const (
+	// headerPrefix is the prefix of the headers added to the messages published to the dead-letter queue.
+	headerPrefix = "jaeger-dlq-"
+	// HeaderError is the header recording the error that caused the message to be dead-lettered.
+	HeaderError = headerPrefix + "error"
+	// HeaderTopic is the header recording the topic the message was consumed from.
+	HeaderTopic = headerPrefix + "topic"
+	// HeaderPartition is the header recording the partition the message was consumed from.
+	HeaderPartition = headerPrefix + "partition"
+	// HeaderOffset is the header recording the offset of the message in its partition.
+	HeaderOffset = headerPrefix + "offset"
+	// HeaderAttempts is the header recording the number of times processing the message was attempted.
+	HeaderAttempts = headerPrefix + "attempts"
+)
+
+// Message contains the parts of a consumed kafka message that are published to the dead-letter queue.
+type Message interface {
+	processor.Message
+	Key() []byte
+	Topic() string
+	Partition() int32
+	Offset() int64
+	Headers() []*sarama.RecordHeader
+}
+
+// ProcessorParams are the parameters of a dead-letter processor
+type ProcessorParams struct {
+	// Processor is the decorated processor, typically a retrying processor propagating its errors.
+	Processor      processor.SpanProcessor
+	Producer       sarama.SyncProducer
+	Topic          string
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+}
+
+type processorMetrics struct {
+	Undecodable      metrics.Counter `metric:"dlq.messages" tags:"reason=undecodable"`
+	RetriesExhausted metrics.Counter `metric:"dlq.messages" tags:"reason=retries-exhausted"`
+	PublishErrors    metrics.Counter `metric:"dlq.publish-errors"`
+}
+
+type deadLetterProcessor struct {
+	processor processor.SpanProcessor
+	producer  sarama.SyncProducer
+	topic     string
+	metrics   processorMetrics
+	logger    *zap.Logger
+}
+
+// NewProcessor returns a processor publishing the messages that the decorated processor fails to process
+// to the dead-letter topic. The error is only returned if the message cannot be published, so that
+// the offset of the message is not committed.
+func NewProcessor(params ProcessorParams) processor.SpanProcessor {
+	d := &deadLetterProcessor{
+		processor: params.Processor,
+		producer:  params.Producer,
+		topic:     params.Topic,
+		logger:    params.Logger,
+	}
+	metrics.MustInit(&d.metrics, params.MetricsFactory, nil)
+	return d
+}
+
+func (d *deadLetterProcessor) Process(message processor.Message) error {
+	err := d.processor.Process(message)
+	if err == nil {
+		return nil
+	}
+
+	attempts := uint(1)
+	var exhausted *decorator.RetriesExhaustedError
+	if errors.As(err, &exhausted) {
+		attempts = exhausted.Attempts
+	}
+	if _, _, publishErr := d.producer.SendMessage(d.deadLetter(message, err, attempts)); publishErr != nil {
+		d.metrics.PublishErrors.Inc(1)
+		d.logger.Error("Failed to publish message to the dead-letter queue",
+			zap.String("topic", d.topic), zap.NamedError("cause", err), zap.Error(publishErr))
+		return publishErr
+	}
+	if errors.Is(err, processor.ErrCannotUnmarshal) {
+		d.metrics.Undecodable.Inc(1)
+	} else {
+		d.metrics.RetriesExhausted.Inc(1)
+	}
+	return nil
+}
+
+// deadLetter builds the message published to the dead-letter topic, which keeps the key, value and headers
+// of the original message, and records where it comes from and why it could not be processed.
+func (d *deadLetterProcessor) deadLetter(message processor.Message, err error, attempts uint) *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic: d.topic,
+		Value: sarama.ByteEncoder(message.Value()),
+	}
+	var headers []sarama.RecordHeader
+	if m, ok := message.(Message); ok {
+		if m.Key() != nil {
+			msg.Key = sarama.ByteEncoder(m.Key())
+		}
+		headers = originalHeaders(m.Headers())
+		headers = append(headers,
+			header(HeaderTopic, m.Topic()),
+			header(HeaderPartition, strconv.FormatInt(int64(m.Partition()), 10)),
+			header(HeaderOffset, strconv.FormatInt(m.Offset(), 10)))
+	}
+	msg.Headers = append(headers,
+		header(HeaderError, err.Error()),
+		header(HeaderAttempts, strconv.FormatUint(uint64(attempts), 10)))
+	return msg
+}
+
+// Close does not close the producer, which is shared by the processors of all the partitions.
+func (d *deadLetterProcessor) Close() error {
+	return nil
+}
+
+// originalHeaders returns the headers of a message without the ones added by the dead-letter queue,
+// which are present if a replayed message is dead-lettered again.
+func originalHeaders(headers []*sarama.RecordHeader) []sarama.RecordHeader {
+	result := make([]sarama.RecordHeader, 0, len(headers)+5)
+	for _, h := range headers {
+		if h == nil || strings.HasPrefix(string(h.Key), headerPrefix) {
+			continue
+		}
+		result = append(result, *h)
+	}
+	return result
+}
+
+func header(key, value string) sarama.RecordHeader {
+	return sarama.RecordHeader{Key: []byte(key), Value: []byte(value)}
+}
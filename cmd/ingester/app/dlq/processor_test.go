@@ -0,0 +1,189 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	saramaMocks "github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	kmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/decorator"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
+)
+
+type recordingProducer struct {
+	*saramaMocks.SyncProducer
+	published []*sarama.ProducerMessage
+}
+
+func (p *recordingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.published = append(p.published, msg)
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func newMessage() *kmocks.Message {
+	msg := &kmocks.Message{}
+	msg.On("Key").Return([]byte("key"))
+	msg.On("Value").Return([]byte("value"))
+	msg.On("Topic").Return("jaeger-spans")
+	msg.On("Partition").Return(int32(3))
+	msg.On("Offset").Return(int64(42))
+	msg.On("Headers").Return([]*sarama.RecordHeader{
+		{Key: []byte(kafka.HeaderTenant), Value: []byte("acme")},
+		{Key: []byte(HeaderError), Value: []byte("previous error")},
+	})
+	return msg
+}
+
+func TestProcessorSuccess(t *testing.T) {
+	sp := &mocks.SpanProcessor{}
+	msg := newMessage()
+	sp.On("Process", msg).Return(nil)
+	producer := &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)}
+
+	p := NewProcessor(ProcessorParams{
+		Processor:      sp,
+		Producer:       producer,
+		Topic:          "jaeger-spans-dlq",
+		MetricsFactory: metricstest.NewFactory(0),
+		Logger:         zap.NewNop(),
+	})
+
+	assert.NoError(t, p.Process(msg))
+	assert.Empty(t, producer.published)
+	assert.NoError(t, p.Close())
+}
+
+func TestProcessorDeadLetter(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedReason  string
+		expectedAttempt string
+	}{
+		{
+			name:            "undecodable",
+			err:             fmt.Errorf("%w: bad proto", processor.ErrCannotUnmarshal),
+			expectedReason:  "undecodable",
+			expectedAttempt: "1",
+		},
+		{
+			name:            "retries exhausted",
+			err:             &decorator.RetriesExhaustedError{Attempts: 11, Err: errors.New("storage down")},
+			expectedReason:  "retries-exhausted",
+			expectedAttempt: "11",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sp := &mocks.SpanProcessor{}
+			msg := newMessage()
+			sp.On("Process", msg).Return(test.err)
+			producer := &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)}
+			producer.ExpectSendMessageAndSucceed()
+			metricsFactory := metricstest.NewFactory(0)
+
+			p := NewProcessor(ProcessorParams{
+				Processor:      sp,
+				Producer:       producer,
+				Topic:          "jaeger-spans-dlq",
+				MetricsFactory: metricsFactory,
+				Logger:         zap.NewNop(),
+			})
+
+			assert.NoError(t, p.Process(msg))
+			require.Len(t, producer.published, 1)
+			published := producer.published[0]
+			assert.Equal(t, "jaeger-spans-dlq", published.Topic)
+			assert.Equal(t, sarama.ByteEncoder("key"), published.Key)
+			assert.Equal(t, sarama.ByteEncoder("value"), published.Value)
+			assert.Equal(t, []sarama.RecordHeader{
+				header(kafka.HeaderTenant, "acme"),
+				header(HeaderTopic, "jaeger-spans"),
+				header(HeaderPartition, "3"),
+				header(HeaderOffset, "42"),
+				header(HeaderError, test.err.Error()),
+				header(HeaderAttempts, test.expectedAttempt),
+			}, published.Headers)
+			metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+				Name:  "dlq.messages",
+				Tags:  map[string]string{"reason": test.expectedReason},
+				Value: 1,
+			})
+		})
+	}
+}
+
+type valueOnlyMessage struct{}
+
+func (valueOnlyMessage) Value() []byte {
+	return []byte("value")
+}
+
+func TestProcessorDeadLetterWithoutMetadata(t *testing.T) {
+	sp := &mocks.SpanProcessor{}
+	msg := valueOnlyMessage{}
+	sp.On("Process", msg).Return(errors.New("storage down"))
+	producer := &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)}
+	producer.ExpectSendMessageAndSucceed()
+
+	p := NewProcessor(ProcessorParams{
+		Processor:      sp,
+		Producer:       producer,
+		Topic:          "jaeger-spans-dlq",
+		MetricsFactory: metricstest.NewFactory(0),
+		Logger:         zap.NewNop(),
+	})
+
+	assert.NoError(t, p.Process(msg))
+	require.Len(t, producer.published, 1)
+	assert.Nil(t, producer.published[0].Key)
+	assert.Equal(t, []sarama.RecordHeader{
+		header(HeaderError, "storage down"),
+		header(HeaderAttempts, "1"),
+	}, producer.published[0].Headers)
+}
+
+func TestProcessorPublishError(t *testing.T) {
+	sp := &mocks.SpanProcessor{}
+	msg := newMessage()
+	sp.On("Process", msg).Return(errors.New("storage down"))
+	producer := &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)}
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	metricsFactory := metricstest.NewFactory(0)
+
+	p := NewProcessor(ProcessorParams{
+		Processor:      sp,
+		Producer:       producer,
+		Topic:          "jaeger-spans-dlq",
+		MetricsFactory: metricsFactory,
+		Logger:         zap.NewNop(),
+	})
+
+	assert.Equal(t, sarama.ErrOutOfBrokers, p.Process(msg))
+	metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "dlq.publish-errors", Value: 1},
+		metricstest.ExpectedMetric{Name: "dlq.messages", Tags: map[string]string{"reason": "retries-exhausted"}, Value: 0})
+}
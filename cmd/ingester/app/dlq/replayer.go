@@ -0,0 +1,149 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+)
+
+// offsetGetter returns the offsets of a partition, as implemented by sarama.Client.
+type offsetGetter interface {
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+}
+
+// ReplayerParams are the parameters of a Replayer
+type ReplayerParams struct {
+	// Topic is the dead-letter topic.
+	Topic string
+	// TargetTopic overrides the topic the messages are replayed to, which defaults to the topic
+	// recorded in their headers.
+	TargetTopic string
+	Client      offsetGetter
+	Consumer    sarama.Consumer
+	// OffsetManager stores the offsets up to which the partitions have been replayed
+	// under the consumer group of the replay.
+	OffsetManager sarama.OffsetManager
+	Producer      sarama.SyncProducer
+	Logger        *zap.Logger
+}
+
+// Replayer re-injects the messages of the dead-letter topic into the topic they were consumed from.
+type Replayer struct {
+	params ReplayerParams
+}
+
+// NewReplayer creates a new Replayer
+func NewReplayer(params ReplayerParams) *Replayer {
+	return &Replayer{params: params}
+}
+
+// Replay re-injects the messages published to the dead-letter topic since the previous replay, up to
+// the end of the partitions when the replay starts, and returns the number of messages replayed.
+func (r *Replayer) Replay() (int, error) {
+	partitions, err := r.params.Consumer.Partitions(r.params.Topic)
+	if err != nil {
+		return 0, fmt.Errorf("cannot list the partitions of topic %s: %w", r.params.Topic, err)
+	}
+	total := 0
+	for _, partition := range partitions {
+		replayed, err := r.replayPartition(partition)
+		total += replayed
+		if err != nil {
+			return total, fmt.Errorf("cannot replay partition %d of topic %s: %w", partition, r.params.Topic, err)
+		}
+	}
+	return total, nil
+}
+
+func (r *Replayer) replayPartition(partition int32) (int, error) {
+	pom, err := r.params.OffsetManager.ManagePartition(r.params.Topic, partition)
+	if err != nil {
+		return 0, err
+	}
+	defer pom.Close()
+
+	oldest, err := r.params.Client.GetOffset(r.params.Topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, err
+	}
+	// The messages following the last replayed one may have been deleted by the retention since.
+	start, _ := pom.NextOffset()
+	if start < oldest {
+		start = oldest
+	}
+	end, err := r.params.Client.GetOffset(r.params.Topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, err
+	}
+	if start >= end {
+		r.params.Logger.Info("No message to replay", zap.Int32("partition", partition))
+		return 0, nil
+	}
+
+	r.params.Logger.Info("Replaying partition",
+		zap.Int32("partition", partition), zap.Int64("start-offset", start), zap.Int64("end-offset", end))
+	pc, err := r.params.Consumer.ConsumePartition(r.params.Topic, partition, start)
+	if err != nil {
+		return 0, err
+	}
+	defer pc.Close()
+
+	replayed := 0
+	for {
+		select {
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return replayed, fmt.Errorf("partition consumer closed before offset %d", end)
+			}
+			if err := r.replayMessage(msg); err != nil {
+				return replayed, err
+			}
+			pom.MarkOffset(msg.Offset+1, "")
+			replayed++
+			if msg.Offset+1 >= end {
+				return replayed, nil
+			}
+		case err := <-pc.Errors():
+			return replayed, err
+		}
+	}
+}
+
+func (r *Replayer) replayMessage(msg *sarama.ConsumerMessage) error {
+	topic := r.params.TargetTopic
+	if topic == "" {
+		for _, h := range msg.Headers {
+			if h != nil && string(h.Key) == HeaderTopic {
+				topic = string(h.Value)
+			}
+		}
+	}
+	if topic == "" {
+		return fmt.Errorf("message at offset %d has no %s header, a target topic is required", msg.Offset, HeaderTopic)
+	}
+	replay := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: originalHeaders(msg.Headers),
+	}
+	if msg.Key != nil {
+		replay.Key = sarama.ByteEncoder(msg.Key)
+	}
+	_, _, err := r.params.Producer.SendMessage(replay)
+	return err
+}
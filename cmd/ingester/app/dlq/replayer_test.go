@@ -0,0 +1,206 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	saramaMocks "github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
+)
+
+const dlqTopic = "jaeger-spans-dlq"
+
+type fakeClient struct {
+	oldest, newest int64
+	err            error
+}
+
+func (c *fakeClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	if time == sarama.OffsetOldest {
+		return c.oldest, c.err
+	}
+	return c.newest, c.err
+}
+
+type fakeOffsetManager struct {
+	sarama.OffsetManager
+	partitions map[int32]*fakePartitionOffsetManager
+}
+
+func (m *fakeOffsetManager) ManagePartition(topic string, partition int32) (sarama.PartitionOffsetManager, error) {
+	pom, ok := m.partitions[partition]
+	if !ok {
+		return nil, errors.New("partition already managed")
+	}
+	return pom, nil
+}
+
+type fakePartitionOffsetManager struct {
+	sarama.PartitionOffsetManager
+	next   int64
+	closed bool
+}
+
+func (m *fakePartitionOffsetManager) NextOffset() (int64, string) {
+	return m.next, ""
+}
+
+func (m *fakePartitionOffsetManager) MarkOffset(offset int64, metadata string) {
+	m.next = offset
+}
+
+func (m *fakePartitionOffsetManager) Close() error {
+	m.closed = true
+	return nil
+}
+
+type replayerTest struct {
+	consumer  *saramaMocks.Consumer
+	producer  *recordingProducer
+	client    *fakeClient
+	partition *fakePartitionOffsetManager
+	replayer  *Replayer
+}
+
+func newReplayerTest(t *testing.T, targetTopic string, next int64) *replayerTest {
+	consumer := saramaMocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{dlqTopic: {0}})
+	r := &replayerTest{
+		consumer:  consumer,
+		producer:  &recordingProducer{SyncProducer: saramaMocks.NewSyncProducer(t, nil)},
+		client:    &fakeClient{oldest: 1, newest: 4},
+		partition: &fakePartitionOffsetManager{next: next},
+	}
+	r.replayer = NewReplayer(ReplayerParams{
+		Topic:         dlqTopic,
+		TargetTopic:   targetTopic,
+		Client:        r.client,
+		Consumer:      consumer,
+		OffsetManager: &fakeOffsetManager{partitions: map[int32]*fakePartitionOffsetManager{0: r.partition}},
+		Producer:      r.producer,
+		Logger:        zap.NewNop(),
+	})
+	return r
+}
+
+func deadLetter(value string) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Key:   []byte("key-" + value),
+		Value: []byte(value),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(kafka.HeaderTenant), Value: []byte("acme")},
+			{Key: []byte(HeaderTopic), Value: []byte("jaeger-spans")},
+			{Key: []byte(HeaderError), Value: []byte("storage down")},
+			{Key: []byte(HeaderAttempts), Value: []byte("11")},
+		},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := newReplayerTest(t, "", sarama.OffsetOldest)
+	pc := r.consumer.ExpectConsumePartition(dlqTopic, 0, 1)
+	for _, value := range []string{"a", "b", "c"} {
+		pc.YieldMessage(deadLetter(value))
+		r.producer.ExpectSendMessageAndSucceed()
+	}
+
+	replayed, err := r.replayer.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 3, replayed)
+	require.Len(t, r.producer.published, 3)
+	for i, value := range []string{"a", "b", "c"} {
+		msg := r.producer.published[i]
+		assert.Equal(t, "jaeger-spans", msg.Topic)
+		assert.Equal(t, sarama.ByteEncoder("key-"+value), msg.Key)
+		assert.Equal(t, sarama.ByteEncoder(value), msg.Value)
+		assert.Equal(t, []sarama.RecordHeader{header(kafka.HeaderTenant, "acme")}, msg.Headers)
+	}
+	assert.Equal(t, int64(4), r.partition.next)
+	assert.True(t, r.partition.closed)
+	assert.NoError(t, r.consumer.Close())
+}
+
+func TestReplayTargetTopic(t *testing.T) {
+	r := newReplayerTest(t, "jaeger-spans-replay", 1)
+	r.client.oldest, r.client.newest = 0, 2
+	msg := deadLetter("a")
+	msg.Key = nil
+	msg.Headers = nil
+	r.consumer.ExpectConsumePartition(dlqTopic, 0, 1).YieldMessage(msg)
+	r.producer.ExpectSendMessageAndSucceed()
+
+	replayed, err := r.replayer.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	require.Len(t, r.producer.published, 1)
+	assert.Equal(t, "jaeger-spans-replay", r.producer.published[0].Topic)
+	assert.Nil(t, r.producer.published[0].Key)
+	assert.Equal(t, int64(2), r.partition.next)
+}
+
+func TestReplayNothing(t *testing.T) {
+	r := newReplayerTest(t, "", 4)
+
+	replayed, err := r.replayer.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 0, replayed)
+	assert.Empty(t, r.producer.published)
+	assert.True(t, r.partition.closed)
+}
+
+func TestReplayErrors(t *testing.T) {
+	t.Run("missing topic header", func(t *testing.T) {
+		r := newReplayerTest(t, "", sarama.OffsetOldest)
+		msg := deadLetter("a")
+		msg.Headers = msg.Headers[:1]
+		r.consumer.ExpectConsumePartition(dlqTopic, 0, 1).YieldMessage(msg)
+
+		replayed, err := r.replayer.Replay()
+		assert.EqualError(t, err, "cannot replay partition 0 of topic jaeger-spans-dlq: "+
+			"message at offset 1 has no jaeger-dlq-topic header, a target topic is required")
+		assert.Equal(t, 0, replayed)
+		assert.Equal(t, sarama.OffsetOldest, r.partition.next)
+	})
+	t.Run("producer error", func(t *testing.T) {
+		r := newReplayerTest(t, "", sarama.OffsetOldest)
+		r.consumer.ExpectConsumePartition(dlqTopic, 0, 1).YieldMessage(deadLetter("a"))
+		r.producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+
+		_, err := r.replayer.Replay()
+		assert.True(t, errors.Is(err, sarama.ErrOutOfBrokers))
+		assert.Equal(t, sarama.OffsetOldest, r.partition.next)
+	})
+	t.Run("offset error", func(t *testing.T) {
+		r := newReplayerTest(t, "", sarama.OffsetOldest)
+		r.client.err = sarama.ErrOutOfBrokers
+
+		_, err := r.replayer.Replay()
+		assert.True(t, errors.Is(err, sarama.ErrOutOfBrokers))
+	})
+	t.Run("unknown topic", func(t *testing.T) {
+		r := newReplayerTest(t, "", sarama.OffsetOldest)
+		r.consumer.SetTopicMetadata(map[string][]int32{})
+
+		_, err := r.replayer.Replay()
+		assert.Error(t, err)
+	})
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/pkg/kafka/auth"
 	kafkaConsumer "github.com/jaegertracing/jaeger/pkg/kafka/consumer"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 )
 
@@ -37,6 +38,8 @@ const (
 	SuffixBrokers = ".brokers"
 	// SuffixTopic is a suffix for the topic flag
 	SuffixTopic = ".topic"
+	// SuffixTopicRegex is a suffix for the topic regex flag
+	SuffixTopicRegex = ".topic-regex"
 	// SuffixGroupID is a suffix for the group-id flag
 	SuffixGroupID = ".group-id"
 	// SuffixClientID is a suffix for the client-id flag
@@ -49,6 +52,14 @@ const (
 	SuffixDeadlockInterval = ".deadlockInterval"
 	// SuffixParallelism is a suffix for the parallelism flag
 	SuffixParallelism = ".parallelism"
+	// SuffixRetryMaxAttempts is a suffix for the maximum number of retries of a failing message
+	SuffixRetryMaxAttempts = ".retry.max-attempts"
+	// SuffixRetryMinBackoff is a suffix for the minimum backoff between retries
+	SuffixRetryMinBackoff = ".retry.min-backoff"
+	// SuffixRetryMaxBackoff is a suffix for the maximum backoff between retries
+	SuffixRetryMaxBackoff = ".retry.max-backoff"
+	// SuffixDLQTopic is a suffix for the dead-letter queue topic flag
+	SuffixDLQTopic = ".dlq.topic"
 	// SuffixHTTPPort is a suffix for the HTTP port
 	SuffixHTTPPort = ".http-port"
 	// DefaultBroker is the default kafka broker
@@ -65,6 +76,12 @@ const (
 	DefaultEncoding = kafka.EncodingProto
 	// DefaultDeadlockInterval is the default deadlock interval
 	DefaultDeadlockInterval = time.Duration(0)
+	// DefaultRetryMaxAttempts is the default maximum number of retries of a failing message
+	DefaultRetryMaxAttempts = 10
+	// DefaultRetryMinBackoff is the default minimum backoff between retries
+	DefaultRetryMinBackoff = time.Second
+	// DefaultRetryMaxBackoff is the default maximum backoff between retries
+	DefaultRetryMaxBackoff = time.Minute
 )
 
 // Options stores the configuration options for the Ingester
@@ -73,6 +90,12 @@ type Options struct {
 	Parallelism                 int           `mapstructure:"parallelism"`
 	Encoding                    string        `mapstructure:"encoding"`
 	DeadlockInterval            time.Duration `mapstructure:"deadlock_interval"`
+	RetryMaxAttempts            uint          `mapstructure:"retry_max_attempts"`
+	RetryMinBackoff             time.Duration `mapstructure:"retry_min_backoff"`
+	RetryMaxBackoff             time.Duration `mapstructure:"retry_max_backoff"`
+	DLQTopic                    string        `mapstructure:"dlq_topic"`
+	// Tenancy configures the isolation of the consumed spans per tenant
+	Tenancy tenancy.Options `mapstructure:"-"`
 }
 
 // AddFlags adds flags for Builder
@@ -85,6 +108,23 @@ func AddFlags(flagSet *flag.FlagSet) {
 		ConfigPrefix+SuffixDeadlockInterval,
 		DefaultDeadlockInterval,
 		"Interval to check for deadlocks. If no messages gets processed in given time, ingester app will exit. Value of 0 disables deadlock check.")
+	flagSet.Uint(
+		ConfigPrefix+SuffixRetryMaxAttempts,
+		DefaultRetryMaxAttempts,
+		"The maximum number of times a message that fails to be written to storage is retried")
+	flagSet.Duration(
+		ConfigPrefix+SuffixRetryMinBackoff,
+		DefaultRetryMinBackoff,
+		"The minimum backoff before retrying a message, doubled on every retry")
+	flagSet.Duration(
+		ConfigPrefix+SuffixRetryMaxBackoff,
+		DefaultRetryMaxBackoff,
+		"The maximum backoff before retrying a message")
+	flagSet.String(
+		ConfigPrefix+SuffixDLQTopic,
+		"",
+		"The name of the kafka dead-letter topic that messages which cannot be unmarshalled or written to storage after all the retries are published to. "+
+			"If empty, such messages are dropped")
 
 	// Authentication flags
 	flagSet.String(
@@ -94,7 +134,12 @@ func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(
 		KafkaConsumerConfigPrefix+SuffixTopic,
 		DefaultTopic,
-		"The name of the kafka topic to consume from")
+		"The name of the kafka topic to consume from, or a comma-separated list of topics")
+	flagSet.String(
+		KafkaConsumerConfigPrefix+SuffixTopicRegex,
+		"",
+		"A regular expression matching the names of kafka topics to consume from in addition to the topics above, "+
+			"e.g. 'jaeger-spans-.*' for the topics spans are routed to by the collector")
 	flagSet.String(
 		KafkaConsumerConfigPrefix+SuffixGroupID,
 		DefaultGroupID,
@@ -118,7 +163,12 @@ func AddFlags(flagSet *flag.FlagSet) {
 // InitFromViper initializes Builder with properties from viper
 func (o *Options) InitFromViper(v *viper.Viper) {
 	o.Brokers = strings.Split(stripWhiteSpace(v.GetString(KafkaConsumerConfigPrefix+SuffixBrokers)), ",")
-	o.Topic = v.GetString(KafkaConsumerConfigPrefix + SuffixTopic)
+	if topics := stripWhiteSpace(v.GetString(KafkaConsumerConfigPrefix + SuffixTopic)); topics != "" {
+		o.Topics = strings.Split(topics, ",")
+		// Topic holds the first topic for the components consuming from a single topic.
+		o.Topic = o.Topics[0]
+	}
+	o.TopicRegex = v.GetString(KafkaConsumerConfigPrefix + SuffixTopicRegex)
 	o.GroupID = v.GetString(KafkaConsumerConfigPrefix + SuffixGroupID)
 	o.ClientID = v.GetString(KafkaConsumerConfigPrefix + SuffixClientID)
 	o.ProtocolVersion = v.GetString(KafkaConsumerConfigPrefix + SuffixProtocolVersion)
@@ -126,9 +176,14 @@ func (o *Options) InitFromViper(v *viper.Viper) {
 
 	o.Parallelism = v.GetInt(ConfigPrefix + SuffixParallelism)
 	o.DeadlockInterval = v.GetDuration(ConfigPrefix + SuffixDeadlockInterval)
+	o.RetryMaxAttempts = v.GetUint(ConfigPrefix + SuffixRetryMaxAttempts)
+	o.RetryMinBackoff = v.GetDuration(ConfigPrefix + SuffixRetryMinBackoff)
+	o.RetryMaxBackoff = v.GetDuration(ConfigPrefix + SuffixRetryMaxBackoff)
+	o.DLQTopic = v.GetString(ConfigPrefix + SuffixDLQTopic)
 	authenticationOptions := auth.AuthenticationConfig{}
 	authenticationOptions.InitFromViper(KafkaConsumerConfigPrefix, v)
 	o.AuthenticationConfig = authenticationOptions
+	o.Tenancy = tenancy.InitFromViper(v)
 }
 
 // stripWhiteSpace removes all whitespace characters from a string
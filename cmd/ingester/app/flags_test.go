@@ -40,19 +40,43 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--kafka.consumer.protocol-version=1.0.0",
 		"--ingester.parallelism=5",
 		"--ingester.deadlockInterval=2m",
+		"--ingester.retry.max-attempts=3",
+		"--ingester.retry.min-backoff=100ms",
+		"--ingester.retry.max-backoff=5s",
+		"--ingester.dlq.topic=jaeger-spans-dlq",
 	})
 	o.InitFromViper(v)
 
 	assert.Equal(t, "topic1", o.Topic)
+	assert.Equal(t, []string{"topic1"}, o.Topics)
+	assert.Empty(t, o.TopicRegex)
 	assert.Equal(t, []string{"127.0.0.1:9092", "0.0.0:1234"}, o.Brokers)
 	assert.Equal(t, "group1", o.GroupID)
 	assert.Equal(t, "client-id1", o.ClientID)
 	assert.Equal(t, "1.0.0", o.ProtocolVersion)
 	assert.Equal(t, 5, o.Parallelism)
 	assert.Equal(t, 2*time.Minute, o.DeadlockInterval)
+	assert.Equal(t, uint(3), o.RetryMaxAttempts)
+	assert.Equal(t, 100*time.Millisecond, o.RetryMinBackoff)
+	assert.Equal(t, 5*time.Second, o.RetryMaxBackoff)
+	assert.Equal(t, "jaeger-spans-dlq", o.DLQTopic)
 	assert.Equal(t, kafka.EncodingJSON, o.Encoding)
 }
 
+func TestTopicFlags(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--kafka.consumer.topic=jaeger-spans, jaeger-spans-checkout",
+		"--kafka.consumer.topic-regex=jaeger-spans-tenant-.*",
+	})
+	o.InitFromViper(v)
+
+	assert.Equal(t, "jaeger-spans", o.Topic)
+	assert.Equal(t, []string{"jaeger-spans", "jaeger-spans-checkout"}, o.Topics)
+	assert.Equal(t, "jaeger-spans-tenant-.*", o.TopicRegex)
+}
+
 func TestTLSFlags(t *testing.T) {
 	kerb := auth.KerberosConfig{ServiceName: "kafka", ConfigPath: "/etc/krb5.conf", KeyTabPath: "/etc/security/kafka.keytab"}
 	plain := auth.PlainTextConfig{Username: "", Password: "", Mechanism: "PLAIN"}
@@ -107,4 +131,8 @@ func TestFlagDefaults(t *testing.T) {
 	assert.Equal(t, DefaultParallelism, o.Parallelism)
 	assert.Equal(t, DefaultEncoding, o.Encoding)
 	assert.Equal(t, DefaultDeadlockInterval, o.DeadlockInterval)
+	assert.Equal(t, uint(DefaultRetryMaxAttempts), o.RetryMaxAttempts)
+	assert.Equal(t, DefaultRetryMinBackoff, o.RetryMinBackoff)
+	assert.Equal(t, DefaultRetryMaxBackoff, o.RetryMaxBackoff)
+	assert.Empty(t, o.DLQTopic)
 }
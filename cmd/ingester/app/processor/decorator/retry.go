@@ -15,6 +15,8 @@
 package decorator
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"time"
@@ -89,8 +91,25 @@ func PropagateError(b bool) RetryOption {
 	}
 }
 
+// RetriesExhaustedError is returned by the retrying processor, when errors are propagated,
+// if the message still cannot be processed after the maximum number of attempts.
+type RetriesExhaustedError struct {
+	// Attempts is the number of times the message was processed, including the first attempt.
+	Attempts uint
+	Err      error
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("failed to process message after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
 // NewRetryingProcessor returns a processor that retries failures using an exponential backoff
-// with jitter.
+// with jitter. Messages that cannot be unmarshalled are not retried.
 func NewRetryingProcessor(f metrics.Factory, processor processor.SpanProcessor, opts ...RetryOption) processor.SpanProcessor {
 	options := defaultOpts
 	for _, opt := range opts {
@@ -113,7 +132,15 @@ func (d *retryDecorator) Process(message processor.Message) error {
 		return nil
 	}
 
-	for attempts := uint(0); err != nil && d.options.maxAttempts > attempts; attempts++ {
+	if errors.Is(err, processor.ErrCannotUnmarshal) {
+		if d.options.propagateError {
+			return err
+		}
+		return nil
+	}
+
+	attempts := uint(0)
+	for ; err != nil && d.options.maxAttempts > attempts; attempts++ {
 		time.Sleep(d.computeInterval(attempts))
 		err = d.processor.Process(message)
 		d.retryAttempts.Inc(1)
@@ -122,7 +149,7 @@ func (d *retryDecorator) Process(message processor.Message) error {
 	if err != nil {
 		d.exhausted.Inc(1)
 		if d.options.propagateError {
-			return err
+			return &RetriesExhaustedError{Attempts: attempts + 1, Err: err}
 		}
 	}
 
@@ -16,13 +16,16 @@ package decorator
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
 )
 
@@ -59,7 +62,11 @@ func TestNewRetryingProcessorError(t *testing.T) {
 	lf := metricstest.NewFactory(0)
 	rp := NewRetryingProcessor(lf, mockProcessor, opts...)
 
-	assert.Error(t, rp.Process(msg))
+	err := rp.Process(msg)
+	var exhausted *RetriesExhaustedError
+	require.True(t, errors.As(err, &exhausted))
+	assert.Equal(t, uint(3), exhausted.Attempts)
+	assert.EqualError(t, err, "failed to process message after 3 attempts: retry")
 
 	mockProcessor.AssertNumberOfCalls(t, "Process", 3)
 	c, _ := lf.Snapshot()
@@ -88,6 +95,35 @@ func TestNewRetryingProcessorNoErrorPropagation(t *testing.T) {
 	assert.Equal(t, int64(1), c["span-processor.retry-attempts"])
 }
 
+func TestNewRetryingProcessorUnmarshalError(t *testing.T) {
+	for _, propagate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("propagate=%v", propagate), func(t *testing.T) {
+			mockProcessor := &mocks.SpanProcessor{}
+			msg := &fakeMsg{}
+			unmarshalErr := fmt.Errorf("%w: moocow", processor.ErrCannotUnmarshal)
+			mockProcessor.On("Process", msg).Return(unmarshalErr)
+			opts := []RetryOption{
+				MinBackoffInterval(0),
+				MaxAttempts(2),
+				PropagateError(propagate),
+				Rand(&fakeRand{})}
+			lf := metricstest.NewFactory(0)
+			rp := NewRetryingProcessor(lf, mockProcessor, opts...)
+
+			err := rp.Process(msg)
+			if propagate {
+				assert.Equal(t, unmarshalErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockProcessor.AssertNumberOfCalls(t, "Process", 1)
+			lf.AssertCounterMetrics(t,
+				metricstest.ExpectedMetric{Name: "span-processor.retry-exhausted", Value: 0},
+				metricstest.ExpectedMetric{Name: "span-processor.retry-attempts", Value: 0})
+		})
+	}
+}
+
 type fakeRand struct{}
 
 func (f *fakeRand) Int63n(v int64) int64 {
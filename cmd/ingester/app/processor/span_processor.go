@@ -16,9 +16,13 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 
+	"github.com/Shopify/sarama"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -36,16 +40,35 @@ type Message interface {
 	Value() []byte
 }
 
+// HeadersMessage is a Message whose record headers are available, the span processor
+// reads the tenant of the span from them.
+type HeadersMessage interface {
+	Message
+	Headers() []*sarama.RecordHeader
+}
+
+// ErrCannotUnmarshal is returned by the KafkaSpanProcessor when the message is not a valid span.
+// Processing such a message again cannot succeed.
+var ErrCannotUnmarshal = errors.New("cannot unmarshall byte array into span")
+
+// ErrUnexpectedTenant is returned by the KafkaSpanProcessor when the span belongs to a tenant while
+// multi-tenancy is disabled, or to a tenant that is not allowed. The message can be written once
+// multi-tenancy is configured as in the collector.
+var ErrUnexpectedTenant = errors.New("unexpected tenant")
+
 // SpanProcessorParams stores the necessary parameters for a SpanProcessor
 type SpanProcessorParams struct {
 	Writer       spanstore.Writer
 	Unmarshaller kafka.Unmarshaller
+	// TenancyMgr validates the tenants of the spans, multi-tenancy is disabled if nil
+	TenancyMgr *tenancy.Manager
 }
 
 // KafkaSpanProcessor implements SpanProcessor for Kafka messages
 type KafkaSpanProcessor struct {
 	unmarshaller kafka.Unmarshaller
 	writer       spanstore.Writer
+	tenancyMgr   *tenancy.Manager
 	io.Closer
 }
 
@@ -54,15 +77,27 @@ func NewSpanProcessor(params SpanProcessorParams) *KafkaSpanProcessor {
 	return &KafkaSpanProcessor{
 		unmarshaller: params.Unmarshaller,
 		writer:       params.Writer,
+		tenancyMgr:   params.TenancyMgr,
 	}
 }
 
-// Process unmarshals and writes a single kafka message
+// Process unmarshals and writes a single kafka message, in the storage of the tenant
+// recorded in its headers by the collector
 func (s KafkaSpanProcessor) Process(message Message) error {
 	span, err := s.unmarshaller.Unmarshal(message.Value())
 	if err != nil {
-		return fmt.Errorf("cannot unmarshall byte array into span: %w", err)
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
 	}
 	// TODO context should be propagated from upstream components
-	return s.writer.WriteSpan(context.TODO(), span)
+	ctx := context.Background()
+	if m, ok := message.(HeadersMessage); ok {
+		if tenant := kafka.TenantFromHeaders(m.Headers()); tenant != "" {
+			// the spans of a tenant are never written to the storage of the default tenant
+			if s.tenancyMgr == nil || !s.tenancyMgr.Enabled || !s.tenancyMgr.Valid(tenant) {
+				return fmt.Errorf("%w %q, check the multi-tenancy flags", ErrUnexpectedTenant, tenant)
+			}
+			ctx = tenancy.WithTenant(ctx, tenant)
+		}
+	}
+	return s.writer.WriteSpan(ctx, span)
 }
@@ -19,11 +19,15 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	cmocks "github.com/jaegertracing/jaeger/cmd/ingester/app/consumer/mocks"
 	"github.com/jaegertracing/jaeger/model"
 	umocks "github.com/jaegertracing/jaeger/pkg/kafka/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/kafka"
 	smocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
@@ -45,6 +49,7 @@ func TestSpanProcessor_Process(t *testing.T) {
 	span := &model.Span{}
 
 	message.On("Value").Return(data)
+	message.On("Headers").Return(nil)
 	unmarshallerMock.On("Unmarshal", data).Return(span, nil)
 	writer.On("WriteSpan", context.Background(), span).Return(nil)
 
@@ -54,6 +59,61 @@ func TestSpanProcessor_Process(t *testing.T) {
 	writer.AssertExpectations(t)
 }
 
+func TestSpanProcessor_ProcessTenant(t *testing.T) {
+	writer := &smocks.Writer{}
+	unmarshallerMock := &umocks.Unmarshaller{}
+	processor := &KafkaSpanProcessor{
+		unmarshaller: unmarshallerMock,
+		writer:       writer,
+		tenancyMgr:   tenancy.NewManager(&tenancy.Options{Enabled: true}),
+	}
+
+	message := &cmocks.Message{}
+	data := []byte("police")
+	span := &model.Span{}
+
+	message.On("Value").Return(data)
+	message.On("Headers").Return([]*sarama.RecordHeader{
+		{Key: []byte(kafka.HeaderService), Value: []byte("frontend")},
+		{Key: []byte(kafka.HeaderTenant), Value: []byte("acme")},
+	})
+	unmarshallerMock.On("Unmarshal", data).Return(span, nil)
+	writer.On("WriteSpan", tenancy.WithTenant(context.Background(), "acme"), span).Return(nil)
+
+	assert.Nil(t, processor.Process(message))
+
+	message.AssertExpectations(t)
+	writer.AssertExpectations(t)
+}
+
+func TestSpanProcessor_ProcessUnexpectedTenant(t *testing.T) {
+	for _, tenancyMgr := range []*tenancy.Manager{
+		nil,
+		tenancy.NewManager(&tenancy.Options{}),
+		tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"globex"}}),
+	} {
+		writer := &smocks.Writer{}
+		unmarshallerMock := &umocks.Unmarshaller{}
+		processor := NewSpanProcessor(SpanProcessorParams{
+			Writer:       writer,
+			Unmarshaller: unmarshallerMock,
+			TenancyMgr:   tenancyMgr,
+		})
+
+		message := &cmocks.Message{}
+		data := []byte("police")
+		message.On("Value").Return(data)
+		message.On("Headers").Return([]*sarama.RecordHeader{
+			{Key: []byte(kafka.HeaderTenant), Value: []byte("acme")},
+		})
+		unmarshallerMock.On("Unmarshal", data).Return(&model.Span{}, nil)
+
+		err := processor.Process(message)
+		assert.ErrorIs(t, err, ErrUnexpectedTenant)
+		writer.AssertNotCalled(t, "WriteSpan", mock.Anything, mock.Anything)
+	}
+}
+
 func TestSpanProcessor_ProcessError(t *testing.T) {
 	writer := &smocks.Writer{}
 	unmarshallerMock := &umocks.Unmarshaller{}
@@ -68,7 +128,9 @@ func TestSpanProcessor_ProcessError(t *testing.T) {
 	message.On("Value").Return(data)
 	unmarshallerMock.On("Unmarshal", data).Return(nil, errors.New("moocow"))
 
-	assert.Error(t, processor.Process(message))
+	err := processor.Process(message)
+	assert.True(t, errors.Is(err, ErrCannotUnmarshal))
+	assert.EqualError(t, err, "cannot unmarshall byte array into span: moocow")
 
 	message.AssertExpectations(t)
 	writer.AssertNotCalled(t, "WriteSpan")
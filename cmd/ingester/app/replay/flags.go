@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	configPrefix      = "ingester.replay"
+	suffixStartTime   = ".start-time"
+	suffixEndTime     = ".end-time"
+	suffixGroupID     = ".group-id"
+	timeFormatExample = "2021-09-01T10:00:00Z"
+
+	// DefaultGroupID is the default consumer group recording the progress of the replays
+	DefaultGroupID = "jaeger-ingester-replay"
+)
+
+// Options stores the configuration options of a replay
+type Options struct {
+	StartTime string `mapstructure:"start_time"`
+	EndTime   string `mapstructure:"end_time"`
+	GroupID   string `mapstructure:"group_id"`
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(
+		configPrefix+suffixStartTime,
+		"",
+		fmt.Sprintf("The start time of the messages to replay, in RFC3339 format, e.g. %s. "+
+			"When set, the ingester replays the messages published to the topic between the start and end time to the configured storage, "+
+			"which can differ from the storage of the live ingester, then exits", timeFormatExample))
+	flagSet.String(
+		configPrefix+suffixEndTime,
+		"",
+		"The end time (exclusive) of the messages to replay, in RFC3339 format. If empty, the replay ends with the last message published when it starts")
+	flagSet.String(
+		configPrefix+suffixGroupID,
+		DefaultGroupID,
+		"The Consumer Group recording the progress of the replay, which allows an interrupted replay of the same time range to resume")
+}
+
+// InitFromViper initializes Options with properties from viper
+func (o *Options) InitFromViper(v *viper.Viper) {
+	o.StartTime = v.GetString(configPrefix + suffixStartTime)
+	o.EndTime = v.GetString(configPrefix + suffixEndTime)
+	o.GroupID = v.GetString(configPrefix + suffixGroupID)
+}
+
+// Enabled returns true if the ingester is configured to replay a time range instead of consuming the topic.
+func (o *Options) Enabled() bool {
+	return o.StartTime != ""
+}
+
+// TimeRange parses the start and end time of the replay. The end time is the zero time if not set.
+func (o *Options) TimeRange() (start time.Time, end time.Time, err error) {
+	if o.StartTime == "" {
+		return start, end, errors.New("the replay start time is required")
+	}
+	if start, err = time.Parse(time.RFC3339, o.StartTime); err != nil {
+		return start, end, fmt.Errorf("invalid replay start time: %w", err)
+	}
+	if o.EndTime == "" {
+		return start, end, nil
+	}
+	if end, err = time.Parse(time.RFC3339, o.EndTime); err != nil {
+		return start, end, fmt.Errorf("invalid replay end time: %w", err)
+	}
+	if !end.After(start) {
+		return start, end, fmt.Errorf("the replay end time %s is not after the start time %s", o.EndTime, o.StartTime)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestOptionsWithFlags(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--ingester.replay.start-time=2021-09-01T10:00:00Z",
+		"--ingester.replay.end-time=2021-09-01T12:30:00+02:00",
+		"--ingester.replay.group-id=group1",
+	})
+	o.InitFromViper(v)
+
+	assert.True(t, o.Enabled())
+	assert.Equal(t, "group1", o.GroupID)
+	start, end, err := o.TimeRange()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, time.Date(2021, 9, 1, 10, 30, 0, 0, time.UTC), end.UTC())
+}
+
+func TestFlagDefaults(t *testing.T) {
+	o := &Options{}
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{})
+	o.InitFromViper(v)
+
+	assert.False(t, o.Enabled())
+	assert.Equal(t, DefaultGroupID, o.GroupID)
+}
+
+func TestTimeRange(t *testing.T) {
+	tests := []struct {
+		options     Options
+		expectedErr string
+	}{
+		{
+			options:     Options{},
+			expectedErr: "the replay start time is required",
+		},
+		{
+			options:     Options{StartTime: "yesterday"},
+			expectedErr: `invalid replay start time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			options:     Options{StartTime: "2021-09-01T10:00:00Z", EndTime: "today"},
+			expectedErr: `invalid replay end time: parsing time "today" as "2006-01-02T15:04:05Z07:00": cannot parse "today" as "2006"`,
+		},
+		{
+			options:     Options{StartTime: "2021-09-01T10:00:00Z", EndTime: "2021-09-01T10:00:00Z"},
+			expectedErr: "the replay end time 2021-09-01T10:00:00Z is not after the start time 2021-09-01T10:00:00Z",
+		},
+		{
+			options: Options{StartTime: "2021-09-01T10:00:00Z"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.options.StartTime+"/"+test.options.EndTime, func(t *testing.T) {
+			start, end, err := test.options.TimeRange()
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC), start)
+			assert.True(t, end.IsZero())
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+)
+
+const replayNamespace = "replay"
+
+// offsetClient resolves the offsets of a partition, as implemented by sarama.Client.
+type offsetClient interface {
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+	io.Closer
+}
+
+// Params are the parameters of a Replayer
+type Params struct {
+	Topic string
+	Start time.Time
+	// End is the exclusive end of the time range, the zero time replays up to the last message
+	// published when the replay starts.
+	End time.Time
+	// Parallelism is the number of messages of a partition processed concurrently.
+	Parallelism int
+	// Processor writes the messages to storage. Messages that cannot be unmarshalled are skipped,
+	// any other error stops the replay.
+	Processor processor.SpanProcessor
+	Client    offsetClient
+	Consumer  sarama.Consumer
+	// OffsetManager records the progress of the replay under its own consumer group.
+	OffsetManager  sarama.OffsetManager
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+}
+
+// Replayer consumes the messages that were published to a topic within a time range and writes them
+// to storage, independently of the consumer group of the ingester.
+type Replayer struct {
+	params Params
+	// metadata identifies the time range in the committed offsets, so that a replay only resumes
+	// from the offsets committed by a replay of the same time range.
+	metadata            string
+	partitionsRemaining metrics.Gauge
+	remaining           int64
+}
+
+type partitionMetrics struct {
+	messages        metrics.Counter
+	skippedMessages metrics.Counter
+	currentOffset   metrics.Gauge
+	offsetLag       metrics.Gauge
+}
+
+// New creates a new Replayer
+func New(params Params) *Replayer {
+	if params.Parallelism < 1 {
+		params.Parallelism = 1
+	}
+	f := params.MetricsFactory.Namespace(metrics.NSOptions{Name: replayNamespace, Tags: nil})
+	return &Replayer{
+		params:              params,
+		metadata:            fmt.Sprintf("%d-%d", params.Start.UnixNano(), params.End.UnixNano()),
+		partitionsRemaining: f.Gauge(metrics.Options{Name: "partitions-remaining", Tags: nil}),
+	}
+}
+
+// Run replays all the partitions of the topic concurrently and returns once they are all replayed,
+// or when the first error occurs.
+func (r *Replayer) Run(ctx context.Context) error {
+	partitions, err := r.params.Consumer.Partitions(r.params.Topic)
+	if err != nil {
+		return fmt.Errorf("cannot list the partitions of topic %s: %w", r.params.Topic, err)
+	}
+	r.params.Logger.Info("Starting replay",
+		zap.String("topic", r.params.Topic),
+		zap.Time("start", r.params.Start),
+		zap.Time("end", r.params.End),
+		zap.Int("partitions", len(partitions)))
+	atomic.StoreInt64(&r.remaining, int64(len(partitions)))
+	r.partitionsRemaining.Update(int64(len(partitions)))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mux      sync.Mutex
+		firstErr error
+	)
+	for _, partition := range partitions {
+		wg.Add(1)
+		go func(partition int32) {
+			defer wg.Done()
+			if err := r.replayPartition(ctx, partition); err != nil {
+				mux.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("cannot replay partition %d of topic %s: %w", partition, r.params.Topic, err)
+				}
+				mux.Unlock()
+				cancel()
+				return
+			}
+			r.partitionsRemaining.Update(atomic.AddInt64(&r.remaining, -1))
+		}(partition)
+	}
+	wg.Wait()
+	if firstErr == nil {
+		r.params.Logger.Info("Replay complete", zap.String("topic", r.params.Topic))
+	}
+	return firstErr
+}
+
+// Close closes the kafka clients of the Replayer. Closing the offset manager commits the replayed offsets.
+func (r *Replayer) Close() error {
+	var errs []error
+	for _, closer := range []io.Closer{r.params.OffsetManager, r.params.Consumer, r.params.Client} {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close the replay kafka clients: %v", errs)
+	}
+	return nil
+}
+
+func (r *Replayer) newPartitionMetrics(partition int32) partitionMetrics {
+	f := r.params.MetricsFactory.Namespace(metrics.NSOptions{
+		Name: replayNamespace,
+		Tags: map[string]string{"partition": strconv.Itoa(int(partition))},
+	})
+	return partitionMetrics{
+		messages:        f.Counter(metrics.Options{Name: "messages", Tags: nil}),
+		skippedMessages: f.Counter(metrics.Options{Name: "skipped-messages", Tags: nil}),
+		currentOffset:   f.Gauge(metrics.Options{Name: "current-offset", Tags: nil}),
+		offsetLag:       f.Gauge(metrics.Options{Name: "offset-lag", Tags: nil}),
+	}
+}
+
+// offsets resolves the range of offsets of the partition to replay, resuming from the offset
+// committed by a previous replay of the same time range.
+func (r *Replayer) offsets(partition int32, pom sarama.PartitionOffsetManager) (start int64, end int64, err error) {
+	newest, err := r.params.Client.GetOffset(r.params.Topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, 0, err
+	}
+	// The offset of the first message published at or after the time, negative if there is none.
+	offsetAt := func(t time.Time) (int64, error) {
+		offset, err := r.params.Client.GetOffset(r.params.Topic, partition, t.UnixNano()/int64(time.Millisecond))
+		if err != nil || offset < 0 || offset > newest {
+			return newest, err
+		}
+		return offset, nil
+	}
+	if start, err = offsetAt(r.params.Start); err != nil {
+		return 0, 0, err
+	}
+	end = newest
+	if !r.params.End.IsZero() {
+		if end, err = offsetAt(r.params.End); err != nil {
+			return 0, 0, err
+		}
+	}
+	if committed, metadata := pom.NextOffset(); metadata == r.metadata && committed > start {
+		start = committed
+	}
+	return start, end, nil
+}
+
+func (r *Replayer) replayPartition(ctx context.Context, partition int32) error {
+	pom, err := r.params.OffsetManager.ManagePartition(r.params.Topic, partition)
+	if err != nil {
+		return err
+	}
+	defer pom.Close()
+
+	start, end, err := r.offsets(partition, pom)
+	if err != nil {
+		return err
+	}
+	m := r.newPartitionMetrics(partition)
+	if start >= end {
+		m.offsetLag.Update(0)
+		r.params.Logger.Info("No message to replay", zap.Int32("partition", partition))
+		return nil
+	}
+	m.offsetLag.Update(end - start)
+
+	r.params.Logger.Info("Replaying partition",
+		zap.Int32("partition", partition), zap.Int64("start-offset", start), zap.Int64("end-offset", end))
+	pc, err := r.params.Consumer.ConsumePartition(r.params.Topic, partition, start)
+	if err != nil {
+		return err
+	}
+	defer pc.Close()
+
+	batch := make([]*sarama.ConsumerMessage, 0, r.params.Parallelism)
+	for {
+		select {
+		case msg, ok := <-pc.Messages():
+			if !ok {
+				return fmt.Errorf("partition consumer closed before offset %d", end)
+			}
+			next := msg.Offset + 1
+			if msg.Offset < end {
+				batch = append(batch, msg)
+			} else {
+				// The offsets preceding the end offset are not all messages, e.g. transaction markers.
+				next = end
+			}
+			last := next >= end
+			if len(batch) < cap(batch) && !last {
+				continue
+			}
+			if err := r.processBatch(batch, m); err != nil {
+				return err
+			}
+			pom.MarkOffset(next, r.metadata)
+			m.currentOffset.Update(msg.Offset)
+			m.offsetLag.Update(end - next)
+			if last {
+				r.params.Logger.Info("Replayed partition", zap.Int32("partition", partition))
+				return nil
+			}
+			batch = batch[:0]
+		case consumerErr := <-pc.Errors():
+			return consumerErr
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// processBatch processes the messages concurrently, and returns an error if any of them
+// could not be written to storage.
+func (r *Replayer) processBatch(batch []*sarama.ConsumerMessage, m partitionMetrics) error {
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = r.params.Processor.Process(message{batch[i]})
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if errors.Is(err, processor.ErrCannotUnmarshal) {
+			r.params.Logger.Warn("Skipping message that cannot be unmarshalled",
+				zap.Int32("partition", batch[i].Partition), zap.Int64("offset", batch[i].Offset), zap.Error(err))
+			m.skippedMessages.Inc(1)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot process message at offset %d: %w", batch[i].Offset, err)
+		}
+	}
+	m.messages.Inc(int64(len(batch)))
+	return nil
+}
+
+// message adapts a sarama message to the span processors.
+type message struct {
+	*sarama.ConsumerMessage
+}
+
+func (m message) Value() []byte {
+	return m.ConsumerMessage.Value
+}
+
+func (m message) Headers() []*sarama.RecordHeader {
+	return m.ConsumerMessage.Headers
+}
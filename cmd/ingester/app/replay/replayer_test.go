@@ -0,0 +1,289 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	saramaMocks "github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/processor/mocks"
+)
+
+const topic = "jaeger-spans"
+
+var (
+	startTime = time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+	endTime   = startTime.Add(time.Hour)
+)
+
+type fakeClient struct {
+	// offsets of the partitions by timestamp in milliseconds, or sarama.OffsetNewest
+	offsets map[int32]map[int64]int64
+	err     error
+	closed  bool
+}
+
+func (c *fakeClient) GetOffset(topic string, partition int32, time int64) (int64, error) {
+	return c.offsets[partition][time], c.err
+}
+
+func (c *fakeClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+type fakeOffsetManager struct {
+	sarama.OffsetManager
+	partitions map[int32]*fakePartitionOffsetManager
+	closed     bool
+}
+
+func (m *fakeOffsetManager) ManagePartition(topic string, partition int32) (sarama.PartitionOffsetManager, error) {
+	pom, ok := m.partitions[partition]
+	if !ok {
+		return nil, errors.New("partition already managed")
+	}
+	return pom, nil
+}
+
+func (m *fakeOffsetManager) Close() error {
+	m.closed = true
+	return nil
+}
+
+type fakePartitionOffsetManager struct {
+	sarama.PartitionOffsetManager
+	next     int64
+	metadata string
+	closed   bool
+}
+
+func (m *fakePartitionOffsetManager) NextOffset() (int64, string) {
+	return m.next, m.metadata
+}
+
+func (m *fakePartitionOffsetManager) MarkOffset(offset int64, metadata string) {
+	m.next, m.metadata = offset, metadata
+}
+
+func (m *fakePartitionOffsetManager) Close() error {
+	m.closed = true
+	return nil
+}
+
+type replayerTest struct {
+	client         *fakeClient
+	consumer       *saramaMocks.Consumer
+	offsetManager  *fakeOffsetManager
+	processor      *mocks.SpanProcessor
+	metricsFactory *metricstest.Factory
+	replayer       *Replayer
+}
+
+// newReplayerTest creates a replayer of a topic with a single partition, whose offsets
+// at the start and end time are 1 and 3, and whose newest offset is 5.
+func newReplayerTest(t *testing.T, parallelism int) *replayerTest {
+	consumer := saramaMocks.NewConsumer(t, nil)
+	consumer.SetTopicMetadata(map[string][]int32{topic: {0}})
+	r := &replayerTest{
+		client: &fakeClient{offsets: map[int32]map[int64]int64{
+			0: {millis(startTime): 1, millis(endTime): 3, sarama.OffsetNewest: 5},
+		}},
+		consumer: consumer,
+		offsetManager: &fakeOffsetManager{partitions: map[int32]*fakePartitionOffsetManager{
+			0: {next: sarama.OffsetNewest},
+		}},
+		processor:      &mocks.SpanProcessor{},
+		metricsFactory: metricstest.NewFactory(0),
+	}
+	r.replayer = New(Params{
+		Topic:          topic,
+		Start:          startTime,
+		End:            endTime,
+		Parallelism:    parallelism,
+		Processor:      r.processor,
+		Client:         r.client,
+		Consumer:       consumer,
+		OffsetManager:  r.offsetManager,
+		MetricsFactory: r.metricsFactory,
+		Logger:         zap.NewNop(),
+	})
+	return r
+}
+
+func (r *replayerTest) yield(pc *saramaMocks.PartitionConsumer, values ...string) {
+	for _, value := range values {
+		pc.YieldMessage(&sarama.ConsumerMessage{Value: []byte(value)})
+	}
+}
+
+func hasValue(value string) interface{} {
+	return mock.MatchedBy(func(msg processor.Message) bool {
+		return string(msg.Value()) == value
+	})
+}
+
+func TestReplay(t *testing.T) {
+	for _, parallelism := range []int{1, 2, 10} {
+		t.Run(fmt.Sprintf("parallelism=%d", parallelism), func(t *testing.T) {
+			r := newReplayerTest(t, parallelism)
+			r.processor.On("Process", hasValue("a")).Return(nil)
+			r.processor.On("Process", hasValue("b")).Return(nil)
+			r.yield(r.consumer.ExpectConsumePartition(topic, 0, 1), "a", "b", "c", "d")
+
+			require.NoError(t, r.replayer.Run(context.Background()))
+
+			r.processor.AssertNumberOfCalls(t, "Process", 2)
+			pom := r.offsetManager.partitions[0]
+			assert.Equal(t, int64(3), pom.next)
+			assert.Equal(t, r.replayer.metadata, pom.metadata)
+			assert.True(t, pom.closed)
+			r.metricsFactory.AssertCounterMetrics(t, metricstest.ExpectedMetric{
+				Name: "replay.messages", Tags: map[string]string{"partition": "0"}, Value: 2,
+			})
+			r.metricsFactory.AssertGaugeMetrics(t,
+				metricstest.ExpectedMetric{Name: "replay.partitions-remaining", Value: 0},
+				metricstest.ExpectedMetric{Name: "replay.offset-lag", Tags: map[string]string{"partition": "0"}, Value: 0},
+				metricstest.ExpectedMetric{Name: "replay.current-offset", Tags: map[string]string{"partition": "0"}, Value: 2})
+
+			require.NoError(t, r.replayer.Close())
+			assert.True(t, r.offsetManager.closed)
+			assert.True(t, r.client.closed)
+		})
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	r := newReplayerTest(t, 1)
+	tests := []struct {
+		name          string
+		committed     int64
+		metadata      string
+		expectedStart int64
+	}{
+		{name: "no committed offset", committed: sarama.OffsetNewest, expectedStart: 1},
+		{name: "resumed", committed: 2, metadata: r.replayer.metadata, expectedStart: 2},
+		{name: "completed", committed: 3, metadata: r.replayer.metadata, expectedStart: 3},
+		{name: "other time range", committed: 2, metadata: "0-0", expectedStart: 1},
+		{name: "before the time range", committed: 0, metadata: r.replayer.metadata, expectedStart: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pom := &fakePartitionOffsetManager{next: test.committed, metadata: test.metadata}
+			start, end, err := r.replayer.offsets(0, pom)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStart, start)
+			assert.Equal(t, int64(3), end)
+		})
+	}
+}
+
+func TestReplayIgnoresOtherTimeRange(t *testing.T) {
+	r := newReplayerTest(t, 1)
+	r.offsetManager.partitions[0].next = 2
+	r.offsetManager.partitions[0].metadata = "0-0"
+	r.processor.On("Process", mock.Anything).Return(nil)
+	r.yield(r.consumer.ExpectConsumePartition(topic, 0, 1), "a", "b")
+
+	require.NoError(t, r.replayer.Run(context.Background()))
+	r.processor.AssertNumberOfCalls(t, "Process", 2)
+}
+
+func TestReplayUpToNewest(t *testing.T) {
+	r := newReplayerTest(t, 1)
+	r.replayer.params.End = time.Time{}
+	r.replayer.metadata = "resolved with a zero end time"
+	r.client.offsets[0][sarama.OffsetNewest] = 3
+	// No message was published after the end time.
+	r.client.offsets[0][millis(endTime)] = -1
+	r.processor.On("Process", mock.Anything).Return(nil)
+	r.yield(r.consumer.ExpectConsumePartition(topic, 0, 1), "a", "b")
+
+	require.NoError(t, r.replayer.Run(context.Background()))
+	r.processor.AssertNumberOfCalls(t, "Process", 2)
+}
+
+func TestReplayEmptyRange(t *testing.T) {
+	r := newReplayerTest(t, 1)
+	// No message was published after the start time.
+	r.client.offsets[0][millis(startTime)] = -1
+	r.client.offsets[0][millis(endTime)] = -1
+
+	require.NoError(t, r.replayer.Run(context.Background()))
+	r.processor.AssertNotCalled(t, "Process", mock.Anything)
+	assert.True(t, r.offsetManager.partitions[0].closed)
+}
+
+func TestReplaySkipsUndecodableMessages(t *testing.T) {
+	r := newReplayerTest(t, 2)
+	r.processor.On("Process", hasValue("a")).Return(fmt.Errorf("%w: bad proto", processor.ErrCannotUnmarshal))
+	r.processor.On("Process", hasValue("b")).Return(nil)
+	r.yield(r.consumer.ExpectConsumePartition(topic, 0, 1), "a", "b")
+
+	require.NoError(t, r.replayer.Run(context.Background()))
+	r.metricsFactory.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "replay.messages", Tags: map[string]string{"partition": "0"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "replay.skipped-messages", Tags: map[string]string{"partition": "0"}, Value: 1})
+}
+
+func TestReplayErrors(t *testing.T) {
+	t.Run("processor error", func(t *testing.T) {
+		r := newReplayerTest(t, 1)
+		r.processor.On("Process", mock.Anything).Return(errors.New("storage down"))
+		r.yield(r.consumer.ExpectConsumePartition(topic, 0, 1), "a", "b")
+
+		err := r.replayer.Run(context.Background())
+		assert.EqualError(t, err, "cannot replay partition 0 of topic jaeger-spans: cannot process message at offset 1: storage down")
+		assert.Equal(t, sarama.OffsetNewest, r.offsetManager.partitions[0].next)
+	})
+	t.Run("offset error", func(t *testing.T) {
+		r := newReplayerTest(t, 1)
+		r.client.err = sarama.ErrOutOfBrokers
+
+		err := r.replayer.Run(context.Background())
+		assert.True(t, errors.Is(err, sarama.ErrOutOfBrokers))
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		r := newReplayerTest(t, 1)
+		r.consumer.ExpectConsumePartition(topic, 0, 1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := r.replayer.Run(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+	t.Run("unknown topic", func(t *testing.T) {
+		r := newReplayerTest(t, 1)
+		r.consumer.SetTopicMetadata(map[string][]int32{})
+
+		assert.Error(t, r.replayer.Run(context.Background()))
+	})
+}
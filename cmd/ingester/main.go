@@ -15,10 +15,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -31,11 +34,15 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/flags"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app"
 	"github.com/jaegertracing/jaeger/cmd/ingester/app/builder"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/dlq"
+	"github.com/jaegertracing/jaeger/cmd/ingester/app/replay"
 	"github.com/jaegertracing/jaeger/cmd/status"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 func main() {
@@ -71,6 +78,16 @@ func main() {
 
 			options := app.Options{}
 			options.InitFromViper(v)
+			if options.Tenancy.Enabled {
+				if err := storageFactory.ValidateTenantIsolation(); err != nil {
+					logger.Fatal("Failed to enable multi-tenancy", zap.Error(err))
+				}
+			}
+			replayOptions := replay.Options{}
+			replayOptions.InitFromViper(v)
+			if replayOptions.Enabled() {
+				return runReplay(svc, storageFactory, spanWriter, metricsFactory, options, replayOptions)
+			}
 			consumer, err := builder.CreateConsumer(logger, metricsFactory, spanWriter, options)
 			if err != nil {
 				logger.Fatal("Unable to create consumer", zap.Error(err))
@@ -102,6 +119,7 @@ func main() {
 	command.AddCommand(env.Command())
 	command.AddCommand(docs.Command(v))
 	command.AddCommand(status.Command(v, ports.IngesterAdminHTTP))
+	command.AddCommand(dlq.ReplayCommand())
 
 	config.AddFlags(
 		v,
@@ -109,6 +127,8 @@ func main() {
 		svc.AddFlags,
 		storageFactory.AddPipelineFlags,
 		app.AddFlags,
+		replay.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -116,3 +136,38 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runReplay writes the spans published to the topic within the replay time range, and returns
+// once they are all written or the replay is interrupted.
+func runReplay(
+	svc *flags.Service,
+	storageFactory *storage.Factory,
+	spanWriter spanstore.Writer,
+	metricsFactory metrics.Factory,
+	options app.Options,
+	replayOptions replay.Options,
+) error {
+	logger := svc.Logger
+	replayer, err := builder.CreateReplayer(logger, metricsFactory, spanWriter, options, replayOptions)
+	if err != nil {
+		logger.Fatal("Unable to create replayer", zap.Error(err))
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	svc.HC().Ready()
+	replayErr := replayer.Run(ctx)
+
+	if err := replayer.Close(); err != nil {
+		logger.Error("Failed to close replayer", zap.Error(err))
+	}
+	if closer, ok := spanWriter.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Error("Failed to close span writer", zap.Error(err))
+		}
+	}
+	if err := storageFactory.Close(); err != nil {
+		logger.Error("Failed to close storage factory", zap.Error(err))
+	}
+	svc.Admin.Close()
+	return replayErr
+}
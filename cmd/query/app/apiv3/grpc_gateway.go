@@ -17,6 +17,7 @@ package apiv3
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
@@ -25,14 +26,16 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v3"
 )
 
 // RegisterGRPCGateway registers api_v3 endpoints into provided mux.
-func RegisterGRPCGateway(ctx context.Context, logger *zap.Logger, r *mux.Router, basePath string, grpcEndpoint string, grpcTLS tlscfg.Options) error {
+func RegisterGRPCGateway(ctx context.Context, logger *zap.Logger, r *mux.Router, basePath string, grpcEndpoint string, grpcTLS tlscfg.Options, tenancyMgr *tenancy.Manager) error {
 	jsonpb := &runtime.JSONPb{}
 	grpcGatewayMux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, jsonpb),
+		runtime.WithIncomingHeaderMatcher(tenantHeaderMatcher(tenancyMgr)),
 	)
 	var handler http.Handler = grpcGatewayMux
 	if basePath != "/" {
@@ -53,3 +56,13 @@ func RegisterGRPCGateway(ctx context.Context, logger *zap.Logger, r *mux.Router,
 	}
 	return api_v3.RegisterQueryServiceHandlerFromEndpoint(ctx, grpcGatewayMux, grpcEndpoint, dialOpts)
 }
+
+// tenantHeaderMatcher forwards the tenant header to the gRPC server, in addition to the default headers
+func tenantHeaderMatcher(tenancyMgr *tenancy.Manager) runtime.HeaderMatcherFunc {
+	return func(key string) (string, bool) {
+		if tenancyMgr != nil && tenancyMgr.Enabled && strings.EqualFold(key, tenancyMgr.Header) {
+			return tenancyMgr.Header, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
@@ -100,6 +100,8 @@ func TestBuildQueryServiceOptions(t *testing.T) {
 	assert.NotNil(t, qSvcOpts.Adjuster)
 	assert.Nil(t, qSvcOpts.ArchiveSpanReader)
 	assert.Nil(t, qSvcOpts.ArchiveSpanWriter)
+	assert.NotNil(t, qSvcOpts.TenancyMgr)
+	assert.False(t, qSvcOpts.TenancyMgr.Enabled)
 
 	comboFactory := struct {
 		*mocks.Factory
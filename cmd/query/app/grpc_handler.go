@@ -32,6 +32,7 @@ import (
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 const (
@@ -109,17 +110,11 @@ func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.Quer
 	if query == nil {
 		return status.Errorf(codes.InvalidArgument, "missing query")
 	}
-	queryParams := spanstore.TraceQueryParameters{
-		ServiceName:   query.ServiceName,
-		OperationName: query.OperationName,
-		Tags:          query.Tags,
-		StartTimeMin:  query.StartTimeMin,
-		StartTimeMax:  query.StartTimeMax,
-		DurationMin:   query.DurationMin,
-		DurationMax:   query.DurationMax,
-		NumTraces:     int(query.SearchDepth),
+	queryParams, err := toTraceQueryParameters(query)
+	if err != nil {
+		return err
 	}
-	traces, err := g.queryService.FindTraces(stream.Context(), &queryParams)
+	traces, err := g.queryService.FindTraces(stream.Context(), queryParams)
 	if err != nil {
 		g.logger.Error("failed when searching for traces", zap.Error(err))
 		return status.Errorf(codes.Internal, "failed when searching for traces: %v", err)
@@ -132,6 +127,30 @@ func (g *GRPCHandler) FindTraces(r *api_v2.FindTracesRequest, stream api_v2.Quer
 	return nil
 }
 
+func toTraceQueryParameters(query *api_v2.TraceQueryParameters) (*spanstore.TraceQueryParameters, error) {
+	queryParams := &spanstore.TraceQueryParameters{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		Tags:          query.Tags,
+		StartTimeMin:  query.StartTimeMin,
+		StartTimeMax:  query.StartTimeMax,
+		DurationMin:   query.DurationMin,
+		DurationMax:   query.DurationMax,
+		NumTraces:     int(query.SearchDepth),
+	}
+	if query.Query != "" {
+		q, err := querylang.Parse(query.Query)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid trace query: %v", err)
+		}
+		if query.ServiceName == "" && q.Pushdown().ServiceName == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "the trace query must have a service= condition applying to all the matching traces when the service name is not set")
+		}
+		queryParams.Query = q
+	}
+	return queryParams, nil
+}
+
 func (g *GRPCHandler) sendSpanChunks(spans []*model.Span, sendFn func(*api_v2.SpansResponseChunk) error) error {
 	chunk := make([]model.Span, 0, len(spans))
 	for i := 0; i < len(spans); i += maxSpanCountInChunk {
@@ -147,6 +166,50 @@ func (g *GRPCHandler) sendSpanChunks(spans []*model.Span, sendFn func(*api_v2.Sp
 	return nil
 }
 
+// DiffTraces is the gRPC handler to compare two traces.
+func (g *GRPCHandler) DiffTraces(ctx context.Context, r *api_v2.DiffTracesRequest) (*api_v2.DiffTracesResponse, error) {
+	if r == nil {
+		return nil, errNilRequest
+	}
+	if r.TraceIDA == (model.TraceID{}) || r.TraceIDB == (model.TraceID{}) {
+		return nil, errUninitializedTraceID
+	}
+	diff, err := g.queryService.DiffTraces(ctx, r.TraceIDA, r.TraceIDB)
+	if err == spanstore.ErrTraceNotFound {
+		g.logger.Error(msgTraceNotFound, zap.Error(err))
+		return nil, status.Errorf(codes.NotFound, "%s: %v", msgTraceNotFound, err)
+	}
+	if err != nil {
+		g.logger.Error("failed to compare traces", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to compare traces: %v", err)
+	}
+	return &api_v2.DiffTracesResponse{Diff: diff}, nil
+}
+
+// DiffTraceSets is the gRPC handler to compare the traces found by two queries.
+func (g *GRPCHandler) DiffTraceSets(ctx context.Context, r *api_v2.DiffTraceSetsRequest) (*api_v2.DiffTraceSetsResponse, error) {
+	if r == nil {
+		return nil, errNilRequest
+	}
+	if r.QueryA == nil || r.QueryB == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "missing query")
+	}
+	queryA, err := toTraceQueryParameters(r.QueryA)
+	if err != nil {
+		return nil, err
+	}
+	queryB, err := toTraceQueryParameters(r.QueryB)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := g.queryService.DiffTraceSets(ctx, queryA, queryB)
+	if err != nil {
+		g.logger.Error("failed to compare trace sets", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "failed to compare trace sets: %v", err)
+	}
+	return &api_v2.DiffTraceSetsResponse{Diff: diff}, nil
+}
+
 // GetServices is the gRPC handler to fetch services.
 func (g *GRPCHandler) GetServices(ctx context.Context, r *api_v2.GetServicesRequest) (*api_v2.GetServicesResponse, error) {
 	services, err := g.queryService.GetServices(ctx)
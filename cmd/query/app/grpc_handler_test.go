@@ -460,6 +460,121 @@ func TestFindTracesMissingQuery_GRPC(t *testing.T) {
 	})
 }
 
+func TestFindTracesWithQueryLanguage_GRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+			return q.Query != nil && q.Query.String() == "service=foo AND {tag.error=true}"
+		})).Return([]*model.Trace{mockTraceGRPC}, nil).Once()
+
+		res, err := client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{Query: "service=foo AND {tag.error=true}"},
+		})
+		require.NoError(t, err)
+		spanResChunk, err := res.Recv()
+		require.NoError(t, err)
+		assert.Len(t, spanResChunk.Spans, len(mockTraceGRPC.Spans))
+
+		res, err = client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{Query: "service="},
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		res, err = client.FindTraces(context.Background(), &api_v2.FindTracesRequest{
+			Query: &api_v2.TraceQueryParameters{Query: "duration>1s"},
+		})
+		require.NoError(t, err)
+		_, err = res.Recv()
+		assertGRPCError(t, err, codes.InvalidArgument, "must have a service= condition")
+	})
+}
+
+func TestDiffTracesSuccessGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(mockTraceGRPC, nil).Twice()
+
+		res, err := client.DiffTraces(context.Background(), &api_v2.DiffTracesRequest{
+			TraceIDA: mockTraceID,
+			TraceIDB: mockTraceID,
+		})
+		require.NoError(t, err)
+		assert.Len(t, res.Diff.Spans, len(mockTraceGRPC.Spans))
+	})
+}
+
+func TestDiffTracesFailuresGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		_, err := client.DiffTraces(context.Background(), &api_v2.DiffTracesRequest{TraceIDA: mockTraceID})
+		assert.ErrorIs(t, err, errUninitializedTraceID)
+
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, spanstore.ErrTraceNotFound).Once()
+		server.archiveSpanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, spanstore.ErrTraceNotFound).Once()
+		_, err = client.DiffTraces(context.Background(), &api_v2.DiffTracesRequest{TraceIDA: mockTraceID, TraceIDB: mockTraceID})
+		assertGRPCError(t, err, codes.NotFound, "trace not found")
+
+		server.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+			Return(nil, errStorageGRPC).Once()
+		_, err = client.DiffTraces(context.Background(), &api_v2.DiffTracesRequest{TraceIDA: mockTraceID, TraceIDB: mockTraceID})
+		assertGRPCError(t, err, codes.Internal, "failed to compare traces")
+	})
+}
+
+func TestDiffTraceSetsSuccessGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Return([]*model.Trace{mockTraceGRPC}, nil).Twice()
+
+		res, err := client.DiffTraceSets(context.Background(), &api_v2.DiffTraceSetsRequest{
+			QueryA: &api_v2.TraceQueryParameters{ServiceName: "before"},
+			QueryB: &api_v2.TraceQueryParameters{ServiceName: "after"},
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, res.Diff.TracesA.Count)
+		assert.EqualValues(t, 1, res.Diff.TracesB.Count)
+	})
+}
+
+func TestDiffTraceSetsFailuresGRPC(t *testing.T) {
+	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
+		_, err := client.DiffTraceSets(context.Background(), &api_v2.DiffTraceSetsRequest{
+			QueryA: &api_v2.TraceQueryParameters{ServiceName: "before"},
+		})
+		assertGRPCError(t, err, codes.InvalidArgument, "missing query")
+
+		_, err = client.DiffTraceSets(context.Background(), &api_v2.DiffTraceSetsRequest{
+			QueryA: &api_v2.TraceQueryParameters{Query: "service="},
+			QueryB: &api_v2.TraceQueryParameters{ServiceName: "after"},
+		})
+		assertGRPCError(t, err, codes.InvalidArgument, "invalid trace query")
+
+		_, err = client.DiffTraceSets(context.Background(), &api_v2.DiffTraceSetsRequest{
+			QueryA: &api_v2.TraceQueryParameters{ServiceName: "before"},
+			QueryB: &api_v2.TraceQueryParameters{Query: "service="},
+		})
+		assertGRPCError(t, err, codes.InvalidArgument, "invalid trace query")
+
+		server.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+			Return(nil, errStorageGRPC).Once()
+		_, err = client.DiffTraceSets(context.Background(), &api_v2.DiffTraceSetsRequest{
+			QueryA: &api_v2.TraceQueryParameters{ServiceName: "before"},
+			QueryB: &api_v2.TraceQueryParameters{ServiceName: "after"},
+		})
+		assertGRPCError(t, err, codes.Internal, "failed to compare trace sets")
+	})
+}
+
+func TestDiffNilRequestOnHandlerGRPC(t *testing.T) {
+	grpcHandler := &GRPCHandler{}
+	_, err := grpcHandler.DiffTraces(context.Background(), nil)
+	assert.EqualError(t, err, errNilRequest.Error())
+	_, err = grpcHandler.DiffTraceSets(context.Background(), nil)
+	assert.EqualError(t, err, errNilRequest.Error())
+}
+
 func TestFindTracesFailure_GRPC(t *testing.T) {
 	withServerAndClient(t, func(server *grpcServer, client *grpcClient) {
 		mockErrorGRPC := fmt.Errorf("whatsamattayou")
@@ -16,14 +16,21 @@
 package app
 
 import (
+	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	spanstoremocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
 func TestDeduplicateDependencies(t *testing.T) {
@@ -353,3 +360,62 @@ func TestGetDependenciesLookbackParsingFailure(t *testing.T) {
 	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&service=testing&lookback=shazbot", &response)
 	assert.Error(t, err)
 }
+
+func TestGetOperationDependencies(t *testing.T) {
+	opReader := &depsmocks.OperationReader{}
+	reader := struct {
+		*depsmocks.Reader
+		*depsmocks.OperationReader
+	}{&depsmocks.Reader{}, opReader}
+	qs := querysvc.NewQueryService(&spanstoremocks.Reader{}, reader, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, HandlerOptions.Logger(zap.NewNop())).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	link := model.OperationDependencyLink{
+		Parent:          "killer",
+		ParentOperation: "stab",
+		Child:           "queen",
+		ChildOperation:  "die",
+		CallCount:       2,
+		ErrorCount:      1,
+		Latency:         model.LatencySummary{Min: time.Millisecond, Max: 3 * time.Millisecond, Total: 4 * time.Millisecond},
+	}
+	opReader.On("GetOperationDependencies", endTs, defaultDependencyLookbackDuration).
+		Return([]model.OperationDependencyLink{link, link, {Parent: "king", Child: "jester", CallCount: 1}}, nil).Once()
+	opReader.On("GetOperationDependencies", endTs, defaultDependencyLookbackDuration).Return(nil, errStorage).Once()
+
+	var response struct {
+		Data []ui.OperationDependencyLink `json:"data"`
+	}
+	err := getJSON(server.URL+"/api/dependencies?endTs=1476374248550&service=queen&granularity=operation", &response)
+	require.NoError(t, err)
+	assert.Equal(t, []ui.OperationDependencyLink{{
+		Parent:          "killer",
+		ParentOperation: "stab",
+		Child:           "queen",
+		ChildOperation:  "die",
+		CallCount:       4,
+		ErrorCount:      2,
+		MinDuration:     1000,
+		MaxDuration:     3000,
+		MeanDuration:    2000,
+	}}, response.Data)
+
+	err = getJSON(server.URL+"/api/dependencies?endTs=1476374248550&granularity=operation", &response)
+	assert.EqualError(t, err, parsedError(500, errStorage.Error()))
+}
+
+func TestGetOperationDependenciesFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&granularity=operation", &response)
+	assert.EqualError(t, err, parsedError(501, dependencystore.ErrOperationDependenciesNotSupported.Error()))
+	err = getJSON(ts.server.URL+"/api/dependencies?endTs=1476374248550&granularity=endpoint", &response)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400 error from server")
+}
@@ -22,6 +22,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 // HandlerOption is a function that sets some option on the APIHandler
@@ -74,3 +75,11 @@ func (handlerOptions) MetricsQueryService(mqs querysvc.MetricsQueryService) Hand
 		apiHandler.metricsQueryService = mqs
 	}
 }
+
+// Tenancy creates a HandlerOption that initializes the tenancy manager,
+// which extracts the tenant of the requests.
+func (handlerOptions) Tenancy(tenancyMgr *tenancy.Manager) HandlerOption {
+	return func(apiHandler *APIHandler) {
+		apiHandler.tenancyMgr = tenancyMgr
+	}
+}
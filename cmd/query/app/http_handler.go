@@ -34,10 +34,13 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/model"
 	uiconv "github.com/jaegertracing/jaeger/model/converter/json"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
 	ui "github.com/jaegertracing/jaeger/model/json"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
@@ -50,6 +53,7 @@ const (
 	rateParam             = "ratePer"
 	quantileParam         = "quantile"
 	groupByOperationParam = "groupByOperation"
+	criticalPathParam     = "criticalPath"
 
 	defaultAPIPrefix  = "api"
 	prettyPrintIndent = "    "
@@ -88,6 +92,7 @@ type APIHandler struct {
 	apiPrefix           string
 	logger              *zap.Logger
 	tracer              opentracing.Tracer
+	tenancyMgr          *tenancy.Manager
 }
 
 // NewAPIHandler returns an APIHandler
@@ -117,9 +122,14 @@ func NewAPIHandler(queryService *querysvc.QueryService, options ...HandlerOption
 
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router) {
+	// the diff routes must be registered before /traces/{traceID}
+	aH.handleFunc(router, aH.diffTraces, "/traces/diff").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.diffTraceSets, "/traces/diff/aggregate").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getTrace, "/traces/{%s}", traceIDParam).Methods(http.MethodGet)
 	aH.handleFunc(router, aH.archiveTrace, "/archive/{%s}", traceIDParam).Methods(http.MethodPost)
 	aH.handleFunc(router, aH.search, "/traces").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.searchSpans, "/spans").Methods(http.MethodGet)
+	aH.handleFunc(router, aH.spanStats, "/stats").Methods(http.MethodGet)
 	aH.handleFunc(router, aH.getServices, "/services").Methods(http.MethodGet)
 	// TODO change the UI to use this endpoint. Requires ?service= parameter.
 	aH.handleFunc(router, aH.getOperations, "/operations").Methods(http.MethodGet)
@@ -145,7 +155,7 @@ func (aH *APIHandler) handleFunc(
 		nethttp.OperationNameFunc(func(r *http.Request) string {
 			return route
 		}))
-	return router.HandleFunc(route, traceMiddleware.ServeHTTP)
+	return router.Handle(route, tenancy.ExtractTenantHTTPHandler(aH.tenancyMgr, traceMiddleware))
 }
 
 func (aH *APIHandler) route(route string, args ...interface{}) string {
@@ -254,6 +264,50 @@ func (aH *APIHandler) search(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func (aH *APIHandler) searchSpans(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.queryParser.parseSpanQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	spans, err := aH.queryService.FindSpans(r.Context(), query)
+	if errors.Is(err, spanstore.ErrFindSpansNotSupported) {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	uiSpans := make([]*ui.Span, len(spans))
+	for i, span := range spans {
+		uiSpans[i] = uiconv.FromDomainEmbedProcess(span)
+	}
+	structuredRes := structuredResponse{
+		Data: uiSpans,
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+func (aH *APIHandler) spanStats(w http.ResponseWriter, r *http.Request) {
+	query, err := aH.queryParser.parseSpanStatsQueryParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	stats, err := aH.queryService.GetSpanStats(r.Context(), query)
+	if errors.Is(err, spanstore.ErrSpanStatsNotSupported) {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	structuredRes := structuredResponse{
+		Data: uiconv.SpanStatsFromDomain(stats),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func (aH *APIHandler) tracesByIDs(ctx context.Context, traceIDs []model.TraceID) ([]*model.Trace, []structuredError, error) {
 	var errors []structuredError
 	retMe := make([]*model.Trace, 0, len(traceIDs))
@@ -279,6 +333,10 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	service := r.FormValue(serviceParam)
+	if dqp.byOperation {
+		aH.operationDependencies(w, r, dqp, service)
+		return
+	}
 
 	dependencies, err := aH.queryService.GetDependencies(r.Context(), dqp.endTs, dqp.lookback)
 	if aH.handleError(w, err, http.StatusInternalServerError) {
@@ -292,6 +350,29 @@ func (aH *APIHandler) dependencies(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+func (aH *APIHandler) operationDependencies(w http.ResponseWriter, r *http.Request, dqp dependenciesQueryParameters, service string) {
+	dependencies, err := aH.queryService.GetOperationDependencies(r.Context(), dqp.endTs, dqp.lookback)
+	if errors.Is(err, dependencystore.ErrOperationDependenciesNotSupported) {
+		aH.handleError(w, err, http.StatusNotImplemented)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+
+	// the storage can return several links between the same operations, e.g. computed over different periods
+	links := dependencystore.NewOperationLinks()
+	for _, dependency := range dependencies {
+		if service == "" || dependency.Parent == service || dependency.Child == service {
+			links.Add(dependency)
+		}
+	}
+	structuredRes := structuredResponse{
+		Data: uiconv.OperationDependenciesFromDomain(links.Links()),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func (aH *APIHandler) latencies(w http.ResponseWriter, r *http.Request) {
 	q, err := strconv.ParseFloat(r.FormValue(quantileParam), 64)
 	if err != nil {
@@ -347,6 +428,12 @@ func (aH *APIHandler) metrics(w http.ResponseWriter, r *http.Request, getMetrics
 }
 
 func (aH *APIHandler) convertModelToUI(trace *model.Trace, adjust bool) (*ui.Trace, *structuredError) {
+	return aH.convertModelToUIWithCriticalPath(trace, adjust, false)
+}
+
+// convertModelToUIWithCriticalPath converts the trace to the UI format, and decorates it with its critical path
+// if requested. The critical path is computed after the adjusters are applied, so that it matches the UI spans.
+func (aH *APIHandler) convertModelToUIWithCriticalPath(trace *model.Trace, adjust bool, criticalPath bool) (*ui.Trace, *structuredError) {
 	var errors []error
 	if adjust {
 		var err error
@@ -356,6 +443,9 @@ func (aH *APIHandler) convertModelToUI(trace *model.Trace, adjust bool) (*ui.Tra
 		}
 	}
 	uiTrace := uiconv.FromDomain(trace)
+	if criticalPath {
+		uiTrace.CriticalPath = uiconv.CriticalPathFromDomain(criticalpath.Compute(trace))
+	}
 	var uiError *structuredError
 	if err := multierror.Wrap(errors); err != nil {
 		uiError = &structuredError{
@@ -431,7 +521,7 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var uiErrors []structuredError
-	uiTrace, uiErr := aH.convertModelToUI(trace, shouldAdjust(r))
+	uiTrace, uiErr := aH.convertModelToUIWithCriticalPath(trace, shouldAdjust(r), shouldComputeCriticalPath(r))
 	if uiErr != nil {
 		uiErrors = append(uiErrors, *uiErr)
 	}
@@ -445,12 +535,56 @@ func (aH *APIHandler) getTrace(w http.ResponseWriter, r *http.Request) {
 	aH.writeJSON(w, r, &structuredRes)
 }
 
+// diffTraces implements the REST API /traces/diff?a={trace-id}&b={trace-id}.
+// It compares the span trees of the two traces and responds with the diff in the UI JSON format.
+func (aH *APIHandler) diffTraces(w http.ResponseWriter, r *http.Request) {
+	traceIDA, traceIDB, err := aH.queryParser.parseTraceDiffParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	diff, err := aH.queryService.DiffTraces(r.Context(), traceIDA, traceIDB)
+	if err == spanstore.ErrTraceNotFound {
+		aH.handleError(w, err, http.StatusNotFound)
+		return
+	}
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: uiconv.TraceDiffFromDomain(diff),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
+// diffTraceSets implements the REST API /traces/diff/aggregate?a={query}&b={query}.
+// It compares the durations of the traces found by the two URL-encoded search queries
+// and responds with the diff in the UI JSON format.
+func (aH *APIHandler) diffTraceSets(w http.ResponseWriter, r *http.Request) {
+	queryA, queryB, err := aH.queryParser.parseTraceSetDiffParams(r)
+	if aH.handleError(w, err, http.StatusBadRequest) {
+		return
+	}
+	diff, err := aH.queryService.DiffTraceSets(r.Context(), &queryA.TraceQueryParameters, &queryB.TraceQueryParameters)
+	if aH.handleError(w, err, http.StatusInternalServerError) {
+		return
+	}
+	structuredRes := structuredResponse{
+		Data: uiconv.TraceSetDiffFromDomain(diff),
+	}
+	aH.writeJSON(w, r, &structuredRes)
+}
+
 func shouldAdjust(r *http.Request) bool {
 	raw := r.FormValue("raw")
 	isRaw, _ := strconv.ParseBool(raw)
 	return !isRaw
 }
 
+func shouldComputeCriticalPath(r *http.Request) bool {
+	criticalPath, _ := strconv.ParseBool(r.FormValue(criticalPathParam))
+	return criticalPath
+}
+
 // archiveTrace implements the REST API POST:/archive/{trace-id}.
 // It passes the traceID to queryService.ArchiveTrace for writing.
 func (aH *APIHandler) archiveTrace(w http.ResponseWriter, r *http.Request) {
@@ -483,6 +617,9 @@ func (aH *APIHandler) handleError(w http.ResponseWriter, err error, statusCode i
 	if errors.Is(err, disabled.ErrDisabled) {
 		statusCode = http.StatusNotImplemented
 	}
+	if errors.Is(err, tenancy.ErrMissingTenant) || errors.Is(err, tenancy.ErrInvalidTenant) {
+		statusCode = tenancy.HTTPStatusCode(err)
+	}
 	if statusCode == http.StatusInternalServerError {
 		aH.logger.Error("HTTP handler, Internal Server Error", zap.Error(err))
 	}
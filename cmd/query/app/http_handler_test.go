@@ -17,6 +17,7 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -43,6 +45,7 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	ui "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
@@ -323,6 +326,38 @@ func TestGetTrace(t *testing.T) {
 	}
 }
 
+func TestGetTraceWithCriticalPath(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	trace := &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID:   mockTraceID,
+				SpanID:    model.NewSpanID(1),
+				StartTime: time.Unix(10, 0),
+				Duration:  time.Second,
+				Process:   &model.Process{},
+			},
+		},
+	}
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mockTraceID).
+		Return(trace, nil).Twice()
+
+	var response structuredTraceResponse
+	err := getJSON(ts.server.URL+`/api/traces/`+mockTraceID.String()+`?criticalPath=true`, &response)
+	require.NoError(t, err)
+	require.Len(t, response.Traces, 1)
+	assert.Equal(t, []ui.CriticalPathSegment{
+		{SpanID: ui.SpanID(model.NewSpanID(1).String()), StartTime: 10000000, Duration: 1000000},
+	}, response.Traces[0].CriticalPath)
+
+	var responseWithoutCriticalPath structuredTraceResponse
+	err = getJSON(ts.server.URL+`/api/traces/`+mockTraceID.String(), &responseWithoutCriticalPath)
+	require.NoError(t, err)
+	require.Len(t, responseWithoutCriticalPath.Traces, 1)
+	assert.Empty(t, responseWithoutCriticalPath.Traces[0].CriticalPath)
+}
+
 func TestGetTraceDBFailure(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -470,6 +505,112 @@ func TestSearchDBFailure(t *testing.T) {
 	assert.EqualError(t, err, parsedError(500, "whatsamattayou"))
 }
 
+func TestSearchSpans(t *testing.T) {
+	spanFinder := &spanstoremocks.SpanFinder{}
+	reader := struct {
+		*spanstoremocks.Reader
+		*spanstoremocks.SpanFinder
+	}{&spanstoremocks.Reader{}, spanFinder}
+	qs := querysvc.NewQueryService(reader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, HandlerOptions.Logger(zap.NewNop())).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	spanFinder.On("FindSpans", mock.Anything, mock.MatchedBy(func(query *spanstore.SpanQueryParameters) bool {
+		return query.ServiceName == "service" && query.SortBy == spanstore.SortByDuration && query.NumSpans == 2
+	})).Return(mockTrace.Spans, nil).Once()
+	spanFinder.On("FindSpans", mock.Anything, mock.Anything).Return(nil, errors.New("whatsamattayou")).Once()
+
+	var response struct {
+		Data []*ui.Span `json:"data"`
+	}
+	err := getJSON(server.URL+`/api/spans?service=service&sortBy=duration&limit=2`, &response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, len(mockTrace.Spans))
+	for i, span := range response.Data {
+		assert.Equal(t, ui.TraceID(mockTrace.Spans[i].TraceID.String()), span.TraceID)
+		require.NotNil(t, span.Process)
+		assert.Equal(t, mockTrace.Spans[i].Process.ServiceName, span.Process.ServiceName)
+	}
+
+	err = getJSON(server.URL+`/api/spans?service=service`, &response)
+	assert.EqualError(t, err, parsedError(500, "whatsamattayou"))
+}
+
+func TestSearchSpansFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/spans?service=service`, &response)
+	assert.EqualError(t, err, parsedError(501, spanstore.ErrFindSpansNotSupported.Error()))
+	err = getJSON(ts.server.URL+`/api/spans?service=service&sortBy=name`, &response)
+	assert.EqualError(t, err, parsedError(400, `unable to parse param 'sortBy': unsupported value \"name\", expected \"startTime\" or \"duration\"`))
+}
+
+func TestGetSpanStats(t *testing.T) {
+	statsReader := &spanstoremocks.SpanStatsReader{}
+	reader := struct {
+		*spanstoremocks.Reader
+		*spanstoremocks.SpanStatsReader
+	}{&spanstoremocks.Reader{}, statsReader}
+	qs := querysvc.NewQueryService(reader, &depsmocks.Reader{}, querysvc.QueryServiceOptions{})
+	r := NewRouter()
+	NewAPIHandler(qs, HandlerOptions.Logger(zap.NewNop())).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	stats := []spanstore.SpanStats{
+		{
+			ServiceName:   "service",
+			OperationName: "operation",
+			Count:         3,
+			ErrorCount:    1,
+			P50:           2 * time.Millisecond,
+			P90:           5 * time.Millisecond,
+			P99:           5 * time.Millisecond,
+			Histogram: []spanstore.HistogramBucket{
+				{UpperBound: time.Millisecond},
+				{LowerBound: time.Millisecond, Count: 3},
+			},
+		},
+	}
+	statsReader.On("GetSpanStats", mock.Anything, mock.MatchedBy(func(query *spanstore.SpanStatsQueryParameters) bool {
+		return query.ServiceName == "service" && query.GroupByOperation && len(query.Buckets) == 1
+	})).Return(stats, nil).Once()
+	statsReader.On("GetSpanStats", mock.Anything, mock.Anything).Return(nil, errors.New("whatsamattayou")).Once()
+
+	var response struct {
+		Data []ui.SpanStats `json:"data"`
+	}
+	err := getJSON(server.URL+`/api/stats?service=service&groupByOperation=true&bucket=1ms`, &response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "operation", response.Data[0].OperationName)
+	assert.Equal(t, int64(3), response.Data[0].Count)
+	assert.Equal(t, int64(1), response.Data[0].ErrorCount)
+	assert.Equal(t, uint64(2000), response.Data[0].P50)
+	assert.Equal(t, []ui.HistogramBucket{
+		{UpperBound: 1000},
+		{LowerBound: 1000, Count: 3},
+	}, response.Data[0].Histogram)
+
+	err = getJSON(server.URL+`/api/stats?service=service`, &response)
+	assert.EqualError(t, err, parsedError(500, "whatsamattayou"))
+}
+
+func TestGetSpanStatsFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	var response structuredResponse
+	err := getJSON(ts.server.URL+`/api/stats?service=service`, &response)
+	assert.EqualError(t, err, parsedError(501, spanstore.ErrSpanStatsNotSupported.Error()))
+	err = getJSON(ts.server.URL+`/api/stats?bucket=1s&bucket=1ms`, &response)
+	assert.EqualError(t, err, parsedError(400, "parameters 'bucket' should be positive and increasing"))
+}
+
 func TestSearchFailures(t *testing.T) {
 	tests := []struct {
 		urlStr string
@@ -500,6 +641,121 @@ func testIndividualSearchFailures(t *testing.T, urlStr, errMsg string) {
 	assert.EqualError(t, err, errMsg)
 }
 
+func TestDiffTraces(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 0x123)).
+		Return(mockTrace, nil).Once()
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), model.NewTraceID(0, 0x456)).
+		Return(mockTrace, nil).Once()
+
+	var response struct {
+		Data   ui.TraceDiff      `json:"data"`
+		Errors []structuredError `json:"errors"`
+	}
+	err := getJSON(ts.server.URL+`/api/traces/diff?a=123&b=456`, &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	require.Len(t, response.Data.Spans, 2)
+	for _, span := range response.Data.Spans {
+		assert.Equal(t, "matched", span.Status)
+		assert.Equal(t, span.SpanIDA, span.SpanIDB)
+		assert.EqualValues(t, 0, span.DurationDelta)
+	}
+	assert.Equal(t, ui.SpanID(mockTrace.Spans[0].SpanID.String()), response.Data.Spans[0].SpanIDA)
+	assert.Equal(t, model.DurationAsMicroseconds(mockTrace.Spans[0].Duration), response.Data.Spans[0].DurationA)
+}
+
+func TestDiffTracesFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	err := getJSON(ts.server.URL+`/api/traces/diff?a=123`, nil)
+	assert.EqualError(t, err, parsedError(400, "parameter 'b' is required"))
+
+	err = getJSON(ts.server.URL+`/api/traces/diff?a=123&b=xyz`, nil)
+	assert.EqualError(t, err, parsedError(400, `unable to parse param 'b': strconv.ParseUint: parsing \"xyz\": invalid syntax`))
+
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, spanstore.ErrTraceNotFound).Once()
+	err = getJSON(ts.server.URL+`/api/traces/diff?a=123&b=456`, nil)
+	assert.EqualError(t, err, parsedError(404, "trace not found"))
+
+	ts.spanReader.On("GetTrace", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("model.TraceID")).
+		Return(nil, errStorage).Once()
+	err = getJSON(ts.server.URL+`/api/traces/diff?a=123&b=456`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
+func TestDiffTraceSets(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.ServiceName == "before"
+	})).Return([]*model.Trace{mockTrace}, nil).Once()
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(func(q *spanstore.TraceQueryParameters) bool {
+		return q.ServiceName == "after" && q.NumTraces == 10
+	})).Return([]*model.Trace{mockTrace, mockTrace}, nil).Once()
+
+	var response struct {
+		Data   ui.TraceSetDiff   `json:"data"`
+		Errors []structuredError `json:"errors"`
+	}
+	query := url.Values{}
+	query.Set("a", "service=before")
+	query.Set("b", "service=after&limit=10")
+	err := getJSON(ts.server.URL+`/api/traces/diff/aggregate?`+query.Encode(), &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+	assert.EqualValues(t, 1, response.Data.TracesA.Count)
+	assert.EqualValues(t, 2, response.Data.TracesB.Count)
+	require.Len(t, response.Data.Operations, 1)
+	assert.EqualValues(t, 2, response.Data.Operations[0].A.Count)
+	assert.EqualValues(t, 4, response.Data.Operations[0].B.Count)
+}
+
+func TestDiffTraceSetsFailures(t *testing.T) {
+	ts := initializeTestServer()
+	defer ts.server.Close()
+
+	tests := []struct {
+		a, b   string
+		errMsg string
+	}{
+		{
+			a:      "service=foo",
+			errMsg: parsedError(400, "parameter 'b' is required"),
+		},
+		{
+			a:      "service=foo",
+			b:      "operation=bar",
+			errMsg: parsedError(400, "invalid parameter 'b': parameter 'service' is required"),
+		},
+		{
+			a:      "traceID=123",
+			b:      "service=foo",
+			errMsg: parsedError(400, "parameter 'traceID' is not supported in the trace sets to compare"),
+		},
+		{
+			a:      "service=foo;",
+			b:      "service=foo",
+			errMsg: parsedError(400, "unable to parse param 'a': invalid semicolon separator in query"),
+		},
+	}
+	for _, test := range tests {
+		query := url.Values{}
+		query.Set("a", test.a)
+		query.Set("b", test.b)
+		err := getJSON(ts.server.URL+`/api/traces/diff/aggregate?`+query.Encode(), nil)
+		assert.EqualError(t, err, test.errMsg)
+	}
+
+	ts.spanReader.On("FindTraces", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*spanstore.TraceQueryParameters")).
+		Return(nil, errStorage).Once()
+	err := getJSON(ts.server.URL+`/api/traces/diff/aggregate?a=service%3Dfoo&b=service%3Dbar`, nil)
+	assert.EqualError(t, err, parsedError(500, errStorageMsg))
+}
+
 func TestGetServicesSuccess(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
@@ -516,6 +772,44 @@ func TestGetServicesSuccess(t *testing.T) {
 	assert.Equal(t, expectedServices, actualServices)
 }
 
+func TestGetServicesWithTenancy(t *testing.T) {
+	tenancyMgr := tenancy.NewManager(&tenancy.Options{Enabled: true, Tenants: []string{"acme"}, Required: true})
+	ts := initializeTestServerWithHandler(querysvc.QueryServiceOptions{TenancyMgr: tenancyMgr}, HandlerOptions.Tenancy(tenancyMgr))
+	defer ts.server.Close()
+	ts.spanReader.On("GetServices", mock.MatchedBy(func(ctx context.Context) bool {
+		return tenancy.GetTenant(ctx) == "acme"
+	})).Return([]string{"trifle"}, nil).Once()
+
+	testCases := []struct {
+		name    string
+		tenant  string
+		errCode string
+	}{
+		{name: "missing tenant", errCode: "401"},
+		{name: "not allowed tenant", tenant: "other", errCode: "403"},
+		{name: "allowed tenant", tenant: "acme"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", ts.server.URL+"/api/services", nil)
+			require.NoError(t, err)
+			if tc.tenant != "" {
+				req.Header.Set(tenancy.DefaultHeader, tc.tenant)
+			}
+			var response structuredResponse
+			err = execJSON(req, &response)
+			if tc.errCode != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []interface{}{"trifle"}, response.Data)
+		})
+	}
+	ts.spanReader.AssertExpectations(t)
+}
+
 func TestGetServicesStorageFailure(t *testing.T) {
 	ts := initializeTestServer()
 	defer ts.server.Close()
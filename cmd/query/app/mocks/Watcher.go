@@ -41,6 +41,20 @@ func (_m *Watcher) Add(name string) error {
 	return r0
 }
 
+// Close provides a mock function with given fields:
+func (_m *Watcher) Close() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Errors provides a mock function with given fields:
 func (_m *Watcher) Errors() chan error {
 	ret := _m.Called()
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 const (
@@ -44,6 +46,22 @@ const (
 	spanKindParam    = "spanKind"
 	endTimeParam     = "end"
 	prettyPrintParam = "prettyPrint"
+	queryParam       = "q"
+	diffAParam       = "a"
+	diffBParam       = "b"
+	sortByParam      = "sortBy"
+	orderParam       = "order"
+	groupByTagParam  = "groupByTag"
+	bucketParam      = "bucket"
+	granularityParam = "granularity"
+
+	sortByStartTime = "startTime"
+	sortByDuration  = "duration"
+	orderAscending  = "asc"
+	orderDescending = "desc"
+
+	granularityService   = "service"
+	granularityOperation = "operation"
 )
 
 var (
@@ -52,6 +70,18 @@ var (
 	// errServiceParameterRequired occurs when no service name is defined.
 	errServiceParameterRequired = fmt.Errorf("parameter '%s' is required", serviceParam)
 
+	// errQueryServiceRequired occurs when neither the service parameter nor the trace query define the service name,
+	// which the storage backends need to search their indexes.
+	errQueryServiceRequired = fmt.Errorf("parameter '%s' must have a 'service=' condition applying to all the matching traces when parameter '%s' is not set", queryParam, serviceParam)
+
+	errDiffTraceIDsNotSupported = fmt.Errorf("parameter '%s' is not supported in the trace sets to compare", traceIDParam)
+
+	errSpanQueryTraceIDsNotSupported = fmt.Errorf("parameter '%s' is not supported in span search", traceIDParam)
+
+	errSpanQueryLanguageNotSupported = fmt.Errorf("parameter '%s' is not supported in span search", queryParam)
+
+	errBucketsNotIncreasing = fmt.Errorf("parameters '%s' should be positive and increasing", bucketParam)
+
 	jaegerToOtelSpanKind = map[string]string{
 		"unspecified": metrics.SpanKind_SPAN_KIND_UNSPECIFIED.String(),
 		"internal":    metrics.SpanKind_SPAN_KIND_INTERNAL.String(),
@@ -77,6 +107,8 @@ type (
 	dependenciesQueryParameters struct {
 		endTs    time.Time
 		lookback time.Duration
+		// byOperation is set when the links between operations rather than services are requested
+		byOperation bool
 	}
 
 	durationParser = func(s string) (time.Duration, error)
@@ -113,7 +145,7 @@ func newDurationUnitsParser(units time.Duration) durationParser {
 //
 // Trace query syntax:
 //     query ::= param | param '&' query
-//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags
+//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags | q
 //     service ::= 'service=' strValue
 //     operation ::= 'operation=' strValue
 //     limit ::= 'limit=' intValue
@@ -125,6 +157,7 @@ func newDurationUnitsParser(units time.Duration) durationParser {
 //     key := strValue
 //     keyValue := strValue ':' strValue
 //     tags :== 'tags=' jsonMap
+//     q ::= 'q=' strValue in the trace query language, e.g. service=foo AND {span.http.status_code>=500}
 func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParameters, error) {
 	service := r.FormValue(serviceParam)
 	operation := r.FormValue(operationParam)
@@ -164,6 +197,13 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 		return nil, err
 	}
 
+	var query *querylang.Query
+	if q := r.FormValue(queryParam); q != "" {
+		if query, err = querylang.Parse(q); err != nil {
+			return nil, newParseError(err, queryParam)
+		}
+	}
+
 	var traceIDs []model.TraceID
 	for _, id := range r.Form[traceIDParam] {
 		if traceID, err := model.TraceIDFromString(id); err == nil {
@@ -183,6 +223,7 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 			NumTraces:     limit,
 			DurationMin:   minDuration,
 			DurationMax:   maxDuration,
+			Query:         query,
 		},
 		traceIDs: traceIDs,
 	}
@@ -193,6 +234,152 @@ func (p *queryParser) parseTraceQueryParams(r *http.Request) (*traceQueryParamet
 	return traceQuery, nil
 }
 
+// parseTraceDiffParams takes a request and returns the IDs of the traces to compare.
+//
+// Trace diff query syntax:
+//     query ::= 'a=' traceID '&b=' traceID
+func (p *queryParser) parseTraceDiffParams(r *http.Request) (model.TraceID, model.TraceID, error) {
+	var traceIDs [2]model.TraceID
+	for i, param := range []string{diffAParam, diffBParam} {
+		value := r.FormValue(param)
+		if value == "" {
+			return model.TraceID{}, model.TraceID{}, fmt.Errorf("parameter '%s' is required", param)
+		}
+		traceID, err := model.TraceIDFromString(value)
+		if err != nil {
+			return model.TraceID{}, model.TraceID{}, newParseError(err, param)
+		}
+		traceIDs[i] = traceID
+	}
+	return traceIDs[0], traceIDs[1], nil
+}
+
+// parseTraceSetDiffParams takes a request and returns the queries of the sets of traces to compare.
+// Each query is URL-encoded in the syntax of the trace search, without traceID parameters.
+//
+// Trace set diff query syntax:
+//     query ::= 'a=' urlEncodedTraceQuery '&b=' urlEncodedTraceQuery
+func (p *queryParser) parseTraceSetDiffParams(r *http.Request) (*traceQueryParameters, *traceQueryParameters, error) {
+	var queries [2]*traceQueryParameters
+	for i, param := range []string{diffAParam, diffBParam} {
+		value := r.FormValue(param)
+		if value == "" {
+			return nil, nil, fmt.Errorf("parameter '%s' is required", param)
+		}
+		form, err := url.ParseQuery(value)
+		if err != nil {
+			return nil, nil, newParseError(err, param)
+		}
+		query, err := p.parseTraceQueryParams(&http.Request{Form: form})
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid parameter '%s': %w", param, err)
+		}
+		if len(query.traceIDs) > 0 {
+			return nil, nil, errDiffTraceIDsNotSupported
+		}
+		queries[i] = query
+	}
+	return queries[0], queries[1], nil
+}
+
+// parseSpanQueryParams takes a request and constructs a model of span search parameters.
+// The filters are the same as in the trace search and must all be satisfied by each span,
+// except for the trace query language and the trace IDs, which are not supported.
+//
+// Span query syntax:
+//     query ::= param | param '&' query
+//     param ::= service | operation | limit | start | end | minDuration | maxDuration | tag | tags | sortBy | order
+//     sortBy ::= 'sortBy=' ( 'startTime' | 'duration' ), defaults to startTime
+//     order ::= 'order=' ( 'asc' | 'desc' ), defaults to desc
+func (p *queryParser) parseSpanQueryParams(r *http.Request) (*spanstore.SpanQueryParameters, error) {
+	traceQuery, err := p.parseTraceQueryParams(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(traceQuery.traceIDs) > 0 {
+		return nil, errSpanQueryTraceIDsNotSupported
+	}
+	if traceQuery.Query != nil {
+		return nil, errSpanQueryLanguageNotSupported
+	}
+	query := &spanstore.SpanQueryParameters{
+		ServiceName:   traceQuery.ServiceName,
+		OperationName: traceQuery.OperationName,
+		Tags:          traceQuery.Tags,
+		StartTimeMin:  traceQuery.StartTimeMin,
+		StartTimeMax:  traceQuery.StartTimeMax,
+		DurationMin:   traceQuery.DurationMin,
+		DurationMax:   traceQuery.DurationMax,
+		NumSpans:      traceQuery.NumTraces,
+	}
+	switch sortBy := r.FormValue(sortByParam); sortBy {
+	case "", sortByStartTime:
+		query.SortBy = spanstore.SortByStartTime
+	case sortByDuration:
+		query.SortBy = spanstore.SortByDuration
+	default:
+		return nil, newParseError(fmt.Errorf("unsupported value %q, expected %q or %q", sortBy, sortByStartTime, sortByDuration), sortByParam)
+	}
+	switch order := r.FormValue(orderParam); order {
+	case "", orderDescending:
+	case orderAscending:
+		query.Ascending = true
+	default:
+		return nil, newParseError(fmt.Errorf("unsupported value %q, expected %q or %q", order, orderAscending, orderDescending), orderParam)
+	}
+	return query, nil
+}
+
+// parseSpanStatsQueryParams takes a request and constructs a model of span statistics parameters.
+// The statistics are computed per service, and optionally per operation and per value of a tag,
+// for the spans that started within the time window. The histogram buckets are defined by their
+// upper bounds, expressed as duration strings like "1ms".
+//
+// Span statistics query syntax:
+//     query ::= param | param '&' query
+//     param ::= service | operation | start | end | groupByOperation | groupByTag | bucket
+//     service ::= 'service=' strValue
+//     operation ::= 'operation=' strValue
+//     start ::= 'start=' intValue in unix microseconds
+//     end ::= 'end=' intValue in unix microseconds
+//     groupByOperation ::= 'groupByOperation=' boolValue
+//     groupByTag ::= 'groupByTag=' strValue
+//     bucket ::= 'bucket=' strValue (units are "ns", "us" (or "µs"), "ms", "s", "m", "h")
+func (p *queryParser) parseSpanStatsQueryParams(r *http.Request) (*spanstore.SpanStatsQueryParameters, error) {
+	startTime, err := p.parseTime(r, startTimeParam, time.Microsecond)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := p.parseTime(r, endTimeParam, time.Microsecond)
+	if err != nil {
+		return nil, err
+	}
+	groupByOperation, err := parseBool(r, groupByOperationParam)
+	if err != nil {
+		return nil, err
+	}
+	var buckets []time.Duration
+	for _, bucket := range r.Form[bucketParam] {
+		upperBound, err := time.ParseDuration(bucket)
+		if err != nil {
+			return nil, newParseError(err, bucketParam)
+		}
+		if upperBound <= 0 || (len(buckets) > 0 && upperBound <= buckets[len(buckets)-1]) {
+			return nil, errBucketsNotIncreasing
+		}
+		buckets = append(buckets, upperBound)
+	}
+	return &spanstore.SpanStatsQueryParameters{
+		ServiceName:      r.FormValue(serviceParam),
+		OperationName:    r.FormValue(operationParam),
+		StartTimeMin:     startTime,
+		StartTimeMax:     endTime,
+		GroupByOperation: groupByOperation,
+		GroupByTag:       r.FormValue(groupByTagParam),
+		Buckets:          buckets,
+	}, nil
+}
+
 // parseDependenciesQueryParams takes a request and constructs a model of dependencies query parameters.
 //
 // The dependencies API does not operate on the latency space, instead its timestamps are just time range selections,
@@ -205,7 +392,18 @@ func (p *queryParser) parseDependenciesQueryParams(r *http.Request) (dqp depende
 	}
 
 	dqp.lookback, err = parseDuration(r, lookbackParam, newDurationUnitsParser(time.Millisecond), defaultDependencyLookbackDuration)
-	return dqp, err
+	if err != nil {
+		return dqp, err
+	}
+
+	switch granularity := r.FormValue(granularityParam); granularity {
+	case "", granularityService:
+	case granularityOperation:
+		dqp.byOperation = true
+	default:
+		return dqp, newParseError(fmt.Errorf("unsupported value %q, expected %q or %q", granularity, granularityService, granularityOperation), granularityParam)
+	}
+	return dqp, nil
 }
 
 // parseMetricsQueryParams takes a request and constructs a model of metrics query parameters.
@@ -371,7 +569,12 @@ func mapSpanKindsToOpenTelemetry(spanKinds []string) ([]string, error) {
 
 func (p *queryParser) validateQuery(traceQuery *traceQueryParameters) error {
 	if len(traceQuery.traceIDs) == 0 && traceQuery.ServiceName == "" {
-		return errServiceParameterRequired
+		if traceQuery.Query == nil {
+			return errServiceParameterRequired
+		}
+		if traceQuery.Query.Pushdown().ServiceName == "" {
+			return errQueryServiceRequired
+		}
 	}
 	if traceQuery.DurationMin != 0 && traceQuery.DurationMax != 0 {
 		if traceQuery.DurationMax < traceQuery.DurationMin {
@@ -18,6 +18,7 @@ package app
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"testing"
 	"time"
@@ -173,6 +174,168 @@ func TestParseTraceQuery(t *testing.T) {
 	}
 }
 
+func TestParseTraceQueryWithQueryLanguage(t *testing.T) {
+	parser := &queryParser{timeNow: time.Now}
+
+	request, err := http.NewRequest(http.MethodGet, "x?start=0&end=0&q="+url.QueryEscape("service=foo AND {tag.error=true}"), nil)
+	require.NoError(t, err)
+	tqp, err := parser.parseTraceQueryParams(request)
+	require.NoError(t, err)
+	assert.Empty(t, tqp.ServiceName)
+	require.NotNil(t, tqp.Query)
+	assert.Equal(t, "service=foo AND {tag.error=true}", tqp.Query.String())
+
+	request, err = http.NewRequest(http.MethodGet, "x?q="+url.QueryEscape("service=foo AND"), nil)
+	require.NoError(t, err)
+	_, err = parser.parseTraceQueryParams(request)
+	assert.EqualError(t, err, "unable to parse param 'q': unexpected end of query at position 15")
+
+	request, err = http.NewRequest(http.MethodGet, "x?q="+url.QueryEscape("service=foo OR service=bar"), nil)
+	require.NoError(t, err)
+	_, err = parser.parseTraceQueryParams(request)
+	assert.Equal(t, errQueryServiceRequired, err)
+
+	request, err = http.NewRequest(http.MethodGet, "x?service=foo&q="+url.QueryEscape("tag.error=true"), nil)
+	require.NoError(t, err)
+	_, err = parser.parseTraceQueryParams(request)
+	assert.NoError(t, err)
+}
+
+func TestParseSpanQuery(t *testing.T) {
+	parser := &queryParser{timeNow: time.Now}
+	tests := []struct {
+		urlStr        string
+		errMsg        string
+		expectedQuery *spanstore.SpanQueryParameters
+	}{
+		{
+			urlStr: "x?service=service&operation=operation&start=0&end=0&limit=50&minDuration=20ms&tag=k:v&sortBy=duration",
+			expectedQuery: &spanstore.SpanQueryParameters{
+				ServiceName:   "service",
+				OperationName: "operation",
+				Tags:          map[string]string{"k": "v"},
+				StartTimeMin:  time.Unix(0, 0),
+				StartTimeMax:  time.Unix(0, 0),
+				DurationMin:   20 * time.Millisecond,
+				NumSpans:      50,
+				SortBy:        spanstore.SortByDuration,
+			},
+		},
+		{
+			urlStr: "x?service=service&start=0&end=0&sortBy=startTime&order=asc",
+			expectedQuery: &spanstore.SpanQueryParameters{
+				ServiceName:  "service",
+				Tags:         map[string]string{},
+				StartTimeMin: time.Unix(0, 0),
+				StartTimeMax: time.Unix(0, 0),
+				NumSpans:     defaultQueryLimit,
+				Ascending:    true,
+			},
+		},
+		{urlStr: "x?start=0&end=0", errMsg: "parameter 'service' is required"},
+		{urlStr: "x?traceID=1", errMsg: "parameter 'traceID' is not supported in span search"},
+		{urlStr: "x?q=service%3Dfoo", errMsg: "parameter 'q' is not supported in span search"},
+		{urlStr: "x?service=service&sortBy=name", errMsg: `unable to parse param 'sortBy': unsupported value "name", expected "startTime" or "duration"`},
+		{urlStr: "x?service=service&order=up", errMsg: `unable to parse param 'order': unsupported value "up", expected "asc" or "desc"`},
+	}
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, test.urlStr, nil)
+			require.NoError(t, err)
+			query, err := parser.parseSpanQueryParams(request)
+			if test.errMsg != "" {
+				assert.EqualError(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedQuery, query)
+		})
+	}
+}
+
+func TestParseSpanStatsQuery(t *testing.T) {
+	parser := &queryParser{timeNow: time.Now}
+	tests := []struct {
+		urlStr        string
+		errMsg        string
+		expectedQuery *spanstore.SpanStatsQueryParameters
+	}{
+		{
+			urlStr: "x?service=service&operation=operation&start=0&end=1000&groupByOperation=true&groupByTag=http.status_code&bucket=10ms&bucket=1s",
+			expectedQuery: &spanstore.SpanStatsQueryParameters{
+				ServiceName:      "service",
+				OperationName:    "operation",
+				StartTimeMin:     time.Unix(0, 0),
+				StartTimeMax:     time.Unix(0, 1000*int64(time.Microsecond)),
+				GroupByOperation: true,
+				GroupByTag:       "http.status_code",
+				Buckets:          []time.Duration{10 * time.Millisecond, time.Second},
+			},
+		},
+		{
+			urlStr: "x?start=0&end=0",
+			expectedQuery: &spanstore.SpanStatsQueryParameters{
+				StartTimeMin: time.Unix(0, 0),
+				StartTimeMax: time.Unix(0, 0),
+			},
+		},
+		{urlStr: "x?start=foo", errMsg: `unable to parse param 'start': strconv.ParseInt: parsing "foo": invalid syntax`},
+		{urlStr: "x?groupByOperation=foo", errMsg: `unable to parse param 'groupByOperation': strconv.ParseBool: parsing "foo": invalid syntax`},
+		{urlStr: "x?bucket=foo", errMsg: `unable to parse param 'bucket': time: invalid duration "foo"`},
+		{urlStr: "x?bucket=1s&bucket=10ms", errMsg: "parameters 'bucket' should be positive and increasing"},
+		{urlStr: "x?bucket=0s", errMsg: "parameters 'bucket' should be positive and increasing"},
+	}
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, test.urlStr, nil)
+			require.NoError(t, err)
+			query, err := parser.parseSpanStatsQueryParams(request)
+			if test.errMsg != "" {
+				assert.EqualError(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedQuery, query)
+		})
+	}
+}
+
+func TestParseDependenciesQuery(t *testing.T) {
+	parser := &queryParser{timeNow: time.Now}
+	tests := []struct {
+		urlStr        string
+		errMsg        string
+		expectedQuery dependenciesQueryParameters
+	}{
+		{
+			urlStr:        "x?endTs=1000&lookback=60000",
+			expectedQuery: dependenciesQueryParameters{endTs: time.Unix(1, 0), lookback: time.Minute},
+		},
+		{
+			urlStr:        "x?endTs=1000&lookback=60000&granularity=service",
+			expectedQuery: dependenciesQueryParameters{endTs: time.Unix(1, 0), lookback: time.Minute},
+		},
+		{
+			urlStr:        "x?endTs=1000&lookback=60000&granularity=operation",
+			expectedQuery: dependenciesQueryParameters{endTs: time.Unix(1, 0), lookback: time.Minute, byOperation: true},
+		},
+		{urlStr: "x?endTs=1000&granularity=endpoint", errMsg: `unable to parse param 'granularity': unsupported value "endpoint", expected "service" or "operation"`},
+	}
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, test.urlStr, nil)
+			require.NoError(t, err)
+			query, err := parser.parseDependenciesQueryParams(request)
+			if test.errMsg != "" {
+				assert.EqualError(t, err, test.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedQuery, query)
+		})
+	}
+}
+
 func TestParseBool(t *testing.T) {
 	for _, tc := range []struct {
 		input string
@@ -21,9 +21,13 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/cmd/query/app/tracediff"
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
 	"github.com/jaegertracing/jaeger/pkg/multierror"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -42,6 +46,8 @@ type QueryServiceOptions struct {
 	ArchiveSpanReader spanstore.Reader
 	ArchiveSpanWriter spanstore.Writer
 	Adjuster          adjuster.Adjuster
+	// TenancyMgr validates the tenant carried by the context of every call
+	TenancyMgr *tenancy.Manager
 }
 
 // QueryService contains span utils required by the query-service.
@@ -67,6 +73,9 @@ func NewQueryService(spanReader spanstore.Reader, dependencyReader dependencysto
 
 // GetTrace is the queryService implementation of spanstore.Reader.GetTrace
 func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	trace, err := qs.spanReader.GetTrace(ctx, traceID)
 	if err == spanstore.ErrTraceNotFound {
 		if qs.options.ArchiveSpanReader == nil {
@@ -79,6 +88,9 @@ func (qs QueryService) GetTrace(ctx context.Context, traceID model.TraceID) (*mo
 
 // GetServices is the queryService implementation of spanstore.Reader.GetServices
 func (qs QueryService) GetServices(ctx context.Context) ([]string, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	return qs.spanReader.GetServices(ctx)
 }
 
@@ -87,16 +99,96 @@ func (qs QueryService) GetOperations(
 	ctx context.Context,
 	query spanstore.OperationQueryParameters,
 ) ([]spanstore.Operation, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	return qs.spanReader.GetOperations(ctx, query)
 }
 
 // FindTraces is the queryService implementation of spanstore.Reader.FindTraces
 func (qs QueryService) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	return qs.spanReader.FindTraces(ctx, query)
 }
 
+// FindSpans is the queryService implementation of spanstore.SpanFinder.
+// It returns spanstore.ErrFindSpansNotSupported if the span reader does not support span search.
+func (qs QueryService) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	finder, ok := qs.spanReader.(spanstore.SpanFinder)
+	if !ok {
+		return nil, spanstore.ErrFindSpansNotSupported
+	}
+	return finder.FindSpans(ctx, query)
+}
+
+// GetSpanStats is the queryService implementation of spanstore.SpanStatsReader.
+// It returns spanstore.ErrSpanStatsNotSupported if the span reader does not support span statistics.
+func (qs QueryService) GetSpanStats(ctx context.Context, query *spanstore.SpanStatsQueryParameters) ([]spanstore.SpanStats, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	statsReader, ok := qs.spanReader.(spanstore.SpanStatsReader)
+	if !ok {
+		return nil, spanstore.ErrSpanStatsNotSupported
+	}
+	return statsReader.GetSpanStats(ctx, query)
+}
+
+// GetCriticalPath returns the critical path of a trace, after applying the adjusters to it.
+func (qs QueryService) GetCriticalPath(ctx context.Context, traceID model.TraceID) ([]criticalpath.Segment, error) {
+	trace, err := qs.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	// the adjusters return the trace even when they fail
+	trace, _ = qs.Adjust(trace)
+	return criticalpath.Compute(trace), nil
+}
+
+// DiffTraces compares two traces, after applying the adjusters to them.
+func (qs QueryService) DiffTraces(ctx context.Context, traceIDA, traceIDB model.TraceID) (*api_v2.TraceDiff, error) {
+	traceA, err := qs.GetTrace(ctx, traceIDA)
+	if err != nil {
+		return nil, err
+	}
+	traceB, err := qs.GetTrace(ctx, traceIDB)
+	if err != nil {
+		return nil, err
+	}
+	// the adjusters return the trace even when they fail
+	traceA, _ = qs.Adjust(traceA)
+	traceB, _ = qs.Adjust(traceB)
+	return tracediff.Diff(traceA, traceB), nil
+}
+
+// DiffTraceSets compares the traces found by two queries, after applying the adjusters to them.
+func (qs QueryService) DiffTraceSets(ctx context.Context, queryA, queryB *spanstore.TraceQueryParameters) (*api_v2.TraceSetDiff, error) {
+	tracesA, err := qs.FindTraces(ctx, queryA)
+	if err != nil {
+		return nil, err
+	}
+	tracesB, err := qs.FindTraces(ctx, queryB)
+	if err != nil {
+		return nil, err
+	}
+	for _, traces := range [][]*model.Trace{tracesA, tracesB} {
+		for i, trace := range traces {
+			traces[i], _ = qs.Adjust(trace)
+		}
+	}
+	return tracediff.DiffSets(tracesA, tracesB), nil
+}
+
 // ArchiveTrace is the queryService utility to archive traces.
 func (qs QueryService) ArchiveTrace(ctx context.Context, traceID model.TraceID) error {
+	if err := qs.checkTenant(ctx); err != nil {
+		return err
+	}
 	if qs.options.ArchiveSpanWriter == nil {
 		return errNoArchiveSpanStorage
 	}
@@ -122,9 +214,32 @@ func (qs QueryService) Adjust(trace *model.Trace) (*model.Trace, error) {
 
 // GetDependencies implements dependencystore.Reader.GetDependencies
 func (qs QueryService) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
 	return qs.dependencyReader.GetDependencies(ctx, endTs, lookback)
 }
 
+// GetOperationDependencies is the queryService implementation of dependencystore.OperationReader.
+// It returns dependencystore.ErrOperationDependenciesNotSupported if the dependency reader does not support it.
+func (qs QueryService) GetOperationDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.OperationDependencyLink, error) {
+	if err := qs.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+	opReader, ok := qs.dependencyReader.(dependencystore.OperationReader)
+	if !ok {
+		return nil, dependencystore.ErrOperationDependenciesNotSupported
+	}
+	return opReader.GetOperationDependencies(ctx, endTs, lookback)
+}
+
+// checkTenant rejects the calls whose context does not carry a valid tenant.
+// The tenant is put in the context by the HTTP and gRPC handlers, and used by the storage to isolate the data.
+func (qs QueryService) checkTenant(ctx context.Context) error {
+	_, err := qs.options.TenancyMgr.Tenant(tenancy.GetTenant(ctx))
+	return err
+}
+
 // InitArchiveStorage tries to initialize archive storage reader/writer if storage factory supports them.
 func (opts *QueryServiceOptions) InitArchiveStorage(storageFactory storage.Factory, logger *zap.Logger) bool {
 	archiveFactory, ok := storageFactory.(storage.ArchiveFactory)
@@ -22,11 +22,15 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	depsmocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
@@ -94,6 +98,16 @@ func withAdjuster() testOption {
 	}
 }
 
+func withTenancy(tenants ...string) testOption {
+	return func(tqs *testQueryService, options *QueryServiceOptions) {
+		options.TenancyMgr = tenancy.NewManager(&tenancy.Options{
+			Enabled:  true,
+			Tenants:  tenants,
+			Required: true,
+		})
+	}
+}
+
 func initializeTestService(optionAppliers ...testOption) *testQueryService {
 	readStorage := &spanstoremocks.Reader{}
 	dependencyStorage := &depsmocks.Reader{}
@@ -205,6 +219,121 @@ func TestFindTraces(t *testing.T) {
 	assert.Len(t, traces, 1)
 }
 
+// Test QueryService.FindSpans()
+func TestFindSpans(t *testing.T) {
+	spanFinder := &spanstoremocks.SpanFinder{}
+	reader := struct {
+		*spanstoremocks.Reader
+		*spanstoremocks.SpanFinder
+	}{&spanstoremocks.Reader{}, spanFinder}
+	qs := NewQueryService(reader, &depsmocks.Reader{}, QueryServiceOptions{})
+
+	params := &spanstore.SpanQueryParameters{ServiceName: "service", SortBy: spanstore.SortByDuration}
+	spanFinder.On("FindSpans", mock.Anything, params).Return(mockTrace.Spans, nil).Once()
+	spans, err := qs.FindSpans(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, mockTrace.Spans, spans)
+}
+
+func TestFindSpansNotSupported(t *testing.T) {
+	tqs := initializeTestService()
+	_, err := tqs.queryService.FindSpans(context.Background(), &spanstore.SpanQueryParameters{})
+	assert.Equal(t, spanstore.ErrFindSpansNotSupported, err)
+}
+
+// Test QueryService.GetSpanStats()
+func TestGetSpanStats(t *testing.T) {
+	statsReader := &spanstoremocks.SpanStatsReader{}
+	reader := struct {
+		*spanstoremocks.Reader
+		*spanstoremocks.SpanStatsReader
+	}{&spanstoremocks.Reader{}, statsReader}
+	qs := NewQueryService(reader, &depsmocks.Reader{}, QueryServiceOptions{})
+
+	params := &spanstore.SpanStatsQueryParameters{ServiceName: "service", GroupByOperation: true}
+	expected := []spanstore.SpanStats{{ServiceName: "service", OperationName: "operation", Count: 1}}
+	statsReader.On("GetSpanStats", mock.Anything, params).Return(expected, nil).Once()
+	stats, err := qs.GetSpanStats(context.Background(), params)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	tqs := initializeTestService()
+	_, err = tqs.queryService.GetSpanStats(context.Background(), params)
+	assert.Equal(t, spanstore.ErrSpanStatsNotSupported, err)
+}
+
+// Test QueryService.GetCriticalPath()
+func TestGetCriticalPath(t *testing.T) {
+	tqs := initializeTestService(withAdjuster())
+	trace := &model.Trace{
+		Spans: []*model.Span{
+			{
+				TraceID:   mockTraceID,
+				SpanID:    model.NewSpanID(1),
+				StartTime: time.Unix(10, 0),
+				Duration:  time.Second,
+			},
+		},
+	}
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(trace, nil).Once()
+
+	path, err := tqs.queryService.GetCriticalPath(context.Background(), mockTraceID)
+	assert.NoError(t, err)
+	assert.Equal(t, []criticalpath.Segment{
+		{SpanID: model.NewSpanID(1), StartTime: time.Unix(10, 0), Duration: time.Second},
+	}, path)
+
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	_, err = tqs.queryService.GetCriticalPath(context.Background(), mockTraceID)
+	assert.Equal(t, spanstore.ErrTraceNotFound, err)
+}
+
+// Test QueryService.DiffTraces()
+func TestDiffTraces(t *testing.T) {
+	tqs := initializeTestService(withAdjuster())
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Twice()
+
+	diff, err := tqs.queryService.DiffTraces(context.Background(), mockTraceID, mockTraceID)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Spans, 2)
+	for _, span := range diff.Spans {
+		assert.Equal(t, api_v2.SpanDiff_MATCHED, span.Status)
+	}
+
+	otherTraceID := model.NewTraceID(0, 1)
+	tqs.spanReader.On("GetTrace", mock.Anything, mockTraceID).Return(mockTrace, nil).Once()
+	tqs.spanReader.On("GetTrace", mock.Anything, otherTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	_, err = tqs.queryService.DiffTraces(context.Background(), mockTraceID, otherTraceID)
+	assert.Equal(t, spanstore.ErrTraceNotFound, err)
+
+	tqs.spanReader.On("GetTrace", mock.Anything, otherTraceID).Return(nil, spanstore.ErrTraceNotFound).Once()
+	_, err = tqs.queryService.DiffTraces(context.Background(), otherTraceID, mockTraceID)
+	assert.Equal(t, spanstore.ErrTraceNotFound, err)
+}
+
+// Test QueryService.DiffTraceSets()
+func TestDiffTraceSets(t *testing.T) {
+	tqs := initializeTestService(withAdjuster())
+	queryA := &spanstore.TraceQueryParameters{ServiceName: "a"}
+	queryB := &spanstore.TraceQueryParameters{ServiceName: "b"}
+	tqs.spanReader.On("FindTraces", mock.Anything, queryA).Return([]*model.Trace{mockTrace}, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, queryB).Return([]*model.Trace{mockTrace, mockTrace}, nil).Once()
+
+	diff, err := tqs.queryService.DiffTraceSets(context.Background(), queryA, queryB)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, diff.TracesA.Count)
+	assert.EqualValues(t, 2, diff.TracesB.Count)
+
+	tqs.spanReader.On("FindTraces", mock.Anything, queryA).Return(nil, errors.New("find error")).Once()
+	_, err = tqs.queryService.DiffTraceSets(context.Background(), queryA, queryB)
+	assert.EqualError(t, err, "find error")
+
+	tqs.spanReader.On("FindTraces", mock.Anything, queryA).Return(nil, nil).Once()
+	tqs.spanReader.On("FindTraces", mock.Anything, queryB).Return(nil, errors.New("find error")).Once()
+	_, err = tqs.queryService.DiffTraceSets(context.Background(), queryA, queryB)
+	assert.EqualError(t, err, "find error")
+}
+
 // Test QueryService.ArchiveTrace() with no ArchiveSpanWriter.
 func TestArchiveTraceNoOptions(t *testing.T) {
 	tqs := initializeTestService()
@@ -286,6 +415,26 @@ func TestGetDependencies(t *testing.T) {
 	assert.Equal(t, expectedDependencies, actualDependencies)
 }
 
+func TestGetOperationDependencies(t *testing.T) {
+	opReader := &depsmocks.OperationReader{}
+	reader := struct {
+		*depsmocks.Reader
+		*depsmocks.OperationReader
+	}{&depsmocks.Reader{}, opReader}
+	qs := NewQueryService(&spanstoremocks.Reader{}, reader, QueryServiceOptions{})
+
+	endTs := time.Unix(0, 1476374248550*millisToNanosMultiplier)
+	expected := []model.OperationDependencyLink{{Parent: "killer", ParentOperation: "a", Child: "queen", ChildOperation: "b", CallCount: 12}}
+	opReader.On("GetOperationDependencies", endTs, defaultDependencyLookbackDuration).Return(expected, nil).Once()
+	links, err := qs.GetOperationDependencies(context.Background(), endTs, defaultDependencyLookbackDuration)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, links)
+
+	tqs := initializeTestService()
+	_, err = tqs.queryService.GetOperationDependencies(context.Background(), endTs, defaultDependencyLookbackDuration)
+	assert.Equal(t, dependencystore.ErrOperationDependenciesNotSupported, err)
+}
+
 type fakeStorageFactory1 struct {
 }
 
@@ -310,6 +459,68 @@ func (f *fakeStorageFactory2) CreateArchiveSpanWriter() (spanstore.Writer, error
 var _ storage.Factory = new(fakeStorageFactory1)
 var _ storage.ArchiveFactory = new(fakeStorageFactory2)
 
+func TestQueryServiceRejectsMissingOrInvalidTenant(t *testing.T) {
+	tqs := initializeTestService(withTenancy("acme"), withArchiveSpanWriter())
+	now := time.Now()
+	calls := map[string]func(ctx context.Context) error{
+		"GetTrace": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetTrace(ctx, mockTraceID)
+			return err
+		},
+		"GetServices": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetServices(ctx)
+			return err
+		},
+		"GetOperations": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "abc"})
+			return err
+		},
+		"FindTraces": func(ctx context.Context) error {
+			_, err := tqs.queryService.FindTraces(ctx, &spanstore.TraceQueryParameters{})
+			return err
+		},
+		"FindSpans": func(ctx context.Context) error {
+			_, err := tqs.queryService.FindSpans(ctx, &spanstore.SpanQueryParameters{})
+			return err
+		},
+		"GetSpanStats": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetSpanStats(ctx, &spanstore.SpanStatsQueryParameters{})
+			return err
+		},
+		"ArchiveTrace": func(ctx context.Context) error {
+			return tqs.queryService.ArchiveTrace(ctx, mockTraceID)
+		},
+		"GetDependencies": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetDependencies(ctx, now, time.Hour)
+			return err
+		},
+		"GetOperationDependencies": func(ctx context.Context) error {
+			_, err := tqs.queryService.GetOperationDependencies(ctx, now, time.Hour)
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, call(context.Background()), tenancy.ErrMissingTenant)
+			assert.ErrorIs(t, call(tenancy.WithTenant(context.Background(), "other")), tenancy.ErrInvalidTenant)
+		})
+	}
+	// the storage must never be reached
+	tqs.spanReader.AssertExpectations(t)
+	tqs.archiveSpanWriter.AssertExpectations(t)
+	tqs.depsReader.AssertExpectations(t)
+}
+
+func TestQueryServiceAcceptsValidTenant(t *testing.T) {
+	tqs := initializeTestService(withTenancy("acme"))
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	tqs.spanReader.On("GetServices", ctx).Return([]string{"abc"}, nil).Once()
+
+	services, err := tqs.queryService.GetServices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, services)
+}
+
 func TestInitArchiveStorageErrors(t *testing.T) {
 	opts := &QueryServiceOptions{}
 	logger := zap.NewNop()
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracediff
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+type operationKey struct {
+	service   string
+	operation string
+}
+
+type durations struct {
+	traces     []time.Duration
+	operations map[operationKey][]time.Duration
+}
+
+// DiffSets compares the durations of two sets of traces, e.g. the traces before and after a deploy,
+// overall and by operation.
+func DiffSets(a, b []*model.Trace) *api_v2.TraceSetDiff {
+	durationsA, durationsB := collectDurations(a), collectDurations(b)
+	keys := make([]operationKey, 0, len(durationsA.operations))
+	for key := range durationsA.operations {
+		keys = append(keys, key)
+	}
+	for key := range durationsB.operations {
+		if _, ok := durationsA.operations[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].service != keys[j].service {
+			return keys[i].service < keys[j].service
+		}
+		return keys[i].operation < keys[j].operation
+	})
+	diff := &api_v2.TraceSetDiff{
+		TracesA:    newDurationStats(durationsA.traces),
+		TracesB:    newDurationStats(durationsB.traces),
+		Operations: make([]api_v2.OperationDiff, len(keys)),
+	}
+	for i, key := range keys {
+		statsA := newDurationStats(durationsA.operations[key])
+		statsB := newDurationStats(durationsB.operations[key])
+		diff.Operations[i] = api_v2.OperationDiff{
+			ServiceName:   key.service,
+			OperationName: key.operation,
+			A:             statsA,
+			B:             statsB,
+			MeanDelta:     statsB.Mean - statsA.Mean,
+			P50Delta:      statsB.P50 - statsA.P50,
+			P90Delta:      statsB.P90 - statsA.P90,
+			P99Delta:      statsB.P99 - statsA.P99,
+		}
+	}
+	return diff
+}
+
+func collectDurations(traces []*model.Trace) durations {
+	d := durations{operations: make(map[operationKey][]time.Duration)}
+	for _, trace := range traces {
+		if len(trace.Spans) == 0 {
+			continue
+		}
+		d.traces = append(d.traces, traceDuration(trace))
+		for _, span := range trace.Spans {
+			key := operationKey{service: span.Process.GetServiceName(), operation: span.OperationName}
+			d.operations[key] = append(d.operations[key], span.Duration)
+		}
+	}
+	return d
+}
+
+func newDurationStats(values []time.Duration) api_v2.DurationStats {
+	stats := api_v2.DurationStats{Count: int64(len(values))}
+	if len(values) == 0 {
+		return stats
+	}
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, v := range sorted {
+		sum += v
+	}
+	stats.Mean = sum / time.Duration(len(sorted))
+	stats.P50 = percentile(sorted, 0.5)
+	stats.P90 = percentile(sorted, 0.9)
+	stats.P99 = percentile(sorted, 0.99)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
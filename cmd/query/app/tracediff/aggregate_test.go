@@ -0,0 +1,95 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracediff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+func TestDiffSets(t *testing.T) {
+	trace := func(rootDuration, childDuration time.Duration) *model.Trace {
+		return newTrace(
+			spanSpec{id: 1, service: "frontend", operation: "GET", duration: rootDuration},
+			spanSpec{id: 2, parent: 1, service: "driver", operation: "find", duration: childDuration},
+		)
+	}
+	before := []*model.Trace{
+		trace(10*time.Millisecond, 1*time.Millisecond),
+		trace(20*time.Millisecond, 2*time.Millisecond),
+		trace(30*time.Millisecond, 3*time.Millisecond),
+		{},
+	}
+	after := []*model.Trace{
+		trace(40*time.Millisecond, 4*time.Millisecond),
+		newTrace(spanSpec{id: 1, service: "frontend", operation: "POST", duration: 50 * time.Millisecond}),
+	}
+
+	diff := DiffSets(before, after)
+	assert.Equal(t, api_v2.DurationStats{
+		Count: 3,
+		Mean:  20 * time.Millisecond,
+		P50:   20 * time.Millisecond,
+		P90:   30 * time.Millisecond,
+		P99:   30 * time.Millisecond,
+	}, diff.TracesA)
+	assert.Equal(t, int64(2), diff.TracesB.Count)
+	assert.Equal(t, 45*time.Millisecond, diff.TracesB.Mean)
+
+	require.Len(t, diff.Operations, 3)
+	assert.Equal(t, api_v2.OperationDiff{
+		ServiceName:   "driver",
+		OperationName: "find",
+		A: api_v2.DurationStats{
+			Count: 3,
+			Mean:  2 * time.Millisecond,
+			P50:   2 * time.Millisecond,
+			P90:   3 * time.Millisecond,
+			P99:   3 * time.Millisecond,
+		},
+		B: api_v2.DurationStats{
+			Count: 1,
+			Mean:  4 * time.Millisecond,
+			P50:   4 * time.Millisecond,
+			P90:   4 * time.Millisecond,
+			P99:   4 * time.Millisecond,
+		},
+		MeanDelta: 2 * time.Millisecond,
+		P50Delta:  2 * time.Millisecond,
+		P90Delta:  1 * time.Millisecond,
+		P99Delta:  1 * time.Millisecond,
+	}, diff.Operations[0])
+	assert.Equal(t, "GET", diff.Operations[1].OperationName)
+	assert.Equal(t, "POST", diff.Operations[2].OperationName)
+	assert.Zero(t, diff.Operations[2].A.Count)
+	assert.Equal(t, 50*time.Millisecond, diff.Operations[2].MeanDelta)
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(1), percentile(values, 0))
+	assert.Equal(t, time.Duration(50), percentile(values, 0.5))
+	assert.Equal(t, time.Duration(99), percentile(values, 0.99))
+	assert.Equal(t, time.Duration(100), percentile(values, 1))
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracediff compares traces, one to one or as sets of traces.
+package tracediff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+type differ struct {
+	criticalPathA map[model.SpanID]bool
+	criticalPathB map[model.SpanID]bool
+	spans         []api_v2.SpanDiff
+}
+
+// Diff compares two traces. The span trees of the traces are aligned by the service and
+// operation names of the spans: the n-th child span with a given name of a span is compared
+// with the n-th child span with the same name of the aligned span in the other trace.
+func Diff(a, b *model.Trace) *api_v2.TraceDiff {
+	rootsA, rootsB := buildTree(a), buildTree(b)
+	d := &differ{
+		criticalPathA: criticalPath(a),
+		criticalPathB: criticalPath(b),
+	}
+	d.align("", rootsA, rootsB)
+	return &api_v2.TraceDiff{
+		DurationA: traceDuration(a),
+		DurationB: traceDuration(b),
+		Spans:     d.spans,
+	}
+}
+
+// align compares the sibling spans of both trees, then their children recursively.
+func (d *differ) align(parentPath string, a, b []*node) {
+	groupsA, namesA := groupByName(a)
+	groupsB, namesB := groupByName(b)
+	names := namesA
+	for _, name := range namesB {
+		if _, ok := groupsA[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		nodesA, nodesB := groupsA[name], groupsB[name]
+		for i := 0; i < len(nodesA) || i < len(nodesB); i++ {
+			path := parentPath + "/" + name
+			if i > 0 {
+				path += fmt.Sprintf("[%d]", i)
+			}
+			var nodeA, nodeB *node
+			if i < len(nodesA) {
+				nodeA = nodesA[i]
+			}
+			if i < len(nodesB) {
+				nodeB = nodesB[i]
+			}
+			d.spans = append(d.spans, d.diffSpans(path, nodeA, nodeB))
+			d.align(path, children(nodeA), children(nodeB))
+		}
+	}
+}
+
+func (d *differ) diffSpans(path string, a, b *node) api_v2.SpanDiff {
+	diff := api_v2.SpanDiff{Path: path}
+	switch {
+	case a == nil:
+		diff.Status = api_v2.SpanDiff_ADDED
+	case b == nil:
+		diff.Status = api_v2.SpanDiff_MISSING
+	default:
+		diff.Status = api_v2.SpanDiff_MATCHED
+		diff.DurationDelta = b.span.Duration - a.span.Duration
+		diff.ChangedTags = diffTags(a.span.Tags, b.span.Tags)
+	}
+	if a != nil {
+		diff.ServiceName = a.span.Process.GetServiceName()
+		diff.OperationName = a.span.OperationName
+		diff.SpanIDA = a.span.SpanID
+		diff.DurationA = a.span.Duration
+		diff.CriticalPathA = d.criticalPathA[a.span.SpanID]
+	}
+	if b != nil {
+		diff.ServiceName = b.span.Process.GetServiceName()
+		diff.OperationName = b.span.OperationName
+		diff.SpanIDB = b.span.SpanID
+		diff.DurationB = b.span.Duration
+		diff.CriticalPathB = d.criticalPathB[b.span.SpanID]
+	}
+	return diff
+}
+
+// groupByName groups the nodes by name, and returns the names in the order of their first node.
+func groupByName(nodes []*node) (map[string][]*node, []string) {
+	groups := make(map[string][]*node)
+	var names []string
+	for _, n := range nodes {
+		name := n.name()
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], n)
+	}
+	return groups, names
+}
+
+func children(n *node) []*node {
+	if n == nil {
+		return nil
+	}
+	return n.children
+}
+
+// diffTags returns the tags with a different value in a and b, sorted by key.
+func diffTags(a, b model.KeyValues) []api_v2.TagDiff {
+	valuesA, valuesB := tagValues(a), tagValues(b)
+	var diffs []api_v2.TagDiff
+	for key, valueA := range valuesA {
+		if valueB, ok := valuesB[key]; !ok || valueA != valueB {
+			diffs = append(diffs, api_v2.TagDiff{Key: key, ValueA: valueA, ValueB: valueB})
+		}
+	}
+	for key, valueB := range valuesB {
+		if _, ok := valuesA[key]; !ok {
+			diffs = append(diffs, api_v2.TagDiff{Key: key, ValueB: valueB})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+func tagValues(tags model.KeyValues) map[string]string {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		values[tag.Key] = tag.AsString()
+	}
+	return values
+}
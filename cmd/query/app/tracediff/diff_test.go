@@ -0,0 +1,152 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracediff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+)
+
+var baseTime = time.Unix(1000, 0)
+
+type spanSpec struct {
+	id        uint64
+	parent    uint64
+	service   string
+	operation string
+	start     time.Duration
+	duration  time.Duration
+	tags      model.KeyValues
+}
+
+func newTrace(specs ...spanSpec) *model.Trace {
+	trace := &model.Trace{}
+	for _, s := range specs {
+		span := &model.Span{
+			TraceID:       model.NewTraceID(0, 1),
+			SpanID:        model.NewSpanID(s.id),
+			OperationName: s.operation,
+			StartTime:     baseTime.Add(s.start),
+			Duration:      s.duration,
+			Process:       model.NewProcess(s.service, nil),
+			Tags:          s.tags,
+		}
+		if s.parent != 0 {
+			span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, model.NewSpanID(s.parent))}
+		}
+		trace.Spans = append(trace.Spans, span)
+	}
+	return trace
+}
+
+func TestDiff(t *testing.T) {
+	a := newTrace(
+		spanSpec{id: 1, service: "frontend", operation: "GET", duration: 100 * time.Millisecond, tags: model.KeyValues{model.String("version", "1")}},
+		spanSpec{id: 2, parent: 1, service: "driver", operation: "find", start: 10 * time.Millisecond, duration: 20 * time.Millisecond},
+		spanSpec{id: 3, parent: 1, service: "driver", operation: "find", start: 40 * time.Millisecond, duration: 50 * time.Millisecond},
+		spanSpec{id: 4, parent: 1, service: "redis", operation: "get", start: 5 * time.Millisecond, duration: 5 * time.Millisecond},
+	)
+	b := newTrace(
+		spanSpec{id: 11, service: "frontend", operation: "GET", duration: 150 * time.Millisecond, tags: model.KeyValues{model.String("version", "2"), model.Bool("error", true)}},
+		spanSpec{id: 12, parent: 11, service: "driver", operation: "find", start: 10 * time.Millisecond, duration: 130 * time.Millisecond},
+		spanSpec{id: 13, parent: 11, service: "mysql", operation: "select", start: 20 * time.Millisecond, duration: 10 * time.Millisecond},
+	)
+
+	diff := Diff(a, b)
+	assert.Equal(t, 100*time.Millisecond, diff.DurationA)
+	assert.Equal(t, 150*time.Millisecond, diff.DurationB)
+	expected := []api_v2.SpanDiff{
+		{
+			Path:          "/frontend::GET",
+			ServiceName:   "frontend",
+			OperationName: "GET",
+			Status:        api_v2.SpanDiff_MATCHED,
+			SpanIDA:       model.NewSpanID(1),
+			SpanIDB:       model.NewSpanID(11),
+			DurationA:     100 * time.Millisecond,
+			DurationB:     150 * time.Millisecond,
+			DurationDelta: 50 * time.Millisecond,
+			ChangedTags: []api_v2.TagDiff{
+				{Key: "error", ValueB: "true"},
+				{Key: "version", ValueA: "1", ValueB: "2"},
+			},
+			CriticalPathA: true,
+			CriticalPathB: true,
+		},
+		{
+			Path:          "/frontend::GET/redis::get",
+			ServiceName:   "redis",
+			OperationName: "get",
+			Status:        api_v2.SpanDiff_MISSING,
+			SpanIDA:       model.NewSpanID(4),
+			DurationA:     5 * time.Millisecond,
+			CriticalPathA: true,
+		},
+		{
+			Path:          "/frontend::GET/driver::find",
+			ServiceName:   "driver",
+			OperationName: "find",
+			Status:        api_v2.SpanDiff_MATCHED,
+			SpanIDA:       model.NewSpanID(2),
+			SpanIDB:       model.NewSpanID(12),
+			DurationA:     20 * time.Millisecond,
+			DurationB:     130 * time.Millisecond,
+			DurationDelta: 110 * time.Millisecond,
+			CriticalPathA: true,
+			CriticalPathB: true,
+		},
+		{
+			Path:          "/frontend::GET/driver::find[1]",
+			ServiceName:   "driver",
+			OperationName: "find",
+			Status:        api_v2.SpanDiff_MISSING,
+			SpanIDA:       model.NewSpanID(3),
+			DurationA:     50 * time.Millisecond,
+			CriticalPathA: true,
+		},
+		{
+			Path:          "/frontend::GET/mysql::select",
+			ServiceName:   "mysql",
+			OperationName: "select",
+			Status:        api_v2.SpanDiff_ADDED,
+			SpanIDB:       model.NewSpanID(13),
+			DurationB:     10 * time.Millisecond,
+		},
+	}
+	require.Len(t, diff.Spans, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i], diff.Spans[i], expected[i].Path)
+	}
+}
+
+func TestDiffIdenticalTraces(t *testing.T) {
+	trace := newTrace(
+		spanSpec{id: 1, service: "frontend", operation: "GET", duration: time.Second},
+		spanSpec{id: 2, parent: 1, service: "driver", operation: "find", duration: time.Millisecond},
+	)
+	diff := Diff(trace, trace)
+	require.Len(t, diff.Spans, 2)
+	for _, span := range diff.Spans {
+		assert.Equal(t, api_v2.SpanDiff_MATCHED, span.Status)
+		assert.Zero(t, span.DurationDelta)
+		assert.Empty(t, span.ChangedTags)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracediff
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
+)
+
+// node is a span of the span tree of a trace.
+type node struct {
+	span     *model.Span
+	children []*node
+}
+
+func (n *node) name() string {
+	return n.span.Process.GetServiceName() + "::" + n.span.OperationName
+}
+
+// buildTree returns the root spans of the trace, i.e. the spans whose parent is not in the trace.
+// The children of each span are sorted by start time.
+func buildTree(trace *model.Trace) []*node {
+	nodes := make(map[model.SpanID]*node, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if _, ok := nodes[span.SpanID]; !ok {
+			nodes[span.SpanID] = &node{span: span}
+		}
+	}
+	var roots []*node
+	for _, span := range trace.Spans {
+		n := nodes[span.SpanID]
+		if n.span != span {
+			// duplicate span ID, the first span wins
+			continue
+		}
+		parent, ok := nodes[span.ParentSpanID()]
+		if !ok || parent == n {
+			roots = append(roots, n)
+			continue
+		}
+		parent.children = append(parent.children, n)
+	}
+	sortNodes(roots)
+	for _, n := range nodes {
+		sortNodes(n.children)
+	}
+	return roots
+}
+
+func sortNodes(nodes []*node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if !nodes[i].span.StartTime.Equal(nodes[j].span.StartTime) {
+			return nodes[i].span.StartTime.Before(nodes[j].span.StartTime)
+		}
+		return nodes[i].name() < nodes[j].name()
+	})
+}
+
+// criticalPath returns the IDs of the spans on the critical path of the trace.
+func criticalPath(trace *model.Trace) map[model.SpanID]bool {
+	path := make(map[model.SpanID]bool)
+	for _, segment := range criticalpath.Compute(trace) {
+		path[segment.SpanID] = true
+	}
+	return path
+}
+
+// traceDuration returns the time between the start of the first span and the end of the last span.
+func traceDuration(trace *model.Trace) time.Duration {
+	var start, end time.Time
+	for i, span := range trace.Spans {
+		spanEnd := span.StartTime.Add(span.Duration)
+		if i == 0 || span.StartTime.Before(start) {
+			start = span.StartTime
+		}
+		if i == 0 || spanEnd.After(end) {
+			end = spanEnd
+		}
+	}
+	return end.Sub(start)
+}
@@ -36,10 +36,12 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/query/app/querysvc"
 	"github.com/jaegertracing/jaeger/cmd/status"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/version"
 	metricsPlugin "github.com/jaegertracing/jaeger/plugin/metrics"
 	"github.com/jaegertracing/jaeger/plugin/storage"
 	"github.com/jaegertracing/jaeger/ports"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	storageMetrics "github.com/jaegertracing/jaeger/storage/spanstore/metrics"
 )
@@ -100,6 +102,11 @@ func main() {
 			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
 				logger.Fatal("Failed to init storage factory", zap.Error(err))
 			}
+			if queryOpts.Tenancy.Enabled {
+				if err := storageFactory.ValidateTenantIsolation(); err != nil {
+					logger.Fatal("Failed to enable multi-tenancy", zap.Error(err))
+				}
+			}
 			spanReader, err := storageFactory.CreateSpanReader()
 			if err != nil {
 				logger.Fatal("Failed to create span reader", zap.Error(err))
@@ -110,6 +117,24 @@ func main() {
 				logger.Fatal("Failed to create dependency reader", zap.Error(err))
 			}
 
+			spanMetricsReader, err := createSpanMetricsReader(storageFactory)
+			if err != nil {
+				logger.Fatal("Failed to create span metrics reader", zap.Error(err))
+			}
+			if spanMetricsReader != nil {
+				metricsReaderFactory.SetSpanMetricsReader(spanMetricsReader)
+			} else if metricsReaderFactory.UsesSpanMetrics() {
+				logger.Fatal("The span metrics are not persisted by the span storage: "+
+					"use a span storage that stores them, e.g. elasticsearch or badger, or another "+metricsPlugin.StorageTypeEnvVar,
+					zap.String("span-storage", storageFactory.SpanReaderType))
+			}
+			storageMetricsFactory, err := storageFactory.CreateMetricsFactory()
+			if err != nil {
+				logger.Fatal("Failed to create storage metrics factory", zap.Error(err))
+			}
+			if storageMetricsFactory != nil {
+				metricsReaderFactory.SetStorageMetricsFactory(storageMetricsFactory)
+			}
 			metricsQueryService, err := createMetricsQueryService(metricsReaderFactory, v, logger)
 			if err != nil {
 				logger.Fatal("Failed to create metrics query service", zap.Error(err))
@@ -156,6 +181,7 @@ func main() {
 		storageFactory.AddFlags,
 		app.AddFlags,
 		metricsReaderFactory.AddFlags,
+		tenancy.AddFlags,
 	)
 
 	if err := command.Execute(); err != nil {
@@ -173,3 +199,11 @@ func createMetricsQueryService(factory *metricsPlugin.Factory, v *viper.Viper, l
 	factory.InitFromViper(v, logger)
 	return factory.CreateMetricsReader()
 }
+
+func createSpanMetricsReader(storageFactory *storage.Factory) (metricsstore.SpanMetricsReader, error) {
+	smFactory, err := storageFactory.CreateSpanMetricsStoreFactory()
+	if err != nil || smFactory == nil {
+		return nil, err
+	}
+	return smFactory.CreateSpanMetricsReader()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"flag"
+
+	"github.com/spf13/viper"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/ports"
+)
+
+const (
+	flagGRPCHostPort = "grpc.host-port"
+)
+
+var tlsGRPCFlagsConfig = tlscfg.ServerFlagsConfig{
+	Prefix: "grpc",
+}
+
+// Options holds the configuration of the remote storage server
+type Options struct {
+	// GRPCHostPort is the host:port address the gRPC storage services are served on
+	GRPCHostPort string
+	// TLSGRPC configures secure transport (Jaeger components to the remote storage server)
+	TLSGRPC tlscfg.Options
+}
+
+// AddFlags adds flags for Options
+func AddFlags(flagSet *flag.FlagSet) {
+	flagSet.String(flagGRPCHostPort, ports.PortToHostPort(ports.RemoteStorageGRPC), "The host:port (e.g. 127.0.0.1:17271 or :17271) of the gRPC storage server")
+	tlsGRPCFlagsConfig.AddFlags(flagSet)
+}
+
+// InitFromViper initializes Options with properties from viper
+func (o *Options) InitFromViper(v *viper.Viper) *Options {
+	o.GRPCHostPort = ports.FormatHostPort(v.GetString(flagGRPCHostPort))
+	o.TLSGRPC = tlsGRPCFlagsConfig.InitFromViper(v)
+	return o
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--grpc.host-port=127.0.0.1:8081",
+		"--grpc.tls.enabled=true",
+	})
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, "127.0.0.1:8081", opts.GRPCHostPort)
+	assert.True(t, opts.TLSGRPC.Enabled)
+}
+
+func TestDefaultFlags(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	opts := new(Options).InitFromViper(v)
+	assert.Equal(t, ":17271", opts.GRPCHostPort)
+	assert.False(t, opts.TLSGRPC.Enabled)
+}
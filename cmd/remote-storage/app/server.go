@@ -0,0 +1,236 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"net"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/netutils"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+// Server exposes a storage.Factory over the gRPC storage plugin protocol,
+// so that several Jaeger components can share the same storage backend.
+type Server struct {
+	logger             *zap.Logger
+	options            *Options
+	grpcConn           net.Listener
+	grpcServer         *grpc.Server
+	healthServer       *health.Server
+	unavailableChannel chan healthcheck.Status
+}
+
+// NewServer creates the storage components of the factory and the gRPC server exposing them
+func NewServer(options *Options, storageFactory storage.Factory, logger *zap.Logger) (*Server, error) {
+	services, err := createPluginServices(storageFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	var grpcOpts []grpc.ServerOption
+	if options.TLSGRPC.Enabled {
+		tlsCfg, err := options.TLSGRPC.Config(logger)
+		if err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	plugin := &shared.StorageGRPCPlugin{
+		Impl:                   services.Store,
+		ArchiveImpl:            services.ArchiveStore,
+		DependenciesWriterImpl: services.DependenciesWriter,
+		SamplingStoreImpl:      services.SamplingStore,
+	}
+	if err := plugin.GRPCServer(nil, grpcServer); err != nil {
+		return nil, err
+	}
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+
+	return &Server{
+		logger:             logger,
+		options:            options,
+		grpcServer:         grpcServer,
+		healthServer:       healthServer,
+		unavailableChannel: make(chan healthcheck.Status),
+	}, nil
+}
+
+func createPluginServices(storageFactory storage.Factory) (*shared.PluginServices, error) {
+	reader, err := storageFactory.CreateSpanReader()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := storageFactory.CreateSpanWriter()
+	if err != nil {
+		return nil, err
+	}
+	depReader, err := storageFactory.CreateDependencyReader()
+	if err != nil {
+		return nil, err
+	}
+	services := &shared.PluginServices{
+		Store: &storagePlugin{
+			reader:    reader,
+			writer:    unclosableWriter{writer},
+			depReader: depReader,
+		},
+	}
+	if depWriter, ok := depReader.(dependencystore.Writer); ok {
+		services.DependenciesWriter = &dependenciesWriterPlugin{writer: depWriter}
+	}
+
+	if samplingFactory, ok := storageFactory.(storage.SamplingStoreFactory); ok {
+		lock, err := samplingFactory.CreateLock()
+		if err != nil {
+			return nil, err
+		}
+		store, err := samplingFactory.CreateSamplingStore()
+		if err != nil {
+			return nil, err
+		}
+		services.SamplingStore = &samplingStorePlugin{store: store, lock: lock}
+	}
+
+	archiveFactory, ok := storageFactory.(storage.ArchiveFactory)
+	if !ok {
+		return services, nil
+	}
+	archiveReader, err := archiveFactory.CreateArchiveSpanReader()
+	if errors.Is(err, storage.ErrArchiveStorageNotSupported) || errors.Is(err, storage.ErrArchiveStorageNotConfigured) {
+		return services, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	archiveWriter, err := archiveFactory.CreateArchiveSpanWriter()
+	if err != nil {
+		return nil, err
+	}
+	services.ArchiveStore = &archiveStoragePlugin{
+		reader: archiveReader,
+		writer: unclosableWriter{archiveWriter},
+	}
+	return services, nil
+}
+
+// HealthCheckStatus returns health check status channel a client can subscribe to
+func (s *Server) HealthCheckStatus() chan healthcheck.Status {
+	return s.unavailableChannel
+}
+
+// Start listens on the gRPC port and serves the storage services
+func (s *Server) Start() error {
+	grpcConn, err := net.Listen("tcp", s.options.GRPCHostPort)
+	if err != nil {
+		return err
+	}
+	s.grpcConn = grpcConn
+
+	var grpcPort int
+	if port, err := netutils.GetPort(s.grpcConn.Addr()); err == nil {
+		grpcPort = port
+	}
+
+	go func() {
+		s.logger.Info("Starting GRPC server", zap.Int("port", grpcPort), zap.String("addr", s.options.GRPCHostPort))
+		if err := s.grpcServer.Serve(s.grpcConn); err != nil {
+			s.logger.Error("Could not start GRPC server", zap.Error(err))
+		}
+		s.unavailableChannel <- healthcheck.Unavailable
+	}()
+	s.healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	return nil
+}
+
+// Close stops the gRPC server, the clients see the server as not serving while the pending calls complete
+func (s *Server) Close() error {
+	s.healthServer.Shutdown()
+	s.grpcServer.GracefulStop()
+	s.options.TLSGRPC.Close()
+	return nil
+}
+
+type storagePlugin struct {
+	reader    spanstore.Reader
+	writer    spanstore.Writer
+	depReader dependencystore.Reader
+}
+
+func (p *storagePlugin) SpanReader() spanstore.Reader {
+	return p.reader
+}
+
+func (p *storagePlugin) SpanWriter() spanstore.Writer {
+	return p.writer
+}
+
+func (p *storagePlugin) DependencyReader() dependencystore.Reader {
+	return p.depReader
+}
+
+type archiveStoragePlugin struct {
+	reader spanstore.Reader
+	writer spanstore.Writer
+}
+
+func (p *archiveStoragePlugin) ArchiveSpanReader() spanstore.Reader {
+	return p.reader
+}
+
+func (p *archiveStoragePlugin) ArchiveSpanWriter() spanstore.Writer {
+	return p.writer
+}
+
+type dependenciesWriterPlugin struct {
+	writer dependencystore.Writer
+}
+
+func (p *dependenciesWriterPlugin) DependencyWriter() dependencystore.Writer {
+	return p.writer
+}
+
+type samplingStorePlugin struct {
+	store samplingstore.Store
+	lock  distributedlock.Lock
+}
+
+func (p *samplingStorePlugin) SamplingStore() samplingstore.Store {
+	return p.store
+}
+
+func (p *samplingStorePlugin) Lock() distributedlock.Lock {
+	return p.lock
+}
+
+// unclosableWriter hides the io.Closer of the writers shared by all the clients,
+// which would otherwise be closed by the Close call of the first client shutting down.
+type unclosableWriter struct {
+	spanstore.Writer
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	lockMocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	grpcConfig "github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage"
+	dependencyStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/mocks"
+	samplingStoreMocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func startServer(t *testing.T, options *Options, storageFactory storage.Factory) *Server {
+	server, err := NewServer(options, storageFactory, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	go func() {
+		for range server.HealthCheckStatus() {
+		}
+	}()
+	return server
+}
+
+func newMemoryFactory(t *testing.T) *memory.Factory {
+	f := memory.NewFactory()
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	return f
+}
+
+func TestServerWriteAndReadSpans(t *testing.T) {
+	server := startServer(t, &Options{GRPCHostPort: "127.0.0.1:0"}, newMemoryFactory(t))
+	defer server.Close()
+
+	cfg := &grpcConfig.Configuration{
+		RemoteServerAddr:     server.grpcConn.Addr().String(),
+		RemoteConnectTimeout: time.Second,
+	}
+	services, err := cfg.Build(zap.NewNop())
+	require.NoError(t, err)
+	defer services.Closer.Close()
+
+	capabilities, err := services.Capabilities.Capabilities()
+	require.NoError(t, err)
+	assert.False(t, capabilities.ArchiveSpanWriter)
+	assert.True(t, capabilities.StreamingSpanWriter)
+
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		SpanID:        model.NewSpanID(1),
+		OperationName: "op",
+		Process:       model.NewProcess("svc", nil),
+		StartTime:     time.Now(),
+	}
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	require.NoError(t, services.Store.SpanWriter().WriteSpan(acme, span))
+
+	trace, err := services.Store.SpanReader().GetTrace(acme, span.TraceID)
+	require.NoError(t, err)
+	require.Len(t, trace.Spans, 1)
+	assert.Equal(t, span.SpanID, trace.Spans[0].SpanID)
+
+	_, err = services.Store.SpanReader().GetTrace(context.Background(), span.TraceID)
+	assert.Error(t, err, "the trace is only visible to its tenant")
+}
+
+func TestServerDoesNotCloseSharedWriter(t *testing.T) {
+	writer := new(spanStoreMocks.Writer)
+	factory := new(mocks.Factory)
+	factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+	factory.On("CreateSpanWriter").Return(writer, nil)
+	factory.On("CreateDependencyReader").Return(nil, nil)
+
+	services, err := createPluginServices(factory)
+	require.NoError(t, err)
+	assert.Equal(t, unclosableWriter{writer}, services.Store.SpanWriter())
+	assert.Nil(t, services.ArchiveStore)
+}
+
+func TestServerArchiveStorage(t *testing.T) {
+	factory := struct {
+		*mocks.Factory
+		*mocks.ArchiveFactory
+	}{new(mocks.Factory), new(mocks.ArchiveFactory)}
+	factory.Factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+	factory.Factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+	factory.Factory.On("CreateDependencyReader").Return(nil, nil)
+	archiveReader, archiveWriter := new(spanStoreMocks.Reader), new(spanStoreMocks.Writer)
+	factory.ArchiveFactory.On("CreateArchiveSpanReader").Return(archiveReader, nil)
+	factory.ArchiveFactory.On("CreateArchiveSpanWriter").Return(archiveWriter, nil)
+
+	services, err := createPluginServices(factory)
+	require.NoError(t, err)
+	require.NotNil(t, services.ArchiveStore)
+	assert.Equal(t, archiveReader, services.ArchiveStore.ArchiveSpanReader())
+	assert.Equal(t, unclosableWriter{archiveWriter}, services.ArchiveStore.ArchiveSpanWriter())
+}
+
+func TestServerArchiveStorageNotSupported(t *testing.T) {
+	factory := struct {
+		*mocks.Factory
+		*mocks.ArchiveFactory
+	}{new(mocks.Factory), new(mocks.ArchiveFactory)}
+	factory.Factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+	factory.Factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+	factory.Factory.On("CreateDependencyReader").Return(nil, nil)
+	factory.ArchiveFactory.On("CreateArchiveSpanReader").Return(nil, storage.ErrArchiveStorageNotConfigured)
+
+	services, err := createPluginServices(factory)
+	require.NoError(t, err)
+	assert.Nil(t, services.ArchiveStore)
+}
+
+func TestServerSamplingStoreAndDependenciesWriter(t *testing.T) {
+	factory := struct {
+		*mocks.Factory
+		*mocks.SamplingStoreFactory
+	}{new(mocks.Factory), new(mocks.SamplingStoreFactory)}
+	depReader := struct {
+		*dependencyStoreMocks.Reader
+		*dependencyStoreMocks.Writer
+	}{new(dependencyStoreMocks.Reader), new(dependencyStoreMocks.Writer)}
+	factory.Factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+	factory.Factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+	factory.Factory.On("CreateDependencyReader").Return(depReader, nil)
+	lock, store := new(lockMocks.Lock), new(samplingStoreMocks.Store)
+	factory.SamplingStoreFactory.On("CreateLock").Return(lock, nil)
+	factory.SamplingStoreFactory.On("CreateSamplingStore").Return(store, nil)
+
+	services, err := createPluginServices(factory)
+	require.NoError(t, err)
+	require.NotNil(t, services.DependenciesWriter)
+	assert.Equal(t, depReader, services.DependenciesWriter.DependencyWriter())
+	require.NotNil(t, services.SamplingStore)
+	assert.Equal(t, lock, services.SamplingStore.Lock())
+	assert.Equal(t, store, services.SamplingStore.SamplingStore())
+}
+
+func TestServerSamplingStoreErrors(t *testing.T) {
+	someErr := errors.New("made-up error")
+	tests := []struct {
+		name  string
+		setup func(f *mocks.SamplingStoreFactory)
+	}{
+		{
+			name: "lock",
+			setup: func(f *mocks.SamplingStoreFactory) {
+				f.On("CreateLock").Return(nil, someErr)
+			},
+		},
+		{
+			name: "sampling store",
+			setup: func(f *mocks.SamplingStoreFactory) {
+				f.On("CreateLock").Return(new(lockMocks.Lock), nil)
+				f.On("CreateSamplingStore").Return(nil, someErr)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			factory := struct {
+				*mocks.Factory
+				*mocks.SamplingStoreFactory
+			}{new(mocks.Factory), new(mocks.SamplingStoreFactory)}
+			test.setup(factory.SamplingStoreFactory)
+			factory.Factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+			factory.Factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+			factory.Factory.On("CreateDependencyReader").Return(nil, nil)
+
+			_, err := createPluginServices(factory)
+			assert.EqualError(t, err, "made-up error")
+		})
+	}
+}
+
+func TestServerStorageErrors(t *testing.T) {
+	someErr := errors.New("made-up error")
+	tests := []struct {
+		name  string
+		setup func(f *mocks.Factory, af *mocks.ArchiveFactory)
+	}{
+		{
+			name: "span reader",
+			setup: func(f *mocks.Factory, af *mocks.ArchiveFactory) {
+				f.On("CreateSpanReader").Return(nil, someErr)
+			},
+		},
+		{
+			name: "span writer",
+			setup: func(f *mocks.Factory, af *mocks.ArchiveFactory) {
+				f.On("CreateSpanWriter").Return(nil, someErr)
+			},
+		},
+		{
+			name: "dependency reader",
+			setup: func(f *mocks.Factory, af *mocks.ArchiveFactory) {
+				f.On("CreateDependencyReader").Return(nil, someErr)
+			},
+		},
+		{
+			name: "archive reader",
+			setup: func(f *mocks.Factory, af *mocks.ArchiveFactory) {
+				af.On("CreateArchiveSpanReader").Return(nil, someErr)
+			},
+		},
+		{
+			name: "archive writer",
+			setup: func(f *mocks.Factory, af *mocks.ArchiveFactory) {
+				af.On("CreateArchiveSpanReader").Return(new(spanStoreMocks.Reader), nil)
+				af.On("CreateArchiveSpanWriter").Return(nil, someErr)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			factory := struct {
+				*mocks.Factory
+				*mocks.ArchiveFactory
+			}{new(mocks.Factory), new(mocks.ArchiveFactory)}
+			test.setup(factory.Factory, factory.ArchiveFactory)
+			factory.Factory.On("CreateSpanReader").Return(new(spanStoreMocks.Reader), nil)
+			factory.Factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+			factory.Factory.On("CreateDependencyReader").Return(nil, nil)
+
+			_, err := NewServer(&Options{}, factory, zap.NewNop())
+			assert.EqualError(t, err, "made-up error")
+		})
+	}
+}
+
+func TestServerHealthCheck(t *testing.T) {
+	server := startServer(t, &Options{GRPCHostPort: "127.0.0.1:0"}, newMemoryFactory(t))
+	conn, err := grpc.Dial(server.grpcConn.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+	resp, err := healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	server.Close()
+	_, err = healthClient.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Error(t, err)
+}
+
+func TestServerTLSError(t *testing.T) {
+	options := &Options{
+		TLSGRPC: tlscfg.Options{
+			Enabled:  true,
+			CertPath: "invalid/path",
+		},
+	}
+	_, err := NewServer(options, newMemoryFactory(t), zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestServerStartError(t *testing.T) {
+	server, err := NewServer(&Options{GRPCHostPort: "-1"}, newMemoryFactory(t), zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, server.Start())
+}
+
+func TestServerUnavailableAfterClose(t *testing.T) {
+	server, err := NewServer(&Options{GRPCHostPort: "127.0.0.1:0"}, newMemoryFactory(t), zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	go server.Close()
+	select {
+	case status := <-server.HealthCheckStatus():
+		assert.Equal(t, healthcheck.Unavailable, status)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to become unavailable")
+	}
+}
+
+var _ spanstore.Writer = unclosableWriter{}
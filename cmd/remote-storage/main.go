@@ -0,0 +1,109 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+	_ "go.uber.org/automaxprocs"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/docs"
+	"github.com/jaegertracing/jaeger/cmd/env"
+	"github.com/jaegertracing/jaeger/cmd/flags"
+	"github.com/jaegertracing/jaeger/cmd/remote-storage/app"
+	"github.com/jaegertracing/jaeger/cmd/status"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/version"
+	"github.com/jaegertracing/jaeger/plugin/storage"
+	"github.com/jaegertracing/jaeger/ports"
+)
+
+func main() {
+	svc := flags.NewService(ports.RemoteStorageAdminHTTP)
+
+	storageFactory, err := storage.NewFactory(storage.FactoryConfigFromEnvAndCLI(os.Args, os.Stderr))
+	if err != nil {
+		log.Fatalf("Cannot initialize storage factory: %v", err)
+	}
+
+	v := viper.New()
+	var command = &cobra.Command{
+		Use:   "jaeger-remote-storage",
+		Short: "Jaeger remote storage exposes a storage backend over the gRPC storage plugin protocol.",
+		Long: `Jaeger remote storage exposes a storage backend over the gRPC storage plugin protocol,
+so that it can be shared by several collectors and query services configured with --grpc-storage-plugin.remote.server.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svc.Start(v); err != nil {
+				return err
+			}
+			logger := svc.Logger // shortcut
+			baseFactory := svc.MetricsFactory.Namespace(metrics.NSOptions{Name: "jaeger"})
+			version.NewInfoMetrics(baseFactory.Namespace(metrics.NSOptions{Name: "remote_storage"}))
+
+			storageFactory.InitFromViper(v, logger)
+			if err := storageFactory.Initialize(baseFactory, logger); err != nil {
+				logger.Fatal("Failed to init storage factory", zap.Error(err))
+			}
+
+			opts := new(app.Options).InitFromViper(v)
+			server, err := app.NewServer(opts, storageFactory, logger)
+			if err != nil {
+				logger.Fatal("Failed to create server", zap.Error(err))
+			}
+
+			go func() {
+				for s := range server.HealthCheckStatus() {
+					svc.SetHealthCheckStatus(s)
+				}
+			}()
+
+			if err := server.Start(); err != nil {
+				logger.Fatal("Could not start server", zap.Error(err))
+			}
+
+			svc.RunAndThen(func() {
+				server.Close()
+				if err := storageFactory.Close(); err != nil {
+					logger.Error("Failed to close storage factory", zap.Error(err))
+				}
+			})
+			return nil
+		},
+	}
+
+	command.AddCommand(version.Command())
+	command.AddCommand(env.Command())
+	command.AddCommand(docs.Command(v))
+	command.AddCommand(status.Command(v, ports.RemoteStorageAdminHTTP))
+
+	config.AddFlags(
+		v,
+		command,
+		svc.AddFlags,
+		storageFactory.AddFlags,
+		app.AddFlags,
+	)
+
+	if err := command.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
@@ -17,9 +17,13 @@ package json
 
 import (
 	"strings"
+	"time"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
 	"github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 // FromDomain converts model.Trace into json.Trace format.
@@ -190,3 +194,137 @@ func DependenciesFromDomain(dependencyLinks []model.DependencyLink) []json.Depen
 	}
 	return retMe
 }
+
+// CriticalPathFromDomain converts []criticalpath.Segment into []json.CriticalPathSegment format.
+func CriticalPathFromDomain(segments []criticalpath.Segment) []json.CriticalPathSegment {
+	retMe := make([]json.CriticalPathSegment, len(segments))
+	for i, segment := range segments {
+		retMe[i] = json.CriticalPathSegment{
+			SpanID:    json.SpanID(segment.SpanID.String()),
+			StartTime: model.TimeAsEpochMicroseconds(segment.StartTime),
+			Duration:  model.DurationAsMicroseconds(segment.Duration),
+		}
+	}
+	return retMe
+}
+
+// SpanStatsFromDomain converts []spanstore.SpanStats into []json.SpanStats format.
+func SpanStatsFromDomain(stats []spanstore.SpanStats) []json.SpanStats {
+	retMe := make([]json.SpanStats, len(stats))
+	for i, s := range stats {
+		histogram := make([]json.HistogramBucket, len(s.Histogram))
+		for j, bucket := range s.Histogram {
+			histogram[j] = json.HistogramBucket{
+				LowerBound: model.DurationAsMicroseconds(bucket.LowerBound),
+				UpperBound: model.DurationAsMicroseconds(bucket.UpperBound),
+				Count:      bucket.Count,
+			}
+		}
+		retMe[i] = json.SpanStats{
+			ServiceName:   s.ServiceName,
+			OperationName: s.OperationName,
+			TagValue:      s.TagValue,
+			Count:         s.Count,
+			ErrorCount:    s.ErrorCount,
+			P50:           model.DurationAsMicroseconds(s.P50),
+			P90:           model.DurationAsMicroseconds(s.P90),
+			P99:           model.DurationAsMicroseconds(s.P99),
+			Histogram:     histogram,
+		}
+	}
+	return retMe
+}
+
+// OperationDependenciesFromDomain converts []model.OperationDependencyLink into []json.OperationDependencyLink format.
+func OperationDependenciesFromDomain(links []model.OperationDependencyLink) []json.OperationDependencyLink {
+	retMe := make([]json.OperationDependencyLink, len(links))
+	for i, l := range links {
+		retMe[i] = json.OperationDependencyLink{
+			Parent:          l.Parent,
+			ParentOperation: l.ParentOperation,
+			Child:           l.Child,
+			ChildOperation:  l.ChildOperation,
+			CallCount:       l.CallCount,
+			ErrorCount:      l.ErrorCount,
+			MinDuration:     model.DurationAsMicroseconds(l.Latency.Min),
+			MaxDuration:     model.DurationAsMicroseconds(l.Latency.Max),
+			MeanDuration:    model.DurationAsMicroseconds(l.MeanLatency()),
+		}
+	}
+	return retMe
+}
+
+// TraceDiffFromDomain converts api_v2.TraceDiff into json.TraceDiff format.
+func TraceDiffFromDomain(diff *api_v2.TraceDiff) *json.TraceDiff {
+	spans := make([]json.SpanDiff, len(diff.Spans))
+	for i, s := range diff.Spans {
+		changedTags := make([]json.TagDiff, len(s.ChangedTags))
+		for j, tag := range s.ChangedTags {
+			changedTags[j] = json.TagDiff{Key: tag.Key, ValueA: tag.ValueA, ValueB: tag.ValueB}
+		}
+		spans[i] = json.SpanDiff{
+			Path:          s.Path,
+			ServiceName:   s.ServiceName,
+			OperationName: s.OperationName,
+			Status:        strings.ToLower(s.Status.String()),
+			SpanIDA:       spanIDFromDomain(s.SpanIDA),
+			SpanIDB:       spanIDFromDomain(s.SpanIDB),
+			DurationA:     model.DurationAsMicroseconds(s.DurationA),
+			DurationB:     model.DurationAsMicroseconds(s.DurationB),
+			DurationDelta: durationDeltaAsMicroseconds(s.DurationDelta),
+			ChangedTags:   changedTags,
+			CriticalPathA: s.CriticalPathA,
+			CriticalPathB: s.CriticalPathB,
+		}
+	}
+	return &json.TraceDiff{
+		DurationA: model.DurationAsMicroseconds(diff.DurationA),
+		DurationB: model.DurationAsMicroseconds(diff.DurationB),
+		Spans:     spans,
+	}
+}
+
+// TraceSetDiffFromDomain converts api_v2.TraceSetDiff into json.TraceSetDiff format.
+func TraceSetDiffFromDomain(diff *api_v2.TraceSetDiff) *json.TraceSetDiff {
+	operations := make([]json.OperationDiff, len(diff.Operations))
+	for i, op := range diff.Operations {
+		operations[i] = json.OperationDiff{
+			ServiceName:   op.ServiceName,
+			OperationName: op.OperationName,
+			A:             durationStatsFromDomain(op.A),
+			B:             durationStatsFromDomain(op.B),
+			MeanDelta:     durationDeltaAsMicroseconds(op.MeanDelta),
+			P50Delta:      durationDeltaAsMicroseconds(op.P50Delta),
+			P90Delta:      durationDeltaAsMicroseconds(op.P90Delta),
+			P99Delta:      durationDeltaAsMicroseconds(op.P99Delta),
+		}
+	}
+	return &json.TraceSetDiff{
+		TracesA:    durationStatsFromDomain(diff.TracesA),
+		TracesB:    durationStatsFromDomain(diff.TracesB),
+		Operations: operations,
+	}
+}
+
+func durationStatsFromDomain(stats api_v2.DurationStats) json.DurationStats {
+	return json.DurationStats{
+		Count: stats.Count,
+		Mean:  model.DurationAsMicroseconds(stats.Mean),
+		P50:   model.DurationAsMicroseconds(stats.P50),
+		P90:   model.DurationAsMicroseconds(stats.P90),
+		P99:   model.DurationAsMicroseconds(stats.P99),
+	}
+}
+
+// spanIDFromDomain returns an empty span ID for the spans missing on one side of a diff.
+func spanIDFromDomain(spanID model.SpanID) json.SpanID {
+	if spanID == 0 {
+		return ""
+	}
+	return json.SpanID(spanID.String())
+}
+
+// durationDeltaAsMicroseconds converts a possibly negative duration to microseconds.
+func durationDeltaAsMicroseconds(d time.Duration) int64 {
+	return int64(d / time.Microsecond)
+}
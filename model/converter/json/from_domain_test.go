@@ -28,7 +28,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/model/criticalpath"
 	jModel "github.com/jaegertracing/jaeger/model/json"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 const NumberOfFixtures = 1
@@ -178,3 +181,136 @@ func TestDependenciesFromDomain(t *testing.T) {
 	actual := DependenciesFromDomain(input)
 	assert.EqualValues(t, expected, actual)
 }
+
+func TestCriticalPathFromDomain(t *testing.T) {
+	segments := []criticalpath.Segment{
+		{SpanID: model.NewSpanID(0x1f), StartTime: time.Unix(1, 0), Duration: 3 * time.Millisecond},
+	}
+	expected := []jModel.CriticalPathSegment{
+		{SpanID: "000000000000001f", StartTime: 1000000, Duration: 3000},
+	}
+	assert.Equal(t, expected, CriticalPathFromDomain(segments))
+}
+
+func TestSpanStatsFromDomain(t *testing.T) {
+	stats := []spanstore.SpanStats{
+		{
+			ServiceName:   "frontend",
+			OperationName: "GET",
+			Count:         3,
+			ErrorCount:    1,
+			P50:           time.Millisecond,
+			P90:           2 * time.Millisecond,
+			P99:           3 * time.Millisecond,
+			Histogram: []spanstore.HistogramBucket{
+				{UpperBound: 2 * time.Millisecond, Count: 1},
+				{LowerBound: 2 * time.Millisecond, Count: 2},
+			},
+		},
+	}
+	expected := []jModel.SpanStats{
+		{
+			ServiceName:   "frontend",
+			OperationName: "GET",
+			Count:         3,
+			ErrorCount:    1,
+			P50:           1000,
+			P90:           2000,
+			P99:           3000,
+			Histogram: []jModel.HistogramBucket{
+				{UpperBound: 2000, Count: 1},
+				{LowerBound: 2000, Count: 2},
+			},
+		},
+	}
+	assert.Equal(t, expected, SpanStatsFromDomain(stats))
+}
+
+func TestTraceDiffFromDomain(t *testing.T) {
+	diff := &api_v2.TraceDiff{
+		DurationA: 3 * time.Millisecond,
+		DurationB: 2 * time.Millisecond,
+		Spans: []api_v2.SpanDiff{
+			{
+				Path:          "/frontend::GET",
+				ServiceName:   "frontend",
+				OperationName: "GET",
+				Status:        api_v2.SpanDiff_MATCHED,
+				SpanIDA:       model.SpanID(0x1f),
+				SpanIDB:       model.SpanID(0x2f),
+				DurationA:     3 * time.Millisecond,
+				DurationB:     2 * time.Millisecond,
+				DurationDelta: -time.Millisecond,
+				ChangedTags:   []api_v2.TagDiff{{Key: "version", ValueA: "1", ValueB: "2"}},
+				CriticalPathA: true,
+			},
+			{
+				Path:          "/frontend::GET/redis::SET",
+				ServiceName:   "redis",
+				OperationName: "SET",
+				Status:        api_v2.SpanDiff_ADDED,
+				SpanIDB:       model.SpanID(0x3f),
+				DurationB:     time.Millisecond,
+			},
+		},
+	}
+	expected := &jModel.TraceDiff{
+		DurationA: 3000,
+		DurationB: 2000,
+		Spans: []jModel.SpanDiff{
+			{
+				Path:          "/frontend::GET",
+				ServiceName:   "frontend",
+				OperationName: "GET",
+				Status:        "matched",
+				SpanIDA:       "000000000000001f",
+				SpanIDB:       "000000000000002f",
+				DurationA:     3000,
+				DurationB:     2000,
+				DurationDelta: -1000,
+				ChangedTags:   []jModel.TagDiff{{Key: "version", ValueA: "1", ValueB: "2"}},
+				CriticalPathA: true,
+			},
+			{
+				Path:          "/frontend::GET/redis::SET",
+				ServiceName:   "redis",
+				OperationName: "SET",
+				Status:        "added",
+				SpanIDB:       "000000000000003f",
+				DurationB:     1000,
+				ChangedTags:   []jModel.TagDiff{},
+			},
+		},
+	}
+	assert.Equal(t, expected, TraceDiffFromDomain(diff))
+}
+
+func TestTraceSetDiffFromDomain(t *testing.T) {
+	diff := &api_v2.TraceSetDiff{
+		TracesA: api_v2.DurationStats{Count: 2, Mean: 2 * time.Millisecond, P50: time.Millisecond, P90: 3 * time.Millisecond, P99: 3 * time.Millisecond},
+		TracesB: api_v2.DurationStats{Count: 1, Mean: time.Millisecond, P50: time.Millisecond, P90: time.Millisecond, P99: time.Millisecond},
+		Operations: []api_v2.OperationDiff{
+			{
+				ServiceName:   "frontend",
+				OperationName: "GET",
+				A:             api_v2.DurationStats{Count: 2, Mean: 2 * time.Millisecond},
+				B:             api_v2.DurationStats{Count: 1, Mean: time.Millisecond},
+				MeanDelta:     -time.Millisecond,
+			},
+		},
+	}
+	expected := &jModel.TraceSetDiff{
+		TracesA: jModel.DurationStats{Count: 2, Mean: 2000, P50: 1000, P90: 3000, P99: 3000},
+		TracesB: jModel.DurationStats{Count: 1, Mean: 1000, P50: 1000, P90: 1000, P99: 1000},
+		Operations: []jModel.OperationDiff{
+			{
+				ServiceName:   "frontend",
+				OperationName: "GET",
+				A:             jModel.DurationStats{Count: 2, Mean: 2000},
+				B:             jModel.DurationStats{Count: 1, Mean: 1000},
+				MeanDelta:     -1000,
+			},
+		},
+	}
+	assert.Equal(t, expected, TraceSetDiffFromDomain(diff))
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package criticalpath
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Segment is a time interval of the critical path spent in a span itself,
+// i.e. while the span was not waiting for one of its children.
+type Segment struct {
+	SpanID    model.SpanID
+	StartTime time.Time
+	Duration  time.Duration
+}
+
+type node struct {
+	span *model.Span
+	// async is true when the span follows from its parent instead of being a child of it
+	async    bool
+	children []*node
+	// asyncEnd is the end time of the last asynchronous descendant of the span, if any
+	asyncEnd time.Time
+}
+
+func (n *node) startTime() time.Time {
+	return n.span.StartTime
+}
+
+func (n *node) endTime() time.Time {
+	return n.span.StartTime.Add(n.span.Duration)
+}
+
+// lastEnd returns the end time of the span, or of its last asynchronous descendant if it finished later.
+func (n *node) lastEnd() time.Time {
+	return maxTime(n.endTime(), n.asyncEnd)
+}
+
+// tailEnd returns the end time of the work started by the span that its parent did not wait for.
+func (n *node) tailEnd() time.Time {
+	if n.async {
+		return n.lastEnd()
+	}
+	return n.asyncEnd
+}
+
+// Compute returns the segments of the critical path of the trace, ordered by start time.
+//
+// The critical path starts from the root span, the first span without a parent in the trace.
+// Walking back from the end of a span, the child span that finished last is on the critical path,
+// and so on from the start of that child, so concurrent children that finished earlier are not.
+// The time of a child span outside of the lifetime of its parent is ignored.
+//
+// Spans referenced with FOLLOWS_FROM are asynchronous: the parent span does not wait for them,
+// so they are not on the critical path of the parent. However, when they or their descendants
+// finish after the parent, they extend the latency of the trace, and the critical path continues
+// through them after the end of the parent. The time between the end of the parent and the start
+// of the asynchronous span, e.g. the time spent in a queue, is not attributed to any span.
+func Compute(trace *model.Trace) []Segment {
+	root := buildTree(trace)
+	if root == nil {
+		return nil
+	}
+	var c computation
+	c.walk(root, root.startTime(), root.lastEnd())
+	// the segments were added from the end of the trace
+	for i, j := 0, len(c.segments)-1; i < j; i, j = i+1, j-1 {
+		c.segments[i], c.segments[j] = c.segments[j], c.segments[i]
+	}
+	return c.segments
+}
+
+// buildTree returns the root span of the trace. A span with several references is
+// attached to its first CHILD_OF parent, or to its first FOLLOWS_FROM parent.
+func buildTree(trace *model.Trace) *node {
+	nodes := make(map[model.SpanID]*node, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if _, ok := nodes[span.SpanID]; !ok {
+			nodes[span.SpanID] = &node{span: span}
+		}
+	}
+	var roots []*node
+	for _, span := range trace.Spans {
+		n := nodes[span.SpanID]
+		if n.span != span {
+			// duplicate span ID, the first span wins
+			continue
+		}
+		parent := findParent(n, nodes)
+		if parent == nil {
+			roots = append(roots, n)
+			continue
+		}
+		parent.children = append(parent.children, n)
+	}
+	if len(roots) == 0 {
+		// the references form a cycle
+		return nil
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].startTime().Before(roots[j].startTime())
+	})
+	setAsyncEnd(roots[0])
+	return roots[0]
+}
+
+func findParent(n *node, nodes map[model.SpanID]*node) *node {
+	var followsFrom *node
+	for _, ref := range n.span.References {
+		if ref.TraceID != n.span.TraceID {
+			continue
+		}
+		parent, ok := nodes[ref.SpanID]
+		if !ok || parent == n {
+			continue
+		}
+		if ref.RefType == model.ChildOf {
+			return parent
+		}
+		if followsFrom == nil {
+			followsFrom = parent
+		}
+	}
+	if followsFrom != nil {
+		n.async = true
+	}
+	return followsFrom
+}
+
+// setAsyncEnd sets the asyncEnd of the span and of its descendants. The spans reachable from a root
+// form a tree, since each span has at most one parent.
+func setAsyncEnd(n *node) {
+	for _, child := range n.children {
+		setAsyncEnd(child)
+		if end := child.tailEnd(); end.After(n.asyncEnd) {
+			n.asyncEnd = end
+		}
+	}
+}
+
+type computation struct {
+	segments []Segment
+}
+
+// walk adds the segments of the critical path of the span within [from, until], from the end.
+func (c *computation) walk(n *node, from, until time.Time) {
+	start := maxTime(n.startTime(), from)
+	cursor := until
+	if end := n.endTime(); cursor.After(end) {
+		// the trace goes on after the span finished, because of an asynchronous descendant
+		if child := lastFinished(n.children, cursor, (*node).tailEnd); child != nil && child.tailEnd().After(end) {
+			from := start
+			if child.async {
+				from = child.startTime()
+			}
+			c.walk(child, from, minTime(child.tailEnd(), cursor))
+			cursor = child.startTime()
+		}
+		if cursor.After(end) {
+			cursor = end
+		}
+	}
+	synchronous := make([]*node, 0, len(n.children))
+	for _, child := range n.children {
+		if !child.async && child.endTime().After(start) {
+			synchronous = append(synchronous, child)
+		}
+	}
+	for cursor.After(start) {
+		child := lastFinished(synchronous, cursor, (*node).endTime)
+		if child == nil {
+			break
+		}
+		childEnd := minTime(child.endTime(), cursor)
+		c.add(n, childEnd, cursor)
+		c.walk(child, start, childEnd)
+		cursor = child.startTime()
+	}
+	c.add(n, start, cursor)
+}
+
+// lastFinished returns the node starting before the cursor that finished last, at most at the cursor.
+func lastFinished(nodes []*node, cursor time.Time, end func(*node) time.Time) *node {
+	var last *node
+	var lastEnd time.Time
+	for _, n := range nodes {
+		if !n.startTime().Before(cursor) {
+			continue
+		}
+		e := minTime(end(n), cursor)
+		if last == nil || e.After(lastEnd) {
+			last, lastEnd = n, e
+		}
+	}
+	return last
+}
+
+// add adds a segment of the span, merging it with the previous segment of the same span if they are contiguous.
+func (c *computation) add(n *node, start, end time.Time) {
+	if !end.After(start) {
+		return
+	}
+	if last := len(c.segments) - 1; last >= 0 {
+		if s := &c.segments[last]; s.SpanID == n.span.SpanID && s.StartTime.Equal(end) {
+			s.StartTime = start
+			s.Duration += end.Sub(start)
+			return
+		}
+	}
+	c.segments = append(c.segments, Segment{
+		SpanID:    n.span.SpanID,
+		StartTime: start,
+		Duration:  end.Sub(start),
+	})
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
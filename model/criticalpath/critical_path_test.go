@@ -0,0 +1,194 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package criticalpath
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+var (
+	baseTime = time.Unix(1000, 0)
+	traceID  = model.NewTraceID(0, 1)
+)
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+func span(id uint64, start, duration int, refs ...model.SpanRef) *model.Span {
+	return &model.Span{
+		TraceID:    traceID,
+		SpanID:     model.NewSpanID(id),
+		StartTime:  baseTime.Add(ms(start)),
+		Duration:   ms(duration),
+		References: refs,
+	}
+}
+
+func childOf(id uint64) model.SpanRef {
+	return model.NewChildOfRef(traceID, model.NewSpanID(id))
+}
+
+func followsFrom(id uint64) model.SpanRef {
+	return model.NewFollowsFromRef(traceID, model.NewSpanID(id))
+}
+
+func segment(id uint64, start, duration int) Segment {
+	return Segment{SpanID: model.NewSpanID(id), StartTime: baseTime.Add(ms(start)), Duration: ms(duration)}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name     string
+		spans    []*model.Span
+		expected []Segment
+	}{
+		{
+			name:  "empty trace",
+			spans: nil,
+		},
+		{
+			name:     "single span",
+			spans:    []*model.Span{span(1, 0, 100)},
+			expected: []Segment{segment(1, 0, 100)},
+		},
+		{
+			name: "sequential children",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 20, childOf(1)),
+				span(3, 40, 50, childOf(1)),
+			},
+			expected: []Segment{
+				segment(1, 0, 10),
+				segment(2, 10, 20),
+				segment(1, 30, 10),
+				segment(3, 40, 50),
+				segment(1, 90, 10),
+			},
+		},
+		{
+			name: "concurrent children",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 60, childOf(1)),
+				// finishes before 2, so 1 was not waiting for it
+				span(3, 10, 30, childOf(1)),
+				// overlaps with the start of 2, so only its end is on the critical path
+				span(4, 5, 10, childOf(1)),
+			},
+			expected: []Segment{
+				segment(1, 0, 5),
+				segment(4, 5, 5),
+				segment(2, 10, 60),
+				segment(1, 70, 30),
+			},
+		},
+		{
+			name: "nested children",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 80, childOf(1)),
+				span(3, 20, 30, childOf(2)),
+			},
+			expected: []Segment{
+				segment(1, 0, 10),
+				segment(2, 10, 10),
+				segment(3, 20, 30),
+				segment(2, 50, 40),
+				segment(1, 90, 10),
+			},
+		},
+		{
+			name: "child outliving its parent",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 50, 100, childOf(1)),
+				span(3, -10, 20, childOf(1)),
+			},
+			expected: []Segment{
+				segment(3, 0, 10),
+				segment(1, 10, 40),
+				segment(2, 50, 50),
+			},
+		},
+		{
+			name: "asynchronous child within the parent",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 20, followsFrom(1)),
+			},
+			expected: []Segment{segment(1, 0, 100)},
+		},
+		{
+			name: "asynchronous child finishing after the parent",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 20, childOf(1)),
+				span(3, 120, 30, followsFrom(2)),
+				span(4, 125, 10, childOf(3)),
+			},
+			expected: []Segment{
+				segment(1, 0, 10),
+				segment(2, 10, 20),
+				segment(3, 120, 5),
+				segment(4, 125, 10),
+				segment(3, 135, 15),
+			},
+		},
+		{
+			name: "CHILD_OF reference preferred over FOLLOWS_FROM",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(2, 10, 20),
+				span(3, 40, 20, followsFrom(2), childOf(1)),
+			},
+			expected: []Segment{
+				segment(1, 0, 40),
+				segment(3, 40, 20),
+				segment(1, 60, 40),
+			},
+		},
+		{
+			name: "orphan and duplicate spans",
+			spans: []*model.Span{
+				span(1, 0, 100),
+				span(1, 0, 200),
+				span(2, -10, 5, childOf(42)),
+				span(3, 10, 20, childOf(1)),
+			},
+			expected: []Segment{
+				segment(2, -10, 5),
+			},
+		},
+		{
+			name: "cycle",
+			spans: []*model.Span{
+				span(1, 0, 100, childOf(2)),
+				span(2, 0, 100, childOf(1)),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Compute(&model.Trace{Spans: test.spans}))
+		})
+	}
+}
@@ -15,6 +15,8 @@
 
 package model
 
+import "time"
+
 const (
 	// JaegerDependencyLinkSource describes a dependency diagram that was generated from Jaeger traces.
 	JaegerDependencyLinkSource = "jaeger"
@@ -27,3 +29,74 @@ func (d DependencyLink) ApplyDefaults() DependencyLink {
 	}
 	return d
 }
+
+// OperationDependencyLink is a dependency between an operation of a parent service and an operation
+// of a child service, with the statistics of the calls from the former to the latter.
+type OperationDependencyLink struct {
+	Parent          string
+	ParentOperation string
+	Child           string
+	ChildOperation  string
+	CallCount       uint64
+	// ErrorCount is the number of calls whose child span has the error tag.
+	ErrorCount uint64
+	// Latency summarizes the durations of the child spans.
+	Latency LatencySummary
+	Source  string
+}
+
+// LatencySummary summarizes the durations of a number of calls. It can be merged with the summaries
+// of other calls, therefore it holds the total duration rather than the mean.
+type LatencySummary struct {
+	Min   time.Duration
+	Max   time.Duration
+	Total time.Duration
+}
+
+// ApplyDefaults applies defaults to the OperationDependencyLink.
+func (d OperationDependencyLink) ApplyDefaults() OperationDependencyLink {
+	if d.Source == "" {
+		d.Source = JaegerDependencyLinkSource
+	}
+	return d
+}
+
+// Observe records a call with the given duration in the link.
+func (d *OperationDependencyLink) Observe(duration time.Duration, isError bool) {
+	d.Merge(OperationDependencyLink{
+		CallCount:  1,
+		ErrorCount: boolToCount(isError),
+		Latency:    LatencySummary{Min: duration, Max: duration, Total: duration},
+	})
+}
+
+// Merge adds the calls of another link between the same operations to the link.
+func (d *OperationDependencyLink) Merge(other OperationDependencyLink) {
+	if other.CallCount == 0 {
+		return
+	}
+	if d.CallCount == 0 || other.Latency.Min < d.Latency.Min {
+		d.Latency.Min = other.Latency.Min
+	}
+	if other.Latency.Max > d.Latency.Max {
+		d.Latency.Max = other.Latency.Max
+	}
+	d.Latency.Total += other.Latency.Total
+	d.CallCount += other.CallCount
+	d.ErrorCount += other.ErrorCount
+}
+
+// MeanLatency returns the mean duration of the calls.
+func (d OperationDependencyLink) MeanLatency() time.Duration {
+	if d.CallCount == 0 {
+		return 0
+	}
+	return d.Latency.Total / time.Duration(d.CallCount)
+}
+
+func boolToCount(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -16,6 +16,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,3 +29,32 @@ func TestDependencyLinkApplyDefaults(t *testing.T) {
 	dl = DependencyLink{Source: networkSource}.ApplyDefaults()
 	assert.Equal(t, networkSource, dl.Source)
 }
+
+func TestOperationDependencyLinkApplyDefaults(t *testing.T) {
+	dl := OperationDependencyLink{}.ApplyDefaults()
+	assert.Equal(t, JaegerDependencyLinkSource, dl.Source)
+
+	networkSource := "network"
+	dl = OperationDependencyLink{Source: networkSource}.ApplyDefaults()
+	assert.Equal(t, networkSource, dl.Source)
+}
+
+func TestOperationDependencyLinkMerge(t *testing.T) {
+	dl := OperationDependencyLink{}
+	assert.Equal(t, time.Duration(0), dl.MeanLatency())
+
+	dl.Observe(2*time.Millisecond, false)
+	dl.Observe(4*time.Millisecond, true)
+	dl.Merge(OperationDependencyLink{})
+	dl.Merge(OperationDependencyLink{
+		CallCount:  2,
+		ErrorCount: 1,
+		Latency:    LatencySummary{Min: time.Millisecond, Max: 5 * time.Millisecond, Total: 6 * time.Millisecond},
+	})
+	assert.Equal(t, OperationDependencyLink{
+		CallCount:  4,
+		ErrorCount: 2,
+		Latency:    LatencySummary{Min: time.Millisecond, Max: 5 * time.Millisecond, Total: 12 * time.Millisecond},
+	}, dl)
+	assert.Equal(t, 3*time.Millisecond, dl.MeanLatency())
+}
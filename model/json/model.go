@@ -50,10 +50,11 @@ const (
 
 // Trace is a list of spans
 type Trace struct {
-	TraceID   TraceID               `json:"traceID"`
-	Spans     []Span                `json:"spans"`
-	Processes map[ProcessID]Process `json:"processes"`
-	Warnings  []string              `json:"warnings"`
+	TraceID      TraceID               `json:"traceID"`
+	Spans        []Span                `json:"spans"`
+	Processes    map[ProcessID]Process `json:"processes"`
+	Warnings     []string              `json:"warnings"`
+	CriticalPath []CriticalPathSegment `json:"criticalPath,omitempty"`
 }
 
 // Span is a span denoting a piece of work in some infrastructure
@@ -103,6 +104,34 @@ type KeyValue struct {
 	Value interface{} `json:"value"`
 }
 
+// CriticalPathSegment is a time interval of the critical path of a trace spent in a span
+type CriticalPathSegment struct {
+	SpanID    SpanID `json:"spanID"`
+	StartTime uint64 `json:"startTime"` // microseconds since Unix epoch
+	Duration  uint64 `json:"duration"`  // microseconds
+}
+
+// SpanStats are the duration and error statistics of a group of spans
+type SpanStats struct {
+	ServiceName   string            `json:"serviceName"`
+	OperationName string            `json:"operationName,omitempty"`
+	TagValue      string            `json:"tagValue,omitempty"`
+	Count         int64             `json:"count"`
+	ErrorCount    int64             `json:"errorCount"`
+	P50           uint64            `json:"p50"` // microseconds
+	P90           uint64            `json:"p90"` // microseconds
+	P99           uint64            `json:"p99"` // microseconds
+	Histogram     []HistogramBucket `json:"histogram"`
+}
+
+// HistogramBucket is the number of spans with a duration within [lowerBound, upperBound),
+// the last bucket of a histogram has no upper bound
+type HistogramBucket struct {
+	LowerBound uint64 `json:"lowerBound"`           // microseconds
+	UpperBound uint64 `json:"upperBound,omitempty"` // microseconds
+	Count      int64  `json:"count"`
+}
+
 // DependencyLink shows dependencies between services
 type DependencyLink struct {
 	Parent    string `json:"parent"`
@@ -110,8 +139,79 @@ type DependencyLink struct {
 	CallCount uint64 `json:"callCount"`
 }
 
+// OperationDependencyLink shows dependencies between operations of different services
+type OperationDependencyLink struct {
+	Parent          string `json:"parent"`
+	ParentOperation string `json:"parentOperation"`
+	Child           string `json:"child"`
+	ChildOperation  string `json:"childOperation"`
+	CallCount       uint64 `json:"callCount"`
+	ErrorCount      uint64 `json:"errorCount"`
+	MinDuration     uint64 `json:"minDuration"`  // microseconds
+	MaxDuration     uint64 `json:"maxDuration"`  // microseconds
+	MeanDuration    uint64 `json:"meanDuration"` // microseconds
+}
+
 // Operation defines the data in the operation response when query operation by service and span kind
 type Operation struct {
 	Name     string `json:"name"`
 	SpanKind string `json:"spanKind"`
 }
+
+// TraceDiff compares the span trees of two traces
+type TraceDiff struct {
+	DurationA uint64     `json:"durationA"` // microseconds
+	DurationB uint64     `json:"durationB"` // microseconds
+	Spans     []SpanDiff `json:"spans"`
+}
+
+// SpanDiff compares the spans of two traces found at the same position of the span trees
+type SpanDiff struct {
+	Path          string    `json:"path"`
+	ServiceName   string    `json:"serviceName"`
+	OperationName string    `json:"operationName"`
+	Status        string    `json:"status"` // matched, added or missing
+	SpanIDA       SpanID    `json:"spanIDA,omitempty"`
+	SpanIDB       SpanID    `json:"spanIDB,omitempty"`
+	DurationA     uint64    `json:"durationA"`     // microseconds
+	DurationB     uint64    `json:"durationB"`     // microseconds
+	DurationDelta int64     `json:"durationDelta"` // microseconds
+	ChangedTags   []TagDiff `json:"changedTags"`
+	CriticalPathA bool      `json:"criticalPathA"`
+	CriticalPathB bool      `json:"criticalPathB"`
+}
+
+// TagDiff is a tag whose value differs between two aligned spans
+type TagDiff struct {
+	Key    string `json:"key"`
+	ValueA string `json:"valueA"`
+	ValueB string `json:"valueB"`
+}
+
+// TraceSetDiff compares the durations of two sets of traces
+type TraceSetDiff struct {
+	TracesA    DurationStats   `json:"tracesA"`
+	TracesB    DurationStats   `json:"tracesB"`
+	Operations []OperationDiff `json:"operations"`
+}
+
+// OperationDiff compares the durations of the spans of an operation in two sets of traces
+type OperationDiff struct {
+	ServiceName   string        `json:"serviceName"`
+	OperationName string        `json:"operationName"`
+	A             DurationStats `json:"a"`
+	B             DurationStats `json:"b"`
+	MeanDelta     int64         `json:"meanDelta"` // microseconds
+	P50Delta      int64         `json:"p50Delta"`  // microseconds
+	P90Delta      int64         `json:"p90Delta"`  // microseconds
+	P99Delta      int64         `json:"p99Delta"`  // microseconds
+}
+
+// DurationStats summarizes a set of durations
+type DurationStats struct {
+	Count int64  `json:"count"`
+	Mean  uint64 `json:"mean"` // microseconds
+	P50   uint64 `json:"p50"`  // microseconds
+	P90   uint64 `json:"p90"`  // microseconds
+	P99   uint64 `json:"p99"`  // microseconds
+}
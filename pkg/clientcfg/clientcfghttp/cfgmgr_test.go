@@ -22,21 +22,37 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
 type mockSamplingStore struct {
 	samplingResponse *sampling.SamplingStrategyResponse
+	processTags      map[string]string
 }
 
-func (m *mockSamplingStore) GetSamplingStrategy(_ context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error) {
+func (m *mockSamplingStore) GetSamplingStrategy(ctx context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error) {
+	m.processTags = strategystore.GetProcessTags(ctx)
 	if m.samplingResponse == nil {
 		return nil, errors.New("no mock response provided")
 	}
 	return m.samplingResponse, nil
 }
 
+func (m *mockSamplingStore) DryRun(_ context.Context, query strategystore.DryRunQuery) (*strategystore.DryRunResult, error) {
+	m.processTags = query.ProcessTags
+	if m.samplingResponse == nil {
+		return nil, errors.New("no mock response provided")
+	}
+	return &strategystore.DryRunResult{
+		ServiceName: query.ServiceName,
+		Operation:   query.Operation,
+		ServiceRule: &strategystore.MatchedRule{Path: "default_strategy"},
+		Strategy:    m.samplingResponse,
+	}, nil
+}
+
 type mockBaggageMgr struct {
 	baggageResponse []*baggage.BaggageRestriction
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 
 	"github.com/jaegertracing/jaeger/cmd/agent/app/configmanager"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	tSampling "github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -46,6 +47,10 @@ type HTTPHandlerParams struct {
 	// LegacySamplingEndpoint enables returning sampling strategy from "/" endpoint
 	// using Thrift 0.9.2 enum codes.
 	LegacySamplingEndpoint bool
+
+	// SamplingDryRunner enables the "/sampling/dry-run" endpoint, which explains
+	// how the sampling strategy of a service is selected.
+	SamplingDryRunner strategystore.DryRunner
 }
 
 // HTTPHandler implements endpoints for used by Jaeger clients to retrieve client configuration,
@@ -59,6 +64,9 @@ type HTTPHandler struct {
 		// Number of good sampling requests against the old endpoint / using Thrift 0.9.2 enum codes
 		LegacySamplingRequestSuccess metrics.Counter `metric:"http-server.requests" tags:"type=sampling-legacy"`
 
+		// Number of good sampling dry-run requests
+		SamplingDryRunRequestSuccess metrics.Counter `metric:"http-server.requests" tags:"type=sampling-dry-run"`
+
 		// Number of good baggage requests
 		BaggageRequestSuccess metrics.Counter `metric:"http-server.requests" tags:"type=baggage"`
 
@@ -96,6 +104,12 @@ func (h *HTTPHandler) RegisterRoutes(router *mux.Router) {
 		h.serveSamplingHTTP(w, r, false /* thriftEnums092 */)
 	}).Methods(http.MethodGet)
 
+	if h.params.SamplingDryRunner != nil {
+		router.HandleFunc(prefix+"/sampling/dry-run", func(w http.ResponseWriter, r *http.Request) {
+			h.serveSamplingDryRunHTTP(w, r)
+		}).Methods(http.MethodGet)
+	}
+
 	router.HandleFunc(prefix+"/baggageRestrictions", func(w http.ResponseWriter, r *http.Request) {
 		h.serveBaggageHTTP(w, r)
 	}).Methods(http.MethodGet)
@@ -112,6 +126,26 @@ func (h *HTTPHandler) serviceFromRequest(w http.ResponseWriter, r *http.Request)
 	return services[0], nil
 }
 
+// processTagsFromRequest parses the optional "tag" parameters, formatted as "key:value",
+// which clients can use to report their process tags.
+func (h *HTTPHandler) processTagsFromRequest(w http.ResponseWriter, r *http.Request) (map[string]string, error) {
+	params := r.URL.Query()["tag"]
+	if len(params) == 0 {
+		return nil, nil
+	}
+	tags := make(map[string]string, len(params))
+	for _, param := range params {
+		kv := strings.SplitN(param, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			h.metrics.BadRequest.Inc(1)
+			http.Error(w, fmt.Sprintf("malformed 'tag' parameter %q, expecting key:value", param), http.StatusBadRequest)
+			return nil, errBadRequest
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
+
 func (h *HTTPHandler) writeJSON(w http.ResponseWriter, json []byte) error {
 	w.Header().Add("Content-Type", mimeTypeApplicationJSON)
 	if _, err := w.Write(json); err != nil {
@@ -126,7 +160,15 @@ func (h *HTTPHandler) serveSamplingHTTP(w http.ResponseWriter, r *http.Request,
 	if err != nil {
 		return
 	}
-	resp, err := h.params.ConfigManager.GetSamplingStrategy(r.Context(), service)
+	tags, err := h.processTagsFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	ctx := r.Context()
+	if tags != nil {
+		ctx = strategystore.WithProcessTags(ctx, tags)
+	}
+	resp, err := h.params.ConfigManager.GetSamplingStrategy(ctx, service)
 	if err != nil {
 		h.metrics.CollectorProxyFailures.Inc(1)
 		http.Error(w, fmt.Sprintf("collector error: %+v", err), http.StatusInternalServerError)
@@ -151,6 +193,37 @@ func (h *HTTPHandler) serveSamplingHTTP(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+func (h *HTTPHandler) serveSamplingDryRunHTTP(w http.ResponseWriter, r *http.Request) {
+	service, err := h.serviceFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	tags, err := h.processTagsFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	result, err := h.params.SamplingDryRunner.DryRun(r.Context(), strategystore.DryRunQuery{
+		ServiceName: service,
+		Operation:   r.URL.Query().Get("operation"),
+		ProcessTags: tags,
+	})
+	if err != nil {
+		h.metrics.CollectorProxyFailures.Inc(1)
+		http.Error(w, fmt.Sprintf("collector error: %+v", err), http.StatusInternalServerError)
+		return
+	}
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		h.metrics.BadThriftFailures.Inc(1)
+		http.Error(w, "cannot marshall Thrift to JSON", http.StatusInternalServerError)
+		return
+	}
+	if err = h.writeJSON(w, jsonBytes); err != nil {
+		return
+	}
+	h.metrics.SamplingDryRunRequestSuccess.Inc(1)
+}
+
 func (h *HTTPHandler) serveBaggageHTTP(w http.ResponseWriter, r *http.Request) {
 	service, err := h.serviceFromRequest(w, r)
 	if err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics/metricstest"
 
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	tSampling092 "github.com/jaegertracing/jaeger/pkg/clientcfg/clientcfghttp/thrift-0.9.2"
 	"github.com/jaegertracing/jaeger/thrift-gen/baggage"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
@@ -60,6 +61,7 @@ func withServer(
 		MetricsFactory:         metricsFactory,
 		BasePath:               basePath,
 		LegacySamplingEndpoint: true,
+		SamplingDryRunner:      samplingStore,
 	})
 	r := mux.NewRouter()
 	handler.RegisterRoutes(r)
@@ -107,6 +109,30 @@ func testHTTPHandler(t *testing.T, basePath string) {
 			})
 		}
 
+		t.Run("request against endpoint /sampling with process tags", func(t *testing.T) {
+			resp, err := http.Get(ts.server.URL + basePath + "/sampling?service=Y&tag=environment:prod&tag=hostname:host:1")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, map[string]string{"environment": "prod", "hostname": "host:1"}, ts.samplingStore.processTags)
+		})
+
+		t.Run("request against endpoint /sampling/dry-run", func(t *testing.T) {
+			resp, err := http.Get(ts.server.URL + basePath + "/sampling/dry-run?service=Y&operation=op&tag=environment:prod")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+			var objResp strategystore.DryRunResult
+			require.NoError(t, json.Unmarshal(body, &objResp))
+			assert.Equal(t, "Y", objResp.ServiceName)
+			assert.Equal(t, "op", objResp.Operation)
+			assert.Equal(t, "default_strategy", objResp.ServiceRule.Path)
+			assert.EqualValues(t, ts.samplingStore.samplingResponse, objResp.Strategy)
+			assert.Equal(t, map[string]string{"environment": "prod"}, ts.samplingStore.processTags)
+		})
+
 		t.Run("request against endpoint /baggageRestrictions", func(t *testing.T) {
 			resp, err := http.Get(ts.server.URL + basePath + "/baggageRestrictions?service=Y")
 			require.NoError(t, err)
@@ -121,7 +147,8 @@ func testHTTPHandler(t *testing.T, basePath string) {
 
 		// handler must emit metrics
 		ts.metricsFactory.AssertCounterMetrics(t, []metricstest.ExpectedMetric{
-			{Name: "http-server.requests", Tags: map[string]string{"type": "sampling"}, Value: 1},
+			{Name: "http-server.requests", Tags: map[string]string{"type": "sampling"}, Value: 2},
+			{Name: "http-server.requests", Tags: map[string]string{"type": "sampling-dry-run"}, Value: 1},
 			{Name: "http-server.requests", Tags: map[string]string{"type": "sampling-legacy"}, Value: 1},
 			{Name: "http-server.requests", Tags: map[string]string{"type": "baggage"}, Value: 1},
 		}...)
@@ -156,6 +183,52 @@ func TestHTTPHandlerErrors(t *testing.T) {
 				{Name: "http-server.errors", Tags: map[string]string{"source": "all", "status": "4xx"}, Value: 1},
 			},
 		},
+		{
+			description: "sampling endpoint malformed tag",
+			url:         "/sampling?service=Y&tag=environment",
+			statusCode:  http.StatusBadRequest,
+			body:        "malformed 'tag' parameter \"environment\", expecting key:value\n",
+			metrics: []metricstest.ExpectedMetric{
+				{Name: "http-server.errors", Tags: map[string]string{"source": "all", "status": "4xx"}, Value: 1},
+			},
+		},
+		{
+			description: "dry-run endpoint no service name",
+			url:         "/sampling/dry-run",
+			statusCode:  http.StatusBadRequest,
+			body:        "'service' parameter must be provided once\n",
+			metrics: []metricstest.ExpectedMetric{
+				{Name: "http-server.errors", Tags: map[string]string{"source": "all", "status": "4xx"}, Value: 1},
+			},
+		},
+		{
+			description: "dry-run endpoint malformed tag",
+			url:         "/sampling/dry-run?service=Y&tag=:prod",
+			statusCode:  http.StatusBadRequest,
+			body:        "malformed 'tag' parameter \":prod\", expecting key:value\n",
+			metrics: []metricstest.ExpectedMetric{
+				{Name: "http-server.errors", Tags: map[string]string{"source": "all", "status": "4xx"}, Value: 1},
+			},
+		},
+		{
+			description: "dry-run collector error",
+			url:         "/sampling/dry-run?service=Y",
+			statusCode:  http.StatusInternalServerError,
+			body:        "collector error: no mock response provided\n",
+			metrics: []metricstest.ExpectedMetric{
+				{Name: "http-server.errors", Tags: map[string]string{"source": "collector-proxy", "status": "5xx"}, Value: 1},
+			},
+		},
+		{
+			description:          "dry-run marshalling error",
+			mockSamplingResponse: probabilistic(math.NaN()),
+			url:                  "/sampling/dry-run?service=Y",
+			statusCode:           http.StatusInternalServerError,
+			body:                 "cannot marshall Thrift to JSON\n",
+			metrics: []metricstest.ExpectedMetric{
+				{Name: "http-server.errors", Tags: map[string]string{"source": "thrift", "status": "5xx"}, Value: 1},
+			},
+		},
 		{
 			description: "baggage endpoint too many service names",
 			url:         "/baggageRestrictions?service=Y&service=Y",
@@ -230,10 +303,32 @@ func TestHTTPHandlerErrors(t *testing.T) {
 
 			ts.metricsFactory.AssertCounterMetrics(t,
 				metricstest.ExpectedMetric{Name: "http-server.errors", Tags: map[string]string{"source": "write", "status": "5xx"}, Value: 2})
+
+			req = httptest.NewRequest("GET", "http://localhost:80/sampling/dry-run?service=X", nil)
+			handler.serveSamplingDryRunHTTP(w, req)
+
+			ts.metricsFactory.AssertCounterMetrics(t,
+				metricstest.ExpectedMetric{Name: "http-server.errors", Tags: map[string]string{"source": "write", "status": "5xx"}, Value: 3})
 		})
 	})
 }
 
+func TestHTTPHandlerWithoutDryRunner(t *testing.T) {
+	handler := NewHTTPHandler(HTTPHandlerParams{
+		ConfigManager:  &ConfigManager{SamplingStrategyStore: &mockSamplingStore{}},
+		MetricsFactory: metricstest.NewFactory(0),
+	})
+	r := mux.NewRouter()
+	handler.RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/sampling/dry-run?service=Y")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func probabilistic(probability float64) *sampling.SamplingStrategyResponse {
 	return &sampling.SamplingStrategyResponse{
 		StrategyType: sampling.SamplingStrategyType_PROBABILISTIC,
@@ -28,6 +28,9 @@ type Client interface {
 	CreateIndex(index string) IndicesCreateService
 	CreateTemplate(id string) TemplateCreateService
 	Index() IndexService
+	IndexDocument() DocumentIndexService
+	Get() GetService
+	Delete() DeleteService
 	Search(indices ...string) SearchService
 	MultiSearch() MultiSearchService
 	io.Closer
@@ -60,12 +63,44 @@ type IndexService interface {
 	Add()
 }
 
+// DocumentIndexService is an abstraction for elastic.IndexService, which writes a single document synchronously
+type DocumentIndexService interface {
+	Index(index string) DocumentIndexService
+	Type(typ string) DocumentIndexService
+	Id(id string) DocumentIndexService
+	BodyJson(body interface{}) DocumentIndexService
+	OpType(opType string) DocumentIndexService
+	IfSeqNo(seqNo int64) DocumentIndexService
+	IfPrimaryTerm(primaryTerm int64) DocumentIndexService
+	Do(ctx context.Context) (*elastic.IndexResponse, error)
+}
+
+// GetService is an abstraction for elastic.GetService
+type GetService interface {
+	Index(index string) GetService
+	Type(typ string) GetService
+	Id(id string) GetService
+	Do(ctx context.Context) (*elastic.GetResult, error)
+}
+
+// DeleteService is an abstraction for elastic.DeleteService
+type DeleteService interface {
+	Index(index string) DeleteService
+	Type(typ string) DeleteService
+	Id(id string) DeleteService
+	IfSeqNo(seqNo int64) DeleteService
+	IfPrimaryTerm(primaryTerm int64) DeleteService
+	Do(ctx context.Context) (*elastic.DeleteResponse, error)
+}
+
 // SearchService is an abstraction for elastic.SearchService
 type SearchService interface {
 	Size(size int) SearchService
+	Sort(field string, ascending bool) SearchService
 	Aggregation(name string, aggregation elastic.Aggregation) SearchService
 	IgnoreUnavailable(ignoreUnavailable bool) SearchService
 	Query(query elastic.Query) SearchService
+	SearchAfter(sortValues ...interface{}) SearchService
 	Do(ctx context.Context) (*elastic.SearchResult, error)
 }
 
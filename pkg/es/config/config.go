@@ -65,6 +65,8 @@ type Configuration struct {
 	IndexDateLayoutSpans           string         `mapstructure:"-"`
 	IndexDateLayoutServices        string         `mapstructure:"-"`
 	IndexDateLayoutDependencies    string         `mapstructure:"-"`
+	IndexDateLayoutSampling        string         `mapstructure:"-"`
+	IndexDateLayoutSpanMetrics     string         `mapstructure:"-"`
 	IndexRolloverFrequencySpans    string         `mapstructure:"-"`
 	IndexRolloverFrequencyServices string         `mapstructure:"-"`
 	Tags                           TagsAsFields   `mapstructure:"tags_as_fields"`
@@ -104,6 +106,8 @@ type ClientBuilder interface {
 	GetIndexDateLayoutSpans() string
 	GetIndexDateLayoutServices() string
 	GetIndexDateLayoutDependencies() string
+	GetIndexDateLayoutSampling() string
+	GetIndexDateLayoutSpanMetrics() string
 	GetIndexRolloverFrequencySpansDuration() time.Duration
 	GetIndexRolloverFrequencyServicesDuration() time.Duration
 	GetTagsFilePath() string
@@ -316,6 +320,16 @@ func (c *Configuration) GetIndexDateLayoutDependencies() string {
 	return c.IndexDateLayoutDependencies
 }
 
+// GetIndexDateLayoutSampling returns jaeger-sampling index date layout
+func (c *Configuration) GetIndexDateLayoutSampling() string {
+	return c.IndexDateLayoutSampling
+}
+
+// GetIndexDateLayoutSpanMetrics returns jaeger-spanmetrics index date layout
+func (c *Configuration) GetIndexDateLayoutSpanMetrics() string {
+	return c.IndexDateLayoutSpanMetrics
+}
+
 // GetIndexRolloverFrequencySpansDuration returns jaeger-span index rollover frequency duration
 func (c *Configuration) GetIndexRolloverFrequencySpansDuration() time.Duration {
 	if c.IndexRolloverFrequencySpans == "hour" {
@@ -74,6 +74,38 @@ func (_m *Client) CreateTemplate(id string) es.TemplateCreateService {
 	return r0
 }
 
+// Delete provides a mock function with given fields:
+func (_m *Client) Delete() es.DeleteService {
+	ret := _m.Called()
+
+	var r0 es.DeleteService
+	if rf, ok := ret.Get(0).(func() es.DeleteService); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DeleteService)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields:
+func (_m *Client) Get() es.GetService {
+	ret := _m.Called()
+
+	var r0 es.GetService
+	if rf, ok := ret.Get(0).(func() es.GetService); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.GetService)
+		}
+	}
+
+	return r0
+}
+
 // GetVersion provides a mock function with given fields:
 func (_m *Client) GetVersion() uint {
 	ret := _m.Called()
@@ -104,6 +136,22 @@ func (_m *Client) Index() es.IndexService {
 	return r0
 }
 
+// IndexDocument provides a mock function with given fields:
+func (_m *Client) IndexDocument() es.DocumentIndexService {
+	ret := _m.Called()
+
+	var r0 es.DocumentIndexService
+	if rf, ok := ret.Get(0).(func() es.DocumentIndexService); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.DocumentIndexService)
+		}
+	}
+
+	return r0
+}
+
 // IndexExists provides a mock function with given fields: index
 func (_m *Client) IndexExists(index string) es.IndicesExistsService {
 	ret := _m.Called(index)
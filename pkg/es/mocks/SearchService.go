@@ -102,6 +102,24 @@ func (_m *SearchService) Query(query elastic.Query) es.SearchService {
 	return r0
 }
 
+// SearchAfter provides a mock function with given fields: sortValues
+func (_m *SearchService) SearchAfter(sortValues ...interface{}) es.SearchService {
+	var _ca []interface{}
+	_ca = append(_ca, sortValues...)
+	ret := _m.Called(_ca...)
+
+	var r0 es.SearchService
+	if rf, ok := ret.Get(0).(func(...interface{}) es.SearchService); ok {
+		r0 = rf(sortValues...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.SearchService)
+		}
+	}
+
+	return r0
+}
+
 // Size provides a mock function with given fields: size
 func (_m *SearchService) Size(size int) es.SearchService {
 	ret := _m.Called(size)
@@ -117,3 +135,19 @@ func (_m *SearchService) Size(size int) es.SearchService {
 
 	return r0
 }
+
+// Sort provides a mock function with given fields: field, ascending
+func (_m *SearchService) Sort(field string, ascending bool) es.SearchService {
+	ret := _m.Called(field, ascending)
+
+	var r0 es.SearchService
+	if rf, ok := ret.Get(0).(func(string, bool) es.SearchService); ok {
+		r0 = rf(field, ascending)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(es.SearchService)
+		}
+	}
+
+	return r0
+}
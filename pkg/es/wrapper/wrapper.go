@@ -63,6 +63,21 @@ func (c ClientWrapper) Index() es.IndexService {
 	return WrapESIndexService(r, c.bulkService, c.esVersion)
 }
 
+// IndexDocument calls Index of the internal client.
+func (c ClientWrapper) IndexDocument() es.DocumentIndexService {
+	return WrapESDocumentIndexService(c.client.Index())
+}
+
+// Get calls this function to internal client.
+func (c ClientWrapper) Get() es.GetService {
+	return WrapESGetService(c.client.Get())
+}
+
+// Delete calls this function to internal client.
+func (c ClientWrapper) Delete() es.DeleteService {
+	return WrapESDeleteService(c.client.Delete())
+}
+
 // Search calls this function to internal client.
 func (c ClientWrapper) Search(indices ...string) es.SearchService {
 	searchService := c.client.Search(indices...)
@@ -180,6 +195,115 @@ func (i IndexServiceWrapper) Add() {
 
 // ---
 
+// DocumentIndexServiceWrapper is a wrapper around elastic.IndexService.
+// See wrapper_nolint.go for more functions.
+type DocumentIndexServiceWrapper struct {
+	indexService *elastic.IndexService
+}
+
+// WrapESDocumentIndexService creates an DocumentIndexService out of *elastic.IndexService.
+func WrapESDocumentIndexService(indexService *elastic.IndexService) DocumentIndexServiceWrapper {
+	return DocumentIndexServiceWrapper{indexService: indexService}
+}
+
+// Index calls this function to internal service.
+func (i DocumentIndexServiceWrapper) Index(index string) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.Index(index))
+}
+
+// Type calls this function to internal service.
+func (i DocumentIndexServiceWrapper) Type(typ string) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.Type(typ))
+}
+
+// OpType calls this function to internal service.
+func (i DocumentIndexServiceWrapper) OpType(opType string) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.OpType(opType))
+}
+
+// IfSeqNo calls this function to internal service.
+func (i DocumentIndexServiceWrapper) IfSeqNo(seqNo int64) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.IfSeqNo(seqNo))
+}
+
+// IfPrimaryTerm calls this function to internal service.
+func (i DocumentIndexServiceWrapper) IfPrimaryTerm(primaryTerm int64) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.IfPrimaryTerm(primaryTerm))
+}
+
+// Do calls this function to internal service.
+func (i DocumentIndexServiceWrapper) Do(ctx context.Context) (*elastic.IndexResponse, error) {
+	return i.indexService.Do(ctx)
+}
+
+// ---
+
+// GetServiceWrapper is a wrapper around elastic.GetService.
+// See wrapper_nolint.go for more functions.
+type GetServiceWrapper struct {
+	getService *elastic.GetService
+}
+
+// WrapESGetService creates an GetService out of *elastic.GetService.
+func WrapESGetService(getService *elastic.GetService) GetServiceWrapper {
+	return GetServiceWrapper{getService: getService}
+}
+
+// Index calls this function to internal service.
+func (g GetServiceWrapper) Index(index string) es.GetService {
+	return WrapESGetService(g.getService.Index(index))
+}
+
+// Type calls this function to internal service.
+func (g GetServiceWrapper) Type(typ string) es.GetService {
+	return WrapESGetService(g.getService.Type(typ))
+}
+
+// Do calls this function to internal service.
+func (g GetServiceWrapper) Do(ctx context.Context) (*elastic.GetResult, error) {
+	return g.getService.Do(ctx)
+}
+
+// ---
+
+// DeleteServiceWrapper is a wrapper around elastic.DeleteService.
+// See wrapper_nolint.go for more functions.
+type DeleteServiceWrapper struct {
+	deleteService *elastic.DeleteService
+}
+
+// WrapESDeleteService creates an DeleteService out of *elastic.DeleteService.
+func WrapESDeleteService(deleteService *elastic.DeleteService) DeleteServiceWrapper {
+	return DeleteServiceWrapper{deleteService: deleteService}
+}
+
+// Index calls this function to internal service.
+func (d DeleteServiceWrapper) Index(index string) es.DeleteService {
+	return WrapESDeleteService(d.deleteService.Index(index))
+}
+
+// Type calls this function to internal service.
+func (d DeleteServiceWrapper) Type(typ string) es.DeleteService {
+	return WrapESDeleteService(d.deleteService.Type(typ))
+}
+
+// IfSeqNo calls this function to internal service.
+func (d DeleteServiceWrapper) IfSeqNo(seqNo int64) es.DeleteService {
+	return WrapESDeleteService(d.deleteService.IfSeqNo(seqNo))
+}
+
+// IfPrimaryTerm calls this function to internal service.
+func (d DeleteServiceWrapper) IfPrimaryTerm(primaryTerm int64) es.DeleteService {
+	return WrapESDeleteService(d.deleteService.IfPrimaryTerm(primaryTerm))
+}
+
+// Do calls this function to internal service.
+func (d DeleteServiceWrapper) Do(ctx context.Context) (*elastic.DeleteResponse, error) {
+	return d.deleteService.Do(ctx)
+}
+
+// ---
+
 // SearchServiceWrapper is a wrapper around elastic.ESSearchService
 type SearchServiceWrapper struct {
 	searchService *elastic.SearchService
@@ -195,6 +319,11 @@ func (s SearchServiceWrapper) Size(size int) es.SearchService {
 	return WrapESSearchService(s.searchService.Size(size))
 }
 
+// Sort calls this function to internal service.
+func (s SearchServiceWrapper) Sort(field string, ascending bool) es.SearchService {
+	return WrapESSearchService(s.searchService.Sort(field, ascending))
+}
+
 // Aggregation calls this function to internal service.
 func (s SearchServiceWrapper) Aggregation(name string, aggregation elastic.Aggregation) es.SearchService {
 	return WrapESSearchService(s.searchService.Aggregation(name, aggregation))
@@ -210,6 +339,11 @@ func (s SearchServiceWrapper) Query(query elastic.Query) es.SearchService {
 	return WrapESSearchService(s.searchService.Query(query))
 }
 
+// SearchAfter calls this function to internal service.
+func (s SearchServiceWrapper) SearchAfter(sortValues ...interface{}) es.SearchService {
+	return WrapESSearchService(s.searchService.SearchAfter(sortValues...))
+}
+
 // Do calls this function to internal service.
 func (s SearchServiceWrapper) Do(ctx context.Context) (*elastic.SearchResult, error) {
 	return s.searchService.Do(ctx)
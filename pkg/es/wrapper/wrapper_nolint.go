@@ -28,3 +28,23 @@ func (i IndexServiceWrapper) Id(id string) es.IndexService {
 func (i IndexServiceWrapper) BodyJson(body interface{}) es.IndexService {
 	return WrapESIndexService(i.bulkIndexReq.Doc(body), i.bulkService, i.esVersion)
 }
+
+// Id calls this function to internal service.
+func (i DocumentIndexServiceWrapper) Id(id string) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.Id(id))
+}
+
+// BodyJson calls this function to internal service.
+func (i DocumentIndexServiceWrapper) BodyJson(body interface{}) es.DocumentIndexService {
+	return WrapESDocumentIndexService(i.indexService.BodyJson(body))
+}
+
+// Id calls this function to internal service.
+func (g GetServiceWrapper) Id(id string) es.GetService {
+	return WrapESGetService(g.getService.Id(id))
+}
+
+// Id calls this function to internal service.
+func (d DeleteServiceWrapper) Id(id string) es.DeleteService {
+	return WrapESDeleteService(d.deleteService.Id(id))
+}
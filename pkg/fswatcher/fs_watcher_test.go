@@ -37,4 +37,6 @@ func TestFsWatcher(t *testing.T) {
 
 	errs := w.Errors()
 	assert.NotZero(t, errs)
+
+	assert.NoError(t, w.Close())
 }
@@ -20,6 +20,7 @@ import (
 
 	gogoproto "github.com/gogo/protobuf/proto"
 	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/proto" // must be initialized first, so that its codec is overridden below
 	"google.golang.org/protobuf/proto"
 )
 
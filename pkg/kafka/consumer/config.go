@@ -15,7 +15,9 @@
 package consumer
 
 import (
+	"fmt"
 	"io"
+	"regexp"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -44,6 +46,8 @@ type Configuration struct {
 
 	Brokers         []string `mapstructure:"brokers"`
 	Topic           string   `mapstructure:"topic"`
+	Topics          []string `mapstructure:"topics"`
+	TopicRegex      string   `mapstructure:"topic_regex"`
 	GroupID         string   `mapstructure:"group_id"`
 	ClientID        string   `mapstructure:"client_id"`
 	ProtocolVersion string   `mapstructure:"protocol_version"`
@@ -69,5 +73,41 @@ func (c *Configuration) NewConsumer(logger *zap.Logger) (Consumer, error) {
 	// that does not set saramaConfig.Consumer.Offsets.CommitInterval to its default value 1s.
 	// then the samara-cluster fails if the default interval is not 1s.
 	saramaConfig.Consumer.Offsets.CommitInterval = time.Second
-	return cluster.NewConsumer(c.Brokers, c.GroupID, []string{c.Topic}, saramaConfig)
+	if c.TopicRegex != "" {
+		re, err := regexp.Compile(c.TopicRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic regex: %w", err)
+		}
+		saramaConfig.Group.Topics.Whitelist = re
+	}
+	return cluster.NewConsumer(c.Brokers, c.GroupID, c.topics(), saramaConfig)
+}
+
+// topics returns the topics to consume from, Topics taking precedence over Topic.
+// The topics matching TopicRegex are consumed in addition to them.
+func (c *Configuration) topics() []string {
+	if len(c.Topics) > 0 {
+		return c.Topics
+	}
+	if c.Topic != "" {
+		return []string{c.Topic}
+	}
+	return nil
+}
+
+// NewClient creates a new kafka client, which is not bound to the consumer group
+func (c *Configuration) NewClient(logger *zap.Logger) (sarama.Client, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = c.ClientID
+	if len(c.ProtocolVersion) > 0 {
+		ver, err := sarama.ParseKafkaVersion(c.ProtocolVersion)
+		if err != nil {
+			return nil, err
+		}
+		saramaConfig.Version = ver
+	}
+	if err := c.AuthenticationConfig.SetConfiguration(saramaConfig, logger); err != nil {
+		return nil, err
+	}
+	return sarama.NewClient(c.Brokers, saramaConfig)
 }
@@ -44,6 +44,23 @@ type Configuration struct {
 
 // NewProducer creates a new asynchronous kafka producer
 func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, error) {
+	saramaConfig, err := c.newSaramaConfig(logger)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewAsyncProducer(c.Brokers, saramaConfig)
+}
+
+// NewSyncProducer creates a new synchronous kafka producer
+func (c *Configuration) NewSyncProducer(logger *zap.Logger) (sarama.SyncProducer, error) {
+	saramaConfig, err := c.newSaramaConfig(logger)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewSyncProducer(c.Brokers, saramaConfig)
+}
+
+func (c *Configuration) newSaramaConfig(logger *zap.Logger) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Producer.RequiredAcks = c.RequiredAcks
 	saramaConfig.Producer.Compression = c.Compression
@@ -63,5 +80,5 @@ func (c *Configuration) NewProducer(logger *zap.Logger) (sarama.AsyncProducer, e
 	if err := c.AuthenticationConfig.SetConfiguration(saramaConfig, logger); err != nil {
 		return nil, err
 	}
-	return sarama.NewAsyncProducer(c.Brokers, saramaConfig)
+	return saramaConfig, nil
 }
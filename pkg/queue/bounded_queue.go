@@ -30,6 +30,20 @@ type Consumer interface {
 	Consume(item interface{})
 }
 
+// Queue is the producer-consumer contract shared by the in-memory and the persistent queues.
+type Queue interface {
+	// StartConsumers starts a given number of goroutines passing the items of the queue to the callback.
+	StartConsumers(num int, callback func(item interface{}))
+	// Produce submits an item to the queue and returns false if the item was dropped.
+	Produce(item interface{}) bool
+	// Size returns the number of items in the queue.
+	Size() int
+	// Capacity returns the maximum number of items in the queue.
+	Capacity() int
+	// Stop stops the consumers and releases the resources of the queue.
+	Stop()
+}
+
 // BoundedQueue implements a producer-consumer exchange similar to a ring buffer queue,
 // where the queue is bounded and if it fills up due to slow consumers, the new items written by
 // the producer force the earliest items to be dropped. The implementation is actually based on
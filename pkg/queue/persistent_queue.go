@@ -0,0 +1,593 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// SyncAlways flushes every item to disk before Produce returns.
+	SyncAlways = "always"
+	// SyncInterval flushes the written items to disk periodically.
+	SyncInterval = "interval"
+	// SyncNever leaves flushing to the operating system.
+	SyncNever = "never"
+
+	// DefaultSegmentSize is the default size of a segment file.
+	DefaultSegmentSize = 64 * 1024 * 1024
+	// DefaultMaxSize is the default maximum size of the segment files of the queue.
+	DefaultMaxSize = 1024 * 1024 * 1024
+	// DefaultSyncInterval is the default period for flushing the items and saving the consumed position.
+	DefaultSyncInterval = time.Second
+)
+
+// PersistentQueueOptions configures a PersistentQueue.
+type PersistentQueueOptions struct {
+	// Directory holds the segment files and the checkpoint of the queue.
+	Directory string
+	// SegmentSize is the size above which a new segment file is started.
+	SegmentSize int64
+	// MaxSize is the maximum size of the segment files; items are dropped when it is reached.
+	MaxSize int64
+	// SyncPolicy is one of SyncAlways, SyncInterval and SyncNever.
+	SyncPolicy string
+	// SyncInterval is how often the items are flushed with SyncInterval, and the consumed position saved.
+	SyncInterval time.Duration
+	// Encode and Decode convert the items to and from their representation on disk.
+	Encode func(item interface{}) ([]byte, error)
+	Decode func(data []byte) (interface{}, error)
+
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+}
+
+type persistentQueueMetrics struct {
+	// BytesOnDisk is the size of the segment files
+	BytesOnDisk metrics.Gauge `metric:"bytes_on_disk"`
+	// Segments is the number of segment files
+	Segments metrics.Gauge `metric:"segments"`
+	// ReplayPending is the number of items found on disk at startup which have not been consumed yet
+	ReplayPending metrics.Gauge `metric:"replay_pending"`
+	// Replayed counts the items found on disk at startup which have been consumed
+	Replayed metrics.Counter `metric:"replayed"`
+	// WriteErrors counts the items which could not be written to disk
+	WriteErrors metrics.Counter `metric:"write_errors"`
+	// CorruptedRecords counts the records which could not be read or decoded
+	CorruptedRecords metrics.Counter `metric:"corrupted_records"`
+}
+
+// PersistentQueue is a write-ahead queue storing the items in segment files on disk before they are
+// consumed, so that the items survive slow consumers and restarts. Consumed items are acknowledged
+// once the consumer returns, and the position of the oldest unacknowledged item is saved periodically;
+// the items after that position are consumed again after a crash, so delivery is at least once.
+// Fully consumed segment files are deleted.
+type PersistentQueue struct {
+	opts    PersistentQueueOptions
+	logger  *zap.Logger
+	metrics persistentQueueMetrics
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	stopped bool
+	// segments are ordered by id, the last one being written to.
+	segments  []*segment
+	writeFile *os.File
+	dirty     bool
+	// bytes is the total size of the segment files.
+	bytes int64
+	// pending is the number of items produced and not yet consumed.
+	pending int
+
+	readFile    *os.File
+	readPos     position
+	readRecords int
+
+	// inflight holds the items passed to the consumers in the order they were read,
+	// committed is the end of the last item before the first unacknowledged one.
+	inflight  []*inflightRecord
+	committed position
+
+	replayEnd     position
+	replayTotal   int
+	replayedCount int
+
+	items        chan *persistentItem
+	stopCh       chan struct{}
+	startOnce    sync.Once
+	dispatcherWG sync.WaitGroup
+	workersWG    sync.WaitGroup
+	syncWG       sync.WaitGroup
+}
+
+type inflightRecord struct {
+	end  position
+	done bool
+}
+
+type persistentItem struct {
+	value  interface{}
+	record *inflightRecord
+}
+
+// NewPersistentQueue opens the queue in the directory, recovering the items which were not consumed
+// before the queue was last stopped.
+func NewPersistentQueue(opts PersistentQueueOptions) (*PersistentQueue, error) {
+	if opts.Directory == "" {
+		return nil, fmt.Errorf("the persistent queue requires a directory")
+	}
+	if opts.Encode == nil || opts.Decode == nil {
+		return nil, fmt.Errorf("the persistent queue requires an encoder and a decoder")
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = DefaultSyncInterval
+	}
+	switch opts.SyncPolicy {
+	case "":
+		opts.SyncPolicy = SyncInterval
+	case SyncAlways, SyncInterval, SyncNever:
+	default:
+		return nil, fmt.Errorf("unknown sync policy %q, must be one of %s, %s or %s", opts.SyncPolicy, SyncAlways, SyncInterval, SyncNever)
+	}
+	if opts.MetricsFactory == nil {
+		opts.MetricsFactory = metrics.NullFactory
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	q := &PersistentQueue{
+		opts:   opts,
+		logger: opts.Logger,
+		items:  make(chan *persistentItem),
+		stopCh: make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	metrics.Init(&q.metrics, opts.MetricsFactory, nil)
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+	q.syncWG.Add(1)
+	go q.syncLoop()
+	return q, nil
+}
+
+// recover loads the segment files, skipping the consumed records and truncating the records
+// which were partially written before a crash.
+func (q *PersistentQueue) recover() error {
+	dir := q.opts.Directory
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create the queue directory: %w", err)
+	}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list the queue segments: %w", err)
+	}
+	checkpoint, found, err := readCheckpoint(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the queue checkpoint: %w", err)
+	}
+
+	// segment ids keep increasing, so that a stale checkpoint never points to a new segment
+	nextID := uint64(1)
+	if found {
+		nextID = checkpoint.segment + 1
+	}
+	for len(segments) > 0 && found && segments[0].id < checkpoint.segment {
+		if err := os.Remove(segmentPath(dir, segments[0].id)); err != nil {
+			return fmt.Errorf("failed to remove a consumed queue segment: %w", err)
+		}
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		segments = []*segment{{id: nextID}}
+		checkpoint = position{segment: nextID}
+	} else if !found || segments[0].id != checkpoint.segment || checkpoint.offset > segments[0].size {
+		checkpoint = position{segment: segments[0].id}
+	}
+
+	for i, s := range segments {
+		path := segmentPath(dir, s.id)
+		offset := int64(0)
+		if i == 0 {
+			offset = checkpoint.offset
+		}
+		if s.size > 0 {
+			records, end, err := scanSegment(path, offset, q.opts.MaxSize)
+			if err != nil {
+				return fmt.Errorf("failed to read the queue segment %s: %w", path, err)
+			}
+			if end < s.size {
+				q.logger.Warn("Truncating the corrupted end of a queue segment",
+					zap.String("segment", path), zap.Int64("offset", end), zap.Int64("size", s.size))
+				if err := os.Truncate(path, end); err != nil {
+					return fmt.Errorf("failed to truncate the queue segment %s: %w", path, err)
+				}
+				s.size = end
+			}
+			s.records = records
+		}
+		q.pending += s.records
+		q.bytes += s.size
+	}
+
+	active := segments[len(segments)-1]
+	q.writeFile, err = os.OpenFile(segmentPath(dir, active.id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open the queue segment: %w", err)
+	}
+	q.segments = segments
+	q.readPos = checkpoint
+	q.committed = checkpoint
+	q.replayEnd = q.writePos()
+	q.replayTotal = q.pending
+	q.updateGauges()
+	if q.replayTotal > 0 {
+		q.logger.Info("Replaying the items of the persistent queue",
+			zap.Int("items", q.replayTotal), zap.Int64("bytes", q.bytes))
+	}
+	return nil
+}
+
+func (q *PersistentQueue) writePos() position {
+	active := q.segments[len(q.segments)-1]
+	return position{segment: active.id, offset: active.size}
+}
+
+func (q *PersistentQueue) updateGauges() {
+	q.metrics.BytesOnDisk.Update(q.bytes)
+	q.metrics.Segments.Update(int64(len(q.segments)))
+	q.metrics.ReplayPending.Update(int64(q.replayTotal - q.replayedCount))
+}
+
+// StartConsumers starts a given number of goroutines passing the items of the queue to the callback.
+// An item is acknowledged once the callback returns.
+func (q *PersistentQueue) StartConsumers(num int, callback func(item interface{})) {
+	q.startOnce.Do(func() {
+		q.dispatcherWG.Add(1)
+		go q.dispatch()
+	})
+	for i := 0; i < num; i++ {
+		q.workersWG.Add(1)
+		go func() {
+			defer q.workersWG.Done()
+			for item := range q.items {
+				callback(item.value)
+				q.ack(item.record)
+			}
+		}()
+	}
+}
+
+// Produce writes the item to disk. It returns false if the item cannot be encoded or written,
+// if the queue is stopped, or if the queue has reached its maximum size.
+func (q *PersistentQueue) Produce(item interface{}) bool {
+	data, err := q.opts.Encode(item)
+	if err != nil {
+		q.logger.Error("Failed to encode a queue item", zap.Error(err))
+		return false
+	}
+	record := encodeRecord(data)
+	size := int64(len(record))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped || q.bytes+size > q.opts.MaxSize {
+		return false
+	}
+	active := q.segments[len(q.segments)-1]
+	if active.size > 0 && active.size+size > q.opts.SegmentSize {
+		if err := q.rotate(); err != nil {
+			q.metrics.WriteErrors.Inc(1)
+			q.logger.Error("Failed to start a new queue segment", zap.Error(err))
+			return false
+		}
+		active = q.segments[len(q.segments)-1]
+	}
+	if _, err := q.writeFile.Write(record); err != nil {
+		q.metrics.WriteErrors.Inc(1)
+		q.logger.Error("Failed to write a queue item", zap.Error(err))
+		// remove the partial record, if any, so that the following records can be read
+		if err := q.writeFile.Truncate(active.size); err != nil {
+			q.logger.Error("Failed to truncate the queue segment", zap.Error(err))
+		}
+		return false
+	}
+	if q.opts.SyncPolicy == SyncAlways {
+		if err := q.writeFile.Sync(); err != nil {
+			q.metrics.WriteErrors.Inc(1)
+			q.logger.Error("Failed to sync the queue segment", zap.Error(err))
+		}
+	} else {
+		q.dirty = true
+	}
+	active.size += size
+	active.records++
+	q.bytes += size
+	q.pending++
+	q.metrics.BytesOnDisk.Update(q.bytes)
+	q.cond.Signal()
+	return true
+}
+
+// rotate seals the active segment and starts a new one.
+func (q *PersistentQueue) rotate() error {
+	if q.opts.SyncPolicy != SyncNever {
+		if err := q.writeFile.Sync(); err != nil {
+			return err
+		}
+	}
+	id := q.segments[len(q.segments)-1].id + 1
+	f, err := os.OpenFile(segmentPath(q.opts.Directory, id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := q.writeFile.Close(); err != nil {
+		q.logger.Warn("Failed to close the queue segment", zap.Error(err))
+	}
+	q.writeFile = f
+	q.dirty = false
+	q.segments = append(q.segments, &segment{id: id})
+	q.metrics.Segments.Update(int64(len(q.segments)))
+	return nil
+}
+
+// dispatch passes the items read from disk to the consumers until the queue is stopped.
+func (q *PersistentQueue) dispatch() {
+	defer q.dispatcherWG.Done()
+	defer close(q.items)
+	for {
+		item, ok := q.next()
+		if !ok {
+			return
+		}
+		select {
+		case q.items <- item:
+		case <-q.stopCh:
+			return
+		}
+	}
+}
+
+// next blocks until an item is available and returns it, or returns false once the queue is stopped.
+func (q *PersistentQueue) next() (*persistentItem, bool) {
+	for {
+		q.mu.Lock()
+		for !q.stopped && q.readPos == q.writePos() {
+			q.cond.Wait()
+		}
+		if q.stopped {
+			q.mu.Unlock()
+			return nil, false
+		}
+		if idx := q.segmentIndex(q.readPos.segment); q.readPos.offset >= q.segments[idx].size {
+			// the segment is sealed, since it has been fully read and the write position is ahead
+			q.readPos = position{segment: q.segments[idx+1].id}
+			q.readRecords = 0
+			q.closeReadFile()
+		}
+		pos := q.readPos
+		q.mu.Unlock()
+
+		data, n, err := q.read(pos)
+		if err != nil {
+			q.skipSegment(pos, err)
+			continue
+		}
+		value, err := q.opts.Decode(data)
+
+		q.mu.Lock()
+		end := position{segment: pos.segment, offset: pos.offset + n}
+		q.readPos = end
+		q.readRecords++
+		if pos.before(q.replayEnd) {
+			q.replayedCount++
+			q.metrics.Replayed.Inc(1)
+			q.metrics.ReplayPending.Update(int64(q.replayTotal - q.replayedCount))
+			if q.replayedCount == q.replayTotal {
+				q.logger.Info("Finished replaying the items of the persistent queue", zap.Int("items", q.replayTotal))
+			}
+		}
+		record := &inflightRecord{end: end}
+		q.inflight = append(q.inflight, record)
+		q.mu.Unlock()
+
+		if err != nil {
+			q.metrics.CorruptedRecords.Inc(1)
+			q.logger.Error("Failed to decode a queue item", zap.Error(err))
+			q.ack(record)
+			continue
+		}
+		return &persistentItem{value: value, record: record}, true
+	}
+}
+
+func (q *PersistentQueue) read(pos position) ([]byte, int64, error) {
+	if q.readFile == nil {
+		f, err := os.Open(filepath.Clean(segmentPath(q.opts.Directory, pos.segment)))
+		if err != nil {
+			return nil, 0, err
+		}
+		q.readFile = f
+	}
+	data, n, err := readRecord(q.readFile, pos.offset, q.opts.MaxSize)
+	if err == io.EOF {
+		err = errCorruptedRecord
+	}
+	return data, n, err
+}
+
+// skipSegment gives up on the rest of a segment which cannot be read, the remaining items of the segment are lost.
+func (q *PersistentQueue) skipSegment(pos position, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := q.segments[q.segmentIndex(pos.segment)]
+	skipped := s.records - q.readRecords
+	q.logger.Error("Failed to read the queue segment, skipping the rest of the segment",
+		zap.String("segment", segmentPath(q.opts.Directory, pos.segment)),
+		zap.Int64("offset", pos.offset),
+		zap.Int("items", skipped),
+		zap.Error(err))
+	q.metrics.CorruptedRecords.Inc(int64(skipped))
+	q.pending -= skipped
+	q.readRecords = s.records
+	q.readPos.offset = s.size
+	if pos.segment == q.writePos().segment {
+		// the segment being written cannot be skipped without losing the next items
+		if err := q.rotate(); err != nil {
+			q.logger.Error("Failed to start a new queue segment", zap.Error(err))
+		}
+	}
+}
+
+func (q *PersistentQueue) segmentIndex(id uint64) int {
+	for i, s := range q.segments {
+		if s.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (q *PersistentQueue) closeReadFile() {
+	if q.readFile != nil {
+		q.readFile.Close()
+		q.readFile = nil
+	}
+}
+
+// ack marks the record as consumed, advances the committed position over the consecutive
+// consumed records, and deletes the segments which have been fully consumed.
+func (q *PersistentQueue) ack(record *inflightRecord) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	record.done = true
+	for len(q.inflight) > 0 && q.inflight[0].done {
+		q.committed = q.inflight[0].end
+		q.inflight[0] = nil
+		q.inflight = q.inflight[1:]
+		q.pending--
+	}
+	for len(q.segments) > 1 && q.segments[0].id < q.committed.segment {
+		s := q.segments[0]
+		if err := os.Remove(segmentPath(q.opts.Directory, s.id)); err != nil {
+			q.logger.Error("Failed to remove a consumed queue segment", zap.Error(err))
+			return
+		}
+		q.bytes -= s.size
+		q.segments = q.segments[1:]
+		q.updateGauges()
+	}
+}
+
+// syncLoop periodically flushes the segment being written and saves the committed position.
+func (q *PersistentQueue) syncLoop() {
+	defer q.syncWG.Done()
+	ticker := time.NewTicker(q.opts.SyncInterval)
+	defer ticker.Stop()
+	var saved position
+	for {
+		select {
+		case <-ticker.C:
+			saved = q.sync(saved)
+		case <-q.stopCh:
+			return
+		}
+	}
+}
+
+func (q *PersistentQueue) sync(saved position) position {
+	q.mu.Lock()
+	if q.dirty && q.opts.SyncPolicy == SyncInterval {
+		if err := q.writeFile.Sync(); err != nil {
+			q.metrics.WriteErrors.Inc(1)
+			q.logger.Error("Failed to sync the queue segment", zap.Error(err))
+		}
+		q.dirty = false
+	}
+	committed := q.committed
+	q.mu.Unlock()
+	if committed == saved {
+		return saved
+	}
+	if err := writeCheckpoint(q.opts.Directory, committed); err != nil {
+		q.logger.Error("Failed to save the queue checkpoint", zap.Error(err))
+		return saved
+	}
+	return committed
+}
+
+// Size returns the number of items on disk which have not been consumed.
+func (q *PersistentQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// Capacity returns 0, since the persistent queue is bounded by its size on disk rather than by a number of items.
+func (q *PersistentQueue) Capacity() int {
+	return 0
+}
+
+// Bytes returns the size of the segment files of the queue.
+func (q *PersistentQueue) Bytes() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.bytes
+}
+
+// Stop stops the consumers once their current items are consumed, and saves the committed position.
+// The items which have not been consumed remain on disk, to be consumed when the queue is reopened.
+func (q *PersistentQueue) Stop() {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	close(q.stopCh)
+	q.dispatcherWG.Wait()
+	q.workersWG.Wait()
+	q.syncWG.Wait()
+
+	if q.opts.SyncPolicy != SyncNever {
+		if err := q.writeFile.Sync(); err != nil {
+			q.logger.Error("Failed to sync the queue segment", zap.Error(err))
+		}
+	}
+	// force saving the committed position
+	q.sync(position{segment: ^uint64(0)})
+	if err := q.writeFile.Close(); err != nil {
+		q.logger.Error("Failed to close the queue segment", zap.Error(err))
+	}
+	q.closeReadFile()
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+)
+
+func newTestPersistentQueue(t *testing.T, dir string, configure ...func(*PersistentQueueOptions)) (*PersistentQueue, *metricstest.Factory) {
+	mf := metricstest.NewFactory(time.Hour)
+	opts := PersistentQueueOptions{
+		Directory:      dir,
+		SyncInterval:   10 * time.Millisecond,
+		Encode:         func(item interface{}) ([]byte, error) { return []byte(item.(string)), nil },
+		Decode:         func(data []byte) (interface{}, error) { return string(data), nil },
+		MetricsFactory: mf,
+	}
+	for _, c := range configure {
+		c(&opts)
+	}
+	q, err := NewPersistentQueue(opts)
+	require.NoError(t, err)
+	return q, mf
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "queue")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+type collectedItems struct {
+	sync.Mutex
+	items []string
+}
+
+func (c *collectedItems) add(item interface{}) {
+	c.Lock()
+	defer c.Unlock()
+	c.items = append(c.items, item.(string))
+}
+
+func (c *collectedItems) get() []string {
+	c.Lock()
+	defer c.Unlock()
+	items := append([]string(nil), c.items...)
+	sort.Strings(items)
+	return items
+}
+
+func TestPersistentQueueProduceConsume(t *testing.T) {
+	q, _ := newTestPersistentQueue(t, tempDir(t))
+	var _ Queue = q
+
+	var consumed collectedItems
+	q.StartConsumers(3, consumed.add)
+	for _, item := range []string{"a", "b", "c"} {
+		assert.True(t, q.Produce(item))
+	}
+	assert.Eventually(t, func() bool { return len(consumed.get()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, consumed.get())
+	assert.Eventually(t, func() bool { return q.Size() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, 0, q.Capacity())
+
+	q.Stop()
+	q.Stop()
+	assert.False(t, q.Produce("d"))
+}
+
+func TestPersistentQueueReplay(t *testing.T) {
+	dir := tempDir(t)
+	q, _ := newTestPersistentQueue(t, dir)
+	for _, item := range []string{"a", "b", "c", "d"} {
+		assert.True(t, q.Produce(item))
+	}
+	assert.Equal(t, 4, q.Size())
+	q.Stop()
+
+	// consume the first two items only
+	q, _ = newTestPersistentQueue(t, dir)
+	assert.Equal(t, 4, q.Size())
+	var consumed collectedItems
+	release := make(chan struct{})
+	q.StartConsumers(1, func(item interface{}) {
+		if len(consumed.get()) == 2 {
+			<-release
+		}
+		consumed.add(item)
+	})
+	assert.Eventually(t, func() bool { return len(consumed.get()) == 2 }, time.Second, time.Millisecond)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	q.Stop()
+
+	// the third item was being consumed while stopping, the fourth one is replayed
+	q, mf := newTestPersistentQueue(t, dir)
+	consumed = collectedItems{}
+	q.StartConsumers(1, consumed.add)
+	assert.Eventually(t, func() bool { return len(consumed.get()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"d"}, consumed.get())
+	q.Stop()
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "replayed", Value: 1})
+	mf.AssertGaugeMetrics(t, metricstest.ExpectedMetric{Name: "replay_pending", Value: 0})
+
+	q, _ = newTestPersistentQueue(t, dir)
+	assert.Equal(t, 0, q.Size())
+	q.Stop()
+}
+
+func TestPersistentQueueSegments(t *testing.T) {
+	dir := tempDir(t)
+	q, mf := newTestPersistentQueue(t, dir, func(opts *PersistentQueueOptions) {
+		opts.SegmentSize = 3 * (recordHeaderSize + 4)
+		opts.MaxSize = 10 * (recordHeaderSize + 4)
+	})
+	for i := 0; i < 10; i++ {
+		assert.True(t, q.Produce("item"))
+	}
+	assert.False(t, q.Produce("item"), "the queue is full")
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	assert.Len(t, segments, 4)
+	assert.EqualValues(t, 10*(recordHeaderSize+4), q.Bytes())
+
+	var consumed collectedItems
+	q.StartConsumers(2, consumed.add)
+	assert.Eventually(t, func() bool { return q.Size() == 0 }, time.Second, time.Millisecond)
+	segments, err = listSegments(dir)
+	require.NoError(t, err)
+	assert.Len(t, segments, 1)
+	assert.EqualValues(t, recordHeaderSize+4, q.Bytes())
+	assert.True(t, q.Produce("item"))
+	q.Stop()
+
+	mf.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "segments", Value: 1},
+		metricstest.ExpectedMetric{Name: "bytes_on_disk", Value: 2 * (recordHeaderSize + 4)},
+	)
+}
+
+func TestPersistentQueueTruncatesPartialRecord(t *testing.T) {
+	dir := tempDir(t)
+	q, _ := newTestPersistentQueue(t, dir, func(opts *PersistentQueueOptions) {
+		opts.SyncPolicy = SyncAlways
+	})
+	assert.True(t, q.Produce("a"))
+	assert.True(t, q.Produce("b"))
+	q.Stop()
+
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	path := segmentPath(dir, segments[0].id)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(encodeRecord([]byte("partial"))[:10])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	q, _ = newTestPersistentQueue(t, dir)
+	assert.Equal(t, 2, q.Size())
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2*(recordHeaderSize+1), info.Size())
+
+	var consumed collectedItems
+	q.StartConsumers(1, consumed.add)
+	assert.True(t, q.Produce("c"))
+	assert.Eventually(t, func() bool { return len(consumed.get()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, consumed.get())
+	q.Stop()
+}
+
+func TestPersistentQueueCodecErrors(t *testing.T) {
+	q, mf := newTestPersistentQueue(t, tempDir(t), func(opts *PersistentQueueOptions) {
+		opts.SyncPolicy = SyncNever
+		opts.Encode = func(item interface{}) ([]byte, error) {
+			if item == nil {
+				return nil, errors.New("cannot encode")
+			}
+			return []byte(item.(string)), nil
+		}
+		opts.Decode = func(data []byte) (interface{}, error) {
+			if string(data) == "bad" {
+				return nil, errors.New("cannot decode")
+			}
+			return string(data), nil
+		}
+	})
+	assert.False(t, q.Produce(nil))
+	assert.True(t, q.Produce("bad"))
+	assert.True(t, q.Produce("good"))
+
+	var consumed collectedItems
+	q.StartConsumers(1, consumed.add)
+	assert.Eventually(t, func() bool { return q.Size() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"good"}, consumed.get())
+	q.Stop()
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "corrupted_records", Value: 1})
+}
+
+func TestPersistentQueueInvalidOptions(t *testing.T) {
+	codec := func(opts *PersistentQueueOptions) {
+		opts.Encode = func(item interface{}) ([]byte, error) { return nil, nil }
+		opts.Decode = func(data []byte) (interface{}, error) { return nil, nil }
+	}
+	withCodec := PersistentQueueOptions{Directory: "dir", SyncPolicy: "sometimes"}
+	codec(&withCodec)
+	tests := []struct {
+		opts PersistentQueueOptions
+		err  string
+	}{
+		{PersistentQueueOptions{}, "the persistent queue requires a directory"},
+		{PersistentQueueOptions{Directory: "dir"}, "the persistent queue requires an encoder and a decoder"},
+		{withCodec, `unknown sync policy "sometimes"`},
+	}
+	for _, test := range tests {
+		_, err := NewPersistentQueue(test.opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), test.err)
+	}
+
+	dir := tempDir(t)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, checkpointFile), []byte("bad"), 0600))
+	opts := PersistentQueueOptions{Directory: dir}
+	codec(&opts)
+	_, err := NewPersistentQueue(opts)
+	assert.Contains(t, err.Error(), "failed to read the queue checkpoint")
+}
+
+func TestPersistentQueueStaleCheckpoint(t *testing.T) {
+	dir := tempDir(t)
+	require.NoError(t, writeCheckpoint(dir, position{segment: 7, offset: 100}))
+	require.NoError(t, ioutil.WriteFile(segmentPath(dir, 3), encodeRecord([]byte("consumed")), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other"+segmentFileSuffix), nil, 0600))
+
+	q, _ := newTestPersistentQueue(t, dir)
+	assert.Equal(t, 0, q.Size())
+	assert.True(t, q.Produce("a"))
+	q.Stop()
+
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	assert.EqualValues(t, 8, segments[0].id)
+}
+
+func TestPersistentQueueSkipsCorruptedSegment(t *testing.T) {
+	dir := tempDir(t)
+	q, mf := newTestPersistentQueue(t, dir)
+	assert.True(t, q.Produce("a"))
+	assert.True(t, q.Produce("b"))
+
+	// corrupt the checksum of the first record after it was written
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	f, err := os.OpenFile(segmentPath(dir, segments[0].id), os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, 4)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	var consumed collectedItems
+	q.StartConsumers(1, consumed.add)
+	assert.Eventually(t, func() bool { return q.Size() == 0 }, time.Second, time.Millisecond)
+	assert.True(t, q.Produce("c"))
+	assert.Eventually(t, func() bool { return len(consumed.get()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"c"}, consumed.get())
+	q.Stop()
+	mf.AssertCounterMetrics(t, metricstest.ExpectedMetric{Name: "corrupted_records", Value: 2})
+}
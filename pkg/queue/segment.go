@@ -0,0 +1,187 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	segmentFileSuffix = ".seg"
+	checkpointFile    = "checkpoint"
+
+	// recordHeaderSize is the size of the length and the CRC-32 checksum preceding the payload of a record.
+	recordHeaderSize = 8
+)
+
+var (
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errCorruptedRecord = errors.New("corrupted record")
+)
+
+// position locates a record boundary in the segment files.
+type position struct {
+	segment uint64
+	offset  int64
+}
+
+func (p position) before(other position) bool {
+	return p.segment < other.segment || (p.segment == other.segment && p.offset < other.offset)
+}
+
+// segment is a file of the queue, holding a sequence of records.
+type segment struct {
+	id   uint64
+	size int64
+	// records is the number of records of the segment which have not been consumed before the queue was opened.
+	records int
+}
+
+func segmentPath(dir string, id uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", id, segmentFileSuffix))
+}
+
+// listSegments returns the segment files of the directory ordered by id.
+func listSegments(dir string) ([]*segment, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []*segment
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, segmentFileSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, &segment{id: id, size: f.Size()})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].id < segments[j].id })
+	return segments, nil
+}
+
+// encodeRecord prepends the length and the checksum of the payload.
+func encodeRecord(data []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(data, crcTable))
+	copy(record[recordHeaderSize:], data)
+	return record
+}
+
+// readRecord reads the payload of the record at the offset and returns the size of the record.
+// It returns io.EOF at the end of the file, and errCorruptedRecord if the record is truncated or
+// its checksum does not match.
+func readRecord(f *os.File, offset int64, maxSize int64) ([]byte, int64, error) {
+	var header [recordHeaderSize]byte
+	n, err := f.ReadAt(header[:], offset)
+	if err == io.EOF && n == 0 {
+		return nil, 0, io.EOF
+	}
+	if n < recordHeaderSize {
+		return nil, 0, errCorruptedRecord
+	}
+	length := int64(binary.BigEndian.Uint32(header[:]))
+	if length > maxSize {
+		return nil, 0, errCorruptedRecord
+	}
+	data := make([]byte, length)
+	if n, _ := f.ReadAt(data, offset+recordHeaderSize); int64(n) < length {
+		return nil, 0, errCorruptedRecord
+	}
+	if crc32.Checksum(data, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, 0, errCorruptedRecord
+	}
+	return data, recordHeaderSize + length, nil
+}
+
+// scanSegment counts the valid records of the segment from the offset, and returns the offset
+// following the last valid record.
+func scanSegment(path string, offset int64, maxSize int64) (int, int64, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	records := 0
+	for {
+		_, n, err := readRecord(f, offset, maxSize)
+		if err == io.EOF || err == errCorruptedRecord {
+			return records, offset, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		records++
+		offset += n
+	}
+}
+
+// readCheckpoint returns the position up to which the records have been consumed,
+// or false if no checkpoint was written.
+func readCheckpoint(dir string) (position, bool, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, checkpointFile))
+	if os.IsNotExist(err) {
+		return position{}, false, nil
+	}
+	if err != nil {
+		return position{}, false, err
+	}
+	if len(b) != 16 {
+		return position{}, false, fmt.Errorf("invalid checkpoint file of %d bytes", len(b))
+	}
+	return position{
+		segment: binary.BigEndian.Uint64(b),
+		offset:  int64(binary.BigEndian.Uint64(b[8:])),
+	}, true, nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint with the position.
+func writeCheckpoint(dir string, pos position) error {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:], pos.segment)
+	binary.BigEndian.PutUint64(b[8:], uint64(pos.offset))
+	tmp := filepath.Join(dir, checkpointFile+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b[:]); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, checkpointFile))
+}
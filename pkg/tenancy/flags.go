@@ -0,0 +1,67 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	tenancyEnabled  = "multi-tenancy.enabled"
+	tenancyHeader   = "multi-tenancy.header"
+	tenancyTenants  = "multi-tenancy.tenants"
+	tenancyRequired = "multi-tenancy.required"
+
+	// DefaultHeader is the default HTTP header and gRPC metadata key carrying the tenant
+	DefaultHeader = "x-tenant"
+)
+
+// Options describes the configuration of multi-tenancy
+type Options struct {
+	// Enabled turns on the isolation of the data per tenant
+	Enabled bool
+	// Header is the HTTP header, or gRPC metadata key, carrying the tenant
+	Header string
+	// Tenants is the list of allowed tenants, any well-formed tenant is allowed if empty
+	Tenants []string
+	// Required rejects the requests without a tenant, instead of using the default tenant
+	Required bool
+}
+
+// AddFlags adds flags for multi-tenancy
+func AddFlags(flags *flag.FlagSet) {
+	flags.Bool(tenancyEnabled, false, "(experimental) Enables isolation of the traces per tenant, the tenant being read from the multi-tenancy.header of the requests. "+
+		"The storage must isolate the tenants: cassandra, and elasticsearch with aliases, are rejected")
+	flags.String(tenancyHeader, DefaultHeader, "The HTTP header, or gRPC metadata key, carrying the tenant")
+	flags.String(tenancyTenants, "", "Comma separated list of allowed tenants, any tenant made of lowercase letters, digits, '_' and '-' is allowed if empty")
+	flags.Bool(tenancyRequired, true, "Rejects the requests without a tenant, otherwise they use the default tenant")
+}
+
+// InitFromViper initializes Options with properties from viper
+func InitFromViper(v *viper.Viper) Options {
+	var opts Options
+	opts.Enabled = v.GetBool(tenancyEnabled)
+	opts.Header = v.GetString(tenancyHeader)
+	opts.Required = v.GetBool(tenancyRequired)
+	for _, tenant := range strings.Split(v.GetString(tenancyTenants), ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			opts.Tenants = append(opts.Tenants, tenant)
+		}
+	}
+	return opts
+}
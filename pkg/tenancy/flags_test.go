@@ -0,0 +1,47 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+)
+
+func TestFlags(t *testing.T) {
+	v, command := config.Viperize(AddFlags)
+	command.ParseFlags([]string{
+		"--multi-tenancy.enabled=true",
+		"--multi-tenancy.header=x-team",
+		"--multi-tenancy.tenants=acme, globex,",
+		"--multi-tenancy.required=false",
+	})
+	assert.Equal(t, Options{
+		Enabled:  true,
+		Header:   "x-team",
+		Tenants:  []string{"acme", "globex"},
+		Required: false,
+	}, InitFromViper(v))
+}
+
+func TestFlagsDefaults(t *testing.T) {
+	v, _ := config.Viperize(AddFlags)
+	assert.Equal(t, Options{
+		Header:   DefaultHeader,
+		Required: true,
+	}, InitFromViper(v))
+}
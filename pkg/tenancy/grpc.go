@@ -0,0 +1,90 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GetValidTenant validates the tenant carried by the incoming gRPC metadata and returns the tenant to use.
+// The returned error is a gRPC status error.
+func GetValidTenant(ctx context.Context, m *Manager) (string, error) {
+	if m == nil || !m.Enabled {
+		return "", nil
+	}
+	var values []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		values = md.Get(m.Header)
+	}
+	if len(values) > 1 {
+		return "", status.Error(codes.PermissionDenied, ErrInvalidTenant.Error())
+	}
+	var tenant string
+	if len(values) == 1 {
+		tenant = values[0]
+	}
+	tenant, err := m.Tenant(tenant)
+	if err != nil {
+		code := codes.PermissionDenied
+		if errors.Is(err, ErrMissingTenant) {
+			code = codes.Unauthenticated
+		}
+		return "", status.Error(code, err.Error())
+	}
+	return tenant, nil
+}
+
+// NewGuardingUnaryInterceptor returns a gRPC interceptor rejecting the unary calls without a valid tenant,
+// and passing the tenant to the handler in the context.
+func NewGuardingUnaryInterceptor(m *Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		tenant, err := GetValidTenant(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		return handler(WithTenant(ctx, tenant), req)
+	}
+}
+
+// NewGuardingStreamInterceptor returns a gRPC interceptor rejecting the streaming calls without a valid tenant,
+// and passing the tenant to the handler in the stream context.
+func NewGuardingStreamInterceptor(m *Manager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		tenant, err := GetValidTenant(ss.Context(), m)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tenantedServerStream{
+			ServerStream: ss,
+			ctx:          WithTenant(ss.Context(), tenant),
+		})
+	}
+}
+
+// tenantedServerStream overrides the context of a grpc.ServerStream
+type tenantedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tenantedServerStream) Context() context.Context {
+	return s.ctx
+}
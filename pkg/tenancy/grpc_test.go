@@ -0,0 +1,96 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestGetValidTenant(t *testing.T) {
+	m := NewManager(&Options{Enabled: true, Required: true, Tenants: []string{"acme"}})
+	tests := []struct {
+		name    string
+		md      metadata.MD
+		tenant  string
+		errCode codes.Code
+	}{
+		{name: "valid", md: metadata.Pairs("x-tenant", "acme"), tenant: "acme", errCode: codes.OK},
+		{name: "no metadata", errCode: codes.Unauthenticated},
+		{name: "missing", md: metadata.Pairs("other", "acme"), errCode: codes.Unauthenticated},
+		{name: "not allowed", md: metadata.Pairs("x-tenant", "globex"), errCode: codes.PermissionDenied},
+		{name: "several", md: metadata.Pairs("x-tenant", "acme", "x-tenant", "acme"), errCode: codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.md)
+			}
+			tenant, err := GetValidTenant(ctx, m)
+			assert.Equal(t, test.errCode, status.Code(err))
+			assert.Equal(t, test.tenant, tenant)
+		})
+	}
+
+	tenant, err := GetValidTenant(context.Background(), &Manager{})
+	require.NoError(t, err)
+	assert.Equal(t, "", tenant)
+}
+
+func TestGuardingInterceptors(t *testing.T) {
+	m := NewManager(&Options{Enabled: true, Required: true})
+	valid := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))
+
+	unary := NewGuardingUnaryInterceptor(m)
+	res, err := unary(valid, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return GetTenant(ctx), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", res)
+	_, err = unary(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream := NewGuardingStreamInterceptor(m)
+	var tenant string
+	err = stream(nil, &mockServerStream{ctx: valid}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		tenant = GetTenant(ss.Context())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+	err = stream(nil, &mockServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		return nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
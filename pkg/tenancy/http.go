@@ -0,0 +1,64 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"errors"
+	"net/http"
+)
+
+// TenantFromHTTP validates the tenant carried by the header of the HTTP request and returns the tenant to use
+func (m *Manager) TenantFromHTTP(r *http.Request) (string, error) {
+	if m == nil || !m.Enabled {
+		return "", nil
+	}
+	values := r.Header.Values(m.Header)
+	if len(values) > 1 {
+		return "", ErrInvalidTenant
+	}
+	var tenant string
+	if len(values) == 1 {
+		tenant = values[0]
+	}
+	return m.Tenant(tenant)
+}
+
+// ExtractTenantHTTPHandler returns a handler rejecting the requests without a valid tenant,
+// and passing the tenant to the given handler in the request context.
+func ExtractTenantHTTPHandler(m *Manager, h http.Handler) http.Handler {
+	if m == nil || !m.Enabled {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, err := m.TenantFromHTTP(r)
+		if err != nil {
+			http.Error(w, err.Error(), HTTPStatusCode(err))
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+	})
+}
+
+// HTTPStatusCode returns the HTTP status code matching a tenant validation error
+func HTTPStatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrMissingTenant):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidTenant):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
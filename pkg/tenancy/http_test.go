@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractTenantHTTPHandler(t *testing.T) {
+	m := NewManager(&Options{Enabled: true, Required: true, Tenants: []string{"acme"}})
+	var tenant string
+	handler := ExtractTenantHTTPHandler(m, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = GetTenant(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		tenants  []string
+		expected int
+	}{
+		{name: "valid", tenants: []string{"acme"}, expected: http.StatusOK},
+		{name: "missing", expected: http.StatusUnauthorized},
+		{name: "not allowed", tenants: []string{"globex"}, expected: http.StatusForbidden},
+		{name: "several", tenants: []string{"acme", "globex"}, expected: http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tenant = ""
+			r := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+			for _, v := range test.tenants {
+				r.Header.Add("X-Tenant", v)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, test.expected, w.Code)
+			if test.expected == http.StatusOK {
+				assert.Equal(t, "acme", tenant)
+			}
+		})
+	}
+}
+
+func TestExtractTenantHTTPHandlerDisabled(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/traces", nil)
+	w := httptest.NewRecorder()
+	ExtractTenantHTTPHandler(&Manager{}, http.NotFoundHandler()).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHTTPStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatusCode(errors.New("boom")))
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrMissingTenant is returned when a tenant is required but the request does not carry one
+	ErrMissingTenant = errors.New("missing tenant")
+	// ErrInvalidTenant is returned when the tenant of the request is malformed or not allowed
+	ErrInvalidTenant = errors.New("invalid tenant")
+
+	// the tenant ends up in index names and storage keys, so it is restricted to a safe alphabet
+	tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+)
+
+// Manager validates the tenants of the requests.
+// The zero value, as well as a nil Manager, has multi-tenancy disabled.
+type Manager struct {
+	// Enabled is true if the data is isolated per tenant
+	Enabled bool
+	// Header is the HTTP header, or gRPC metadata key, carrying the tenant
+	Header   string
+	required bool
+	allowed  map[string]struct{}
+}
+
+// NewManager creates a Manager from the given options
+func NewManager(options *Options) *Manager {
+	header := options.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+	m := &Manager{
+		Enabled:  options.Enabled,
+		Header:   strings.ToLower(header),
+		required: options.Required,
+	}
+	if len(options.Tenants) > 0 {
+		m.allowed = make(map[string]struct{}, len(options.Tenants))
+		for _, tenant := range options.Tenants {
+			m.allowed[tenant] = struct{}{}
+		}
+	}
+	return m
+}
+
+// Valid returns true if the given tenant may be used
+func (m *Manager) Valid(tenant string) bool {
+	_, err := m.Tenant(tenant)
+	return err == nil
+}
+
+// Tenant validates the tenant carried by a request and returns the tenant to use.
+// The empty string is the default tenant, used when multi-tenancy is disabled,
+// or when the request carries no tenant and a tenant is not required.
+func (m *Manager) Tenant(tenant string) (string, error) {
+	if m == nil || !m.Enabled {
+		return "", nil
+	}
+	if tenant == "" {
+		if m.required {
+			return "", ErrMissingTenant
+		}
+		return "", nil
+	}
+	if !tenantPattern.MatchString(tenant) {
+		return "", ErrInvalidTenant
+	}
+	if m.allowed != nil {
+		if _, ok := m.allowed[tenant]; !ok {
+			return "", ErrInvalidTenant
+		}
+	}
+	return tenant, nil
+}
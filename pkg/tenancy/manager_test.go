@@ -0,0 +1,62 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		tenant   string
+		expected string
+		err      error
+	}{
+		{name: "disabled", options: Options{}, tenant: "acme", expected: ""},
+		{name: "allowed", options: Options{Enabled: true, Tenants: []string{"acme"}}, tenant: "acme", expected: "acme"},
+		{name: "not allowed", options: Options{Enabled: true, Tenants: []string{"acme"}}, tenant: "globex", err: ErrInvalidTenant},
+		{name: "any tenant", options: Options{Enabled: true}, tenant: "globex", expected: "globex"},
+		{name: "malformed", options: Options{Enabled: true}, tenant: "Acme/1", err: ErrInvalidTenant},
+		{name: "required", options: Options{Enabled: true, Required: true}, tenant: "", err: ErrMissingTenant},
+		{name: "optional", options: Options{Enabled: true}, tenant: "", expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(&test.options)
+			tenant, err := m.Tenant(test.tenant)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.expected, tenant)
+			assert.Equal(t, test.err == nil, m.Valid(test.tenant))
+		})
+	}
+}
+
+func TestNewManagerHeader(t *testing.T) {
+	assert.Equal(t, DefaultHeader, NewManager(&Options{}).Header)
+	assert.Equal(t, "x-team", NewManager(&Options{Header: "X-Team"}).Header)
+	assert.False(t, (&Manager{}).Enabled)
+	var m *Manager
+	assert.True(t, m.Valid("acme"))
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, "", GetTenant(context.Background()))
+	assert.Equal(t, "acme", GetTenant(WithTenant(context.Background(), "acme")))
+}
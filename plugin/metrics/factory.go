@@ -23,7 +23,9 @@ import (
 
 	"github.com/jaegertracing/jaeger/plugin"
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
+	"github.com/jaegertracing/jaeger/plugin/metrics/grpcplugin"
 	"github.com/jaegertracing/jaeger/plugin/metrics/prometheus"
+	"github.com/jaegertracing/jaeger/plugin/metrics/spanmetrics"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/metricsstore"
 )
@@ -32,11 +34,24 @@ const (
 	// disabledStorageType is the storage type used when METRICS_STORAGE_TYPE is unset.
 	disabledStorageType = ""
 
-	prometheusStorageType = "prometheus"
+	prometheusStorageType  = "prometheus"
+	spanMetricsStorageType = "spanmetrics"
+	grpcPluginStorageType  = "grpc-plugin"
 )
 
 // AllStorageTypes defines all available storage backends.
-var AllStorageTypes = []string{prometheusStorageType}
+var AllStorageTypes = []string{prometheusStorageType, spanMetricsStorageType, grpcPluginStorageType}
+
+// spanMetricsConsumer is implemented by the factories of metrics stores backed by the span metrics
+// aggregated by the collector.
+type spanMetricsConsumer interface {
+	SetSpanMetricsReader(reader metricsstore.SpanMetricsReader)
+}
+
+// storageMetricsConsumer is implemented by the factories of metrics stores served by the span storage.
+type storageMetricsConsumer interface {
+	SetMetricsFactory(factory storage.MetricsFactory)
+}
 
 // Factory implements storage.Factory interface as a meta-factory for storage components.
 type Factory struct {
@@ -65,6 +80,10 @@ func (f *Factory) getFactoryOfType(factoryType string) (storage.MetricsFactory,
 	switch factoryType {
 	case prometheusStorageType:
 		return prometheus.NewFactory(), nil
+	case spanMetricsStorageType:
+		return spanmetrics.NewFactory(), nil
+	case grpcPluginStorageType:
+		return grpcplugin.NewFactory(), nil
 	case disabledStorageType:
 		return disabled.NewFactory(), nil
 	}
@@ -79,6 +98,32 @@ func (f *Factory) Initialize(logger *zap.Logger) error {
 	return nil
 }
 
+// SetSpanMetricsReader sets the source of the span metrics aggregated by the collector
+// for the metrics stores backed by them.
+func (f *Factory) SetSpanMetricsReader(reader metricsstore.SpanMetricsReader) {
+	for _, factory := range f.factories {
+		if consumer, ok := factory.(spanMetricsConsumer); ok {
+			consumer.SetSpanMetricsReader(reader)
+		}
+	}
+}
+
+// UsesSpanMetrics returns true if the metrics store is backed by the span metrics aggregated by the collector.
+func (f *Factory) UsesSpanMetrics() bool {
+	_, ok := f.factories[f.MetricsStorageType].(spanMetricsConsumer)
+	return ok
+}
+
+// SetStorageMetricsFactory sets the metrics factory of the span storage
+// for the metrics stores served by it.
+func (f *Factory) SetStorageMetricsFactory(factory storage.MetricsFactory) {
+	for _, ff := range f.factories {
+		if consumer, ok := ff.(storageMetricsConsumer); ok {
+			consumer.SetMetricsFactory(factory)
+		}
+	}
+}
+
 // CreateMetricsReader implements storage.MetricsFactory.
 func (f *Factory) CreateMetricsReader() (metricsstore.Reader, error) {
 	factory, ok := f.factories[f.MetricsStorageType]
@@ -20,11 +20,14 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/plugin/metrics/disabled"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
+	metricsStoreMocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
 	"github.com/jaegertracing/jaeger/storage/mocks"
 )
 
@@ -48,7 +51,7 @@ func TestUnsupportedMetricsStorageType(t *testing.T) {
 	f, err := NewFactory(withConfig("foo"))
 	require.Error(t, err)
 	assert.Nil(t, f)
-	assert.EqualError(t, err, `unknown metrics type "foo". Valid types are [prometheus]`)
+	assert.EqualError(t, err, `unknown metrics type "foo". Valid types are [prometheus spanmetrics grpc-plugin]`)
 }
 
 func TestDisabledMetricsStorageType(t *testing.T) {
@@ -78,6 +81,39 @@ func TestCreateMetricsReader(t *testing.T) {
 	assert.EqualError(t, err, `no "foo" backend registered for metrics store`)
 }
 
+func TestSpanMetricsStorageType(t *testing.T) {
+	f, err := NewFactory(withConfig(spanMetricsStorageType))
+	require.NoError(t, err)
+	require.NoError(t, f.Initialize(zap.NewNop()))
+
+	_, err = f.CreateMetricsReader()
+	require.Error(t, err)
+
+	assert.True(t, f.UsesSpanMetrics())
+	f.SetSpanMetricsReader(memory.NewSpanMetricsStore())
+	reader, err := f.CreateMetricsReader()
+	require.NoError(t, err)
+	assert.NotNil(t, reader)
+}
+
+func TestGRPCPluginStorageType(t *testing.T) {
+	f, err := NewFactory(withConfig(grpcPluginStorageType))
+	require.NoError(t, err)
+	require.NoError(t, f.Initialize(zap.NewNop()))
+	assert.False(t, f.UsesSpanMetrics())
+
+	_, err = f.CreateMetricsReader()
+	require.Error(t, err)
+
+	pluginFactory := new(mocks.MetricsFactory)
+	pluginFactory.On("Initialize", mock.Anything).Return(nil)
+	pluginFactory.On("CreateMetricsReader").Return(new(metricsStoreMocks.Reader), nil)
+	f.SetStorageMetricsFactory(pluginFactory)
+	reader, err := f.CreateMetricsReader()
+	require.NoError(t, err)
+	assert.NotNil(t, reader)
+}
+
 type configurable struct {
 	mocks.MetricsFactory
 	flagSet *flag.FlagSet
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var errNoStoragePlugin = errors.New("no storage plugin serving the metrics: use SPAN_STORAGE_TYPE=grpc-plugin " +
+	"with a plugin that supports reading metrics")
+
+// Factory implements storage.MetricsFactory and reads the metrics from the gRPC storage plugin used for the spans.
+type Factory struct {
+	logger        *zap.Logger
+	pluginFactory storage.MetricsFactory
+}
+
+// NewFactory creates a new Factory.
+func NewFactory() *Factory {
+	return &Factory{}
+}
+
+// SetMetricsFactory sets the metrics factory of the storage plugin.
+func (f *Factory) SetMetricsFactory(factory storage.MetricsFactory) {
+	f.pluginFactory = factory
+}
+
+// Initialize implements storage.MetricsFactory.
+func (f *Factory) Initialize(logger *zap.Logger) error {
+	f.logger = logger
+	return nil
+}
+
+// CreateMetricsReader implements storage.MetricsFactory.
+func (f *Factory) CreateMetricsReader() (metricsstore.Reader, error) {
+	if f.pluginFactory == nil {
+		return nil, errNoStoragePlugin
+	}
+	if err := f.pluginFactory.Initialize(f.logger); err != nil {
+		return nil, err
+	}
+	return f.pluginFactory.CreateMetricsReader()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcplugin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	metricsStoreMocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+)
+
+var _ storage.MetricsFactory = new(Factory)
+
+type mockMetricsFactory struct {
+	reader  metricsstore.Reader
+	initErr error
+	logger  *zap.Logger
+}
+
+func (m *mockMetricsFactory) Initialize(logger *zap.Logger) error {
+	m.logger = logger
+	return m.initErr
+}
+
+func (m *mockMetricsFactory) CreateMetricsReader() (metricsstore.Reader, error) {
+	return m.reader, nil
+}
+
+func TestGRPCPluginFactory(t *testing.T) {
+	f := NewFactory()
+	logger := zap.NewNop()
+	assert.NoError(t, f.Initialize(logger))
+
+	_, err := f.CreateMetricsReader()
+	assert.Equal(t, errNoStoragePlugin, err)
+
+	pluginFactory := &mockMetricsFactory{reader: new(metricsStoreMocks.Reader)}
+	f.SetMetricsFactory(pluginFactory)
+	reader, err := f.CreateMetricsReader()
+	require.NoError(t, err)
+	assert.Equal(t, pluginFactory.reader, reader)
+	assert.Equal(t, logger, pluginFactory.logger)
+
+	pluginFactory.initErr = errors.New("init error")
+	_, err = f.CreateMetricsReader()
+	assert.EqualError(t, err, "init error")
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	spanmetricsstore "github.com/jaegertracing/jaeger/plugin/metrics/spanmetrics/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var errNoSpanMetricsReader = errors.New("no source of span metrics: enable --collector.span-metrics.enabled in the same process, " +
+	"or use a span storage backend that persists the span metrics")
+
+// Factory implements storage.MetricsFactory and creates a metrics reader backed by the span metrics
+// aggregated by the collector, either in the same process or persisted in the span storage.
+type Factory struct {
+	options           *Options
+	logger            *zap.Logger
+	spanMetricsReader metricsstore.SpanMetricsReader
+}
+
+// NewFactory creates a new Factory.
+func NewFactory() *Factory {
+	return &Factory{
+		options: NewOptions("spanmetrics"),
+	}
+}
+
+// AddFlags implements plugin.Configurable.
+func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
+	f.options.AddFlags(flagSet)
+}
+
+// InitFromViper implements plugin.Configurable.
+func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
+	f.options.InitFromViper(v)
+}
+
+// SetSpanMetricsReader sets the source of the span metrics.
+func (f *Factory) SetSpanMetricsReader(reader metricsstore.SpanMetricsReader) {
+	f.spanMetricsReader = reader
+}
+
+// Initialize implements storage.MetricsFactory.
+func (f *Factory) Initialize(logger *zap.Logger) error {
+	f.logger = logger
+	return nil
+}
+
+// CreateMetricsReader implements storage.MetricsFactory.
+func (f *Factory) CreateMetricsReader() (metricsstore.Reader, error) {
+	if f.spanMetricsReader == nil {
+		return nil, errNoSpanMetricsReader
+	}
+	return spanmetricsstore.NewMetricsReader(f.logger, f.spanMetricsReader, f.options.BucketInterval), nil
+}
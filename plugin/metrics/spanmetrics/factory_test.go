@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/storage"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var _ storage.MetricsFactory = new(Factory)
+
+func TestSpanMetricsFactory(t *testing.T) {
+	f := NewFactory()
+	assert.NoError(t, f.Initialize(zap.NewNop()))
+	assert.NotNil(t, f.logger)
+
+	_, err := f.CreateMetricsReader()
+	assert.Equal(t, errNoSpanMetricsReader, err)
+
+	f.SetSpanMetricsReader(memory.NewSpanMetricsStore())
+	reader, err := f.CreateMetricsReader()
+	require.NoError(t, err)
+	minStep, err := reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultBucketInterval, minStep)
+}
+
+func TestWithConfiguration(t *testing.T) {
+	f := NewFactory()
+	v, command := config.Viperize(f.AddFlags)
+	err := command.ParseFlags([]string{
+		"--spanmetrics.bucket-interval=1m",
+	})
+	require.NoError(t, err)
+
+	f.InitFromViper(v, zap.NewNop())
+	assert.Equal(t, time.Minute, f.options.BucketInterval)
+}
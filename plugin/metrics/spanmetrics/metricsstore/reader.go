@@ -0,0 +1,306 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+const (
+	latenciesMetricName = "service_latencies"
+	latenciesMetricDesc = "%.2fth quantile latency, grouped by service"
+	callsMetricName     = "service_call_rate"
+	callsMetricDesc     = "calls/sec, grouped by service"
+	errorsMetricName    = "service_error_rate"
+	errorsMetricDesc    = "error rate, computed as a fraction of errors/sec over calls/sec, grouped by service"
+
+	serviceNameLabel = "service_name"
+	operationLabel   = "operation"
+)
+
+var errInvalidStep = errors.New("step must be positive")
+
+type (
+	// MetricsReader serves the metrics query API from the span metrics aggregated by the collector.
+	MetricsReader struct {
+		source         metricsstore.SpanMetricsReader
+		bucketInterval time.Duration
+		logger         *zap.Logger
+	}
+
+	metricsQueryParams struct {
+		metricsstore.BaseQueryParameters
+		metricName string
+		metricDesc string
+		// computeValue returns the value of a data point from the span metrics within the rate window,
+		// or false if there is no value for that data point.
+		computeValue func(w *window) (float64, bool)
+	}
+
+	// window holds the sum of the span metrics of a series within the rate window of a data point.
+	window struct {
+		ratePer       time.Duration
+		calls         int64
+		errors        int64
+		latencyBounds []time.Duration
+		latencyCounts []int64
+	}
+
+	series struct {
+		labels  []*metrics.Label
+		records []*metricsstore.SpanMetrics
+	}
+)
+
+// NewMetricsReader returns a new MetricsReader. The bucketInterval is the time resolution of the
+// span metrics aggregated by the collector and the minimum step of the metrics queries.
+func NewMetricsReader(logger *zap.Logger, source metricsstore.SpanMetricsReader, bucketInterval time.Duration) *MetricsReader {
+	return &MetricsReader{
+		source:         source,
+		bucketInterval: bucketInterval,
+		logger:         logger,
+	}
+}
+
+// GetLatencies gets the latency metrics for the given set of latency query parameters.
+// The quantile is estimated from the latency histogram, by linear interpolation within the bucket it falls in.
+func (m *MetricsReader) GetLatencies(ctx context.Context, requestParams *metricsstore.LatenciesQueryParameters) (*metrics.MetricFamily, error) {
+	return m.executeQuery(ctx, metricsQueryParams{
+		BaseQueryParameters: requestParams.BaseQueryParameters,
+		metricName:          latenciesMetricName,
+		metricDesc:          fmt.Sprintf(latenciesMetricDesc, requestParams.Quantile),
+		computeValue: func(w *window) (float64, bool) {
+			return quantile(requestParams.Quantile, w.latencyBounds, w.latencyCounts)
+		},
+	})
+}
+
+// GetCallRates gets the call rate metrics for the given set of call rate query parameters.
+func (m *MetricsReader) GetCallRates(ctx context.Context, requestParams *metricsstore.CallRateQueryParameters) (*metrics.MetricFamily, error) {
+	return m.executeQuery(ctx, metricsQueryParams{
+		BaseQueryParameters: requestParams.BaseQueryParameters,
+		metricName:          callsMetricName,
+		metricDesc:          callsMetricDesc,
+		computeValue: func(w *window) (float64, bool) {
+			return float64(w.calls) / w.ratePer.Seconds(), true
+		},
+	})
+}
+
+// GetErrorRates gets the error rate metrics for the given set of error rate query parameters.
+func (m *MetricsReader) GetErrorRates(ctx context.Context, requestParams *metricsstore.ErrorRateQueryParameters) (*metrics.MetricFamily, error) {
+	return m.executeQuery(ctx, metricsQueryParams{
+		BaseQueryParameters: requestParams.BaseQueryParameters,
+		metricName:          errorsMetricName,
+		metricDesc:          errorsMetricDesc,
+		computeValue: func(w *window) (float64, bool) {
+			if w.calls == 0 {
+				return 0, false
+			}
+			return float64(w.errors) / float64(w.calls), true
+		},
+	})
+}
+
+// GetMinStepDuration gets the minimum step duration (the smallest possible duration between two data points in a time series) supported,
+// which is the time resolution of the aggregated span metrics.
+func (m *MetricsReader) GetMinStepDuration(_ context.Context, _ *metricsstore.MinStepDurationQueryParameters) (time.Duration, error) {
+	return m.bucketInterval, nil
+}
+
+// executeQuery loads the span metrics within the query range and computes a data point at every step,
+// from the span metrics of the time buckets starting within the rate window preceding it.
+func (m *MetricsReader) executeQuery(ctx context.Context, p metricsQueryParams) (*metrics.MetricFamily, error) {
+	if p.GroupByOperation {
+		p.metricName = strings.Replace(p.metricName, "service", "service_operation", 1)
+		p.metricDesc += " & operation"
+	}
+	if *p.Step <= 0 {
+		return &metrics.MetricFamily{}, errInvalidStep
+	}
+	endTime := *p.EndTime
+	startTime := endTime.Add(-*p.Lookback)
+	records, err := m.source.GetSpanMetrics(ctx, &metricsstore.SpanMetricsQuery{
+		StartTime:    startTime.Add(-*p.RatePer),
+		EndTime:      endTime,
+		ServiceNames: p.ServiceNames,
+		SpanKinds:    p.SpanKinds,
+	})
+	if err != nil {
+		return &metrics.MetricFamily{}, fmt.Errorf("failed loading span metrics: %w", err)
+	}
+	m.logger.Debug("Span metrics loaded", zap.String("metric", p.metricName), zap.Int("records", len(records)))
+
+	var ms []*metrics.Metric
+	for _, s := range groupBySeries(records, p.BaseQueryParameters) {
+		var points []*metrics.MetricPoint
+		for ts := startTime; !ts.After(endTime); ts = ts.Add(*p.Step) {
+			w := newWindow(s.records, ts.Add(-*p.RatePer), ts, *p.RatePer)
+			if w == nil {
+				continue
+			}
+			value, ok := p.computeValue(w)
+			if !ok {
+				continue
+			}
+			points = append(points, toMetricPoint(ts, value))
+		}
+		if len(points) > 0 {
+			ms = append(ms, &metrics.Metric{Labels: s.labels, MetricPoints: points})
+		}
+	}
+	return &metrics.MetricFamily{
+		Name:    p.metricName,
+		Type:    metrics.MetricType_GAUGE,
+		Help:    p.metricDesc,
+		Metrics: ms,
+	}, nil
+}
+
+// groupBySeries filters the span metrics by service and span kind, and groups them
+// by service and optionally by operation, in a deterministic order.
+func groupBySeries(records []*metricsstore.SpanMetrics, p metricsstore.BaseQueryParameters) []*series {
+	services := make(map[string]bool, len(p.ServiceNames))
+	for _, s := range p.ServiceNames {
+		services[s] = true
+	}
+	spanKinds := make(map[string]bool, len(p.SpanKinds))
+	for _, k := range p.SpanKinds {
+		spanKinds[k] = true
+	}
+	bySeries := make(map[string]*series)
+	for _, r := range records {
+		if !services[r.ServiceName] || (len(spanKinds) > 0 && !spanKinds[r.SpanKind]) {
+			continue
+		}
+		key := r.ServiceName
+		labels := []*metrics.Label{{Name: serviceNameLabel, Value: r.ServiceName}}
+		if p.GroupByOperation {
+			key += "\x00" + r.OperationName
+			labels = append(labels, &metrics.Label{Name: operationLabel, Value: r.OperationName})
+		}
+		s, ok := bySeries[key]
+		if !ok {
+			s = &series{labels: labels}
+			bySeries[key] = s
+		}
+		s.records = append(s.records, r)
+	}
+	keys := make([]string, 0, len(bySeries))
+	for k := range bySeries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]*series, len(keys))
+	for i, k := range keys {
+		result[i] = bySeries[k]
+	}
+	return result
+}
+
+// newWindow sums the span metrics of the time buckets starting within [startTime, endTime).
+// It returns nil if there are none. Latency histograms with bounds different from the first one are ignored.
+func newWindow(records []*metricsstore.SpanMetrics, startTime, endTime time.Time, ratePer time.Duration) *window {
+	var w *window
+	for _, r := range records {
+		if r.Timestamp.Before(startTime) || !r.Timestamp.Before(endTime) {
+			continue
+		}
+		if w == nil {
+			w = &window{
+				ratePer:       ratePer,
+				latencyBounds: r.LatencyBounds,
+				latencyCounts: make([]int64, len(r.LatencyCounts)),
+			}
+		}
+		w.calls += r.Calls
+		w.errors += r.Errors
+		if sameBounds(w.latencyBounds, r.LatencyBounds) && len(r.LatencyCounts) == len(w.latencyCounts) {
+			for i, c := range r.LatencyCounts {
+				w.latencyCounts[i] += c
+			}
+		}
+	}
+	return w
+}
+
+func sameBounds(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// quantile estimates the quantile in milliseconds from a latency histogram, the same way as Prometheus'
+// histogram_quantile: by linear interpolation within the bucket the quantile falls in, and returning
+// the highest bound when it falls in the bucket above all bounds.
+func quantile(q float64, bounds []time.Duration, counts []int64) (float64, bool) {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 || len(bounds) == 0 {
+		return 0, false
+	}
+	rank := q * float64(total)
+	var cumulative int64
+	for i, c := range counts {
+		if c > 0 && float64(cumulative+c) >= rank {
+			if i == len(bounds) {
+				break
+			}
+			lower := 0.0
+			if i > 0 {
+				lower = toMillis(bounds[i-1])
+			}
+			upper := toMillis(bounds[i])
+			return lower + (upper-lower)*(rank-float64(cumulative))/float64(c), true
+		}
+		cumulative += c
+	}
+	return toMillis(bounds[len(bounds)-1]), true
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func toMetricPoint(ts time.Time, value float64) *metrics.MetricPoint {
+	timestamp, _ := types.TimestampProto(ts)
+	return &metrics.MetricPoint{
+		Timestamp: timestamp,
+		Value: &metrics.MetricPoint_GaugeValue{
+			GaugeValue: &metrics.GaugeValue{
+				Value: &metrics.GaugeValue_DoubleValue{DoubleValue: value},
+			},
+		},
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var (
+	testBounds  = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	testEndTime = time.Unix(1200, 0)
+)
+
+type failingSource struct{}
+
+func (failingSource) GetSpanMetrics(context.Context, *metricsstore.SpanMetricsQuery) ([]*metricsstore.SpanMetrics, error) {
+	return nil, errors.New("storage error")
+}
+
+func spanMetrics(service, operation, kind string, timestamp time.Time, calls, errors int64, counts ...int64) *metricsstore.SpanMetrics {
+	return &metricsstore.SpanMetrics{
+		ServiceName:   service,
+		OperationName: operation,
+		SpanKind:      kind,
+		Timestamp:     timestamp,
+		Calls:         calls,
+		Errors:        errors,
+		LatencyBounds: testBounds,
+		LatencyCounts: counts,
+	}
+}
+
+// newTestReader returns a reader over two one-minute buckets of the service "frontend" ending at testEndTime,
+// and one bucket of the service "backend".
+func newTestReader(t *testing.T) *MetricsReader {
+	store := memory.NewSpanMetricsStore()
+	bucket1 := testEndTime.Add(-2 * time.Minute)
+	bucket2 := testEndTime.Add(-time.Minute)
+	require.NoError(t, store.WriteSpanMetrics(context.Background(), []*metricsstore.SpanMetrics{
+		spanMetrics("frontend", "GET /", "SPAN_KIND_SERVER", bucket1, 60, 6, 60, 0, 0),
+		spanMetrics("frontend", "GET /", "SPAN_KIND_SERVER", bucket2, 120, 0, 60, 60, 0),
+		spanMetrics("frontend", "POST /", "SPAN_KIND_SERVER", bucket2, 60, 30, 0, 0, 60),
+		spanMetrics("frontend", "GET /backend", "SPAN_KIND_CLIENT", bucket2, 60, 0, 60, 0, 0),
+		spanMetrics("backend", "query", "SPAN_KIND_SERVER", bucket2, 60, 0, 60, 0, 0),
+	}))
+	return NewMetricsReader(zap.NewNop(), store, time.Minute)
+}
+
+func baseQueryParams(groupByOperation bool, spanKinds ...string) metricsstore.BaseQueryParameters {
+	lookback := time.Minute
+	step := time.Minute
+	ratePer := time.Minute
+	return metricsstore.BaseQueryParameters{
+		ServiceNames:     []string{"frontend"},
+		GroupByOperation: groupByOperation,
+		EndTime:          &testEndTime,
+		Lookback:         &lookback,
+		Step:             &step,
+		RatePer:          &ratePer,
+		SpanKinds:        spanKinds,
+	}
+}
+
+// values returns the data points of each metric, keyed by the value of the last label.
+func values(t *testing.T, mf *metrics.MetricFamily) map[string][]float64 {
+	result := make(map[string][]float64)
+	for _, m := range mf.Metrics {
+		label := m.Labels[len(m.Labels)-1].Value
+		for _, mp := range m.MetricPoints {
+			result[label] = append(result[label], mp.GetGaugeValue().GetDoubleValue())
+		}
+	}
+	return result
+}
+
+func TestGetCallRates(t *testing.T) {
+	reader := newTestReader(t)
+
+	mf, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQueryParams(false, "SPAN_KIND_SERVER"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_call_rate", mf.Name)
+	assert.Equal(t, metrics.MetricType_GAUGE, mf.Type)
+	assert.Equal(t, map[string][]float64{"frontend": {1, 3}}, values(t, mf))
+	require.Len(t, mf.Metrics, 1)
+	assert.Equal(t, []*metrics.Label{{Name: "service_name", Value: "frontend"}}, mf.Metrics[0].Labels)
+	assert.Equal(t, testEndTime.Unix(), mf.Metrics[0].MetricPoints[1].Timestamp.Seconds)
+
+	mf, err = reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQueryParams(true),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_operation_call_rate", mf.Name)
+	assert.Equal(t, map[string][]float64{"GET /": {1, 2}, "GET /backend": {1}, "POST /": {1}}, values(t, mf))
+}
+
+func TestGetErrorRates(t *testing.T) {
+	reader := newTestReader(t)
+
+	mf, err := reader.GetErrorRates(context.Background(), &metricsstore.ErrorRateQueryParameters{
+		BaseQueryParameters: baseQueryParams(true, "SPAN_KIND_SERVER"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_operation_error_rate", mf.Name)
+	assert.Equal(t, map[string][]float64{"GET /": {0.1, 0}, "POST /": {0.5}}, values(t, mf))
+}
+
+func TestGetLatencies(t *testing.T) {
+	reader := newTestReader(t)
+
+	mf, err := reader.GetLatencies(context.Background(), &metricsstore.LatenciesQueryParameters{
+		BaseQueryParameters: baseQueryParams(true, "SPAN_KIND_SERVER"),
+		Quantile:            0.5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "service_operation_latencies", mf.Name)
+	assert.Equal(t, "0.50th quantile latency, grouped by service & operation", mf.Help)
+	assert.Equal(t, map[string][]float64{"GET /": {5, 10}, "POST /": {100}}, values(t, mf))
+}
+
+func TestGetMinStepDuration(t *testing.T) {
+	reader := newTestReader(t)
+	minStep, err := reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, minStep)
+}
+
+func TestExecuteQueryErrors(t *testing.T) {
+	reader := NewMetricsReader(zap.NewNop(), failingSource{}, time.Minute)
+	_, err := reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQueryParams(false),
+	})
+	assert.EqualError(t, err, "failed loading span metrics: storage error")
+
+	params := baseQueryParams(false)
+	step := time.Duration(0)
+	params.Step = &step
+	_, err = reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{BaseQueryParameters: params})
+	assert.Equal(t, errInvalidStep, err)
+}
+
+func TestQuantile(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        float64
+		counts   []int64
+		expected float64
+		ok       bool
+	}{
+		{name: "empty", q: 0.5, counts: []int64{0, 0, 0}},
+		{name: "first bucket", q: 0.5, counts: []int64{10, 0, 0}, expected: 5, ok: true},
+		{name: "second bucket", q: 0.75, counts: []int64{10, 10, 0}, expected: 55, ok: true},
+		{name: "above all bounds", q: 0.99, counts: []int64{1, 0, 9}, expected: 100, ok: true},
+		{name: "zero quantile", q: 0, counts: []int64{0, 5, 0}, expected: 10, ok: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, ok := quantile(test.q, testBounds, test.counts)
+			assert.Equal(t, test.ok, ok)
+			assert.InDelta(t, test.expected, value, 1e-9)
+		})
+	}
+}
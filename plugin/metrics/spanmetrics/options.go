@@ -0,0 +1,54 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetrics
+
+import (
+	"flag"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	suffixBucketInterval = ".bucket-interval"
+
+	defaultBucketInterval = 15 * time.Second
+)
+
+// Options stores the configuration entries for this metrics store.
+type Options struct {
+	namespace string
+	// BucketInterval is the time resolution of the span metrics aggregated by the collector.
+	BucketInterval time.Duration
+}
+
+// NewOptions creates a new Options struct.
+func NewOptions(namespace string) *Options {
+	return &Options{
+		namespace:      namespace,
+		BucketInterval: defaultBucketInterval,
+	}
+}
+
+// AddFlags from this metrics store to the CLI.
+func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
+	flagSet.Duration(opt.namespace+suffixBucketInterval, defaultBucketInterval, "The time resolution of the span metrics aggregated by the collector, "+
+		"it must match --collector.span-metrics.bucket-interval and is the minimum step of the metrics queries")
+}
+
+// InitFromViper initializes the options struct with values from Viper.
+func (opt *Options) InitFromViper(v *viper.Viper) {
+	opt.BucketInterval = v.GetDuration(opt.namespace + suffixBucketInterval)
+}
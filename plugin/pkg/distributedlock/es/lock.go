@@ -0,0 +1,147 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+)
+
+// Lock is a distributed lock based off Elasticsearch. Each lease is a document of the locks index
+// which is only modified if it has not changed since it was read, so it requires Elasticsearch 6.7
+// or later, or OpenSearch. The leases expire according to the clocks of the hosts, which must be in sync.
+type Lock struct {
+	client   es.Client
+	index    string
+	tenantID string
+	now      func() time.Time
+}
+
+const (
+	defaultTTL = 60 * time.Second
+
+	locksIndex = "jaeger-locks"
+	lockType   = "_doc"
+	opCreate   = "create"
+)
+
+var (
+	errLockOwnership = errors.New("this host does not own the resource lock")
+)
+
+type lease struct {
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewLock creates a new instance of a distributed locking mechanism based off Elasticsearch.
+func NewLock(client es.Client, indexPrefix, tenantID string) *Lock {
+	if indexPrefix != "" && !strings.HasSuffix(indexPrefix, "-") {
+		indexPrefix += "-"
+	}
+	return &Lock{
+		client:   client,
+		index:    indexPrefix + locksIndex,
+		tenantID: tenantID,
+		now:      time.Now,
+	}
+}
+
+// Acquire acquires a lease around a given resource, or extends it if this host already holds it.
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	now := l.now()
+	newLease := lease{Owner: l.tenantID, Expires: now.Add(ttl)}
+	current, result, err := l.get(resource)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire resource lock due to elasticsearch error: %w", err)
+	}
+	if result == nil {
+		// There is no lease yet, only one of the hosts succeeds in creating it
+		_, err = l.client.IndexDocument().Index(l.index).Type(lockType).Id(resource).
+			OpType(opCreate).BodyJson(&newLease).Do(context.Background())
+		return applied(err, "failed to acquire resource lock due to elasticsearch error")
+	}
+	if current.Owner != l.tenantID && now.Before(current.Expires) {
+		return false, nil
+	}
+	// This host already owns the lock or the lease of the other host has expired
+	_, err = l.client.IndexDocument().Index(l.index).Type(lockType).Id(resource).
+		IfSeqNo(*result.SeqNo).IfPrimaryTerm(*result.PrimaryTerm).BodyJson(&newLease).Do(context.Background())
+	return applied(err, "failed to extend lease on resource lock")
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	current, result, err := l.get(resource)
+	if err != nil {
+		return false, fmt.Errorf("failed to forfeit resource lock due to elasticsearch error: %w", err)
+	}
+	if result == nil || current.Owner != l.tenantID || !l.now().Before(current.Expires) {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	_, err = l.client.Delete().Index(l.index).Type(lockType).Id(resource).
+		IfSeqNo(*result.SeqNo).IfPrimaryTerm(*result.PrimaryTerm).Do(context.Background())
+	if elastic.IsConflict(err) || elastic.IsNotFound(err) {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to forfeit resource lock due to elasticsearch error: %w", err)
+	}
+	return true, nil
+}
+
+// get returns the current lease of the resource, and a nil result if there is none.
+func (l *Lock) get(resource string) (lease, *elastic.GetResult, error) {
+	var current lease
+	result, err := l.client.Get().Index(l.index).Type(lockType).Id(resource).Do(context.Background())
+	if elastic.IsNotFound(err) {
+		return current, nil, nil
+	}
+	if err != nil {
+		return current, nil, err
+	}
+	if !result.Found || result.Source == nil {
+		return current, nil, nil
+	}
+	if result.SeqNo == nil || result.PrimaryTerm == nil {
+		return current, nil, errors.New("elasticsearch did not return the sequence number of the lock, version 6.7 or later is required")
+	}
+	if err := json.Unmarshal(*result.Source, &current); err != nil {
+		return current, nil, err
+	}
+	return current, result, nil
+}
+
+// applied returns whether a conditional write succeeded, a conflict means that another host modified the lease.
+func applied(err error, msg string) (bool, error) {
+	if elastic.IsConflict(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", msg, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package es
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+)
+
+var _ distributedlock.Lock = &Lock{} // check API conformance
+
+const (
+	localhost    = "localhost"
+	samplingLock = "sampling_lock"
+)
+
+var (
+	now         = time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	errConflict = &elastic.Error{Status: http.StatusConflict}
+	errNotFound = &elastic.Error{Status: http.StatusNotFound}
+)
+
+type esLockTest struct {
+	client *mocks.Client
+	lock   *Lock
+}
+
+func withESLock(fn func(r *esLockTest)) {
+	client := &mocks.Client{}
+	r := &esLockTest{
+		client: client,
+		lock:   NewLock(client, "foo", localhost),
+	}
+	r.lock.now = func() time.Time { return now }
+	fn(r)
+}
+
+func getResult(owner string, expires time.Time) *elastic.GetResult {
+	source, _ := json.Marshal(lease{Owner: owner, Expires: expires})
+	raw := json.RawMessage(source)
+	seqNo, primaryTerm := int64(3), int64(1)
+	return &elastic.GetResult{Found: true, Source: &raw, SeqNo: &seqNo, PrimaryTerm: &primaryTerm}
+}
+
+func (r *esLockTest) mockGet(result *elastic.GetResult, err error) {
+	getService := &mocks.GetService{}
+	r.client.On("Get").Return(getService)
+	getService.On("Index", "foo-jaeger-locks").Return(getService)
+	getService.On("Type", lockType).Return(getService)
+	getService.On("Id", samplingLock).Return(getService)
+	getService.On("Do", mock.Anything).Return(result, err)
+}
+
+func (r *esLockTest) mockIndex(create bool, err error) *mocks.DocumentIndexService {
+	indexService := &mocks.DocumentIndexService{}
+	r.client.On("IndexDocument").Return(indexService)
+	indexService.On("Index", "foo-jaeger-locks").Return(indexService)
+	indexService.On("Type", lockType).Return(indexService)
+	indexService.On("Id", samplingLock).Return(indexService)
+	if create {
+		indexService.On("OpType", opCreate).Return(indexService)
+	} else {
+		indexService.On("IfSeqNo", int64(3)).Return(indexService)
+		indexService.On("IfPrimaryTerm", int64(1)).Return(indexService)
+	}
+	indexService.On("BodyJson", &lease{Owner: localhost, Expires: now.Add(time.Minute)}).Return(indexService)
+	indexService.On("Do", mock.Anything).Return(&elastic.IndexResponse{}, err)
+	return indexService
+}
+
+func TestAcquire(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		getResult      *elastic.GetResult
+		getError       error
+		create         bool
+		skipIndex      bool
+		indexError     error
+		acquired       bool
+		expectedErrMsg string
+	}{
+		{
+			caption:  "new lease",
+			getError: errNotFound,
+			create:   true,
+			acquired: true,
+		},
+		{
+			caption:    "new lease created by another host",
+			getError:   errNotFound,
+			create:     true,
+			indexError: errConflict,
+			acquired:   false,
+		},
+		{
+			caption:        "new lease error",
+			getError:       errNotFound,
+			create:         true,
+			indexError:     errors.New("index error"),
+			expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: index error",
+		},
+		{
+			caption:   "extend own lease",
+			getResult: getResult(localhost, now.Add(time.Second)),
+			acquired:  true,
+		},
+		{
+			caption:   "take over expired lease",
+			getResult: getResult("otherhost", now),
+			acquired:  true,
+		},
+		{
+			caption:    "lease modified by another host",
+			getResult:  getResult("otherhost", now),
+			indexError: errConflict,
+			acquired:   false,
+		},
+		{
+			caption:        "extend lease error",
+			getResult:      getResult(localhost, now.Add(time.Second)),
+			indexError:     errors.New("index error"),
+			expectedErrMsg: "failed to extend lease on resource lock: index error",
+		},
+		{
+			caption:   "lease held by another host",
+			getResult: getResult("otherhost", now.Add(time.Second)),
+			skipIndex: true,
+			acquired:  false,
+		},
+		{
+			caption:        "get error",
+			getError:       errors.New("get error"),
+			skipIndex:      true,
+			expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: get error",
+		},
+		{
+			caption:        "elasticsearch too old",
+			getResult:      &elastic.GetResult{Found: true, Source: getResult(localhost, now).Source},
+			skipIndex:      true,
+			expectedErrMsg: "failed to acquire resource lock due to elasticsearch error: elasticsearch did not return the sequence number of the lock, version 6.7 or later is required",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			withESLock(func(r *esLockTest) {
+				r.mockGet(tc.getResult, tc.getError)
+				var indexService *mocks.DocumentIndexService
+				if !tc.skipIndex {
+					indexService = r.mockIndex(tc.create, tc.indexError)
+				}
+				acquired, err := r.lock.Acquire(samplingLock, time.Minute)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+					assert.Equal(t, tc.acquired, acquired)
+				} else {
+					assert.EqualError(t, err, tc.expectedErrMsg)
+				}
+				if indexService != nil {
+					indexService.AssertExpectations(t)
+				}
+			})
+		})
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		getResult      *elastic.GetResult
+		getError       error
+		deleteError    error
+		skipDelete     bool
+		forfeited      bool
+		expectedErrMsg string
+	}{
+		{
+			caption:   "own lease",
+			getResult: getResult(localhost, now.Add(time.Second)),
+			forfeited: true,
+		},
+		{
+			caption:        "lease modified by another host",
+			getResult:      getResult(localhost, now.Add(time.Second)),
+			deleteError:    errConflict,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "delete error",
+			getResult:      getResult(localhost, now.Add(time.Second)),
+			deleteError:    errors.New("delete error"),
+			expectedErrMsg: "failed to forfeit resource lock due to elasticsearch error: delete error",
+		},
+		{
+			caption:        "no lease",
+			getError:       errNotFound,
+			skipDelete:     true,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "lease held by another host",
+			getResult:      getResult("otherhost", now.Add(time.Second)),
+			skipDelete:     true,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "expired lease",
+			getResult:      getResult(localhost, now),
+			skipDelete:     true,
+			expectedErrMsg: "failed to forfeit resource lock: this host does not own the resource lock",
+		},
+		{
+			caption:        "get error",
+			getError:       errors.New("get error"),
+			skipDelete:     true,
+			expectedErrMsg: "failed to forfeit resource lock due to elasticsearch error: get error",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caption, func(t *testing.T) {
+			withESLock(func(r *esLockTest) {
+				r.mockGet(tc.getResult, tc.getError)
+				if !tc.skipDelete {
+					deleteService := &mocks.DeleteService{}
+					r.client.On("Delete").Return(deleteService)
+					deleteService.On("Index", "foo-jaeger-locks").Return(deleteService)
+					deleteService.On("Type", lockType).Return(deleteService)
+					deleteService.On("Id", samplingLock).Return(deleteService)
+					deleteService.On("IfSeqNo", int64(3)).Return(deleteService)
+					deleteService.On("IfPrimaryTerm", int64(1)).Return(deleteService)
+					deleteService.On("Do", mock.Anything).Return(&elastic.DeleteResponse{}, tc.deleteError)
+				}
+				forfeited, err := r.lock.Forfeit(samplingLock)
+				if tc.expectedErrMsg == "" {
+					require.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, tc.expectedErrMsg)
+				}
+				assert.Equal(t, tc.forfeited, forfeited)
+			})
+		})
+	}
+}
+
+func TestAcquireDefaultTTL(t *testing.T) {
+	withESLock(func(r *esLockTest) {
+		r.lock.now = func() time.Time { return now.Add(time.Minute - defaultTTL) }
+		r.mockGet(nil, errNotFound)
+		r.mockIndex(true, nil)
+		acquired, err := r.lock.Acquire(samplingLock, 0)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultTTL = 60 * time.Second
+
+var errLockOwnership = errors.New("this host does not own the resource lock")
+
+type lease struct {
+	owner   string
+	expires time.Time
+}
+
+// Lock is a lock held in the memory of the process. It is only suitable for storage backends
+// that cannot be shared between several processes, such as the memory or the Badger stores.
+type Lock struct {
+	sync.Mutex
+	owner  string
+	leases map[string]lease
+	now    func() time.Time
+}
+
+// NewLock creates a new instance of a local lock for the given owner.
+func NewLock(owner string) *Lock {
+	return &Lock{
+		owner:  owner,
+		leases: make(map[string]lease),
+		now:    time.Now,
+	}
+}
+
+// Acquire acquires a lease around a given resource, or extends it if this owner already holds it.
+func (l *Lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	l.Lock()
+	defer l.Unlock()
+	now := l.now()
+	if current, ok := l.leases[resource]; ok && current.owner != l.owner && now.Before(current.expires) {
+		return false, nil
+	}
+	l.leases[resource] = lease{owner: l.owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Forfeit forfeits an existing lease around a given resource.
+func (l *Lock) Forfeit(resource string) (bool, error) {
+	l.Lock()
+	defer l.Unlock()
+	current, ok := l.leases[resource]
+	if !ok || current.owner != l.owner || !l.now().Before(current.expires) {
+		return false, fmt.Errorf("failed to forfeit resource lock: %w", errLockOwnership)
+	}
+	delete(l.leases, resource)
+	return true, nil
+}
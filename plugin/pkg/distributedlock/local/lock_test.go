@@ -0,0 +1,80 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samplingLock = "sampling_lock"
+
+func TestAcquireAndForfeit(t *testing.T) {
+	lock := NewLock("localhost")
+
+	acquired, err := lock.Acquire(samplingLock, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = lock.Acquire(samplingLock, 0)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the owner can extend its lease")
+
+	forfeited, err := lock.Forfeit(samplingLock)
+	require.NoError(t, err)
+	assert.True(t, forfeited)
+
+	forfeited, err = lock.Forfeit(samplingLock)
+	assert.EqualError(t, err, "failed to forfeit resource lock: this host does not own the resource lock")
+	assert.False(t, forfeited)
+}
+
+func TestAcquireHeldByAnotherOwner(t *testing.T) {
+	now := time.Unix(0, 0)
+	lock := NewLock("localhost")
+	lock.now = func() time.Time { return now }
+	lock.leases[samplingLock] = lease{owner: "otherhost", expires: now.Add(time.Minute)}
+
+	acquired, err := lock.Acquire(samplingLock, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	forfeited, err := lock.Forfeit(samplingLock)
+	assert.Error(t, err)
+	assert.False(t, forfeited)
+
+	now = now.Add(time.Minute)
+	acquired, err = lock.Acquire(samplingLock, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the lease of the other owner has expired")
+}
+
+func TestForfeitExpiredLease(t *testing.T) {
+	now := time.Unix(0, 0)
+	lock := NewLock("localhost")
+	lock.now = func() time.Time { return now }
+
+	acquired, err := lock.Acquire(samplingLock, time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	now = now.Add(time.Second)
+	forfeited, err := lock.Forfeit(samplingLock)
+	assert.Error(t, err)
+	assert.False(t, forfeited)
+}
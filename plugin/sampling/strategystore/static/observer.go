@@ -0,0 +1,146 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+const (
+	// maxObservedTagValues limits the number of values recorded for a process tag of a service.
+	maxObservedTagValues = 100
+
+	// maxObservedOperations limits the number of root span operations recorded for a service.
+	maxObservedOperations = 1000
+)
+
+// serviceObserver records what the services report along with their spans: the values of the
+// process tags referenced by the sampling rules, and the operations of their root spans, which
+// operation patterns are expanded to.
+type serviceObserver struct {
+	sync.RWMutex
+	services map[string]*observedService
+}
+
+type observedService struct {
+	tags       map[string]map[string]struct{}
+	operations map[string]struct{}
+}
+
+func newServiceObserver() *serviceObserver {
+	return &serviceObserver{services: make(map[string]*observedService)}
+}
+
+func (o *serviceObserver) observe(span *model.Span, tagKeys map[string]struct{}) {
+	if span.Process == nil || span.Process.ServiceName == "" {
+		return
+	}
+	service := span.Process.ServiceName
+	operation := ""
+	if span.ParentSpanID() == model.NewSpanID(0) {
+		operation = span.OperationName
+	}
+
+	o.RLock()
+	known := o.services[service].knows(span.Process.Tags, operation, tagKeys)
+	o.RUnlock()
+	if known {
+		return
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	s, ok := o.services[service]
+	if !ok {
+		s = &observedService{
+			tags:       make(map[string]map[string]struct{}),
+			operations: make(map[string]struct{}),
+		}
+		o.services[service] = s
+	}
+	for _, tag := range span.Process.Tags {
+		if _, ok := tagKeys[tag.Key]; !ok {
+			continue
+		}
+		values, ok := s.tags[tag.Key]
+		if !ok {
+			values = make(map[string]struct{})
+			s.tags[tag.Key] = values
+		}
+		if len(values) < maxObservedTagValues {
+			values[tag.AsString()] = struct{}{}
+		}
+	}
+	if operation != "" && len(s.operations) < maxObservedOperations {
+		s.operations[operation] = struct{}{}
+	}
+}
+
+// knows returns true if recording the tags and operation would not change the observations.
+func (s *observedService) knows(tags []model.KeyValue, operation string, tagKeys map[string]struct{}) bool {
+	if s == nil {
+		return false
+	}
+	for _, tag := range tags {
+		if _, ok := tagKeys[tag.Key]; !ok {
+			continue
+		}
+		values := s.tags[tag.Key]
+		if _, ok := values[tag.AsString()]; !ok && len(values) < maxObservedTagValues {
+			return false
+		}
+	}
+	if operation == "" || len(s.operations) >= maxObservedOperations {
+		return true
+	}
+	_, ok := s.operations[operation]
+	return ok
+}
+
+// processTags returns the sorted values observed for each process tag of the service.
+func (o *serviceObserver) processTags(service string) map[string][]string {
+	o.RLock()
+	defer o.RUnlock()
+	s, ok := o.services[service]
+	if !ok {
+		return nil
+	}
+	tags := make(map[string][]string, len(s.tags))
+	for key, values := range s.tags {
+		for v := range values {
+			tags[key] = append(tags[key], v)
+		}
+		sort.Strings(tags[key])
+	}
+	return tags
+}
+
+// operations returns the root span operations observed for the service.
+func (o *serviceObserver) operations(service string) []string {
+	o.RLock()
+	defer o.RUnlock()
+	s, ok := o.services[service]
+	if !ok {
+		return nil
+	}
+	operations := make([]string, 0, len(s.operations))
+	for op := range s.operations {
+		operations = append(operations, op)
+	}
+	return operations
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func makeSpan(service, operation string, root bool, tags ...model.KeyValue) *model.Span {
+	span := &model.Span{
+		TraceID:       model.NewTraceID(0, 1),
+		OperationName: operation,
+		Process:       model.NewProcess(service, tags),
+	}
+	if !root {
+		span.References = []model.SpanRef{model.NewChildOfRef(model.NewTraceID(0, 1), model.NewSpanID(1))}
+	}
+	return span
+}
+
+func TestServiceObserver(t *testing.T) {
+	o := newServiceObserver()
+	tagKeys := map[string]struct{}{"environment": {}, "hostname": {}}
+
+	o.observe(&model.Span{OperationName: "no-process"}, tagKeys)
+	o.observe(makeSpan("", "no-service", true), tagKeys)
+	assert.Empty(t, o.services)
+
+	o.observe(makeSpan("foo", "GET /a", true, model.String("environment", "prod"), model.String("ip", "10.0.0.1")), tagKeys)
+	o.observe(makeSpan("foo", "SELECT", false, model.String("environment", "staging")), tagKeys)
+	o.observe(makeSpan("foo", "GET /a", true, model.String("environment", "prod"), model.Int64("hostname", 42)), tagKeys)
+
+	assert.Equal(t, map[string][]string{
+		"environment": {"prod", "staging"},
+		"hostname":    {"42"},
+	}, o.processTags("foo"))
+	assert.Equal(t, []string{"GET /a"}, o.operations("foo"))
+
+	assert.Nil(t, o.processTags("bar"))
+	assert.Nil(t, o.operations("bar"))
+}
+
+func TestServiceObserverLimits(t *testing.T) {
+	o := newServiceObserver()
+	tagKeys := map[string]struct{}{"hostname": {}}
+
+	for i := 0; i < maxObservedTagValues+10; i++ {
+		o.observe(makeSpan("foo", fmt.Sprintf("op-%d", i), true, model.String("hostname", fmt.Sprintf("host-%d", i))), tagKeys)
+	}
+	for i := 0; i < maxObservedOperations; i++ {
+		o.observe(makeSpan("foo", fmt.Sprintf("op-%d", i), true), tagKeys)
+	}
+	assert.Len(t, o.processTags("foo")["hostname"], maxObservedTagValues)
+	assert.Len(t, o.operations("foo"), maxObservedOperations)
+
+	s := o.services["foo"]
+	assert.True(t, s.knows([]model.KeyValue{model.String("hostname", "host-999")}, "op-9999", tagKeys))
+}
@@ -29,7 +29,7 @@ const (
 
 // Options holds configuration for the static sampling strategy store.
 type Options struct {
-	// StrategiesFile is the path for the sampling strategies file in JSON format
+	// StrategiesFile is the path for the sampling strategies file in JSON or YAML format
 	StrategiesFile string
 	// ReloadInterval is the time interval to check and reload sampling strategies file
 	ReloadInterval time.Duration
@@ -38,7 +38,7 @@ type Options struct {
 // AddFlags adds flags for Options
 func AddFlags(flagSet *flag.FlagSet) {
 	flagSet.Duration(samplingStrategiesReloadInterval, 0, "Reload interval to check and reload sampling strategies file. Zero value means no reloading")
-	flagSet.String(samplingStrategiesFile, "", "The path for the sampling strategies file in JSON format. See sampling documentation to see format of the file")
+	flagSet.String(samplingStrategiesFile, "", "The path for the sampling strategies file in JSON or YAML format (with a .yaml or .yml extension). See sampling documentation to see format of the file")
 }
 
 // InitFromViper initializes Options with properties from viper
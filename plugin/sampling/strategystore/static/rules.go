@@ -0,0 +1,339 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+)
+
+// compiledRule is a validated rule with its patterns compiled.
+type compiledRule struct {
+	path       string
+	name       string
+	source     string
+	service    *regexp.Regexp // nil matches any service
+	tags       map[string]*regexp.Regexp
+	strategy   strategy
+	operations []*compiledOperationRule
+	// lowerBound is the first rate limited operation rule, if any, whose max traces per second
+	// are the lower bound of all the operations of the service.
+	lowerBound *compiledOperationRule
+}
+
+type compiledOperationRule struct {
+	path   string
+	source string
+	// name is set when the rule matches a single operation, pattern otherwise.
+	name     string
+	pattern  *regexp.Regexp
+	strategy strategy
+}
+
+// sourceLocator resolves the elements of a strategies file to their line in the file.
+// JSON being a subset of YAML, both formats are located with the YAML parser.
+type sourceLocator struct {
+	name string
+	root *yaml.Node
+}
+
+func newSourceLocator(name string, data []byte) *sourceLocator {
+	l := &sourceLocator{name: name}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err == nil && len(doc.Content) > 0 {
+		l.root = doc.Content[0]
+	}
+	return l
+}
+
+// position returns the file and line of the element found by following the path
+// of mapping keys and sequence indices, or only the file if the element is not found.
+func (l *sourceLocator) position(path ...interface{}) string {
+	node := l.root
+	for _, p := range path {
+		node = childNode(node, p)
+	}
+	if node == nil {
+		return l.name
+	}
+	return fmt.Sprintf("%s:%d", l.name, node.Line)
+}
+
+func childNode(node *yaml.Node, p interface{}) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	switch key := p.(type) {
+	case string:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+	case int:
+		if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+			return node.Content[key]
+		}
+	}
+	return nil
+}
+
+// compileRules validates the rules and compiles their patterns. The errors locate the invalid rule
+// by its index and its position in the strategies file.
+func compileRules(rules []*rule, locator *sourceLocator) ([]*compiledRule, error) {
+	compiled := make([]*compiledRule, 0, len(rules))
+	for i, r := range rules {
+		if r == nil {
+			return nil, fmt.Errorf("%s: rules[%d]: empty rule", locator.position("rules", i), i)
+		}
+		cr, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: rules[%d]: %w", locator.position("rules", i), i, err)
+		}
+		cr.path = fmt.Sprintf("rules[%d]", i)
+		cr.source = locator.position("rules", i)
+
+		var lowerBound *compiledOperationRule
+		for j, o := range r.OperationStrategies {
+			path := fmt.Sprintf("rules[%d].operation_strategies[%d]", i, j)
+			source := locator.position("rules", i, "operation_strategies", j)
+			if o == nil {
+				return nil, fmt.Errorf("%s: %s: empty operation strategy", source, path)
+			}
+			co, err := compileOperationRule(o)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", source, path, err)
+			}
+			co.path, co.source = path, source
+			// Clients only support a single lower bound of traces per second for all the operations
+			// of a service, which rate limited operations are sampled with.
+			if co.strategy.Type == samplerTypeRateLimiting {
+				if lowerBound != nil && lowerBound.strategy.Param != co.strategy.Param {
+					return nil, fmt.Errorf(
+						"%s: %s: max traces per second %v differs from %v of %s, "+
+							"all the rate limited operations of a service must have the same limit",
+						source, path, co.strategy.Param, lowerBound.strategy.Param, lowerBound.path)
+				}
+				if lowerBound == nil {
+					lowerBound = co
+				}
+			}
+			cr.operations = append(cr.operations, co)
+		}
+		cr.lowerBound = lowerBound
+		compiled = append(compiled, cr)
+	}
+	return compiled, nil
+}
+
+func compileRule(r *rule) (*compiledRule, error) {
+	if err := validateStrategy(r.strategy); err != nil {
+		return nil, err
+	}
+	cr := &compiledRule{name: r.Name, strategy: r.strategy}
+	var err error
+	if cr.service, err = compileNamePattern("service", r.Service, r.ServiceRegex); err != nil {
+		return nil, err
+	}
+	if len(r.ProcessTags) > 0 {
+		cr.tags = make(map[string]*regexp.Regexp, len(r.ProcessTags))
+		for key, value := range r.ProcessTags {
+			if key == "" {
+				return nil, fmt.Errorf("process_tags: empty tag name")
+			}
+			cr.tags[key] = compileGlob(value)
+		}
+	}
+	return cr, nil
+}
+
+func compileOperationRule(o *operationRule) (*compiledOperationRule, error) {
+	if err := validateStrategy(o.strategy); err != nil {
+		return nil, err
+	}
+	if o.Operation == "" && o.OperationRegex == "" {
+		return nil, fmt.Errorf("one of operation or operation_regex is required")
+	}
+	co := &compiledOperationRule{strategy: o.strategy}
+	if o.OperationRegex == "" && !isGlob(o.Operation) {
+		co.name = o.Operation
+		return co, nil
+	}
+	var err error
+	co.pattern, err = compileNamePattern("operation", o.Operation, o.OperationRegex)
+	return co, err
+}
+
+func validateStrategy(s strategy) error {
+	switch s.Type {
+	case samplerTypeProbabilistic:
+		if s.Param < 0 || s.Param > 1 {
+			return fmt.Errorf("sampling probability %v is not between 0 and 1", s.Param)
+		}
+	case samplerTypeRateLimiting:
+		if s.Param < 0 || s.Param > math.MaxInt16 {
+			return fmt.Errorf("max traces per second %v is not between 0 and %d", s.Param, math.MaxInt16)
+		}
+	default:
+		return fmt.Errorf("unknown strategy type %q, must be %q or %q",
+			s.Type, samplerTypeProbabilistic, samplerTypeRateLimiting)
+	}
+	return nil
+}
+
+// compileNamePattern compiles either the glob or the regular expression of a name.
+func compileNamePattern(field, glob, expr string) (*regexp.Regexp, error) {
+	if glob != "" && expr != "" {
+		return nil, fmt.Errorf("%s and %s_regex are mutually exclusive", field, field)
+	}
+	if expr != "" {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_regex: %w", field, err)
+		}
+		return re, nil
+	}
+	if glob != "" {
+		return compileGlob(glob), nil
+	}
+	return nil, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?")
+}
+
+// compileGlob compiles a glob pattern where '*' matches any sequence of characters
+// and '?' any single character.
+func compileGlob(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// matches returns true if the rule matches the service and, for each of its process tag patterns,
+// the service has reported the tag and all of its values match the pattern.
+func (r *compiledRule) matches(service string, tags map[string][]string) bool {
+	if r.service != nil && !r.service.MatchString(service) {
+		return false
+	}
+	for key, pattern := range r.tags {
+		values := tags[key]
+		if len(values) == 0 {
+			return false
+		}
+		for _, v := range values {
+			if !pattern.MatchString(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lowerBoundWarning explains that the rate limited operations of the rule also guarantee their
+// max traces per second to the other operations of the service, or returns "" if it has none.
+func (r *compiledRule) lowerBoundWarning() string {
+	if r.lowerBound == nil {
+		return ""
+	}
+	return fmt.Sprintf(
+		"%s: %s sets a lower bound of %v traces per second for every operation of the service, "+
+			"including the operations sampled with a probability",
+		r.lowerBound.source, r.lowerBound.path, r.lowerBound.strategy.Param)
+}
+
+func (r *compiledRule) matchedRule() *ss.MatchedRule {
+	return &ss.MatchedRule{Path: r.path, Name: r.name, Source: r.source}
+}
+
+func (o *compiledOperationRule) matches(operation string) bool {
+	if o.pattern == nil {
+		return o.name == operation
+	}
+	return o.pattern.MatchString(operation)
+}
+
+// matchOperation returns the first operation rule matching the operation.
+func (r *compiledRule) matchOperation(operation string) *compiledOperationRule {
+	for _, o := range r.operations {
+		if o.matches(operation) {
+			return o
+		}
+	}
+	return nil
+}
+
+// samplingStrategy builds the strategy of a service matched by the rule. Since clients only
+// understand operation names, operation patterns are expanded to the known operations of the service.
+func (r *compiledRule) samplingStrategy(operations []string) *sampling.SamplingStrategyResponse {
+	resp := newStrategyResponse(&r.strategy)
+	if len(r.operations) == 0 {
+		return resp
+	}
+	opS := &sampling.PerOperationSamplingStrategies{
+		DefaultSamplingProbability: defaultSamplingProbability,
+	}
+	if resp.StrategyType == sampling.SamplingStrategyType_PROBABILISTIC {
+		opS.DefaultSamplingProbability = resp.ProbabilisticSampling.SamplingRate
+	}
+	names := make([]string, 0, len(operations)+len(r.operations))
+	for _, o := range r.operations {
+		if o.pattern == nil {
+			names = append(names, o.name)
+		}
+	}
+	names = append(names, operations...)
+	sort.Strings(names)
+
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		o := r.matchOperation(name)
+		if o == nil {
+			continue
+		}
+		probability := o.strategy.Param
+		if o.strategy.Type == samplerTypeRateLimiting {
+			// Rate limited operations rely solely on the lower bound of the guaranteed throughput sampler.
+			probability = 0
+			opS.DefaultLowerBoundTracesPerSecond = o.strategy.Param
+		}
+		opS.PerOperationStrategies = append(opS.PerOperationStrategies,
+			&sampling.OperationSamplingStrategy{
+				Operation: name,
+				ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{
+					SamplingRate: probability,
+				},
+			})
+	}
+	resp.OperationSampling = opS
+	return resp
+}
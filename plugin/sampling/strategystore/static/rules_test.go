@@ -0,0 +1,247 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package static
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
+)
+
+func TestUnmarshalStrategiesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		data   string
+		err    string
+	}{
+		{
+			name:   "JSON syntax error",
+			source: "strategies.json",
+			data:   "{\n  \"rules\": [\n    {\"type\": }\n  ]\n}",
+			err:    "failed to unmarshal strategies from strategies.json:3: invalid character '}' looking for beginning of value",
+		},
+		{
+			name:   "YAML type error",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: probabilistic\n    param: often\n",
+			err:    "failed to unmarshal strategies from strategies.yaml: yaml: unmarshal errors:\n  line 3: cannot unmarshal !!str `often` into float64",
+		},
+		{
+			name:   "unknown strategy type",
+			source: "strategies.yaml",
+			data:   "rules:\n  - service: foo\n    type: probabilistic\n    param: 1\n  - service: bar\n    type: sometimes\n",
+			err:    `invalid sampling strategies: strategies.yaml:5: rules[1]: unknown strategy type "sometimes", must be "probabilistic" or "ratelimiting"`,
+		},
+		{
+			name:   "probability out of range",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: probabilistic\n    param: 1.5\n",
+			err:    "invalid sampling strategies: strategies.yaml:2: rules[0]: sampling probability 1.5 is not between 0 and 1",
+		},
+		{
+			name:   "rate limit out of range",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: ratelimiting\n    param: 40000\n",
+			err:    "invalid sampling strategies: strategies.yaml:2: rules[0]: max traces per second 40000 is not between 0 and 32767",
+		},
+		{
+			name:   "service and service_regex",
+			source: "strategies.json",
+			data:   `{"rules": [{"service": "a*", "service_regex": "a.*", "type": "probabilistic", "param": 1}]}`,
+			err:    "invalid sampling strategies: strategies.json:1: rules[0]: service and service_regex are mutually exclusive",
+		},
+		{
+			name:   "invalid service_regex",
+			source: "strategies.yaml",
+			data:   "rules:\n  - service_regex: \"(\"\n    type: probabilistic\n    param: 1\n",
+			err:    "invalid sampling strategies: strategies.yaml:2: rules[0]: invalid service_regex: error parsing regexp: missing closing ): `^(?:()$`",
+		},
+		{
+			name:   "empty process tag name",
+			source: "strategies.yaml",
+			data:   "rules:\n  - process_tags:\n      \"\": prod\n    type: probabilistic\n    param: 1\n",
+			err:    "invalid sampling strategies: strategies.yaml:2: rules[0]: process_tags: empty tag name",
+		},
+		{
+			name:   "empty rule",
+			source: "strategies.json",
+			data:   "{\n\"rules\": [\nnull\n]}",
+			err:    "invalid sampling strategies: strategies.json:3: rules[0]: empty rule",
+		},
+		{
+			name:   "missing operation",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: probabilistic\n    param: 1\n    operation_strategies:\n      - type: probabilistic\n        param: 1\n",
+			err:    "invalid sampling strategies: strategies.yaml:5: rules[0].operation_strategies[0]: one of operation or operation_regex is required",
+		},
+		{
+			name:   "empty operation strategy",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: probabilistic\n    param: 1\n    operation_strategies:\n      - null\n",
+			err:    "invalid sampling strategies: strategies.yaml:5: rules[0].operation_strategies[0]: empty operation strategy",
+		},
+		{
+			name:   "invalid operation strategy",
+			source: "strategies.yaml",
+			data:   "rules:\n  - type: probabilistic\n    param: 1\n    operation_strategies:\n      - operation: a\n        type: probabilistic\n        param: -1\n",
+			err:    "invalid sampling strategies: strategies.yaml:5: rules[0].operation_strategies[0]: sampling probability -1 is not between 0 and 1",
+		},
+		{
+			name:   "different operation rate limits",
+			source: "strategies.yaml",
+			data: "rules:\n  - type: probabilistic\n    param: 1\n    operation_strategies:\n" +
+				"      - operation: a\n        type: ratelimiting\n        param: 1\n" +
+				"      - operation: b\n        type: ratelimiting\n        param: 2\n",
+			err: "invalid sampling strategies: strategies.yaml:8: rules[0].operation_strategies[1]: max traces per second 2 differs from 1 " +
+				"of rules[0].operation_strategies[0], all the rate limited operations of a service must have the same limit",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := unmarshalStrategies(test.source, []byte(test.data))
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestUnmarshalStrategiesFormats(t *testing.T) {
+	for _, source := range []string{"strategies.yaml", "strategies.YML", "http://example.com/strategies.yaml?v=1"} {
+		s, err := unmarshalStrategies(source, []byte("rules:\n  - service: foo\n    type: probabilistic\n    param: 1\n"))
+		require.NoError(t, err, source)
+		require.Len(t, s.rules, 1, source)
+		assert.Equal(t, source+":2", s.rules[0].source)
+	}
+
+	s, err := unmarshalStrategies("strategies.json", []byte("null"))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = unmarshalStrategies("strategies.yaml", []byte(""))
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestSourceLocator(t *testing.T) {
+	l := newSourceLocator("fixtures/rules.yaml", []byte("a:\n  - b: 1\n  - c:\n      - 2\n"))
+	assert.Equal(t, "fixtures/rules.yaml:1", l.position())
+	assert.Equal(t, "fixtures/rules.yaml:3", l.position("a", 1))
+	assert.Equal(t, "fixtures/rules.yaml:4", l.position("a", 1, "c", 0))
+	assert.Equal(t, "fixtures/rules.yaml", l.position("a", 2))
+	assert.Equal(t, "fixtures/rules.yaml", l.position("b"))
+	assert.Equal(t, "fixtures/rules.yaml", l.position("a", "b"))
+	assert.Equal(t, "fixtures/rules.yaml", l.position("a", 0, "b", 0))
+
+	l = newSourceLocator("bad.yaml", []byte("{"))
+	assert.Equal(t, "bad.yaml", l.position("a"))
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{pattern: "web-*", name: "web-1", match: true},
+		{pattern: "web-*", name: "web-", match: true},
+		{pattern: "web-*", name: "xweb-1", match: false},
+		{pattern: "GET /cart/*", name: "GET /cart/items/1", match: true},
+		{pattern: "canary-?", name: "canary-1", match: true},
+		{pattern: "canary-?", name: "canary-12", match: false},
+		{pattern: "GET /(a|b)", name: "GET /(a|b)", match: true},
+		{pattern: "GET /(a|b)", name: "GET /a", match: false},
+		{pattern: "", name: "", match: true},
+		{pattern: "", name: "a", match: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.match, compileGlob(test.pattern).MatchString(test.name), "%s ~ %s", test.pattern, test.name)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	s, err := unmarshalStrategies("fixtures/rules.yaml", []byte(`
+rules:
+  - service: checkout*
+    process_tags:
+      environment: prod
+      hostname: web-*
+    type: probabilistic
+    param: 1
+  - type: probabilistic
+    param: 1
+`))
+	require.NoError(t, err)
+	r := s.rules[0]
+	prod := map[string][]string{"environment": {"prod"}, "hostname": {"web-1", "web-2"}}
+	assert.True(t, r.matches("checkout", prod))
+	assert.True(t, r.matches("checkout-eu", prod))
+	assert.False(t, r.matches("cart", prod))
+	assert.False(t, r.matches("checkout", nil))
+	assert.False(t, r.matches("checkout", map[string][]string{"environment": {"prod"}}))
+	assert.False(t, r.matches("checkout", map[string][]string{"environment": {"prod", "staging"}, "hostname": {"web-1"}}))
+	assert.False(t, r.matches("checkout", map[string][]string{"environment": {"prod"}, "hostname": {"web-1", "db-1"}}))
+
+	assert.True(t, s.rules[1].matches("anything", nil))
+}
+
+func TestRuleSamplingStrategy(t *testing.T) {
+	s, err := unmarshalStrategies("fixtures/rules.yaml", []byte(`
+rules:
+  - type: ratelimiting
+    param: 3
+    operation_strategies:
+      - operation: /health
+        type: probabilistic
+        param: 0
+      - operation: GET *
+        type: probabilistic
+        param: 0.5
+      - operation_regex: GET /(a|b)
+        type: probabilistic
+        param: 1
+      - operation: POST *
+        type: ratelimiting
+        param: 2
+  - type: probabilistic
+    param: 0.3
+`))
+	require.NoError(t, err)
+
+	resp := s.rules[0].samplingStrategy([]string{"POST /a", "GET /a", "/health", "DELETE /a"})
+	assert.Equal(t, sampling.SamplingStrategyType_RATE_LIMITING, resp.StrategyType)
+	assert.EqualValues(t, 3, resp.RateLimitingSampling.MaxTracesPerSecond)
+	require.NotNil(t, resp.OperationSampling)
+	opS := resp.OperationSampling
+	assert.Equal(t, defaultSamplingProbability, opS.DefaultSamplingProbability)
+	assert.EqualValues(t, 2, opS.DefaultLowerBoundTracesPerSecond)
+	require.Len(t, opS.PerOperationStrategies, 3)
+	// operations are sorted by name, and the first matching operation rule applies
+	assert.Equal(t, "/health", opS.PerOperationStrategies[0].Operation)
+	assert.EqualValues(t, 0, opS.PerOperationStrategies[0].ProbabilisticSampling.SamplingRate)
+	assert.Equal(t, "GET /a", opS.PerOperationStrategies[1].Operation)
+	assert.EqualValues(t, 0.5, opS.PerOperationStrategies[1].ProbabilisticSampling.SamplingRate)
+	assert.Equal(t, "POST /a", opS.PerOperationStrategies[2].Operation)
+	assert.EqualValues(t, 0, opS.PerOperationStrategies[2].ProbabilisticSampling.SamplingRate)
+
+	// the earlier glob takes precedence over the regular expression
+	assert.Equal(t, "rules[0].operation_strategies[1]", s.rules[0].matchOperation("GET /b").path)
+	assert.Nil(t, s.rules[0].matchOperation("DELETE /a"))
+
+	resp = s.rules[1].samplingStrategy([]string{"GET /a"})
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.3), *resp)
+}
@@ -17,25 +17,51 @@ package static
 // strategy defines a sampling strategy. Type can be "probabilistic" or "ratelimiting"
 // and Param will represent "sampling probability" and "max traces per second" respectively.
 type strategy struct {
-	Type  string  `json:"type"`
-	Param float64 `json:"param"`
+	Type  string  `json:"type" yaml:"type"`
+	Param float64 `json:"param" yaml:"param"`
 }
 
 // operationStrategy defines an operation specific sampling strategy.
 type operationStrategy struct {
-	Operation string `json:"operation"`
-	strategy
+	Operation string `json:"operation" yaml:"operation"`
+	strategy  `yaml:",inline"`
 }
 
 // serviceStrategy defines a service specific sampling strategy.
 type serviceStrategy struct {
-	Service             string               `json:"service"`
-	OperationStrategies []*operationStrategy `json:"operation_strategies"`
-	strategy
+	Service             string               `json:"service" yaml:"service"`
+	OperationStrategies []*operationStrategy `json:"operation_strategies" yaml:"operation_strategies"`
+	strategy            `yaml:",inline"`
 }
 
-// strategies holds a default sampling strategy and service specific sampling strategies.
+// rule defines the sampling strategy of the services it matches. Rules are evaluated in order
+// and the first rule matching a service selects its strategy.
+type rule struct {
+	Name string `json:"name" yaml:"name"`
+	// Service is a glob pattern of the service name, ServiceRegex a regular expression
+	// matching the whole service name. When both are empty the rule matches any service.
+	Service      string `json:"service" yaml:"service"`
+	ServiceRegex string `json:"service_regex" yaml:"service_regex"`
+	// ProcessTags maps the names of process tags to glob patterns of their values.
+	ProcessTags         map[string]string `json:"process_tags" yaml:"process_tags"`
+	OperationStrategies []*operationRule  `json:"operation_strategies" yaml:"operation_strategies"`
+	strategy            `yaml:",inline"`
+}
+
+// operationRule defines the sampling strategy of the operations it matches. Operation is either
+// an operation name or a glob pattern, OperationRegex a regular expression matching the whole name.
+type operationRule struct {
+	Operation      string `json:"operation" yaml:"operation"`
+	OperationRegex string `json:"operation_regex" yaml:"operation_regex"`
+	strategy       `yaml:",inline"`
+}
+
+// strategies holds a default sampling strategy, service specific sampling strategies and sampling rules.
 type strategies struct {
-	DefaultStrategy   *serviceStrategy   `json:"default_strategy"`
-	ServiceStrategies []*serviceStrategy `json:"service_strategies"`
+	DefaultStrategy   *serviceStrategy   `json:"default_strategy" yaml:"default_strategy"`
+	ServiceStrategies []*serviceStrategy `json:"service_strategies" yaml:"service_strategies"`
+	Rules             []*rule            `json:"rules" yaml:"rules"`
+
+	rules   []*compiledRule
+	locator *sourceLocator
 }
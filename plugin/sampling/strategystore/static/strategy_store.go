@@ -23,13 +23,17 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"path"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 
 	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
 
@@ -39,8 +43,10 @@ var nullJSON = []byte("null")
 
 type strategyStore struct {
 	logger *zap.Logger
+	source string
 
 	storedStrategies atomic.Value // holds *storedStrategies
+	observer         *serviceObserver
 
 	cancelFunc context.CancelFunc
 }
@@ -48,6 +54,13 @@ type strategyStore struct {
 type storedStrategies struct {
 	defaultStrategy   *sampling.SamplingStrategyResponse
 	serviceStrategies map[string]*sampling.SamplingStrategyResponse
+
+	// rules take precedence over the service strategies, they are evaluated in order.
+	rules []*compiledRule
+	// tagKeys are the process tags referenced by the rules.
+	tagKeys map[string]struct{}
+	// config is the parsed strategies file, used to explain the selected strategies.
+	config *strategies
 }
 
 type strategyLoader func() ([]byte, error)
@@ -57,6 +70,8 @@ func NewStrategyStore(options Options, logger *zap.Logger) (ss.StrategyStore, er
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	h := &strategyStore{
 		logger:     logger,
+		source:     options.StrategiesFile,
+		observer:   newServiceObserver(),
 		cancelFunc: cancelFunc,
 	}
 	h.storedStrategies.Store(defaultStrategies())
@@ -67,7 +82,7 @@ func NewStrategyStore(options Options, logger *zap.Logger) (ss.StrategyStore, er
 	}
 
 	loadFn := h.samplingStrategyLoader(options.StrategiesFile)
-	strategies, err := loadStrategies(loadFn)
+	strategies, err := loadStrategies(options.StrategiesFile, loadFn)
 	if err != nil {
 		return nil, err
 	}
@@ -80,8 +95,13 @@ func NewStrategyStore(options Options, logger *zap.Logger) (ss.StrategyStore, er
 }
 
 // GetSamplingStrategy implements StrategyStore#GetSamplingStrategy.
-func (h *strategyStore) GetSamplingStrategy(_ context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error) {
+func (h *strategyStore) GetSamplingStrategy(ctx context.Context, serviceName string) (*sampling.SamplingStrategyResponse, error) {
 	ss := h.storedStrategies.Load().(*storedStrategies)
+	if len(ss.rules) > 0 {
+		if r := ss.matchRule(serviceName, h.processTags(ctx, serviceName)); r != nil {
+			return ss.ruleStrategy(r, h.observer.operations(serviceName)), nil
+		}
+	}
 	serviceStrategies := ss.serviceStrategies
 	if strategy, ok := serviceStrategies[serviceName]; ok {
 		return strategy, nil
@@ -90,6 +110,68 @@ func (h *strategyStore) GetSamplingStrategy(_ context.Context, serviceName strin
 	return ss.defaultStrategy, nil
 }
 
+// ObserveSpan implements strategystore.SpanObserver. It records the process tags
+// and operations that the sampling rules are matched against.
+func (h *strategyStore) ObserveSpan(span *model.Span) {
+	stored := h.storedStrategies.Load().(*storedStrategies)
+	if len(stored.rules) == 0 {
+		return
+	}
+	h.observer.observe(span, stored.tagKeys)
+}
+
+// DryRun implements strategystore.DryRunner.
+func (h *strategyStore) DryRun(ctx context.Context, query ss.DryRunQuery) (*ss.DryRunResult, error) {
+	stored := h.storedStrategies.Load().(*storedStrategies)
+	result := &ss.DryRunResult{
+		ServiceName: query.ServiceName,
+		Operation:   query.Operation,
+	}
+	var r *compiledRule
+	if len(stored.rules) > 0 {
+		result.ProcessTags = h.processTags(ss.WithProcessTags(ctx, query.ProcessTags), query.ServiceName)
+		r = stored.matchRule(query.ServiceName, result.ProcessTags)
+	}
+	if r == nil {
+		result.Strategy, result.ServiceRule, result.OperationRule = stored.explain(query.ServiceName, query.Operation)
+	} else {
+		operations := h.observer.operations(query.ServiceName)
+		if query.Operation != "" {
+			operations = append(operations, query.Operation)
+		}
+		result.Strategy = stored.ruleStrategy(r, operations)
+		result.ServiceRule = r.matchedRule()
+		if warning := r.lowerBoundWarning(); warning != "" && result.Strategy.OperationSampling != nil {
+			result.Warnings = append(result.Warnings, warning)
+		}
+		if query.Operation != "" {
+			if o := r.matchOperation(query.Operation); o != nil {
+				result.OperationRule = &ss.MatchedRule{Path: o.path, Source: o.source}
+			} else {
+				result.OperationRule = stored.explainDefaultOperation(query.Operation)
+			}
+		}
+	}
+	if result.Strategy.OperationSampling == nil {
+		// without per-operation strategies the strategy of the service applies to all its operations
+		result.OperationRule = nil
+	}
+	return result, nil
+}
+
+// processTags returns the process tags that the client reported along with its request, or if it
+// did not, the process tags that the service reported along with its spans.
+func (h *strategyStore) processTags(ctx context.Context, serviceName string) map[string][]string {
+	if tags := ss.GetProcessTags(ctx); len(tags) > 0 {
+		processTags := make(map[string][]string, len(tags))
+		for k, v := range tags {
+			processTags[k] = []string{v}
+		}
+		return processTags
+	}
+	return h.observer.processTags(serviceName)
+}
+
 // Close stops updating the strategies
 func (h *strategyStore) Close() {
 	h.cancelFunc()
@@ -175,72 +257,202 @@ func (h *strategyStore) reloadSamplingStrategy(loadFn strategyLoader, lastValue
 }
 
 func (h *strategyStore) updateSamplingStrategy(bytes []byte) error {
-	var strategies strategies
-	if err := json.Unmarshal(bytes, &strategies); err != nil {
-		return fmt.Errorf("failed to unmarshal sampling strategies: %w", err)
+	strategies, err := unmarshalStrategies(h.source, bytes)
+	if err != nil {
+		return err
 	}
-	h.parseStrategies(&strategies)
+	h.parseStrategies(strategies)
 	h.logger.Info("Updated sampling strategies:" + string(bytes))
 	return nil
 }
 
 // TODO good candidate for a global util function
-func loadStrategies(loadFn strategyLoader) (*strategies, error) {
+func loadStrategies(source string, loadFn strategyLoader) (*strategies, error) {
 	strategyBytes, err := loadFn()
 	if err != nil {
 		return nil, err
 	}
+	return unmarshalStrategies(source, strategyBytes)
+}
 
+// unmarshalStrategies unmarshals the strategies read from the source, in YAML format if the
+// source has a .yaml or .yml extension and in JSON format otherwise, and validates their rules.
+func unmarshalStrategies(source string, data []byte) (*strategies, error) {
 	var strategies *strategies
-	if err := json.Unmarshal(strategyBytes, &strategies); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal strategies: %w", err)
+	if isYAML(source) {
+		if err := yaml.Unmarshal(data, &strategies); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal strategies from %s: %w", source, err)
+		}
+	} else if err := json.Unmarshal(data, &strategies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal strategies from %s: %w", jsonErrorPosition(source, data, err), err)
 	}
+	if strategies == nil {
+		return nil, nil
+	}
+	strategies.locator = newSourceLocator(source, data)
+	rules, err := compileRules(strategies.Rules, strategies.locator)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling strategies: %w", err)
+	}
+	strategies.rules = rules
 	return strategies, nil
 }
 
+func isYAML(source string) bool {
+	if u, err := url.Parse(source); err == nil && isURL(source) {
+		source = u.Path
+	}
+	switch strings.ToLower(path.Ext(source)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// jsonErrorPosition returns the file and line of a JSON syntax or type error.
+func jsonErrorPosition(source string, data []byte, err error) string {
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	default:
+		return source
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return fmt.Sprintf("%s:%d", source, bytes.Count(data[:offset], []byte("\n"))+1)
+}
+
 func (h *strategyStore) parseStrategies(strategies *strategies) {
 	if strategies == nil {
 		h.logger.Info("No sampling strategies provided or URL is unavailable, using defaults")
 		return
 	}
 	newStore := defaultStrategies()
+	newStore.config = strategies
 	if strategies.DefaultStrategy != nil {
 		newStore.defaultStrategy = h.parseServiceStrategies(strategies.DefaultStrategy)
 	}
 
-	merge := true
-	if newStore.defaultStrategy.OperationSampling == nil ||
-		newStore.defaultStrategy.OperationSampling.PerOperationStrategies == nil {
-		merge = false
-	}
-
 	for _, s := range strategies.ServiceStrategies {
 		newStore.serviceStrategies[s.Service] = h.parseServiceStrategies(s)
+		newStore.mergeWithDefaultStrategy(newStore.serviceStrategies[s.Service])
+	}
 
-		// Merge with the default operation strategies, because only merging with
-		// the default strategy has no effect on service strategies (the default strategy
-		// is not merged with and only used as a fallback).
-		opS := newStore.serviceStrategies[s.Service].OperationSampling
-		if opS == nil {
-			if newStore.defaultStrategy.OperationSampling == nil ||
-				newStore.serviceStrategies[s.Service].ProbabilisticSampling == nil {
-				continue
-			}
-			// Service has no per-operation strategies, so just reference the default settings and change default samplingRate.
-			newOpS := *newStore.defaultStrategy.OperationSampling
-			newOpS.DefaultSamplingProbability = newStore.serviceStrategies[s.Service].ProbabilisticSampling.SamplingRate
-			newStore.serviceStrategies[s.Service].OperationSampling = &newOpS
-			continue
+	newStore.rules = strategies.rules
+	newStore.tagKeys = make(map[string]struct{})
+	for _, r := range newStore.rules {
+		for key := range r.tags {
+			newStore.tagKeys[key] = struct{}{}
 		}
-		if merge {
-			opS.PerOperationStrategies = mergePerOperationSamplingStrategies(
-				opS.PerOperationStrategies,
-				newStore.defaultStrategy.OperationSampling.PerOperationStrategies)
+		if warning := r.lowerBoundWarning(); warning != "" {
+			h.logger.Warn("Sampling rule with rate limited operations", zap.String("rule", r.path), zap.String("warning", warning))
 		}
 	}
 	h.storedStrategies.Store(newStore)
 }
 
+// mergeWithDefaultStrategy merges the strategy of a service with the default operation strategies,
+// because only merging with the default strategy has no effect on service strategies
+// (the default strategy is not merged with and only used as a fallback).
+func (s *storedStrategies) mergeWithDefaultStrategy(strategy *sampling.SamplingStrategyResponse) {
+	defaultOpS := s.defaultStrategy.OperationSampling
+	opS := strategy.OperationSampling
+	if opS == nil {
+		if defaultOpS == nil || strategy.ProbabilisticSampling == nil {
+			return
+		}
+		// Service has no per-operation strategies, so just reference the default settings and change default samplingRate.
+		newOpS := *defaultOpS
+		newOpS.DefaultSamplingProbability = strategy.ProbabilisticSampling.SamplingRate
+		strategy.OperationSampling = &newOpS
+		return
+	}
+	if defaultOpS != nil && defaultOpS.PerOperationStrategies != nil {
+		opS.PerOperationStrategies = mergePerOperationSamplingStrategies(
+			opS.PerOperationStrategies,
+			defaultOpS.PerOperationStrategies)
+	}
+}
+
+// matchRule returns the first rule matching the service and its process tags.
+func (s *storedStrategies) matchRule(service string, tags map[string][]string) *compiledRule {
+	for _, r := range s.rules {
+		if r.matches(service, tags) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ruleStrategy builds the strategy of a service matched by the rule.
+func (s *storedStrategies) ruleStrategy(r *compiledRule, operations []string) *sampling.SamplingStrategyResponse {
+	strategy := r.samplingStrategy(operations)
+	s.mergeWithDefaultStrategy(strategy)
+	return strategy
+}
+
+// explain returns the strategy of a service not matched by any rule, along with the service
+// strategy or default strategy which selected it and the operation strategy that applies to the operation.
+func (s *storedStrategies) explain(service, operation string) (*sampling.SamplingStrategyResponse, *ss.MatchedRule, *ss.MatchedRule) {
+	if strategy, ok := s.serviceStrategies[service]; ok {
+		// A service listed several times gets the strategy of its last entry.
+		i := len(s.config.ServiceStrategies) - 1
+		for s.config.ServiceStrategies[i].Service != service {
+			i--
+		}
+		path := fmt.Sprintf("service_strategies[%d]", i)
+		serviceRule := &ss.MatchedRule{Path: path, Source: s.position("service_strategies", i)}
+		if operation == "" {
+			return strategy, serviceRule, nil
+		}
+		for j, o := range s.config.ServiceStrategies[i].OperationStrategies {
+			if o.Operation == operation && o.Type == samplerTypeProbabilistic {
+				return strategy, serviceRule, &ss.MatchedRule{
+					Path:   fmt.Sprintf("%s.operation_strategies[%d]", path, j),
+					Source: s.position("service_strategies", i, "operation_strategies", j),
+				}
+			}
+		}
+		return strategy, serviceRule, s.explainDefaultOperation(operation)
+	}
+	serviceRule := &ss.MatchedRule{Path: "default_strategy"}
+	if s.config != nil && s.config.DefaultStrategy != nil {
+		serviceRule.Source = s.position("default_strategy")
+	}
+	if operation == "" {
+		return s.defaultStrategy, serviceRule, nil
+	}
+	return s.defaultStrategy, serviceRule, s.explainDefaultOperation(operation)
+}
+
+// explainDefaultOperation returns the operation strategy of the default strategy
+// that applies to the operation, if any.
+func (s *storedStrategies) explainDefaultOperation(operation string) *ss.MatchedRule {
+	if s.config == nil || s.config.DefaultStrategy == nil {
+		return nil
+	}
+	for i, o := range s.config.DefaultStrategy.OperationStrategies {
+		if o.Operation == operation && o.Type == samplerTypeProbabilistic {
+			return &ss.MatchedRule{
+				Path:   fmt.Sprintf("default_strategy.operation_strategies[%d]", i),
+				Source: s.position("default_strategy", "operation_strategies", i),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *storedStrategies) position(path ...interface{}) string {
+	if s.config == nil || s.config.locator == nil {
+		return ""
+	}
+	return s.config.locator.position(path...)
+}
+
 // mergePerOperationStrategies merges two operation strategies a and b, where a takes precedence over b.
 func mergePerOperationSamplingStrategies(
 	a, b []*sampling.OperationSamplingStrategy,
@@ -304,6 +516,15 @@ func (h *strategyStore) parseOperationStrategy(
 }
 
 func (h *strategyStore) parseStrategy(strategy *strategy) *sampling.SamplingStrategyResponse {
+	if resp := newStrategyResponse(strategy); resp != nil {
+		return resp
+	}
+	h.logger.Warn("Failed to parse sampling strategy", zap.Any("strategy", strategy))
+	return defaultStrategyResponse()
+}
+
+// newStrategyResponse returns the response for a strategy, or nil if the strategy type is unknown.
+func newStrategyResponse(strategy *strategy) *sampling.SamplingStrategyResponse {
 	switch strategy.Type {
 	case samplerTypeProbabilistic:
 		return &sampling.SamplingStrategyResponse{
@@ -320,8 +541,7 @@ func (h *strategyStore) parseStrategy(strategy *strategy) *sampling.SamplingStra
 			},
 		}
 	default:
-		h.logger.Warn("Failed to parse sampling strategy", zap.Any("strategy", strategy))
-		return defaultStrategyResponse()
+		return nil
 	}
 }
 
@@ -31,6 +31,8 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	ss "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
+	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/thrift-gen/sampling"
 )
@@ -96,7 +98,7 @@ func TestStrategyStoreWithFile(t *testing.T) {
 
 	_, err = NewStrategyStore(Options{StrategiesFile: "fixtures/bad_strategies.json"}, zap.NewNop())
 	assert.EqualError(t, err,
-		"failed to unmarshal strategies: json: cannot unmarshal string into Go value of type static.strategies")
+		"failed to unmarshal strategies from fixtures/bad_strategies.json:1: json: cannot unmarshal string into Go value of type static.strategies")
 
 	// Test default strategy
 	logger, buf := testutils.NewLogger()
@@ -487,3 +489,177 @@ func TestSamplingStrategyLoader(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "bad-content", string(content))
 }
+
+func TestRules(t *testing.T) {
+	logger, buf := testutils.NewLogger()
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/rules.yaml"}, logger)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "fixtures/rules.yaml:25: rules[0].operation_strategies[1] sets a lower bound of 5 traces per second "+
+		"for every operation of the service, including the operations sampled with a probability")
+	prod := ss.WithProcessTags(context.Background(), map[string]string{"environment": "prod"})
+
+	// without process tags the rule does not match and the service strategy applies
+	s, err := store.GetSamplingStrategy(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.2, s.ProbabilisticSampling.SamplingRate)
+
+	// rules take precedence over service strategies
+	s, err = store.GetSamplingStrategy(prod, "checkout")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.01, s.ProbabilisticSampling.SamplingRate)
+	require.NotNil(t, s.OperationSampling)
+	assert.EqualValues(t, 0.01, s.OperationSampling.DefaultSamplingProbability)
+	assert.EqualValues(t, 5, s.OperationSampling.DefaultLowerBoundTracesPerSecond)
+	assert.Equal(t, []*sampling.OperationSamplingStrategy{
+		{Operation: "/login", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0}},
+		{Operation: "/health", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0}},
+	}, s.OperationSampling.PerOperationStrategies)
+
+	// the process tags and operations reported with the spans are matched against the rules
+	observer := store.(ss.SpanObserver)
+	observer.ObserveSpan(makeSpan("checkout-eu", "GET /cart/items", true, model.String("environment", "prod")))
+	observer.ObserveSpan(makeSpan("checkout-eu", "POST /orders", true, model.String("environment", "prod")))
+	observer.ObserveSpan(makeSpan("checkout-eu", "GET /home", true, model.String("environment", "prod")))
+	observer.ObserveSpan(makeSpan("checkout-eu", "GET /cart/nested", false, model.String("environment", "prod")))
+	s, err = store.GetSamplingStrategy(context.Background(), "checkout-eu")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.01, s.ProbabilisticSampling.SamplingRate)
+	assert.Equal(t, []*sampling.OperationSamplingStrategy{
+		{Operation: "/login", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0}},
+		{Operation: "GET /cart/items", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0.1}},
+		{Operation: "POST /orders", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0}},
+		{Operation: "/health", ProbabilisticSampling: &sampling.ProbabilisticSamplingStrategy{SamplingRate: 0}},
+	}, s.OperationSampling.PerOperationStrategies)
+
+	// a tag pattern matches only if all the values reported by the service match
+	observer.ObserveSpan(makeSpan("checkout-us", "GET /home", true, model.String("environment", "prod")))
+	observer.ObserveSpan(makeSpan("checkout-us", "GET /home", true, model.String("environment", "staging")))
+	s, err = store.GetSamplingStrategy(context.Background(), "checkout-us")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.5, s.ProbabilisticSampling.SamplingRate)
+
+	// rules are evaluated in order
+	s, err = store.GetSamplingStrategy(ss.WithProcessTags(context.Background(), map[string]string{"hostname": "canary-1"}), "web-frontend")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, s.ProbabilisticSampling.SamplingRate)
+	assert.EqualValues(t, 1, s.OperationSampling.DefaultSamplingProbability)
+	s, err = store.GetSamplingStrategy(ss.WithProcessTags(context.Background(), map[string]string{"hostname": "web-1"}), "web-frontend")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_RATE_LIMITING, 10), *s)
+
+	// the default strategy applies when nothing matches
+	s, err = store.GetSamplingStrategy(prod, "cart")
+	require.NoError(t, err)
+	assert.EqualValues(t, 0.5, s.ProbabilisticSampling.SamplingRate)
+}
+
+func TestRulesJSON(t *testing.T) {
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/rules.json"}, zap.NewNop())
+	require.NoError(t, err)
+
+	s, err := store.GetSamplingStrategy(context.Background(), "payments-eu")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_RATE_LIMITING, 20), *s)
+
+	s, err = store.GetSamplingStrategy(ss.WithProcessTags(context.Background(), map[string]string{"environment": "staging"}), "cart")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 1), *s)
+
+	s, err = store.GetSamplingStrategy(context.Background(), "cart")
+	require.NoError(t, err)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.5), *s)
+}
+
+func TestObserveSpanWithoutRules(t *testing.T) {
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/strategies.json"}, zap.NewNop())
+	require.NoError(t, err)
+	store.(ss.SpanObserver).ObserveSpan(makeSpan("foo", "op", true, model.String("environment", "prod")))
+	assert.Empty(t, store.(*strategyStore).observer.services)
+}
+
+func TestDryRun(t *testing.T) {
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/rules.yaml"}, zap.NewNop())
+	require.NoError(t, err)
+	dryRunner := store.(ss.DryRunner)
+
+	result, err := dryRunner.DryRun(context.Background(), ss.DryRunQuery{
+		ServiceName: "checkout",
+		Operation:   "GET /cart/items",
+		ProcessTags: map[string]string{"environment": "prod"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"environment": {"prod"}}, result.ProcessTags)
+	assert.Equal(t, &ss.MatchedRule{Path: "rules[0]", Name: "checkout-prod", Source: "fixtures/rules.yaml:15"}, result.ServiceRule)
+	assert.Equal(t, &ss.MatchedRule{Path: "rules[0].operation_strategies[0]", Source: "fixtures/rules.yaml:22"}, result.OperationRule)
+	assert.Equal(t, "GET /cart/items", result.Strategy.OperationSampling.PerOperationStrategies[1].Operation)
+	assert.EqualValues(t, 0.1, result.Strategy.OperationSampling.PerOperationStrategies[1].ProbabilisticSampling.SamplingRate)
+	assert.EqualValues(t, 5, result.Strategy.OperationSampling.DefaultLowerBoundTracesPerSecond)
+	require.Len(t, result.Warnings, 1, "the lower bound of the rate limited operations applies to GET /cart/items too")
+	assert.Contains(t, result.Warnings[0], "rules[0].operation_strategies[1] sets a lower bound of 5 traces per second")
+
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{
+		ServiceName: "checkout",
+		Operation:   "/health",
+		ProcessTags: map[string]string{"environment": "prod"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "rules[0]", result.ServiceRule.Path)
+	assert.Equal(t, &ss.MatchedRule{Path: "default_strategy.operation_strategies[0]", Source: "fixtures/rules.yaml:5"}, result.OperationRule)
+
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "checkout", Operation: "/health"})
+	require.NoError(t, err)
+	assert.Nil(t, result.ProcessTags)
+	assert.Equal(t, &ss.MatchedRule{Path: "service_strategies[0]", Source: "fixtures/rules.yaml:10"}, result.ServiceRule)
+	assert.Equal(t, "default_strategy.operation_strategies[0]", result.OperationRule.Path)
+	assert.EqualValues(t, 0.2, result.Strategy.ProbabilisticSampling.SamplingRate)
+	assert.Empty(t, result.Warnings)
+
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "checkout", Operation: "GET /"})
+	require.NoError(t, err)
+	assert.Nil(t, result.OperationRule)
+
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "cart"})
+	require.NoError(t, err)
+	assert.Equal(t, &ss.MatchedRule{Path: "default_strategy", Source: "fixtures/rules.yaml:2"}, result.ServiceRule)
+	assert.Nil(t, result.OperationRule)
+
+	// the operation strategies do not apply to a rate limited service without per-operation strategies
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{
+		ServiceName: "web-frontend",
+		Operation:   "/health",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "rules[2]", result.ServiceRule.Path)
+	assert.Nil(t, result.OperationRule)
+}
+
+func TestDryRunWithoutRules(t *testing.T) {
+	store, err := NewStrategyStore(Options{StrategiesFile: "fixtures/operation_strategies.json"}, zap.NewNop())
+	require.NoError(t, err)
+	dryRunner := store.(ss.DryRunner)
+
+	result, err := dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "foo", Operation: "op1"})
+	require.NoError(t, err)
+	assert.Equal(t, &ss.MatchedRule{Path: "service_strategies[0]", Source: "fixtures/operation_strategies.json:29"}, result.ServiceRule)
+	assert.Equal(t, &ss.MatchedRule{
+		Path:   "service_strategies[0].operation_strategies[1]",
+		Source: "fixtures/operation_strategies.json:39",
+	}, result.OperationRule)
+
+	// rate limited operation strategies are ignored
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "foo", Operation: "op2"})
+	require.NoError(t, err)
+	assert.Nil(t, result.OperationRule)
+
+	result, err = dryRunner.DryRun(context.Background(), ss.DryRunQuery{ServiceName: "foo", Operation: "op7"})
+	require.NoError(t, err)
+	assert.Equal(t, "default_strategy.operation_strategies[3]", result.OperationRule.Path)
+
+	store, err = NewStrategyStore(Options{}, zap.NewNop())
+	require.NoError(t, err)
+	result, err = store.(ss.DryRunner).DryRun(context.Background(), ss.DryRunQuery{ServiceName: "foo", Operation: "op"})
+	require.NoError(t, err)
+	assert.Equal(t, &ss.MatchedRule{Path: "default_strategy"}, result.ServiceRule)
+	assert.Nil(t, result.OperationRule)
+	assert.EqualValues(t, makeResponse(sampling.SamplingStrategyType_PROBABILISTIC, 0.001), *result.Strategy)
+}
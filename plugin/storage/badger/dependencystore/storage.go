@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -47,7 +48,7 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 	// dependencyKeyPrefix + timestamp + parent + child key and do a key-only seek (which is fast - but requires additional writes)
 
 	// GetDependencies is not shipped with a context like the SpanReader / SpanWriter
-	traces, err := s.reader.FindTraces(context.Background(), params)
+	traces, err := s.reader.FindTraces(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +59,24 @@ func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time,
 	return depMapToSlice(deps), err
 }
 
+// GetOperationDependencies returns all dependencies between operations of different services,
+// implements dependencystore.OperationReader
+func (s *DependencyStore) GetOperationDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.OperationDependencyLink, error) {
+	params := &spanstore.TraceQueryParameters{
+		StartTimeMin: endTs.Add(-1 * lookback),
+		StartTimeMax: endTs,
+	}
+	traces, err := s.reader.FindTraces(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	links := dependencystore.NewOperationLinks()
+	for _, tr := range traces {
+		links.AddTrace(tr)
+	}
+	return links.Links(), nil
+}
+
 // depMapToSlice modifies the spans to DependencyLink in the same way as the memory storage plugin
 func depMapToSlice(deps map[string]*model.DependencyLink) []model.DependencyLink {
 	retMe := make([]model.DependencyLink, 0, len(deps))
@@ -95,5 +95,17 @@ func TestDependencyReader(t *testing.T) {
 		assert.NotEmpty(t, links)
 		assert.Equal(t, spans-1, len(links))                // First span does not create a dependency
 		assert.Equal(t, uint64(traces), links[0].CallCount) // Each trace calls the same services
+
+		opLinks, err := dr.(dependencystore.OperationReader).GetOperationDependencies(context.Background(), time.Now(), time.Hour)
+		assert.NoError(t, err)
+		require.Len(t, opLinks, spans-1)
+		assert.Equal(t, model.OperationDependencyLink{
+			Parent:          "service-0",
+			ParentOperation: "operation-a",
+			Child:           "service-1",
+			ChildOperation:  "operation-a",
+			CallCount:       uint64(traces),
+			Latency:         model.LatencySummary{Min: 1, Max: time.Duration(traces), Total: time.Duration(traces * (traces + 1) / 2)},
+		}, opLinks[0])
 	})
 }
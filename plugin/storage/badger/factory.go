@@ -27,9 +27,16 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/hostname"
+	"github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/local"
 	depStore "github.com/jaegertracing/jaeger/plugin/storage/badger/dependencystore"
+	badgerSamplingStore "github.com/jaegertracing/jaeger/plugin/storage/badger/samplingstore"
+	badgerSpanMetricsStore "github.com/jaegertracing/jaeger/plugin/storage/badger/spanmetricsstore"
 	badgerStore "github.com/jaegertracing/jaeger/plugin/storage/badger/spanstore"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -164,6 +171,37 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return depStore.NewDependencyStore(sr), nil
 }
 
+// ValidateTenantIsolation implements storage.TenantIsolationFactory, the keys of the spans and
+// of the indices are prefixed by the tenant.
+func (f *Factory) ValidateTenantIsolation() error {
+	return nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	return badgerSamplingStore.NewSamplingStore(f.store, f.Options.Primary.SpanStoreTTL), nil
+}
+
+// CreateSpanMetricsWriter implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsWriter() (metricsstore.SpanMetricsWriter, error) {
+	return badgerSpanMetricsStore.NewSpanMetricsStore(f.store, f.Options.Primary.SpanStoreTTL), nil
+}
+
+// CreateSpanMetricsReader implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsReader() (metricsstore.SpanMetricsReader, error) {
+	return badgerSpanMetricsStore.NewSpanMetricsStore(f.store, f.Options.Primary.SpanStoreTTL), nil
+}
+
+// CreateLock implements storage.SamplingStoreFactory. Badger cannot be shared between processes,
+// so the lock only needs to be held within this one.
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := hostname.AsIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return local.NewLock(hostname), nil
+}
+
 // Close Implements io.Closer and closes the underlying storage
 func (f *Factory) Close() error {
 	close(f.maintenanceDone)
@@ -0,0 +1,205 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+/*
+	The keys are made of a prefix byte followed by the insertion time in nanoseconds, written in BigEndian
+	order so that the keys are sorted by time. The prefixes are outside of the range of the span keys
+	(0x80-0x8F) and of the tenant prefixes (ASCII), see the spanstore package.
+
+	KEY: 0x90<timestamp> VALUE: JSON of the throughput
+	KEY: 0x91<timestamp><hostname> VALUE: JSON of the probabilities and QPS
+*/
+
+const (
+	throughputKeyPrefix    byte = 0x90
+	probabilitiesKeyPrefix byte = 0x91
+	timestampLength             = 8
+)
+
+type probabilitiesAndQPS struct {
+	Hostname      string                              `json:"hostname"`
+	Probabilities model.ServiceOperationProbabilities `json:"probabilities"`
+	QPS           model.ServiceOperationQPS           `json:"qps"`
+}
+
+// SamplingStore stores the data used by adaptive sampling in Badger
+type SamplingStore struct {
+	store *badger.DB
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewSamplingStore creates a SamplingStore which expires the data after the given ttl
+func NewSamplingStore(db *badger.DB, ttl time.Duration) *SamplingStore {
+	return &SamplingStore{
+		store: db,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// InsertThroughput implements samplingstore.Store
+func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	value, err := json.Marshal(throughput)
+	if err != nil {
+		return err
+	}
+	return s.insert(createKey(throughputKeyPrefix, s.now(), ""), value)
+}
+
+// InsertProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	value, err := json.Marshal(probabilitiesAndQPS{
+		Hostname:      hostname,
+		Probabilities: probabilities,
+		QPS:           qps,
+	})
+	if err != nil {
+		return err
+	}
+	return s.insert(createKey(probabilitiesKeyPrefix, s.now(), hostname), value)
+}
+
+// GetThroughput implements samplingstore.Store
+func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	var throughput []*model.Throughput
+	err := s.scan(throughputKeyPrefix, start, end, func(value []byte) error {
+		var t []*model.Throughput
+		if err := json.Unmarshal(value, &t); err != nil {
+			return err
+		}
+		throughput = append(throughput, t...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading throughput from storage: %w", err)
+	}
+	return throughput, nil
+}
+
+// GetProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	hostProbabilitiesAndQPS := make(map[string][]model.ServiceOperationData)
+	err := s.scan(probabilitiesKeyPrefix, start, end, func(value []byte) error {
+		var p probabilitiesAndQPS
+		if err := json.Unmarshal(value, &p); err != nil {
+			return err
+		}
+		hostProbabilitiesAndQPS[p.Hostname] = append(hostProbabilitiesAndQPS[p.Hostname], p.data())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading probabilities and qps from storage: %w", err)
+	}
+	return hostProbabilitiesAndQPS, nil
+}
+
+// GetLatestProbabilities implements samplingstore.Store
+func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	var p probabilitiesAndQPS
+	err := s.store.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefix := []byte{probabilitiesKeyPrefix}
+		// in reverse mode, Seek finds the greatest key lower than the next prefix
+		it.Seek([]byte{probabilitiesKeyPrefix + 1})
+		if !it.ValidForPrefix(prefix) {
+			return nil
+		}
+		return it.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, &p)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading probabilities from storage: %w", err)
+	}
+	if p.Probabilities == nil {
+		return model.ServiceOperationProbabilities{}, nil
+	}
+	return p.Probabilities, nil
+}
+
+func (s *SamplingStore) insert(key, value []byte) error {
+	return s.store.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry(key, value).WithTTL(s.ttl))
+	})
+}
+
+// scan calls fn with the values of the keys of the given prefix inserted after start and up to end
+func (s *SamplingStore) scan(prefix byte, start, end time.Time, fn func(value []byte) error) error {
+	return s.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		keyPrefix := []byte{prefix}
+		seekKey := keyPrefix
+		if start.UnixNano() >= 0 {
+			seekKey = createKey(prefix, start.Add(time.Nanosecond), "")
+		}
+		endTs := uint64(end.UnixNano())
+		for it.Seek(seekKey); it.ValidForPrefix(keyPrefix); it.Next() {
+			key := it.Item().Key()
+			if binary.BigEndian.Uint64(key[1:1+timestampLength]) > endTs {
+				return nil
+			}
+			if err := it.Item().Value(fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// data returns the probabilities and QPS per operation, the QPS defaults to 0 for the operations without one
+func (p probabilitiesAndQPS) data() model.ServiceOperationData {
+	data := make(model.ServiceOperationData)
+	for svc, opProbabilities := range p.Probabilities {
+		data[svc] = make(map[string]*model.ProbabilityAndQPS)
+		for op, probability := range opProbabilities {
+			data[svc][op] = &model.ProbabilityAndQPS{
+				Probability: probability,
+				QPS:         p.QPS[svc][op],
+			}
+		}
+	}
+	return data
+}
+
+func createKey(prefix byte, ts time.Time, suffix string) []byte {
+	key := make([]byte, 1+timestampLength+len(suffix))
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:], uint64(ts.UnixNano()))
+	copy(key[1+timestampLength:], suffix)
+	return key
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+func withSamplingStore(t *testing.T, fn func(s *SamplingStore)) {
+	dir, err := ioutil.TempDir("", "badger-sampling")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fn(NewSamplingStore(db, time.Hour))
+}
+
+func TestThroughput(t *testing.T) {
+	withSamplingStore(t, func(s *SamplingStore) {
+		now := time.Unix(1000, 0)
+		s.now = func() time.Time { return now }
+
+		first := []*model.Throughput{{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}}}
+		require.NoError(t, s.InsertThroughput(first))
+		now = now.Add(time.Minute)
+		second := []*model.Throughput{{Service: "svc", Operation: "op", Count: 20, Probabilities: map[string]struct{}{}}}
+		require.NoError(t, s.InsertThroughput(second))
+
+		throughput, err := s.GetThroughput(time.Unix(0, 0), now)
+		require.NoError(t, err)
+		assert.Equal(t, append(first, second...), throughput)
+
+		throughput, err = s.GetThroughput(now.Add(-time.Minute), now)
+		require.NoError(t, err)
+		assert.Equal(t, second, throughput, "start is exclusive")
+
+		throughput, err = s.GetThroughput(time.Time{}, now.Add(-time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, first, throughput, "end is inclusive")
+	})
+}
+
+func TestProbabilitiesAndQPS(t *testing.T) {
+	withSamplingStore(t, func(s *SamplingStore) {
+		latest, err := s.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Empty(t, latest)
+
+		now := time.Unix(1000, 0)
+		s.now = func() time.Time { return now }
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-a",
+			model.ServiceOperationProbabilities{"svc": {"op": 0.1}},
+			model.ServiceOperationQPS{"svc": {"op": 5}},
+		))
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-b",
+			model.ServiceOperationProbabilities{"svc": {"op": 0.2}},
+			model.ServiceOperationQPS{},
+		))
+		now = now.Add(time.Minute)
+		require.NoError(t, s.InsertProbabilitiesAndQPS("host-a",
+			model.ServiceOperationProbabilities{"svc": {"op": 0.3}},
+			model.ServiceOperationQPS{"svc": {"op": 7}},
+		))
+
+		data, err := s.GetProbabilitiesAndQPS(now.Add(-2*time.Minute), now)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]model.ServiceOperationData{
+			"host-a": {
+				{"svc": {"op": {Probability: 0.1, QPS: 5}}},
+				{"svc": {"op": {Probability: 0.3, QPS: 7}}},
+			},
+			"host-b": {
+				{"svc": {"op": {Probability: 0.2, QPS: 0}}},
+			},
+		}, data)
+
+		latest, err = s.GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Equal(t, model.ServiceOperationProbabilities{"svc": {"op": 0.3}}, latest)
+	})
+}
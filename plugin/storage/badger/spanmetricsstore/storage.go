@@ -0,0 +1,153 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+/*
+	The keys are made of a prefix byte followed by the start of the time bucket in nanoseconds, written
+	in BigEndian order so that the keys are sorted by time, and by the fields identifying the metrics.
+	The prefix is outside of the range of the span keys (0x80-0x8F) and of the sampling keys (0x90-0x91).
+	As for the spans, the keys of a tenant other than the default one are prefixed with <tenant>0x00.
+
+	KEY: 0x92<timestamp><source>0x00<serviceName>0x00<operationName>0x00<spanKind> VALUE: JSON of the metrics
+*/
+
+const (
+	spanMetricsKeyPrefix byte = 0x92
+	timestampLength           = 8
+	keySeparator         byte = 0x00
+)
+
+// SpanMetricsStore stores the span metrics aggregated by the collector in Badger
+type SpanMetricsStore struct {
+	store *badger.DB
+	ttl   time.Duration
+}
+
+// NewSpanMetricsStore creates a SpanMetricsStore which expires the metrics after the given ttl
+func NewSpanMetricsStore(db *badger.DB, ttl time.Duration) *SpanMetricsStore {
+	return &SpanMetricsStore{
+		store: db,
+		ttl:   ttl,
+	}
+}
+
+// WriteSpanMetrics implements metricsstore.SpanMetricsWriter
+func (s *SpanMetricsStore) WriteSpanMetrics(ctx context.Context, spanMetrics []*metricsstore.SpanMetrics) error {
+	tenantPrefix := tenantKeyPrefix(tenancy.GetTenant(ctx))
+	entries := make([]*badger.Entry, 0, len(spanMetrics))
+	for _, m := range spanMetrics {
+		value, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, badger.NewEntry(createKey(tenantPrefix, m), value).WithTTL(s.ttl))
+	}
+	return s.store.Update(func(txn *badger.Txn) error {
+		for _, entry := range entries {
+			if err := txn.SetEntry(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetSpanMetrics implements metricsstore.SpanMetricsReader
+func (s *SpanMetricsStore) GetSpanMetrics(ctx context.Context, query *metricsstore.SpanMetricsQuery) ([]*metricsstore.SpanMetrics, error) {
+	keyPrefix := append(tenantKeyPrefix(tenancy.GetTenant(ctx)), spanMetricsKeyPrefix)
+	var result []*metricsstore.SpanMetrics
+	err := s.store.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		seekKey := keyPrefix
+		if query.StartTime.UnixNano() >= 0 {
+			seekKey = appendTimestamp(append([]byte(nil), keyPrefix...), query.StartTime)
+		}
+		endTs := uint64(query.EndTime.UnixNano())
+		for it.Seek(seekKey); it.ValidForPrefix(keyPrefix); it.Next() {
+			key := it.Item().Key()
+			if len(key) < len(keyPrefix)+timestampLength {
+				continue
+			}
+			if binary.BigEndian.Uint64(key[len(keyPrefix):]) >= endTs {
+				return nil
+			}
+			var m metricsstore.SpanMetrics
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &m)
+			}); err != nil {
+				return err
+			}
+			if query.Matches(&m) {
+				result = append(result, &m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading span metrics from storage: %w", err)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+func createKey(tenantPrefix []byte, m *metricsstore.SpanMetrics) []byte {
+	var key bytes.Buffer
+	key.Write(tenantPrefix)
+	key.WriteByte(spanMetricsKeyPrefix)
+	key.Write(appendTimestamp(nil, m.Timestamp))
+	for i, field := range []string{m.Source, m.ServiceName, m.OperationName, m.SpanKind} {
+		if i > 0 {
+			key.WriteByte(keySeparator)
+		}
+		key.WriteString(field)
+	}
+	return key.Bytes()
+}
+
+func appendTimestamp(key []byte, ts time.Time) []byte {
+	var b [timestampLength]byte
+	binary.BigEndian.PutUint64(b[:], uint64(ts.UnixNano()))
+	return append(key, b[:]...)
+}
+
+// tenantKeyPrefix returns the prefix of the keys of the given tenant, nil for the default tenant,
+// the same as the prefix of the span keys.
+func tenantKeyPrefix(tenant string) []byte {
+	if tenant == "" {
+		return nil
+	}
+	prefix := make([]byte, len(tenant)+1)
+	copy(prefix, tenant)
+	return prefix
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+func withSpanMetricsStore(t *testing.T, fn func(s *SpanMetricsStore)) {
+	dir, err := ioutil.TempDir("", "badger-span-metrics")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fn(NewSpanMetricsStore(db, time.Hour))
+}
+
+func makeSpanMetrics(ts time.Time, service string, calls int64) *metricsstore.SpanMetrics {
+	return &metricsstore.SpanMetrics{
+		ServiceName:   service,
+		OperationName: "GET",
+		SpanKind:      "SPAN_KIND_SERVER",
+		Source:        "collector-1",
+		Timestamp:     ts,
+		Calls:         calls,
+		Errors:        1,
+		LatencyBounds: []time.Duration{time.Millisecond},
+		LatencyCounts: []int64{calls - 1, 1},
+	}
+}
+
+func TestWriteAndGetSpanMetrics(t *testing.T) {
+	withSpanMetricsStore(t, func(s *SpanMetricsStore) {
+		ctx := context.Background()
+		start := time.Unix(1000, 0).UTC()
+		first := makeSpanMetrics(start, "frontend", 2)
+		second := makeSpanMetrics(start.Add(time.Minute), "frontend", 3)
+		other := makeSpanMetrics(start, "backend", 4)
+		require.NoError(t, s.WriteSpanMetrics(ctx, []*metricsstore.SpanMetrics{second, first, other}))
+
+		metrics, err := s.GetSpanMetrics(ctx, &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(2*time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, metrics, 3)
+		assert.ElementsMatch(t, []*metricsstore.SpanMetrics{first, other}, metrics[:2])
+		assert.Equal(t, second, metrics[2])
+
+		metrics, err = s.GetSpanMetrics(ctx, &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.Len(t, metrics, 2, "end is exclusive")
+
+		// writing the metrics of the same bucket again replaces them
+		updated := makeSpanMetrics(start, "frontend", 5)
+		require.NoError(t, s.WriteSpanMetrics(ctx, []*metricsstore.SpanMetrics{updated}))
+		metrics, err = s.GetSpanMetrics(ctx, &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(time.Second)})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*metricsstore.SpanMetrics{updated, other}, metrics)
+	})
+}
+
+func TestSpanMetricsTenantIsolation(t *testing.T) {
+	withSpanMetricsStore(t, func(s *SpanMetricsStore) {
+		start := time.Unix(1000, 0).UTC()
+		acme := tenancy.WithTenant(context.Background(), "acme")
+		require.NoError(t, s.WriteSpanMetrics(acme, []*metricsstore.SpanMetrics{makeSpanMetrics(start, "frontend", 2)}))
+
+		metrics, err := s.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.Empty(t, metrics)
+
+		metrics, err = s.GetSpanMetrics(tenancy.WithTenant(context.Background(), "other"), &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.Empty(t, metrics)
+
+		metrics, err = s.GetSpanMetrics(acme, &metricsstore.SpanMetricsQuery{StartTime: start, EndTime: start.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.Len(t, metrics, 1)
+	})
+}
@@ -33,6 +33,13 @@ type CacheStore struct {
 
 	store *badger.DB
 	ttl   time.Duration
+
+	// prefix is the key prefix of the tenant of this cache, see tenantKeyPrefix
+	prefix  []byte
+	prefill bool
+	// tenants holds the caches of the other tenants, created on first use
+	tenantsLock sync.Mutex
+	tenants     map[string]*CacheStore
 }
 
 // NewCacheStore returns initialized CacheStore for badger use
@@ -42,6 +49,8 @@ func NewCacheStore(db *badger.DB, ttl time.Duration, prefill bool) *CacheStore {
 		operations: make(map[string]map[string]uint64),
 		ttl:        ttl,
 		store:      db,
+		prefill:    prefill,
+		tenants:    make(map[string]*CacheStore),
 	}
 
 	if prefill {
@@ -50,6 +59,30 @@ func NewCacheStore(db *badger.DB, ttl time.Duration, prefill bool) *CacheStore {
 	return cs
 }
 
+// forTenant returns the cache of the given tenant, the empty tenant being the default one
+func (c *CacheStore) forTenant(tenant string) *CacheStore {
+	if tenant == "" {
+		return c
+	}
+	c.tenantsLock.Lock()
+	defer c.tenantsLock.Unlock()
+	if tc, ok := c.tenants[tenant]; ok {
+		return tc
+	}
+	tc := &CacheStore{
+		services:   make(map[string]uint64),
+		operations: make(map[string]map[string]uint64),
+		ttl:        c.ttl,
+		store:      c.store,
+		prefix:     tenantKeyPrefix(tenant),
+	}
+	if c.prefill {
+		tc.populateCaches()
+	}
+	c.tenants[tenant] = tc
+	return tc
+}
+
 func (c *CacheStore) populateCaches() {
 	c.cacheLock.Lock()
 	defer c.cacheLock.Unlock()
@@ -67,7 +100,7 @@ func (c *CacheStore) loadServices() {
 		it := txn.NewIterator(opts)
 		defer it.Close()
 
-		serviceKey := []byte{serviceNameIndexKey}
+		serviceKey := prefixKey(c.prefix, []byte{serviceNameIndexKey})
 
 		// Seek all the services first
 		for it.Seek(serviceKey); it.ValidForPrefix(serviceKey); it.Next() {
@@ -94,6 +127,7 @@ func (c *CacheStore) loadOperations(service string) {
 		serviceKey := make([]byte, len(service)+1)
 		serviceKey[0] = operationNameIndexKey
 		copy(serviceKey[1:], service)
+		serviceKey = prefixKey(c.prefix, serviceKey)
 
 		// Seek all the services first
 		for it.Seek(serviceKey); it.ValidForPrefix(serviceKey); it.Next() {
@@ -32,8 +32,10 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/badger"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 func TestWriteReadBack(t *testing.T) {
@@ -90,6 +92,65 @@ func TestWriteReadBack(t *testing.T) {
 	})
 }
 
+func TestTenantIsolation(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		tid := time.Now()
+		ctxAcme := tenancy.WithTenant(context.Background(), "acme")
+		ctxOther := tenancy.WithTenant(context.Background(), "other")
+		span := &model.Span{
+			TraceID:       model.TraceID{Low: 1, High: 1},
+			SpanID:        model.SpanID(1),
+			OperationName: "operation",
+			Process: &model.Process{
+				ServiceName: "service",
+			},
+			StartTime: tid,
+			Duration:  time.Second,
+			Tags:      model.KeyValues{model.String("key", "value")},
+		}
+		assert.NoError(t, sw.WriteSpan(ctxAcme, span))
+
+		queries := []*spanstore.TraceQueryParameters{
+			{StartTimeMin: tid.Add(-time.Minute), StartTimeMax: tid.Add(time.Minute)},
+			{ServiceName: "service", StartTimeMin: tid.Add(-time.Minute), StartTimeMax: tid.Add(time.Minute)},
+			{ServiceName: "service", OperationName: "operation", StartTimeMin: tid.Add(-time.Minute), StartTimeMax: tid.Add(time.Minute)},
+			{ServiceName: "service", Tags: map[string]string{"key": "value"}, StartTimeMin: tid.Add(-time.Minute), StartTimeMax: tid.Add(time.Minute)},
+			{ServiceName: "service", DurationMin: time.Millisecond, StartTimeMin: tid.Add(-time.Minute), StartTimeMax: tid.Add(time.Minute)},
+		}
+
+		trace, err := sr.GetTrace(ctxAcme, span.TraceID)
+		require.NoError(t, err)
+		assert.Len(t, trace.Spans, 1)
+		services, err := sr.GetServices(ctxAcme)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"service"}, services)
+		operations, err := sr.GetOperations(ctxAcme, spanstore.OperationQueryParameters{ServiceName: "service"})
+		require.NoError(t, err)
+		assert.Len(t, operations, 1)
+		for _, query := range queries {
+			traces, err := sr.FindTraces(ctxAcme, query)
+			require.NoError(t, err)
+			assert.Len(t, traces, 1)
+		}
+
+		for _, ctx := range []context.Context{ctxOther, context.Background()} {
+			_, err := sr.GetTrace(ctx, span.TraceID)
+			assert.Equal(t, spanstore.ErrTraceNotFound, err)
+			services, err := sr.GetServices(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, services)
+			operations, err := sr.GetOperations(ctx, spanstore.OperationQueryParameters{ServiceName: "service"})
+			require.NoError(t, err)
+			assert.Empty(t, operations)
+			for _, query := range queries {
+				traces, err := sr.FindTraces(ctx, query)
+				require.NoError(t, err)
+				assert.Empty(t, traces)
+			}
+		}
+	})
+}
+
 func TestValidation(t *testing.T) {
 	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
 		tid := time.Now()
@@ -308,6 +369,195 @@ func TestFindNothing(t *testing.T) {
 	})
 }
 
+func TestFindTracesByQuery(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		startT := time.Now()
+		traces := 30
+		for i := 0; i < traces; i++ {
+			traceID := model.TraceID{High: 1, Low: uint64(i)}
+			root := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(1),
+				OperationName: "GET",
+				Process:       &model.Process{ServiceName: "frontend"},
+				StartTime:     startT.Add(time.Duration(i) * time.Millisecond),
+				Duration:      time.Duration(i) * time.Millisecond,
+			}
+			child := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(2),
+				OperationName: "query",
+				Process:       &model.Process{ServiceName: fmt.Sprintf("backend-%d", i%3)},
+				StartTime:     root.StartTime,
+				Duration:      time.Millisecond,
+				Tags:          []model.KeyValue{model.Bool("error", i%5 == 0)},
+			}
+			require.NoError(t, sw.WriteSpan(context.Background(), root))
+			require.NoError(t, sw.WriteSpan(context.Background(), child))
+		}
+
+		tests := []struct {
+			query     string
+			numTraces int
+			expected  int
+		}{
+			{query: "service=frontend", expected: traces},
+			{query: "service=frontend", numTraces: 4, expected: 4},
+			{query: "service=backend-1", expected: 10},
+			{query: "tag.error=true", expected: 6},
+			{query: "service=frontend AND tag.error=true", expected: 6},
+			{query: "{service=frontend AND tag.error=true}", expected: 0},
+			{query: "{service=backend-0 AND tag.error=true}", expected: 2},
+			{query: "service=backend-0 AND NOT tag.error=true", expected: 8},
+			{query: "{service=frontend AND duration>=20ms} OR service=backend-2", expected: 16},
+			{query: "{service=frontend AND duration>=20ms} OR service=backend-2", numTraces: 5, expected: 5},
+		}
+		for _, test := range tests {
+			q, err := querylang.Parse(test.query)
+			require.NoError(t, err)
+			params := &spanstore.TraceQueryParameters{
+				StartTimeMin: startT,
+				StartTimeMax: startT.Add(time.Second),
+				NumTraces:    test.numTraces,
+				Query:        q,
+			}
+			found, err := sr.FindTraces(context.Background(), params)
+			require.NoError(t, err)
+			assert.Len(t, found, test.expected, test.query)
+			for _, trace := range found {
+				assert.True(t, q.Matches(trace), test.query)
+			}
+
+			ids, err := sr.FindTraceIDs(context.Background(), params)
+			require.NoError(t, err)
+			assert.Len(t, ids, test.expected, test.query)
+		}
+	})
+}
+
+func TestFindSpans(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		startT := time.Now()
+		for i := 0; i < 10; i++ {
+			traceID := model.TraceID{High: 1, Low: uint64(i)}
+			root := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(1),
+				OperationName: "GET",
+				Process:       &model.Process{ServiceName: "frontend"},
+				StartTime:     startT.Add(time.Duration(i) * time.Millisecond),
+				Duration:      time.Duration(10-i) * time.Millisecond,
+			}
+			child := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(2),
+				OperationName: "query",
+				Process:       &model.Process{ServiceName: "backend"},
+				StartTime:     root.StartTime,
+				Duration:      time.Millisecond,
+				Tags:          []model.KeyValue{model.Bool("error", i%5 == 0)},
+			}
+			require.NoError(t, sw.WriteSpan(context.Background(), root))
+			require.NoError(t, sw.WriteSpan(context.Background(), child))
+		}
+		finder, ok := sr.(spanstore.SpanFinder)
+		require.True(t, ok)
+
+		params := &spanstore.SpanQueryParameters{
+			ServiceName:  "frontend",
+			StartTimeMin: startT,
+			StartTimeMax: startT.Add(time.Second),
+			NumSpans:     3,
+			SortBy:       spanstore.SortByDuration,
+		}
+		spans, err := finder.FindSpans(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, spans, 3)
+		for i, span := range spans {
+			assert.Equal(t, "GET", span.OperationName)
+			assert.Equal(t, model.TraceID{High: 1, Low: uint64(i)}, span.TraceID)
+		}
+
+		params = &spanstore.SpanQueryParameters{
+			ServiceName:  "backend",
+			Tags:         map[string]string{"error": "true"},
+			StartTimeMin: startT,
+			StartTimeMax: startT.Add(time.Second),
+			Ascending:    true,
+		}
+		spans, err = finder.FindSpans(context.Background(), params)
+		require.NoError(t, err)
+		require.Len(t, spans, 2)
+		assert.Equal(t, model.TraceID{High: 1, Low: 0}, spans[0].TraceID)
+		assert.Equal(t, model.TraceID{High: 1, Low: 5}, spans[1].TraceID)
+
+		_, err = finder.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "frontend"})
+		assert.EqualError(t, err, "start and end time must be set")
+		_, err = finder.FindSpans(context.Background(), nil)
+		assert.EqualError(t, err, "malformed request object")
+	})
+}
+
+func TestGetSpanStats(t *testing.T) {
+	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
+		startT := time.Now()
+		for i := 0; i < 10; i++ {
+			traceID := model.TraceID{High: 1, Low: uint64(i)}
+			root := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(1),
+				OperationName: "GET",
+				Process:       &model.Process{ServiceName: "frontend"},
+				StartTime:     startT.Add(time.Duration(i) * time.Millisecond),
+				Duration:      time.Duration(i+1) * time.Millisecond,
+				Tags:          []model.KeyValue{model.Bool("error", i%5 == 0)},
+			}
+			child := &model.Span{
+				TraceID:       traceID,
+				SpanID:        model.SpanID(2),
+				OperationName: "query",
+				Process:       &model.Process{ServiceName: "backend"},
+				StartTime:     root.StartTime,
+				Duration:      time.Millisecond,
+			}
+			require.NoError(t, sw.WriteSpan(context.Background(), root))
+			require.NoError(t, sw.WriteSpan(context.Background(), child))
+		}
+		statsReader, ok := sr.(spanstore.SpanStatsReader)
+		require.True(t, ok)
+
+		stats, err := statsReader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			ServiceName:  "frontend",
+			StartTimeMin: startT,
+			StartTimeMax: startT.Add(time.Second),
+			Buckets:      []time.Duration{5 * time.Millisecond},
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.EqualValues(t, 10, stats[0].Count)
+		assert.EqualValues(t, 2, stats[0].ErrorCount)
+		assert.Equal(t, 5*time.Millisecond, stats[0].P50)
+		assert.Equal(t, 9*time.Millisecond, stats[0].P90)
+		assert.Equal(t, 10*time.Millisecond, stats[0].P99)
+		assert.EqualValues(t, 4, stats[0].Histogram[0].Count)
+		assert.EqualValues(t, 6, stats[0].Histogram[1].Count)
+
+		stats, err = statsReader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			StartTimeMin: startT,
+			StartTimeMax: startT.Add(time.Second),
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "backend", stats[0].ServiceName)
+		assert.Equal(t, "frontend", stats[1].ServiceName)
+
+		_, err = statsReader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{})
+		assert.EqualError(t, err, "start and end time must be set")
+		_, err = statsReader.GetSpanStats(context.Background(), nil)
+		assert.EqualError(t, err, "malformed request object")
+	})
+}
+
 func TestWriteDuplicates(t *testing.T) {
 	runFactoryTest(t, func(tb testing.TB, sw spanstore.Writer, sr spanstore.Reader) {
 		tid := time.Now()
@@ -27,6 +27,7 @@ import (
 	"github.com/dgraph-io/badger/v3"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -60,6 +61,8 @@ var (
 
 const (
 	defaultNumTraces = 100
+	// queryBatchSize is the number of traces loaded at once to evaluate a structured query
+	queryBatchSize   = 100
 	sizeOfTraceID    = 16
 	encodingTypeBits = 0x0F
 )
@@ -68,6 +71,8 @@ const (
 type TraceReader struct {
 	store *badger.DB
 	cache *CacheStore
+	// prefix is the key prefix of the tenant of this reader, see tenantKeyPrefix
+	prefix []byte
 }
 
 // executionPlan is internal structure to track the index filtering
@@ -92,6 +97,20 @@ func NewTraceReader(db *badger.DB, c *CacheStore) *TraceReader {
 	}
 }
 
+// forTenant returns a reader of the keys of the tenant carried by the context
+func (r *TraceReader) forTenant(ctx context.Context) *TraceReader {
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" || r.prefix != nil {
+		// default tenant, or already scoped to the tenant
+		return r
+	}
+	return &TraceReader{
+		store:  r.store,
+		cache:  r.cache.forTenant(tenant),
+		prefix: tenantKeyPrefix(tenant),
+	}
+}
+
 func decodeValue(val []byte, encodeType byte) (*model.Span, error) {
 	sp := model.Span{}
 	switch encodeType {
@@ -116,7 +135,7 @@ func (r *TraceReader) getTraces(traceIDs []model.TraceID) ([]*model.Trace, error
 	prefixes := make([][]byte, 0, len(traceIDs))
 
 	for _, traceID := range traceIDs {
-		prefixes = append(prefixes, createPrimaryKeySeekPrefix(traceID))
+		prefixes = append(prefixes, prefixKey(r.prefix, createPrimaryKeySeekPrefix(traceID)))
 	}
 
 	err := r.store.View(func(txn *badger.Txn) error {
@@ -158,6 +177,7 @@ func (r *TraceReader) getTraces(traceIDs []model.TraceID) ([]*model.Trace, error
 
 // GetTrace takes a traceID and returns a Trace associated with that traceID
 func (r *TraceReader) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
+	r = r.forTenant(ctx)
 	traces, err := r.getTraces([]model.TraceID{traceID})
 	if err != nil {
 		return nil, err
@@ -182,13 +202,13 @@ func (r *TraceReader) scanTimeRange(plan *executionPlan) ([]model.TraceID, error
 		it := txn.NewIterator(opts)
 		defer it.Close()
 
-		startIndex := []byte{spanKeyPrefix}
+		startIndex := prefixKey(r.prefix, []byte{spanKeyPrefix})
 		prevTraceID := []byte{}
 		for it.Seek(startIndex); it.ValidForPrefix(startIndex); it.Next() {
 			item := it.Item()
 
 			key := []byte{}
-			key = item.KeyCopy(key)
+			key = item.KeyCopy(key)[len(r.prefix):]
 
 			timestamp := key[sizeOfTraceID+1 : sizeOfTraceID+1+8]
 			traceID := key[1 : sizeOfTraceID+1]
@@ -242,6 +262,7 @@ func createPrimaryKeySeekPrefix(traceID model.TraceID) []byte {
 
 // GetServices fetches the sorted service list that have not expired
 func (r *TraceReader) GetServices(ctx context.Context) ([]string, error) {
+	r = r.forTenant(ctx)
 	return r.cache.GetServices()
 }
 
@@ -250,6 +271,7 @@ func (r *TraceReader) GetOperations(
 	ctx context.Context,
 	query spanstore.OperationQueryParameters,
 ) ([]spanstore.Operation, error) {
+	r = r.forTenant(ctx)
 	return r.cache.GetOperations(query.ServiceName)
 }
 
@@ -355,7 +377,7 @@ func filterIDs(plan *executionPlan, innerIDs [][]byte) []model.TraceID {
 			items++
 		}
 
-		if items == plan.limit {
+		if plan.limit > 0 && items == plan.limit {
 			return traces
 		}
 	}
@@ -407,6 +429,7 @@ func (r *TraceReader) durationQueries(plan *executionPlan, query *spanstore.Trac
 	}
 	binary.BigEndian.PutUint64(endKey[1:], durMax)
 	binary.BigEndian.PutUint64(startKey[1:], durMin)
+	startKey, endKey = prefixKey(r.prefix, startKey), prefixKey(r.prefix, endKey)
 
 	// This is not unique index result - same TraceID can be matched from multiple spans
 	indexResults, _ := r.scanRangeIndex(plan, startKey, endKey)
@@ -458,6 +481,10 @@ func mergeJoinIds(left, right [][]byte) [][]byte {
 
 // FindTraces retrieves traces that match the traceQuery
 func (r *TraceReader) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	r = r.forTenant(ctx)
+	if query != nil && query.Query != nil {
+		return r.findTracesByQuery(query)
+	}
 	keys, err := r.FindTraceIDs(ctx, query)
 	if err != nil {
 		return nil, err
@@ -468,6 +495,14 @@ func (r *TraceReader) FindTraces(ctx context.Context, query *spanstore.TraceQuer
 
 // FindTraceIDs retrieves only the TraceIDs that match the traceQuery, but not the trace data
 func (r *TraceReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	r = r.forTenant(ctx)
+	if query != nil && query.Query != nil {
+		traces, err := r.findTracesByQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.TraceIDs(traces), nil
+	}
 	// Validate and set query defaults which were not defined
 	if err := validateQuery(query); err != nil {
 		return nil, err
@@ -475,9 +510,124 @@ func (r *TraceReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQu
 
 	setQueryDefaults(query)
 
+	return r.findTraceIDs(query, query.NumTraces)
+}
+
+// findTracesByQuery evaluates the structured query against all the traces found by the indexes
+// for the conditions it implies, loading them in batches until enough traces match.
+func (r *TraceReader) findTracesByQuery(query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	pushed := spanstore.PushDownQuery(query, 1)
+	if err := validateQuery(pushed); err != nil {
+		return nil, err
+	}
+	setQueryDefaults(pushed)
+
+	keys, err := r.findTraceIDs(pushed, 0)
+	if err != nil {
+		return nil, err
+	}
+	var traces []*model.Trace
+	for start := 0; start < len(keys) && len(traces) < pushed.NumTraces; start += queryBatchSize {
+		end := start + queryBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch, err := r.getTraces(keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range batch {
+			if len(traces) < pushed.NumTraces && spanstore.MatchesQuery(trace, query) {
+				traces = append(traces, trace)
+			}
+		}
+	}
+	return traces, nil
+}
+
+// FindSpans retrieves the spans that match the query. The traces found by the indexes are loaded
+// in batches and their spans filtered, so all candidate spans are sorted before applying the limit.
+func (r *TraceReader) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	r = r.forTenant(ctx)
+	if query == nil {
+		return nil, ErrMalformedRequestObject
+	}
+	traceQuery := query.ToTraceQueryParameters()
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, err
+	}
+	keys, err := r.findTraceIDs(traceQuery, 0)
+	if err != nil {
+		return nil, err
+	}
+	var spans []*model.Span
+	for start := 0; start < len(keys); start += queryBatchSize {
+		end := start + queryBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch, err := r.getTraces(keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range batch {
+			for _, span := range trace.Spans {
+				if spanstore.MatchesSpan(span, query) {
+					spans = append(spans, span)
+				}
+			}
+		}
+	}
+	return spanstore.SortSpans(spans, query), nil
+}
+
+// GetSpanStats computes the statistics of the spans that match the query, scanning the traces
+// found by the indexes in batches.
+func (r *TraceReader) GetSpanStats(ctx context.Context, query *spanstore.SpanStatsQueryParameters) ([]spanstore.SpanStats, error) {
+	r = r.forTenant(ctx)
+	if query == nil {
+		return nil, ErrMalformedRequestObject
+	}
+	traceQuery := &spanstore.TraceQueryParameters{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		StartTimeMin:  query.StartTimeMin,
+		StartTimeMax:  query.StartTimeMax,
+	}
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, err
+	}
+	keys, err := r.findTraceIDs(traceQuery, 0)
+	if err != nil {
+		return nil, err
+	}
+	aggregator := spanstore.NewSpanStatsAggregator(query)
+	for start := 0; start < len(keys); start += queryBatchSize {
+		end := start + queryBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch, err := r.getTraces(keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range batch {
+			for _, span := range trace.Spans {
+				aggregator.Add(span)
+			}
+		}
+	}
+	return aggregator.Stats(), nil
+}
+
+// findTraceIDs looks up the trace IDs in the indexes, up to limit trace IDs or all of them if limit is 0.
+func (r *TraceReader) findTraceIDs(query *spanstore.TraceQueryParameters, limit int) ([]model.TraceID, error) {
 	// Find matches using indexes that are using service as part of the key
 	indexSeeks := make([][]byte, 0, 1)
 	indexSeeks = serviceQueries(query, indexSeeks)
+	for i := range indexSeeks {
+		indexSeeks[i] = prefixKey(r.prefix, indexSeeks[i])
+	}
 
 	startStampBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(startStampBytes, model.TimeAsEpochMicroseconds(query.StartTimeMin))
@@ -488,7 +638,7 @@ func (r *TraceReader) FindTraceIDs(ctx context.Context, query *spanstore.TraceQu
 	plan := &executionPlan{
 		startTimeMin: startStampBytes,
 		startTimeMax: endStampBytes,
-		limit:        query.NumTraces,
+		limit:        limit,
 	}
 
 	if query.DurationMax != 0 || query.DurationMin != 0 {
@@ -621,6 +771,10 @@ func (r *TraceReader) scanRangeIndex(plan *executionPlan, indexStartValue []byte
 // scanRangeFunction seeks until the index end has been reached
 func scanRangeFunction(it *badger.Iterator, indexEndValue []byte) bool {
 	if it.Valid() {
+		// the keys of the next tenants may be shorter than the index end
+		if len(it.Item().Key()) < len(indexEndValue) {
+			return false
+		}
 		compareSlice := it.Item().Key()[:len(indexEndValue)]
 		return bytes.Compare(indexEndValue, compareSlice) >= 0
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 /*
@@ -32,6 +33,9 @@ import (
 	That includes RocksDB also (this key structure should work as-is with RocksDB)
 
 	Keys are written in BigEndian order to allow lexicographic sorting of keys
+
+	The keys of a tenant other than the default one are prefixed with <tenant>0x00. The tenant names
+	are made of ASCII characters, so they never collide with the keys of the default tenant.
 */
 
 const (
@@ -102,6 +106,13 @@ func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 		}
 	}
 
+	tenant := tenancy.GetTenant(ctx)
+	if prefix := tenantKeyPrefix(tenant); prefix != nil {
+		for _, entry := range entriesToStore {
+			entry.Key = prefixKey(prefix, entry.Key)
+		}
+	}
+
 	err = w.store.Update(func(txn *badger.Txn) error {
 		// Write the entries
 		for i := range entriesToStore {
@@ -119,11 +130,32 @@ func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 	})
 
 	// Do cache refresh here to release the transaction earlier
-	w.cache.Update(span.Process.ServiceName, span.OperationName, expireTime)
+	w.cache.forTenant(tenant).Update(span.Process.ServiceName, span.OperationName, expireTime)
 
 	return err
 }
 
+// tenantKeyPrefix returns the prefix of the keys of the given tenant, nil for the default tenant
+func tenantKeyPrefix(tenant string) []byte {
+	if tenant == "" {
+		return nil
+	}
+	prefix := make([]byte, len(tenant)+1)
+	copy(prefix, tenant)
+	return prefix
+}
+
+// prefixKey returns a new key made of the prefix followed by the key
+func prefixKey(prefix, key []byte) []byte {
+	if len(prefix) == 0 {
+		return key
+	}
+	prefixed := make([]byte, len(prefix)+len(key))
+	copy(prefixed, prefix)
+	copy(prefixed[len(prefix):], key)
+	return prefixed
+}
+
 func createIndexKey(indexPrefixKey byte, value []byte, startTime uint64, traceID model.TraceID) []byte {
 	// KEY: indexKey<indexValue><startTime><traceId> (traceId is last 16 bytes of the key)
 	key := make([]byte, 1+len(value)+8+sizeOfTraceID)
@@ -18,7 +18,9 @@ package cassandra
 import (
 	"errors"
 	"flag"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
@@ -31,6 +33,7 @@ import (
 	cLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/cassandra"
 	cDepStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/dependencystore"
 	cSamplingStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/samplingstore"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/schema"
 	cSpanStore "github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage"
@@ -99,6 +102,9 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 		return err
 	}
 	f.primarySession = primarySession
+	if err := f.validateSchema(primarySession, f.Options.GetPrimary().Keyspace); err != nil {
+		return err
+	}
 
 	if f.archiveConfig != nil {
 		if archiveSession, err := f.archiveConfig.NewSession(logger); err == nil {
@@ -106,12 +112,43 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 		} else {
 			return err
 		}
+		if err := f.validateSchema(f.archiveSession, f.Options.Get(archiveStorageConfig).Keyspace); err != nil {
+			return err
+		}
 	} else {
 		logger.Info("Cassandra archive storage configuration is empty, skipping")
 	}
 	return nil
 }
 
+// validateSchema compares the schema of the keyspace with the one expected by the stores and,
+// depending on the schema validation option, logs the mismatches or returns them as an error.
+func (f *Factory) validateSchema(session cassandra.Session, keyspace string) error {
+	if f.Options.SchemaValidation == SchemaValidationNone {
+		return nil
+	}
+	report, err := schema.Validate(session, keyspace)
+	if err != nil {
+		// The validation relies on the system_schema keyspace, which some Cassandra compatible databases do not provide.
+		f.logger.Warn("Cannot validate the schema of the keyspace", zap.String("keyspace", keyspace), zap.Error(err))
+		return nil
+	}
+	if len(report.Mismatches) > 0 {
+		if f.Options.SchemaValidation == SchemaValidationFail {
+			return fmt.Errorf("the schema of keyspace %s does not match the expected schema: %s",
+				keyspace, strings.Join(report.Mismatches, "; "))
+		}
+		f.logger.Warn("The schema of the keyspace does not match the expected schema",
+			zap.String("keyspace", keyspace), zap.Strings("mismatches", report.Mismatches))
+		return nil
+	}
+	if report.Version < schema.LatestVersion {
+		f.logger.Info("The keyspace uses an older schema version, it can be migrated with jaeger-cassandra-schema",
+			zap.String("keyspace", keyspace), zap.Int("version", report.Version), zap.Int("latest", schema.LatestVersion))
+	}
+	return nil
+}
+
 // CreateSpanReader implements storage.Factory
 func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 	return cSpanStore.NewSpanReader(f.primarySession, f.primaryMetricsFactory, f.logger), nil
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
@@ -65,9 +66,13 @@ func TestCassandraFactory(t *testing.T) {
 	var (
 		session = &mocks.Session{}
 		query   = &mocks.Query{}
+		iter    = &mocks.Iterator{}
 	)
 	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
 	query.On("Exec").Return(nil)
+	query.On("Iter").Return(iter)
+	iter.On("Scan", mock.Anything).Return(false)
+	iter.On("Close").Return(nil)
 	f.primaryConfig = newMockSessionBuilder(session, nil)
 	f.archiveConfig = newMockSessionBuilder(nil, errors.New("made-up error"))
 	assert.EqualError(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "made-up error")
@@ -109,6 +114,67 @@ func TestCassandraFactory(t *testing.T) {
 	assert.NoError(t, f.Close())
 }
 
+func TestCassandraFactorySchemaValidation(t *testing.T) {
+	newSession := func(scanErr error) *mocks.Session {
+		session := &mocks.Session{}
+		query := &mocks.Query{}
+		iter := &mocks.Iterator{}
+		session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
+		query.On("Iter").Return(iter)
+		iter.On("Scan", mock.Anything).Return(false)
+		iter.On("Close").Return(scanErr)
+		return session
+	}
+	tests := []struct {
+		validation string
+		scanErr    error
+		err        string
+		log        string
+	}{
+		{
+			validation: SchemaValidationFail,
+			err:        "the schema of keyspace jaeger_v1_test does not match the expected schema: table traces is missing; ",
+		},
+		{
+			validation: SchemaValidationWarn,
+			log:        "The schema of the keyspace does not match the expected schema",
+		},
+		{
+			validation: SchemaValidationFail,
+			scanErr:    errors.New("unauthorized"),
+			log:        "Cannot validate the schema of the keyspace",
+		},
+		{
+			validation: SchemaValidationNone,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.validation, func(t *testing.T) {
+			logger, logBuf := testutils.NewLogger()
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			command.ParseFlags([]string{"--cassandra.schema.validation=" + test.validation})
+			f.InitFromViper(v, zap.NewNop())
+			session := newSession(test.scanErr)
+			f.primaryConfig = newMockSessionBuilder(session, nil)
+
+			err := f.Initialize(metrics.NullFactory, logger)
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if test.log != "" {
+				assert.Contains(t, logBuf.String(), test.log)
+			}
+			if test.validation == SchemaValidationNone {
+				session.AssertNotCalled(t, "Query", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestExclusiveWhitelistBlacklist(t *testing.T) {
 	logger, logBuf := testutils.NewLogger()
 	f := NewFactory()
@@ -126,9 +192,13 @@ func TestExclusiveWhitelistBlacklist(t *testing.T) {
 	var (
 		session = &mocks.Session{}
 		query   = &mocks.Query{}
+		iter    = &mocks.Iterator{}
 	)
 	session.On("Query", mock.AnythingOfType("string"), mock.Anything).Return(query)
 	query.On("Exec").Return(nil)
+	query.On("Iter").Return(iter)
+	iter.On("Scan", mock.Anything).Return(false)
+	iter.On("Close").Return(nil)
 	f.primaryConfig = newMockSessionBuilder(session, nil)
 	f.archiveConfig = newMockSessionBuilder(nil, errors.New("made-up error"))
 	assert.EqualError(t, f.Initialize(metrics.NullFactory, zap.NewNop()), "made-up error")
@@ -140,7 +210,7 @@ func TestExclusiveWhitelistBlacklist(t *testing.T) {
 	_, err := f.CreateSpanWriter()
 	assert.EqualError(t, err, "only one of TagIndexBlacklist and TagIndexWhitelist can be specified")
 
-	f.archiveConfig = &mockSessionBuilder{}
+	f.archiveConfig = newMockSessionBuilder(session, nil)
 	assert.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
 
 	_, err = f.CreateArchiveSpanWriter()
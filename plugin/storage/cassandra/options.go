@@ -52,6 +52,14 @@ const (
 	suffixIndexLogs              = ".index.logs"
 	suffixIndexTags              = ".index.tags"
 	suffixIndexProcessTags       = ".index.process-tags"
+	suffixSchemaValidation       = ".schema.validation"
+
+	// SchemaValidationWarn logs the mismatches between the keyspace schema and the expected one.
+	SchemaValidationWarn = "warn"
+	// SchemaValidationFail refuses to start when the keyspace schema does not match the expected one.
+	SchemaValidationFail = "fail"
+	// SchemaValidationNone skips the validation of the keyspace schema.
+	SchemaValidationNone = "none"
 )
 
 // Options contains various type of Cassandra configs and provides the ability
@@ -62,6 +70,7 @@ type Options struct {
 	others                 map[string]*namespaceConfig
 	SpanStoreWriteCacheTTL time.Duration `mapstructure:"span_store_write_cache_ttl"`
 	Index                  IndexConfig   `mapstructure:"index"`
+	SchemaValidation       string        `mapstructure:"schema_validation"`
 }
 
 // IndexConfig configures indexing.
@@ -102,6 +111,7 @@ func NewOptions(primaryNamespace string, otherNamespaces ...string) *Options {
 		},
 		others:                 make(map[string]*namespaceConfig, len(otherNamespaces)),
 		SpanStoreWriteCacheTTL: time.Hour * 12,
+		SchemaValidation:       SchemaValidationWarn,
 	}
 
 	for _, namespace := range otherNamespaces {
@@ -140,6 +150,11 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		opt.Primary.namespace+suffixIndexProcessTags,
 		!opt.Index.ProcessTags,
 		"Controls process tag indexing. Set to false to disable.")
+	flagSet.String(
+		opt.Primary.namespace+suffixSchemaValidation,
+		opt.SchemaValidation,
+		"What to do when the schema of the keyspace does not match the one expected by Jaeger on startup: "+
+			"'warn' to log the differences, 'fail' to refuse to start or 'none' to skip the validation")
 }
 
 func addFlags(flagSet *flag.FlagSet, nsConfig namespaceConfig) {
@@ -226,6 +241,7 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Index.Tags = v.GetBool(opt.Primary.namespace + suffixIndexTags)
 	opt.Index.Logs = v.GetBool(opt.Primary.namespace + suffixIndexLogs)
 	opt.Index.ProcessTags = v.GetBool(opt.Primary.namespace + suffixIndexProcessTags)
+	opt.SchemaValidation = v.GetString(opt.Primary.namespace + suffixSchemaValidation)
 }
 
 func tlsFlagsConfig(namespace string) tlscfg.ClientFlagsConfig {
@@ -64,6 +64,7 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--cas.index.tag-whitelist=flerg, flarg,florg ",
 		"--cas.index.tags=true",
 		"--cas.index.process-tags=false",
+		"--cas.schema.validation=fail",
 		// enable aux with a couple overrides
 		"--cas-aux.enabled=true",
 		"--cas-aux.keyspace=jaeger-archive",
@@ -81,6 +82,7 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, true, opts.Index.Tags)
 	assert.Equal(t, false, opts.Index.ProcessTags)
 	assert.Equal(t, true, opts.Index.Logs)
+	assert.Equal(t, SchemaValidationFail, opts.SchemaValidation)
 
 	aux := opts.Get("cas-aux")
 	require.NotNil(t, aux)
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+)
+
+const (
+	tablesQuery         = "SELECT table_name, compaction, default_time_to_live FROM system_schema.tables WHERE keyspace_name = ?"
+	columnsQuery        = "SELECT table_name, column_name, type FROM system_schema.columns WHERE keyspace_name = ?"
+	typesQuery          = "SELECT type_name, field_names FROM system_schema.types WHERE keyspace_name = ?"
+	releaseVersionQuery = "SELECT release_version FROM system.local"
+)
+
+// keyspaceSchema is the schema of a keyspace as described by the system_schema keyspace.
+type keyspaceSchema struct {
+	tables map[string]*tableSchema
+	// types maps the user defined types to their fields.
+	types map[string][]string
+}
+
+type tableSchema struct {
+	// columns maps the columns to their CQL type.
+	columns    map[string]string
+	compaction map[string]string
+	defaultTTL int
+}
+
+func readKeyspace(session cassandra.Session, keyspace string) (*keyspaceSchema, error) {
+	k := &keyspaceSchema{
+		tables: make(map[string]*tableSchema),
+		types:  make(map[string][]string),
+	}
+
+	var (
+		name       string
+		compaction map[string]string
+		ttl        int
+	)
+	iter := session.Query(tablesQuery, keyspace).Iter()
+	for iter.Scan(&name, &compaction, &ttl) {
+		k.tables[name] = &tableSchema{columns: make(map[string]string), compaction: compaction, defaultTTL: ttl}
+		compaction = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot read the tables of keyspace %s: %w", keyspace, err)
+	}
+
+	var column, columnType string
+	iter = session.Query(columnsQuery, keyspace).Iter()
+	for iter.Scan(&name, &column, &columnType) {
+		if t, ok := k.tables[name]; ok {
+			t.columns[column] = columnType
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot read the columns of keyspace %s: %w", keyspace, err)
+	}
+
+	var fields []string
+	iter = session.Query(typesQuery, keyspace).Iter()
+	for iter.Scan(&name, &fields) {
+		k.types[name] = fields
+		fields = nil
+	}
+	if err := iter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot read the types of keyspace %s: %w", keyspace, err)
+	}
+	return k, nil
+}
+
+// version returns the version of the schema, 0 if the keyspace does not contain any table.
+func (k *keyspaceSchema) version() int {
+	switch {
+	case len(k.tables) == 0:
+		return 0
+	case k.tables["operation_names_v2"] != nil:
+		return 3
+	case k.tables["dependencies_v2"] != nil:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// DetectVersion returns the version of the schema of the keyspace, 0 if the keyspace does not contain any table.
+func DetectVersion(session cassandra.Session, keyspace string) (int, error) {
+	k, err := readKeyspace(session, keyspace)
+	if err != nil {
+		return 0, err
+	}
+	return k.version(), nil
+}
+
+// cassandraMajorVersion returns the major version of the Cassandra node the session is connected to.
+func cassandraMajorVersion(session cassandra.Session) (int, error) {
+	var release string
+	iter := session.Query(releaseVersionQuery).Iter()
+	iter.Scan(&release)
+	if err := iter.Close(); err != nil {
+		return 0, fmt.Errorf("cannot read the Cassandra release version: %w", err)
+	}
+	major, err := strconv.Atoi(strings.SplitN(release, ".", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse the Cassandra release version '%s': %w", release, err)
+	}
+	return major, nil
+}
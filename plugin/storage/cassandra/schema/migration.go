@@ -0,0 +1,258 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/dependencystore"
+)
+
+// dependenciesBucket is the time bucket of the dependencies_v2 table, it must match the one of the dependency store.
+const dependenciesBucket = 24 * time.Hour
+
+// migration upgrades the schema of a keyspace from one version to the next.
+// Migrations are idempotent, so that an interrupted migration can be applied again.
+type migration struct {
+	from        int
+	description string
+	apply       func(m *Migrator, k *keyspaceSchema) error
+}
+
+var migrations = []migration{
+	{from: 1, description: "create the dependencies_v2 table and copy the dependencies", apply: (*Migrator).migrateDependencies},
+	{from: 2, description: "create the operation_names_v2 and adaptive sampling tables and copy the operation names", apply: (*Migrator).migrateOperationNames},
+}
+
+// Migrator creates the schema of a keyspace or migrates it forward to the latest version.
+type Migrator struct {
+	session cassandra.Session
+	params  Params
+	dryRun  bool
+	out     io.Writer
+}
+
+// NewMigrator creates a Migrator executing the statements with the session, which must not be bound
+// to the keyspace as the keyspace may not exist yet. The statements are written to out and,
+// in dry-run mode, not executed.
+func NewMigrator(session cassandra.Session, params Params, dryRun bool, out io.Writer) *Migrator {
+	return &Migrator{
+		session: session,
+		params:  params,
+		dryRun:  dryRun,
+		out:     out,
+	}
+}
+
+// Migrate creates the keyspace if it does not contain any table, or applies the migrations from its current
+// schema version to the latest. It returns the version of the schema before and after the migration.
+func (m *Migrator) Migrate() (from int, to int, err error) {
+	if err := m.params.Validate(); err != nil {
+		return 0, 0, err
+	}
+	k, err := readKeyspace(m.session, m.params.Keyspace)
+	if err != nil {
+		return 0, 0, err
+	}
+	from = k.version()
+	if from == 0 {
+		return 0, LatestVersion, m.create()
+	}
+	version := from
+	for _, mig := range migrations {
+		if mig.from != version {
+			continue
+		}
+		m.comment("migrate from version %d to %d: %s", mig.from, mig.from+1, mig.description)
+		if err := mig.apply(m, k); err != nil {
+			return from, version, fmt.Errorf("failed to migrate from version %d to %d: %w", mig.from, mig.from+1, err)
+		}
+		version++
+	}
+	return from, version, nil
+}
+
+func (m *Migrator) create() error {
+	major, err := cassandraMajorVersion(m.session)
+	if err != nil {
+		return err
+	}
+	template := templateFor(major)
+	statements, err := Statements(template, m.params)
+	if err != nil {
+		return err
+	}
+	m.comment("create keyspace %s from template v%03d for Cassandra %d", m.params.Keyspace, template, major)
+	for _, stmt := range statements {
+		if err := m.exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) migrateDependencies(k *keyspaceSchema) error {
+	if !contains(k.types["dependency"], "source") {
+		if err := m.exec(fmt.Sprintf("ALTER TYPE %s.dependency ADD source text", m.params.Keyspace)); err != nil {
+			return err
+		}
+	}
+	ttl := 0
+	if t, ok := k.tables["dependencies"]; ok {
+		ttl = t.defaultTTL
+	}
+	if err := m.exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.dependencies_v2 (
+    ts_bucket    timestamp,
+    ts           timestamp,
+    dependencies list<frozen<dependency>>,
+    PRIMARY KEY (ts_bucket, ts)
+) WITH CLUSTERING ORDER BY (ts DESC)
+    AND compaction = {
+        'min_threshold': '4',
+        'max_threshold': '32',
+        'class': 'org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy'
+    }
+    AND default_time_to_live = %d`, m.params.Keyspace, ttl)); err != nil {
+		return err
+	}
+	if _, ok := k.tables["dependencies"]; !ok {
+		return nil
+	}
+
+	selectStmt := fmt.Sprintf("SELECT ts, dependencies FROM %s.dependencies", m.params.Keyspace)
+	insertStmt := fmt.Sprintf("INSERT INTO %s.dependencies_v2 (ts_bucket, ts, dependencies) VALUES (?, ?, ?)", m.params.Keyspace)
+	if m.dryRun {
+		m.comment("copy each row of %s into %s", selectStmt, insertStmt)
+		return nil
+	}
+	var (
+		ts     time.Time
+		deps   []dependencystore.Dependency
+		copied int
+	)
+	iter := m.session.Query(selectStmt).Iter()
+	for iter.Scan(&ts, &deps) {
+		if err := m.session.Query(insertStmt, ts.Truncate(dependenciesBucket), ts, deps).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to copy the dependencies of %s: %w", ts, err)
+		}
+		deps = nil
+		copied++
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read the dependencies: %w", err)
+	}
+	m.comment("copied %d rows from %s.dependencies to %s.dependencies_v2", copied, m.params.Keyspace, m.params.Keyspace)
+	return nil
+}
+
+func (m *Migrator) migrateOperationNames(k *keyspaceSchema) error {
+	ttl := int(m.params.TraceTTL.Seconds())
+	if t, ok := k.tables["operation_names"]; ok {
+		ttl = t.defaultTTL
+	}
+	for _, stmt := range []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.operation_names_v2 (
+    service_name        text,
+    span_kind           text,
+    operation_name      text,
+    PRIMARY KEY ((service_name), span_kind, operation_name)
+)
+    WITH compaction = {
+        'min_threshold': '4',
+        'max_threshold': '32',
+        'class': 'org.apache.cassandra.db.compaction.SizeTieredCompactionStrategy'
+    }
+    AND default_time_to_live = %d
+    AND speculative_retry = 'NONE'
+    AND gc_grace_seconds = 10800`, m.params.Keyspace, ttl),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.operation_throughput (
+    bucket        int,
+    ts            timeuuid,
+    throughput    text,
+    PRIMARY KEY(bucket, ts)
+) WITH CLUSTERING ORDER BY (ts desc)`, m.params.Keyspace),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.sampling_probabilities (
+    bucket        int,
+    ts            timeuuid,
+    hostname      text,
+    probabilities text,
+    PRIMARY KEY(bucket, ts)
+) WITH CLUSTERING ORDER BY (ts desc)`, m.params.Keyspace),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.leases (
+    name text,
+    owner text,
+    PRIMARY KEY (name)
+)`, m.params.Keyspace),
+	} {
+		if err := m.exec(stmt); err != nil {
+			return err
+		}
+	}
+	if _, ok := k.tables["operation_names"]; !ok {
+		return nil
+	}
+
+	selectStmt := fmt.Sprintf("SELECT service_name, operation_name FROM %s.operation_names", m.params.Keyspace)
+	insertStmt := fmt.Sprintf("INSERT INTO %s.operation_names_v2 (service_name, span_kind, operation_name) VALUES (?, '', ?)", m.params.Keyspace)
+	if m.dryRun {
+		m.comment("copy each row of %s into %s", selectStmt, insertStmt)
+		return nil
+	}
+	var (
+		service, operation string
+		copied             int
+	)
+	iter := m.session.Query(selectStmt).Iter()
+	for iter.Scan(&service, &operation) {
+		if err := m.session.Query(insertStmt, service, operation).Exec(); err != nil {
+			iter.Close()
+			return fmt.Errorf("failed to copy the operation %s of service %s: %w", operation, service, err)
+		}
+		copied++
+	}
+	if err := iter.Close(); err != nil {
+		return fmt.Errorf("failed to read the operation names: %w", err)
+	}
+	m.comment("copied %d rows from %s.operation_names to %s.operation_names_v2", copied, m.params.Keyspace, m.params.Keyspace)
+	return nil
+}
+
+func (m *Migrator) exec(stmt string) error {
+	fmt.Fprintf(m.out, "%s;\n\n", stmt)
+	if m.dryRun {
+		return nil
+	}
+	if err := m.session.Query(stmt).Exec(); err != nil {
+		return fmt.Errorf("failed to execute statement %q: %w", stmt, err)
+	}
+	return nil
+}
+
+func (m *Migrator) comment(format string, args ...interface{}) {
+	fmt.Fprintf(m.out, "-- "+format+"\n", args...)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/dependencystore"
+)
+
+const (
+	selectDependencies   = "SELECT ts, dependencies FROM jaeger_v1_test.dependencies"
+	insertDependencies   = "INSERT INTO jaeger_v1_test.dependencies_v2 (ts_bucket, ts, dependencies) VALUES (?, ?, ?)"
+	selectOperationNames = "SELECT service_name, operation_name FROM jaeger_v1_test.operation_names"
+	insertOperationNames = "INSERT INTO jaeger_v1_test.operation_names_v2 (service_name, span_kind, operation_name) VALUES (?, '', ?)"
+)
+
+func TestMigrateCreatesKeyspace(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		session := newFakeSession()
+		session.rows[releaseVersionQuery] = [][]interface{}{{"4.0.1"}}
+		out := &bytes.Buffer{}
+
+		from, to, err := NewMigrator(session, testParams(), dryRun, out).Migrate()
+		require.NoError(t, err)
+		assert.Equal(t, 0, from)
+		assert.Equal(t, LatestVersion, to)
+
+		statements, err := Statements(4, testParams())
+		require.NoError(t, err)
+		if dryRun {
+			assert.Empty(t, session.executed)
+		} else {
+			assert.Equal(t, statements, session.executed)
+		}
+		assert.Contains(t, out.String(), "-- create keyspace jaeger_v1_test from template v004 for Cassandra 4\n")
+		assert.Contains(t, out.String(), statements[0]+";\n")
+	}
+}
+
+func TestMigrateFromVersion1(t *testing.T) {
+	ts := time.Date(2021, 10, 20, 13, 30, 0, 0, time.UTC)
+	deps := []dependencystore.Dependency{{Parent: "frontend", Child: "driver", CallCount: 3}}
+
+	tables := tablesOf(1)
+	tables["dependencies"].defaultTTL = 0
+	session := newFakeSession().withKeyspace(tables, map[string][]string{"dependency": {"parent", "child", "call_count"}})
+	session.rows[selectDependencies] = [][]interface{}{{ts, deps}}
+	session.rows[selectOperationNames] = [][]interface{}{{"frontend", "HTTP GET /dispatch"}}
+	out := &bytes.Buffer{}
+
+	from, to, err := NewMigrator(session, testParams(), false, out).Migrate()
+	require.NoError(t, err)
+	assert.Equal(t, 1, from)
+	assert.Equal(t, 3, to)
+
+	require.Len(t, session.executed, 8)
+	assert.Equal(t, "ALTER TYPE jaeger_v1_test.dependency ADD source text", session.executed[0])
+	assert.True(t, strings.HasPrefix(session.executed[1], "CREATE TABLE IF NOT EXISTS jaeger_v1_test.dependencies_v2"))
+	assert.True(t, strings.HasSuffix(session.executed[1], "AND default_time_to_live = 0"))
+	assert.Equal(t, insertDependencies, session.executed[2])
+	assert.Equal(t, []interface{}{time.Date(2021, 10, 20, 0, 0, 0, 0, time.UTC), ts, deps}, session.values[2])
+	assert.True(t, strings.HasPrefix(session.executed[3], "CREATE TABLE IF NOT EXISTS jaeger_v1_test.operation_names_v2"))
+	assert.Contains(t, session.executed[3], "AND default_time_to_live = 172800")
+	assert.Equal(t, insertOperationNames, session.executed[7])
+	assert.Equal(t, []interface{}{"frontend", "HTTP GET /dispatch"}, session.values[7])
+
+	assert.Contains(t, out.String(), "-- migrate from version 1 to 2: create the dependencies_v2 table and copy the dependencies\n")
+	assert.Contains(t, out.String(), "-- copied 1 rows from jaeger_v1_test.dependencies to jaeger_v1_test.dependencies_v2\n")
+	assert.Contains(t, out.String(), "-- migrate from version 2 to 3: ")
+	assert.Contains(t, out.String(), "-- copied 1 rows from jaeger_v1_test.operation_names to jaeger_v1_test.operation_names_v2\n")
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	// The type was altered and the table created by a previous, interrupted, migration.
+	tables := tablesOf(2)
+	tables["dependencies"] = tableOf(expectedTables[len(expectedTables)-1].previous)
+	session := newFakeSession().withKeyspace(tables, map[string][]string{"dependency": {"parent", "child", "call_count", "source"}})
+
+	from, to, err := NewMigrator(session, testParams(), false, &bytes.Buffer{}).Migrate()
+	require.NoError(t, err)
+	assert.Equal(t, 2, from)
+	assert.Equal(t, 3, to)
+	for _, stmt := range session.executed {
+		assert.True(t, strings.HasPrefix(stmt, "CREATE TABLE IF NOT EXISTS") || stmt == insertOperationNames, stmt)
+	}
+
+	session = newFakeSession().withKeyspace(tablesOf(3), nil)
+	from, to, err = NewMigrator(session, testParams(), false, &bytes.Buffer{}).Migrate()
+	require.NoError(t, err)
+	assert.Equal(t, 3, from)
+	assert.Equal(t, 3, to)
+	assert.Empty(t, session.executed)
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	session := newFakeSession().withKeyspace(tablesOf(2), nil)
+	session.rows[selectOperationNames] = [][]interface{}{{"frontend", "HTTP GET /dispatch"}}
+	out := &bytes.Buffer{}
+
+	from, to, err := NewMigrator(session, testParams(), true, out).Migrate()
+	require.NoError(t, err)
+	assert.Equal(t, 2, from)
+	assert.Equal(t, 3, to)
+	assert.Empty(t, session.executed)
+	assert.Contains(t, out.String(), "CREATE TABLE IF NOT EXISTS jaeger_v1_test.operation_names_v2")
+	assert.Contains(t, out.String(), "-- copy each row of "+selectOperationNames+" into "+insertOperationNames+"\n")
+}
+
+func TestMigrateErrors(t *testing.T) {
+	params := testParams()
+	params.Mode = "dev"
+	_, _, err := NewMigrator(newFakeSession(), params, false, &bytes.Buffer{}).Migrate()
+	assert.EqualError(t, err, "invalid mode 'dev', expecting 'prod' or 'test'")
+
+	session := newFakeSession()
+	session.errors[tablesQuery] = errors.New("unauthorized")
+	_, _, err = NewMigrator(session, testParams(), false, &bytes.Buffer{}).Migrate()
+	assert.EqualError(t, err, "cannot read the tables of keyspace jaeger_v1_test: unauthorized")
+
+	session = newFakeSession()
+	session.rows[releaseVersionQuery] = [][]interface{}{{"unknown"}}
+	_, _, err = NewMigrator(session, testParams(), false, &bytes.Buffer{}).Migrate()
+	assert.EqualError(t, err, `cannot parse the Cassandra release version 'unknown': strconv.Atoi: parsing "unknown": invalid syntax`)
+
+	session = newFakeSession().withKeyspace(tablesOf(2), nil)
+	session.rows[selectOperationNames] = [][]interface{}{{"frontend", "HTTP GET /dispatch"}}
+	session.errors[insertOperationNames] = errors.New("timeout")
+	from, to, err := NewMigrator(session, testParams(), false, &bytes.Buffer{}).Migrate()
+	assert.EqualError(t, err, "failed to migrate from version 2 to 3: failed to copy the operation HTTP GET /dispatch of service frontend: timeout")
+	assert.Equal(t, 2, from)
+	assert.Equal(t, 2, to)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// templates contains the CQL templates creating the keyspace, the same ones create.sh renders.
+//
+//go:embed *.cql.tmpl
+var templates embed.FS
+
+const (
+	// LatestVersion is the version of the schema created by the latest templates.
+	// The v004 template creates the same span and dependency tables as v003 on Cassandra 4,
+	// which removed the dclocal_read_repair_chance table option, so both create version 3.
+	LatestVersion = 3
+
+	// ModeProd replicates the keyspace with the NetworkTopologyStrategy.
+	ModeProd = "prod"
+	// ModeTest creates a keyspace usable on a single node cluster.
+	ModeTest = "test"
+)
+
+var (
+	keyspacePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	commentPattern  = regexp.MustCompile(`--.*`)
+)
+
+// Params are the parameters of the keyspace, as set by the environment variables of create.sh.
+type Params struct {
+	Keyspace          string
+	Mode              string
+	Datacenter        string
+	ReplicationFactor int
+	TraceTTL          time.Duration
+	DependenciesTTL   time.Duration
+}
+
+// Validate returns an error if the parameters cannot be used to create the keyspace.
+func (p Params) Validate() error {
+	if !keyspacePattern.MatchString(p.Keyspace) {
+		return fmt.Errorf("invalid keyspace '%s', please use letters, digits or underscores", p.Keyspace)
+	}
+	switch p.Mode {
+	case ModeProd:
+		if p.Datacenter == "" {
+			return fmt.Errorf("the datacenter is required in %s mode", ModeProd)
+		}
+	case ModeTest:
+	default:
+		return fmt.Errorf("invalid mode '%s', expecting '%s' or '%s'", p.Mode, ModeProd, ModeTest)
+	}
+	return nil
+}
+
+func (p Params) replication() string {
+	replicationFactor := p.ReplicationFactor
+	if p.Mode == ModeProd {
+		if replicationFactor == 0 {
+			replicationFactor = 2
+		}
+		return fmt.Sprintf("{'class': 'NetworkTopologyStrategy', '%s': '%d' }", p.Datacenter, replicationFactor)
+	}
+	if replicationFactor == 0 {
+		replicationFactor = 1
+	}
+	return fmt.Sprintf("{'class': 'SimpleStrategy', 'replication_factor': '%d'}", replicationFactor)
+}
+
+// Statements renders the template of the given version and returns its statements.
+func Statements(template int, params Params) ([]string, error) {
+	tmpl, err := templates.ReadFile(fmt.Sprintf("v%03d.cql.tmpl", template))
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema template version %d", template)
+	}
+	cql := commentPattern.ReplaceAllString(string(tmpl), "")
+	cql = strings.NewReplacer(
+		"${keyspace}", params.Keyspace,
+		"${replication}", params.replication(),
+		"${trace_ttl}", strconv.Itoa(int(params.TraceTTL.Seconds())),
+		"${dependencies_ttl}", strconv.Itoa(int(params.DependenciesTTL.Seconds())),
+	).Replace(cql)
+
+	var statements []string
+	for _, stmt := range strings.Split(cql, ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements, nil
+}
+
+// templateFor returns the version of the template creating the latest schema on the given Cassandra major version.
+func templateFor(cassandraMajorVersion int) int {
+	if cassandraMajorVersion >= 4 {
+		return 4
+	}
+	return 3
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testParams() Params {
+	return Params{
+		Keyspace: "jaeger_v1_test",
+		Mode:     ModeTest,
+		TraceTTL: 48 * time.Hour,
+	}
+}
+
+func TestStatements(t *testing.T) {
+	for template := 1; template <= 4; template++ {
+		statements, err := Statements(template, testParams())
+		require.NoError(t, err)
+		assert.Equal(t,
+			"CREATE KEYSPACE IF NOT EXISTS jaeger_v1_test WITH replication = {'class': 'SimpleStrategy', 'replication_factor': '1'}",
+			statements[0])
+		for _, stmt := range statements {
+			assert.NotContains(t, stmt, "--")
+			assert.NotContains(t, stmt, "${")
+		}
+	}
+
+	statements, err := Statements(4, testParams())
+	require.NoError(t, err)
+	assert.Len(t, statements, 14)
+	assert.Contains(t, statements[9], "CREATE TABLE IF NOT EXISTS jaeger_v1_test.service_name_index")
+	assert.Contains(t, statements[9], "AND default_time_to_live = 172800")
+	assert.True(t, strings.HasSuffix(statements[13], "AND default_time_to_live = 0"))
+
+	_, err = Statements(5, testParams())
+	assert.EqualError(t, err, "unknown schema template version 5")
+}
+
+func TestReplication(t *testing.T) {
+	tests := []struct {
+		params   Params
+		expected string
+	}{
+		{
+			params:   Params{Mode: ModeTest},
+			expected: "{'class': 'SimpleStrategy', 'replication_factor': '1'}",
+		},
+		{
+			params:   Params{Mode: ModeTest, ReplicationFactor: 3},
+			expected: "{'class': 'SimpleStrategy', 'replication_factor': '3'}",
+		},
+		{
+			params:   Params{Mode: ModeProd, Datacenter: "dc1"},
+			expected: "{'class': 'NetworkTopologyStrategy', 'dc1': '2' }",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.params.replication())
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		params Params
+		err    string
+	}{
+		{params: Params{Keyspace: "jaeger_v1_dc1", Mode: ModeProd, Datacenter: "dc1"}},
+		{params: Params{Keyspace: "jaeger_v1_test", Mode: ModeTest}},
+		{
+			params: Params{Keyspace: "jaeger-v1", Mode: ModeTest},
+			err:    "invalid keyspace 'jaeger-v1', please use letters, digits or underscores",
+		},
+		{
+			params: Params{Keyspace: "jaeger_v1", Mode: ModeProd},
+			err:    "the datacenter is required in prod mode",
+		},
+		{
+			params: Params{Keyspace: "jaeger_v1", Mode: "dev"},
+			err:    "invalid mode 'dev', expecting 'prod' or 'test'",
+		},
+	}
+	for _, test := range tests {
+		err := test.params.Validate()
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestTemplateFor(t *testing.T) {
+	assert.Equal(t, 3, templateFor(3))
+	assert.Equal(t, 4, templateFor(4))
+	assert.Equal(t, 4, templateFor(5))
+}
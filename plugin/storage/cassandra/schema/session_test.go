@@ -0,0 +1,106 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"reflect"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+)
+
+// fakeSession returns the rows registered for the statements and records the executed statements.
+type fakeSession struct {
+	rows     map[string][][]interface{}
+	errors   map[string]error
+	executed []string
+	values   [][]interface{}
+}
+
+func newFakeSession() *fakeSession {
+	return &fakeSession{
+		rows:   make(map[string][][]interface{}),
+		errors: make(map[string]error),
+	}
+}
+
+// withKeyspace registers the system_schema rows describing the tables.
+func (s *fakeSession) withKeyspace(tables map[string]*tableSchema, types map[string][]string) *fakeSession {
+	for name, t := range tables {
+		s.rows[tablesQuery] = append(s.rows[tablesQuery], []interface{}{name, t.compaction, t.defaultTTL})
+		for column, columnType := range t.columns {
+			s.rows[columnsQuery] = append(s.rows[columnsQuery], []interface{}{name, column, columnType})
+		}
+	}
+	for name, fields := range types {
+		s.rows[typesQuery] = append(s.rows[typesQuery], []interface{}{name, fields})
+	}
+	return s
+}
+
+func (s *fakeSession) Query(stmt string, values ...interface{}) cassandra.Query {
+	return &fakeQuery{session: s, stmt: stmt, values: values}
+}
+
+func (s *fakeSession) Close() {}
+
+type fakeQuery struct {
+	session *fakeSession
+	stmt    string
+	values  []interface{}
+}
+
+func (q *fakeQuery) Exec() error {
+	if err := q.session.errors[q.stmt]; err != nil {
+		return err
+	}
+	q.session.executed = append(q.session.executed, q.stmt)
+	q.session.values = append(q.session.values, q.values)
+	return nil
+}
+
+func (q *fakeQuery) String() string { return q.stmt }
+
+func (q *fakeQuery) ScanCAS(dest ...interface{}) (bool, error) { return false, nil }
+
+func (q *fakeQuery) Iter() cassandra.Iterator {
+	return &fakeIterator{rows: q.session.rows[q.stmt], err: q.session.errors[q.stmt]}
+}
+
+func (q *fakeQuery) Bind(v ...interface{}) cassandra.Query {
+	q.values = v
+	return q
+}
+
+func (q *fakeQuery) Consistency(level cassandra.Consistency) cassandra.Query { return q }
+
+func (q *fakeQuery) PageSize(int) cassandra.Query { return q }
+
+type fakeIterator struct {
+	rows [][]interface{}
+	err  error
+}
+
+func (i *fakeIterator) Scan(dest ...interface{}) bool {
+	if i.err != nil || len(i.rows) == 0 {
+		return false
+	}
+	for j, v := range i.rows[0] {
+		reflect.ValueOf(dest[j]).Elem().Set(reflect.ValueOf(v))
+	}
+	i.rows = i.rows[1:]
+	return true
+}
+
+func (i *fakeIterator) Close() error { return i.err }
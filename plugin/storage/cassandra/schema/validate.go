@@ -0,0 +1,204 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/pkg/cassandra"
+)
+
+const (
+	sizeTieredCompaction = "SizeTieredCompactionStrategy"
+	timeWindowCompaction = "TimeWindowCompactionStrategy"
+)
+
+// expectedTable describes a table the span and dependency stores read from and write to.
+type expectedTable struct {
+	name       string
+	columns    map[string]string
+	compaction string
+	// previous is the table of the previous schema versions the stores fall back to when the table does not exist.
+	previous *expectedTable
+}
+
+// expectedTables are the tables used by plugin/storage/cassandra/spanstore and plugin/storage/cassandra/dependencystore.
+var expectedTables = []*expectedTable{
+	{
+		name: "traces",
+		columns: map[string]string{
+			"trace_id":       "blob",
+			"span_id":        "bigint",
+			"span_hash":      "bigint",
+			"parent_id":      "bigint",
+			"operation_name": "text",
+			"flags":          "int",
+			"start_time":     "bigint",
+			"duration":       "bigint",
+			"tags":           "list<frozen<keyvalue>>",
+			"logs":           "list<frozen<log>>",
+			"refs":           "list<frozen<span_ref>>",
+			"process":        "frozen<process>",
+		},
+		compaction: timeWindowCompaction,
+	},
+	{
+		name:       "service_names",
+		columns:    map[string]string{"service_name": "text"},
+		compaction: sizeTieredCompaction,
+	},
+	{
+		name: "operation_names_v2",
+		columns: map[string]string{
+			"service_name":   "text",
+			"span_kind":      "text",
+			"operation_name": "text",
+		},
+		compaction: sizeTieredCompaction,
+		previous: &expectedTable{
+			name: "operation_names",
+			columns: map[string]string{
+				"service_name":   "text",
+				"operation_name": "text",
+			},
+			compaction: sizeTieredCompaction,
+		},
+	},
+	{
+		name: "service_operation_index",
+		columns: map[string]string{
+			"service_name":   "text",
+			"operation_name": "text",
+			"start_time":     "bigint",
+			"trace_id":       "blob",
+		},
+		compaction: timeWindowCompaction,
+	},
+	{
+		name: "service_name_index",
+		columns: map[string]string{
+			"service_name": "text",
+			"bucket":       "int",
+			"start_time":   "bigint",
+			"trace_id":     "blob",
+		},
+		compaction: timeWindowCompaction,
+	},
+	{
+		name: "duration_index",
+		columns: map[string]string{
+			"service_name":   "text",
+			"operation_name": "text",
+			"bucket":         "timestamp",
+			"duration":       "bigint",
+			"start_time":     "bigint",
+			"trace_id":       "blob",
+		},
+		compaction: timeWindowCompaction,
+	},
+	{
+		name: "tag_index",
+		columns: map[string]string{
+			"service_name": "text",
+			"tag_key":      "text",
+			"tag_value":    "text",
+			"start_time":   "bigint",
+			"trace_id":     "blob",
+			"span_id":      "bigint",
+		},
+		compaction: timeWindowCompaction,
+	},
+	{
+		name: "dependencies_v2",
+		columns: map[string]string{
+			"ts_bucket":    "timestamp",
+			"ts":           "timestamp",
+			"dependencies": "list<frozen<dependency>>",
+		},
+		compaction: sizeTieredCompaction,
+		previous: &expectedTable{
+			name: "dependencies",
+			columns: map[string]string{
+				"ts":           "timestamp",
+				"ts_index":     "timestamp",
+				"dependencies": "list<frozen<dependency>>",
+			},
+			compaction: sizeTieredCompaction,
+		},
+	},
+}
+
+// Report is the result of the validation of the schema of a keyspace.
+type Report struct {
+	Version int
+	// Mismatches describe the differences between the schema and the one the span and dependency stores expect.
+	Mismatches []string
+}
+
+// Validate compares the schema of the keyspace with the tables, columns and compaction strategies
+// the span and dependency stores expect. The tables of older schema versions the stores still support
+// are not reported as mismatches.
+func Validate(session cassandra.Session, keyspace string) (*Report, error) {
+	k, err := readKeyspace(session, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Version: k.version(), Mismatches: k.validate()}, nil
+}
+
+func (k *keyspaceSchema) validate() []string {
+	var mismatches []string
+	for _, expected := range expectedTables {
+		table, ok := k.tables[expected.name]
+		if !ok && expected.previous != nil {
+			if table, ok = k.tables[expected.previous.name]; ok {
+				expected = expected.previous
+			}
+		}
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("table %s is missing", expected.name))
+			continue
+		}
+		mismatches = append(mismatches, table.validate(expected)...)
+	}
+	return mismatches
+}
+
+func (t *tableSchema) validate(expected *expectedTable) []string {
+	var mismatches []string
+	columns := make([]string, 0, len(expected.columns))
+	for column := range expected.columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		actual, ok := t.columns[column]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("table %s: column %s is missing", expected.name, column))
+		} else if actual != expected.columns[column] {
+			mismatches = append(mismatches, fmt.Sprintf("table %s: column %s is of type %s instead of %s",
+				expected.name, column, actual, expected.columns[column]))
+		}
+	}
+	// The compaction class is either fully qualified or the name of a strategy of org.apache.cassandra.db.compaction.
+	class := t.compaction["class"]
+	if class[strings.LastIndex(class, ".")+1:] != expected.compaction {
+		mismatches = append(mismatches, fmt.Sprintf("table %s: compaction strategy is %s instead of %s",
+			expected.name, class, expected.compaction))
+	}
+	return mismatches
+}
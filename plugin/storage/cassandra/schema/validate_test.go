@@ -0,0 +1,119 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tableOf(expected *expectedTable) *tableSchema {
+	columns := make(map[string]string, len(expected.columns))
+	for column, columnType := range expected.columns {
+		columns[column] = columnType
+	}
+	return &tableSchema{
+		columns:    columns,
+		compaction: map[string]string{"class": "org.apache.cassandra.db.compaction." + expected.compaction},
+		defaultTTL: 172800,
+	}
+}
+
+// tablesOf returns the tables of the span and dependency stores of the given schema version.
+func tablesOf(version int) map[string]*tableSchema {
+	tables := make(map[string]*tableSchema)
+	for _, expected := range expectedTables {
+		switch {
+		case expected.name == "operation_names_v2" && version < 3:
+			tables[expected.previous.name] = tableOf(expected.previous)
+		case expected.name == "dependencies_v2" && version < 2:
+			tables[expected.previous.name] = tableOf(expected.previous)
+		default:
+			tables[expected.name] = tableOf(expected)
+		}
+	}
+	return tables
+}
+
+func TestDetectVersion(t *testing.T) {
+	for version := 0; version <= LatestVersion; version++ {
+		tables := map[string]*tableSchema{}
+		if version > 0 {
+			tables = tablesOf(version)
+		}
+		actual, err := DetectVersion(newFakeSession().withKeyspace(tables, nil), "jaeger_v1_test")
+		require.NoError(t, err)
+		assert.Equal(t, version, actual)
+	}
+
+	session := newFakeSession()
+	session.errors[tablesQuery] = errors.New("unauthorized")
+	_, err := DetectVersion(session, "jaeger_v1_test")
+	assert.EqualError(t, err, "cannot read the tables of keyspace jaeger_v1_test: unauthorized")
+}
+
+func TestReadKeyspace(t *testing.T) {
+	session := newFakeSession().withKeyspace(
+		map[string]*tableSchema{"traces": tableOf(expectedTables[0])},
+		map[string][]string{"dependency": {"parent", "child", "call_count"}})
+	k, err := readKeyspace(session, "jaeger_v1_test")
+	require.NoError(t, err)
+	assert.Equal(t, expectedTables[0].columns, k.tables["traces"].columns)
+	assert.Equal(t, 172800, k.tables["traces"].defaultTTL)
+	assert.Equal(t, []string{"parent", "child", "call_count"}, k.types["dependency"])
+
+	for _, query := range []string{columnsQuery, typesQuery} {
+		session := newFakeSession()
+		session.errors[query] = errors.New("unauthorized")
+		_, err := readKeyspace(session, "jaeger_v1_test")
+		assert.Error(t, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for version := 1; version <= LatestVersion; version++ {
+		report, err := Validate(newFakeSession().withKeyspace(tablesOf(version), nil), "jaeger_v1_test")
+		require.NoError(t, err)
+		assert.Equal(t, version, report.Version)
+		assert.Empty(t, report.Mismatches, "version %d", version)
+	}
+
+	tables := tablesOf(LatestVersion)
+	delete(tables, "tag_index")
+	delete(tables["traces"].columns, "span_hash")
+	tables["service_names"].columns["service_name"] = "varchar"
+	tables["duration_index"].compaction["class"] = "LeveledCompactionStrategy"
+	report, err := Validate(newFakeSession().withKeyspace(tables, nil), "jaeger_v1_test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"table traces: column span_hash is missing",
+		"table service_names: column service_name is of type varchar instead of text",
+		"table duration_index: compaction strategy is LeveledCompactionStrategy instead of TimeWindowCompactionStrategy",
+		"table tag_index is missing",
+	}, report.Mismatches)
+
+	report, err = Validate(newFakeSession(), "jaeger_v1_test")
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Version)
+	assert.Len(t, report.Mismatches, len(expectedTables))
+
+	session := newFakeSession()
+	session.errors[tablesQuery] = errors.New("unauthorized")
+	_, err = Validate(session, "jaeger_v1_test")
+	assert.Error(t, err)
+}
@@ -68,6 +68,9 @@ const (
 	// limitMultiple exists because many spans that are returned from indices can have the same trace, limitMultiple increases
 	// the number of responses from the index, so we can respect the user's limit value they provided.
 	limitMultiple = 3
+	// queryOversampling is how many more traces are searched for when the results are post-filtered
+	// by a structured query
+	queryOversampling = 5
 )
 
 var (
@@ -237,6 +240,9 @@ func validateQuery(p *spanstore.TraceQueryParameters) error {
 
 // FindTraces retrieves traces that match the traceQuery
 func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	if traceQuery != nil && traceQuery.Query != nil {
+		return s.findTracesByQuery(ctx, traceQuery)
+	}
 	uniqueTraceIDs, err := s.FindTraceIDs(ctx, traceQuery)
 	if err != nil {
 		return nil, err
@@ -253,8 +259,35 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	return retMe, nil
 }
 
+// findTracesByQuery searches the indexes for the conditions implied by the structured query, and then
+// evaluates the whole query against the traces found.
+func (s *SpanReader) findTracesByQuery(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	filter := *traceQuery
+	if filter.NumTraces == 0 {
+		filter.NumTraces = defaultNumTraces
+	}
+	pushed := spanstore.PushDownQuery(&filter, queryOversampling)
+	if traceQuery.DurationMin == 0 && traceQuery.DurationMax == 0 {
+		// the duration index cannot be combined with the tags index and has exclusive bounds,
+		// so durations implied by the query are only post-filtered
+		pushed.DurationMin, pushed.DurationMax = 0, 0
+	}
+	traces, err := s.FindTraces(ctx, pushed)
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.FilterTraces(traces, &filter), nil
+}
+
 // FindTraceIDs retrieve traceIDs that match the traceQuery
 func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
+	if traceQuery != nil && traceQuery.Query != nil {
+		traces, err := s.findTracesByQuery(ctx, traceQuery)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.TraceIDs(traces), nil
+	}
 	if err := validateQuery(traceQuery); err != nil {
 		return nil, err
 	}
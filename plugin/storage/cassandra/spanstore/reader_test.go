@@ -34,6 +34,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/cassandra/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 type spanReaderTest struct {
@@ -180,6 +181,7 @@ func TestSpanReaderFindTraces(t *testing.T) {
 		queryTags                         bool
 		queryOperation                    bool
 		queryDuration                     bool
+		query                             string
 		mainQueryError                    error
 		tagsQueryError                    error
 		serviceNameAndOperationQueryError error
@@ -272,6 +274,16 @@ func TestSpanReaderFindTraces(t *testing.T) {
 				"duration query error",
 			},
 		},
+		{
+			caption:       "structured query",
+			query:         "NOT tag.error=true",
+			expectedCount: 2,
+		},
+		{
+			caption:       "structured query post-filter",
+			query:         "tag.error=true",
+			expectedCount: 0,
+		},
 		{
 			caption:        "load trace error",
 			loadQueryError: errors.New("load query error"),
@@ -383,6 +395,11 @@ func TestSpanReaderFindTraces(t *testing.T) {
 					queryParams.DurationMax = time.Minute * 3
 
 				}
+				if testCase.query != "" {
+					q, err := querylang.Parse(testCase.query)
+					require.NoError(t, err)
+					queryParams.Query = q
+				}
 				res, err := r.reader.FindTraces(context.Background(), queryParams)
 				if testCase.expectedError == "" {
 					assert.NoError(t, err)
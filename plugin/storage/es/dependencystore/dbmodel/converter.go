@@ -47,3 +47,49 @@ func ToDomainDependencies(dLinks []DependencyLink) []model.DependencyLink {
 	}
 	return ret
 }
+
+// FromDomainOperationDependencies converts model.OperationDependencyLink to database representation
+func FromDomainOperationDependencies(dLinks []model.OperationDependencyLink) []OperationDependencyLink {
+	if dLinks == nil {
+		return nil
+	}
+	ret := make([]OperationDependencyLink, len(dLinks))
+	for i, d := range dLinks {
+		ret[i] = OperationDependencyLink{
+			Parent:          d.Parent,
+			ParentOperation: d.ParentOperation,
+			Child:           d.Child,
+			ChildOperation:  d.ChildOperation,
+			CallCount:       d.CallCount,
+			ErrorCount:      d.ErrorCount,
+			MinDuration:     model.DurationAsMicroseconds(d.Latency.Min),
+			MaxDuration:     model.DurationAsMicroseconds(d.Latency.Max),
+			TotalDuration:   model.DurationAsMicroseconds(d.Latency.Total),
+		}
+	}
+	return ret
+}
+
+// ToDomainOperationDependencies converts database representation of operation dependencies to model.OperationDependencyLink
+func ToDomainOperationDependencies(dLinks []OperationDependencyLink) []model.OperationDependencyLink {
+	if dLinks == nil {
+		return nil
+	}
+	ret := make([]model.OperationDependencyLink, len(dLinks))
+	for i, d := range dLinks {
+		ret[i] = model.OperationDependencyLink{
+			Parent:          d.Parent,
+			ParentOperation: d.ParentOperation,
+			Child:           d.Child,
+			ChildOperation:  d.ChildOperation,
+			CallCount:       d.CallCount,
+			ErrorCount:      d.ErrorCount,
+			Latency: model.LatencySummary{
+				Min:   model.MicrosecondsAsDuration(d.MinDuration),
+				Max:   model.MicrosecondsAsDuration(d.MaxDuration),
+				Total: model.MicrosecondsAsDuration(d.TotalDuration),
+			},
+		}
+	}
+	return ret
+}
@@ -17,6 +17,7 @@ package dbmodel
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -49,3 +50,35 @@ func TestConvertDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertOperationDependencies(t *testing.T) {
+	tests := []struct {
+		dLinks []model.OperationDependencyLink
+	}{
+		{
+			dLinks: []model.OperationDependencyLink{{
+				Parent:          "foo",
+				ParentOperation: "get",
+				Child:           "bar",
+				ChildOperation:  "put",
+				CallCount:       3,
+				ErrorCount:      1,
+				Latency:         model.LatencySummary{Min: time.Millisecond, Max: 3 * time.Millisecond, Total: 6 * time.Millisecond},
+			}},
+		},
+		{
+			dLinks: []model.OperationDependencyLink{},
+		},
+		{
+			dLinks: nil,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			got := FromDomainOperationDependencies(test.dLinks)
+			a := ToDomainOperationDependencies(got)
+			assert.Equal(t, test.dLinks, a)
+		})
+	}
+}
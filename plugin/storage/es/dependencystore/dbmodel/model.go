@@ -20,6 +20,8 @@ import "time"
 type TimeDependencies struct {
 	Timestamp    time.Time        `json:"timestamp"`
 	Dependencies []DependencyLink `json:"dependencies"`
+	// OperationDependencies are stored in documents of their own, where Dependencies is empty
+	OperationDependencies []OperationDependencyLink `json:"operationDependencies,omitempty"`
 }
 
 // DependencyLink shows dependencies between services
@@ -28,3 +30,17 @@ type DependencyLink struct {
 	Child     string `json:"child"`
 	CallCount uint64 `json:"callCount"`
 }
+
+// OperationDependencyLink is a dependency between operations of different services,
+// the durations are in microseconds like the durations of the spans.
+type OperationDependencyLink struct {
+	Parent          string `json:"parent"`
+	ParentOperation string `json:"parentOperation"`
+	Child           string `json:"child"`
+	ChildOperation  string `json:"childOperation"`
+	CallCount       uint64 `json:"callCount"`
+	ErrorCount      uint64 `json:"errorCount"`
+	MinDuration     uint64 `json:"minDuration"`
+	MaxDuration     uint64 `json:"maxDuration"`
+	TotalDuration   uint64 `json:"totalDuration"`
+}
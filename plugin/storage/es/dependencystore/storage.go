@@ -28,12 +28,16 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
 const (
-	dependencyType  = "dependencies"
-	dependencyIndex = "jaeger-dependencies-"
+	dependencyType             = "dependencies"
+	dependencyIndex            = "jaeger-dependencies-"
+	timestampField             = "timestamp"
+	operationDependenciesField = "operationDependencies"
 )
 
 // DependencyStore handles all queries and insertions to ElasticSearch dependencies
@@ -62,7 +66,12 @@ func NewDependencyStore(client es.Client, logger *zap.Logger, indexPrefix, index
 
 // WriteDependencies implements dependencystore.Writer#WriteDependencies.
 func (s *DependencyStore) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
-	indexName := indexWithDate(s.indexPrefix, s.indexDateLayout, ts)
+	return s.WriteTenantDependencies(context.Background(), ts, dependencies)
+}
+
+// WriteTenantDependencies implements dependencystore.TenantWriter#WriteTenantDependencies.
+func (s *DependencyStore) WriteTenantDependencies(ctx context.Context, ts time.Time, dependencies []model.DependencyLink) error {
+	indexName := tenantIndex(ctx, indexWithDate(s.indexPrefix, s.indexDateLayout, ts))
 	s.writeDependencies(indexName, ts, dependencies)
 	return nil
 }
@@ -83,33 +92,110 @@ func (s *DependencyStore) writeDependencies(indexName string, ts time.Time, depe
 		}).Add()
 }
 
-// GetDependencies returns all interservice dependencies
+// GetDependencies returns all interservice dependencies, summing the call counts of the links
+// between the same services written in several documents, e.g. by each flush of each collector.
 func (s *DependencyStore) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
-	indices := getIndices(s.indexPrefix, s.indexDateLayout, endTs, lookback)
-	searchResult, err := s.client.Search(indices...).
-		Size(s.maxDocCount).
-		Query(buildTSQuery(endTs, lookback)).
-		IgnoreUnavailable(true).
-		Do(ctx)
+	query := elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery(operationDependenciesField))
+	docs, err := s.searchDependencies(ctx, query, endTs, lookback)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search for dependencies: %w", err)
+		return nil, err
+	}
+	return dbmodel.ToDomainDependencies(mergeDependencies(docs)), nil
+}
+
+// mergeDependencies returns the links of the documents, in the order they are first seen,
+// with the call counts of the links between the same services summed.
+func mergeDependencies(docs []dbmodel.TimeDependencies) []dbmodel.DependencyLink {
+	type linkKey struct {
+		parent, child string
+	}
+	var links []dbmodel.DependencyLink
+	indices := make(map[linkKey]int)
+	for _, tToD := range docs {
+		for _, link := range tToD.Dependencies {
+			key := linkKey{parent: link.Parent, child: link.Child}
+			if i, ok := indices[key]; ok {
+				links[i].CallCount += link.CallCount
+				continue
+			}
+			indices[key] = len(links)
+			links = append(links, link)
+		}
 	}
+	return links
+}
+
+// WriteOperationDependencies implements dependencystore.OperationWriter#WriteOperationDependencies.
+func (s *DependencyStore) WriteOperationDependencies(ctx context.Context, ts time.Time, dependencies []model.OperationDependencyLink) error {
+	indexName := tenantIndex(ctx, indexWithDate(s.indexPrefix, s.indexDateLayout, ts))
+	s.client.Index().Index(indexName).Type(dependencyType).
+		BodyJson(&dbmodel.TimeDependencies{Timestamp: ts,
+			OperationDependencies: dbmodel.FromDomainOperationDependencies(dependencies),
+		}).Add()
+	return nil
+}
 
-	var retDependencies []dbmodel.DependencyLink
-	hits := searchResult.Hits.Hits
-	for _, hit := range hits {
-		source := hit.Source
-		var tToD dbmodel.TimeDependencies
-		if err := json.Unmarshal(*source, &tToD); err != nil {
-			return nil, errors.New("unmarshalling ElasticSearch documents failed")
+// GetOperationDependencies returns all dependencies between operations of different services,
+// implements dependencystore.OperationReader
+func (s *DependencyStore) GetOperationDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.OperationDependencyLink, error) {
+	query := elastic.NewExistsQuery(operationDependenciesField)
+	docs, err := s.searchDependencies(ctx, query, endTs, lookback)
+	if err != nil {
+		return nil, err
+	}
+	links := dependencystore.NewOperationLinks()
+	for _, tToD := range docs {
+		for _, link := range dbmodel.ToDomainOperationDependencies(tToD.OperationDependencies) {
+			links.Add(link)
 		}
-		retDependencies = append(retDependencies, tToD.Dependencies...)
 	}
-	return dbmodel.ToDomainDependencies(retDependencies), nil
+	return links.Links(), nil
 }
 
-func buildTSQuery(endTs time.Time, lookback time.Duration) elastic.Query {
-	return elastic.NewRangeQuery("timestamp").Gte(endTs.Add(-lookback)).Lte(endTs)
+// searchDependencies returns the documents within the lookback matching the query. They are read in pages
+// of maxDocCount sorted by timestamp, each page starting at the timestamp of the last document of the previous
+// one and excluding the documents already read at that timestamp, as the documents have no unique field to sort by.
+func (s *DependencyStore) searchDependencies(ctx context.Context, query elastic.Query, endTs time.Time, lookback time.Duration) ([]dbmodel.TimeDependencies, error) {
+	indices := getIndices(s.indexPrefix, s.indexDateLayout, endTs, lookback)
+	for i, index := range indices {
+		indices[i] = tenantIndex(ctx, index)
+	}
+	var docs []dbmodel.TimeDependencies
+	from := endTs.Add(-lookback)
+	var readAtFrom []string // ids of the documents already read at the timestamp from
+	for {
+		pageQuery := elastic.NewBoolQuery().Filter(query, elastic.NewRangeQuery(timestampField).Gte(from).Lte(endTs))
+		if len(readAtFrom) > 0 {
+			pageQuery.MustNot(elastic.NewIdsQuery().Ids(readAtFrom...))
+		}
+		searchResult, err := s.client.Search(indices...).
+			Size(s.maxDocCount).
+			Query(pageQuery).
+			Sort(timestampField, true).
+			IgnoreUnavailable(true).
+			Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for dependencies: %w", err)
+		}
+
+		hits := searchResult.Hits.Hits
+		for _, hit := range hits {
+			source := hit.Source
+			var tToD dbmodel.TimeDependencies
+			if err := json.Unmarshal(*source, &tToD); err != nil {
+				return nil, errors.New("unmarshalling ElasticSearch documents failed")
+			}
+			docs = append(docs, tToD)
+			if !tToD.Timestamp.Equal(from) {
+				from = tToD.Timestamp
+				readAtFrom = nil
+			}
+			readAtFrom = append(readAtFrom, hit.Id)
+		}
+		if len(hits) == 0 || len(hits) < s.maxDocCount {
+			return docs, nil
+		}
+	}
 }
 
 func getIndices(prefix, dateLayout string, ts time.Time, lookback time.Duration) []string {
@@ -127,3 +213,12 @@ func getIndices(prefix, dateLayout string, ts time.Time, lookback time.Duration)
 func indexWithDate(indexNamePrefix, indexDateLayout string, date time.Time) string {
 	return indexNamePrefix + date.UTC().Format(indexDateLayout)
 }
+
+// tenantIndex returns the index name of the tenant carried by the context.
+// The indices of the default tenant are not prefixed.
+func tenantIndex(ctx context.Context, index string) string {
+	if tenant := tenancy.GetTenant(ctx); tenant != "" {
+		return tenant + "-" + index
+	}
+	return index
+}
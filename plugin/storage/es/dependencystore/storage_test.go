@@ -26,11 +26,14 @@ import (
 	"github.com/olivere/elastic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 )
 
@@ -55,8 +58,11 @@ func withDepStorage(indexPrefix, indexDateLayout string, maxDocCount int, fn fun
 	fn(r)
 }
 
-var _ dependencystore.Reader = &DependencyStore{} // check API conformance
-var _ dependencystore.Writer = &DependencyStore{} // check API conformance
+var _ dependencystore.Reader = &DependencyStore{}          // check API conformance
+var _ dependencystore.Writer = &DependencyStore{}          // check API conformance
+var _ dependencystore.OperationReader = &DependencyStore{} // check API conformance
+var _ dependencystore.OperationWriter = &DependencyStore{} // check API conformance
+var _ dependencystore.TenantWriter = &DependencyStore{}    // check API conformance
 
 func TestNewSpanReaderIndexPrefix(t *testing.T) {
 	testCases := []struct {
@@ -175,6 +181,7 @@ func TestGetDependencies(t *testing.T) {
 				return size == testCase.maxDocCount
 			})).Return(searchService)
 			searchService.On("Query", mock.Anything).Return(searchService)
+			searchService.On("Sort", timestampField, true).Return(searchService)
 			searchService.On("IgnoreUnavailable", mock.AnythingOfType("bool")).Return(searchService)
 			searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
 
@@ -190,6 +197,262 @@ func TestGetDependencies(t *testing.T) {
 	}
 }
 
+func TestWriteOperationDependencies(t *testing.T) {
+	withDepStorage("", "2006-01-02", defaultMaxDocCount, func(r *depStorageTest) {
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		indexName := indexWithDate("", "2006-01-02", fixedTime)
+		writeService := &mocks.IndexService{}
+		links := []model.OperationDependencyLink{{Parent: "hello", ParentOperation: "a", Child: "world", ChildOperation: "b", CallCount: 1}}
+
+		r.client.On("Index").Return(writeService)
+		writeService.On("Index", stringMatcher(indexName)).Return(writeService)
+		writeService.On("Type", stringMatcher(dependencyType)).Return(writeService)
+		writeService.On("BodyJson", &dbmodel.TimeDependencies{
+			Timestamp:             fixedTime,
+			OperationDependencies: dbmodel.FromDomainOperationDependencies(links),
+		}).Return(writeService)
+		writeService.On("Add", mock.Anything).Return(nil, nil)
+		assert.NoError(t, r.storage.WriteOperationDependencies(context.Background(), fixedTime, links))
+		writeService.AssertExpectations(t)
+	})
+}
+
+func TestGetOperationDependencies(t *testing.T) {
+	operationDependencies :=
+		`{
+			"ts": 798434479000000,
+			"operationDependencies": [
+				{ "parent": "hello",
+				  "parentOperation": "a",
+				  "child": "world",
+				  "childOperation": "b",
+				  "callCount": 12,
+				  "errorCount": 2,
+				  "minDuration": 1000,
+				  "maxDuration": 5000,
+				  "totalDuration": 24000
+				}
+			]
+		}`
+
+	testCases := []struct {
+		searchResult   *elastic.SearchResult
+		searchError    error
+		expectedError  string
+		expectedOutput []model.OperationDependencyLink
+	}{
+		{
+			searchResult: createSearchResults(operationDependencies, operationDependencies),
+			expectedOutput: []model.OperationDependencyLink{
+				{
+					Parent:          "hello",
+					ParentOperation: "a",
+					Child:           "world",
+					ChildOperation:  "b",
+					CallCount:       24,
+					ErrorCount:      4,
+					Latency:         model.LatencySummary{Min: time.Millisecond, Max: 5 * time.Millisecond, Total: 48 * time.Millisecond},
+				},
+			},
+		},
+		{
+			searchResult:  createSearchResult(`badJson{hello}world`),
+			expectedError: "unmarshalling ElasticSearch documents failed",
+		},
+		{
+			searchError:   errors.New("search failure"),
+			expectedError: "failed to search for dependencies: search failure",
+		},
+	}
+	for _, testCase := range testCases {
+		withDepStorage("", "2006-01-02", defaultMaxDocCount, func(r *depStorageTest) {
+			fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+
+			searchService := &mocks.SearchService{}
+			r.client.On("Search", "jaeger-dependencies-1995-04-21", "jaeger-dependencies-1995-04-20").Return(searchService)
+			searchService.On("Size", defaultMaxDocCount).Return(searchService)
+			searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(searchService)
+			searchService.On("Sort", timestampField, true).Return(searchService)
+			searchService.On("IgnoreUnavailable", true).Return(searchService)
+			searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+
+			actual, err := r.storage.GetOperationDependencies(context.Background(), fixedTime, 24*time.Hour)
+			if testCase.expectedError != "" {
+				assert.EqualError(t, err, testCase.expectedError)
+				assert.Nil(t, actual)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestGetOperationDependenciesPages(t *testing.T) {
+	withDepStorage("", "2006-01-02", 1, func(r *depStorageTest) {
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		doc := `{"timestamp": "1995-04-21T03:00:00Z", "operationDependencies": [{"parent": "hello", "parentOperation": "a", "child": "world", "childOperation": "b", "callCount": 12}]}`
+		pages := [][]*elastic.SearchHit{
+			{createSearchHit("x", doc)},
+			{createSearchHit("y", doc)},
+			{},
+		}
+
+		var queries []string
+		searchService := &mocks.SearchService{}
+		r.client.On("Search", "jaeger-dependencies-1995-04-21", "jaeger-dependencies-1995-04-20").Return(searchService)
+		searchService.On("Size", 1).Return(searchService)
+		searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Run(func(args mock.Arguments) {
+			source, err := args.Get(0).(elastic.Query).Source()
+			require.NoError(t, err)
+			query, err := json.Marshal(source)
+			require.NoError(t, err)
+			queries = append(queries, string(query))
+		}).Return(searchService)
+		searchService.On("Sort", timestampField, true).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		for _, hits := range pages {
+			searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}, nil).Once()
+		}
+
+		actual, err := r.storage.GetOperationDependencies(context.Background(), fixedTime, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.OperationDependencyLink{
+			{Parent: "hello", ParentOperation: "a", Child: "world", ChildOperation: "b", CallCount: 24},
+		}, actual)
+		require.Len(t, queries, 3)
+		assert.Contains(t, queries[0], `{"exists":{"field":"operationDependencies"}}`)
+		assert.Contains(t, queries[1], `"ids":{"values":["x"]}`)
+		assert.Contains(t, queries[2], `"ids":{"values":["x","y"]}`)
+	})
+}
+
+func TestWriteAndGetOperationDependencies(t *testing.T) {
+	withDepStorage("", "2006-01-02", defaultMaxDocCount, func(r *depStorageTest) {
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		links := []model.OperationDependencyLink{
+			{
+				Parent:          "frontend",
+				ParentOperation: "GET /dispatch",
+				Child:           "backend",
+				ChildOperation:  "FindNearest",
+				CallCount:       3,
+				ErrorCount:      1,
+				Latency:         model.LatencySummary{Min: time.Millisecond, Max: 5 * time.Millisecond, Total: 9 * time.Millisecond},
+			},
+		}
+
+		// the document indexed by the writer is the one returned by the search of the reader
+		var doc json.RawMessage
+		writeService := &mocks.IndexService{}
+		r.client.On("Index").Return(writeService)
+		writeService.On("Index", stringMatcher("jaeger-dependencies-1995-04-21")).Return(writeService)
+		writeService.On("Type", stringMatcher(dependencyType)).Return(writeService)
+		writeService.On("BodyJson", mock.Anything).Run(func(args mock.Arguments) {
+			var err error
+			doc, err = json.Marshal(args.Get(0))
+			require.NoError(t, err)
+		}).Return(writeService)
+		writeService.On("Add", mock.Anything).Return(nil, nil)
+		require.NoError(t, r.storage.WriteOperationDependencies(context.Background(), fixedTime, links))
+
+		searchService := &mocks.SearchService{}
+		r.client.On("Search", "jaeger-dependencies-1995-04-21", "jaeger-dependencies-1995-04-20").Return(searchService)
+		searchService.On("Size", defaultMaxDocCount).Return(searchService)
+		searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Return(searchService)
+		searchService.On("Sort", timestampField, true).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		searchService.On("Do", mock.Anything).Return(createSearchResults(string(doc)), nil)
+
+		actual, err := r.storage.GetOperationDependencies(context.Background(), fixedTime, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, links, actual)
+	})
+}
+
+func TestWriteAndGetTenantDependencies(t *testing.T) {
+	withDepStorage("", "2006-01-02", defaultMaxDocCount, func(r *depStorageTest) {
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		links := []model.DependencyLink{{Parent: "frontend", Child: "backend", CallCount: 3}}
+
+		var doc json.RawMessage
+		writeService := &mocks.IndexService{}
+		r.client.On("Index").Return(writeService)
+		writeService.On("Index", stringMatcher("acme-jaeger-dependencies-1995-04-21")).Return(writeService)
+		writeService.On("Type", stringMatcher(dependencyType)).Return(writeService)
+		writeService.On("BodyJson", mock.Anything).Run(func(args mock.Arguments) {
+			var err error
+			doc, err = json.Marshal(args.Get(0))
+			require.NoError(t, err)
+		}).Return(writeService)
+		writeService.On("Add", mock.Anything).Return(nil, nil)
+		require.NoError(t, r.storage.WriteTenantDependencies(ctx, fixedTime, links))
+
+		searchService := &mocks.SearchService{}
+		r.client.On("Search", "acme-jaeger-dependencies-1995-04-21", "acme-jaeger-dependencies-1995-04-20").Return(searchService)
+		searchService.On("Size", defaultMaxDocCount).Return(searchService)
+		searchService.On("Query", mock.Anything).Return(searchService)
+		searchService.On("Sort", timestampField, true).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		searchService.On("Do", mock.Anything).Return(createSearchResults(string(doc)), nil)
+
+		actual, err := r.storage.GetDependencies(ctx, fixedTime, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, links, actual)
+	})
+}
+
+func TestGetDependenciesPages(t *testing.T) {
+	withDepStorage("", "2006-01-02", 2, func(r *depStorageTest) {
+		fixedTime := time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+		// the documents written by two collectors at the same time span the first and the second page
+		pages := [][]*elastic.SearchHit{
+			{
+				createSearchHit("a", `{"timestamp": "1995-04-21T02:00:00Z", "dependencies": [{"parent": "frontend", "child": "backend", "callCount": 3}]}`),
+				createSearchHit("b", `{"timestamp": "1995-04-21T03:00:00Z", "dependencies": [{"parent": "frontend", "child": "backend", "callCount": 2}]}`),
+			},
+			{
+				createSearchHit("c", `{"timestamp": "1995-04-21T03:00:00Z", "dependencies": [{"parent": "backend", "child": "db", "callCount": 1}]}`),
+			},
+		}
+
+		var queries []string
+		searchService := &mocks.SearchService{}
+		r.client.On("Search", "jaeger-dependencies-1995-04-21", "jaeger-dependencies-1995-04-20").Return(searchService)
+		searchService.On("Size", 2).Return(searchService)
+		searchService.On("Query", mock.AnythingOfType("*elastic.BoolQuery")).Run(func(args mock.Arguments) {
+			source, err := args.Get(0).(elastic.Query).Source()
+			require.NoError(t, err)
+			query, err := json.Marshal(source)
+			require.NoError(t, err)
+			queries = append(queries, string(query))
+		}).Return(searchService)
+		searchService.On("Sort", timestampField, true).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		for _, hits := range pages {
+			searchService.On("Do", mock.Anything).Return(&elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}, nil).Once()
+		}
+
+		actual, err := r.storage.GetDependencies(context.Background(), fixedTime, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []model.DependencyLink{
+			{Parent: "frontend", Child: "backend", CallCount: 5},
+			{Parent: "backend", Child: "db", CallCount: 1},
+		}, actual)
+		require.Len(t, queries, 2)
+		assert.Contains(t, queries[0], `"must_not":{"exists":{"field":"operationDependencies"}}`)
+		assert.NotContains(t, queries[0], `"ids"`)
+		assert.Contains(t, queries[1], `"from":"1995-04-21T03:00:00Z"`)
+		assert.Contains(t, queries[1], `"ids":{"values":["b"]}`)
+	})
+}
+
+func createSearchHit(id, doc string) *elastic.SearchHit {
+	raw := json.RawMessage(doc)
+	return &elastic.SearchHit{Id: id, Source: &raw}
+}
+
 func createSearchResult(dependencyLink string) *elastic.SearchResult {
 	dependencyLinkRaw := []byte(dependencyLink)
 	hits := make([]*elastic.SearchHit, 1)
@@ -200,6 +463,15 @@ func createSearchResult(dependencyLink string) *elastic.SearchResult {
 	return searchResult
 }
 
+func createSearchResults(docs ...string) *elastic.SearchResult {
+	hits := make([]*elastic.SearchHit, len(docs))
+	for i, doc := range docs {
+		raw := json.RawMessage(doc)
+		hits[i] = &elastic.SearchHit{Source: &raw}
+	}
+	return &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}
+}
+
 func TestGetIndices(t *testing.T) {
 	fixedTime := time.Date(1995, time.April, 21, 4, 12, 19, 95, time.UTC)
 	testCases := []struct {
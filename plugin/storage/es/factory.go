@@ -16,6 +16,7 @@
 package es
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -24,12 +25,19 @@ import (
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/pkg/es"
 	"github.com/jaegertracing/jaeger/pkg/es/config"
+	"github.com/jaegertracing/jaeger/pkg/hostname"
+	esLock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/es"
 	esDepStore "github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
+	esSamplingStore "github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore"
+	esSpanMetricsStore "github.com/jaegertracing/jaeger/plugin/storage/es/spanmetricsstore"
 	esSpanStore "github.com/jaegertracing/jaeger/plugin/storage/es/spanstore"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -114,6 +122,115 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return reader, nil
 }
 
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	cfg := f.primaryConfig
+	if cfg.GetUseILM() && !cfg.GetUseReadWriteAliases() {
+		return nil, fmt.Errorf("--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
+	}
+	store := esSamplingStore.NewSamplingStore(esSamplingStore.SamplingStoreParams{
+		Client:              f.primaryClient,
+		Logger:              f.logger,
+		IndexPrefix:         cfg.GetIndexPrefix(),
+		IndexDateLayout:     cfg.GetIndexDateLayoutSampling(),
+		MaxDocCount:         cfg.GetMaxDocCount(),
+		UseReadWriteAliases: cfg.GetUseReadWriteAliases(),
+	})
+	if cfg.IsCreateIndexTemplates() {
+		mappingBuilder := mappings.MappingBuilder{
+			TemplateBuilder: es.TextTemplateBuilder{},
+			Shards:          cfg.GetNumShards(),
+			Replicas:        cfg.GetNumReplicas(),
+			EsVersion:       cfg.GetVersion(),
+			IndexPrefix:     cfg.GetIndexPrefix(),
+			UseILM:          cfg.GetUseILM(),
+		}
+		samplingMapping, err := mappingBuilder.GetSamplingMappings()
+		if err != nil {
+			return nil, err
+		}
+		if err := store.CreateTemplates(samplingMapping); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// CreateSpanMetricsWriter implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsWriter() (metricsstore.SpanMetricsWriter, error) {
+	store, err := f.createSpanMetricsStore()
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// CreateSpanMetricsReader implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsReader() (metricsstore.SpanMetricsReader, error) {
+	store, err := f.createSpanMetricsStore()
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (f *Factory) createSpanMetricsStore() (*esSpanMetricsStore.SpanMetricsStore, error) {
+	cfg := f.primaryConfig
+	if cfg.GetUseILM() && !cfg.GetUseReadWriteAliases() {
+		return nil, fmt.Errorf("--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
+	}
+	store := esSpanMetricsStore.NewSpanMetricsStore(esSpanMetricsStore.SpanMetricsStoreParams{
+		Client:              f.primaryClient,
+		Logger:              f.logger,
+		IndexPrefix:         cfg.GetIndexPrefix(),
+		IndexDateLayout:     cfg.GetIndexDateLayoutSpanMetrics(),
+		MaxDocCount:         cfg.GetMaxDocCount(),
+		UseReadWriteAliases: cfg.GetUseReadWriteAliases(),
+	})
+	if cfg.IsCreateIndexTemplates() {
+		mappingBuilder := mappings.MappingBuilder{
+			TemplateBuilder: es.TextTemplateBuilder{},
+			Shards:          cfg.GetNumShards(),
+			Replicas:        cfg.GetNumReplicas(),
+			EsVersion:       cfg.GetVersion(),
+			IndexPrefix:     cfg.GetIndexPrefix(),
+			UseILM:          cfg.GetUseILM(),
+		}
+		spanMetricsMapping, err := mappingBuilder.GetSpanMetricsMappings()
+		if err != nil {
+			return nil, err
+		}
+		if err := store.CreateTemplates(spanMetricsMapping); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := hostname.AsIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info("Using unique participantName in the distributed lock", zap.String("participantName", hostname))
+
+	return esLock.NewLock(f.primaryClient, f.primaryConfig.GetIndexPrefix(), hostname), nil
+}
+
+// ValidateTenantIsolation implements storage.TenantIsolationFactory. The data of the tenants is stored
+// in indices prefixed by the tenant, which cannot be used with the read and write aliases as neither
+// es-rollover nor es-index-cleaner manages aliases per tenant.
+func (f *Factory) ValidateTenantIsolation() error {
+	if f.primaryConfig.GetUseReadWriteAliases() {
+		return errors.New("--es.use-aliases cannot be used with multi-tenancy, the aliases of the indices of the tenants are not managed")
+	}
+	if f.archiveConfig.IsStorageEnabled() && f.archiveConfig.GetUseReadWriteAliases() {
+		return errors.New("--es-archive.use-aliases cannot be used with multi-tenancy, the aliases of the indices of the tenants are not managed")
+	}
+	return nil
+}
+
 // CreateArchiveSpanReader implements storage.ArchiveFactory
 func (f *Factory) CreateArchiveSpanReader() (spanstore.Reader, error) {
 	if !f.archiveConfig.IsStorageEnabled() {
@@ -33,7 +33,11 @@ import (
 	"github.com/jaegertracing/jaeger/storage"
 )
 
-var _ storage.Factory = new(Factory)
+var (
+	_ storage.Factory                 = new(Factory)
+	_ storage.SamplingStoreFactory    = new(Factory)
+	_ storage.SpanMetricsStoreFactory = new(Factory)
+)
 
 type mockClientBuilder struct {
 	escfg.Configuration
@@ -86,6 +90,18 @@ func TestElasticsearchFactory(t *testing.T) {
 
 	_, err = f.CreateArchiveSpanWriter()
 	assert.NoError(t, err)
+
+	_, err = f.CreateSamplingStore()
+	assert.NoError(t, err)
+
+	_, err = f.CreateSpanMetricsWriter()
+	assert.NoError(t, err)
+
+	_, err = f.CreateSpanMetricsReader()
+	assert.NoError(t, err)
+
+	_, err = f.CreateLock()
+	assert.NoError(t, err)
 	assert.NoError(t, f.Close())
 }
 
@@ -115,6 +131,27 @@ func TestElasticsearchILMUsedWithoutReadWriteAliases(t *testing.T) {
 	r, err := f.CreateSpanReader()
 	require.EqualError(t, err, "--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
 	assert.Nil(t, r)
+
+	s, err := f.CreateSamplingStore()
+	require.EqualError(t, err, "--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
+	assert.Nil(t, s)
+
+	smw, err := f.CreateSpanMetricsWriter()
+	require.EqualError(t, err, "--es.use-ilm must always be used in conjunction with --es.use-aliases to ensure ES writers and readers refer to the single index mapping")
+	assert.Nil(t, smw)
+}
+
+func TestElasticsearchTenantIsolation(t *testing.T) {
+	f := NewFactory()
+	f.primaryConfig = &mockClientBuilder{}
+	f.archiveConfig = &mockClientBuilder{}
+	assert.NoError(t, f.ValidateTenantIsolation())
+
+	f.archiveConfig = &mockClientBuilder{Configuration: escfg.Configuration{Enabled: true, UseReadWriteAliases: true}}
+	assert.EqualError(t, f.ValidateTenantIsolation(), "--es-archive.use-aliases cannot be used with multi-tenancy, the aliases of the indices of the tenants are not managed")
+
+	f.primaryConfig = &mockClientBuilder{Configuration: escfg.Configuration{UseReadWriteAliases: true}}
+	assert.EqualError(t, f.ValidateTenantIsolation(), "--es.use-aliases cannot be used with multi-tenancy, the aliases of the indices of the tenants are not managed")
 }
 
 func TestTagKeysAsFields(t *testing.T) {
@@ -183,6 +220,12 @@ func TestCreateTemplateError(t *testing.T) {
 	w, err := f.CreateSpanWriter()
 	assert.Nil(t, w)
 	assert.Error(t, err, "template-error")
+	s, err := f.CreateSamplingStore()
+	assert.Nil(t, s)
+	assert.EqualError(t, err, "template-error")
+	smr, err := f.CreateSpanMetricsReader()
+	assert.Nil(t, smr)
+	assert.EqualError(t, err, "template-error")
 }
 
 func TestArchiveDisabled(t *testing.T) {
@@ -63,6 +63,16 @@ func (mb *MappingBuilder) GetDependenciesMappings() (string, error) {
 	return mb.GetMapping("jaeger-dependencies")
 }
 
+// GetSamplingMappings returns adaptive sampling mappings
+func (mb *MappingBuilder) GetSamplingMappings() (string, error) {
+	return mb.GetMapping("jaeger-sampling")
+}
+
+// GetSpanMetricsMappings returns span metrics mappings
+func (mb *MappingBuilder) GetSpanMetricsMappings() (string, error) {
+	return mb.GetMapping("jaeger-spanmetrics")
+}
+
 func loadMapping(name string) string {
 	s, _ := MAPPINGS.ReadFile(name)
 	return string(s)
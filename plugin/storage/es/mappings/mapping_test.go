@@ -44,6 +44,10 @@ func TestMappingBuilder_GetMapping(t *testing.T) {
 		{mapping: "jaeger-service", esVersion: 6},
 		{mapping: "jaeger-dependencies", esVersion: 7},
 		{mapping: "jaeger-dependencies", esVersion: 6},
+		{mapping: "jaeger-sampling", esVersion: 7},
+		{mapping: "jaeger-sampling", esVersion: 6},
+		{mapping: "jaeger-spanmetrics", esVersion: 7},
+		{mapping: "jaeger-spanmetrics", esVersion: 6},
 	}
 	for _, tt := range tests {
 		t.Run(tt.mapping, func(t *testing.T) {
@@ -82,6 +86,10 @@ func TestMappingBuilder_loadMapping(t *testing.T) {
 		{name: "jaeger-service-7.json"},
 		{name: "jaeger-dependencies.json"},
 		{name: "jaeger-dependencies-7.json"},
+		{name: "jaeger-sampling.json"},
+		{name: "jaeger-sampling-7.json"},
+		{name: "jaeger-spanmetrics.json"},
+		{name: "jaeger-spanmetrics-7.json"},
 	}
 	for _, test := range tests {
 		mapping := loadMapping(test.name)
@@ -322,3 +330,29 @@ func TestMappingBuilder_GetDependenciesMappings(t *testing.T) {
 	_, err := mappingBuilder.GetDependenciesMappings()
 	assert.EqualError(t, err, "template load error")
 }
+
+func TestMappingBuilder_GetSamplingMappings(t *testing.T) {
+	tb := mocks.TemplateBuilder{}
+	ta := mocks.TemplateApplier{}
+	ta.On("Execute", mock.Anything, mock.Anything).Return(errors.New("template load error"))
+	tb.On("Parse", mock.Anything).Return(&ta, nil)
+
+	mappingBuilder := MappingBuilder{
+		TemplateBuilder: &tb,
+	}
+	_, err := mappingBuilder.GetSamplingMappings()
+	assert.EqualError(t, err, "template load error")
+}
+
+func TestMappingBuilder_GetSpanMetricsMappings(t *testing.T) {
+	tb := mocks.TemplateBuilder{}
+	ta := mocks.TemplateApplier{}
+	ta.On("Execute", mock.Anything, mock.Anything).Return(errors.New("template load error"))
+	tb.On("Parse", mock.Anything).Return(&ta, nil)
+
+	mappingBuilder := MappingBuilder{
+		TemplateBuilder: &tb,
+	}
+	_, err := mappingBuilder.GetSpanMetricsMappings()
+	assert.EqualError(t, err, "template load error")
+}
@@ -345,6 +345,10 @@ func initFromViper(cfg *namespaceConfig, v *viper.Viper) {
 
 	// Dependencies calculation should be daily, and this index size is very small
 	cfg.IndexDateLayoutDependencies = initDateLayout(defaultIndexRolloverFrequency, separator)
+	// The adaptive sampling data is small as well
+	cfg.IndexDateLayoutSampling = initDateLayout(defaultIndexRolloverFrequency, separator)
+	// The span metrics are aggregated per service and operation, so they are small too
+	cfg.IndexDateLayoutSpanMetrics = initDateLayout(defaultIndexRolloverFrequency, separator)
 }
 
 // GetPrimary returns primary configuration.
@@ -95,6 +95,8 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, "test,tags", primary.Tags.Include)
 	assert.Equal(t, "20060102", primary.IndexDateLayoutServices)
 	assert.Equal(t, "2006010215", primary.IndexDateLayoutSpans)
+	assert.Equal(t, "20060102", primary.IndexDateLayoutSampling)
+	assert.Equal(t, "20060102", primary.IndexDateLayoutSpanMetrics)
 	aux := opts.Get("es.aux")
 	assert.Equal(t, []string{"3.3.3.3", "4.4.4.4"}, aux.Servers)
 	assert.Equal(t, "hello", aux.Username)
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import (
+	"sort"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+// FromDomainThroughput converts model throughput to database representation
+func FromDomainThroughput(throughput []*model.Throughput) []Throughput {
+	if throughput == nil {
+		return nil
+	}
+	ret := make([]Throughput, len(throughput))
+	for i, t := range throughput {
+		probabilities := make([]string, 0, len(t.Probabilities))
+		for p := range t.Probabilities {
+			probabilities = append(probabilities, p)
+		}
+		sort.Strings(probabilities)
+		ret[i] = Throughput{
+			Service:       t.Service,
+			Operation:     t.Operation,
+			Count:         t.Count,
+			Probabilities: probabilities,
+		}
+	}
+	return ret
+}
+
+// ToDomainThroughput converts database representation of throughput to model
+func ToDomainThroughput(throughput []Throughput) []*model.Throughput {
+	if throughput == nil {
+		return nil
+	}
+	ret := make([]*model.Throughput, len(throughput))
+	for i, t := range throughput {
+		probabilities := make(map[string]struct{}, len(t.Probabilities))
+		for _, p := range t.Probabilities {
+			probabilities[p] = struct{}{}
+		}
+		ret[i] = &model.Throughput{
+			Service:       t.Service,
+			Operation:     t.Operation,
+			Count:         t.Count,
+			Probabilities: probabilities,
+		}
+	}
+	return ret
+}
+
+// FromDomainProbabilitiesAndQPS converts model probabilities and qps to database representation,
+// the QPS defaults to 0 for the operations without one
+func FromDomainProbabilitiesAndQPS(probabilities model.ServiceOperationProbabilities, qps model.ServiceOperationQPS) []ProbabilityAndQPS {
+	var ret []ProbabilityAndQPS
+	for svc, opProbabilities := range probabilities {
+		for op, probability := range opProbabilities {
+			ret = append(ret, ProbabilityAndQPS{
+				Service:     svc,
+				Operation:   op,
+				Probability: probability,
+				QPS:         qps[svc][op],
+			})
+		}
+	}
+	return ret
+}
+
+// ToDomainProbabilitiesAndQPS converts database representation of probabilities and qps to model
+func ToDomainProbabilitiesAndQPS(probabilities []ProbabilityAndQPS) model.ServiceOperationData {
+	ret := make(model.ServiceOperationData)
+	for _, p := range probabilities {
+		if _, ok := ret[p.Service]; !ok {
+			ret[p.Service] = make(map[string]*model.ProbabilityAndQPS)
+		}
+		ret[p.Service][p.Operation] = &model.ProbabilityAndQPS{
+			Probability: p.Probability,
+			QPS:         p.QPS,
+		}
+	}
+	return ret
+}
+
+// ToDomainProbabilities converts database representation of probabilities and qps to model probabilities
+func ToDomainProbabilities(probabilities []ProbabilityAndQPS) model.ServiceOperationProbabilities {
+	ret := make(model.ServiceOperationProbabilities)
+	for _, p := range probabilities {
+		if _, ok := ret[p.Service]; !ok {
+			ret[p.Service] = make(map[string]float64)
+		}
+		ret[p.Service][p.Operation] = p.Probability
+	}
+	return ret
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+func TestConvertThroughput(t *testing.T) {
+	tests := []struct {
+		throughput []*model.Throughput
+	}{
+		{
+			throughput: []*model.Throughput{{
+				Service:       "svc",
+				Operation:     "op",
+				Count:         10,
+				Probabilities: map[string]struct{}{"0.1": {}, "0.5": {}},
+			}},
+		},
+		{
+			throughput: []*model.Throughput{},
+		},
+		{
+			throughput: nil,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			got := FromDomainThroughput(test.throughput)
+			a := ToDomainThroughput(got)
+			assert.Equal(t, test.throughput, a)
+		})
+	}
+}
+
+func TestConvertProbabilitiesAndQPS(t *testing.T) {
+	probabilities := model.ServiceOperationProbabilities{
+		"svc": {"op": 0.1, "other-op": 0.2},
+	}
+	qps := model.ServiceOperationQPS{
+		"svc": {"op": 5},
+	}
+	got := FromDomainProbabilitiesAndQPS(probabilities, qps)
+	assert.Len(t, got, 2)
+	assert.Equal(t, model.ServiceOperationData{
+		"svc": {
+			"op":       {Probability: 0.1, QPS: 5},
+			"other-op": {Probability: 0.2, QPS: 0},
+		},
+	}, ToDomainProbabilitiesAndQPS(got))
+	assert.Equal(t, probabilities, ToDomainProbabilities(got))
+
+	assert.Empty(t, FromDomainProbabilitiesAndQPS(nil, nil))
+	assert.Equal(t, model.ServiceOperationProbabilities{}, ToDomainProbabilities(nil))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import "time"
+
+const (
+	// ThroughputType is the type of the documents holding the throughput of the operations
+	ThroughputType = "throughput"
+	// ProbabilitiesType is the type of the documents holding the probabilities and QPS calculated by a host
+	ProbabilitiesType = "probabilities"
+)
+
+// TimeSampling encapsulates the sampling data stored at a given time, either the throughput
+// or the probabilities and QPS of a host depending on the type of the document
+type TimeSampling struct {
+	Timestamp     time.Time           `json:"timestamp"`
+	Type          string              `json:"type"`
+	Hostname      string              `json:"hostname,omitempty"`
+	Throughput    []Throughput        `json:"throughput,omitempty"`
+	Probabilities []ProbabilityAndQPS `json:"probabilities,omitempty"`
+}
+
+// Throughput is the number of queries an operation received
+type Throughput struct {
+	Service       string   `json:"service"`
+	Operation     string   `json:"operation"`
+	Count         int64    `json:"count"`
+	Probabilities []string `json:"probabilities"`
+}
+
+// ProbabilityAndQPS is the sampling probability and measured qps of an operation
+type ProbabilityAndQPS struct {
+	Service     string  `json:"service"`
+	Operation   string  `json:"operation"`
+	Probability float64 `json:"probability"`
+	QPS         float64 `json:"qps"`
+}
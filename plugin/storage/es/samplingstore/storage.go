@@ -0,0 +1,212 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore/dbmodel"
+)
+
+const (
+	samplingType   = "sampling"
+	samplingIndex  = "jaeger-sampling-"
+	timestampField = "timestamp"
+	typeField      = "type"
+)
+
+// SamplingStoreParams holds constructor params for NewSamplingStore
+type SamplingStoreParams struct {
+	Client              es.Client
+	Logger              *zap.Logger
+	IndexPrefix         string
+	IndexDateLayout     string
+	MaxDocCount         int
+	UseReadWriteAliases bool
+}
+
+// SamplingStore stores the data used by adaptive sampling in Elasticsearch
+type SamplingStore struct {
+	client              es.Client
+	logger              *zap.Logger
+	indexPrefix         string
+	indexDateLayout     string
+	maxDocCount         int
+	useReadWriteAliases bool
+	now                 func() time.Time
+}
+
+// NewSamplingStore returns a SamplingStore
+func NewSamplingStore(p SamplingStoreParams) *SamplingStore {
+	var prefix string
+	if p.IndexPrefix != "" && !strings.HasSuffix(p.IndexPrefix, "-") {
+		prefix = p.IndexPrefix + "-"
+	}
+	return &SamplingStore{
+		client:              p.Client,
+		logger:              p.Logger,
+		indexPrefix:         prefix + samplingIndex,
+		indexDateLayout:     p.IndexDateLayout,
+		maxDocCount:         p.MaxDocCount,
+		useReadWriteAliases: p.UseReadWriteAliases,
+		now:                 time.Now,
+	}
+}
+
+// CreateTemplates creates index templates.
+func (s *SamplingStore) CreateTemplates(samplingTemplate string) error {
+	_, err := s.client.CreateTemplate(strings.TrimSuffix(s.indexPrefix, "-")).Body(samplingTemplate).Do(context.Background())
+	return err
+}
+
+// InsertThroughput implements samplingstore.Store
+func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	ts := s.now()
+	s.writeDocument(ts, &dbmodel.TimeSampling{
+		Timestamp:  ts,
+		Type:       dbmodel.ThroughputType,
+		Throughput: dbmodel.FromDomainThroughput(throughput),
+	})
+	return nil
+}
+
+// InsertProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	ts := s.now()
+	s.writeDocument(ts, &dbmodel.TimeSampling{
+		Timestamp:     ts,
+		Type:          dbmodel.ProbabilitiesType,
+		Hostname:      hostname,
+		Probabilities: dbmodel.FromDomainProbabilitiesAndQPS(probabilities, qps),
+	})
+	return nil
+}
+
+// GetThroughput implements samplingstore.Store
+func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	docs, err := s.search(s.readIndices(start, end), dbmodel.ThroughputType, buildTSQuery(start, end), s.maxDocCount, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for throughput: %w", err)
+	}
+	var throughput []*model.Throughput
+	for _, doc := range docs {
+		throughput = append(throughput, dbmodel.ToDomainThroughput(doc.Throughput)...)
+	}
+	return throughput, nil
+}
+
+// GetProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	docs, err := s.search(s.readIndices(start, end), dbmodel.ProbabilitiesType, buildTSQuery(start, end), s.maxDocCount, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for probabilities and qps: %w", err)
+	}
+	hostProbabilitiesAndQPS := make(map[string][]model.ServiceOperationData)
+	for _, doc := range docs {
+		hostProbabilitiesAndQPS[doc.Hostname] = append(hostProbabilitiesAndQPS[doc.Hostname], dbmodel.ToDomainProbabilitiesAndQPS(doc.Probabilities))
+	}
+	return hostProbabilitiesAndQPS, nil
+}
+
+// GetLatestProbabilities implements samplingstore.Store
+func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	index := s.indexPrefix + "*"
+	if s.useReadWriteAliases {
+		index = s.indexPrefix + "read"
+	}
+	docs, err := s.search([]string{index}, dbmodel.ProbabilitiesType, elastic.NewMatchAllQuery(), 1, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for latest probabilities: %w", err)
+	}
+	if len(docs) == 0 {
+		return model.ServiceOperationProbabilities{}, nil
+	}
+	return dbmodel.ToDomainProbabilities(docs[0].Probabilities), nil
+}
+
+func (s *SamplingStore) writeDocument(ts time.Time, doc *dbmodel.TimeSampling) {
+	s.client.Index().Index(s.writeIndex(ts)).Type(samplingType).BodyJson(doc).Add()
+}
+
+func (s *SamplingStore) writeIndex(ts time.Time) string {
+	if s.useReadWriteAliases {
+		return s.indexPrefix + "write"
+	}
+	return indexWithDate(s.indexPrefix, s.indexDateLayout, ts)
+}
+
+func (s *SamplingStore) readIndices(start, end time.Time) []string {
+	if s.useReadWriteAliases {
+		return []string{s.indexPrefix + "read"}
+	}
+	return getIndices(s.indexPrefix, s.indexDateLayout, start, end)
+}
+
+func (s *SamplingStore) search(indices []string, docType string, query elastic.Query, size int, ascending bool) ([]dbmodel.TimeSampling, error) {
+	searchResult, err := s.client.Search(indices...).
+		Size(size).
+		Query(elastic.NewBoolQuery().Must(query, elastic.NewTermQuery(typeField, docType))).
+		Sort(timestampField, ascending).
+		IgnoreUnavailable(true).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	hits := searchResult.Hits.Hits
+	docs := make([]dbmodel.TimeSampling, 0, len(hits))
+	for _, hit := range hits {
+		var doc dbmodel.TimeSampling
+		if err := json.Unmarshal(*hit.Source, &doc); err != nil {
+			return nil, fmt.Errorf("unmarshalling ElasticSearch documents failed: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// buildTSQuery follows the semantics of the Cassandra sampling store: start is exclusive and end is inclusive.
+func buildTSQuery(start, end time.Time) elastic.Query {
+	return elastic.NewRangeQuery(timestampField).Gt(start).Lte(end)
+}
+
+func getIndices(prefix, dateLayout string, start, end time.Time) []string {
+	var indices []string
+	firstIndex := indexWithDate(prefix, dateLayout, start)
+	currentIndex := indexWithDate(prefix, dateLayout, end)
+	for currentIndex != firstIndex && end.After(start) {
+		indices = append(indices, currentIndex)
+		end = end.Add(-24 * time.Hour)
+		currentIndex = indexWithDate(prefix, dateLayout, end)
+	}
+	return append(indices, firstIndex)
+}
+
+func indexWithDate(indexNamePrefix, indexDateLayout string, date time.Time) string {
+	return indexNamePrefix + date.UTC().Format(indexDateLayout)
+}
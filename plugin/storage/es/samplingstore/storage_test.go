@@ -0,0 +1,285 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samplingstore
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+)
+
+var _ samplingstore.Store = &SamplingStore{} // check API conformance
+
+var fixedTime = time.Date(1995, time.April, 21, 4, 21, 19, 95, time.UTC)
+
+type samplingStorageTest struct {
+	client  *mocks.Client
+	storage *SamplingStore
+}
+
+func withSamplingStorage(indexPrefix string, useAliases bool, fn func(r *samplingStorageTest)) {
+	client := &mocks.Client{}
+	r := &samplingStorageTest{
+		client: client,
+		storage: NewSamplingStore(SamplingStoreParams{
+			Client:              client,
+			Logger:              zap.NewNop(),
+			IndexPrefix:         indexPrefix,
+			IndexDateLayout:     "2006-01-02",
+			MaxDocCount:         1000,
+			UseReadWriteAliases: useAliases,
+		}),
+	}
+	r.storage.now = func() time.Time { return fixedTime }
+	fn(r)
+}
+
+func TestNewSamplingStoreIndexPrefix(t *testing.T) {
+	testCases := []struct {
+		prefix   string
+		expected string
+	}{
+		{prefix: "", expected: ""},
+		{prefix: "foo", expected: "foo-"},
+		{prefix: ":", expected: ":-"},
+	}
+	for _, testCase := range testCases {
+		s := NewSamplingStore(SamplingStoreParams{Client: &mocks.Client{}, IndexPrefix: testCase.prefix})
+		assert.Equal(t, testCase.expected+samplingIndex, s.indexPrefix)
+	}
+}
+
+func TestCreateTemplates(t *testing.T) {
+	withSamplingStorage("foo", false, func(r *samplingStorageTest) {
+		templateService := &mocks.TemplateCreateService{}
+		r.client.On("CreateTemplate", "foo-jaeger-sampling").Return(templateService)
+		templateService.On("Body", "template").Return(templateService)
+		templateService.On("Do", mock.Anything).Return(nil, errors.New("template error"))
+		assert.EqualError(t, r.storage.CreateTemplates("template"), "template error")
+	})
+}
+
+func TestInsertThroughput(t *testing.T) {
+	testCases := []struct {
+		useAliases bool
+		index      string
+	}{
+		{index: "jaeger-sampling-1995-04-21"},
+		{useAliases: true, index: "jaeger-sampling-write"},
+	}
+	for _, testCase := range testCases {
+		withSamplingStorage("", testCase.useAliases, func(r *samplingStorageTest) {
+			throughput := []*model.Throughput{{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{}}}
+			writeService := &mocks.IndexService{}
+			r.client.On("Index").Return(writeService)
+			writeService.On("Index", testCase.index).Return(writeService)
+			writeService.On("Type", samplingType).Return(writeService)
+			writeService.On("BodyJson", &dbmodel.TimeSampling{
+				Timestamp:  fixedTime,
+				Type:       dbmodel.ThroughputType,
+				Throughput: dbmodel.FromDomainThroughput(throughput),
+			}).Return(writeService)
+			writeService.On("Add")
+			assert.NoError(t, r.storage.InsertThroughput(throughput))
+			writeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestInsertProbabilitiesAndQPS(t *testing.T) {
+	withSamplingStorage("", false, func(r *samplingStorageTest) {
+		probabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.1}}
+		qps := model.ServiceOperationQPS{"svc": {"op": 5}}
+		writeService := &mocks.IndexService{}
+		r.client.On("Index").Return(writeService)
+		writeService.On("Index", "jaeger-sampling-1995-04-21").Return(writeService)
+		writeService.On("Type", samplingType).Return(writeService)
+		writeService.On("BodyJson", &dbmodel.TimeSampling{
+			Timestamp:     fixedTime,
+			Type:          dbmodel.ProbabilitiesType,
+			Hostname:      "host",
+			Probabilities: []dbmodel.ProbabilityAndQPS{{Service: "svc", Operation: "op", Probability: 0.1, QPS: 5}},
+		}).Return(writeService)
+		writeService.On("Add")
+		assert.NoError(t, r.storage.InsertProbabilitiesAndQPS("host", probabilities, qps))
+		writeService.AssertExpectations(t)
+	})
+}
+
+func mockSearch(r *samplingStorageTest, size int, ascending bool, result *elastic.SearchResult, err error, indices ...interface{}) {
+	searchService := &mocks.SearchService{}
+	r.client.On("Search", indices...).Return(searchService)
+	searchService.On("Size", size).Return(searchService)
+	searchService.On("Query", mock.Anything).Return(searchService)
+	searchService.On("Sort", timestampField, ascending).Return(searchService)
+	searchService.On("IgnoreUnavailable", true).Return(searchService)
+	searchService.On("Do", mock.Anything).Return(result, err)
+}
+
+func TestGetThroughput(t *testing.T) {
+	doc := `{"timestamp":"1995-04-21T04:21:19Z","type":"throughput",
+		"throughput":[{"service":"svc","operation":"op","count":10,"probabilities":["0.1"]}]}`
+	testCases := []struct {
+		caption        string
+		useAliases     bool
+		indices        []interface{}
+		searchResult   *elastic.SearchResult
+		searchError    error
+		expectedError  string
+		expectedOutput []*model.Throughput
+	}{
+		{
+			caption:      "daily indices",
+			indices:      []interface{}{"jaeger-sampling-1995-04-21", "jaeger-sampling-1995-04-20"},
+			searchResult: createSearchResult(doc, doc),
+			expectedOutput: []*model.Throughput{
+				{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}},
+				{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}},
+			},
+		},
+		{
+			caption:        "read alias",
+			useAliases:     true,
+			indices:        []interface{}{"jaeger-sampling-read"},
+			searchResult:   createSearchResult(),
+			expectedOutput: nil,
+		},
+		{
+			caption:       "bad document",
+			indices:       []interface{}{"jaeger-sampling-1995-04-21", "jaeger-sampling-1995-04-20"},
+			searchResult:  createSearchResult(`badJson{hello}world`),
+			expectedError: "failed to search for throughput: unmarshalling ElasticSearch documents failed: invalid character 'b' looking for beginning of value",
+		},
+		{
+			caption:       "search error",
+			indices:       []interface{}{"jaeger-sampling-1995-04-21", "jaeger-sampling-1995-04-20"},
+			searchError:   errors.New("search failure"),
+			expectedError: "failed to search for throughput: search failure",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSamplingStorage("", testCase.useAliases, func(r *samplingStorageTest) {
+				mockSearch(r, 1000, true, testCase.searchResult, testCase.searchError, testCase.indices...)
+				actual, err := r.storage.GetThroughput(fixedTime.Add(-24*time.Hour), fixedTime)
+				if testCase.expectedError != "" {
+					assert.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, actual)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expectedOutput, actual)
+				}
+			})
+		})
+	}
+}
+
+func TestGetProbabilitiesAndQPS(t *testing.T) {
+	withSamplingStorage("", false, func(r *samplingStorageTest) {
+		result := createSearchResult(
+			`{"timestamp":"1995-04-21T04:20:19Z","type":"probabilities","hostname":"host-a",
+				"probabilities":[{"service":"svc","operation":"op","probability":0.1,"qps":5}]}`,
+			`{"timestamp":"1995-04-21T04:21:19Z","type":"probabilities","hostname":"host-b",
+				"probabilities":[{"service":"svc","operation":"op","probability":0.2,"qps":7}]}`,
+		)
+		mockSearch(r, 1000, true, result, nil, "jaeger-sampling-1995-04-21")
+		actual, err := r.storage.GetProbabilitiesAndQPS(fixedTime.Add(-time.Minute), fixedTime)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]model.ServiceOperationData{
+			"host-a": {{"svc": {"op": {Probability: 0.1, QPS: 5}}}},
+			"host-b": {{"svc": {"op": {Probability: 0.2, QPS: 7}}}},
+		}, actual)
+	})
+	withSamplingStorage("", false, func(r *samplingStorageTest) {
+		mockSearch(r, 1000, true, nil, errors.New("search failure"), "jaeger-sampling-1995-04-21")
+		_, err := r.storage.GetProbabilitiesAndQPS(fixedTime.Add(-time.Minute), fixedTime)
+		assert.EqualError(t, err, "failed to search for probabilities and qps: search failure")
+	})
+}
+
+func TestGetLatestProbabilities(t *testing.T) {
+	testCases := []struct {
+		caption        string
+		useAliases     bool
+		index          string
+		searchResult   *elastic.SearchResult
+		searchError    error
+		expectedError  string
+		expectedOutput model.ServiceOperationProbabilities
+	}{
+		{
+			caption: "all indices",
+			index:   "jaeger-sampling-*",
+			searchResult: createSearchResult(`{"timestamp":"1995-04-21T04:21:19Z","type":"probabilities","hostname":"host",
+				"probabilities":[{"service":"svc","operation":"op","probability":0.1,"qps":5}]}`),
+			expectedOutput: model.ServiceOperationProbabilities{"svc": {"op": 0.1}},
+		},
+		{
+			caption:        "no probabilities",
+			useAliases:     true,
+			index:          "jaeger-sampling-read",
+			searchResult:   createSearchResult(),
+			expectedOutput: model.ServiceOperationProbabilities{},
+		},
+		{
+			caption:       "search error",
+			index:         "jaeger-sampling-*",
+			searchError:   errors.New("search failure"),
+			expectedError: "failed to search for latest probabilities: search failure",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSamplingStorage("", testCase.useAliases, func(r *samplingStorageTest) {
+				mockSearch(r, 1, false, testCase.searchResult, testCase.searchError, testCase.index)
+				actual, err := r.storage.GetLatestProbabilities()
+				if testCase.expectedError != "" {
+					assert.EqualError(t, err, testCase.expectedError)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expectedOutput, actual)
+				}
+			})
+		})
+	}
+}
+
+func TestGetIndices(t *testing.T) {
+	assert.Equal(t, []string{"jaeger-sampling-1995-04-21"},
+		getIndices(samplingIndex, "2006-01-02", fixedTime.Add(-time.Hour), fixedTime))
+	assert.Equal(t, []string{"jaeger-sampling-1995-04-21", "jaeger-sampling-1995-04-20", "jaeger-sampling-1995-04-19"},
+		getIndices(samplingIndex, "2006-01-02", fixedTime.Add(-48*time.Hour), fixedTime))
+}
+
+func createSearchResult(docs ...string) *elastic.SearchResult {
+	hits := make([]*elastic.SearchHit, len(docs))
+	for i, doc := range docs {
+		raw := json.RawMessage(doc)
+		hits[i] = &elastic.SearchHit{Source: &raw}
+	}
+	return &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}
+}
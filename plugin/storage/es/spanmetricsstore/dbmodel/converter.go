@@ -0,0 +1,60 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+// FromDomain converts span metrics to their database representation
+func FromDomain(m *metricsstore.SpanMetrics) *SpanMetrics {
+	bounds := make([]uint64, len(m.LatencyBounds))
+	for i, bound := range m.LatencyBounds {
+		bounds[i] = model.DurationAsMicroseconds(bound)
+	}
+	return &SpanMetrics{
+		Timestamp:     m.Timestamp,
+		Source:        m.Source,
+		ServiceName:   m.ServiceName,
+		OperationName: m.OperationName,
+		SpanKind:      m.SpanKind,
+		Calls:         m.Calls,
+		Errors:        m.Errors,
+		LatencyBounds: bounds,
+		LatencyCounts: m.LatencyCounts,
+	}
+}
+
+// ToDomain converts the database representation of span metrics to the domain model
+func ToDomain(m *SpanMetrics) *metricsstore.SpanMetrics {
+	bounds := make([]time.Duration, len(m.LatencyBounds))
+	for i, bound := range m.LatencyBounds {
+		bounds[i] = model.MicrosecondsAsDuration(bound)
+	}
+	return &metricsstore.SpanMetrics{
+		Timestamp:     m.Timestamp,
+		Source:        m.Source,
+		ServiceName:   m.ServiceName,
+		OperationName: m.OperationName,
+		SpanKind:      m.SpanKind,
+		Calls:         m.Calls,
+		Errors:        m.Errors,
+		LatencyBounds: bounds,
+		LatencyCounts: m.LatencyCounts,
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+func TestConverter(t *testing.T) {
+	m := &metricsstore.SpanMetrics{
+		Timestamp:     time.Date(1995, time.April, 21, 4, 21, 0, 0, time.UTC),
+		Source:        "collector-1",
+		ServiceName:   "frontend",
+		OperationName: "GET",
+		SpanKind:      "SPAN_KIND_SERVER",
+		Calls:         3,
+		Errors:        1,
+		LatencyBounds: []time.Duration{time.Millisecond, time.Second},
+		LatencyCounts: []int64{1, 1, 1},
+	}
+	doc := FromDomain(m)
+	assert.Equal(t, []uint64{1000, 1000000}, doc.LatencyBounds)
+	assert.Equal(t, m, ToDomain(doc))
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbmodel
+
+import "time"
+
+// SpanMetrics is the document holding the RED metrics of a service, operation and span kind
+// aggregated by a collector within one time bucket
+type SpanMetrics struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Source        string    `json:"source"`
+	ServiceName   string    `json:"serviceName"`
+	OperationName string    `json:"operationName"`
+	SpanKind      string    `json:"spanKind"`
+	Calls         int64     `json:"calls"`
+	Errors        int64     `json:"errors"`
+	// LatencyBounds are the upper bounds of the latency histogram buckets in microseconds
+	LatencyBounds []uint64 `json:"latencyBounds"`
+	LatencyCounts []int64  `json:"latencyCounts"`
+}
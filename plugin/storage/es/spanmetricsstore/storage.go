@@ -0,0 +1,214 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olivere/elastic"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/spanmetricsstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+const (
+	spanMetricsType  = "spanMetrics"
+	spanMetricsIndex = "jaeger-spanmetrics-"
+	timestampField   = "timestamp"
+	serviceNameField = "serviceName"
+	spanKindField    = "spanKind"
+	// tenantSeparator separates the tenant from the index name, the same as for the span indices
+	tenantSeparator = "-"
+)
+
+// sortFields identify a document within the documents of a tenant, so that no document is skipped between the pages.
+var sortFields = []string{timestampField, serviceNameField, "operationName", spanKindField, "source"}
+
+// SpanMetricsStoreParams holds constructor params for NewSpanMetricsStore
+type SpanMetricsStoreParams struct {
+	Client              es.Client
+	Logger              *zap.Logger
+	IndexPrefix         string
+	IndexDateLayout     string
+	MaxDocCount         int
+	UseReadWriteAliases bool
+}
+
+// SpanMetricsStore stores the span metrics aggregated by the collector in Elasticsearch
+type SpanMetricsStore struct {
+	client              es.Client
+	logger              *zap.Logger
+	indexPrefix         string
+	indexDateLayout     string
+	maxDocCount         int
+	useReadWriteAliases bool
+}
+
+// NewSpanMetricsStore returns a SpanMetricsStore
+func NewSpanMetricsStore(p SpanMetricsStoreParams) *SpanMetricsStore {
+	var prefix string
+	if p.IndexPrefix != "" && !strings.HasSuffix(p.IndexPrefix, "-") {
+		prefix = p.IndexPrefix + "-"
+	}
+	return &SpanMetricsStore{
+		client:              p.Client,
+		logger:              p.Logger,
+		indexPrefix:         prefix + spanMetricsIndex,
+		indexDateLayout:     p.IndexDateLayout,
+		maxDocCount:         p.MaxDocCount,
+		useReadWriteAliases: p.UseReadWriteAliases,
+	}
+}
+
+// CreateTemplates creates index templates.
+func (s *SpanMetricsStore) CreateTemplates(spanMetricsTemplate string) error {
+	_, err := s.client.CreateTemplate(strings.TrimSuffix(s.indexPrefix, "-")).Body(spanMetricsTemplate).Do(context.Background())
+	return err
+}
+
+// WriteSpanMetrics implements metricsstore.SpanMetricsWriter. The documents are identified by the
+// source, service, operation, span kind and time bucket of the metrics, so that writing the metrics
+// of the same time bucket again replaces them.
+func (s *SpanMetricsStore) WriteSpanMetrics(ctx context.Context, spanMetrics []*metricsstore.SpanMetrics) error {
+	for _, m := range spanMetrics {
+		s.client.Index().
+			Index(s.writeIndex(ctx, m.Timestamp)).
+			Type(spanMetricsType).
+			Id(documentID(m)).
+			BodyJson(dbmodel.FromDomain(m)).
+			Add()
+	}
+	return nil
+}
+
+// GetSpanMetrics implements metricsstore.SpanMetricsReader. The documents are read in pages of
+// maxDocCount, sorted by time bucket and series, each page searching after the last document of the previous one.
+func (s *SpanMetricsStore) GetSpanMetrics(ctx context.Context, query *metricsstore.SpanMetricsQuery) ([]*metricsstore.SpanMetrics, error) {
+	indices := s.readIndices(ctx, query.StartTime, query.EndTime)
+	esQuery := buildQuery(query)
+	var result []*metricsstore.SpanMetrics
+	var searchAfter []interface{}
+	for {
+		search := s.client.Search(indices...).
+			Size(s.maxDocCount).
+			Query(esQuery).
+			IgnoreUnavailable(true)
+		for _, field := range sortFields {
+			search = search.Sort(field, true)
+		}
+		if searchAfter != nil {
+			search = search.SearchAfter(searchAfter...)
+		}
+		searchResult, err := search.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for span metrics: %w", err)
+		}
+		hits := searchResult.Hits.Hits
+		for _, hit := range hits {
+			var doc dbmodel.SpanMetrics
+			if err := json.Unmarshal(*hit.Source, &doc); err != nil {
+				return nil, fmt.Errorf("unmarshalling ElasticSearch documents failed: %w", err)
+			}
+			result = append(result, dbmodel.ToDomain(&doc))
+		}
+		if len(hits) == 0 || len(hits) < s.maxDocCount {
+			return result, nil
+		}
+		searchAfter = hits[len(hits)-1].Sort
+	}
+}
+
+// buildQuery selects the documents of the time buckets, services and span kinds of the query.
+func buildQuery(query *metricsstore.SpanMetricsQuery) elastic.Query {
+	boolQuery := elastic.NewBoolQuery().Filter(elastic.NewRangeQuery(timestampField).Gte(query.StartTime).Lt(query.EndTime))
+	if len(query.ServiceNames) > 0 {
+		boolQuery.Filter(newTermsQuery(serviceNameField, query.ServiceNames))
+	}
+	if len(query.SpanKinds) > 0 {
+		boolQuery.Filter(newTermsQuery(spanKindField, query.SpanKinds))
+	}
+	return boolQuery
+}
+
+func newTermsQuery(field string, values []string) *elastic.TermsQuery {
+	terms := make([]interface{}, len(values))
+	for i, v := range values {
+		terms[i] = v
+	}
+	return elastic.NewTermsQuery(field, terms...)
+}
+
+func (s *SpanMetricsStore) writeIndex(ctx context.Context, ts time.Time) string {
+	if s.useReadWriteAliases {
+		return tenantIndex(ctx, s.indexPrefix+"write")
+	}
+	return tenantIndex(ctx, indexWithDate(s.indexPrefix, s.indexDateLayout, ts))
+}
+
+func (s *SpanMetricsStore) readIndices(ctx context.Context, start, end time.Time) []string {
+	if s.useReadWriteAliases {
+		return []string{tenantIndex(ctx, s.indexPrefix+"read")}
+	}
+	indices := getIndices(s.indexPrefix, s.indexDateLayout, start, end)
+	for i, index := range indices {
+		indices[i] = tenantIndex(ctx, index)
+	}
+	return indices
+}
+
+// documentID identifies the metrics of a source, service, operation and span kind within a time bucket.
+func documentID(m *metricsstore.SpanMetrics) string {
+	hash := sha256.New()
+	for _, field := range []string{m.Source, m.ServiceName, m.OperationName, m.SpanKind} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	return strconv.FormatInt(m.Timestamp.UnixNano(), 10) + "-" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// tenantIndex returns the index name of the tenant carried by the context.
+// The indices of the default tenant are not prefixed.
+func tenantIndex(ctx context.Context, index string) string {
+	if tenant := tenancy.GetTenant(ctx); tenant != "" {
+		return tenant + tenantSeparator + index
+	}
+	return index
+}
+
+func getIndices(prefix, dateLayout string, start, end time.Time) []string {
+	var indices []string
+	firstIndex := indexWithDate(prefix, dateLayout, start)
+	currentIndex := indexWithDate(prefix, dateLayout, end)
+	for currentIndex != firstIndex && end.After(start) {
+		indices = append(indices, currentIndex)
+		end = end.Add(-24 * time.Hour)
+		currentIndex = indexWithDate(prefix, dateLayout, end)
+	}
+	return append(indices, firstIndex)
+}
+
+func indexWithDate(indexNamePrefix, indexDateLayout string, date time.Time) string {
+	return indexNamePrefix + date.UTC().Format(indexDateLayout)
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/pkg/es"
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/spanmetricsstore/dbmodel"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var (
+	_ metricsstore.SpanMetricsWriter = &SpanMetricsStore{} // check API conformance
+	_ metricsstore.SpanMetricsReader = &SpanMetricsStore{} // check API conformance
+)
+
+var fixedTime = time.Date(1995, time.April, 21, 4, 21, 0, 0, time.UTC)
+
+type spanMetricsStorageTest struct {
+	client  *mocks.Client
+	storage *SpanMetricsStore
+}
+
+func withSpanMetricsStorage(indexPrefix string, useAliases bool, fn func(r *spanMetricsStorageTest)) {
+	client := &mocks.Client{}
+	r := &spanMetricsStorageTest{
+		client: client,
+		storage: NewSpanMetricsStore(SpanMetricsStoreParams{
+			Client:              client,
+			Logger:              zap.NewNop(),
+			IndexPrefix:         indexPrefix,
+			IndexDateLayout:     "2006-01-02",
+			MaxDocCount:         1000,
+			UseReadWriteAliases: useAliases,
+		}),
+	}
+	fn(r)
+}
+
+func makeSpanMetrics(service string) *metricsstore.SpanMetrics {
+	return &metricsstore.SpanMetrics{
+		Timestamp:     fixedTime,
+		Source:        "collector-1",
+		ServiceName:   service,
+		OperationName: "GET",
+		SpanKind:      "SPAN_KIND_SERVER",
+		Calls:         2,
+		Errors:        1,
+		LatencyBounds: []time.Duration{time.Millisecond},
+		LatencyCounts: []int64{1, 1},
+	}
+}
+
+func TestNewSpanMetricsStoreIndexPrefix(t *testing.T) {
+	testCases := []struct {
+		prefix   string
+		expected string
+	}{
+		{prefix: "", expected: ""},
+		{prefix: "foo", expected: "foo-"},
+		{prefix: "foo-", expected: ""},
+	}
+	for _, testCase := range testCases {
+		s := NewSpanMetricsStore(SpanMetricsStoreParams{Client: &mocks.Client{}, IndexPrefix: testCase.prefix})
+		assert.Equal(t, testCase.expected+spanMetricsIndex, s.indexPrefix)
+	}
+}
+
+func TestCreateTemplates(t *testing.T) {
+	withSpanMetricsStorage("foo", false, func(r *spanMetricsStorageTest) {
+		templateService := &mocks.TemplateCreateService{}
+		r.client.On("CreateTemplate", "foo-jaeger-spanmetrics").Return(templateService)
+		templateService.On("Body", "template").Return(templateService)
+		templateService.On("Do", mock.Anything).Return(nil, errors.New("template error"))
+		assert.EqualError(t, r.storage.CreateTemplates("template"), "template error")
+	})
+}
+
+func TestWriteSpanMetrics(t *testing.T) {
+	testCases := []struct {
+		caption    string
+		useAliases bool
+		tenant     string
+		index      string
+	}{
+		{caption: "daily index", index: "jaeger-spanmetrics-1995-04-21"},
+		{caption: "write alias", useAliases: true, index: "jaeger-spanmetrics-write"},
+		{caption: "tenant index", tenant: "acme", index: "acme-jaeger-spanmetrics-1995-04-21"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanMetricsStorage("", testCase.useAliases, func(r *spanMetricsStorageTest) {
+				m := makeSpanMetrics("frontend")
+				writeService := &mocks.IndexService{}
+				r.client.On("Index").Return(writeService)
+				writeService.On("Index", testCase.index).Return(writeService)
+				writeService.On("Type", spanMetricsType).Return(writeService)
+				writeService.On("Id", documentID(m)).Return(writeService)
+				writeService.On("BodyJson", dbmodel.FromDomain(m)).Return(writeService)
+				writeService.On("Add")
+				ctx := tenancy.WithTenant(context.Background(), testCase.tenant)
+				assert.NoError(t, r.storage.WriteSpanMetrics(ctx, []*metricsstore.SpanMetrics{m}))
+				writeService.AssertExpectations(t)
+			})
+		})
+	}
+}
+
+func TestDocumentID(t *testing.T) {
+	frontend := makeSpanMetrics("frontend")
+	assert.Equal(t, documentID(frontend), documentID(makeSpanMetrics("frontend")))
+	assert.NotEqual(t, documentID(frontend), documentID(makeSpanMetrics("backend")))
+	later := makeSpanMetrics("frontend")
+	later.Timestamp = later.Timestamp.Add(time.Minute)
+	assert.NotEqual(t, documentID(frontend), documentID(later))
+}
+
+func TestGetSpanMetrics(t *testing.T) {
+	doc, err := json.Marshal(dbmodel.FromDomain(makeSpanMetrics("frontend")))
+	require.NoError(t, err)
+	testCases := []struct {
+		caption        string
+		useAliases     bool
+		tenant         string
+		indices        []interface{}
+		searchResult   *elastic.SearchResult
+		searchError    error
+		expectedError  string
+		expectedOutput []*metricsstore.SpanMetrics
+	}{
+		{
+			caption:        "daily indices",
+			indices:        []interface{}{"jaeger-spanmetrics-1995-04-21", "jaeger-spanmetrics-1995-04-20"},
+			searchResult:   createSearchResult(string(doc)),
+			expectedOutput: []*metricsstore.SpanMetrics{makeSpanMetrics("frontend")},
+		},
+		{
+			caption:        "read alias",
+			useAliases:     true,
+			indices:        []interface{}{"jaeger-spanmetrics-read"},
+			searchResult:   createSearchResult(),
+			expectedOutput: nil,
+		},
+		{
+			caption:        "tenant indices",
+			tenant:         "acme",
+			indices:        []interface{}{"acme-jaeger-spanmetrics-1995-04-21", "acme-jaeger-spanmetrics-1995-04-20"},
+			searchResult:   createSearchResult(),
+			expectedOutput: nil,
+		},
+		{
+			caption:       "bad document",
+			indices:       []interface{}{"jaeger-spanmetrics-1995-04-21", "jaeger-spanmetrics-1995-04-20"},
+			searchResult:  createSearchResult(`badJson{hello}world`),
+			expectedError: "unmarshalling ElasticSearch documents failed: invalid character 'b' looking for beginning of value",
+		},
+		{
+			caption:       "search error",
+			indices:       []interface{}{"jaeger-spanmetrics-1995-04-21", "jaeger-spanmetrics-1995-04-20"},
+			searchError:   errors.New("search failure"),
+			expectedError: "failed to search for span metrics: search failure",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.caption, func(t *testing.T) {
+			withSpanMetricsStorage("", testCase.useAliases, func(r *spanMetricsStorageTest) {
+				searchService := &mocks.SearchService{}
+				r.client.On("Search", testCase.indices...).Return(searchService)
+				searchService.On("Size", 1000).Return(searchService)
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("Sort", mock.Anything, true).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(testCase.searchResult, testCase.searchError)
+
+				ctx := tenancy.WithTenant(context.Background(), testCase.tenant)
+				actual, err := r.storage.GetSpanMetrics(ctx, &metricsstore.SpanMetricsQuery{StartTime: fixedTime.Add(-24 * time.Hour), EndTime: fixedTime})
+				if testCase.expectedError != "" {
+					assert.EqualError(t, err, testCase.expectedError)
+					assert.Nil(t, actual)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, testCase.expectedOutput, actual)
+				}
+			})
+		})
+	}
+}
+
+func TestGetSpanMetricsPages(t *testing.T) {
+	client := &mocks.Client{}
+	store := NewSpanMetricsStore(SpanMetricsStoreParams{
+		Client:          client,
+		Logger:          zap.NewNop(),
+		IndexDateLayout: "2006-01-02",
+		MaxDocCount:     2,
+	})
+	var docs []string
+	var expected []*metricsstore.SpanMetrics
+	for _, service := range []string{"a", "b", "c", "d", "e"} {
+		doc, err := json.Marshal(dbmodel.FromDomain(makeSpanMetrics(service)))
+		require.NoError(t, err)
+		docs = append(docs, string(doc))
+		expected = append(expected, makeSpanMetrics(service))
+	}
+	page := func(docs ...string) *elastic.SearchResult {
+		result := createSearchResult(docs...)
+		for _, hit := range result.Hits.Hits {
+			var doc dbmodel.SpanMetrics
+			require.NoError(t, json.Unmarshal(*hit.Source, &doc))
+			hit.Sort = []interface{}{doc.Timestamp.UnixNano() / int64(time.Millisecond), doc.ServiceName, doc.OperationName, doc.SpanKind, doc.Source}
+		}
+		return result
+	}
+	pages := []*elastic.SearchResult{page(docs[0], docs[1]), page(docs[2], docs[3]), page(docs[4])}
+	var searchAfter [][]interface{}
+	client.On("Search", mock.Anything).Return(func(indices ...string) es.SearchService {
+		searchService := &mocks.SearchService{}
+		searchService.On("Size", 2).Return(searchService)
+		searchService.On("Query", mock.Anything).Return(searchService)
+		searchService.On("Sort", mock.Anything, true).Return(searchService)
+		searchService.On("IgnoreUnavailable", true).Return(searchService)
+		sortValues := []interface{}{mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+		searchService.On("SearchAfter", sortValues...).Return(func(values ...interface{}) es.SearchService {
+			searchAfter = append(searchAfter, values)
+			return searchService
+		})
+		searchService.On("Do", mock.Anything).Return(pages[0], nil).Once()
+		pages = pages[1:]
+		return searchService
+	})
+
+	actual, err := store.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: fixedTime, EndTime: fixedTime.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual, "the documents beyond the max doc count are read from the next pages")
+	require.Len(t, searchAfter, 2)
+	assert.Equal(t, "b", searchAfter[0][1])
+	assert.Equal(t, "d", searchAfter[1][1])
+}
+
+func TestBuildQuery(t *testing.T) {
+	source, err := buildQuery(&metricsstore.SpanMetricsQuery{
+		StartTime:    fixedTime,
+		EndTime:      fixedTime.Add(time.Minute),
+		ServiceNames: []string{"frontend", "driver"},
+		SpanKinds:    []string{"SPAN_KIND_SERVER"},
+	}).Source()
+	require.NoError(t, err)
+	data, err := json.Marshal(source)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"terms":{"serviceName":["frontend","driver"]}}`)
+	assert.Contains(t, string(data), `{"terms":{"spanKind":["SPAN_KIND_SERVER"]}}`)
+
+	source, err = buildQuery(&metricsstore.SpanMetricsQuery{StartTime: fixedTime, EndTime: fixedTime.Add(time.Minute)}).Source()
+	require.NoError(t, err)
+	data, err = json.Marshal(source)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "terms")
+}
+
+func createSearchResult(docs ...string) *elastic.SearchResult {
+	hits := make([]*elastic.SearchHit, len(docs))
+	for i, doc := range docs {
+		raw := json.RawMessage(doc)
+		hits[i] = &elastic.SearchHit{Source: &raw}
+	}
+	return &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}}
+}
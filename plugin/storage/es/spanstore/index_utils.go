@@ -15,7 +15,10 @@
 package spanstore
 
 import (
+	"context"
 	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 )
 
 // returns index name with date
@@ -28,3 +31,12 @@ func indexWithDate(indexPrefix, indexDateLayout string, date time.Time) string {
 func archiveIndex(indexPrefix, archiveSuffix string) string {
 	return indexPrefix + archiveSuffix
 }
+
+// returns the index name, or index prefix, of the tenant carried by the context.
+// The indices of the default tenant are not prefixed.
+func tenantIndex(ctx context.Context, index string) string {
+	if tenant := tenancy.GetTenant(ctx); tenant != "" && index != "" {
+		return tenant + indexPrefixSeparator + index
+	}
+	return index
+}
@@ -60,6 +60,10 @@ const (
 	tagValueField          = "value"
 
 	defaultNumTraces = 100
+	defaultNumSpans  = 100
+	// queryOversampling is how many more traces are searched for when the results are post-filtered
+	// by a structured query
+	queryOversampling = 5
 
 	rolloverMaxSpanAge = time.Hour * 24 * 365 * 100
 )
@@ -287,7 +291,7 @@ func (s *SpanReader) GetServices(ctx context.Context) ([]string, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "GetServices")
 	defer span.Finish()
 	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
+	jaegerIndices := s.timeRangeIndices(tenantIndex(ctx, s.serviceIndexPrefix), s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
 	return s.serviceOperationStorage.getServices(ctx, jaegerIndices, s.maxDocCount)
 }
 
@@ -299,7 +303,7 @@ func (s *SpanReader) GetOperations(
 	span, ctx := opentracing.StartSpanFromContext(ctx, "GetOperations")
 	defer span.Finish()
 	currentTime := time.Now()
-	jaegerIndices := s.timeRangeIndices(s.serviceIndexPrefix, s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
+	jaegerIndices := s.timeRangeIndices(tenantIndex(ctx, s.serviceIndexPrefix), s.serviceIndexDateLayout, currentTime.Add(-s.maxSpanAge), currentTime, s.serviceIndexRolloverFrequency)
 	operations, err := s.serviceOperationStorage.getOperations(ctx, jaegerIndices, query.ServiceName, s.maxDocCount)
 	if err != nil {
 		return nil, err
@@ -333,6 +337,9 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	span, ctx := opentracing.StartSpanFromContext(ctx, "FindTraces")
 	defer span.Finish()
 
+	if traceQuery != nil && traceQuery.Query != nil {
+		return s.findTracesByQuery(ctx, traceQuery)
+	}
 	uniqueTraceIDs, err := s.FindTraceIDs(ctx, traceQuery)
 	if err != nil {
 		return nil, err
@@ -340,11 +347,32 @@ func (s *SpanReader) FindTraces(ctx context.Context, traceQuery *spanstore.Trace
 	return s.multiRead(ctx, uniqueTraceIDs, traceQuery.StartTimeMin, traceQuery.StartTimeMax)
 }
 
+// findTracesByQuery searches for the conditions implied by the structured query, and then
+// evaluates the whole query against the traces found.
+func (s *SpanReader) findTracesByQuery(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
+	filter := *traceQuery
+	if filter.NumTraces == 0 {
+		filter.NumTraces = defaultNumTraces
+	}
+	traces, err := s.FindTraces(ctx, spanstore.PushDownQuery(&filter, queryOversampling))
+	if err != nil {
+		return nil, err
+	}
+	return spanstore.FilterTraces(traces, &filter), nil
+}
+
 // FindTraceIDs retrieves traces IDs that match the traceQuery
 func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "FindTraceIDs")
 	defer span.Finish()
 
+	if traceQuery != nil && traceQuery.Query != nil {
+		traces, err := s.findTracesByQuery(ctx, traceQuery)
+		if err != nil {
+			return nil, err
+		}
+		return spanstore.TraceIDs(traces), nil
+	}
 	if err := validateQuery(traceQuery); err != nil {
 		return nil, err
 	}
@@ -360,6 +388,47 @@ func (s *SpanReader) FindTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	return convertTraceIDsStringsToModels(esTraceIDs)
 }
 
+// FindSpans retrieves the spans that match the query, sorted and limited by Elasticsearch
+func (s *SpanReader) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "FindSpans")
+	defer span.Finish()
+
+	if query == nil {
+		return nil, ErrMalformedRequestObject
+	}
+	traceQuery := query.ToTraceQueryParameters()
+	if err := validateQuery(traceQuery); err != nil {
+		return nil, err
+	}
+	numSpans := query.NumSpans
+	if numSpans <= 0 {
+		numSpans = defaultNumSpans
+	}
+	if s.maxDocCount > 0 && numSpans > s.maxDocCount {
+		numSpans = s.maxDocCount
+	}
+	sortField := startTimeField
+	if query.SortBy == spanstore.SortByDuration {
+		sortField = durationField
+	}
+	jaegerIndices := s.timeRangeIndices(tenantIndex(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, query.StartTimeMin, query.StartTimeMax, s.spanIndexRolloverFrequency)
+
+	searchResult, err := s.client.Search(jaegerIndices...).
+		Size(numSpans).
+		Sort(sortField, query.Ascending).
+		IgnoreUnavailable(true).
+		Query(s.buildFindTraceIDsQuery(traceQuery)).
+		Do(ctx)
+	if err != nil {
+		logErrorToSpan(span, err)
+		return nil, fmt.Errorf("search spans failed: %w", err)
+	}
+	if searchResult.Hits == nil || len(searchResult.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return s.collectSpans(searchResult.Hits.Hits)
+}
+
 func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, startTime, endTime time.Time) ([]*model.Trace, error) {
 
 	childSpan, _ := opentracing.StartSpanFromContext(ctx, "multiRead")
@@ -372,7 +441,7 @@ func (s *SpanReader) multiRead(ctx context.Context, traceIDs []model.TraceID, st
 
 	// Add an hour in both directions so that traces that straddle two indexes are retrieved.
 	// i.e starts in one and ends in another.
-	indices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
+	indices := s.timeRangeIndices(tenantIndex(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, startTime.Add(-time.Hour), endTime.Add(time.Hour), s.spanIndexRolloverFrequency)
 	nextTime := model.TimeAsEpochMicroseconds(startTime.Add(-time.Hour))
 	searchAfterTime := make(map[model.TraceID]uint64)
 	totalDocumentsFetched := make(map[model.TraceID]int)
@@ -563,7 +632,7 @@ func (s *SpanReader) findTraceIDs(ctx context.Context, traceQuery *spanstore.Tra
 	//  }
 	aggregation := s.buildTraceIDAggregation(traceQuery.NumTraces)
 	boolQuery := s.buildFindTraceIDsQuery(traceQuery)
-	jaegerIndices := s.timeRangeIndices(s.spanIndexPrefix, s.spanIndexDateLayout, traceQuery.StartTimeMin, traceQuery.StartTimeMax, s.spanIndexRolloverFrequency)
+	jaegerIndices := s.timeRangeIndices(tenantIndex(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, traceQuery.StartTimeMin, traceQuery.StartTimeMax, s.spanIndexRolloverFrequency)
 
 	searchService := s.client.Search(jaegerIndices...).
 		Size(0). // set to 0 because we don't want actual documents.
@@ -37,6 +37,7 @@ import (
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 const defaultMaxDocCount = 10_000
@@ -696,6 +697,57 @@ func TestSpanReader_FindTraces(t *testing.T) {
 	})
 }
 
+func TestSpanReader_FindTracesWithQuery(t *testing.T) {
+	goodAggregations := make(map[string]*json.RawMessage)
+	rawMessage := []byte(`{"buckets": [{"key": "1","doc_count": 16},{"key": "2","doc_count": 16},{"key": "3","doc_count": 16}]}`)
+	goodAggregations[traceIDAggregation] = (*json.RawMessage)(&rawMessage)
+
+	hits := make([]*elastic.SearchHit, 1)
+	hits[0] = &elastic.SearchHit{
+		Source: (*json.RawMessage)(&exampleESSpan),
+	}
+	searchHits := &elastic.SearchHits{Hits: hits}
+
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{query: "{service=serv AND tag.logtag=helloworld}", expected: 1},
+		{query: "service=serv AND span.tag>1000", expected: 1},
+		{query: "service=serv AND process.processtag=true", expected: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			withSpanReader(func(r *spanReaderTest) {
+				mockSearchService(r).
+					Return(&elastic.SearchResult{Aggregations: elastic.Aggregations(goodAggregations), Hits: searchHits}, nil)
+				mockMultiSearchService(r).
+					Return(&elastic.MultiSearchResult{
+						Responses: []*elastic.SearchResult{
+							{Hits: searchHits},
+						},
+					}, nil)
+
+				q, err := querylang.Parse(test.query)
+				require.NoError(t, err)
+				traceQuery := &spanstore.TraceQueryParameters{
+					StartTimeMin: time.Unix(0, 0),
+					StartTimeMax: time.Now(),
+					Query:        q,
+				}
+
+				traces, err := r.reader.FindTraces(context.Background(), traceQuery)
+				require.NoError(t, err)
+				assert.Len(t, traces, test.expected)
+
+				traceIDs, err := r.reader.FindTraceIDs(context.Background(), traceQuery)
+				require.NoError(t, err)
+				assert.Len(t, traceIDs, test.expected)
+			})
+		})
+	}
+}
+
 func TestSpanReader_FindTracesInvalidQuery(t *testing.T) {
 	goodAggregations := make(map[string]*json.RawMessage)
 	rawMessage := []byte(`{"buckets": [{"key": "1","doc_count": 16},{"key": "2","doc_count": 16},{"key": "3","doc_count": 16}]}`)
@@ -870,6 +922,89 @@ func TestSpanReader_FindTracesSpanCollectionFailure(t *testing.T) {
 	})
 }
 
+func TestSpanReader_FindSpans(t *testing.T) {
+	hits := []*elastic.SearchHit{{Source: (*json.RawMessage)(&exampleESSpan)}}
+	startTime := time.Date(2021, time.March, 10, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		query         *spanstore.SpanQueryParameters
+		searchResult  *elastic.SearchResult
+		searchError   error
+		expectedSize  int
+		expectedSort  string
+		expectedSpans int
+		expectedError string
+	}{
+		{
+			name: "slowest spans",
+			query: &spanstore.SpanQueryParameters{
+				ServiceName:  serviceName,
+				StartTimeMin: startTime,
+				StartTimeMax: startTime.Add(time.Hour),
+				NumSpans:     10,
+				SortBy:       spanstore.SortByDuration,
+			},
+			searchResult:  &elastic.SearchResult{Hits: &elastic.SearchHits{Hits: hits}},
+			expectedSize:  10,
+			expectedSort:  durationField,
+			expectedSpans: 1,
+		},
+		{
+			name: "default limit",
+			query: &spanstore.SpanQueryParameters{
+				StartTimeMin: startTime,
+				StartTimeMax: startTime.Add(time.Hour),
+			},
+			searchResult: &elastic.SearchResult{},
+			expectedSize: defaultNumSpans,
+			expectedSort: startTimeField,
+		},
+		{
+			name: "search failure",
+			query: &spanstore.SpanQueryParameters{
+				StartTimeMin: startTime,
+				StartTimeMax: startTime.Add(time.Hour),
+				NumSpans:     defaultMaxDocCount + 1,
+			},
+			searchError:   errors.New("search failure"),
+			expectedSize:  defaultMaxDocCount,
+			expectedSort:  startTimeField,
+			expectedError: "search spans failed: search failure",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withSpanReader(func(r *spanReaderTest) {
+				searchService := &mocks.SearchService{}
+				searchService.On("Size", test.expectedSize).Return(searchService)
+				searchService.On("Sort", test.expectedSort, test.query.Ascending).Return(searchService)
+				searchService.On("IgnoreUnavailable", true).Return(searchService)
+				searchService.On("Query", mock.Anything).Return(searchService)
+				searchService.On("Do", mock.Anything).Return(test.searchResult, test.searchError)
+				r.client.On("Search", "jaeger-span-").Return(searchService)
+
+				spans, err := r.reader.FindSpans(context.Background(), test.query)
+				if test.expectedError != "" {
+					assert.EqualError(t, err, test.expectedError)
+					return
+				}
+				require.NoError(t, err)
+				assert.Len(t, spans, test.expectedSpans)
+				searchService.AssertExpectations(t)
+			})
+		})
+	}
+}
+
+func TestSpanReader_FindSpansInvalidQuery(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		_, err := r.reader.FindSpans(context.Background(), nil)
+		assert.Equal(t, ErrMalformedRequestObject, err)
+		_, err = r.reader.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: serviceName})
+		assert.Equal(t, ErrStartAndEndTimeNotSet, err)
+	})
+}
+
 func TestFindTraceIDs(t *testing.T) {
 	testCases := []struct {
 		aggregrationID string
@@ -0,0 +1,257 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	spanStatsServicesAggregation    = "services"
+	spanStatsOperationsAggregation  = "operations"
+	spanStatsTagsAggregation        = "tags"
+	spanStatsTagKeyAggregation      = "tagKey"
+	spanStatsTagValuesAggregation   = "tagValues"
+	spanStatsSpansAggregation       = "spans"
+	spanStatsPercentilesAggregation = "durationPercentiles"
+	spanStatsHistogramAggregation   = "durationHistogram"
+	spanStatsErrorsAggregation      = "errors"
+
+	nestedTagKeyField   = nestedTagsField + "." + tagKeyField
+	nestedTagValueField = nestedTagsField + "." + tagValueField
+)
+
+var (
+	spanStatsPercentiles = []float64{50, 90, 99}
+
+	errNonStringAggregationKey = errors.New("non-string key found in aggregation")
+)
+
+// GetSpanStats computes the statistics of the spans that match the query with Elasticsearch aggregations.
+// The percentiles are approximated by Elasticsearch. When grouping by tag, only the span tags stored
+// as nested documents are considered, not the ones stored as object fields (see --es.tags-as-fields).
+func (s *SpanReader) GetSpanStats(ctx context.Context, query *spanstore.SpanStatsQueryParameters) ([]spanstore.SpanStats, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GetSpanStats")
+	defer span.Finish()
+
+	if query == nil {
+		return nil, ErrMalformedRequestObject
+	}
+	if query.StartTimeMin.IsZero() || query.StartTimeMax.IsZero() {
+		return nil, ErrStartAndEndTimeNotSet
+	}
+	if query.StartTimeMax.Before(query.StartTimeMin) {
+		return nil, ErrStartTimeMinGreaterThanMax
+	}
+
+	boolQuery := elastic.NewBoolQuery().Must(s.buildStartTimeQuery(query.StartTimeMin, query.StartTimeMax))
+	if query.ServiceName != "" {
+		boolQuery.Must(s.buildServiceNameQuery(query.ServiceName))
+	}
+	if query.OperationName != "" {
+		boolQuery.Must(s.buildOperationNameQuery(query.OperationName))
+	}
+	jaegerIndices := s.timeRangeIndices(tenantIndex(ctx, s.spanIndexPrefix), s.spanIndexDateLayout, query.StartTimeMin, query.StartTimeMax, s.spanIndexRolloverFrequency)
+
+	searchResult, err := s.client.Search(jaegerIndices...).
+		Size(0). // set to 0 because we don't want actual documents.
+		Aggregation(spanStatsServicesAggregation, s.buildSpanStatsAggregation(query)).
+		IgnoreUnavailable(true).
+		Query(boolQuery).
+		Do(ctx)
+	if err != nil {
+		logErrorToSpan(span, err)
+		return nil, fmt.Errorf("search span stats failed: %w", err)
+	}
+	if searchResult.Aggregations == nil {
+		return nil, nil
+	}
+	services, found := searchResult.Aggregations.Terms(spanStatsServicesAggregation)
+	if !found {
+		return nil, nil
+	}
+	c := spanStatsCollector{query: query, buckets: query.DurationBuckets()}
+	for _, bucket := range services.Buckets {
+		serviceName, ok := bucket.Key.(string)
+		if !ok {
+			return nil, errNonStringAggregationKey
+		}
+		if err := c.collectOperations(bucket.Aggregations, bucket.DocCount, spanstore.SpanStats{ServiceName: serviceName}); err != nil {
+			return nil, err
+		}
+	}
+	spanstore.SortSpanStats(c.stats)
+	return c.stats, nil
+}
+
+// buildSpanStatsAggregation returns the aggregation of the spans by service, then by operation and by tag value if requested.
+//
+//  "aggs": { "services": { "terms": { "field": "process.serviceName" },
+//    "aggs": { "operations": { "terms": { "field": "operationName" },
+//      "aggs": { "tags": { "nested": { "path": "tags" },
+//        "aggs": { "tagKey": { "filter": { "term": { "tags.key": "http.method" }},
+//          "aggs": { "tagValues": { "terms": { "field": "tags.value" },
+//            "aggs": { "spans": { "reverse_nested": {},
+//              "aggs": { "durationPercentiles": ..., "durationHistogram": ..., "errors": ... }}}}}}}}}}}}
+func (s *SpanReader) buildSpanStatsAggregation(query *spanstore.SpanStatsQueryParameters) elastic.Aggregation {
+	groupAggregations := s.buildSpanStatsLeafAggregations(query.DurationBuckets())
+	if query.GroupByTag != "" {
+		spans := elastic.NewReverseNestedAggregation()
+		for name, aggregation := range groupAggregations {
+			spans = spans.SubAggregation(name, aggregation)
+		}
+		values := elastic.NewTermsAggregation().
+			Field(nestedTagValueField).
+			Size(s.maxDocCount).
+			SubAggregation(spanStatsSpansAggregation, spans)
+		key := elastic.NewFilterAggregation().
+			Filter(elastic.NewTermQuery(nestedTagKeyField, query.GroupByTag)).
+			SubAggregation(spanStatsTagValuesAggregation, values)
+		tags := elastic.NewNestedAggregation().
+			Path(nestedTagsField).
+			SubAggregation(spanStatsTagKeyAggregation, key)
+		groupAggregations = map[string]elastic.Aggregation{spanStatsTagsAggregation: tags}
+	}
+	if query.GroupByOperation {
+		operations := elastic.NewTermsAggregation().Field(operationNameField).Size(s.maxDocCount)
+		for name, aggregation := range groupAggregations {
+			operations = operations.SubAggregation(name, aggregation)
+		}
+		groupAggregations = map[string]elastic.Aggregation{spanStatsOperationsAggregation: operations}
+	}
+	services := elastic.NewTermsAggregation().Field(serviceNameField).Size(s.maxDocCount)
+	for name, aggregation := range groupAggregations {
+		services = services.SubAggregation(name, aggregation)
+	}
+	return services
+}
+
+// buildSpanStatsLeafAggregations returns the aggregations computing the statistics of a group of spans.
+func (s *SpanReader) buildSpanStatsLeafAggregations(buckets []time.Duration) map[string]elastic.Aggregation {
+	histogram := elastic.NewRangeAggregation().Field(durationField)
+	var lowerBound int64
+	for i, upperBound := range buckets {
+		upperBoundMicros := int64(model.DurationAsMicroseconds(upperBound))
+		if i == 0 {
+			histogram = histogram.AddUnboundedFrom(upperBoundMicros)
+		} else {
+			histogram = histogram.AddRange(lowerBound, upperBoundMicros)
+		}
+		lowerBound = upperBoundMicros
+	}
+	histogram = histogram.AddUnboundedTo(lowerBound)
+	return map[string]elastic.Aggregation{
+		spanStatsPercentilesAggregation: elastic.NewPercentilesAggregation().Field(durationField).Percentiles(spanStatsPercentiles...),
+		spanStatsHistogramAggregation:   histogram,
+		spanStatsErrorsAggregation:      elastic.NewFilterAggregation().Filter(s.buildTagQuery("error", "true")),
+	}
+}
+
+type spanStatsCollector struct {
+	query   *spanstore.SpanStatsQueryParameters
+	buckets []time.Duration
+	stats   []spanstore.SpanStats
+}
+
+func (c *spanStatsCollector) collectOperations(aggregations elastic.Aggregations, docCount int64, stats spanstore.SpanStats) error {
+	if !c.query.GroupByOperation {
+		return c.collectTags(aggregations, docCount, stats)
+	}
+	operations, found := aggregations.Terms(spanStatsOperationsAggregation)
+	if !found {
+		return nil
+	}
+	for _, bucket := range operations.Buckets {
+		operationName, ok := bucket.Key.(string)
+		if !ok {
+			return errNonStringAggregationKey
+		}
+		stats.OperationName = operationName
+		if err := c.collectTags(bucket.Aggregations, bucket.DocCount, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *spanStatsCollector) collectTags(aggregations elastic.Aggregations, docCount int64, stats spanstore.SpanStats) error {
+	if c.query.GroupByTag == "" {
+		c.collectStats(aggregations, docCount, stats)
+		return nil
+	}
+	tags, found := aggregations.Nested(spanStatsTagsAggregation)
+	if !found {
+		return nil
+	}
+	key, found := tags.Aggregations.Filter(spanStatsTagKeyAggregation)
+	if !found {
+		return nil
+	}
+	values, found := key.Aggregations.Terms(spanStatsTagValuesAggregation)
+	if !found {
+		return nil
+	}
+	for _, bucket := range values.Buckets {
+		tagValue, ok := bucket.Key.(string)
+		if !ok {
+			return errNonStringAggregationKey
+		}
+		spans, found := bucket.Aggregations.ReverseNested(spanStatsSpansAggregation)
+		if !found {
+			continue
+		}
+		stats.TagValue = tagValue
+		c.collectStats(spans.Aggregations, spans.DocCount, stats)
+	}
+	return nil
+}
+
+func (c *spanStatsCollector) collectStats(aggregations elastic.Aggregations, docCount int64, stats spanstore.SpanStats) {
+	stats.Count = docCount
+	if errorSpans, found := aggregations.Filter(spanStatsErrorsAggregation); found {
+		stats.ErrorCount = errorSpans.DocCount
+	}
+	if percentiles, found := aggregations.Percentiles(spanStatsPercentilesAggregation); found {
+		stats.P50 = percentileValue(percentiles, spanStatsPercentiles[0])
+		stats.P90 = percentileValue(percentiles, spanStatsPercentiles[1])
+		stats.P99 = percentileValue(percentiles, spanStatsPercentiles[2])
+	}
+	stats.Histogram = spanstore.NewHistogram(c.buckets)
+	if histogram, found := aggregations.Range(spanStatsHistogramAggregation); found {
+		// the ranges are returned in the order they were defined
+		for i, bucket := range histogram.Buckets {
+			if i < len(stats.Histogram) {
+				stats.Histogram[i].Count = bucket.DocCount
+			}
+		}
+	}
+	c.stats = append(c.stats, stats)
+}
+
+// percentileValue returns a percentile of the span durations, which are stored in microseconds.
+func percentileValue(percentiles *elastic.AggregationPercentilesMetric, percent float64) time.Duration {
+	micros := percentiles.Values[strconv.FormatFloat(percent, 'f', 1, 64)]
+	return time.Duration(micros * float64(time.Microsecond))
+}
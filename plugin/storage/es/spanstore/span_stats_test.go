@@ -0,0 +1,239 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const spanStatsLeafAggregations = `
+	"durationPercentiles": {"values": {"50.0": 1500, "90.0": 20000, "99.0": 2000000}},
+	"durationHistogram": {"buckets": [{"key": "*-10000.0", "doc_count": 2}, {"key": "10000.0-*", "doc_count": 1}]},
+	"errors": {"doc_count": 1}`
+
+func mockSpanStatsSearch(t *testing.T, r *spanReaderTest, aggregations string, err error) *mocks.SearchService {
+	searchService := &mocks.SearchService{}
+	searchService.On("Size", 0).Return(searchService)
+	searchService.On("Aggregation", spanStatsServicesAggregation, mock.AnythingOfType("*elastic.TermsAggregation")).Return(searchService)
+	searchService.On("IgnoreUnavailable", true).Return(searchService)
+	searchService.On("Query", mock.Anything).Return(searchService)
+	var result *elastic.SearchResult
+	if err == nil {
+		result = &elastic.SearchResult{}
+		if aggregations != "" {
+			require.NoError(t, json.Unmarshal([]byte(aggregations), &result.Aggregations))
+		}
+	}
+	searchService.On("Do", mock.Anything).Return(result, err)
+	r.client.On("Search", "jaeger-span-").Return(searchService)
+	return searchService
+}
+
+func TestSpanReader_GetSpanStats(t *testing.T) {
+	startTime := time.Date(2021, time.March, 10, 10, 0, 0, 0, time.UTC)
+	expectedHistogram := []spanstore.HistogramBucket{
+		{UpperBound: 10 * time.Millisecond, Count: 2},
+		{LowerBound: 10 * time.Millisecond, Count: 1},
+	}
+	tests := []struct {
+		name         string
+		query        *spanstore.SpanStatsQueryParameters
+		aggregations string
+		expected     []spanstore.SpanStats
+	}{
+		{
+			name: "by service",
+			query: &spanstore.SpanStatsQueryParameters{
+				ServiceName: "frontend",
+			},
+			aggregations: `{"services": {"buckets": [{"key": "frontend", "doc_count": 3, ` + spanStatsLeafAggregations + `}]}}`,
+			expected: []spanstore.SpanStats{{
+				ServiceName: "frontend",
+				Count:       3,
+				ErrorCount:  1,
+				P50:         1500 * time.Microsecond,
+				P90:         20 * time.Millisecond,
+				P99:         2 * time.Second,
+				Histogram:   expectedHistogram,
+			}},
+		},
+		{
+			name: "by operation and tag",
+			query: &spanstore.SpanStatsQueryParameters{
+				GroupByOperation: true,
+				GroupByTag:       "http.method",
+			},
+			aggregations: `{"services": {"buckets": [
+				{"key": "frontend", "doc_count": 3, "operations": {"buckets": [
+					{"key": "HTTP", "doc_count": 3, "tags": {"doc_count": 6, "tagKey": {"doc_count": 3, "tagValues": {"buckets": [
+						{"key": "POST", "doc_count": 3, "spans": {"doc_count": 3, ` + spanStatsLeafAggregations + `}}
+					]}}}}
+				]}},
+				{"key": "driver", "doc_count": 3, "operations": {"buckets": [
+					{"key": "find", "doc_count": 3, "tags": {"doc_count": 6, "tagKey": {"doc_count": 3, "tagValues": {"buckets": [
+						{"key": "GET", "doc_count": 3, "spans": {"doc_count": 3, ` + spanStatsLeafAggregations + `}}
+					]}}}}
+				]}}
+			]}}`,
+			expected: []spanstore.SpanStats{
+				{
+					ServiceName:   "driver",
+					OperationName: "find",
+					TagValue:      "GET",
+					Count:         3,
+					ErrorCount:    1,
+					P50:           1500 * time.Microsecond,
+					P90:           20 * time.Millisecond,
+					P99:           2 * time.Second,
+					Histogram:     expectedHistogram,
+				},
+				{
+					ServiceName:   "frontend",
+					OperationName: "HTTP",
+					TagValue:      "POST",
+					Count:         3,
+					ErrorCount:    1,
+					P50:           1500 * time.Microsecond,
+					P90:           20 * time.Millisecond,
+					P99:           2 * time.Second,
+					Histogram:     expectedHistogram,
+				},
+			},
+		},
+		{
+			name:  "no aggregations",
+			query: &spanstore.SpanStatsQueryParameters{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withSpanReader(func(r *spanReaderTest) {
+				mockSpanStatsSearch(t, r, test.aggregations, nil)
+				test.query.StartTimeMin = startTime
+				test.query.StartTimeMax = startTime.Add(time.Hour)
+				test.query.Buckets = []time.Duration{10 * time.Millisecond}
+				stats, err := r.reader.GetSpanStats(context.Background(), test.query)
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, stats)
+			})
+		})
+	}
+}
+
+func TestSpanReader_GetSpanStatsErrors(t *testing.T) {
+	startTime := time.Date(2021, time.March, 10, 10, 0, 0, 0, time.UTC)
+	withSpanReader(func(r *spanReaderTest) {
+		_, err := r.reader.GetSpanStats(context.Background(), nil)
+		assert.Equal(t, ErrMalformedRequestObject, err)
+		_, err = r.reader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{})
+		assert.Equal(t, ErrStartAndEndTimeNotSet, err)
+		_, err = r.reader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			StartTimeMin: startTime,
+			StartTimeMax: startTime.Add(-time.Hour),
+		})
+		assert.Equal(t, ErrStartTimeMinGreaterThanMax, err)
+	})
+	withSpanReader(func(r *spanReaderTest) {
+		mockSpanStatsSearch(t, r, "", errors.New("search failure"))
+		_, err := r.reader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			StartTimeMin: startTime,
+			StartTimeMax: startTime.Add(time.Hour),
+		})
+		assert.EqualError(t, err, "search span stats failed: search failure")
+	})
+	withSpanReader(func(r *spanReaderTest) {
+		mockSpanStatsSearch(t, r, `{"services": {"buckets": [{"key": 1, "doc_count": 3}]}}`, nil)
+		_, err := r.reader.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			StartTimeMin: startTime,
+			StartTimeMax: startTime.Add(time.Hour),
+		})
+		assert.Equal(t, errNonStringAggregationKey, err)
+	})
+}
+
+func TestSpanReader_buildSpanStatsAggregation(t *testing.T) {
+	withSpanReader(func(r *spanReaderTest) {
+		aggregation := r.reader.buildSpanStatsAggregation(&spanstore.SpanStatsQueryParameters{
+			GroupByOperation: true,
+			GroupByTag:       "http.method",
+			Buckets:          []time.Duration{time.Millisecond, time.Second},
+		})
+		source, err := aggregation.Source()
+		require.NoError(t, err)
+		actual, err := json.Marshal(source)
+		require.NoError(t, err)
+
+		var services struct {
+			Terms map[string]interface{} `json:"terms"`
+			Aggs  struct {
+				Operations struct {
+					Terms map[string]interface{} `json:"terms"`
+					Aggs  struct {
+						Tags struct {
+							Nested map[string]interface{} `json:"nested"`
+							Aggs   struct {
+								TagKey struct {
+									Filter map[string]interface{} `json:"filter"`
+									Aggs   struct {
+										TagValues struct {
+											Terms map[string]interface{} `json:"terms"`
+											Aggs  struct {
+												Spans struct {
+													Aggs struct {
+														Histogram struct {
+															Range struct {
+																Ranges []map[string]interface{} `json:"ranges"`
+															} `json:"range"`
+														} `json:"durationHistogram"`
+													} `json:"aggregations"`
+												} `json:"spans"`
+											} `json:"aggregations"`
+										} `json:"tagValues"`
+									} `json:"aggregations"`
+								} `json:"tagKey"`
+							} `json:"aggregations"`
+						} `json:"tags"`
+					} `json:"aggregations"`
+				} `json:"operations"`
+			} `json:"aggregations"`
+		}
+		require.NoError(t, json.Unmarshal(actual, &services))
+		assert.Equal(t, serviceNameField, services.Terms["field"])
+		operations := services.Aggs.Operations
+		assert.Equal(t, operationNameField, operations.Terms["field"])
+		tags := operations.Aggs.Tags
+		assert.Equal(t, nestedTagsField, tags.Nested["path"])
+		assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{nestedTagKeyField: "http.method"}}, tags.Aggs.TagKey.Filter)
+		values := tags.Aggs.TagKey.Aggs.TagValues
+		assert.Equal(t, nestedTagValueField, values.Terms["field"])
+		assert.Equal(t, []map[string]interface{}{
+			{"to": float64(1000)},
+			{"from": float64(1000), "to": float64(1000000)},
+			{"from": float64(1000000)},
+		}, values.Aggs.Spans.Aggs.Histogram.Range.Ranges)
+	})
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/es/mocks"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore/dbmodel"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
@@ -282,6 +283,14 @@ func TestSpanIndexName(t *testing.T) {
 	assert.Equal(t, "jaeger-service-1995-04-21", serviceIndexName)
 }
 
+func TestTenantIndexName(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	assert.Equal(t, "acme-jaeger-span-1995-04-21", tenantIndex(ctx, "jaeger-span-1995-04-21"))
+	assert.Equal(t, "acme-foo-jaeger-span-", tenantIndex(ctx, "foo-jaeger-span-"))
+	assert.Equal(t, "", tenantIndex(ctx, ""))
+	assert.Equal(t, "jaeger-span-1995-04-21", tenantIndex(context.Background(), "jaeger-span-1995-04-21"))
+}
+
 func TestWriteSpanInternal(t *testing.T) {
 	withSpanWriter(func(w *spanWriterTest) {
 		indexService := &mocks.IndexService{}
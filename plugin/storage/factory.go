@@ -19,6 +19,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
@@ -63,8 +64,11 @@ var AllStorageTypes = []string{cassandraStorageType, opensearchStorageType, elas
 type Factory struct {
 	FactoryConfig
 	metricsFactory         metrics.Factory
+	logger                 *zap.Logger
 	factories              map[string]storage.Factory
 	downsamplingFlagsAdded bool
+	fanoutFlagsAdded       bool
+	fanoutWriter           *spanstore.FanoutWriter
 }
 
 // NewFactory creates the meta-factory.
@@ -110,6 +114,7 @@ func (f *Factory) getFactoryOfType(factoryType string) (storage.Factory, error)
 // Initialize implements storage.Factory.
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory = metricsFactory
+	f.logger = logger
 	for _, factory := range f.factories {
 		if err := factory.Initialize(metricsFactory, logger); err != nil {
 			return err
@@ -146,6 +151,13 @@ func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
 	var spanWriter spanstore.Writer
 	if len(f.SpanWriterTypes) == 1 {
 		spanWriter = writers[0]
+	} else if f.Fanout.Enabled {
+		fanoutWriter, err := f.createFanoutWriter(writers)
+		if err != nil {
+			return nil, err
+		}
+		f.fanoutWriter = fanoutWriter
+		spanWriter = fanoutWriter
 	} else {
 		spanWriter = spanstore.NewCompositeWriter(writers...)
 	}
@@ -174,6 +186,52 @@ func (f *Factory) CreateSamplingStoreFactory() (storage.SamplingStoreFactory, er
 	return nil, nil
 }
 
+// CreateSpanMetricsStoreFactory returns the factory of the span metrics store, if the span storage supports it
+func (f *Factory) CreateSpanMetricsStoreFactory() (storage.SpanMetricsStoreFactory, error) {
+	factory, ok := f.factories[f.SpanReaderType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.SpanReaderType)
+	}
+	if sm, ok := factory.(storage.SpanMetricsStoreFactory); ok {
+		return sm, nil
+	}
+	// returning nothing is valid here, the span metrics are then only kept in memory by the collector
+	return nil, nil
+}
+
+// CreateMetricsFactory returns the factory of the metrics reader served by the span storage, if it supports it
+func (f *Factory) CreateMetricsFactory() (storage.MetricsFactory, error) {
+	factory, ok := f.factories[f.SpanReaderType]
+	if !ok {
+		return nil, fmt.Errorf("no %s backend registered for span store", f.SpanReaderType)
+	}
+	if ms, ok := factory.(storage.MetricsStoreFactory); ok {
+		return ms.CreateMetricsFactory()
+	}
+	// returning nothing is valid here, the metrics are then read from the backend selected by METRICS_STORAGE_TYPE
+	return nil, nil
+}
+
+// ValidateTenantIsolation returns an error if one of the configured storages does not isolate the data
+// of the tenants, in which case multi-tenancy cannot be enabled
+func (f *Factory) ValidateTenantIsolation() error {
+	storageTypes := make([]string, 0, len(f.factories))
+	for storageType := range f.factories {
+		storageTypes = append(storageTypes, storageType)
+	}
+	sort.Strings(storageTypes)
+	for _, storageType := range storageTypes {
+		factory, ok := f.factories[storageType].(storage.TenantIsolationFactory)
+		if !ok {
+			return fmt.Errorf("%s storage does not isolate the data of the tenants, it cannot be used with multi-tenancy", storageType)
+		}
+		if err := factory.ValidateTenantIsolation(); err != nil {
+			return fmt.Errorf("%s storage cannot be used with multi-tenancy: %w", storageType, err)
+		}
+	}
+	return nil
+}
+
 // CreateDependencyReader implements storage.Factory
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	factory, ok := f.factories[f.DependenciesStorageType]
@@ -193,11 +251,13 @@ func (f *Factory) AddFlags(flagSet *flag.FlagSet) {
 }
 
 // AddPipelineFlags adds all the standard flags as well as the downsampling
-//  flags.  This is intended to be used in Jaeger pipeline services such as
+//  and fan-out flags.  This is intended to be used in Jaeger pipeline services such as
 //  the collector or ingester.
 func (f *Factory) AddPipelineFlags(flagSet *flag.FlagSet) {
 	f.AddFlags(flagSet)
 	f.addDownsamplingFlags(flagSet)
+	f.fanoutFlagsAdded = true
+	f.addFanoutFlags(flagSet)
 }
 
 // addDownsamplingFlags add flags for Downsampling params
@@ -223,6 +283,7 @@ func (f *Factory) InitFromViper(v *viper.Viper, logger *zap.Logger) {
 		}
 	}
 	f.initDownsamplingFromViper(v)
+	f.initFanoutFromViper(v)
 }
 
 func (f *Factory) initDownsamplingFromViper(v *viper.Viper) {
@@ -273,6 +334,12 @@ var _ io.Closer = (*Factory)(nil)
 // Close closes the resources held by the factory
 func (f *Factory) Close() error {
 	var errs []error
+	if f.fanoutWriter != nil {
+		// writes the queued spans before the storages are closed
+		if err := f.fanoutWriter.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	for _, storageType := range f.SpanWriterTypes {
 		if factory, ok := f.factories[storageType]; ok {
 			if closer, ok := factory.(io.Closer); ok {
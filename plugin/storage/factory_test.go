@@ -34,6 +34,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc"
+	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	depStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
@@ -256,6 +258,69 @@ func TestCreateArchive(t *testing.T) {
 	assert.EqualError(t, err, "archive-span-writer-error")
 }
 
+func TestCreateSpanMetricsStoreFactory(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+
+	smFactory, err := f.CreateSpanMetricsStoreFactory()
+	require.NoError(t, err)
+	assert.Nil(t, smFactory)
+
+	memoryFactory := memory.NewFactory()
+	f.factories[cassandraStorageType] = memoryFactory
+	smFactory, err = f.CreateSpanMetricsStoreFactory()
+	require.NoError(t, err)
+	assert.Equal(t, memoryFactory, smFactory)
+
+	delete(f.factories, cassandraStorageType)
+	_, err = f.CreateSpanMetricsStoreFactory()
+	assert.EqualError(t, err, "no cassandra backend registered for span store")
+}
+
+func TestCreateMetricsFactory(t *testing.T) {
+	f, err := NewFactory(defaultCfg())
+	require.NoError(t, err)
+
+	mFactory, err := f.CreateMetricsFactory()
+	require.NoError(t, err)
+	assert.Nil(t, mFactory)
+
+	f.factories[cassandraStorageType] = grpc.NewFactory()
+	mFactory, err = f.CreateMetricsFactory()
+	require.NoError(t, err)
+	assert.NotNil(t, mFactory)
+
+	delete(f.factories, cassandraStorageType)
+	_, err = f.CreateMetricsFactory()
+	assert.EqualError(t, err, "no cassandra backend registered for span store")
+}
+
+type isolatingFactory struct {
+	mocks.Factory
+	err error
+}
+
+func (f *isolatingFactory) ValidateTenantIsolation() error {
+	return f.err
+}
+
+func TestValidateTenantIsolation(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, memoryStorageType}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	err = f.ValidateTenantIsolation()
+	assert.EqualError(t, err, "cassandra storage does not isolate the data of the tenants, it cannot be used with multi-tenancy")
+
+	f.factories[cassandraStorageType] = &isolatingFactory{err: errors.New("aliases")}
+	err = f.ValidateTenantIsolation()
+	assert.EqualError(t, err, "cassandra storage cannot be used with multi-tenancy: aliases")
+
+	f.factories[cassandraStorageType] = &isolatingFactory{}
+	assert.NoError(t, f.ValidateTenantIsolation())
+}
+
 func TestCreateError(t *testing.T) {
 	f, err := NewFactory(defaultCfg())
 	require.NoError(t, err)
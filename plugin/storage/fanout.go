@@ -0,0 +1,242 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/uber/jaeger-lib/metrics"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	fanoutEnabled = "span-storage.fanout.enabled"
+	fanoutPrefix  = "span-storage.fanout."
+
+	suffixFanoutQueueSize       = ".queue-size"
+	suffixFanoutWorkers         = ".workers"
+	suffixFanoutMaxRetries      = ".max-retries"
+	suffixFanoutRetryBackoff    = ".retry-backoff"
+	suffixFanoutMaxRetryBackoff = ".max-retry-backoff"
+	suffixFanoutFilter          = ".filter"
+	suffixFanoutSamplingRatio   = ".sampling-ratio"
+
+	// FanoutStatusRoute is the route of the admin server serving the health of the fan-out destinations.
+	FanoutStatusRoute = "/storage/fanout"
+
+	fanoutFilterAll    = "all"
+	fanoutFilterErrors = "errors"
+	fanoutFilterDebug  = "debug"
+)
+
+// FanoutConfig configures the fan-out of the spans to the span writer types.
+type FanoutConfig struct {
+	// Enabled makes the writes to the span writer types asynchronous and independent of each other
+	Enabled      bool
+	Destinations map[string]FanoutDestinationConfig
+}
+
+// FanoutDestinationConfig configures the writes to a span writer type when the fan-out is enabled.
+type FanoutDestinationConfig struct {
+	QueueSize       int
+	Workers         int
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// Filter is the list of the kinds of spans written, any of them matching
+	Filter []string
+	// SamplingRatio is the ratio of the traces written, after the filter
+	SamplingRatio float64
+}
+
+// addFanoutFlags adds the fan-out flags, which only exist when several span writer types are configured.
+func (f *Factory) addFanoutFlags(flagSet *flag.FlagSet) {
+	if len(f.SpanWriterTypes) < 2 {
+		return
+	}
+	flagSet.Bool(
+		fanoutEnabled,
+		false,
+		"Writes the spans to each span storage type asynchronously, with its own queue, workers, retries and filter, "+
+			"so that a slow or failing storage does not affect the other ones.",
+	)
+	for _, storageType := range f.SpanWriterTypes {
+		prefix := fanoutPrefix + storageType
+		flagSet.Int(prefix+suffixFanoutQueueSize, spanstore.DefaultFanoutQueueSize,
+			fmt.Sprintf("The maximum number of spans queued for %s, beyond which the spans are dropped.", storageType))
+		flagSet.Int(prefix+suffixFanoutWorkers, spanstore.DefaultFanoutWorkers,
+			fmt.Sprintf("The number of concurrent writes to %s.", storageType))
+		flagSet.Int(prefix+suffixFanoutMaxRetries, spanstore.DefaultFanoutMaxRetries,
+			fmt.Sprintf("The number of times a failed write to %s is retried.", storageType))
+		flagSet.Duration(prefix+suffixFanoutRetryBackoff, spanstore.DefaultFanoutRetryBackoff,
+			fmt.Sprintf("The delay before the first retry of a failed write to %s, doubled for each retry.", storageType))
+		flagSet.Duration(prefix+suffixFanoutMaxRetryBackoff, spanstore.DefaultFanoutMaxRetryBackoff,
+			fmt.Sprintf("The maximum delay between the retries of a failed write to %s.", storageType))
+		flagSet.String(prefix+suffixFanoutFilter, fanoutFilterAll,
+			fmt.Sprintf("The comma separated kinds of spans written to %s: %s, %s (spans with the error tag) or %s (debug spans).",
+				storageType, fanoutFilterAll, fanoutFilterErrors, fanoutFilterDebug))
+		flagSet.Float64(prefix+suffixFanoutSamplingRatio, 1.0,
+			fmt.Sprintf("The ratio of the traces written to %s (between 0 and 1), after the filter.", storageType))
+	}
+}
+
+func (f *Factory) initFanoutFromViper(v *viper.Viper) {
+	if !f.fanoutFlagsAdded || len(f.SpanWriterTypes) < 2 {
+		f.FactoryConfig.Fanout = FanoutConfig{}
+		return
+	}
+	f.FactoryConfig.Fanout.Enabled = v.GetBool(fanoutEnabled)
+	f.FactoryConfig.Fanout.Destinations = make(map[string]FanoutDestinationConfig)
+	for _, storageType := range f.SpanWriterTypes {
+		prefix := fanoutPrefix + storageType
+		var filter []string
+		for _, kind := range strings.Split(v.GetString(prefix+suffixFanoutFilter), ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				filter = append(filter, kind)
+			}
+		}
+		f.FactoryConfig.Fanout.Destinations[storageType] = FanoutDestinationConfig{
+			QueueSize:       v.GetInt(prefix + suffixFanoutQueueSize),
+			Workers:         v.GetInt(prefix + suffixFanoutWorkers),
+			MaxRetries:      v.GetInt(prefix + suffixFanoutMaxRetries),
+			RetryBackoff:    v.GetDuration(prefix + suffixFanoutRetryBackoff),
+			MaxRetryBackoff: v.GetDuration(prefix + suffixFanoutMaxRetryBackoff),
+			Filter:          filter,
+			SamplingRatio:   v.GetFloat64(prefix + suffixFanoutSamplingRatio),
+		}
+	}
+}
+
+func (f *Factory) createFanoutWriter(writers []spanstore.Writer) (*spanstore.FanoutWriter, error) {
+	destinations := make([]spanstore.FanoutDestination, len(writers))
+	for i, storageType := range f.SpanWriterTypes {
+		cfg, ok := f.Fanout.Destinations[storageType]
+		if !ok {
+			cfg = FanoutDestinationConfig{SamplingRatio: 1.0}
+		}
+		filter, err := newFanoutFilter(cfg.Filter, cfg.SamplingRatio)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fan-out configuration of %s: %w", storageType, err)
+		}
+		destinations[i] = spanstore.FanoutDestination{
+			Name:            storageType,
+			Writer:          writers[i],
+			Filter:          filter,
+			QueueSize:       cfg.QueueSize,
+			Workers:         cfg.Workers,
+			MaxRetries:      cfg.MaxRetries,
+			RetryBackoff:    cfg.RetryBackoff,
+			MaxRetryBackoff: cfg.MaxRetryBackoff,
+		}
+	}
+	return spanstore.NewFanoutWriter(destinations, spanstore.FanoutOptions{
+		MetricsFactory: f.metricsFactory.Namespace(metrics.NSOptions{Name: "fanout_writer"}),
+		Logger:         f.logger,
+	}), nil
+}
+
+type fanoutDestinationStatus struct {
+	Name        string `json:"name"`
+	Healthy     bool   `json:"healthy"`
+	LastError   string `json:"lastError,omitempty"`
+	QueueLength int    `json:"queueLength"`
+}
+
+// FanoutStatusHandler returns the HTTP handler serving the health of the fan-out destinations as JSON,
+// with a 503 status code if a destination is unhealthy. It returns nil if the spans are not written
+// with a fan-out writer, and must be called after CreateSpanWriter.
+func (f *Factory) FanoutStatusHandler() http.Handler {
+	if f.fanoutWriter == nil {
+		return nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCode := http.StatusOK
+		var destinations []fanoutDestinationStatus
+		for _, s := range f.fanoutWriter.Status() {
+			d := fanoutDestinationStatus{Name: s.Name, Healthy: s.Healthy, QueueLength: s.QueueLength}
+			if s.LastError != nil {
+				d.LastError = s.LastError.Error()
+			}
+			if !s.Healthy {
+				statusCode = http.StatusServiceUnavailable
+			}
+			destinations = append(destinations, d)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(map[string]interface{}{"destinations": destinations})
+	})
+}
+
+// newFanoutFilter returns the filter selecting the spans of the given kinds from the sampled traces,
+// or nil if all the spans are selected.
+func newFanoutFilter(kinds []string, samplingRatio float64) (func(span *model.Span) bool, error) {
+	if samplingRatio < 0 || samplingRatio > 1 {
+		return nil, fmt.Errorf("sampling ratio %v is not between 0 and 1", samplingRatio)
+	}
+	all := false
+	var matchers []func(span *model.Span) bool
+	for _, kind := range kinds {
+		switch kind {
+		case fanoutFilterAll:
+			all = true
+		case fanoutFilterErrors:
+			matchers = append(matchers, isErrorSpan)
+		case fanoutFilterDebug:
+			matchers = append(matchers, func(span *model.Span) bool { return span.Flags.IsDebug() })
+		default:
+			return nil, fmt.Errorf("unknown span filter %q, valid filters are %s, %s and %s",
+				kind, fanoutFilterAll, fanoutFilterErrors, fanoutFilterDebug)
+		}
+	}
+	if all {
+		matchers = nil
+	}
+	var sampler *spanstore.Sampler
+	if samplingRatio < 1 {
+		sampler = spanstore.NewSampler(samplingRatio, "")
+	}
+	if len(matchers) == 0 && sampler == nil {
+		return nil, nil
+	}
+	return func(span *model.Span) bool {
+		if len(matchers) > 0 {
+			matched := false
+			for _, match := range matchers {
+				if match(span) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		return sampler == nil || sampler.ShouldSample(span)
+	}, nil
+}
+
+func isErrorSpan(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey("error")
+	return ok && tag.AsString() == "true"
+}
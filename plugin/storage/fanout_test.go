@@ -0,0 +1,234 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/storage/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+)
+
+func TestParsingFanoutFlags(t *testing.T) {
+	f := Factory{FactoryConfig: FactoryConfig{SpanWriterTypes: []string{elasticsearchStorageType, kafkaStorageType}}}
+	v, command := config.Viperize(f.AddPipelineFlags)
+	err := command.ParseFlags([]string{
+		"--span-storage.fanout.enabled=true",
+		"--span-storage.fanout.elasticsearch.filter=errors, debug",
+		"--span-storage.fanout.elasticsearch.sampling-ratio=0.5",
+		"--span-storage.fanout.kafka.queue-size=100",
+		"--span-storage.fanout.kafka.workers=2",
+		"--span-storage.fanout.kafka.max-retries=5",
+		"--span-storage.fanout.kafka.retry-backoff=1s",
+		"--span-storage.fanout.kafka.max-retry-backoff=1m",
+	})
+	require.NoError(t, err)
+	f.InitFromViper(v, zap.NewNop())
+
+	assert.True(t, f.Fanout.Enabled)
+	assert.Equal(t, FanoutDestinationConfig{
+		QueueSize:       spanstore.DefaultFanoutQueueSize,
+		Workers:         spanstore.DefaultFanoutWorkers,
+		MaxRetries:      spanstore.DefaultFanoutMaxRetries,
+		RetryBackoff:    spanstore.DefaultFanoutRetryBackoff,
+		MaxRetryBackoff: spanstore.DefaultFanoutMaxRetryBackoff,
+		Filter:          []string{fanoutFilterErrors, fanoutFilterDebug},
+		SamplingRatio:   0.5,
+	}, f.Fanout.Destinations[elasticsearchStorageType])
+	assert.Equal(t, FanoutDestinationConfig{
+		QueueSize:       100,
+		Workers:         2,
+		MaxRetries:      5,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: time.Minute,
+		Filter:          []string{fanoutFilterAll},
+		SamplingRatio:   1.0,
+	}, f.Fanout.Destinations[kafkaStorageType])
+}
+
+func TestFanoutFlagsWithSingleStorageType(t *testing.T) {
+	f := Factory{FactoryConfig: FactoryConfig{SpanWriterTypes: []string{elasticsearchStorageType}}}
+	v, command := config.Viperize(f.AddPipelineFlags)
+	assert.Error(t, command.ParseFlags([]string{"--span-storage.fanout.enabled=true"}))
+	f.InitFromViper(v, zap.NewNop())
+	assert.False(t, f.Fanout.Enabled)
+}
+
+func TestCreateFanoutWriter(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType}
+	cfg.Fanout = FanoutConfig{
+		Enabled: true,
+		Destinations: map[string]FanoutDestinationConfig{
+			kafkaStorageType: {Filter: []string{fanoutFilterErrors}, SamplingRatio: 1},
+		},
+	}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+
+	cassandraWriter := new(spanStoreMocks.Writer)
+	kafkaWriter := new(spanStoreMocks.Writer)
+	for storageType, writer := range map[string]*spanStoreMocks.Writer{cassandraStorageType: cassandraWriter, kafkaStorageType: kafkaWriter} {
+		factory := new(mocks.Factory)
+		factory.On("Initialize", mock.Anything, mock.Anything).Return(nil)
+		factory.On("CreateSpanWriter").Return(writer, nil)
+		f.factories[storageType] = factory
+	}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	require.IsType(t, &spanstore.FanoutWriter{}, w)
+
+	errorSpan := &model.Span{Tags: []model.KeyValue{model.Bool("error", true)}}
+	okSpan := &model.Span{}
+	cassandraWriter.On("WriteSpan", mock.Anything, errorSpan).Return(nil).Once()
+	cassandraWriter.On("WriteSpan", mock.Anything, okSpan).Return(nil).Once()
+	kafkaWriter.On("WriteSpan", mock.Anything, errorSpan).Return(nil).Once()
+	require.NoError(t, w.WriteSpan(context.Background(), errorSpan))
+	require.NoError(t, w.WriteSpan(context.Background(), okSpan))
+
+	require.NoError(t, f.Close())
+	cassandraWriter.AssertExpectations(t)
+	kafkaWriter.AssertExpectations(t)
+}
+
+func TestFanoutStatusHandler(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+	cassandraWriter := new(spanStoreMocks.Writer)
+	kafkaWriter := new(spanStoreMocks.Writer)
+	for storageType, writer := range map[string]*spanStoreMocks.Writer{cassandraStorageType: cassandraWriter, kafkaStorageType: kafkaWriter} {
+		factory := new(mocks.Factory)
+		factory.On("Initialize", mock.Anything, mock.Anything).Return(nil)
+		factory.On("CreateSpanWriter").Return(writer, nil)
+		f.factories[storageType] = factory
+	}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	_, err = f.CreateSpanWriter()
+	require.NoError(t, err)
+	assert.Nil(t, f.FanoutStatusHandler(), "the composite writer has no status")
+
+	f.Fanout = FanoutConfig{
+		Enabled: true,
+		Destinations: map[string]FanoutDestinationConfig{
+			kafkaStorageType: {MaxRetries: -1, SamplingRatio: 1},
+		},
+	}
+	w, err := f.CreateSpanWriter()
+	require.NoError(t, err)
+	defer w.(*spanstore.FanoutWriter).Close()
+	handler := f.FanoutStatusHandler()
+	require.NotNil(t, handler)
+
+	getStatus := func() (int, map[string][]fanoutDestinationStatus) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, FanoutStatusRoute, nil))
+		var body map[string][]fanoutDestinationStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+	code, body := getStatus()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []fanoutDestinationStatus{{Name: cassandraStorageType, Healthy: true}, {Name: kafkaStorageType, Healthy: true}}, body["destinations"])
+
+	span := &model.Span{}
+	cassandraWriter.On("WriteSpan", mock.Anything, span).Return(nil)
+	kafkaWriter.On("WriteSpan", mock.Anything, span).Return(errors.New("broker unavailable"))
+	require.NoError(t, w.WriteSpan(context.Background(), span))
+	assert.Eventually(t, func() bool {
+		code, _ := getStatus()
+		return code == http.StatusServiceUnavailable
+	}, time.Second, time.Millisecond)
+	_, body = getStatus()
+	assert.Equal(t, fanoutDestinationStatus{Name: kafkaStorageType, LastError: "broker unavailable"}, body["destinations"][1])
+}
+
+func TestCreateFanoutWriterInvalidFilter(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.SpanWriterTypes = []string{cassandraStorageType, kafkaStorageType}
+	cfg.Fanout = FanoutConfig{
+		Enabled: true,
+		Destinations: map[string]FanoutDestinationConfig{
+			kafkaStorageType: {Filter: []string{"slow"}, SamplingRatio: 1},
+		},
+	}
+	f, err := NewFactory(cfg)
+	require.NoError(t, err)
+	for _, storageType := range cfg.SpanWriterTypes {
+		factory := new(mocks.Factory)
+		factory.On("Initialize", mock.Anything, mock.Anything).Return(nil)
+		factory.On("CreateSpanWriter").Return(new(spanStoreMocks.Writer), nil)
+		f.factories[storageType] = factory
+	}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	_, err = f.CreateSpanWriter()
+	assert.EqualError(t, err, `invalid fan-out configuration of kafka: unknown span filter "slow", valid filters are all, errors and debug`)
+}
+
+func TestNewFanoutFilter(t *testing.T) {
+	errorSpan := &model.Span{Tags: []model.KeyValue{model.String("error", "true")}}
+	debugSpan := &model.Span{Flags: model.DebugFlag}
+	span := &model.Span{Tags: []model.KeyValue{model.Bool("error", false)}}
+
+	filter, err := newFanoutFilter(nil, 1)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = newFanoutFilter([]string{fanoutFilterErrors, fanoutFilterAll}, 1)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = newFanoutFilter([]string{fanoutFilterErrors}, 1)
+	require.NoError(t, err)
+	assert.True(t, filter(errorSpan))
+	assert.False(t, filter(debugSpan))
+	assert.False(t, filter(span))
+
+	filter, err = newFanoutFilter([]string{fanoutFilterErrors, fanoutFilterDebug}, 1)
+	require.NoError(t, err)
+	assert.True(t, filter(errorSpan))
+	assert.True(t, filter(debugSpan))
+	assert.False(t, filter(span))
+
+	filter, err = newFanoutFilter([]string{fanoutFilterErrors}, 0)
+	require.NoError(t, err)
+	assert.False(t, filter(errorSpan))
+
+	filter, err = newFanoutFilter(nil, 0)
+	require.NoError(t, err)
+	assert.False(t, filter(span))
+
+	_, err = newFanoutFilter(nil, 1.5)
+	assert.EqualError(t, err, "sampling ratio 1.5 is not between 0 and 1")
+}
@@ -15,39 +15,113 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"runtime"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/health" // enables the client side health checking
 
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+	"github.com/jaegertracing/jaeger/pkg/multierror"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 )
 
+// remoteServiceConfig balances the calls over all the addresses of the remote server,
+// and only uses the connections reported as serving by the gRPC health service.
+const remoteServiceConfig = `{"loadBalancingPolicy":"round_robin","healthCheckConfig":{"serviceName":""}}`
+
 // Configuration describes the options to customize the storage behavior.
 type Configuration struct {
 	PluginBinary            string `yaml:"binary" mapstructure:"binary"`
 	PluginConfigurationFile string `yaml:"configuration-file" mapstructure:"configuration_file"`
 	PluginLogLevel          string `yaml:"log-level" mapstructure:"log_level"`
+	// WriteBatchSize is the maximum number of spans of a batch written on the WriteSpans stream,
+	// for the plugins supporting it. Spans are written one by one when it is lower than 2, the default.
+	WriteBatchSize          int           `yaml:"write-batch-size" mapstructure:"write_batch_size"`
+	WriteBatchFlushInterval time.Duration `yaml:"write-batch-flush-interval" mapstructure:"write_batch_flush_interval"`
+	WriteQueueSize          int           `yaml:"write-queue-size" mapstructure:"write_queue_size"`
+	// RemoteServerAddr is the address of a running remote storage server. When set, the plugin binary
+	// is not launched and the storage services are called over the network.
+	RemoteServerAddr     string         `yaml:"remote-server" mapstructure:"remote_server"`
+	RemoteTLS            tlscfg.Options `yaml:"remote-tls" mapstructure:"remote_tls"`
+	RemoteConnectTimeout time.Duration  `yaml:"remote-connection-timeout" mapstructure:"remote_connection_timeout"`
 }
 
 // ClientPluginServices defines services plugin can expose and its capabilities
 type ClientPluginServices struct {
 	shared.PluginServices
 	Capabilities shared.PluginCapabilities
+	// Closer releases the connection to the remote server, it is nil for the plugins launched as subprocesses
+	Closer io.Closer
 }
 
 // PluginBuilder is used to create storage plugins. Implemented by Configuration.
 type PluginBuilder interface {
-	Build() (*ClientPluginServices, error)
+	Build(logger *zap.Logger) (*ClientPluginServices, error)
+}
+
+// Build instantiates a PluginServices, connected to the remote server if configured,
+// and to a plugin binary launched as a subprocess otherwise.
+func (c *Configuration) Build(logger *zap.Logger) (*ClientPluginServices, error) {
+	if c.RemoteServerAddr != "" {
+		return c.buildRemote(logger)
+	}
+	return c.buildPlugin()
 }
 
-// Build instantiates a PluginServices
-func (c *Configuration) Build() (*ClientPluginServices, error) {
+func (c *Configuration) buildRemote(logger *zap.Logger) (*ClientPluginServices, error) {
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithDefaultServiceConfig(remoteServiceConfig),
+		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer())),
+		grpc.WithStreamInterceptor(otgrpc.OpenTracingStreamClientInterceptor(opentracing.GlobalTracer())),
+	}
+	if c.RemoteTLS.Enabled {
+		tlsCfg, err := c.RemoteTLS.Config(logger)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.RemoteConnectTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, c.RemoteServerAddr, opts...)
+	if err != nil {
+		c.RemoteTLS.Close()
+		return nil, fmt.Errorf("error connecting to remote storage server %s: %w", c.RemoteServerAddr, err)
+	}
+
+	// the remote server exposes the same services as the plugins
+	raw, err := (&shared.StorageGRPCPlugin{}).GRPCClient(ctx, nil, conn)
+	if err != nil {
+		conn.Close()
+		c.RemoteTLS.Close()
+		return nil, err
+	}
+	services, err := newClientPluginServices(raw)
+	if err != nil {
+		conn.Close()
+		c.RemoteTLS.Close()
+		return nil, err
+	}
+	services.Closer = &remoteConnection{conn: conn, tls: &c.RemoteTLS}
+	return services, nil
+}
+
+func (c *Configuration) buildPlugin() (*ClientPluginServices, error) {
 	// #nosec G204
 	cmd := exec.Command(c.PluginBinary, "--config", c.PluginConfigurationFile)
 
@@ -80,7 +154,10 @@ func (c *Configuration) Build() (*ClientPluginServices, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve storage plugin instance: %w", err)
 	}
+	return newClientPluginServices(raw)
+}
 
+func newClientPluginServices(raw interface{}) (*ClientPluginServices, error) {
 	// in practice, the type of `raw` is *shared.grpcClient, and type casts below cannot fail
 	storagePlugin, ok := raw.(shared.StoragePlugin)
 	if !ok {
@@ -98,11 +175,39 @@ func (c *Configuration) Build() (*ClientPluginServices, error) {
 			raw, shared.StoragePluginIdentifier)
 	}
 
-	return &ClientPluginServices{
+	services := &ClientPluginServices{
 		PluginServices: shared.PluginServices{
 			Store:        storagePlugin,
 			ArchiveStore: archiveStoragePlugin,
 		},
 		Capabilities: capabilities,
-	}, nil
+	}
+	// whether the plugin supports the optional services is known from its capabilities
+	if dependenciesWriter, ok := raw.(shared.DependenciesWriterPlugin); ok {
+		services.DependenciesWriter = dependenciesWriter
+	}
+	if samplingStore, ok := raw.(shared.SamplingStorePlugin); ok {
+		services.SamplingStore = samplingStore
+	}
+	if metricsReader, ok := raw.(shared.MetricsReaderPlugin); ok {
+		services.MetricsReader = metricsReader
+	}
+	return services, nil
+}
+
+// remoteConnection closes the connection to the remote server and stops watching the TLS certificates
+type remoteConnection struct {
+	conn *grpc.ClientConn
+	tls  *tlscfg.Options
+}
+
+func (r *remoteConnection) Close() error {
+	var errs []error
+	if err := r.conn.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.tls.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return multierror.Wrap(errs)
 }
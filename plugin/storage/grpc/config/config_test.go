@@ -0,0 +1,97 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
+)
+
+func TestBuildRemote(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	cfg := &Configuration{
+		RemoteServerAddr:     lis.Addr().String(),
+		RemoteConnectTimeout: time.Second,
+	}
+	services, err := cfg.Build(zap.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, services.Store)
+	assert.NotNil(t, services.ArchiveStore)
+	assert.NotNil(t, services.Capabilities)
+	require.NotNil(t, services.Closer)
+	assert.NoError(t, services.Closer.Close())
+}
+
+func TestBuildRemoteNotServing(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	cfg := &Configuration{
+		RemoteServerAddr:     lis.Addr().String(),
+		RemoteConnectTimeout: 100 * time.Millisecond,
+	}
+	_, err = cfg.Build(zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestBuildRemoteConnectionTimeout(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	cfg := &Configuration{
+		RemoteServerAddr:     addr,
+		RemoteConnectTimeout: 100 * time.Millisecond,
+	}
+	_, err = cfg.Build(zap.NewNop())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error connecting to remote storage server "+addr)
+}
+
+func TestBuildRemoteTLSError(t *testing.T) {
+	cfg := &Configuration{
+		RemoteServerAddr: "localhost:17271",
+		RemoteTLS: tlscfg.Options{
+			Enabled: true,
+			CAPath:  "invalid/path",
+		},
+	}
+	_, err := cfg.Build(zap.NewNop())
+	assert.Error(t, err)
+}
@@ -15,20 +15,33 @@
 package grpc
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+var (
+	errSamplingStoreNotSupported = errors.New("the storage plugin does not support adaptive sampling")
+	errMetricsReaderNotSupported = errors.New("the storage plugin does not support reading metrics")
+
+	_ storage.SamplingStoreFactory = (*Factory)(nil)
+	_ storage.MetricsStoreFactory  = (*Factory)(nil)
+)
+
 // Factory implements storage.Factory and creates storage components backed by a storage plugin.
 type Factory struct {
 	options        Options
@@ -37,9 +50,13 @@ type Factory struct {
 
 	builder config.PluginBuilder
 
-	store        shared.StoragePlugin
-	archiveStore shared.ArchiveStoragePlugin
-	capabilities shared.PluginCapabilities
+	store              shared.StoragePlugin
+	archiveStore       shared.ArchiveStoragePlugin
+	dependenciesWriter shared.DependenciesWriterPlugin
+	samplingStore      shared.SamplingStorePlugin
+	metricsReader      shared.MetricsReaderPlugin
+	capabilities       shared.PluginCapabilities
+	closer             io.Closer
 }
 
 // NewFactory creates a new Factory.
@@ -68,14 +85,18 @@ func (f *Factory) InitFromOptions(opts Options) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
 
-	services, err := f.builder.Build()
+	services, err := f.builder.Build(logger)
 	if err != nil {
 		return fmt.Errorf("grpc-plugin builder failed to create a store: %w", err)
 	}
 
 	f.store = services.Store
 	f.archiveStore = services.ArchiveStore
+	f.dependenciesWriter = services.DependenciesWriter
+	f.samplingStore = services.SamplingStore
+	f.metricsReader = services.MetricsReader
 	f.capabilities = services.Capabilities
+	f.closer = services.Closer
 	logger.Info("External plugin storage configuration", zap.Any("configuration", f.options.Configuration))
 	return nil
 }
@@ -85,14 +106,112 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 	return f.store.SpanReader(), nil
 }
 
-// CreateSpanWriter implements storage.Factory
+// CreateSpanWriter implements storage.Factory. The spans are written in batches on a stream
+// when the plugin supports it, and one by one otherwise.
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
+	if f.options.Configuration.WriteBatchSize > 1 {
+		writer, err := f.createStreamingSpanWriter()
+		if err != nil || writer != nil {
+			return writer, err
+		}
+	}
 	return f.store.SpanWriter(), nil
 }
 
-// CreateDependencyReader implements storage.Factory
+// createStreamingSpanWriter returns nil if the plugin does not support streaming writes
+func (f *Factory) createStreamingSpanWriter() (spanstore.Writer, error) {
+	streamingPlugin, ok := f.store.(shared.StreamingSpanWriterPlugin)
+	if !ok || f.capabilities == nil {
+		return nil, nil
+	}
+	capabilities, err := f.capabilities.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+	if capabilities == nil || !capabilities.StreamingSpanWriter {
+		f.logger.Info("Storage plugin does not support streaming writes, spans are written one by one")
+		return nil, nil
+	}
+	return streamingPlugin.StreamingSpanWriter(shared.StreamingWriterOptions{
+		BatchSize:      f.options.Configuration.WriteBatchSize,
+		FlushInterval:  f.options.Configuration.WriteBatchFlushInterval,
+		QueueSize:      f.options.Configuration.WriteQueueSize,
+		MetricsFactory: f.metricsFactory.Namespace(metrics.NSOptions{Name: "grpc_plugin_writer"}),
+		Logger:         f.logger,
+	}), nil
+}
+
+// CreateDependencyReader implements storage.Factory. The returned reader also implements
+// dependencystore.Writer when the plugin accepts the dependencies computed by Jaeger.
 func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
-	return f.store.DependencyReader(), nil
+	if f.dependenciesWriter == nil {
+		return f.store.DependencyReader(), nil
+	}
+	supported, err := f.supports(func(c *shared.Capabilities) bool { return c.DependenciesWriter })
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return f.store.DependencyReader(), nil
+	}
+	return struct {
+		dependencystore.Reader
+		dependencystore.Writer
+	}{f.store.DependencyReader(), f.dependenciesWriter.DependencyWriter()}, nil
+}
+
+// ValidateTenantIsolation implements storage.TenantIsolationFactory, the tenant is sent to the plugin
+// in the gRPC metadata, and the plugin is responsible for isolating the data of the tenants.
+func (f *Factory) ValidateTenantIsolation() error {
+	return nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	if err := f.checkSamplingStore(); err != nil {
+		return nil, err
+	}
+	return f.samplingStore.SamplingStore(), nil
+}
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	if err := f.checkSamplingStore(); err != nil {
+		return nil, err
+	}
+	return f.samplingStore.Lock(), nil
+}
+
+func (f *Factory) checkSamplingStore() error {
+	if f.samplingStore == nil {
+		return errSamplingStoreNotSupported
+	}
+	supported, err := f.supports(func(c *shared.Capabilities) bool { return c.SamplingStore })
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return errSamplingStoreNotSupported
+	}
+	return nil
+}
+
+// CreateMetricsFactory implements storage.MetricsStoreFactory. The metrics reader can only be
+// created by the returned factory if the plugin supports it.
+func (f *Factory) CreateMetricsFactory() (storage.MetricsFactory, error) {
+	return &metricsFactory{factory: f}, nil
+}
+
+// supports returns whether the plugin advertises the capability checked by the given function
+func (f *Factory) supports(capability func(*shared.Capabilities) bool) (bool, error) {
+	if f.capabilities == nil {
+		return false, nil
+	}
+	capabilities, err := f.capabilities.Capabilities()
+	if err != nil {
+		return false, err
+	}
+	return capabilities != nil && capability(capabilities), nil
 }
 
 // CreateArchiveSpanReader implements storage.ArchiveFactory
@@ -124,3 +243,36 @@ func (f *Factory) CreateArchiveSpanWriter() (spanstore.Writer, error) {
 	}
 	return f.archiveStore.ArchiveSpanWriter(), nil
 }
+
+// Close closes the connection to the remote storage server, if any
+func (f *Factory) Close() error {
+	if f.closer != nil {
+		return f.closer.Close()
+	}
+	return nil
+}
+
+// metricsFactory implements storage.MetricsFactory on top of the storage plugin
+type metricsFactory struct {
+	factory *Factory
+}
+
+// Initialize implements storage.MetricsFactory, the plugin is initialized by the storage factory
+func (m *metricsFactory) Initialize(logger *zap.Logger) error {
+	return nil
+}
+
+// CreateMetricsReader implements storage.MetricsFactory
+func (m *metricsFactory) CreateMetricsReader() (metricsstore.Reader, error) {
+	if m.factory.metricsReader == nil {
+		return nil, errMetricsReaderNotSupported
+	}
+	supported, err := m.factory.supports(func(c *shared.Capabilities) bool { return c.MetricsReader })
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, errMetricsReaderNotSupported
+	}
+	return m.factory.metricsReader.MetricsReader(), nil
+}
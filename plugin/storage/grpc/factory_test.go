@@ -16,36 +16,56 @@ package grpc
 
 import (
 	"errors"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/config"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	lockMocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
 	grpcConfig "github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/mocks"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 	"github.com/jaegertracing/jaeger/storage"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	dependencyStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	metricsStoreMocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	samplingStoreMocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
 )
 
-var _ storage.Factory = new(Factory)
+var (
+	_ storage.Factory              = new(Factory)
+	_ storage.SamplingStoreFactory = new(Factory)
+	_ storage.MetricsFactory       = new(metricsFactory)
+)
 
 type mockPluginBuilder struct {
 	plugin *mockPlugin
+	closer io.Closer
 	err    error
 }
 
-func (b *mockPluginBuilder) Build() (*grpcConfig.ClientPluginServices, error) {
+func (b *mockPluginBuilder) Build(logger *zap.Logger) (*grpcConfig.ClientPluginServices, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
+	if b.closer != nil {
+		return &grpcConfig.ClientPluginServices{
+			PluginServices: shared.PluginServices{Store: b.plugin},
+			Closer:         b.closer,
+		}, nil
+	}
 
 	services := &grpcConfig.ClientPluginServices{
 		PluginServices: shared.PluginServices{
@@ -56,6 +76,15 @@ func (b *mockPluginBuilder) Build() (*grpcConfig.ClientPluginServices, error) {
 	if b.plugin.capabilities != nil {
 		services.Capabilities = b.plugin
 	}
+	if b.plugin.dependencyWriter != nil {
+		services.DependenciesWriter = b.plugin
+	}
+	if b.plugin.samplingStore != nil {
+		services.SamplingStore = b.plugin
+	}
+	if b.plugin.metricsReader != nil {
+		services.MetricsReader = b.plugin
+	}
 
 	return services, nil
 }
@@ -67,6 +96,10 @@ type mockPlugin struct {
 	archiveWriter    spanstore.Writer
 	capabilities     shared.PluginCapabilities
 	dependencyReader dependencystore.Reader
+	dependencyWriter dependencystore.Writer
+	samplingStore    samplingstore.Store
+	lock             distributedlock.Lock
+	metricsReader    metricsstore.Reader
 }
 
 func (mp *mockPlugin) Capabilities() (*shared.Capabilities, error) {
@@ -93,6 +126,22 @@ func (mp *mockPlugin) DependencyReader() dependencystore.Reader {
 	return mp.dependencyReader
 }
 
+func (mp *mockPlugin) DependencyWriter() dependencystore.Writer {
+	return mp.dependencyWriter
+}
+
+func (mp *mockPlugin) SamplingStore() samplingstore.Store {
+	return mp.samplingStore
+}
+
+func (mp *mockPlugin) Lock() distributedlock.Lock {
+	return mp.lock
+}
+
+func (mp *mockPlugin) MetricsReader() metricsstore.Reader {
+	return mp.metricsReader
+}
+
 func TestGRPCStorageFactory(t *testing.T) {
 	f := NewFactory()
 	v := viper.New()
@@ -241,6 +290,216 @@ func TestGRPCStorageFactory_CapabilitiesNil(t *testing.T) {
 	assert.Nil(t, writer)
 }
 
+func TestGRPCStorageFactory_OptionalServices(t *testing.T) {
+	tests := []struct {
+		name            string
+		capabilities    *shared.Capabilities
+		capabilitiesErr error
+		supported       bool
+		err             string
+	}{
+		{
+			name: "supported",
+			capabilities: &shared.Capabilities{
+				DependenciesWriter: true,
+				SamplingStore:      true,
+				MetricsReader:      true,
+			},
+			supported: true,
+		},
+		{
+			name:         "not supported",
+			capabilities: &shared.Capabilities{},
+		},
+		{
+			name:            "capabilities error",
+			capabilitiesErr: errors.New("made-up error"),
+			err:             "made-up error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFactory()
+			f.InitFromViper(viper.New(), zap.NewNop())
+
+			capabilities := new(mocks.PluginCapabilities)
+			capabilities.On("Capabilities").Return(test.capabilities, test.capabilitiesErr)
+			plugin := &mockPlugin{
+				capabilities:     capabilities,
+				dependencyReader: new(dependencyStoreMocks.Reader),
+				dependencyWriter: new(dependencyStoreMocks.Writer),
+				samplingStore:    new(samplingStoreMocks.Store),
+				lock:             new(lockMocks.Lock),
+				metricsReader:    new(metricsStoreMocks.Reader),
+			}
+			f.builder = &mockPluginBuilder{plugin: plugin}
+			require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+			depReader, depErr := f.CreateDependencyReader()
+			store, storeErr := f.CreateSamplingStore()
+			lock, lockErr := f.CreateLock()
+			metricsFactory, err := f.CreateMetricsFactory()
+			require.NoError(t, err)
+			require.NoError(t, metricsFactory.Initialize(zap.NewNop()))
+			metricsReader, metricsErr := metricsFactory.CreateMetricsReader()
+
+			switch {
+			case test.err != "":
+				assert.EqualError(t, depErr, test.err)
+				assert.EqualError(t, storeErr, test.err)
+				assert.EqualError(t, lockErr, test.err)
+				assert.EqualError(t, metricsErr, test.err)
+			case test.supported:
+				require.NoError(t, depErr)
+				depWriter, ok := depReader.(dependencystore.Writer)
+				require.True(t, ok)
+				plugin.dependencyWriter.(*dependencyStoreMocks.Writer).
+					On("WriteDependencies", mock.Anything, mock.Anything).Return(nil)
+				require.NoError(t, depWriter.WriteDependencies(time.Now(), nil))
+				plugin.dependencyWriter.(*dependencyStoreMocks.Writer).AssertExpectations(t)
+				require.NoError(t, storeErr)
+				assert.Equal(t, plugin.samplingStore, store)
+				require.NoError(t, lockErr)
+				assert.Equal(t, plugin.lock, lock)
+				require.NoError(t, metricsErr)
+				assert.Equal(t, plugin.metricsReader, metricsReader)
+			default:
+				require.NoError(t, depErr)
+				assert.Equal(t, plugin.dependencyReader, depReader)
+				assert.Equal(t, errSamplingStoreNotSupported, storeErr)
+				assert.Equal(t, errSamplingStoreNotSupported, lockErr)
+				assert.Equal(t, errMetricsReaderNotSupported, metricsErr)
+			}
+		})
+	}
+}
+
+func TestGRPCStorageFactory_OptionalServicesNotExposed(t *testing.T) {
+	f := NewFactory()
+	f.InitFromViper(viper.New(), zap.NewNop())
+	f.builder = &mockPluginBuilder{plugin: &mockPlugin{dependencyReader: new(dependencyStoreMocks.Reader)}}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+
+	depReader, err := f.CreateDependencyReader()
+	require.NoError(t, err)
+	_, ok := depReader.(dependencystore.Writer)
+	assert.False(t, ok)
+	_, err = f.CreateSamplingStore()
+	assert.Equal(t, errSamplingStoreNotSupported, err)
+	_, err = f.CreateLock()
+	assert.Equal(t, errSamplingStoreNotSupported, err)
+	metricsFactory, err := f.CreateMetricsFactory()
+	require.NoError(t, err)
+	_, err = metricsFactory.CreateMetricsReader()
+	assert.Equal(t, errMetricsReaderNotSupported, err)
+}
+
+type mockStreamingPlugin struct {
+	*mockPlugin
+	streamingWriter spanstore.Writer
+	options         shared.StreamingWriterOptions
+}
+
+func (mp *mockStreamingPlugin) StreamingSpanWriter(options shared.StreamingWriterOptions) spanstore.Writer {
+	mp.options = options
+	return mp.streamingWriter
+}
+
+func TestGRPCStorageFactory_StreamingSpanWriter(t *testing.T) {
+	tests := []struct {
+		name            string
+		flags           []string
+		capabilities    *shared.Capabilities
+		capabilitiesErr error
+		streaming       bool
+		err             string
+	}{
+		{
+			name:         "streaming supported",
+			flags:        []string{"--grpc-storage-plugin.write-batch-size=50"},
+			capabilities: &shared.Capabilities{StreamingSpanWriter: true},
+			streaming:    true,
+		},
+		{
+			name:         "streaming not supported",
+			flags:        []string{"--grpc-storage-plugin.write-batch-size=50"},
+			capabilities: &shared.Capabilities{},
+		},
+		{
+			name:         "streaming disabled by default",
+			capabilities: &shared.Capabilities{StreamingSpanWriter: true},
+		},
+		{
+			name:         "streaming disabled",
+			flags:        []string{"--grpc-storage-plugin.write-batch-size=1"},
+			capabilities: &shared.Capabilities{StreamingSpanWriter: true},
+		},
+		{
+			name:            "capabilities error",
+			flags:           []string{"--grpc-storage-plugin.write-batch-size=50"},
+			capabilitiesErr: errors.New("made-up error"),
+			err:             "made-up error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags(append([]string{
+				"--grpc-storage-plugin.write-batch-flush-interval=1s",
+				"--grpc-storage-plugin.write-queue-size=10",
+			}, test.flags...)))
+			f.InitFromViper(v, zap.NewNop())
+
+			capabilities := new(mocks.PluginCapabilities)
+			capabilities.On("Capabilities").Return(test.capabilities, test.capabilitiesErr)
+			plugin := &mockPlugin{
+				spanWriter:   new(spanStoreMocks.Writer),
+				capabilities: capabilities,
+			}
+			f.builder = &mockPluginBuilder{plugin: plugin}
+			require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+			streamingPlugin := &mockStreamingPlugin{mockPlugin: plugin, streamingWriter: new(spanStoreMocks.Writer)}
+			f.store = streamingPlugin
+
+			writer, err := f.CreateSpanWriter()
+			switch {
+			case test.err != "":
+				assert.EqualError(t, err, test.err)
+			case test.streaming:
+				require.NoError(t, err)
+				assert.Same(t, streamingPlugin.streamingWriter, writer)
+				assert.Equal(t, 50, streamingPlugin.options.BatchSize)
+				assert.Equal(t, time.Second, streamingPlugin.options.FlushInterval)
+				assert.Equal(t, 10, streamingPlugin.options.QueueSize)
+			default:
+				require.NoError(t, err)
+				assert.Same(t, plugin.spanWriter, writer)
+			}
+		})
+	}
+}
+
+type mockCloser struct {
+	err error
+}
+
+func (c *mockCloser) Close() error {
+	return c.err
+}
+
+func TestGRPCStorageFactory_Close(t *testing.T) {
+	f := NewFactory()
+	f.InitFromViper(viper.New(), zap.NewNop())
+	f.builder = &mockPluginBuilder{plugin: &mockPlugin{}}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	assert.NoError(t, f.Close())
+
+	f.builder = &mockPluginBuilder{plugin: &mockPlugin{}, closer: &mockCloser{err: errors.New("made-up error")}}
+	require.NoError(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
+	assert.EqualError(t, f.Close(), "made-up error")
+}
+
 func TestWithConfiguration(t *testing.T) {
 	f := NewFactory()
 	v, command := config.Viperize(f.AddFlags)
@@ -35,8 +35,11 @@ func ServeWithGRPCServer(services *shared.PluginServices, grpcServer func([]grpc
 		VersionedPlugins: map[int]plugin.PluginSet{
 			1: map[string]plugin.Plugin{
 				shared.StoragePluginIdentifier: &shared.StorageGRPCPlugin{
-					Impl:        services.Store,
-					ArchiveImpl: services.ArchiveStore,
+					Impl:                   services.Store,
+					ArchiveImpl:            services.ArchiveStore,
+					DependenciesWriterImpl: services.DependenciesWriter,
+					SamplingStoreImpl:      services.SamplingStore,
+					MetricsReaderImpl:      services.MetricsReader,
 				},
 			},
 		},
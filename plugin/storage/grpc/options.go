@@ -16,19 +16,34 @@ package grpc
 
 import (
 	"flag"
+	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/jaegertracing/jaeger/pkg/config/tlscfg"
 	"github.com/jaegertracing/jaeger/plugin/storage/grpc/config"
+	"github.com/jaegertracing/jaeger/plugin/storage/grpc/shared"
 )
 
 const (
 	pluginBinary            = "grpc-storage-plugin.binary"
 	pluginConfigurationFile = "grpc-storage-plugin.configuration-file"
 	pluginLogLevel          = "grpc-storage-plugin.log-level"
+	pluginWriteBatchSize    = "grpc-storage-plugin.write-batch-size"
+	pluginWriteBatchFlush   = "grpc-storage-plugin.write-batch-flush-interval"
+	pluginWriteQueueSize    = "grpc-storage-plugin.write-queue-size"
 	defaultPluginLogLevel   = "warn"
+
+	remotePrefix                   = "grpc-storage-plugin.remote"
+	remoteServer                   = remotePrefix + ".server"
+	remoteConnectionTimeout        = remotePrefix + ".connection-timeout"
+	defaultRemoteConnectionTimeout = 5 * time.Second
 )
 
+var tlsFlagsConfig = tlscfg.ClientFlagsConfig{
+	Prefix: remotePrefix,
+}
+
 // Options contains GRPC plugins configs and provides the ability
 // to bind them to command line flags
 type Options struct {
@@ -40,6 +55,13 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 	flagSet.String(pluginBinary, "", "The location of the plugin binary")
 	flagSet.String(pluginConfigurationFile, "", "A path pointing to the plugin's configuration file, made available to the plugin with the --config arg")
 	flagSet.String(pluginLogLevel, defaultPluginLogLevel, "Set the log level of the plugin's logger")
+	flagSet.Int(pluginWriteBatchSize, 0, "The maximum number of spans written in a batch, if the plugin supports streaming writes; "+
+		"the errors of the plugin are then logged and counted instead of being returned to the collector (0 or 1 to write spans one by one)")
+	flagSet.Duration(pluginWriteBatchFlush, shared.DefaultWriteBatchFlushInterval, "The maximum time a span waits for its batch to be written")
+	flagSet.Int(pluginWriteQueueSize, shared.DefaultWriteQueueSize, "The maximum number of spans waiting to be batched, writes block when the queue is full")
+	flagSet.String(remoteServer, "", "The address (host:port) of a running remote storage server, used instead of launching the plugin binary")
+	flagSet.Duration(remoteConnectionTimeout, defaultRemoteConnectionTimeout, "The timeout for connecting to the remote storage server")
+	tlsFlagsConfig.AddFlags(flagSet)
 }
 
 // InitFromViper initializes Options with properties from viper
@@ -47,4 +69,10 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	opt.Configuration.PluginBinary = v.GetString(pluginBinary)
 	opt.Configuration.PluginConfigurationFile = v.GetString(pluginConfigurationFile)
 	opt.Configuration.PluginLogLevel = v.GetString(pluginLogLevel)
+	opt.Configuration.WriteBatchSize = v.GetInt(pluginWriteBatchSize)
+	opt.Configuration.WriteBatchFlushInterval = v.GetDuration(pluginWriteBatchFlush)
+	opt.Configuration.WriteQueueSize = v.GetInt(pluginWriteQueueSize)
+	opt.Configuration.RemoteServerAddr = v.GetString(remoteServer)
+	opt.Configuration.RemoteConnectTimeout = v.GetDuration(remoteConnectionTimeout)
+	opt.Configuration.RemoteTLS = tlsFlagsConfig.InitFromViper(v)
 }
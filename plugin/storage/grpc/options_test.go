@@ -16,6 +16,7 @@ package grpc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -29,6 +30,12 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--grpc-storage-plugin.binary=noop-grpc-plugin",
 		"--grpc-storage-plugin.configuration-file=config.json",
 		"--grpc-storage-plugin.log-level=debug",
+		"--grpc-storage-plugin.write-batch-size=50",
+		"--grpc-storage-plugin.write-batch-flush-interval=1s",
+		"--grpc-storage-plugin.write-queue-size=500",
+		"--grpc-storage-plugin.remote.server=localhost:17271",
+		"--grpc-storage-plugin.remote.connection-timeout=10s",
+		"--grpc-storage-plugin.remote.tls.enabled=true",
 	})
 	assert.NoError(t, err)
 	opts.InitFromViper(v)
@@ -36,4 +43,10 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, opts.Configuration.PluginBinary, "noop-grpc-plugin")
 	assert.Equal(t, opts.Configuration.PluginConfigurationFile, "config.json")
 	assert.Equal(t, opts.Configuration.PluginLogLevel, "debug")
+	assert.Equal(t, 50, opts.Configuration.WriteBatchSize)
+	assert.Equal(t, time.Second, opts.Configuration.WriteBatchFlushInterval)
+	assert.Equal(t, 500, opts.Configuration.WriteQueueSize)
+	assert.Equal(t, "localhost:17271", opts.Configuration.RemoteServerAddr)
+	assert.Equal(t, 10*time.Second, opts.Configuration.RemoteConnectTimeout)
+	assert.True(t, opts.Configuration.RemoteTLS.Enabled)
 }
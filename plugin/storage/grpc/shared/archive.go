@@ -79,7 +79,7 @@ func (r *archiveReader) FindTraceIDs(ctx context.Context, query *spanstore.Trace
 
 // WriteSpan saves the span into Archive Storage
 func (w *archiveWriter) WriteSpan(ctx context.Context, span *model.Span) error {
-	_, err := w.client.WriteArchiveSpan(ctx, &storage_v1.WriteSpanRequest{
+	_, err := w.client.WriteArchiveSpan(upgradeContext(ctx), &storage_v1.WriteSpanRequest{
 		Span: span,
 	})
 	if err != nil {
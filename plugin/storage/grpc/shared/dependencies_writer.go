@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+)
+
+var (
+	_ dependencystore.Writer       = (*dependenciesWriter)(nil)
+	_ dependencystore.TenantWriter = (*dependenciesWriter)(nil)
+)
+
+// dependenciesWriter wraps storage_v1.DependenciesWriterPluginClient into dependencystore.Writer
+type dependenciesWriter struct {
+	client storage_v1.DependenciesWriterPluginClient
+}
+
+// WriteDependencies saves the dependency links computed for the given timestamp
+func (w *dependenciesWriter) WriteDependencies(ts time.Time, dependencies []model.DependencyLink) error {
+	return w.WriteTenantDependencies(context.Background(), ts, dependencies)
+}
+
+// WriteTenantDependencies saves the dependency links of the tenant carried by the context,
+// the tenant is sent to the plugin in the gRPC metadata
+func (w *dependenciesWriter) WriteTenantDependencies(ctx context.Context, ts time.Time, dependencies []model.DependencyLink) error {
+	_, err := w.client.WriteDependencies(upgradeContextWithTenant(ctx), &storage_v1.WriteDependenciesRequest{
+		Timestamp:    ts,
+		Dependencies: dependencies,
+	})
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1/mocks"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	dependencyStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
+)
+
+type mockDependenciesWriterPlugin struct {
+	writer *dependencyStoreMocks.Writer
+}
+
+func (p *mockDependenciesWriterPlugin) DependencyWriter() dependencystore.Writer {
+	return p.writer
+}
+
+// tenantDependencyWriter records the tenant of the written dependencies
+type tenantDependencyWriter struct {
+	dependencyStoreMocks.Writer
+	tenant string
+}
+
+func (w *tenantDependencyWriter) WriteTenantDependencies(ctx context.Context, ts time.Time, dependencies []model.DependencyLink) error {
+	w.tenant = tenancy.GetTenant(ctx)
+	return nil
+}
+
+var testDependencies = []model.DependencyLink{
+	{
+		Parent:    "frontend",
+		Child:     "backend",
+		CallCount: 10,
+	},
+}
+
+func TestDependenciesWriter_WriteDependencies(t *testing.T) {
+	ts := time.Unix(1000, 0).UTC()
+	client := new(mocks.DependenciesWriterPluginClient)
+	client.On("WriteDependencies", mock.Anything, &storage_v1.WriteDependenciesRequest{
+		Timestamp:    ts,
+		Dependencies: testDependencies,
+	}).Return(&storage_v1.WriteDependenciesResponse{}, nil)
+	writer := &dependenciesWriter{client: client}
+
+	assert.NoError(t, writer.WriteDependencies(ts, testDependencies))
+}
+
+func TestDependenciesWriter_WriteTenantDependencies(t *testing.T) {
+	client := new(mocks.DependenciesWriterPluginClient)
+	client.On("WriteDependencies", mock.MatchedBy(func(ctx context.Context) bool {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return assert.Equal(t, []string{"acme"}, md.Get(TenantMetadataKey))
+	}), mock.Anything).Return(&storage_v1.WriteDependenciesResponse{}, nil)
+	writer := &dependenciesWriter{client: client}
+
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	assert.NoError(t, writer.WriteTenantDependencies(ctx, time.Now(), testDependencies))
+	client.AssertExpectations(t)
+}
+
+func TestDependenciesWriter_WriteDependencies_Error(t *testing.T) {
+	client := new(mocks.DependenciesWriterPluginClient)
+	client.On("WriteDependencies", mock.Anything, mock.Anything).Return(nil, errors.New("made-up error"))
+	writer := &dependenciesWriter{client: client}
+
+	assert.EqualError(t, writer.WriteDependencies(time.Now(), testDependencies), "plugin error: made-up error")
+}
+
+func TestGRPCServerWriteDependencies(t *testing.T) {
+	ts := time.Unix(1000, 0).UTC()
+	impl := &mockDependenciesWriterPlugin{writer: new(dependencyStoreMocks.Writer)}
+	impl.writer.On("WriteDependencies", ts, testDependencies).Return(nil).Once()
+	impl.writer.On("WriteDependencies", ts, []model.DependencyLink(nil)).Return(errors.New("made-up error"))
+	server := &grpcServer{DependenciesWriterImpl: impl}
+
+	resp, err := server.WriteDependencies(context.Background(), &storage_v1.WriteDependenciesRequest{
+		Timestamp:    ts,
+		Dependencies: testDependencies,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &storage_v1.WriteDependenciesResponse{}, resp)
+
+	_, err = server.WriteDependencies(context.Background(), &storage_v1.WriteDependenciesRequest{Timestamp: ts})
+	assert.EqualError(t, err, "made-up error")
+}
+
+func TestGRPCServerWriteTenantDependencies(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TenantMetadataKey, "acme"))
+	request := &storage_v1.WriteDependenciesRequest{Timestamp: time.Unix(1000, 0).UTC(), Dependencies: testDependencies}
+
+	writer := &tenantDependencyWriter{}
+	server := &grpcServer{DependenciesWriterImpl: &tenantDependenciesWriterPlugin{writer: writer}}
+	_, err := server.WriteDependencies(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", writer.tenant)
+
+	// the dependencies of a tenant are never written with those of the default tenant
+	server = &grpcServer{DependenciesWriterImpl: &mockDependenciesWriterPlugin{writer: new(dependencyStoreMocks.Writer)}}
+	_, err = server.WriteDependencies(ctx, request)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+type tenantDependenciesWriterPlugin struct {
+	writer *tenantDependencyWriter
+}
+
+func (p *tenantDependenciesWriterPlugin) DependencyWriter() dependencystore.Writer {
+	return p.writer
+}
+
+func TestGRPCServerWriteDependencies_NoImpl(t *testing.T) {
+	server := &grpcServer{}
+	_, err := server.WriteDependencies(context.Background(), &storage_v1.WriteDependenciesRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
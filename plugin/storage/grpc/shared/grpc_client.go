@@ -25,8 +25,12 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -35,8 +39,12 @@ var (
 	_ ArchiveStoragePlugin = (*grpcClient)(nil)
 	_ PluginCapabilities   = (*grpcClient)(nil)
 
+	_ DependenciesWriterPlugin = (*grpcClient)(nil)
+	_ SamplingStorePlugin      = (*grpcClient)(nil)
+	_ MetricsReaderPlugin      = (*grpcClient)(nil)
+
 	// upgradeContext composites several steps of upgrading context
-	upgradeContext = composeContextUpgradeFuncs(upgradeContextWithBearerToken)
+	upgradeContext = composeContextUpgradeFuncs(upgradeContextWithBearerToken, upgradeContextWithTenant)
 )
 
 // grpcClient implements shared.StoragePlugin and reads/writes spans and dependencies
@@ -47,6 +55,9 @@ type grpcClient struct {
 	archiveWriterClient storage_v1.ArchiveSpanWriterPluginClient
 	capabilitiesClient  storage_v1.PluginCapabilitiesClient
 	depsReaderClient    storage_v1.DependenciesReaderPluginClient
+	depsWriterClient    storage_v1.DependenciesWriterPluginClient
+	samplingStoreClient storage_v1.SamplingStorePluginClient
+	metricsReaderClient storage_v1.MetricsReaderPluginClient
 }
 
 // ContextUpgradeFunc is a functional type that can be composed to upgrade context
@@ -79,6 +90,22 @@ func upgradeContextWithBearerToken(ctx context.Context) context.Context {
 	return ctx
 }
 
+// upgradeContextWithTenant turns the context into a gRPC outgoing context with the tenant
+// in the request metadata, if the original context carries a tenant.
+// Otherwise returns original context.
+func upgradeContextWithTenant(ctx context.Context) context.Context {
+	tenant := tenancy.GetTenant(ctx)
+	if tenant == "" {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	}
+	md.Set(TenantMetadataKey, tenant)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
 // DependencyReader implements shared.StoragePlugin.
 func (c *grpcClient) DependencyReader() dependencystore.Reader {
 	return c
@@ -102,6 +129,26 @@ func (c *grpcClient) ArchiveSpanWriter() spanstore.Writer {
 	return &archiveWriter{client: c.archiveWriterClient}
 }
 
+// DependencyWriter implements shared.DependenciesWriterPlugin.
+func (c *grpcClient) DependencyWriter() dependencystore.Writer {
+	return &dependenciesWriter{client: c.depsWriterClient}
+}
+
+// SamplingStore implements shared.SamplingStorePlugin.
+func (c *grpcClient) SamplingStore() samplingstore.Store {
+	return &samplingStore{client: c.samplingStoreClient}
+}
+
+// Lock implements shared.SamplingStorePlugin.
+func (c *grpcClient) Lock() distributedlock.Lock {
+	return &lock{client: c.samplingStoreClient}
+}
+
+// MetricsReader implements shared.MetricsReaderPlugin.
+func (c *grpcClient) MetricsReader() metricsstore.Reader {
+	return &metricsReader{client: c.metricsReaderClient}
+}
+
 // GetTrace takes a traceID and returns a Trace associated with that traceID
 func (c *grpcClient) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	stream, err := c.readerClient.GetTrace(upgradeContext(ctx), &storage_v1.GetTraceRequest{
@@ -161,16 +208,7 @@ func (c *grpcClient) GetOperations(
 // FindTraces retrieves traces that match the traceQuery
 func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	stream, err := c.readerClient.FindTraces(upgradeContext(ctx), &storage_v1.FindTracesRequest{
-		Query: &storage_v1.TraceQueryParameters{
-			ServiceName:   query.ServiceName,
-			OperationName: query.OperationName,
-			Tags:          query.Tags,
-			StartTimeMin:  query.StartTimeMin,
-			StartTimeMax:  query.StartTimeMax,
-			DurationMin:   query.DurationMin,
-			DurationMax:   query.DurationMax,
-			NumTraces:     int32(query.NumTraces),
-		},
+		Query: traceQueryToProto(query),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("plugin error: %w", err)
@@ -196,19 +234,75 @@ func (c *grpcClient) FindTraces(ctx context.Context, query *spanstore.TraceQuery
 	return traces, nil
 }
 
+func traceQueryToProto(query *spanstore.TraceQueryParameters) *storage_v1.TraceQueryParameters {
+	protoQuery := &storage_v1.TraceQueryParameters{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		Tags:          query.Tags,
+		StartTimeMin:  query.StartTimeMin,
+		StartTimeMax:  query.StartTimeMax,
+		DurationMin:   query.DurationMin,
+		DurationMax:   query.DurationMax,
+		NumTraces:     int32(query.NumTraces),
+	}
+	if query.Query != nil {
+		protoQuery.Query = query.Query.String()
+	}
+	return protoQuery
+}
+
+// FindSpans retrieves spans that match the query. It returns spanstore.ErrFindSpansNotSupported
+// if the plugin does not implement span search.
+func (c *grpcClient) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	stream, err := c.readerClient.FindSpans(upgradeContext(ctx), &storage_v1.FindSpansRequest{
+		Query: spanQueryToProto(query),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, spanstore.ErrFindSpansNotSupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+
+	var spans []*model.Span
+	for received, err := stream.Recv(); err != io.EOF; received, err = stream.Recv() {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, spanstore.ErrFindSpansNotSupported
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stream error: %w", err)
+		}
+
+		for i := range received.Spans {
+			spans = append(spans, &received.Spans[i])
+		}
+	}
+	return spans, nil
+}
+
+func spanQueryToProto(query *spanstore.SpanQueryParameters) *storage_v1.SpanQueryParameters {
+	sortBy := storage_v1.SpanQueryParameters_START_TIME
+	if query.SortBy == spanstore.SortByDuration {
+		sortBy = storage_v1.SpanQueryParameters_DURATION
+	}
+	return &storage_v1.SpanQueryParameters{
+		ServiceName:   query.ServiceName,
+		OperationName: query.OperationName,
+		Tags:          query.Tags,
+		StartTimeMin:  query.StartTimeMin,
+		StartTimeMax:  query.StartTimeMax,
+		DurationMin:   query.DurationMin,
+		DurationMax:   query.DurationMax,
+		NumSpans:      int32(query.NumSpans),
+		SortBy:        sortBy,
+		Ascending:     query.Ascending,
+	}
+}
+
 // FindTraceIDs retrieves traceIDs that match the traceQuery
 func (c *grpcClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	resp, err := c.readerClient.FindTraceIDs(upgradeContext(ctx), &storage_v1.FindTraceIDsRequest{
-		Query: &storage_v1.TraceQueryParameters{
-			ServiceName:   query.ServiceName,
-			OperationName: query.OperationName,
-			Tags:          query.Tags,
-			StartTimeMin:  query.StartTimeMin,
-			StartTimeMax:  query.StartTimeMax,
-			DurationMin:   query.DurationMin,
-			DurationMax:   query.DurationMax,
-			NumTraces:     int32(query.NumTraces),
-		},
+		Query: traceQueryToProto(query),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("plugin error: %w", err)
@@ -219,7 +313,7 @@ func (c *grpcClient) FindTraceIDs(ctx context.Context, query *spanstore.TraceQue
 
 // WriteSpan saves the span
 func (c *grpcClient) WriteSpan(ctx context.Context, span *model.Span) error {
-	_, err := c.writerClient.WriteSpan(ctx, &storage_v1.WriteSpanRequest{
+	_, err := c.writerClient.WriteSpan(upgradeContext(ctx), &storage_v1.WriteSpanRequest{
 		Span: span,
 	})
 
@@ -241,7 +335,7 @@ func (c *grpcClient) Close() error {
 
 // GetDependencies returns all interservice dependencies
 func (c *grpcClient) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
-	resp, err := c.depsReaderClient.GetDependencies(ctx, &storage_v1.GetDependenciesRequest{
+	resp, err := c.depsReaderClient.GetDependencies(upgradeContext(ctx), &storage_v1.GetDependenciesRequest{
 		EndTime:   endTs,
 		StartTime: endTs.Add(-lookback),
 	})
@@ -262,8 +356,12 @@ func (c *grpcClient) Capabilities() (*Capabilities, error) {
 	}
 
 	return &Capabilities{
-		ArchiveSpanReader: capabilities.ArchiveSpanReader,
-		ArchiveSpanWriter: capabilities.ArchiveSpanWriter,
+		ArchiveSpanReader:   capabilities.ArchiveSpanReader,
+		ArchiveSpanWriter:   capabilities.ArchiveSpanWriter,
+		StreamingSpanWriter: capabilities.StreamingSpanWriter,
+		DependenciesWriter:  capabilities.DependenciesWriter,
+		SamplingStore:       capabilities.SamplingStore,
+		MetricsReader:       capabilities.MetricsReader,
 	}, nil
 }
 
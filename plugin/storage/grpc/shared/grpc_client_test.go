@@ -23,14 +23,17 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
 	grpcMocks "github.com/jaegertracing/jaeger/proto-gen/storage_v1/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 var (
@@ -114,6 +117,11 @@ func TestContextUpgradeWithToken(t *testing.T) {
 	assert.Truef(t, ok, "Expected metadata in context")
 	bearerTokenFromMetadata := md.Get(spanstore.BearerTokenKey)
 	assert.Equal(t, []string{testBearerToken}, bearerTokenFromMetadata)
+
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+	token, ok := spanstore.GetBearerToken(contextFromMetadata(incoming))
+	assert.True(t, ok)
+	assert.Equal(t, testBearerToken, token)
 }
 
 func TestContextUpgradeWithoutToken(t *testing.T) {
@@ -122,6 +130,23 @@ func TestContextUpgradeWithoutToken(t *testing.T) {
 	assert.Falsef(t, ok, "Expected no metadata in context")
 }
 
+func TestContextUpgradeWithTenant(t *testing.T) {
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	md, ok := metadata.FromOutgoingContext(upgradeContextWithTenant(ctx))
+	assert.Truef(t, ok, "Expected metadata in context")
+	assert.Equal(t, []string{"acme"}, md.Get(TenantMetadataKey))
+
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+	assert.Equal(t, "acme", tenancy.GetTenant(contextFromMetadata(incoming)))
+}
+
+func TestContextUpgradeWithoutTenant(t *testing.T) {
+	ctx := upgradeContextWithTenant(context.Background())
+	_, ok := metadata.FromOutgoingContext(ctx)
+	assert.Falsef(t, ok, "Expected no metadata in context")
+	assert.Equal(t, ctx, contextFromMetadata(ctx))
+}
+
 func TestGRPCClientGetServices(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.spanReader.On("GetServices", mock.Anything, &storage_v1.GetServicesRequest{}).
@@ -247,6 +272,59 @@ func TestGRPCClientFindTraces(t *testing.T) {
 	})
 }
 
+func TestGRPCClientFindSpans(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		spanClient := new(grpcMocks.SpanReaderPlugin_FindSpansClient)
+		spanClient.On("Recv").Return(&storage_v1.SpansResponseChunk{
+			Spans: mockTracesSpans,
+		}, nil).Once()
+		spanClient.On("Recv").Return(nil, io.EOF)
+		r.spanReader.On("FindSpans", mock.Anything, &storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{
+				ServiceName: "foo",
+				SortBy:      storage_v1.SpanQueryParameters_DURATION,
+			},
+		}).Return(spanClient, nil)
+
+		s, err := r.client.FindSpans(context.Background(), &spanstore.SpanQueryParameters{
+			ServiceName: "foo",
+			SortBy:      spanstore.SortByDuration,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, s, len(mockTracesSpans))
+	})
+}
+
+func TestGRPCClientFindSpans_Errors(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		unimplementedClient := new(grpcMocks.SpanReaderPlugin_FindSpansClient)
+		unimplementedClient.On("Recv").Return(nil, status.Error(codes.Unimplemented, "not implemented"))
+		r.spanReader.On("FindSpans", mock.Anything, &storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{ServiceName: "unimplemented"},
+		}).Return(unimplementedClient, nil)
+		failingClient := new(grpcMocks.SpanReaderPlugin_FindSpansClient)
+		failingClient.On("Recv").Return(nil, errors.New("an error"))
+		r.spanReader.On("FindSpans", mock.Anything, &storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{ServiceName: "failing"},
+		}).Return(failingClient, nil)
+		r.spanReader.On("FindSpans", mock.Anything, &storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{ServiceName: "broken"},
+		}).Return(nil, errors.New("an error"))
+
+		s, err := r.client.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "unimplemented"})
+		assert.Equal(t, spanstore.ErrFindSpansNotSupported, err)
+		assert.Nil(t, s)
+
+		s, err = r.client.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "failing"})
+		assert.EqualError(t, err, "stream error: an error")
+		assert.Nil(t, s)
+
+		s, err = r.client.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "broken"})
+		assert.EqualError(t, err, "plugin error: an error")
+		assert.Nil(t, s)
+	})
+}
+
 func TestGRPCClientFindTraces_Error(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.spanReader.On("FindTraces", mock.Anything, &storage_v1.FindTracesRequest{
@@ -287,6 +365,22 @@ func TestGRPCClientFindTraceIDs(t *testing.T) {
 	})
 }
 
+func TestGRPCClientFindTraceIDsWithQuery(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanReader.On("FindTraceIDs", mock.Anything, &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{Query: "service=foo AND {tag.error=true}"},
+		}).Return(&storage_v1.FindTraceIDsResponse{
+			TraceIDs: []model.TraceID{mockTraceID},
+		}, nil)
+
+		q, err := querylang.Parse("service = foo and {tag.error = true}")
+		require.NoError(t, err)
+		s, err := r.client.FindTraceIDs(context.Background(), &spanstore.TraceQueryParameters{Query: q})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.TraceID{mockTraceID}, s)
+	})
+}
+
 func TestGRPCClientWriteSpan(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.spanWriter.On("WriteSpan", mock.Anything, &storage_v1.WriteSpanRequest{
@@ -438,6 +532,21 @@ func TestGrpcClientCapabilities(t *testing.T) {
 	})
 }
 
+func TestGrpcClientCapabilities_OptionalServices(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
+			Return(&storage_v1.CapabilitiesResponse{DependenciesWriter: true, SamplingStore: true, MetricsReader: true}, nil)
+
+		capabilities, err := r.client.Capabilities()
+		assert.NoError(t, err)
+		assert.Equal(t, &Capabilities{
+			DependenciesWriter: true,
+			SamplingStore:      true,
+			MetricsReader:      true,
+		}, capabilities)
+	})
+}
+
 func TestGrpcClientCapabilities_NotSupported(t *testing.T) {
 	withGRPCClient(func(r *grpcClientTest) {
 		r.capabilities.On("Capabilities", mock.Anything, &storage_v1.CapabilitiesRequest{}).
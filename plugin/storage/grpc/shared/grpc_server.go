@@ -20,24 +20,53 @@ import (
 	"io"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
 	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 const spanBatchSize = 1000
 
+// TenantMetadataKey is the gRPC metadata key carrying the tenant from Jaeger to the storage plugin
+const TenantMetadataKey = "jaeger.tenant"
+
 // grpcServer implements shared.StoragePlugin and reads/writes spans and dependencies
 type grpcServer struct {
-	Impl        StoragePlugin
-	ArchiveImpl ArchiveStoragePlugin
+	Impl                   StoragePlugin
+	ArchiveImpl            ArchiveStoragePlugin
+	DependenciesWriterImpl DependenciesWriterPlugin
+	SamplingStoreImpl      SamplingStorePlugin
+	MetricsReaderImpl      MetricsReaderPlugin
+}
+
+// contextFromMetadata returns a context carrying the tenant and the bearer token received in the request metadata,
+// so that the plugin implementation can isolate the data with tenancy.GetTenant, and a storage backend
+// exposed by a remote server can authenticate with spanstore.GetBearerToken.
+func contextFromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if values := md.Get(TenantMetadataKey); len(values) > 0 && values[0] != "" {
+		ctx = tenancy.WithTenant(ctx, values[0])
+	}
+	if values := md.Get(spanstore.BearerTokenKey); len(values) > 0 && values[0] != "" {
+		ctx = spanstore.ContextWithBearerToken(ctx, values[0])
+	}
+	return ctx
 }
 
 // GetDependencies returns all interservice dependencies
 func (s *grpcServer) GetDependencies(ctx context.Context, r *storage_v1.GetDependenciesRequest) (*storage_v1.GetDependenciesResponse, error) {
-	deps, err := s.Impl.DependencyReader().GetDependencies(ctx, r.EndTime, r.EndTime.Sub(r.StartTime))
+	deps, err := s.Impl.DependencyReader().GetDependencies(contextFromMetadata(ctx), r.EndTime, r.EndTime.Sub(r.StartTime))
 	if err != nil {
 		return nil, err
 	}
@@ -48,13 +77,43 @@ func (s *grpcServer) GetDependencies(ctx context.Context, r *storage_v1.GetDepen
 
 // WriteSpan saves the span
 func (s *grpcServer) WriteSpan(ctx context.Context, r *storage_v1.WriteSpanRequest) (*storage_v1.WriteSpanResponse, error) {
-	err := s.Impl.SpanWriter().WriteSpan(ctx, r.Span)
+	err := s.Impl.SpanWriter().WriteSpan(contextFromMetadata(ctx), r.Span)
 	if err != nil {
 		return nil, err
 	}
 	return &storage_v1.WriteSpanResponse{}, nil
 }
 
+// WriteSpans saves the batch of spans received on the stream
+func (s *grpcServer) WriteSpans(stream storage_v1.SpanWriterPlugin_WriteSpansServer) error {
+	ctx := contextFromMetadata(stream.Context())
+	writer := s.Impl.SpanWriter()
+	var failed, total int
+	var firstErr error
+	for {
+		r, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, span := range r.Spans {
+			total++
+			if err := writer.WriteSpan(ctx, span); err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	if firstErr != nil {
+		return status.Errorf(codes.Internal, "failed to write %d of %d spans: %v", failed, total, firstErr)
+	}
+	return stream.SendAndClose(&storage_v1.WriteSpansResponse{})
+}
+
 func (s *grpcServer) Close(ctx context.Context, r *storage_v1.CloseWriterRequest) (*storage_v1.CloseWriterResponse, error) {
 	if closer, ok := s.Impl.SpanWriter().(io.Closer); ok {
 		if err := closer.Close(); err != nil {
@@ -69,7 +128,7 @@ func (s *grpcServer) Close(ctx context.Context, r *storage_v1.CloseWriterRequest
 
 // GetTrace takes a traceID and streams a Trace associated with that traceID
 func (s *grpcServer) GetTrace(r *storage_v1.GetTraceRequest, stream storage_v1.SpanReaderPlugin_GetTraceServer) error {
-	trace, err := s.Impl.SpanReader().GetTrace(stream.Context(), r.TraceID)
+	trace, err := s.Impl.SpanReader().GetTrace(contextFromMetadata(stream.Context()), r.TraceID)
 	if err == spanstore.ErrTraceNotFound {
 		return status.Errorf(codes.NotFound, spanstore.ErrTraceNotFound.Error())
 	}
@@ -87,7 +146,7 @@ func (s *grpcServer) GetTrace(r *storage_v1.GetTraceRequest, stream storage_v1.S
 
 // GetServices returns a list of all known services
 func (s *grpcServer) GetServices(ctx context.Context, r *storage_v1.GetServicesRequest) (*storage_v1.GetServicesResponse, error) {
-	services, err := s.Impl.SpanReader().GetServices(ctx)
+	services, err := s.Impl.SpanReader().GetServices(contextFromMetadata(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +160,7 @@ func (s *grpcServer) GetOperations(
 	ctx context.Context,
 	r *storage_v1.GetOperationsRequest,
 ) (*storage_v1.GetOperationsResponse, error) {
-	operations, err := s.Impl.SpanReader().GetOperations(ctx, spanstore.OperationQueryParameters{
+	operations, err := s.Impl.SpanReader().GetOperations(contextFromMetadata(ctx), spanstore.OperationQueryParameters{
 		ServiceName: r.Service,
 		SpanKind:    r.SpanKind,
 	})
@@ -122,16 +181,11 @@ func (s *grpcServer) GetOperations(
 
 // FindTraces streams traces that match the traceQuery
 func (s *grpcServer) FindTraces(r *storage_v1.FindTracesRequest, stream storage_v1.SpanReaderPlugin_FindTracesServer) error {
-	traces, err := s.Impl.SpanReader().FindTraces(stream.Context(), &spanstore.TraceQueryParameters{
-		ServiceName:   r.Query.ServiceName,
-		OperationName: r.Query.OperationName,
-		Tags:          r.Query.Tags,
-		StartTimeMin:  r.Query.StartTimeMin,
-		StartTimeMax:  r.Query.StartTimeMax,
-		DurationMin:   r.Query.DurationMin,
-		DurationMax:   r.Query.DurationMax,
-		NumTraces:     int(r.Query.NumTraces),
-	})
+	query, err := traceQueryFromProto(r.Query)
+	if err != nil {
+		return err
+	}
+	traces, err := s.Impl.SpanReader().FindTraces(contextFromMetadata(stream.Context()), query)
 	if err != nil {
 		return err
 	}
@@ -148,16 +202,11 @@ func (s *grpcServer) FindTraces(r *storage_v1.FindTracesRequest, stream storage_
 
 // FindTraceIDs retrieves traceIDs that match the traceQuery
 func (s *grpcServer) FindTraceIDs(ctx context.Context, r *storage_v1.FindTraceIDsRequest) (*storage_v1.FindTraceIDsResponse, error) {
-	traceIDs, err := s.Impl.SpanReader().FindTraceIDs(ctx, &spanstore.TraceQueryParameters{
-		ServiceName:   r.Query.ServiceName,
-		OperationName: r.Query.OperationName,
-		Tags:          r.Query.Tags,
-		StartTimeMin:  r.Query.StartTimeMin,
-		StartTimeMax:  r.Query.StartTimeMax,
-		DurationMin:   r.Query.DurationMin,
-		DurationMax:   r.Query.DurationMax,
-		NumTraces:     int(r.Query.NumTraces),
-	})
+	query, err := traceQueryFromProto(r.Query)
+	if err != nil {
+		return nil, err
+	}
+	traceIDs, err := s.Impl.SpanReader().FindTraceIDs(contextFromMetadata(ctx), query)
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +215,63 @@ func (s *grpcServer) FindTraceIDs(ctx context.Context, r *storage_v1.FindTraceID
 	}, nil
 }
 
+// FindSpans streams spans that match the query, if the span reader of the plugin supports span search
+func (s *grpcServer) FindSpans(r *storage_v1.FindSpansRequest, stream storage_v1.SpanReaderPlugin_FindSpansServer) error {
+	finder, ok := s.Impl.SpanReader().(spanstore.SpanFinder)
+	if !ok {
+		return status.Error(codes.Unimplemented, spanstore.ErrFindSpansNotSupported.Error())
+	}
+	spans, err := finder.FindSpans(contextFromMetadata(stream.Context()), spanQueryFromProto(r.Query))
+	if err == spanstore.ErrFindSpansNotSupported {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.sendSpans(spans, stream.Send)
+}
+
+func spanQueryFromProto(r *storage_v1.SpanQueryParameters) *spanstore.SpanQueryParameters {
+	sortBy := spanstore.SortByStartTime
+	if r.SortBy == storage_v1.SpanQueryParameters_DURATION {
+		sortBy = spanstore.SortByDuration
+	}
+	return &spanstore.SpanQueryParameters{
+		ServiceName:   r.ServiceName,
+		OperationName: r.OperationName,
+		Tags:          r.Tags,
+		StartTimeMin:  r.StartTimeMin,
+		StartTimeMax:  r.StartTimeMax,
+		DurationMin:   r.DurationMin,
+		DurationMax:   r.DurationMax,
+		NumSpans:      int(r.NumSpans),
+		SortBy:        sortBy,
+		Ascending:     r.Ascending,
+	}
+}
+
+func traceQueryFromProto(r *storage_v1.TraceQueryParameters) (*spanstore.TraceQueryParameters, error) {
+	query := &spanstore.TraceQueryParameters{
+		ServiceName:   r.ServiceName,
+		OperationName: r.OperationName,
+		Tags:          r.Tags,
+		StartTimeMin:  r.StartTimeMin,
+		StartTimeMax:  r.StartTimeMax,
+		DurationMin:   r.DurationMin,
+		DurationMax:   r.DurationMax,
+		NumTraces:     int(r.NumTraces),
+	}
+	if r.Query != "" {
+		q, err := querylang.Parse(r.Query)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid trace query: %v", err)
+		}
+		query.Query = q
+	}
+	return query, nil
+}
+
 func (s *grpcServer) sendSpans(spans []*model.Span, sendFn func(*storage_v1.SpansResponseChunk) error) error {
 	chunk := make([]model.Span, 0, len(spans))
 	for i := 0; i < len(spans); i += spanBatchSize {
@@ -183,8 +289,12 @@ func (s *grpcServer) sendSpans(spans []*model.Span, sendFn func(*storage_v1.Span
 
 func (s *grpcServer) Capabilities(ctx context.Context, request *storage_v1.CapabilitiesRequest) (*storage_v1.CapabilitiesResponse, error) {
 	return &storage_v1.CapabilitiesResponse{
-		ArchiveSpanReader: s.ArchiveImpl != nil,
-		ArchiveSpanWriter: s.ArchiveImpl != nil,
+		ArchiveSpanReader:   s.ArchiveImpl != nil,
+		ArchiveSpanWriter:   s.ArchiveImpl != nil,
+		StreamingSpanWriter: true,
+		DependenciesWriter:  s.DependenciesWriterImpl != nil,
+		SamplingStore:       s.SamplingStoreImpl != nil,
+		MetricsReader:       s.MetricsReaderImpl != nil,
 	}, nil
 }
 
@@ -192,7 +302,7 @@ func (s *grpcServer) GetArchiveTrace(r *storage_v1.GetTraceRequest, stream stora
 	if s.ArchiveImpl == nil {
 		return status.Error(codes.Unimplemented, "not implemented")
 	}
-	trace, err := s.ArchiveImpl.ArchiveSpanReader().GetTrace(stream.Context(), r.TraceID)
+	trace, err := s.ArchiveImpl.ArchiveSpanReader().GetTrace(contextFromMetadata(stream.Context()), r.TraceID)
 	if err == spanstore.ErrTraceNotFound {
 		return status.Errorf(codes.NotFound, spanstore.ErrTraceNotFound.Error())
 	}
@@ -212,9 +322,194 @@ func (s *grpcServer) WriteArchiveSpan(ctx context.Context, r *storage_v1.WriteSp
 	if s.ArchiveImpl == nil {
 		return nil, status.Error(codes.Unimplemented, "not implemented")
 	}
-	err := s.ArchiveImpl.ArchiveSpanWriter().WriteSpan(ctx, r.Span)
+	err := s.ArchiveImpl.ArchiveSpanWriter().WriteSpan(contextFromMetadata(ctx), r.Span)
 	if err != nil {
 		return nil, err
 	}
 	return &storage_v1.WriteSpanResponse{}, nil
 }
+
+// WriteDependencies saves the dependency links computed by Jaeger
+func (s *grpcServer) WriteDependencies(ctx context.Context, r *storage_v1.WriteDependenciesRequest) (*storage_v1.WriteDependenciesResponse, error) {
+	if s.DependenciesWriterImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	ctx = contextFromMetadata(ctx)
+	writer := s.DependenciesWriterImpl.DependencyWriter()
+	var err error
+	if tenantWriter, ok := writer.(dependencystore.TenantWriter); ok {
+		err = tenantWriter.WriteTenantDependencies(ctx, r.Timestamp, r.Dependencies)
+	} else if tenancy.GetTenant(ctx) != "" {
+		return nil, status.Error(codes.Unimplemented, "the dependencies writer does not isolate the tenants")
+	} else {
+		err = writer.WriteDependencies(r.Timestamp, r.Dependencies)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.WriteDependenciesResponse{}, nil
+}
+
+// InsertThroughput saves the aggregated throughput of the operations
+func (s *grpcServer) InsertThroughput(ctx context.Context, r *storage_v1.InsertThroughputRequest) (*storage_v1.InsertThroughputResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	err := s.SamplingStoreImpl.SamplingStore().InsertThroughput(throughputFromProto(r.Throughput))
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.InsertThroughputResponse{}, nil
+}
+
+// InsertProbabilitiesAndQPS saves the sampling probabilities and the qps calculated by a host
+func (s *grpcServer) InsertProbabilitiesAndQPS(
+	ctx context.Context,
+	r *storage_v1.InsertProbabilitiesAndQPSRequest,
+) (*storage_v1.InsertProbabilitiesAndQPSResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	err := s.SamplingStoreImpl.SamplingStore().InsertProbabilitiesAndQPS(
+		r.Hostname,
+		operationValuesFromProto(r.Probabilities),
+		operationValuesFromProto(r.QPS),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.InsertProbabilitiesAndQPSResponse{}, nil
+}
+
+// GetThroughput returns the aggregated throughput of the operations within a time range
+func (s *grpcServer) GetThroughput(ctx context.Context, r *storage_v1.GetThroughputRequest) (*storage_v1.GetThroughputResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	throughput, err := s.SamplingStoreImpl.SamplingStore().GetThroughput(r.StartTime, r.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.GetThroughputResponse{
+		Throughput: throughputToProto(throughput),
+	}, nil
+}
+
+// GetProbabilitiesAndQPS returns the sampling probabilities and the qps calculated by each host within a time range
+func (s *grpcServer) GetProbabilitiesAndQPS(
+	ctx context.Context,
+	r *storage_v1.GetProbabilitiesAndQPSRequest,
+) (*storage_v1.GetProbabilitiesAndQPSResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	hostData, err := s.SamplingStoreImpl.SamplingStore().GetProbabilitiesAndQPS(r.StartTime, r.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.GetProbabilitiesAndQPSResponse{
+		HostData: hostSamplingDataToProto(hostData),
+	}, nil
+}
+
+// GetLatestProbabilities returns the latest sampling probabilities
+func (s *grpcServer) GetLatestProbabilities(
+	ctx context.Context,
+	r *storage_v1.GetLatestProbabilitiesRequest,
+) (*storage_v1.GetLatestProbabilitiesResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	probabilities, err := s.SamplingStoreImpl.SamplingStore().GetLatestProbabilities()
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.GetLatestProbabilitiesResponse{
+		Probabilities: operationValuesToProto(probabilities),
+	}, nil
+}
+
+// AcquireLock acquires a lease around a given resource
+func (s *grpcServer) AcquireLock(ctx context.Context, r *storage_v1.AcquireLockRequest) (*storage_v1.AcquireLockResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	acquired, err := s.SamplingStoreImpl.Lock().Acquire(r.Resource, r.Ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.AcquireLockResponse{Acquired: acquired}, nil
+}
+
+// ForfeitLock forfeits a lease around a given resource
+func (s *grpcServer) ForfeitLock(ctx context.Context, r *storage_v1.ForfeitLockRequest) (*storage_v1.ForfeitLockResponse, error) {
+	if s.SamplingStoreImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	forfeited, err := s.SamplingStoreImpl.Lock().Forfeit(r.Resource)
+	if err != nil {
+		return nil, err
+	}
+	return &storage_v1.ForfeitLockResponse{Forfeited: forfeited}, nil
+}
+
+// GetLatencies returns the latency metrics of the services
+func (s *grpcServer) GetLatencies(ctx context.Context, r *metrics.GetLatenciesRequest) (*metrics.GetMetricsResponse, error) {
+	if s.MetricsReaderImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	m, err := s.MetricsReaderImpl.MetricsReader().GetLatencies(contextFromMetadata(ctx), &metricsstore.LatenciesQueryParameters{
+		BaseQueryParameters: baseQueryParametersFromProto(r.BaseRequest),
+		Quantile:            r.Quantile,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metrics.GetMetricsResponse{Metrics: *m}, nil
+}
+
+// GetCallRates returns the call rate metrics of the services
+func (s *grpcServer) GetCallRates(ctx context.Context, r *metrics.GetCallRatesRequest) (*metrics.GetMetricsResponse, error) {
+	if s.MetricsReaderImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	m, err := s.MetricsReaderImpl.MetricsReader().GetCallRates(contextFromMetadata(ctx), &metricsstore.CallRateQueryParameters{
+		BaseQueryParameters: baseQueryParametersFromProto(r.BaseRequest),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metrics.GetMetricsResponse{Metrics: *m}, nil
+}
+
+// GetErrorRates returns the error rate metrics of the services
+func (s *grpcServer) GetErrorRates(ctx context.Context, r *metrics.GetErrorRatesRequest) (*metrics.GetMetricsResponse, error) {
+	if s.MetricsReaderImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	m, err := s.MetricsReaderImpl.MetricsReader().GetErrorRates(contextFromMetadata(ctx), &metricsstore.ErrorRateQueryParameters{
+		BaseQueryParameters: baseQueryParametersFromProto(r.BaseRequest),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metrics.GetMetricsResponse{Metrics: *m}, nil
+}
+
+// GetMinStepDuration returns the min time resolution supported by the metrics store
+func (s *grpcServer) GetMinStepDuration(
+	ctx context.Context,
+	r *metrics.GetMinStepDurationRequest,
+) (*metrics.GetMinStepDurationResponse, error) {
+	if s.MetricsReaderImpl == nil {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	minStep, err := s.MetricsReaderImpl.MetricsReader().GetMinStepDuration(
+		contextFromMetadata(ctx),
+		&metricsstore.MinStepDurationQueryParameters{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &metrics.GetMinStepDurationResponse{MinStep: minStep}, nil
+}
@@ -17,11 +17,13 @@ package shared
 import (
 	"context"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -32,6 +34,7 @@ import (
 	dependencyStoreMocks "github.com/jaegertracing/jaeger/storage/dependencystore/mocks"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 	spanStoreMocks "github.com/jaegertracing/jaeger/storage/spanstore/mocks"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 type mockStoragePlugin struct {
@@ -195,6 +198,61 @@ func TestGRPCServerFindTraces(t *testing.T) {
 	})
 }
 
+type spanFinderPlugin struct {
+	*mockStoragePlugin
+	spanFinder *spanStoreMocks.SpanFinder
+}
+
+func (plugin spanFinderPlugin) SpanReader() spanstore.Reader {
+	return struct {
+		*spanStoreMocks.Reader
+		*spanStoreMocks.SpanFinder
+	}{plugin.spanReader, plugin.spanFinder}
+}
+
+func TestGRPCServerFindSpans(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		spanFinder := new(spanStoreMocks.SpanFinder)
+		r.server.Impl = spanFinderPlugin{mockStoragePlugin: r.impl, spanFinder: spanFinder}
+
+		spanStream := new(grpcMocks.SpanReaderPlugin_FindSpansServer)
+		spanStream.On("Context").Return(context.Background())
+		spanStream.On("Send", &storage_v1.SpansResponseChunk{Spans: mockTracesSpans}).
+			Return(nil).Once()
+
+		spans := make([]*model.Span, len(mockTracesSpans))
+		for i := range mockTracesSpans {
+			spans[i] = &mockTracesSpans[i]
+		}
+		spanFinder.On("FindSpans", mock.Anything, &spanstore.SpanQueryParameters{
+			ServiceName: "foo",
+			NumSpans:    10,
+			SortBy:      spanstore.SortByDuration,
+			Ascending:   true,
+		}).Return(spans, nil)
+
+		err := r.server.FindSpans(&storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{
+				ServiceName: "foo",
+				NumSpans:    10,
+				SortBy:      storage_v1.SpanQueryParameters_DURATION,
+				Ascending:   true,
+			},
+		}, spanStream)
+		assert.NoError(t, err)
+		spanStream.AssertExpectations(t)
+	})
+}
+
+func TestGRPCServerFindSpansNotSupported(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		err := r.server.FindSpans(&storage_v1.FindSpansRequest{
+			Query: &storage_v1.SpanQueryParameters{},
+		}, nil)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
 func TestGRPCServerFindTraceIDs(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{}).
@@ -208,6 +266,31 @@ func TestGRPCServerFindTraceIDs(t *testing.T) {
 	})
 }
 
+func TestGRPCServerFindTraceIDsWithQuery(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		q, err := querylang.Parse("service=foo AND {tag.error=true}")
+		require.NoError(t, err)
+		r.impl.spanReader.On("FindTraceIDs", mock.Anything, &spanstore.TraceQueryParameters{Query: q}).
+			Return([]model.TraceID{mockTraceID}, nil)
+
+		s, err := r.server.FindTraceIDs(context.Background(), &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{Query: "service=foo AND {tag.error=true}"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &storage_v1.FindTraceIDsResponse{TraceIDs: []model.TraceID{mockTraceID}}, s)
+
+		_, err = r.server.FindTraceIDs(context.Background(), &storage_v1.FindTraceIDsRequest{
+			Query: &storage_v1.TraceQueryParameters{Query: "service="},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		err = r.server.FindTraces(&storage_v1.FindTracesRequest{
+			Query: &storage_v1.TraceQueryParameters{Query: "service="},
+		}, nil)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestGRPCServerWriteSpan(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		r.impl.spanWriter.On("WriteSpan", context.Background(), &mockTraceSpans[0]).
@@ -221,6 +304,55 @@ func TestGRPCServerWriteSpan(t *testing.T) {
 	})
 }
 
+func TestGRPCServerWriteSpans(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.spanWriter.On("WriteSpan", mock.Anything, &mockTraceSpans[0]).Return(nil)
+		r.impl.spanWriter.On("WriteSpan", mock.Anything, &mockTraceSpans[1]).Return(nil)
+
+		stream := new(grpcMocks.SpanWriterPlugin_WriteSpansServer)
+		stream.On("Context").Return(context.Background())
+		stream.On("Recv").Return(&storage_v1.WriteSpansRequest{Spans: []*model.Span{&mockTraceSpans[0]}}, nil).Once()
+		stream.On("Recv").Return(&storage_v1.WriteSpansRequest{Spans: []*model.Span{&mockTraceSpans[1]}}, nil).Once()
+		stream.On("Recv").Return(nil, io.EOF)
+		stream.On("SendAndClose", &storage_v1.WriteSpansResponse{}).Return(nil)
+
+		err := r.server.WriteSpans(stream)
+		assert.NoError(t, err)
+		r.impl.spanWriter.AssertNumberOfCalls(t, "WriteSpan", 2)
+		stream.AssertExpectations(t)
+	})
+}
+
+func TestGRPCServerWriteSpans_Error(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.impl.spanWriter.On("WriteSpan", mock.Anything, &mockTraceSpans[0]).Return(nil)
+		r.impl.spanWriter.On("WriteSpan", mock.Anything, &mockTraceSpans[1]).Return(fmt.Errorf("storage error"))
+
+		stream := new(grpcMocks.SpanWriterPlugin_WriteSpansServer)
+		stream.On("Context").Return(context.Background())
+		stream.On("Recv").Return(&storage_v1.WriteSpansRequest{
+			Spans: []*model.Span{&mockTraceSpans[0], &mockTraceSpans[1]},
+		}, nil).Once()
+		stream.On("Recv").Return(nil, io.EOF)
+
+		err := r.server.WriteSpans(stream)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, err.Error(), "failed to write 1 of 2 spans: storage error")
+		stream.AssertNotCalled(t, "SendAndClose", mock.Anything)
+	})
+}
+
+func TestGRPCServerWriteSpans_RecvError(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		stream := new(grpcMocks.SpanWriterPlugin_WriteSpansServer)
+		stream.On("Context").Return(context.Background())
+		stream.On("Recv").Return(nil, fmt.Errorf("stream error"))
+
+		err := r.server.WriteSpans(stream)
+		assert.EqualError(t, err, "stream error")
+	})
+}
+
 func TestGRPCServerGetDependencies(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		lookback := time.Duration(1 * time.Second)
@@ -370,7 +502,26 @@ func TestGRPCServerCapabilities(t *testing.T) {
 	withGRPCServer(func(r *grpcServerTest) {
 		capabilities, err := r.server.Capabilities(context.Background(), &storage_v1.CapabilitiesRequest{})
 		assert.NoError(t, err)
-		assert.Equal(t, &storage_v1.CapabilitiesResponse{ArchiveSpanReader: true, ArchiveSpanWriter: true}, capabilities)
+		assert.Equal(t, &storage_v1.CapabilitiesResponse{ArchiveSpanReader: true, ArchiveSpanWriter: true, StreamingSpanWriter: true}, capabilities)
+	})
+}
+
+func TestGRPCServerCapabilities_OptionalServices(t *testing.T) {
+	withGRPCServer(func(r *grpcServerTest) {
+		r.server.DependenciesWriterImpl = &mockDependenciesWriterPlugin{}
+		r.server.SamplingStoreImpl = &mockSamplingStorePlugin{}
+		r.server.MetricsReaderImpl = &mockMetricsReaderPlugin{}
+
+		capabilities, err := r.server.Capabilities(context.Background(), &storage_v1.CapabilitiesRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, &storage_v1.CapabilitiesResponse{
+			ArchiveSpanReader:   true,
+			ArchiveSpanWriter:   true,
+			StreamingSpanWriter: true,
+			DependenciesWriter:  true,
+			SamplingStore:       true,
+			MetricsReader:       true,
+		}, capabilities)
 	})
 }
 
@@ -380,6 +531,6 @@ func TestGRPCServerCapabilities_NoArchive(t *testing.T) {
 
 		capabilities, err := r.server.Capabilities(context.Background(), &storage_v1.CapabilitiesRequest{})
 		assert.NoError(t, err)
-		assert.Equal(t, &storage_v1.CapabilitiesResponse{ArchiveSpanReader: false, ArchiveSpanWriter: false}, capabilities)
+		assert.Equal(t, &storage_v1.CapabilitiesResponse{ArchiveSpanReader: false, ArchiveSpanWriter: false, StreamingSpanWriter: true}, capabilities)
 	})
 }
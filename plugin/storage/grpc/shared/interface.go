@@ -17,7 +17,10 @@ package shared
 import (
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -48,6 +51,22 @@ type ArchiveStoragePlugin interface {
 	ArchiveSpanWriter() spanstore.Writer
 }
 
+// DependenciesWriterPlugin is the interface we're exposing as a plugin to store the dependencies computed by Jaeger.
+type DependenciesWriterPlugin interface {
+	DependencyWriter() dependencystore.Writer
+}
+
+// SamplingStorePlugin is the interface we're exposing as a plugin to support adaptive sampling.
+type SamplingStorePlugin interface {
+	SamplingStore() samplingstore.Store
+	Lock() distributedlock.Lock
+}
+
+// MetricsReaderPlugin is the interface we're exposing as a plugin to serve the metrics queries.
+type MetricsReaderPlugin interface {
+	MetricsReader() metricsstore.Reader
+}
+
 // PluginCapabilities allow expose plugin its capabilities.
 type PluginCapabilities interface {
 	Capabilities() (*Capabilities, error)
@@ -57,10 +76,18 @@ type PluginCapabilities interface {
 type Capabilities struct {
 	ArchiveSpanReader bool
 	ArchiveSpanWriter bool
+	// StreamingSpanWriter is true if the plugin accepts batches of spans on the WriteSpans stream
+	StreamingSpanWriter bool
+	DependenciesWriter  bool
+	SamplingStore       bool
+	MetricsReader       bool
 }
 
 // PluginServices defines services plugin can expose
 type PluginServices struct {
-	Store        StoragePlugin
-	ArchiveStore ArchiveStoragePlugin
+	Store              StoragePlugin
+	ArchiveStore       ArchiveStoragePlugin
+	DependenciesWriter DependenciesWriterPlugin
+	SamplingStore      SamplingStorePlugin
+	MetricsReader      MetricsReaderPlugin
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+var _ metricsstore.Reader = (*metricsReader)(nil)
+
+// metricsReader wraps storage_v1.MetricsReaderPluginClient into metricsstore.Reader
+type metricsReader struct {
+	client storage_v1.MetricsReaderPluginClient
+}
+
+// GetLatencies gets the latency metrics for a specific quantile and list of services.
+func (r *metricsReader) GetLatencies(ctx context.Context, params *metricsstore.LatenciesQueryParameters) (*metrics.MetricFamily, error) {
+	baseRequest, err := baseQueryParametersToProto(params.BaseQueryParameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.GetLatencies(upgradeContext(ctx), &metrics.GetLatenciesRequest{
+		BaseRequest: baseRequest,
+		Quantile:    params.Quantile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return &resp.Metrics, nil
+}
+
+// GetCallRates gets the call rate metrics for a given list of services.
+func (r *metricsReader) GetCallRates(ctx context.Context, params *metricsstore.CallRateQueryParameters) (*metrics.MetricFamily, error) {
+	baseRequest, err := baseQueryParametersToProto(params.BaseQueryParameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.GetCallRates(upgradeContext(ctx), &metrics.GetCallRatesRequest{
+		BaseRequest: baseRequest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return &resp.Metrics, nil
+}
+
+// GetErrorRates gets the error rate metrics for a given list of services.
+func (r *metricsReader) GetErrorRates(ctx context.Context, params *metricsstore.ErrorRateQueryParameters) (*metrics.MetricFamily, error) {
+	baseRequest, err := baseQueryParametersToProto(params.BaseQueryParameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.GetErrorRates(upgradeContext(ctx), &metrics.GetErrorRatesRequest{
+		BaseRequest: baseRequest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return &resp.Metrics, nil
+}
+
+// GetMinStepDuration gets the min time resolution supported by the metrics store of the plugin.
+func (r *metricsReader) GetMinStepDuration(ctx context.Context, _ *metricsstore.MinStepDurationQueryParameters) (time.Duration, error) {
+	resp, err := r.client.GetMinStepDuration(upgradeContext(ctx), &metrics.GetMinStepDurationRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("plugin error: %w", err)
+	}
+	return resp.MinStep, nil
+}
+
+func baseQueryParametersToProto(params metricsstore.BaseQueryParameters) (*metrics.MetricsQueryBaseRequest, error) {
+	spanKinds := make([]metrics.SpanKind, len(params.SpanKinds))
+	for i, kind := range params.SpanKinds {
+		value, ok := metrics.SpanKind_value[kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported span kind %q", kind)
+		}
+		spanKinds[i] = metrics.SpanKind(value)
+	}
+	return &metrics.MetricsQueryBaseRequest{
+		ServiceNames:     params.ServiceNames,
+		GroupByOperation: params.GroupByOperation,
+		EndTime:          params.EndTime,
+		Lookback:         params.Lookback,
+		Step:             params.Step,
+		RatePer:          params.RatePer,
+		SpanKinds:        spanKinds,
+	}, nil
+}
+
+func baseQueryParametersFromProto(r *metrics.MetricsQueryBaseRequest) metricsstore.BaseQueryParameters {
+	if r == nil {
+		return metricsstore.BaseQueryParameters{}
+	}
+	spanKinds := make([]string, len(r.SpanKinds))
+	for i, kind := range r.SpanKinds {
+		spanKinds[i] = kind.String()
+	}
+	return metricsstore.BaseQueryParameters{
+		ServiceNames:     r.ServiceNames,
+		GroupByOperation: r.GroupByOperation,
+		EndTime:          r.EndTime,
+		Lookback:         r.Lookback,
+		Step:             r.Step,
+		RatePer:          r.RatePer,
+		SpanKinds:        spanKinds,
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/api_v2/metrics"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	metricsStoreMocks "github.com/jaegertracing/jaeger/storage/metricsstore/mocks"
+)
+
+type mockMetricsReaderPlugin struct {
+	reader *metricsStoreMocks.Reader
+}
+
+func (p *mockMetricsReaderPlugin) MetricsReader() metricsstore.Reader {
+	return p.reader
+}
+
+// metricsReaderLoopback calls the server directly with the metadata the client would send
+type metricsReaderLoopback struct {
+	server *grpcServer
+}
+
+func loopbackContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func (l *metricsReaderLoopback) GetLatencies(
+	ctx context.Context, in *metrics.GetLatenciesRequest, _ ...grpc.CallOption,
+) (*metrics.GetMetricsResponse, error) {
+	return l.server.GetLatencies(loopbackContext(ctx), in)
+}
+
+func (l *metricsReaderLoopback) GetCallRates(
+	ctx context.Context, in *metrics.GetCallRatesRequest, _ ...grpc.CallOption,
+) (*metrics.GetMetricsResponse, error) {
+	return l.server.GetCallRates(loopbackContext(ctx), in)
+}
+
+func (l *metricsReaderLoopback) GetErrorRates(
+	ctx context.Context, in *metrics.GetErrorRatesRequest, _ ...grpc.CallOption,
+) (*metrics.GetMetricsResponse, error) {
+	return l.server.GetErrorRates(loopbackContext(ctx), in)
+}
+
+func (l *metricsReaderLoopback) GetMinStepDuration(
+	ctx context.Context, in *metrics.GetMinStepDurationRequest, _ ...grpc.CallOption,
+) (*metrics.GetMinStepDurationResponse, error) {
+	return l.server.GetMinStepDuration(loopbackContext(ctx), in)
+}
+
+func withMetricsReaderLoopback(fn func(impl *mockMetricsReaderPlugin, reader metricsstore.Reader)) {
+	impl := &mockMetricsReaderPlugin{reader: new(metricsStoreMocks.Reader)}
+	loopback := &metricsReaderLoopback{server: &grpcServer{MetricsReaderImpl: impl}}
+	client := &grpcClient{metricsReaderClient: loopback}
+	fn(impl, client.MetricsReader())
+}
+
+func testBaseQueryParameters() metricsstore.BaseQueryParameters {
+	endTime := time.Unix(1000, 0).UTC()
+	lookback, step, ratePer := time.Hour, 5*time.Second, 10*time.Minute
+	return metricsstore.BaseQueryParameters{
+		ServiceNames:     []string{"svc"},
+		GroupByOperation: true,
+		EndTime:          &endTime,
+		Lookback:         &lookback,
+		Step:             &step,
+		RatePer:          &ratePer,
+		SpanKinds:        []string{"SPAN_KIND_SERVER", "SPAN_KIND_CLIENT"},
+	}
+}
+
+func withTenant(tenant string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return tenancy.GetTenant(ctx) == tenant
+	})
+}
+
+func TestMetricsReader(t *testing.T) {
+	withMetricsReaderLoopback(func(impl *mockMetricsReaderPlugin, reader metricsstore.Reader) {
+		ctx := tenancy.WithTenant(context.Background(), "acme")
+		family := &metrics.MetricFamily{Name: "service_latencies", Type: metrics.MetricType_GAUGE}
+		latenciesParams := &metricsstore.LatenciesQueryParameters{
+			BaseQueryParameters: testBaseQueryParameters(),
+			Quantile:            0.95,
+		}
+		callRateParams := &metricsstore.CallRateQueryParameters{BaseQueryParameters: testBaseQueryParameters()}
+		errorRateParams := &metricsstore.ErrorRateQueryParameters{BaseQueryParameters: testBaseQueryParameters()}
+		impl.reader.On("GetLatencies", withTenant("acme"), latenciesParams).Return(family, nil)
+		impl.reader.On("GetCallRates", withTenant("acme"), callRateParams).Return(family, nil)
+		impl.reader.On("GetErrorRates", withTenant("acme"), errorRateParams).Return(family, nil)
+		impl.reader.On("GetMinStepDuration", withTenant("acme"), &metricsstore.MinStepDurationQueryParameters{}).
+			Return(time.Second, nil)
+
+		actual, err := reader.GetLatencies(ctx, latenciesParams)
+		require.NoError(t, err)
+		assert.Equal(t, family, actual)
+		actual, err = reader.GetCallRates(ctx, callRateParams)
+		require.NoError(t, err)
+		assert.Equal(t, family, actual)
+		actual, err = reader.GetErrorRates(ctx, errorRateParams)
+		require.NoError(t, err)
+		assert.Equal(t, family, actual)
+		minStep, err := reader.GetMinStepDuration(ctx, &metricsstore.MinStepDurationQueryParameters{})
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, minStep)
+	})
+}
+
+func TestMetricsReader_Errors(t *testing.T) {
+	withMetricsReaderLoopback(func(impl *mockMetricsReaderPlugin, reader metricsstore.Reader) {
+		someErr := errors.New("made-up error")
+		impl.reader.On("GetLatencies", mock.Anything, mock.Anything).Return(nil, someErr)
+		impl.reader.On("GetCallRates", mock.Anything, mock.Anything).Return(nil, someErr)
+		impl.reader.On("GetErrorRates", mock.Anything, mock.Anything).Return(nil, someErr)
+		impl.reader.On("GetMinStepDuration", mock.Anything, mock.Anything).Return(time.Duration(0), someErr)
+
+		expected := "plugin error: made-up error"
+		_, err := reader.GetLatencies(context.Background(), &metricsstore.LatenciesQueryParameters{})
+		assert.EqualError(t, err, expected)
+		_, err = reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{})
+		assert.EqualError(t, err, expected)
+		_, err = reader.GetErrorRates(context.Background(), &metricsstore.ErrorRateQueryParameters{})
+		assert.EqualError(t, err, expected)
+		_, err = reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+		assert.EqualError(t, err, expected)
+	})
+}
+
+func TestMetricsReader_InvalidSpanKind(t *testing.T) {
+	withMetricsReaderLoopback(func(impl *mockMetricsReaderPlugin, reader metricsstore.Reader) {
+		base := metricsstore.BaseQueryParameters{SpanKinds: []string{"SERVER"}}
+		expected := `unsupported span kind "SERVER"`
+
+		_, err := reader.GetLatencies(context.Background(), &metricsstore.LatenciesQueryParameters{BaseQueryParameters: base})
+		assert.EqualError(t, err, expected)
+		_, err = reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{BaseQueryParameters: base})
+		assert.EqualError(t, err, expected)
+		_, err = reader.GetErrorRates(context.Background(), &metricsstore.ErrorRateQueryParameters{BaseQueryParameters: base})
+		assert.EqualError(t, err, expected)
+	})
+}
+
+func TestMetricsReader_NotImplemented(t *testing.T) {
+	client := &grpcClient{metricsReaderClient: &metricsReaderLoopback{server: &grpcServer{}}}
+	reader := client.MetricsReader()
+
+	_, err := reader.GetLatencies(context.Background(), &metricsstore.LatenciesQueryParameters{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = reader.GetCallRates(context.Background(), &metricsstore.CallRateQueryParameters{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = reader.GetErrorRates(context.Background(), &metricsstore.ErrorRateQueryParameters{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = reader.GetMinStepDuration(context.Background(), &metricsstore.MinStepDurationQueryParameters{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+}
+
+func TestBaseQueryParametersFromProto_Nil(t *testing.T) {
+	assert.Equal(t, metricsstore.BaseQueryParameters{}, baseQueryParametersFromProto(nil))
+}
@@ -32,15 +32,21 @@ type StorageGRPCPlugin struct {
 	plugin.Plugin
 
 	// Concrete implementation, This is only used for plugins that are written in Go.
-	Impl        StoragePlugin
-	ArchiveImpl ArchiveStoragePlugin
+	Impl                   StoragePlugin
+	ArchiveImpl            ArchiveStoragePlugin
+	DependenciesWriterImpl DependenciesWriterPlugin
+	SamplingStoreImpl      SamplingStorePlugin
+	MetricsReaderImpl      MetricsReaderPlugin
 }
 
 // GRPCServer implements plugin.GRPCPlugin. It is used by go-plugin to create a grpc plugin server.
 func (p *StorageGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
 	server := &grpcServer{
-		Impl:        p.Impl,
-		ArchiveImpl: p.ArchiveImpl,
+		Impl:                   p.Impl,
+		ArchiveImpl:            p.ArchiveImpl,
+		DependenciesWriterImpl: p.DependenciesWriterImpl,
+		SamplingStoreImpl:      p.SamplingStoreImpl,
+		MetricsReaderImpl:      p.MetricsReaderImpl,
 	}
 	storage_v1.RegisterSpanReaderPluginServer(s, server)
 	storage_v1.RegisterSpanWriterPluginServer(s, server)
@@ -48,6 +54,9 @@ func (p *StorageGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server
 	storage_v1.RegisterArchiveSpanWriterPluginServer(s, server)
 	storage_v1.RegisterPluginCapabilitiesServer(s, server)
 	storage_v1.RegisterDependenciesReaderPluginServer(s, server)
+	storage_v1.RegisterDependenciesWriterPluginServer(s, server)
+	storage_v1.RegisterSamplingStorePluginServer(s, server)
+	storage_v1.RegisterMetricsReaderPluginServer(s, server)
 	return nil
 }
 
@@ -60,5 +69,8 @@ func (*StorageGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBro
 		archiveWriterClient: storage_v1.NewArchiveSpanWriterPluginClient(c),
 		capabilitiesClient:  storage_v1.NewPluginCapabilitiesClient(c),
 		depsReaderClient:    storage_v1.NewDependenciesReaderPluginClient(c),
+		depsWriterClient:    storage_v1.NewDependenciesWriterPluginClient(c),
+		samplingStoreClient: storage_v1.NewSamplingStorePluginClient(c),
+		metricsReaderClient: storage_v1.NewMetricsReaderPluginClient(c),
 	}, nil
 }
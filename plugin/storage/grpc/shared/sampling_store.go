@@ -0,0 +1,226 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+)
+
+var (
+	_ samplingstore.Store  = (*samplingStore)(nil)
+	_ distributedlock.Lock = (*lock)(nil)
+)
+
+// samplingStore wraps storage_v1.SamplingStorePluginClient into samplingstore.Store
+type samplingStore struct {
+	client storage_v1.SamplingStorePluginClient
+}
+
+// lock wraps storage_v1.SamplingStorePluginClient into distributedlock.Lock
+type lock struct {
+	client storage_v1.SamplingStorePluginClient
+}
+
+// InsertThroughput inserts aggregated throughput for operations into storage.
+func (s *samplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	_, err := s.client.InsertThroughput(context.Background(), &storage_v1.InsertThroughputRequest{
+		Throughput: throughputToProto(throughput),
+	})
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	return nil
+}
+
+// InsertProbabilitiesAndQPS inserts calculated sampling probabilities and measured qps into storage.
+func (s *samplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	_, err := s.client.InsertProbabilitiesAndQPS(context.Background(), &storage_v1.InsertProbabilitiesAndQPSRequest{
+		Hostname:      hostname,
+		Probabilities: operationValuesToProto(probabilities),
+		QPS:           operationValuesToProto(qps),
+	})
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	return nil
+}
+
+// GetThroughput retrieves aggregated throughput for operations within a time range.
+func (s *samplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	resp, err := s.client.GetThroughput(context.Background(), &storage_v1.GetThroughputRequest{
+		StartTime: start,
+		EndTime:   end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return throughputFromProto(resp.Throughput), nil
+}
+
+// GetProbabilitiesAndQPS retrieves the sampling probabilities and measured qps per host within a time range.
+func (s *samplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	resp, err := s.client.GetProbabilitiesAndQPS(context.Background(), &storage_v1.GetProbabilitiesAndQPSRequest{
+		StartTime: start,
+		EndTime:   end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return hostSamplingDataFromProto(resp.HostData), nil
+}
+
+// GetLatestProbabilities retrieves the latest sampling probabilities.
+func (s *samplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	resp, err := s.client.GetLatestProbabilities(context.Background(), &storage_v1.GetLatestProbabilitiesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("plugin error: %w", err)
+	}
+	return operationValuesFromProto(resp.Probabilities), nil
+}
+
+// Acquire acquires a lease around a given resource.
+func (l *lock) Acquire(resource string, ttl time.Duration) (bool, error) {
+	resp, err := l.client.AcquireLock(context.Background(), &storage_v1.AcquireLockRequest{
+		Resource: resource,
+		Ttl:      ttl,
+	})
+	if err != nil {
+		return false, fmt.Errorf("plugin error: %w", err)
+	}
+	return resp.Acquired, nil
+}
+
+// Forfeit forfeits a lease around a given resource.
+func (l *lock) Forfeit(resource string) (bool, error) {
+	resp, err := l.client.ForfeitLock(context.Background(), &storage_v1.ForfeitLockRequest{
+		Resource: resource,
+	})
+	if err != nil {
+		return false, fmt.Errorf("plugin error: %w", err)
+	}
+	return resp.Forfeited, nil
+}
+
+func throughputToProto(throughput []*model.Throughput) []*storage_v1.Throughput {
+	result := make([]*storage_v1.Throughput, len(throughput))
+	for i, t := range throughput {
+		probabilities := make([]string, 0, len(t.Probabilities))
+		for p := range t.Probabilities {
+			probabilities = append(probabilities, p)
+		}
+		result[i] = &storage_v1.Throughput{
+			Service:       t.Service,
+			Operation:     t.Operation,
+			Count:         t.Count,
+			Probabilities: probabilities,
+		}
+	}
+	return result
+}
+
+func throughputFromProto(throughput []*storage_v1.Throughput) []*model.Throughput {
+	result := make([]*model.Throughput, len(throughput))
+	for i, t := range throughput {
+		probabilities := make(map[string]struct{}, len(t.Probabilities))
+		for _, p := range t.Probabilities {
+			probabilities[p] = struct{}{}
+		}
+		result[i] = &model.Throughput{
+			Service:       t.Service,
+			Operation:     t.Operation,
+			Count:         t.Count,
+			Probabilities: probabilities,
+		}
+	}
+	return result
+}
+
+func operationValuesToProto(values map[string]map[string]float64) []storage_v1.OperationValue {
+	var result []storage_v1.OperationValue
+	for service, operations := range values {
+		for operation, value := range operations {
+			result = append(result, storage_v1.OperationValue{
+				Service:   service,
+				Operation: operation,
+				Value:     value,
+			})
+		}
+	}
+	return result
+}
+
+func operationValuesFromProto(values []storage_v1.OperationValue) map[string]map[string]float64 {
+	result := make(map[string]map[string]float64)
+	for _, v := range values {
+		if _, ok := result[v.Service]; !ok {
+			result[v.Service] = make(map[string]float64)
+		}
+		result[v.Service][v.Operation] = v.Value
+	}
+	return result
+}
+
+func hostSamplingDataToProto(hostData map[string][]model.ServiceOperationData) []storage_v1.HostSamplingData {
+	var result []storage_v1.HostSamplingData
+	for hostname, data := range hostData {
+		for _, serviceData := range data {
+			var operations []storage_v1.OperationSamplingData
+			for service, opData := range serviceData {
+				for operation, pq := range opData {
+					operations = append(operations, storage_v1.OperationSamplingData{
+						Service:     service,
+						Operation:   operation,
+						Probability: pq.Probability,
+						QPS:         pq.QPS,
+					})
+				}
+			}
+			result = append(result, storage_v1.HostSamplingData{
+				Hostname:   hostname,
+				Operations: operations,
+			})
+		}
+	}
+	return result
+}
+
+func hostSamplingDataFromProto(hostData []storage_v1.HostSamplingData) map[string][]model.ServiceOperationData {
+	result := make(map[string][]model.ServiceOperationData)
+	for _, h := range hostData {
+		serviceData := make(model.ServiceOperationData)
+		for _, op := range h.Operations {
+			if _, ok := serviceData[op.Service]; !ok {
+				serviceData[op.Service] = make(map[string]*model.ProbabilityAndQPS)
+			}
+			serviceData[op.Service][op.Operation] = &model.ProbabilityAndQPS{
+				Probability: op.Probability,
+				QPS:         op.QPS,
+			}
+		}
+		result[h.Hostname] = append(result[h.Hostname], serviceData)
+	}
+	return result
+}
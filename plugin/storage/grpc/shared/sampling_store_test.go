@@ -0,0 +1,228 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	lockMocks "github.com/jaegertracing/jaeger/pkg/distributedlock/mocks"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1/mocks"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
+	samplingStoreMocks "github.com/jaegertracing/jaeger/storage/samplingstore/mocks"
+)
+
+type mockSamplingStorePlugin struct {
+	store *samplingStoreMocks.Store
+	lock  *lockMocks.Lock
+}
+
+func (p *mockSamplingStorePlugin) SamplingStore() samplingstore.Store {
+	return p.store
+}
+
+func (p *mockSamplingStorePlugin) Lock() distributedlock.Lock {
+	return p.lock
+}
+
+// samplingStoreLoopback calls the server directly, so that the tests cover the conversions both ways
+type samplingStoreLoopback struct {
+	server *grpcServer
+}
+
+func (l *samplingStoreLoopback) InsertThroughput(
+	ctx context.Context, in *storage_v1.InsertThroughputRequest, _ ...grpc.CallOption,
+) (*storage_v1.InsertThroughputResponse, error) {
+	return l.server.InsertThroughput(ctx, in)
+}
+
+func (l *samplingStoreLoopback) InsertProbabilitiesAndQPS(
+	ctx context.Context, in *storage_v1.InsertProbabilitiesAndQPSRequest, _ ...grpc.CallOption,
+) (*storage_v1.InsertProbabilitiesAndQPSResponse, error) {
+	return l.server.InsertProbabilitiesAndQPS(ctx, in)
+}
+
+func (l *samplingStoreLoopback) GetThroughput(
+	ctx context.Context, in *storage_v1.GetThroughputRequest, _ ...grpc.CallOption,
+) (*storage_v1.GetThroughputResponse, error) {
+	return l.server.GetThroughput(ctx, in)
+}
+
+func (l *samplingStoreLoopback) GetProbabilitiesAndQPS(
+	ctx context.Context, in *storage_v1.GetProbabilitiesAndQPSRequest, _ ...grpc.CallOption,
+) (*storage_v1.GetProbabilitiesAndQPSResponse, error) {
+	return l.server.GetProbabilitiesAndQPS(ctx, in)
+}
+
+func (l *samplingStoreLoopback) GetLatestProbabilities(
+	ctx context.Context, in *storage_v1.GetLatestProbabilitiesRequest, _ ...grpc.CallOption,
+) (*storage_v1.GetLatestProbabilitiesResponse, error) {
+	return l.server.GetLatestProbabilities(ctx, in)
+}
+
+func (l *samplingStoreLoopback) AcquireLock(
+	ctx context.Context, in *storage_v1.AcquireLockRequest, _ ...grpc.CallOption,
+) (*storage_v1.AcquireLockResponse, error) {
+	return l.server.AcquireLock(ctx, in)
+}
+
+func (l *samplingStoreLoopback) ForfeitLock(
+	ctx context.Context, in *storage_v1.ForfeitLockRequest, _ ...grpc.CallOption,
+) (*storage_v1.ForfeitLockResponse, error) {
+	return l.server.ForfeitLock(ctx, in)
+}
+
+func withSamplingStoreLoopback(fn func(impl *mockSamplingStorePlugin, client *grpcClient)) {
+	impl := &mockSamplingStorePlugin{
+		store: new(samplingStoreMocks.Store),
+		lock:  new(lockMocks.Lock),
+	}
+	loopback := &samplingStoreLoopback{server: &grpcServer{SamplingStoreImpl: impl}}
+	fn(impl, &grpcClient{samplingStoreClient: loopback})
+}
+
+func TestSamplingStore_Throughput(t *testing.T) {
+	withSamplingStoreLoopback(func(impl *mockSamplingStorePlugin, client *grpcClient) {
+		throughput := []*model.Throughput{
+			{
+				Service:       "svc",
+				Operation:     "op",
+				Count:         10,
+				Probabilities: map[string]struct{}{"0.1": {}, "0.5": {}},
+			},
+		}
+		start, end := time.Unix(100, 0).UTC(), time.Unix(200, 0).UTC()
+		impl.store.On("InsertThroughput", throughput).Return(nil)
+		impl.store.On("GetThroughput", start, end).Return(throughput, nil)
+
+		require.NoError(t, client.SamplingStore().InsertThroughput(throughput))
+		actual, err := client.SamplingStore().GetThroughput(start, end)
+		require.NoError(t, err)
+		assert.Equal(t, throughput, actual)
+	})
+}
+
+func TestSamplingStore_ProbabilitiesAndQPS(t *testing.T) {
+	withSamplingStoreLoopback(func(impl *mockSamplingStorePlugin, client *grpcClient) {
+		probabilities := model.ServiceOperationProbabilities{"svc": {"op": 0.5, "op2": 0.1}}
+		qps := model.ServiceOperationQPS{"svc": {"op": 3, "op2": 10}}
+		hostData := map[string][]model.ServiceOperationData{
+			"host-a": {
+				{"svc": {"op": {Probability: 0.5, QPS: 3}}},
+				{"svc": {"op": {Probability: 0.4, QPS: 2}}},
+			},
+			"host-b": {
+				{"svc": {"op2": {Probability: 0.1, QPS: 10}}},
+			},
+		}
+		start, end := time.Unix(100, 0).UTC(), time.Unix(200, 0).UTC()
+		impl.store.On("InsertProbabilitiesAndQPS", "host-a", probabilities, qps).Return(nil)
+		impl.store.On("GetProbabilitiesAndQPS", start, end).Return(hostData, nil)
+		impl.store.On("GetLatestProbabilities").Return(probabilities, nil)
+
+		require.NoError(t, client.SamplingStore().InsertProbabilitiesAndQPS("host-a", probabilities, qps))
+		actualHostData, err := client.SamplingStore().GetProbabilitiesAndQPS(start, end)
+		require.NoError(t, err)
+		assert.Equal(t, hostData, actualHostData)
+		actualProbabilities, err := client.SamplingStore().GetLatestProbabilities()
+		require.NoError(t, err)
+		assert.Equal(t, probabilities, actualProbabilities)
+	})
+}
+
+func TestSamplingStore_Lock(t *testing.T) {
+	withSamplingStoreLoopback(func(impl *mockSamplingStorePlugin, client *grpcClient) {
+		impl.lock.On("Acquire", "leader", time.Minute).Return(true, nil)
+		impl.lock.On("Forfeit", "leader").Return(true, nil)
+
+		acquired, err := client.Lock().Acquire("leader", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		forfeited, err := client.Lock().Forfeit("leader")
+		require.NoError(t, err)
+		assert.True(t, forfeited)
+	})
+}
+
+func TestSamplingStore_Errors(t *testing.T) {
+	withSamplingStoreLoopback(func(impl *mockSamplingStorePlugin, client *grpcClient) {
+		someErr := errors.New("made-up error")
+		impl.store.On("InsertThroughput", []*model.Throughput{}).Return(someErr)
+		impl.store.On("InsertProbabilitiesAndQPS", "host", model.ServiceOperationProbabilities{}, model.ServiceOperationQPS{}).
+			Return(someErr)
+		impl.store.On("GetThroughput", time.Time{}, time.Time{}).Return([]*model.Throughput(nil), someErr)
+		impl.store.On("GetProbabilitiesAndQPS", time.Time{}, time.Time{}).
+			Return(map[string][]model.ServiceOperationData(nil), someErr)
+		impl.store.On("GetLatestProbabilities").Return(model.ServiceOperationProbabilities(nil), someErr)
+		impl.lock.On("Acquire", "leader", time.Duration(0)).Return(false, someErr)
+		impl.lock.On("Forfeit", "leader").Return(false, someErr)
+
+		expected := "plugin error: made-up error"
+		assert.EqualError(t, client.SamplingStore().InsertThroughput([]*model.Throughput{}), expected)
+		assert.EqualError(t, client.SamplingStore().InsertProbabilitiesAndQPS(
+			"host", model.ServiceOperationProbabilities{}, model.ServiceOperationQPS{}), expected)
+		_, err := client.SamplingStore().GetThroughput(time.Time{}, time.Time{})
+		assert.EqualError(t, err, expected)
+		_, err = client.SamplingStore().GetProbabilitiesAndQPS(time.Time{}, time.Time{})
+		assert.EqualError(t, err, expected)
+		_, err = client.SamplingStore().GetLatestProbabilities()
+		assert.EqualError(t, err, expected)
+		_, err = client.Lock().Acquire("leader", 0)
+		assert.EqualError(t, err, expected)
+		_, err = client.Lock().Forfeit("leader")
+		assert.EqualError(t, err, expected)
+	})
+}
+
+func TestSamplingStore_NotImplemented(t *testing.T) {
+	client := &grpcClient{samplingStoreClient: &samplingStoreLoopback{server: &grpcServer{}}}
+
+	err := client.SamplingStore().InsertThroughput(nil)
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	err = client.SamplingStore().InsertProbabilitiesAndQPS("host", nil, nil)
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.SamplingStore().GetThroughput(time.Time{}, time.Time{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.SamplingStore().GetProbabilitiesAndQPS(time.Time{}, time.Time{})
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.SamplingStore().GetLatestProbabilities()
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.Lock().Acquire("leader", time.Minute)
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+	_, err = client.Lock().Forfeit("leader")
+	assert.Equal(t, codes.Unimplemented, status.Code(errors.Unwrap(err)))
+}
+
+func TestSamplingStore_ClientMock(t *testing.T) {
+	samplingClient := new(mocks.SamplingStorePluginClient)
+	samplingClient.On("GetLatestProbabilities", context.Background(), &storage_v1.GetLatestProbabilitiesRequest{}).
+		Return(&storage_v1.GetLatestProbabilitiesResponse{}, nil)
+	store := &samplingStore{client: samplingClient}
+
+	probabilities, err := store.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Empty(t, probabilities)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+const (
+	// DefaultWriteBatchSize is the default maximum number of spans of a batch written on the WriteSpans stream
+	DefaultWriteBatchSize = 100
+	// DefaultWriteBatchFlushInterval is the default maximum time a span waits for its batch to be written
+	DefaultWriteBatchFlushInterval = 100 * time.Millisecond
+	// DefaultWriteQueueSize is the default number of spans waiting to be batched before WriteSpan blocks
+	DefaultWriteQueueSize = 10000
+)
+
+// StreamingWriterOptions configures the batching of the spans written on the WriteSpans stream
+type StreamingWriterOptions struct {
+	// BatchSize is the maximum number of spans of a batch
+	BatchSize int
+	// FlushInterval is the maximum time a span waits for its batch to be written
+	FlushInterval time.Duration
+	// QueueSize is the number of spans waiting to be batched, WriteSpan blocks when the queue is full
+	QueueSize int
+	// MetricsFactory is used to report the batches written and the backpressure of the plugin
+	MetricsFactory metrics.Factory
+	// Logger is used to report the batches the plugin failed to write
+	Logger *zap.Logger
+}
+
+// StreamingSpanWriterPlugin is implemented by the plugin clients able to write batches of spans on a stream.
+// It must only be used when the plugin advertises the Capabilities.StreamingSpanWriter capability.
+type StreamingSpanWriterPlugin interface {
+	StreamingSpanWriter(options StreamingWriterOptions) spanstore.Writer
+}
+
+type streamingWriterMetrics struct {
+	// BatchesWritten counts the batches written by the plugin
+	BatchesWritten metrics.Counter `metric:"batches" tags:"result=ok"`
+	// BatchesFailed counts the batches the plugin failed to write
+	BatchesFailed metrics.Counter `metric:"batches" tags:"result=err"`
+	// SpansWritten counts the spans of the batches written by the plugin
+	SpansWritten metrics.Counter `metric:"spans" tags:"result=ok"`
+	// SpansFailed counts the spans of the batches the plugin failed to write
+	SpansFailed metrics.Counter `metric:"spans" tags:"result=err"`
+	// BatchLatency is the time taken by the plugin to write a batch
+	BatchLatency metrics.Timer `metric:"batch_latency"`
+	// QueueLength is the number of spans waiting to be batched
+	QueueLength metrics.Gauge `metric:"queue_length"`
+	// BlockedWrites counts the spans that waited for room in the full queue, i.e. the backpressure of the plugin
+	BlockedWrites metrics.Counter `metric:"blocked_writes"`
+}
+
+type queuedSpan struct {
+	tenant string
+	span   *model.Span
+}
+
+// streamingWriter is a span writer batching the spans, per tenant, by size and time,
+// and writing each batch on a WriteSpans stream. WriteSpan returns once the span is queued,
+// the errors of the plugin are reported in the metrics and logs.
+type streamingWriter struct {
+	client  *grpcClient
+	options StreamingWriterOptions
+	logger  *zap.Logger
+	metrics streamingWriterMetrics
+
+	closeLock sync.RWMutex
+	closed    bool
+	queue     chan queuedSpan
+	stopped   sync.WaitGroup
+}
+
+// StreamingSpanWriter implements StreamingSpanWriterPlugin
+func (c *grpcClient) StreamingSpanWriter(options StreamingWriterOptions) spanstore.Writer {
+	return newStreamingWriter(c, options)
+}
+
+func newStreamingWriter(client *grpcClient, options StreamingWriterOptions) *streamingWriter {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultWriteBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultWriteBatchFlushInterval
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultWriteQueueSize
+	}
+	if options.MetricsFactory == nil {
+		options.MetricsFactory = metrics.NullFactory
+	}
+	if options.Logger == nil {
+		options.Logger = zap.NewNop()
+	}
+	w := &streamingWriter{
+		client:  client,
+		options: options,
+		logger:  options.Logger,
+		queue:   make(chan queuedSpan, options.QueueSize),
+	}
+	metrics.Init(&w.metrics, options.MetricsFactory, nil)
+	w.stopped.Add(1)
+	go w.batchSpans()
+	return w
+}
+
+// WriteSpan queues the span to be written with the next batch of its tenant.
+// It blocks while the queue is full.
+func (w *streamingWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	w.closeLock.RLock()
+	defer w.closeLock.RUnlock()
+	if w.closed {
+		return fmt.Errorf("plugin error: span writer is closed")
+	}
+	item := queuedSpan{tenant: tenancy.GetTenant(ctx), span: span}
+	select {
+	case w.queue <- item:
+		return nil
+	default:
+		w.metrics.BlockedWrites.Inc(1)
+	}
+	select {
+	case w.queue <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes the pending batches and closes the plugin span writer
+func (w *streamingWriter) Close() error {
+	w.closeLock.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.closeLock.Unlock()
+	w.stopped.Wait()
+	return w.client.Close()
+}
+
+func (w *streamingWriter) batchSpans() {
+	defer w.stopped.Done()
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+	batches := make(map[string][]*model.Span)
+	flush := func() {
+		for tenant, batch := range batches {
+			w.writeBatch(tenant, batch)
+			delete(batches, tenant)
+		}
+	}
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch := append(batches[item.tenant], item.span)
+			if len(batch) >= w.options.BatchSize {
+				w.writeBatch(item.tenant, batch)
+				delete(batches, item.tenant)
+			} else {
+				batches[item.tenant] = batch
+			}
+		case <-ticker.C:
+			w.metrics.QueueLength.Update(int64(len(w.queue)))
+			flush()
+		}
+	}
+}
+
+func (w *streamingWriter) writeBatch(tenant string, batch []*model.Span) {
+	start := time.Now()
+	err := w.sendBatch(tenant, batch)
+	w.metrics.BatchLatency.Record(time.Since(start))
+	if err != nil {
+		w.metrics.BatchesFailed.Inc(1)
+		w.metrics.SpansFailed.Inc(int64(len(batch)))
+		w.logger.Error("Failed to write a batch of spans to the storage plugin",
+			zap.Int("spans", len(batch)), zap.String("tenant", tenant), zap.Error(err))
+		return
+	}
+	w.metrics.BatchesWritten.Inc(1)
+	w.metrics.SpansWritten.Inc(int64(len(batch)))
+}
+
+// sendBatch writes the batch on a WriteSpans stream, in chunks of at most spanBatchSize spans
+func (w *streamingWriter) sendBatch(tenant string, batch []*model.Span) error {
+	ctx := upgradeContext(tenancy.WithTenant(context.Background(), tenant))
+	stream, err := w.client.writerClient.WriteSpans(ctx)
+	if err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	for start := 0; start < len(batch); start += spanBatchSize {
+		end := start + spanBatchSize
+		if end > len(batch) {
+			end = len(batch)
+		}
+		if err := stream.Send(&storage_v1.WriteSpansRequest{Spans: batch[start:end]}); err != nil {
+			// the actual error is returned by CloseAndRecv
+			break
+		}
+	}
+	if _, err := stream.CloseAndRecv(); err != nil {
+		return fmt.Errorf("plugin error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/proto-gen/storage_v1"
+	grpcMocks "github.com/jaegertracing/jaeger/proto-gen/storage_v1/mocks"
+)
+
+// recordingStream is a WriteSpans client stream recording the batches sent by the writer
+type recordingStream struct {
+	sync.Mutex
+	batches map[string][][]*model.Span // per tenant
+}
+
+func (s *recordingStream) mock(r *grpcClientTest, closeErr error) {
+	r.spanWriter.On("WriteSpans", mock.Anything).Return(
+		func(ctx context.Context, _ ...grpc.CallOption) storage_v1.SpanWriterPlugin_WriteSpansClient {
+			var tenant string
+			if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(TenantMetadataKey)) > 0 {
+				tenant = md.Get(TenantMetadataKey)[0]
+			}
+			var spans []*model.Span
+			stream := new(grpcMocks.SpanWriterPlugin_WriteSpansClient)
+			stream.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+				spans = append(spans, args.Get(0).(*storage_v1.WriteSpansRequest).Spans...)
+			}).Return(nil)
+			stream.On("CloseAndRecv").Run(func(mock.Arguments) {
+				s.Lock()
+				defer s.Unlock()
+				s.batches[tenant] = append(s.batches[tenant], spans)
+			}).Return(&storage_v1.WriteSpansResponse{}, closeErr)
+			return stream
+		}, nil)
+	r.spanWriter.On("Close", mock.Anything, &storage_v1.CloseWriterRequest{}).
+		Return(&storage_v1.CloseWriterResponse{}, nil)
+}
+
+func (s *recordingStream) batchesOf(tenant string) [][]*model.Span {
+	s.Lock()
+	defer s.Unlock()
+	return s.batches[tenant]
+}
+
+func withStreamingWriter(options StreamingWriterOptions, closeErr error, fn func(w *streamingWriter, stream *recordingStream)) {
+	withGRPCClient(func(r *grpcClientTest) {
+		stream := &recordingStream{batches: make(map[string][][]*model.Span)}
+		stream.mock(r, closeErr)
+		fn(newStreamingWriter(r.client, options), stream)
+	})
+}
+
+func TestStreamingWriterBatchSize(t *testing.T) {
+	options := StreamingWriterOptions{BatchSize: 2, FlushInterval: time.Hour}
+	withStreamingWriter(options, nil, func(w *streamingWriter, stream *recordingStream) {
+		for i := range mockTracesSpans {
+			require.NoError(t, w.WriteSpan(context.Background(), &mockTracesSpans[i]))
+		}
+		assert.Eventually(t, func() bool {
+			return len(stream.batchesOf("")) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []*model.Span{&mockTracesSpans[0], &mockTracesSpans[1]}, stream.batchesOf("")[0])
+
+		require.NoError(t, w.Close())
+		assert.Equal(t, [][]*model.Span{
+			{&mockTracesSpans[0], &mockTracesSpans[1]},
+			{&mockTracesSpans[2]},
+		}, stream.batchesOf(""))
+
+		assert.EqualError(t, w.WriteSpan(context.Background(), &mockTracesSpans[0]), "plugin error: span writer is closed")
+	})
+}
+
+func TestStreamingWriterFlushInterval(t *testing.T) {
+	options := StreamingWriterOptions{BatchSize: 100, FlushInterval: time.Millisecond}
+	withStreamingWriter(options, nil, func(w *streamingWriter, stream *recordingStream) {
+		require.NoError(t, w.WriteSpan(context.Background(), &mockTraceSpans[0]))
+		assert.Eventually(t, func() bool {
+			return len(stream.batchesOf("")) == 1
+		}, time.Second, time.Millisecond)
+		require.NoError(t, w.Close())
+	})
+}
+
+func TestStreamingWriterBatchesPerTenant(t *testing.T) {
+	options := StreamingWriterOptions{BatchSize: 100, FlushInterval: time.Hour}
+	withStreamingWriter(options, nil, func(w *streamingWriter, stream *recordingStream) {
+		require.NoError(t, w.WriteSpan(tenancy.WithTenant(context.Background(), "acme"), &mockTraceSpans[0]))
+		require.NoError(t, w.WriteSpan(tenancy.WithTenant(context.Background(), "megacorp"), &mockTraceSpans[1]))
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, [][]*model.Span{{&mockTraceSpans[0]}}, stream.batchesOf("acme"))
+		assert.Equal(t, [][]*model.Span{{&mockTraceSpans[1]}}, stream.batchesOf("megacorp"))
+	})
+}
+
+func TestStreamingWriterMetrics(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	options := StreamingWriterOptions{BatchSize: 2, FlushInterval: time.Hour, MetricsFactory: metricsFactory}
+	withStreamingWriter(options, errors.New("storage error"), func(w *streamingWriter, stream *recordingStream) {
+		for i := range mockTracesSpans {
+			require.NoError(t, w.WriteSpan(context.Background(), &mockTracesSpans[i]))
+		}
+		require.NoError(t, w.Close())
+
+		metricsFactory.AssertCounterMetrics(t,
+			metricstest.ExpectedMetric{Name: "batches", Tags: map[string]string{"result": "err"}, Value: 2},
+			metricstest.ExpectedMetric{Name: "spans", Tags: map[string]string{"result": "err"}, Value: 3},
+			metricstest.ExpectedMetric{Name: "batches", Tags: map[string]string{"result": "ok"}, Value: 0},
+		)
+	})
+}
+
+func TestStreamingWriterBackpressure(t *testing.T) {
+	metricsFactory := metricstest.NewFactory(0)
+	withGRPCClient(func(r *grpcClientTest) {
+		unblock := make(chan struct{})
+		stream := new(grpcMocks.SpanWriterPlugin_WriteSpansClient)
+		stream.On("Send", mock.Anything).Return(nil)
+		stream.On("CloseAndRecv").Run(func(mock.Arguments) { <-unblock }).Return(&storage_v1.WriteSpansResponse{}, nil)
+		r.spanWriter.On("WriteSpans", mock.Anything).Return(stream, nil)
+		r.spanWriter.On("Close", mock.Anything, &storage_v1.CloseWriterRequest{}).
+			Return(&storage_v1.CloseWriterResponse{}, nil)
+
+		w := newStreamingWriter(r.client, StreamingWriterOptions{
+			BatchSize:      1,
+			FlushInterval:  time.Hour,
+			QueueSize:      1,
+			MetricsFactory: metricsFactory,
+		})
+		// the first span is stuck in the plugin, the second one fills the queue
+		require.NoError(t, w.WriteSpan(context.Background(), &mockTracesSpans[0]))
+		assert.Eventually(t, func() bool { return len(w.queue) == 0 }, time.Second, time.Millisecond)
+		require.NoError(t, w.WriteSpan(context.Background(), &mockTracesSpans[1]))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, w.WriteSpan(ctx, &mockTracesSpans[2]))
+		metricsFactory.AssertCounterMetrics(t,
+			metricstest.ExpectedMetric{Name: "blocked_writes", Value: 1})
+
+		close(unblock)
+		require.NoError(t, w.Close())
+		metricsFactory.AssertCounterMetrics(t,
+			metricstest.ExpectedMetric{Name: "batches", Tags: map[string]string{"result": "ok"}, Value: 2})
+	})
+}
+
+func TestStreamingWriterStreamError(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanWriter.On("WriteSpans", mock.Anything).Return(nil, errors.New("unavailable"))
+		w := newStreamingWriter(r.client, StreamingWriterOptions{})
+		err := w.sendBatch("", []*model.Span{&mockTraceSpans[0]})
+		assert.EqualError(t, err, "plugin error: unavailable")
+	})
+}
+
+func TestGRPCClientStreamingSpanWriter(t *testing.T) {
+	withGRPCClient(func(r *grpcClientTest) {
+		r.spanWriter.On("Close", mock.Anything, &storage_v1.CloseWriterRequest{}).
+			Return(&storage_v1.CloseWriterResponse{}, nil)
+		w := r.client.StreamingSpanWriter(StreamingWriterOptions{})
+		require.IsType(t, &streamingWriter{}, w)
+		sw := w.(*streamingWriter)
+		assert.Equal(t, DefaultWriteBatchSize, sw.options.BatchSize)
+		assert.Equal(t, DefaultWriteBatchFlushInterval, sw.options.FlushInterval)
+		assert.Equal(t, DefaultWriteQueueSize, cap(sw.queue))
+		assert.NoError(t, sw.Close())
+	})
+}
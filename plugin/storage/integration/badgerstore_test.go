@@ -52,6 +52,17 @@ func (s *BadgerIntegrationStorage) initialize() error {
 	s.SpanReader = sr
 	s.SpanWriter = sw
 
+	ss, err := s.factory.CreateSamplingStore()
+	if err != nil {
+		return err
+	}
+	lock, err := s.factory.CreateLock()
+	if err != nil {
+		return err
+	}
+	s.SamplingStore = ss
+	s.Lock = lock
+
 	s.Refresh = s.refresh
 	s.CleanUp = s.cleanUp
 
@@ -35,8 +35,10 @@ import (
 	estemplate "github.com/jaegertracing/jaeger/pkg/es"
 	eswrapper "github.com/jaegertracing/jaeger/pkg/es/wrapper"
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	eslock "github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/es"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/dependencystore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/mappings"
+	"github.com/jaegertracing/jaeger/plugin/storage/es/samplingstore"
 	"github.com/jaegertracing/jaeger/plugin/storage/es/spanstore"
 )
 
@@ -163,6 +165,26 @@ func (s *ESStorageIntegration) initSpanstore(allTagsAsFields, archive bool) erro
 	}
 	s.DependencyReader = dependencyStore
 	s.DependencyWriter = dependencyStore
+
+	samplingStore := samplingstore.NewSamplingStore(samplingstore.SamplingStoreParams{
+		Client:          client,
+		Logger:          s.logger,
+		IndexPrefix:     indexPrefix,
+		IndexDateLayout: indexDateLayout,
+		MaxDocCount:     defaultMaxDocCount,
+	})
+	samplingMapping, err := mappingBuilder.GetSamplingMappings()
+	if err != nil {
+		return err
+	}
+	if err := samplingStore.CreateTemplates(samplingMapping); err != nil {
+		return err
+	}
+	s.SamplingStore = samplingStore
+	if esVersion >= 7 {
+		// the lock relies on the sequence numbers of the documents
+		s.Lock = eslock.NewLock(client, indexPrefix, "localhost")
+	}
 	return nil
 }
 
@@ -227,8 +249,10 @@ func TestElasticsearchStorage_IndexTemplates(t *testing.T) {
 	require.NoError(t, s.initializeES(true, false))
 	serviceTemplateExists, _ := s.client.IndexTemplateExists(indexPrefix + "-jaeger-service").Do(context.Background())
 	spanTemplateExists, _ := s.client.IndexTemplateExists(indexPrefix + "-jaeger-span").Do(context.Background())
+	samplingTemplateExists, _ := s.client.IndexTemplateExists(indexPrefix + "-jaeger-sampling").Do(context.Background())
 	assert.True(t, serviceTemplateExists)
 	assert.True(t, spanTemplateExists)
+	assert.True(t, samplingTemplateExists)
 }
 
 func (s *StorageIntegration) testArchiveTrace(t *testing.T) {
@@ -30,8 +30,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	samplemodel "github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
@@ -45,6 +48,8 @@ type StorageIntegration struct {
 	SpanReader       spanstore.Reader
 	DependencyWriter dependencystore.Writer
 	DependencyReader dependencystore.Reader
+	SamplingStore    samplingstore.Store
+	Lock             distributedlock.Lock
 	Fixtures         []*QueryFixtures
 	// TODO: remove this flag after all storage plugins returns spanKind with operationNames
 	NotSupportSpanKindWithOperation bool
@@ -376,6 +381,168 @@ func (s *StorageIntegration) testGetDependencies(t *testing.T) {
 	assert.EqualValues(t, expected, actual)
 }
 
+func (s *StorageIntegration) testGetOperationDependencies(t *testing.T) {
+	writer, ok := s.DependencyWriter.(dependencystore.OperationWriter)
+	if !ok {
+		t.Skipf("Skipping GetOperationDependencies test because the dependency writer does not support operations")
+		return
+	}
+	reader, ok := s.DependencyReader.(dependencystore.OperationReader)
+	if !ok {
+		t.Skipf("Skipping GetOperationDependencies test because the dependency reader does not support operations")
+		return
+	}
+
+	defer s.cleanUp(t)
+
+	expected := []model.OperationDependencyLink{
+		{
+			Parent:          "hello",
+			ParentOperation: "greet",
+			Child:           "world",
+			ChildOperation:  "spin",
+			CallCount:       uint64(3),
+			ErrorCount:      uint64(1),
+			Latency:         model.LatencySummary{Min: time.Millisecond, Max: 5 * time.Millisecond, Total: 9 * time.Millisecond},
+		},
+	}
+	require.NoError(t, writer.WriteOperationDependencies(context.Background(), time.Now(), expected))
+	s.refresh(t)
+	actual, err := reader.GetOperationDependencies(context.Background(), time.Now(), 5*time.Minute)
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, actual)
+}
+
+// === SamplingStore Integration Tests ===
+
+func (s *StorageIntegration) testGetThroughput(t *testing.T) {
+	if s.SamplingStore == nil {
+		t.Skipf("Skipping GetThroughput test because sampling store is nil")
+		return
+	}
+	defer s.cleanUp(t)
+
+	start := time.Now().Add(-time.Minute)
+	expected := []*samplemodel.Throughput{
+		{Service: "svc-1", Operation: "op-1", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}},
+		{Service: "svc-2", Operation: "op-2", Count: 20, Probabilities: map[string]struct{}{"0.1": {}, "0.5": {}}},
+	}
+	require.NoError(t, s.SamplingStore.InsertThroughput(expected[:1]))
+	// the throughput is returned in the order of the timestamps, which must differ
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, s.SamplingStore.InsertThroughput(expected[1:]))
+	s.refresh(t)
+
+	var actual []*samplemodel.Throughput
+	found := s.waitForCondition(t, func(t *testing.T) bool {
+		var err error
+		actual, err = s.SamplingStore.GetThroughput(start, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return assert.ObjectsAreEqualValues(expected, actual)
+	})
+	if !assert.True(t, found) {
+		t.Log("\t Expected:", expected)
+		t.Log("\t Actual  :", actual)
+	}
+
+	actual, err := s.SamplingStore.GetThroughput(start.Add(-time.Hour), start)
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
+func (s *StorageIntegration) testGetProbabilitiesAndQPS(t *testing.T) {
+	if s.SamplingStore == nil {
+		t.Skipf("Skipping GetProbabilitiesAndQPS test because sampling store is nil")
+		return
+	}
+	defer s.cleanUp(t)
+
+	start := time.Now().Add(-time.Minute)
+	require.NoError(t, s.SamplingStore.InsertProbabilitiesAndQPS("host-1",
+		samplemodel.ServiceOperationProbabilities{"svc": {"op-1": 0.1, "op-2": 0.2}},
+		samplemodel.ServiceOperationQPS{"svc": {"op-1": 5}},
+	))
+	s.refresh(t)
+
+	expected := map[string][]samplemodel.ServiceOperationData{
+		"host-1": {{"svc": {
+			"op-1": {Probability: 0.1, QPS: 5},
+			"op-2": {Probability: 0.2, QPS: 0},
+		}}},
+	}
+	var actual map[string][]samplemodel.ServiceOperationData
+	found := s.waitForCondition(t, func(t *testing.T) bool {
+		var err error
+		actual, err = s.SamplingStore.GetProbabilitiesAndQPS(start, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return assert.ObjectsAreEqualValues(expected, actual)
+	})
+	if !assert.True(t, found) {
+		t.Log("\t Expected:", expected)
+		t.Log("\t Actual  :", actual)
+	}
+}
+
+func (s *StorageIntegration) testGetLatestProbabilities(t *testing.T) {
+	if s.SamplingStore == nil {
+		t.Skipf("Skipping GetLatestProbabilities test because sampling store is nil")
+		return
+	}
+	defer s.cleanUp(t)
+
+	latest, err := s.SamplingStore.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	require.NoError(t, s.SamplingStore.InsertProbabilitiesAndQPS("host-1",
+		samplemodel.ServiceOperationProbabilities{"svc": {"op": 0.1}},
+		samplemodel.ServiceOperationQPS{"svc": {"op": 5}},
+	))
+	// the timestamps of the documents must differ for the latest one to be well defined
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, s.SamplingStore.InsertProbabilitiesAndQPS("host-2",
+		samplemodel.ServiceOperationProbabilities{"svc": {"op": 0.2}},
+		samplemodel.ServiceOperationQPS{"svc": {"op": 7}},
+	))
+	s.refresh(t)
+
+	expected := samplemodel.ServiceOperationProbabilities{"svc": {"op": 0.2}}
+	found := s.waitForCondition(t, func(t *testing.T) bool {
+		latest, err = s.SamplingStore.GetLatestProbabilities()
+		require.NoError(t, err)
+		return assert.ObjectsAreEqualValues(expected, latest)
+	})
+	if !assert.True(t, found) {
+		t.Log("\t Expected:", expected)
+		t.Log("\t Actual  :", latest)
+	}
+}
+
+func (s *StorageIntegration) testLock(t *testing.T) {
+	if s.Lock == nil {
+		t.Skipf("Skipping Lock test because lock is nil")
+		return
+	}
+	defer s.cleanUp(t)
+
+	resource := "integration-test-lock"
+	acquired, err := s.Lock.Acquire(resource, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = s.Lock.Acquire(resource, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the owner of the lock can extend its lease")
+
+	forfeited, err := s.Lock.Forfeit(resource)
+	require.NoError(t, err)
+	assert.True(t, forfeited)
+
+	forfeited, err = s.Lock.Forfeit(resource)
+	assert.Error(t, err)
+	assert.False(t, forfeited)
+}
+
 // IntegrationTestAll runs all integration tests
 func (s *StorageIntegration) IntegrationTestAll(t *testing.T) {
 	t.Run("GetServices", s.testGetServices)
@@ -384,4 +551,9 @@ func (s *StorageIntegration) IntegrationTestAll(t *testing.T) {
 	t.Run("GetLargeSpans", s.testGetLargeSpan)
 	t.Run("FindTraces", s.testFindTraces)
 	t.Run("GetDependencies", s.testGetDependencies)
+	t.Run("GetOperationDependencies", s.testGetOperationDependencies)
+	t.Run("GetThroughput", s.testGetThroughput)
+	t.Run("GetProbabilitiesAndQPS", s.testGetProbabilitiesAndQPS)
+	t.Run("GetLatestProbabilities", s.testGetLatestProbabilities)
+	t.Run("Lock", s.testLock)
 }
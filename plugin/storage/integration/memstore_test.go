@@ -17,11 +17,13 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/jaegertracing/jaeger/pkg/testutils"
+	"github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/local"
 	"github.com/jaegertracing/jaeger/plugin/storage/memory"
 )
 
@@ -39,6 +41,9 @@ func (s *MemStorageIntegrationTestSuite) initialize() error {
 
 	// TODO DependencyWriter is not implemented in memory store
 
+	s.SamplingStore = memory.NewSamplingStore(time.Hour)
+	s.Lock = local.NewLock("localhost")
+
 	s.Refresh = s.refresh
 	s.CleanUp = s.cleanUp
 	return nil
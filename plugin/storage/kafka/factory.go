@@ -38,6 +38,7 @@ type Factory struct {
 
 	producer   sarama.AsyncProducer
 	marshaller Marshaller
+	router     *Router
 	producer.Builder
 }
 
@@ -69,11 +70,6 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	logger.Info("Kafka factory",
 		zap.Any("producer builder", f.Builder),
 		zap.Any("topic", f.options.Topic))
-	p, err := f.NewProducer(logger)
-	if err != nil {
-		return err
-	}
-	f.producer = p
 	switch f.options.Encoding {
 	case EncodingProto:
 		f.marshaller = newProtobufMarshaller()
@@ -82,9 +78,46 @@ func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger)
 	default:
 		return errors.New("kafka encoding is not one of '" + EncodingJSON + "' or '" + EncodingProto + "'")
 	}
+	router, err := f.newRouter()
+	if err != nil {
+		return err
+	}
+	f.router = router
+	p, err := f.NewProducer(logger)
+	if err != nil {
+		return err
+	}
+	f.producer = p
 	return nil
 }
 
+func (f *Factory) newRouter() (*Router, error) {
+	params := RouterParams{
+		DefaultTopic: f.options.Topic,
+		PartitionKey: f.options.PartitionKey,
+		Headers:      supportsHeaders(f.options.Config.ProtocolVersion),
+		Encoding:     f.options.Encoding,
+	}
+	if f.options.RoutingRules != "" {
+		rules, err := LoadRoutingRules(f.options.RoutingRules)
+		if err != nil {
+			return nil, err
+		}
+		params.Rules = rules
+	}
+	return NewRouter(params)
+}
+
+// supportsHeaders returns true if the protocol version supports record headers, which were
+// introduced in Kafka 0.11. Sarama defaults to a version that supports them.
+func supportsHeaders(protocolVersion string) bool {
+	if protocolVersion == "" {
+		return true
+	}
+	version, err := sarama.ParseKafkaVersion(protocolVersion)
+	return err == nil && version.IsAtLeast(sarama.V0_11_0_0)
+}
+
 // CreateSpanReader implements storage.Factory
 func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 	return nil, errors.New("kafka storage is write-only")
@@ -92,7 +125,7 @@ func (f *Factory) CreateSpanReader() (spanstore.Reader, error) {
 
 // CreateSpanWriter implements storage.Factory
 func (f *Factory) CreateSpanWriter() (spanstore.Writer, error) {
-	return NewSpanWriter(f.producer, f.marshaller, f.options.Topic, f.metricsFactory, f.logger), nil
+	return NewRoutingSpanWriter(f.producer, f.marshaller, f.router, f.metricsFactory, f.logger), nil
 }
 
 // CreateDependencyReader implements storage.Factory
@@ -100,6 +133,12 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return nil, errors.New("kafka storage is write-only")
 }
 
+// ValidateTenantIsolation implements storage.TenantIsolationFactory, the tenant is published in the
+// headers of the messages, and the ingester writes the spans to the storage of their tenant.
+func (f *Factory) ValidateTenantIsolation() error {
+	return nil
+}
+
 var _ io.Closer = (*Factory)(nil)
 
 // Close closes the resources held by the factory
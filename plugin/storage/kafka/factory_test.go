@@ -109,6 +109,55 @@ func TestKafkaFactoryMarshallerErr(t *testing.T) {
 	assert.Error(t, f.Initialize(metrics.NullFactory, zap.NewNop()))
 }
 
+func TestKafkaFactoryRouting(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       []string
+		expectedErr string
+	}{
+		{
+			name:  "rules",
+			flags: []string{"--kafka.producer.routing-rules=fixtures/routing_rules.yaml", "--kafka.producer.partition-key=tag:customer"},
+		},
+		{
+			name:        "missing rules",
+			flags:       []string{"--kafka.producer.routing-rules=fixtures/missing.yaml"},
+			expectedErr: "cannot read routing rules: open fixtures/missing.yaml: no such file or directory",
+		},
+		{
+			name:        "invalid partition key",
+			flags:       []string{"--kafka.producer.partition-key=span-id"},
+			expectedErr: `unknown partition key 'span-id', use one of "trace-id", "service" or "tag:<tag>"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewFactory()
+			v, command := config.Viperize(f.AddFlags)
+			require.NoError(t, command.ParseFlags(test.flags))
+			f.InitFromViper(v, zap.NewNop())
+
+			f.Builder = &mockProducerBuilder{t: t}
+			err := f.Initialize(metrics.NullFactory, zap.NewNop())
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, f.router.rules, 3)
+			assert.True(t, f.router.headers)
+		})
+	}
+}
+
+func TestSupportsHeaders(t *testing.T) {
+	assert.True(t, supportsHeaders(""))
+	assert.True(t, supportsHeaders("0.11.0.2"))
+	assert.True(t, supportsHeaders("2.8.0"))
+	assert.False(t, supportsHeaders("0.10.2.0"))
+	assert.False(t, supportsHeaders("invalid"))
+}
+
 func TestKafkaFactoryDoesNotLogPassword(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -47,6 +47,8 @@ const (
 	suffixBatchSize        = ".batch-size"
 	suffixBatchMinMessages = ".batch-min-messages"
 	suffixBatchMaxMessages = ".batch-max-messages"
+	suffixRoutingRules     = ".routing-rules"
+	suffixPartitionKey     = ".partition-key"
 
 	defaultBroker           = "127.0.0.1:9092"
 	defaultTopic            = "jaeger-spans"
@@ -58,6 +60,7 @@ const (
 	defaultBatchSize        = 0
 	defaultBatchMinMessages = 0
 	defaultBatchMaxMessages = 0
+	defaultPartitionKey     = PartitionKeyTraceID
 )
 
 var (
@@ -113,6 +116,9 @@ type Options struct {
 	Config   producer.Configuration `mapstructure:",squash"`
 	Topic    string                 `mapstructure:"topic"`
 	Encoding string                 `mapstructure:"encoding"`
+	// RoutingRules is the path of the file of rules routing spans to other topics than Topic.
+	RoutingRules string `mapstructure:"routing_rules"`
+	PartitionKey string `mapstructure:"partition_key"`
 }
 
 // AddFlags adds flags for Options
@@ -169,6 +175,18 @@ func (opt *Options) AddFlags(flagSet *flag.FlagSet) {
 		defaultEncoding,
 		fmt.Sprintf(`Encoding of spans ("%s" or "%s") sent to kafka.`, EncodingJSON, EncodingProto),
 	)
+	flagSet.String(
+		configPrefix+suffixRoutingRules,
+		"",
+		"The path of a YAML or JSON file of rules routing spans to other topics by service, tenant or tag. "+
+			"Spans not matching any rule are sent to the topic",
+	)
+	flagSet.String(
+		configPrefix+suffixPartitionKey,
+		defaultPartitionKey,
+		fmt.Sprintf(`The key partitioning the spans in the topics ("%s", "%s" or "%s<tag name>"). `+
+			`Spans without the tag are keyed by trace ID`, PartitionKeyTraceID, PartitionKeyService, PartitionKeyTagPrefix),
+	)
 
 	auth.AddFlags(configPrefix, flagSet)
 }
@@ -208,6 +226,8 @@ func (opt *Options) InitFromViper(v *viper.Viper) {
 	}
 	opt.Topic = v.GetString(configPrefix + suffixTopic)
 	opt.Encoding = v.GetString(configPrefix + suffixEncoding)
+	opt.RoutingRules = v.GetString(configPrefix + suffixRoutingRules)
+	opt.PartitionKey = v.GetString(configPrefix + suffixPartitionKey)
 }
 
 // stripWhiteSpace removes all whitespace characters from a string
@@ -42,6 +42,8 @@ func TestOptionsWithFlags(t *testing.T) {
 		"--kafka.producer.batch-size=128000",
 		"--kafka.producer.batch-min-messages=50",
 		"--kafka.producer.batch-max-messages=100",
+		"--kafka.producer.routing-rules=fixtures/routing_rules.yaml",
+		"--kafka.producer.partition-key=service",
 	})
 	opts.InitFromViper(v)
 
@@ -55,6 +57,8 @@ func TestOptionsWithFlags(t *testing.T) {
 	assert.Equal(t, time.Duration(1*time.Second), opts.Config.BatchLinger)
 	assert.Equal(t, 50, opts.Config.BatchMinMessages)
 	assert.Equal(t, 100, opts.Config.BatchMaxMessages)
+	assert.Equal(t, "fixtures/routing_rules.yaml", opts.RoutingRules)
+	assert.Equal(t, PartitionKeyService, opts.PartitionKey)
 }
 
 func TestFlagDefaults(t *testing.T) {
@@ -73,6 +77,8 @@ func TestFlagDefaults(t *testing.T) {
 	assert.Equal(t, time.Duration(0*time.Second), opts.Config.BatchLinger)
 	assert.Equal(t, 0, opts.Config.BatchMinMessages)
 	assert.Equal(t, 0, opts.Config.BatchMaxMessages)
+	assert.Empty(t, opts.RoutingRules)
+	assert.Equal(t, PartitionKeyTraceID, opts.PartitionKey)
 }
 
 func TestCompressionLevelDefaults(t *testing.T) {
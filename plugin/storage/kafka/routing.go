@@ -0,0 +1,239 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"gopkg.in/yaml.v3"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+const (
+	// PartitionKeyTraceID keys the messages by trace ID, so that the spans of a trace land in the same partition.
+	PartitionKeyTraceID = "trace-id"
+	// PartitionKeyService keys the messages by service name.
+	PartitionKeyService = "service"
+	// PartitionKeyTagPrefix keys the messages by the value of a span or process tag, e.g. "tag:customer".
+	PartitionKeyTagPrefix = "tag:"
+
+	// HeaderService is the record header carrying the service name of the span.
+	HeaderService = "jaeger-service"
+	// HeaderSpanFormat is the record header carrying the encoding of the span.
+	HeaderSpanFormat = "jaeger-span-format"
+	// HeaderTenant is the record header carrying the tenant of the span, it is not set for the default tenant.
+	HeaderTenant = "jaeger-tenant"
+)
+
+// RoutingRule routes the spans matching all of its criteria to a topic. Criteria are glob patterns,
+// where '*' matches any sequence of characters and '?' any single character, and a rule without
+// criteria matches all the spans.
+type RoutingRule struct {
+	Topic   string `yaml:"topic"`
+	Service string `yaml:"service"`
+	Tenant  string `yaml:"tenant"`
+	// Tags match the values of span tags, or of process tags if the span does not have the tag.
+	Tags map[string]string `yaml:"tags"`
+}
+
+type routingRules struct {
+	Rules []*RoutingRule `yaml:"rules"`
+}
+
+type compiledRoutingRule struct {
+	topic   string
+	service *regexp.Regexp
+	tenant  *regexp.Regexp
+	tags    map[string]*regexp.Regexp
+}
+
+// Router determines the topic, partition key and headers of the message of a span.
+type Router struct {
+	defaultTopic string
+	rules        []compiledRoutingRule
+	partitionKey func(span *model.Span) string
+	headers      bool
+	encoding     string
+}
+
+// RouterParams are the parameters of a Router
+type RouterParams struct {
+	// DefaultTopic receives the spans that do not match any rule.
+	DefaultTopic string
+	// Rules are evaluated in order, the first matching rule determines the topic of a span.
+	Rules []*RoutingRule
+	// PartitionKey is one of PartitionKeyTraceID (default), PartitionKeyService, or PartitionKeyTagPrefix
+	// followed by a tag name, in which case spans without the tag are keyed by trace ID.
+	PartitionKey string
+	// Headers adds the service name and the encoding of the span to the headers of the messages.
+	// The tenant of the span is always added, so that the ingester writes the span to the storage of the tenant.
+	Headers  bool
+	Encoding string
+}
+
+// NewRouter creates a new Router
+func NewRouter(params RouterParams) (*Router, error) {
+	r := &Router{
+		defaultTopic: params.DefaultTopic,
+		headers:      params.Headers,
+		encoding:     params.Encoding,
+	}
+	for i, rule := range params.Rules {
+		compiled, err := compileRoutingRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing rule %d: %w", i, err)
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	switch key := params.PartitionKey; {
+	case key == "" || key == PartitionKeyTraceID:
+		r.partitionKey = traceIDKey
+	case key == PartitionKeyService:
+		r.partitionKey = func(span *model.Span) string {
+			return span.Process.ServiceName
+		}
+	case strings.HasPrefix(key, PartitionKeyTagPrefix) && len(key) > len(PartitionKeyTagPrefix):
+		tag := strings.TrimPrefix(key, PartitionKeyTagPrefix)
+		r.partitionKey = func(span *model.Span) string {
+			if value, ok := spanTag(span, tag); ok {
+				return value
+			}
+			return traceIDKey(span)
+		}
+	default:
+		return nil, fmt.Errorf(`unknown partition key '%s', use one of "%s", "%s" or "%s<tag>"`,
+			key, PartitionKeyTraceID, PartitionKeyService, PartitionKeyTagPrefix)
+	}
+	return r, nil
+}
+
+// LoadRoutingRules reads the routing rules from a YAML or JSON file.
+func LoadRoutingRules(path string) ([]*RoutingRule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read routing rules: %w", err)
+	}
+	var rules routingRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse routing rules from %s: %w", path, err)
+	}
+	return rules.Rules, nil
+}
+
+func compileRoutingRule(rule *RoutingRule) (compiledRoutingRule, error) {
+	if rule == nil || rule.Topic == "" {
+		return compiledRoutingRule{}, fmt.Errorf("topic is required")
+	}
+	compiled := compiledRoutingRule{topic: rule.Topic}
+	if rule.Service != "" {
+		compiled.service = compileGlob(rule.Service)
+	}
+	if rule.Tenant != "" {
+		compiled.tenant = compileGlob(rule.Tenant)
+	}
+	for key, value := range rule.Tags {
+		if compiled.tags == nil {
+			compiled.tags = make(map[string]*regexp.Regexp, len(rule.Tags))
+		}
+		compiled.tags[key] = compileGlob(value)
+	}
+	return compiled, nil
+}
+
+// compileGlob compiles a glob pattern where '*' matches any sequence of characters
+// and '?' any single character.
+func compileGlob(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+func (r *compiledRoutingRule) matches(tenant string, span *model.Span) bool {
+	if r.service != nil && !r.service.MatchString(span.Process.ServiceName) {
+		return false
+	}
+	if r.tenant != nil && !r.tenant.MatchString(tenant) {
+		return false
+	}
+	for key, pattern := range r.tags {
+		value, ok := spanTag(span, key)
+		if !ok || !pattern.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Route returns the message of the span, whose value is the marshalled span.
+func (r *Router) Route(ctx context.Context, span *model.Span, value []byte) *sarama.ProducerMessage {
+	tenant := tenancy.GetTenant(ctx)
+	msg := &sarama.ProducerMessage{
+		Topic: r.topic(tenant, span),
+		Key:   sarama.StringEncoder(r.partitionKey(span)),
+		Value: sarama.ByteEncoder(value),
+	}
+	if r.headers {
+		msg.Headers = []sarama.RecordHeader{
+			{Key: []byte(HeaderService), Value: []byte(span.Process.ServiceName)},
+			{Key: []byte(HeaderSpanFormat), Value: []byte(r.encoding)},
+		}
+	}
+	if tenant != "" {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(HeaderTenant), Value: []byte(tenant)})
+	}
+	return msg
+}
+
+// TenantFromHeaders returns the tenant carried by the headers of a message, or the default tenant.
+func TenantFromHeaders(headers []*sarama.RecordHeader) string {
+	for _, h := range headers {
+		if h != nil && string(h.Key) == HeaderTenant {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (r *Router) topic(tenant string, span *model.Span) string {
+	for i := range r.rules {
+		if r.rules[i].matches(tenant, span) {
+			return r.rules[i].topic
+		}
+	}
+	return r.defaultTopic
+}
+
+func traceIDKey(span *model.Span) string {
+	return span.TraceID.String()
+}
+
+// spanTag returns the value of the span tag, or of the process tag if the span does not have it.
+func spanTag(span *model.Span, key string) (string, bool) {
+	if tag, ok := model.KeyValues(span.Tags).FindByKey(key); ok {
+		return tag.AsString(), true
+	}
+	if tag, ok := model.KeyValues(span.Process.Tags).FindByKey(key); ok {
+		return tag.AsString(), true
+	}
+	return "", false
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+)
+
+func routedSpan(service string, spanTags, processTags model.KeyValues) *model.Span {
+	return &model.Span{
+		TraceID: model.NewTraceID(1, 2),
+		Tags:    spanTags,
+		Process: model.NewProcess(service, processTags),
+	}
+}
+
+func TestLoadRoutingRules(t *testing.T) {
+	rules, err := LoadRoutingRules("fixtures/routing_rules.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []*RoutingRule{
+		{Topic: "jaeger-spans-checkout", Service: "checkout*"},
+		{Topic: "jaeger-spans-acme", Tenant: "acme"},
+		{Topic: "jaeger-spans-canary", Tags: map[string]string{"deployment": "canary-?"}},
+	}, rules)
+
+	_, err = LoadRoutingRules("fixtures/missing.yaml")
+	assert.Error(t, err)
+	_, err = LoadRoutingRules("routing.go")
+	assert.Error(t, err)
+}
+
+func TestRouterTopic(t *testing.T) {
+	rules, err := LoadRoutingRules("fixtures/routing_rules.yaml")
+	require.NoError(t, err)
+	router, err := NewRouter(RouterParams{DefaultTopic: "jaeger-spans", Rules: rules})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		tenant        string
+		span          *model.Span
+		expectedTopic string
+	}{
+		{
+			name:          "no rule matches",
+			span:          routedSpan("frontend", nil, nil),
+			expectedTopic: "jaeger-spans",
+		},
+		{
+			name:          "service",
+			span:          routedSpan("checkout-api", nil, nil),
+			expectedTopic: "jaeger-spans-checkout",
+		},
+		{
+			name:          "first matching rule",
+			tenant:        "acme",
+			span:          routedSpan("checkout", nil, nil),
+			expectedTopic: "jaeger-spans-checkout",
+		},
+		{
+			name:          "tenant",
+			tenant:        "acme",
+			span:          routedSpan("frontend", nil, nil),
+			expectedTopic: "jaeger-spans-acme",
+		},
+		{
+			name:          "span tag",
+			span:          routedSpan("frontend", model.KeyValues{model.String("deployment", "canary-1")}, nil),
+			expectedTopic: "jaeger-spans-canary",
+		},
+		{
+			name:          "process tag",
+			span:          routedSpan("frontend", nil, model.KeyValues{model.String("deployment", "canary-2")}),
+			expectedTopic: "jaeger-spans-canary",
+		},
+		{
+			name:          "tag not matching",
+			span:          routedSpan("frontend", model.KeyValues{model.String("deployment", "canary-10")}, nil),
+			expectedTopic: "jaeger-spans",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := tenancy.WithTenant(context.Background(), test.tenant)
+			msg := router.Route(ctx, test.span, []byte("span"))
+			assert.Equal(t, test.expectedTopic, msg.Topic)
+			assert.Equal(t, sarama.ByteEncoder("span"), msg.Value)
+			if test.tenant == "" {
+				assert.Nil(t, msg.Headers)
+			} else {
+				assert.Equal(t, []sarama.RecordHeader{{Key: []byte(HeaderTenant), Value: []byte(test.tenant)}}, msg.Headers)
+			}
+		})
+	}
+}
+
+func TestRouterPartitionKey(t *testing.T) {
+	span := routedSpan("frontend",
+		model.KeyValues{model.Int64("customer", 42)},
+		model.KeyValues{model.String("region", "eu")})
+	tests := []struct {
+		partitionKey string
+		expectedKey  string
+	}{
+		{partitionKey: "", expectedKey: span.TraceID.String()},
+		{partitionKey: PartitionKeyTraceID, expectedKey: span.TraceID.String()},
+		{partitionKey: PartitionKeyService, expectedKey: "frontend"},
+		{partitionKey: "tag:customer", expectedKey: "42"},
+		{partitionKey: "tag:region", expectedKey: "eu"},
+		{partitionKey: "tag:missing", expectedKey: span.TraceID.String()},
+	}
+	for _, test := range tests {
+		t.Run(test.partitionKey, func(t *testing.T) {
+			router, err := NewRouter(RouterParams{DefaultTopic: "jaeger-spans", PartitionKey: test.partitionKey})
+			require.NoError(t, err)
+			msg := router.Route(context.Background(), span, nil)
+			assert.Equal(t, sarama.StringEncoder(test.expectedKey), msg.Key)
+		})
+	}
+}
+
+func TestRouterHeaders(t *testing.T) {
+	router, err := NewRouter(RouterParams{DefaultTopic: "jaeger-spans", Headers: true, Encoding: EncodingJSON})
+	require.NoError(t, err)
+	msg := router.Route(context.Background(), routedSpan("frontend", nil, nil), nil)
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte(HeaderService), Value: []byte("frontend")},
+		{Key: []byte(HeaderSpanFormat), Value: []byte(EncodingJSON)},
+	}, msg.Headers)
+}
+
+func TestRouterTenantHeader(t *testing.T) {
+	router, err := NewRouter(RouterParams{DefaultTopic: "jaeger-spans", Headers: true, Encoding: EncodingProto})
+	require.NoError(t, err)
+	ctx := tenancy.WithTenant(context.Background(), "acme")
+	msg := router.Route(ctx, routedSpan("frontend", nil, nil), nil)
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte(HeaderService), Value: []byte("frontend")},
+		{Key: []byte(HeaderSpanFormat), Value: []byte(EncodingProto)},
+		{Key: []byte(HeaderTenant), Value: []byte("acme")},
+	}, msg.Headers)
+
+	headers := make([]*sarama.RecordHeader, len(msg.Headers))
+	for i := range msg.Headers {
+		headers[i] = &msg.Headers[i]
+	}
+	assert.Equal(t, "acme", TenantFromHeaders(headers))
+	assert.Equal(t, "", TenantFromHeaders(headers[:2]))
+	assert.Equal(t, "", TenantFromHeaders(nil))
+}
+
+func TestNewRouterErrors(t *testing.T) {
+	_, err := NewRouter(RouterParams{PartitionKey: "tag:"})
+	assert.EqualError(t, err, `unknown partition key 'tag:', use one of "trace-id", "service" or "tag:<tag>"`)
+
+	_, err = NewRouter(RouterParams{Rules: []*RoutingRule{{Service: "checkout"}}})
+	assert.EqualError(t, err, "invalid routing rule 0: topic is required")
+
+	_, err = NewRouter(RouterParams{Rules: []*RoutingRule{nil}})
+	assert.EqualError(t, err, "invalid routing rule 0: topic is required")
+}
@@ -34,16 +34,30 @@ type SpanWriter struct {
 	metrics    spanWriterMetrics
 	producer   sarama.AsyncProducer
 	marshaller Marshaller
-	topic      string
+	router     *Router
 }
 
-// NewSpanWriter initiates and returns a new kafka spanwriter
+// NewSpanWriter initiates and returns a new kafka spanwriter, which writes all the spans to the topic
+// keyed by trace ID
 func NewSpanWriter(
 	producer sarama.AsyncProducer,
 	marshaller Marshaller,
 	topic string,
 	factory metrics.Factory,
 	logger *zap.Logger,
+) *SpanWriter {
+	router := &Router{defaultTopic: topic, partitionKey: traceIDKey}
+	return NewRoutingSpanWriter(producer, marshaller, router, factory, logger)
+}
+
+// NewRoutingSpanWriter initiates and returns a new kafka spanwriter, which writes the spans
+// to the topics determined by the router
+func NewRoutingSpanWriter(
+	producer sarama.AsyncProducer,
+	marshaller Marshaller,
+	router *Router,
+	factory metrics.Factory,
+	logger *zap.Logger,
 ) *SpanWriter {
 	writeMetrics := spanWriterMetrics{
 		SpansWrittenSuccess: factory.Counter(metrics.Options{Name: "kafka_spans_written", Tags: map[string]string{"status": "success"}}),
@@ -67,7 +81,7 @@ func NewSpanWriter(
 	return &SpanWriter{
 		producer:   producer,
 		marshaller: marshaller,
-		topic:      topic,
+		router:     router,
 		metrics:    writeMetrics,
 	}
 }
@@ -82,11 +96,7 @@ func (w *SpanWriter) WriteSpan(ctx context.Context, span *model.Span) error {
 
 	// The AsyncProducer accepts messages on a channel and produces them asynchronously
 	// in the background as efficiently as possible
-	w.producer.Input() <- &sarama.ProducerMessage{
-		Topic: w.topic,
-		Key:   sarama.StringEncoder(span.TraceID.String()),
-		Value: sarama.ByteEncoder(spanBytes),
-	}
+	w.producer.Input() <- w.router.Route(ctx, span, spanBytes)
 	return nil
 }
 
@@ -17,21 +17,32 @@ package memory
 
 import (
 	"flag"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/uber/jaeger-lib/metrics"
 	"go.uber.org/zap"
 
+	"github.com/jaegertracing/jaeger/pkg/distributedlock"
+	"github.com/jaegertracing/jaeger/pkg/hostname"
+	"github.com/jaegertracing/jaeger/plugin/pkg/distributedlock/local"
 	"github.com/jaegertracing/jaeger/storage/dependencystore"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+	"github.com/jaegertracing/jaeger/storage/samplingstore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
+// samplingRetention is how long the adaptive sampling data is kept, well above the lookback of the adaptive processor.
+const samplingRetention = 24 * time.Hour
+
 // Factory implements storage.Factory and creates storage components backed by memory store.
 type Factory struct {
 	options        Options
 	metricsFactory metrics.Factory
 	logger         *zap.Logger
 	store          *Store
+	metricsStore   *SpanMetricsStore
+	samplingStore  *SamplingStore
 }
 
 // NewFactory creates a new Factory.
@@ -58,6 +69,8 @@ func (f *Factory) InitFromOptions(opts Options) {
 func (f *Factory) Initialize(metricsFactory metrics.Factory, logger *zap.Logger) error {
 	f.metricsFactory, f.logger = metricsFactory, logger
 	f.store = WithConfiguration(f.options.Configuration)
+	f.metricsStore = NewSpanMetricsStore()
+	f.samplingStore = NewSamplingStore(samplingRetention)
 	logger.Info("Memory storage initialized", zap.Any("configuration", f.store.config))
 	f.publishOpts()
 
@@ -79,6 +92,36 @@ func (f *Factory) CreateDependencyReader() (dependencystore.Reader, error) {
 	return f.store, nil
 }
 
+// ValidateTenantIsolation implements storage.TenantIsolationFactory, the store keeps the traces
+// of each tenant in a separate partition.
+func (f *Factory) ValidateTenantIsolation() error {
+	return nil
+}
+
+// CreateSpanMetricsWriter implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsWriter() (metricsstore.SpanMetricsWriter, error) {
+	return f.metricsStore, nil
+}
+
+// CreateSpanMetricsReader implements storage.SpanMetricsStoreFactory
+func (f *Factory) CreateSpanMetricsReader() (metricsstore.SpanMetricsReader, error) {
+	return f.metricsStore, nil
+}
+
+// CreateSamplingStore implements storage.SamplingStoreFactory
+func (f *Factory) CreateSamplingStore() (samplingstore.Store, error) {
+	return f.samplingStore, nil
+}
+
+// CreateLock implements storage.SamplingStoreFactory
+func (f *Factory) CreateLock() (distributedlock.Lock, error) {
+	hostname, err := hostname.AsIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return local.NewLock(hostname), nil
+}
+
 func (f *Factory) publishOpts() {
 	internalFactory := f.metricsFactory.Namespace(metrics.NSOptions{Name: "internal"})
 	internalFactory.Gauge(metrics.Options{Name: limit}).
@@ -29,7 +29,11 @@ import (
 	"github.com/jaegertracing/jaeger/storage"
 )
 
-var _ storage.Factory = new(Factory)
+var (
+	_ storage.Factory                 = new(Factory)
+	_ storage.SpanMetricsStoreFactory = new(Factory)
+	_ storage.SamplingStoreFactory    = new(Factory)
+)
 
 func TestMemoryStorageFactory(t *testing.T) {
 	f := NewFactory()
@@ -44,6 +48,18 @@ func TestMemoryStorageFactory(t *testing.T) {
 	depReader, err := f.CreateDependencyReader()
 	assert.NoError(t, err)
 	assert.Equal(t, f.store, depReader)
+	metricsWriter, err := f.CreateSpanMetricsWriter()
+	assert.NoError(t, err)
+	assert.Equal(t, f.metricsStore, metricsWriter)
+	metricsReader, err := f.CreateSpanMetricsReader()
+	assert.NoError(t, err)
+	assert.Equal(t, f.metricsStore, metricsReader)
+	samplingStore, err := f.CreateSamplingStore()
+	assert.NoError(t, err)
+	assert.Equal(t, f.samplingStore, samplingStore)
+	lock, err := f.CreateLock()
+	assert.NoError(t, err)
+	assert.NotNil(t, lock)
 }
 
 func TestWithConfiguration(t *testing.T) {
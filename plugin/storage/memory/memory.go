@@ -27,18 +27,28 @@ import (
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/model/adjuster"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/dependencystore"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
 )
 
 // Store is an in-memory store of traces
 type Store struct {
 	sync.RWMutex
+	// the traces of the default tenant
+	*tenant
+	// the traces of the other tenants, by tenant
+	perTenant map[string]*tenant
+	deduper   adjuster.Adjuster
+	config    config.Configuration
+}
+
+// tenant holds the traces of a single tenant, the limit of traces applies to each tenant
+type tenant struct {
 	ids        []*model.TraceID
 	traces     map[model.TraceID]*model.Trace
 	services   map[string]struct{}
 	operations map[string]map[spanstore.Operation]struct{}
-	deduper    adjuster.Adjuster
-	config     config.Configuration
 	index      int
 }
 
@@ -50,23 +60,59 @@ func NewStore() *Store {
 // WithConfiguration creates a new in memory storage based on the given configuration
 func WithConfiguration(configuration config.Configuration) *Store {
 	return &Store{
+		tenant:    newTenant(configuration),
+		perTenant: map[string]*tenant{},
+		deduper:   adjuster.SpanIDDeduper(),
+		config:    configuration,
+	}
+}
+
+func newTenant(configuration config.Configuration) *tenant {
+	return &tenant{
 		ids:        make([]*model.TraceID, configuration.MaxTraces),
 		traces:     map[model.TraceID]*model.Trace{},
 		services:   map[string]struct{}{},
 		operations: map[string]map[spanstore.Operation]struct{}{},
-		deduper:    adjuster.SpanIDDeduper(),
-		config:     configuration,
 	}
 }
 
+// getTenant returns the traces of the tenant carried by the context, or no traces for an unknown tenant.
+// The caller must hold the lock.
+func (m *Store) getTenant(ctx context.Context) *tenant {
+	name := tenancy.GetTenant(ctx)
+	if name == "" {
+		return m.tenant
+	}
+	if t, ok := m.perTenant[name]; ok {
+		return t
+	}
+	return &tenant{}
+}
+
+// getOrCreateTenant returns the traces of the tenant carried by the context, creating them if needed.
+// The caller must hold the write lock.
+func (m *Store) getOrCreateTenant(ctx context.Context) *tenant {
+	name := tenancy.GetTenant(ctx)
+	if name == "" {
+		return m.tenant
+	}
+	t, ok := m.perTenant[name]
+	if !ok {
+		t = newTenant(m.config)
+		m.perTenant[name] = t
+	}
+	return t
+}
+
 // GetDependencies returns dependencies between services
 func (m *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error) {
 	// deduper used below can modify the spans, so we take an exclusive lock
 	m.Lock()
 	defer m.Unlock()
+	t := m.getTenant(ctx)
 	deps := map[string]*model.DependencyLink{}
 	startTs := endTs.Add(-1 * lookback)
-	for _, orig := range m.traces {
+	for _, orig := range t.traces {
 		// SpanIDDeduper never returns an err
 		trace, _ := m.deduper.Adjust(orig)
 		if m.traceIsBetweenStartAndEnd(startTs, endTs, trace) {
@@ -97,6 +143,25 @@ func (m *Store) GetDependencies(ctx context.Context, endTs time.Time, lookback t
 	return retMe, nil
 }
 
+// GetOperationDependencies returns dependencies between operations of different services,
+// implements dependencystore.OperationReader
+func (m *Store) GetOperationDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.OperationDependencyLink, error) {
+	// deduper used below can modify the spans, so we take an exclusive lock
+	m.Lock()
+	defer m.Unlock()
+	t := m.getTenant(ctx)
+	links := dependencystore.NewOperationLinks()
+	startTs := endTs.Add(-1 * lookback)
+	for _, orig := range t.traces {
+		// SpanIDDeduper never returns an err
+		trace, _ := m.deduper.Adjust(orig)
+		if m.traceIsBetweenStartAndEnd(startTs, endTs, trace) {
+			links.AddTrace(trace)
+		}
+	}
+	return links.Links(), nil
+}
+
 func (m *Store) findSpan(trace *model.Trace, spanID model.SpanID) *model.Span {
 	for _, s := range trace.Spans {
 		if s.SpanID == spanID {
@@ -119,8 +184,9 @@ func (m *Store) traceIsBetweenStartAndEnd(startTs, endTs time.Time, trace *model
 func (m *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 	m.Lock()
 	defer m.Unlock()
-	if _, ok := m.operations[span.Process.ServiceName]; !ok {
-		m.operations[span.Process.ServiceName] = map[spanstore.Operation]struct{}{}
+	t := m.getOrCreateTenant(ctx)
+	if _, ok := t.operations[span.Process.ServiceName]; !ok {
+		t.operations[span.Process.ServiceName] = map[spanstore.Operation]struct{}{}
 	}
 
 	spanKind, _ := span.GetSpanKind()
@@ -129,31 +195,31 @@ func (m *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 		SpanKind: spanKind,
 	}
 
-	if _, ok := m.operations[span.Process.ServiceName][operation]; !ok {
-		m.operations[span.Process.ServiceName][operation] = struct{}{}
+	if _, ok := t.operations[span.Process.ServiceName][operation]; !ok {
+		t.operations[span.Process.ServiceName][operation] = struct{}{}
 	}
 
-	m.services[span.Process.ServiceName] = struct{}{}
-	if _, ok := m.traces[span.TraceID]; !ok {
-		m.traces[span.TraceID] = &model.Trace{}
+	t.services[span.Process.ServiceName] = struct{}{}
+	if _, ok := t.traces[span.TraceID]; !ok {
+		t.traces[span.TraceID] = &model.Trace{}
 
 		// if we have a limit, let's cleanup the oldest traces
 		if m.config.MaxTraces > 0 {
 			// we only have to deal with this slice if we have a limit
-			m.index = (m.index + 1) % m.config.MaxTraces
+			t.index = (t.index + 1) % m.config.MaxTraces
 
 			// do we have an item already on this position? if so, we are overriding it,
 			// and we need to remove from the map
-			if m.ids[m.index] != nil {
-				delete(m.traces, *m.ids[m.index])
+			if t.ids[t.index] != nil {
+				delete(t.traces, *t.ids[t.index])
 			}
 
 			// update the ring with the trace id
-			m.ids[m.index] = &span.TraceID
+			t.ids[t.index] = &span.TraceID
 		}
 
 	}
-	m.traces[span.TraceID].Spans = append(m.traces[span.TraceID].Spans, span)
+	t.traces[span.TraceID].Spans = append(t.traces[span.TraceID].Spans, span)
 
 	return nil
 }
@@ -162,7 +228,8 @@ func (m *Store) WriteSpan(ctx context.Context, span *model.Span) error {
 func (m *Store) GetTrace(ctx context.Context, traceID model.TraceID) (*model.Trace, error) {
 	m.RLock()
 	defer m.RUnlock()
-	trace, ok := m.traces[traceID]
+	t := m.getTenant(ctx)
+	trace, ok := t.traces[traceID]
 	if !ok {
 		return nil, spanstore.ErrTraceNotFound
 	}
@@ -181,12 +248,24 @@ func (m *Store) copyTrace(trace *model.Trace) (*model.Trace, error) {
 	return copied, err
 }
 
+func (m *Store) copySpan(span *model.Span) (*model.Span, error) {
+	bytes, err := proto.Marshal(span)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &model.Span{}
+	err = proto.Unmarshal(bytes, copied)
+	return copied, err
+}
+
 // GetServices returns a list of all known services
 func (m *Store) GetServices(ctx context.Context) ([]string, error) {
 	m.RLock()
 	defer m.RUnlock()
+	t := m.getTenant(ctx)
 	var retMe []string
-	for k := range m.services {
+	for k := range t.services {
 		retMe = append(retMe, k)
 	}
 	return retMe, nil
@@ -199,8 +278,9 @@ func (m *Store) GetOperations(
 ) ([]spanstore.Operation, error) {
 	m.RLock()
 	defer m.RUnlock()
+	t := m.getTenant(ctx)
 	var retMe []spanstore.Operation
-	if operations, ok := m.operations[query.ServiceName]; ok {
+	if operations, ok := t.operations[query.ServiceName]; ok {
 		for operation := range operations {
 			if query.SpanKind == "" || query.SpanKind == operation.SpanKind {
 				retMe = append(retMe, operation)
@@ -214,8 +294,9 @@ func (m *Store) GetOperations(
 func (m *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParameters) ([]*model.Trace, error) {
 	m.RLock()
 	defer m.RUnlock()
+	t := m.getTenant(ctx)
 	var retMe []*model.Trace
-	for _, trace := range m.traces {
+	for _, trace := range t.traces {
 		if m.validTrace(trace, query) {
 			copied, err := m.copyTrace(trace)
 			if err != nil {
@@ -238,6 +319,45 @@ func (m *Store) FindTraces(ctx context.Context, query *spanstore.TraceQueryParam
 	return retMe, nil
 }
 
+// FindSpans returns the spans satisfying the query parameters, sorted as requested
+func (m *Store) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	m.RLock()
+	defer m.RUnlock()
+	t := m.getTenant(ctx)
+	var matches []*model.Span
+	for _, trace := range t.traces {
+		for _, span := range trace.Spans {
+			if spanstore.MatchesSpan(span, query) {
+				matches = append(matches, span)
+			}
+		}
+	}
+	matches = spanstore.SortSpans(matches, query)
+	retMe := make([]*model.Span, 0, len(matches))
+	for _, span := range matches {
+		copied, err := m.copySpan(span)
+		if err != nil {
+			return nil, err
+		}
+		retMe = append(retMe, copied)
+	}
+	return retMe, nil
+}
+
+// GetSpanStats returns the statistics of the spans satisfying the query parameters
+func (m *Store) GetSpanStats(ctx context.Context, query *spanstore.SpanStatsQueryParameters) ([]spanstore.SpanStats, error) {
+	m.RLock()
+	defer m.RUnlock()
+	t := m.getTenant(ctx)
+	aggregator := spanstore.NewSpanStatsAggregator(query)
+	for _, trace := range t.traces {
+		for _, span := range trace.Spans {
+			aggregator.Add(span)
+		}
+	}
+	return aggregator.Stats(), nil
+}
+
 // FindTraceIDs is not implemented.
 func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryParameters) ([]model.TraceID, error) {
 	return nil, errors.New("not implemented")
@@ -246,7 +366,7 @@ func (m *Store) FindTraceIDs(ctx context.Context, query *spanstore.TraceQueryPar
 func (m *Store) validTrace(trace *model.Trace, query *spanstore.TraceQueryParameters) bool {
 	for _, span := range trace.Spans {
 		if m.validSpan(span, query) {
-			return true
+			return spanstore.MatchesQuery(trace, query)
 		}
 	}
 	return false
@@ -262,7 +382,8 @@ func findKeyValueMatch(kvs model.KeyValues, key, value string) (model.KeyValue,
 }
 
 func (m *Store) validSpan(span *model.Span, query *spanstore.TraceQueryParameters) bool {
-	if query.ServiceName != span.Process.ServiceName {
+	// the service may be omitted when it is constrained by the structured query
+	if (query.ServiceName != "" || query.Query == nil) && query.ServiceName != span.Process.ServiceName {
 		return false
 	}
 	if query.OperationName != "" && query.OperationName != span.OperationName {
@@ -21,10 +21,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jaegertracing/jaeger/model"
 	"github.com/jaegertracing/jaeger/pkg/memory/config"
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
 	"github.com/jaegertracing/jaeger/storage/spanstore"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 var traceID = model.NewTraceID(1, 2)
@@ -173,6 +176,29 @@ func TestStoreGetDependencies(t *testing.T) {
 	})
 }
 
+func TestStoreGetOperationDependencies(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		assert.NoError(t, store.WriteSpan(context.Background(), testingSpan))
+		assert.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+		assert.NoError(t, store.WriteSpan(context.Background(), childSpan2))
+		assert.NoError(t, store.WriteSpan(context.Background(), childSpan2_1))
+		links, err := store.GetOperationDependencies(context.Background(), time.Now(), time.Hour)
+		assert.NoError(t, err)
+		assert.Empty(t, links)
+
+		links, err = store.GetOperationDependencies(context.Background(), time.Unix(0, 0).Add(time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.OperationDependencyLink{{
+			Parent:          "serviceName",
+			ParentOperation: "operationName",
+			Child:           "childService",
+			ChildOperation:  "childOperationName",
+			CallCount:       2,
+			Latency:         model.LatencySummary{Min: 5 * time.Second, Max: 5 * time.Second, Total: 10 * time.Second},
+		}}, links)
+	})
+}
+
 func TestStoreWriteSpan(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		err := store.WriteSpan(context.Background(), testingSpan)
@@ -209,6 +235,46 @@ func TestStoreWithLimit(t *testing.T) {
 	assert.Equal(t, maxTraces, len(store.ids))
 }
 
+func TestStoreIsolatesTenants(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		ctxAcme := tenancy.WithTenant(context.Background(), "acme")
+		ctxOther := tenancy.WithTenant(context.Background(), "other")
+		assert.NoError(t, store.WriteSpan(ctxAcme, testingSpan))
+
+		trace, err := store.GetTrace(ctxAcme, testingSpan.TraceID)
+		assert.NoError(t, err)
+		assert.Len(t, trace.Spans, 1)
+		services, err := store.GetServices(ctxAcme)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testingSpan.Process.ServiceName}, services)
+
+		for _, ctx := range []context.Context{ctxOther, context.Background()} {
+			_, err := store.GetTrace(ctx, testingSpan.TraceID)
+			assert.Equal(t, spanstore.ErrTraceNotFound, err)
+			services, err := store.GetServices(ctx)
+			assert.NoError(t, err)
+			assert.Empty(t, services)
+			traces, err := store.FindTraces(ctx, &spanstore.TraceQueryParameters{ServiceName: testingSpan.Process.ServiceName})
+			assert.NoError(t, err)
+			assert.Empty(t, traces)
+		}
+	})
+}
+
+func TestStoreWithLimitPerTenant(t *testing.T) {
+	store := WithConfiguration(config.Configuration{MaxTraces: 1})
+	ctxAcme := tenancy.WithTenant(context.Background(), "acme")
+	span := &model.Span{
+		TraceID: model.NewTraceID(1, 2),
+		Process: &model.Process{ServiceName: "TestStoreWithLimitPerTenant"},
+	}
+	assert.NoError(t, store.WriteSpan(context.Background(), span))
+	assert.NoError(t, store.WriteSpan(ctxAcme, span))
+
+	assert.Len(t, store.traces, 1)
+	assert.Len(t, store.perTenant["acme"].traces, 1)
+}
+
 func TestStoreGetTraceSuccess(t *testing.T) {
 	withPopulatedMemoryStore(func(store *Store) {
 		trace, err := store.GetTrace(context.Background(), testingSpan.TraceID)
@@ -455,6 +521,44 @@ func TestStoreGetTrace(t *testing.T) {
 	}
 }
 
+func TestStoreFindTracesWithQuery(t *testing.T) {
+	tests := []struct {
+		serviceName  string
+		query        string
+		startTimeMin time.Time
+		traceFound   bool
+	}{
+		{query: "service=childService", traceFound: true},
+		{query: "service=serviceName AND span.span.kind=server", traceFound: true},
+		{query: "{service=serviceName AND span.span.kind=server}", traceFound: false},
+		{query: "{service=childService AND span.span.kind=server}", traceFound: true},
+		{query: "tag.logKey=logValue AND NOT operation^=child", traceFound: false},
+		{query: "duration>=5s", startTimeMin: time.Unix(500, 0), traceFound: false},
+		{serviceName: "childService", query: "operation=operationName", traceFound: true},
+		{serviceName: "childService", query: "operation=wrongOperationName", traceFound: false},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			withPopulatedMemoryStore(func(store *Store) {
+				require.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+				q, err := querylang.Parse(test.query)
+				require.NoError(t, err)
+				traces, err := store.FindTraces(context.Background(), &spanstore.TraceQueryParameters{
+					ServiceName:  test.serviceName,
+					StartTimeMin: test.startTimeMin,
+					Query:        q,
+				})
+				require.NoError(t, err)
+				if test.traceFound {
+					assert.Len(t, traces, 1)
+				} else {
+					assert.Empty(t, traces)
+				}
+			})
+		})
+	}
+}
+
 func TestStore_FindTraceIDs(t *testing.T) {
 	withMemoryStore(func(store *Store) {
 		traceIDs, err := store.FindTraceIDs(context.Background(), nil)
@@ -462,3 +566,75 @@ func TestStore_FindTraceIDs(t *testing.T) {
 		assert.EqualError(t, err, "not implemented")
 	})
 }
+
+func TestStoreFindSpans(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		slowSpan := &model.Span{
+			TraceID:       model.NewTraceID(1, 3),
+			SpanID:        model.NewSpanID(5),
+			Process:       model.NewProcess("serviceName", nil),
+			OperationName: "operationName",
+			Duration:      time.Second * 10,
+			StartTime:     time.Unix(200, 0).UTC(),
+		}
+		require.NoError(t, store.WriteSpan(context.Background(), slowSpan))
+		require.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+
+		spans, err := store.FindSpans(context.Background(), &spanstore.SpanQueryParameters{
+			ServiceName: "serviceName",
+			SortBy:      spanstore.SortByDuration,
+		})
+		require.NoError(t, err)
+		require.Len(t, spans, 2)
+		assert.Equal(t, slowSpan, spans[0])
+		assert.Equal(t, testingSpan.SpanID, spans[1].SpanID)
+
+		spans, err = store.FindSpans(context.Background(), &spanstore.SpanQueryParameters{
+			Tags:     map[string]string{"logKey": "logValue"},
+			NumSpans: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, spans, 1)
+		assert.Equal(t, traceID, spans[0].TraceID)
+
+		spans, err = store.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "unknown"})
+		require.NoError(t, err)
+		assert.Empty(t, spans)
+	})
+}
+
+func TestStoreFindSpansError(t *testing.T) {
+	withMemoryStore(func(store *Store) {
+		require.NoError(t, store.WriteSpan(context.Background(), nonSerializableSpan))
+		_, err := store.FindSpans(context.Background(), &spanstore.SpanQueryParameters{ServiceName: "naughtyService"})
+		assert.Error(t, err)
+	})
+}
+
+func TestStoreGetSpanStats(t *testing.T) {
+	withPopulatedMemoryStore(func(store *Store) {
+		require.NoError(t, store.WriteSpan(context.Background(), childSpan1))
+		require.NoError(t, store.WriteSpan(context.Background(), childSpan2))
+
+		stats, err := store.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			GroupByOperation: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "childService", stats[0].ServiceName)
+		assert.Equal(t, "childOperationName", stats[0].OperationName)
+		assert.EqualValues(t, 2, stats[0].Count)
+		assert.Equal(t, 5*time.Second, stats[0].P99)
+		assert.Equal(t, "serviceName", stats[1].ServiceName)
+		assert.EqualValues(t, 1, stats[1].Count)
+
+		stats, err = store.GetSpanStats(context.Background(), &spanstore.SpanStatsQueryParameters{
+			ServiceName: "childService",
+			GroupByTag:  "span.kind",
+		})
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "local", stats[0].TagValue)
+		assert.Equal(t, "server", stats[1].TagValue)
+	})
+}
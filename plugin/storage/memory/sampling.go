@@ -0,0 +1,149 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+type throughputBucket struct {
+	timestamp  time.Time
+	throughput []*model.Throughput
+}
+
+type probabilitiesBucket struct {
+	timestamp     time.Time
+	hostname      string
+	probabilities model.ServiceOperationProbabilities
+	data          model.ServiceOperationData
+}
+
+// SamplingStore is an in-memory store of the data used by adaptive sampling
+type SamplingStore struct {
+	sync.RWMutex
+	retention     time.Duration
+	throughput    []throughputBucket
+	probabilities []probabilitiesBucket
+	now           func() time.Time
+}
+
+// NewSamplingStore creates an in-memory sampling store which keeps the data for the given retention
+func NewSamplingStore(retention time.Duration) *SamplingStore {
+	return &SamplingStore{
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// InsertThroughput implements samplingstore.Store
+func (s *SamplingStore) InsertThroughput(throughput []*model.Throughput) error {
+	s.Lock()
+	defer s.Unlock()
+	now := s.now()
+	s.purge(now)
+	s.throughput = append(s.throughput, throughputBucket{timestamp: now, throughput: throughput})
+	return nil
+}
+
+// InsertProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) InsertProbabilitiesAndQPS(
+	hostname string,
+	probabilities model.ServiceOperationProbabilities,
+	qps model.ServiceOperationQPS,
+) error {
+	data := make(model.ServiceOperationData)
+	for svc, opProbabilities := range probabilities {
+		data[svc] = make(map[string]*model.ProbabilityAndQPS)
+		for op, probability := range opProbabilities {
+			data[svc][op] = &model.ProbabilityAndQPS{
+				Probability: probability,
+				QPS:         qps[svc][op],
+			}
+		}
+	}
+	s.Lock()
+	defer s.Unlock()
+	now := s.now()
+	s.purge(now)
+	s.probabilities = append(s.probabilities, probabilitiesBucket{
+		timestamp:     now,
+		hostname:      hostname,
+		probabilities: probabilities,
+		data:          data,
+	})
+	return nil
+}
+
+// GetThroughput implements samplingstore.Store
+func (s *SamplingStore) GetThroughput(start, end time.Time) ([]*model.Throughput, error) {
+	s.RLock()
+	defer s.RUnlock()
+	var throughput []*model.Throughput
+	for _, b := range s.throughput {
+		if inRange(b.timestamp, start, end) {
+			throughput = append(throughput, b.throughput...)
+		}
+	}
+	return throughput, nil
+}
+
+// GetProbabilitiesAndQPS implements samplingstore.Store
+func (s *SamplingStore) GetProbabilitiesAndQPS(start, end time.Time) (map[string][]model.ServiceOperationData, error) {
+	s.RLock()
+	defer s.RUnlock()
+	hostProbabilitiesAndQPS := make(map[string][]model.ServiceOperationData)
+	for _, b := range s.probabilities {
+		if inRange(b.timestamp, start, end) {
+			hostProbabilitiesAndQPS[b.hostname] = append(hostProbabilitiesAndQPS[b.hostname], b.data)
+		}
+	}
+	return hostProbabilitiesAndQPS, nil
+}
+
+// GetLatestProbabilities implements samplingstore.Store
+func (s *SamplingStore) GetLatestProbabilities() (model.ServiceOperationProbabilities, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if len(s.probabilities) == 0 {
+		return model.ServiceOperationProbabilities{}, nil
+	}
+	return s.probabilities[len(s.probabilities)-1].probabilities, nil
+}
+
+// purge drops the data older than the retention, the buckets are sorted by timestamp.
+func (s *SamplingStore) purge(now time.Time) {
+	if s.retention <= 0 {
+		return
+	}
+	cutoff := now.Add(-s.retention)
+	i := 0
+	for i < len(s.throughput) && s.throughput[i].timestamp.Before(cutoff) {
+		i++
+	}
+	s.throughput = s.throughput[i:]
+	i = 0
+	for i < len(s.probabilities) && s.probabilities[i].timestamp.Before(cutoff) {
+		i++
+	}
+	s.probabilities = s.probabilities[i:]
+}
+
+// inRange follows the semantics of the Cassandra sampling store: start is exclusive and end is inclusive.
+func inRange(ts, start, end time.Time) bool {
+	return ts.After(start) && !ts.After(end)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/model"
+)
+
+func TestSamplingStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := NewSamplingStore(time.Hour)
+	store.now = func() time.Time { return now }
+
+	latest, err := store.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	throughput := []*model.Throughput{{Service: "svc", Operation: "op", Count: 10, Probabilities: map[string]struct{}{"0.1": {}}}}
+	require.NoError(t, store.InsertThroughput(throughput))
+	require.NoError(t, store.InsertProbabilitiesAndQPS("host",
+		model.ServiceOperationProbabilities{"svc": {"op": 0.1}},
+		model.ServiceOperationQPS{"svc": {"op": 5}},
+	))
+
+	got, err := store.GetThroughput(now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	assert.Equal(t, throughput, got)
+	got, err = store.GetThroughput(now, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, got, "start is exclusive")
+
+	data, err := store.GetProbabilitiesAndQPS(now.Add(-time.Minute), now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]model.ServiceOperationData{
+		"host": {{"svc": {"op": {Probability: 0.1, QPS: 5}}}},
+	}, data)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, store.InsertProbabilitiesAndQPS("host",
+		model.ServiceOperationProbabilities{"svc": {"op": 0.2}},
+		model.ServiceOperationQPS{},
+	))
+	latest, err = store.GetLatestProbabilities()
+	require.NoError(t, err)
+	assert.Equal(t, model.ServiceOperationProbabilities{"svc": {"op": 0.2}}, latest)
+
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, store.InsertThroughput(nil))
+	assert.Len(t, store.throughput, 1)
+	assert.Empty(t, store.probabilities)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+type spanMetricsKey struct {
+	tenant        string
+	source        string
+	serviceName   string
+	operationName string
+	spanKind      string
+	timestamp     int64
+}
+
+// SpanMetricsStore is an in-memory store of the span metrics aggregated by the collector,
+// kept apart per tenant
+type SpanMetricsStore struct {
+	sync.RWMutex
+	metrics map[spanMetricsKey]*metricsstore.SpanMetrics
+}
+
+// NewSpanMetricsStore creates an unbounded in-memory span metrics store
+func NewSpanMetricsStore() *SpanMetricsStore {
+	return &SpanMetricsStore{
+		metrics: map[spanMetricsKey]*metricsstore.SpanMetrics{},
+	}
+}
+
+// WriteSpanMetrics implements metricsstore.SpanMetricsWriter
+func (s *SpanMetricsStore) WriteSpanMetrics(ctx context.Context, spanMetrics []*metricsstore.SpanMetrics) error {
+	tenant := tenancy.GetTenant(ctx)
+	s.Lock()
+	defer s.Unlock()
+	for _, m := range spanMetrics {
+		cp := *m
+		cp.LatencyBounds = append([]time.Duration(nil), m.LatencyBounds...)
+		cp.LatencyCounts = append([]int64(nil), m.LatencyCounts...)
+		s.metrics[spanMetricsKey{
+			tenant:        tenant,
+			source:        m.Source,
+			serviceName:   m.ServiceName,
+			operationName: m.OperationName,
+			spanKind:      m.SpanKind,
+			timestamp:     m.Timestamp.UnixNano(),
+		}] = &cp
+	}
+	return nil
+}
+
+// GetSpanMetrics implements metricsstore.SpanMetricsReader
+func (s *SpanMetricsStore) GetSpanMetrics(ctx context.Context, query *metricsstore.SpanMetricsQuery) ([]*metricsstore.SpanMetrics, error) {
+	tenant := tenancy.GetTenant(ctx)
+	s.RLock()
+	defer s.RUnlock()
+	var result []*metricsstore.SpanMetrics
+	for key, m := range s.metrics {
+		if key.tenant == tenant && query.Matches(m) {
+			result = append(result, m)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/pkg/tenancy"
+	"github.com/jaegertracing/jaeger/storage/metricsstore"
+)
+
+func TestSpanMetricsStore(t *testing.T) {
+	store := NewSpanMetricsStore()
+	ts := time.Unix(1000, 0)
+	newMetrics := func(timestamp time.Time, calls int64) *metricsstore.SpanMetrics {
+		return &metricsstore.SpanMetrics{
+			ServiceName:   "service",
+			OperationName: "operation",
+			SpanKind:      "SPAN_KIND_SERVER",
+			Timestamp:     timestamp,
+			Calls:         calls,
+			LatencyBounds: []time.Duration{time.Millisecond},
+			LatencyCounts: []int64{calls, 0},
+		}
+	}
+	require.NoError(t, store.WriteSpanMetrics(context.Background(), []*metricsstore.SpanMetrics{
+		newMetrics(ts.Add(time.Minute), 1),
+		newMetrics(ts, 2),
+		newMetrics(ts.Add(-time.Minute), 3),
+	}))
+	// writing the same bucket again replaces it
+	require.NoError(t, store.WriteSpanMetrics(context.Background(), []*metricsstore.SpanMetrics{newMetrics(ts, 5)}))
+
+	result, err := store.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(2*time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []*metricsstore.SpanMetrics{newMetrics(ts, 5), newMetrics(ts.Add(time.Minute), 1)}, result)
+
+	// the metrics of another collector are kept side by side
+	other := newMetrics(ts, 7)
+	other.Source = "collector-2"
+	require.NoError(t, store.WriteSpanMetrics(context.Background(), []*metricsstore.SpanMetrics{other}))
+	result, err = store.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = store.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: ts.Add(time.Hour), EndTime: ts.Add(2*time.Hour)})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+
+	// the metrics of each tenant are kept apart
+	acme := tenancy.WithTenant(context.Background(), "acme")
+	require.NoError(t, store.WriteSpanMetrics(acme, []*metricsstore.SpanMetrics{newMetrics(ts, 11)}))
+	result, err = store.GetSpanMetrics(acme, &metricsstore.SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(2*time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []*metricsstore.SpanMetrics{newMetrics(ts, 11)}, result)
+	result, err = store.GetSpanMetrics(context.Background(), &metricsstore.SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+}
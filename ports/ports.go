@@ -35,6 +35,10 @@ const (
 	CollectorGRPC = 14250
 	// CollectorHTTP is the default port for HTTP server for sending spans (e.g. /api/traces endpoint)
 	CollectorHTTP = 14268
+	// CollectorOTLPGRPC is the default port for receiving OTLP spans over gRPC
+	CollectorOTLPGRPC = 4317
+	// CollectorOTLPHTTP is the default port for receiving OTLP spans over HTTP
+	CollectorOTLPHTTP = 4318
 	// CollectorAdminHTTP is the default admin HTTP port (health check, metrics, etc.)
 	CollectorAdminHTTP = 14269
 
@@ -47,6 +51,11 @@ const (
 
 	// IngesterAdminHTTP is the default admin HTTP port (health check, metrics, etc.)
 	IngesterAdminHTTP = 14270
+
+	// RemoteStorageGRPC is the default port of the remote storage server exposing the gRPC storage plugin services
+	RemoteStorageGRPC = 17271
+	// RemoteStorageAdminHTTP is the default admin HTTP port (health check, metrics, etc.)
+	RemoteStorageAdminHTTP = 17270
 )
 
 // PortToHostPort converts the port into a host:port address string
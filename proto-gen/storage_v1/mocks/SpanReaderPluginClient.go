@@ -17,6 +17,36 @@ type SpanReaderPluginClient struct {
 	mock.Mock
 }
 
+// FindSpans provides a mock function with given fields: ctx, in, opts
+func (_m *SpanReaderPluginClient) FindSpans(ctx context.Context, in *storage_v1.FindSpansRequest, opts ...grpc.CallOption) (storage_v1.SpanReaderPlugin_FindSpansClient, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 storage_v1.SpanReaderPlugin_FindSpansClient
+	if rf, ok := ret.Get(0).(func(context.Context, *storage_v1.FindSpansRequest, ...grpc.CallOption) storage_v1.SpanReaderPlugin_FindSpansClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage_v1.SpanReaderPlugin_FindSpansClient)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *storage_v1.FindSpansRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindTraceIDs provides a mock function with given fields: ctx, in, opts
 func (_m *SpanReaderPluginClient) FindTraceIDs(ctx context.Context, in *storage_v1.FindTraceIDsRequest, opts ...grpc.CallOption) (*storage_v1.FindTraceIDsResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -14,6 +14,20 @@ type SpanReaderPluginServer struct {
 	mock.Mock
 }
 
+// FindSpans provides a mock function with given fields: _a0, _a1
+func (_m *SpanReaderPluginServer) FindSpans(_a0 *storage_v1.FindSpansRequest, _a1 storage_v1.SpanReaderPlugin_FindSpansServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*storage_v1.FindSpansRequest, storage_v1.SpanReaderPlugin_FindSpansServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindTraceIDs provides a mock function with given fields: _a0, _a1
 func (_m *SpanReaderPluginServer) FindTraceIDs(_a0 context.Context, _a1 *storage_v1.FindTraceIDsRequest) (*storage_v1.FindTraceIDsResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -76,3 +76,33 @@ func (_m *SpanWriterPluginClient) WriteSpan(ctx context.Context, in *storage_v1.
 
 	return r0, r1
 }
+
+// WriteSpans provides a mock function with given fields: ctx, opts
+func (_m *SpanWriterPluginClient) WriteSpans(ctx context.Context, opts ...grpc.CallOption) (storage_v1.SpanWriterPlugin_WriteSpansClient, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 storage_v1.SpanWriterPlugin_WriteSpansClient
+	if rf, ok := ret.Get(0).(func(context.Context, ...grpc.CallOption) storage_v1.SpanWriterPlugin_WriteSpansClient); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage_v1.SpanWriterPlugin_WriteSpansClient)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -17,6 +17,7 @@ package dependencystore
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jaegertracing/jaeger/model"
@@ -31,3 +32,24 @@ type Writer interface {
 type Reader interface {
 	GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.DependencyLink, error)
 }
+
+// ErrOperationDependenciesNotSupported is returned by GetOperationDependencies when the storage backend
+// does not support dependencies between operations.
+var ErrOperationDependenciesNotSupported = errors.New("dependencies between operations are not supported by the storage backend")
+
+// TenantWriter is an optional capability of a Writer that stores the dependencies of each tenant apart,
+// the tenant being carried by the context, see pkg/tenancy.
+type TenantWriter interface {
+	WriteTenantDependencies(ctx context.Context, ts time.Time, dependencies []model.DependencyLink) error
+}
+
+// OperationWriter is an optional capability of a Writer that stores dependencies between operations,
+// for the tenant carried by the context.
+type OperationWriter interface {
+	WriteOperationDependencies(ctx context.Context, ts time.Time, dependencies []model.OperationDependencyLink) error
+}
+
+// OperationReader is an optional capability of a Reader that loads dependencies between operations.
+type OperationReader interface {
+	GetOperationDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]model.OperationDependencyLink, error)
+}
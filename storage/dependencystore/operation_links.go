@@ -0,0 +1,107 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"sort"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type operationLinkKey struct {
+	parent          string
+	parentOperation string
+	child           string
+	childOperation  string
+}
+
+// OperationLinks accumulates the dependencies between operations of different services,
+// merging the statistics of the links between the same operations.
+type OperationLinks struct {
+	links map[operationLinkKey]*model.OperationDependencyLink
+}
+
+// NewOperationLinks creates an empty OperationLinks.
+func NewOperationLinks() *OperationLinks {
+	return &OperationLinks{links: make(map[operationLinkKey]*model.OperationDependencyLink)}
+}
+
+// AddTrace adds a link for every span whose parent span is in the trace and belongs to another service.
+func (l *OperationLinks) AddTrace(trace *model.Trace) {
+	spans := make(map[model.SpanID]*model.Span, len(trace.Spans))
+	for _, s := range trace.Spans {
+		spans[s.SpanID] = s
+	}
+	for _, s := range trace.Spans {
+		parent, ok := spans[s.ParentSpanID()]
+		if !ok || parent.Process.ServiceName == s.Process.ServiceName {
+			continue
+		}
+		link := l.get(operationLinkKey{
+			parent:          parent.Process.ServiceName,
+			parentOperation: parent.OperationName,
+			child:           s.Process.ServiceName,
+			childOperation:  s.OperationName,
+		})
+		link.Observe(s.Duration, spanstore.IsErrorSpan(s))
+	}
+}
+
+// Add merges the link with the link between the same operations, if any.
+func (l *OperationLinks) Add(link model.OperationDependencyLink) {
+	l.get(operationLinkKey{
+		parent:          link.Parent,
+		parentOperation: link.ParentOperation,
+		child:           link.Child,
+		childOperation:  link.ChildOperation,
+	}).Merge(link)
+}
+
+// Links returns the accumulated links, ordered by parent and child.
+func (l *OperationLinks) Links() []model.OperationDependencyLink {
+	links := make([]model.OperationDependencyLink, 0, len(l.links))
+	for _, link := range l.links {
+		links = append(links, *link)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.Parent != b.Parent {
+			return a.Parent < b.Parent
+		}
+		if a.ParentOperation != b.ParentOperation {
+			return a.ParentOperation < b.ParentOperation
+		}
+		if a.Child != b.Child {
+			return a.Child < b.Child
+		}
+		return a.ChildOperation < b.ChildOperation
+	})
+	return links
+}
+
+func (l *OperationLinks) get(key operationLinkKey) *model.OperationDependencyLink {
+	link, ok := l.links[key]
+	if !ok {
+		link = &model.OperationDependencyLink{
+			Parent:          key.parent,
+			ParentOperation: key.parentOperation,
+			Child:           key.child,
+			ChildOperation:  key.childOperation,
+		}
+		l.links[key] = link
+	}
+	return link
+}
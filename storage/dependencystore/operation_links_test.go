@@ -0,0 +1,82 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dependencystore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func newSpan(spanID, parentID model.SpanID, service, operation string, duration time.Duration, isError bool) *model.Span {
+	span := &model.Span{
+		SpanID:        spanID,
+		OperationName: operation,
+		Duration:      duration,
+		Process:       model.NewProcess(service, nil),
+	}
+	if parentID != 0 {
+		span.References = []model.SpanRef{model.NewChildOfRef(span.TraceID, parentID)}
+	}
+	if isError {
+		span.Tags = []model.KeyValue{model.Bool("error", true)}
+	}
+	return span
+}
+
+func TestOperationLinks(t *testing.T) {
+	links := NewOperationLinks()
+	links.AddTrace(&model.Trace{Spans: []*model.Span{
+		newSpan(1, 0, "frontend", "GET /", 10*time.Millisecond, false),
+		newSpan(2, 1, "frontend", "render", 5*time.Millisecond, false), // same service, not a dependency
+		newSpan(3, 1, "backend", "getUser", 2*time.Millisecond, false),
+		newSpan(4, 1, "backend", "getUser", 4*time.Millisecond, true),
+		newSpan(5, 3, "mysql", "select", time.Millisecond, false),
+		newSpan(6, 9, "mysql", "select", time.Millisecond, false), // parent not in the trace
+	}})
+	links.Add(model.OperationDependencyLink{
+		Parent:          "backend",
+		ParentOperation: "getUser",
+		Child:           "mysql",
+		ChildOperation:  "select",
+		CallCount:       1,
+		ErrorCount:      1,
+		Latency:         model.LatencySummary{Min: 3 * time.Millisecond, Max: 3 * time.Millisecond, Total: 3 * time.Millisecond},
+	})
+
+	assert.Equal(t, []model.OperationDependencyLink{
+		{
+			Parent:          "backend",
+			ParentOperation: "getUser",
+			Child:           "mysql",
+			ChildOperation:  "select",
+			CallCount:       2,
+			ErrorCount:      1,
+			Latency:         model.LatencySummary{Min: time.Millisecond, Max: 3 * time.Millisecond, Total: 4 * time.Millisecond},
+		},
+		{
+			Parent:          "frontend",
+			ParentOperation: "GET /",
+			Child:           "backend",
+			ChildOperation:  "getUser",
+			CallCount:       2,
+			ErrorCount:      1,
+			Latency:         model.LatencySummary{Min: 2 * time.Millisecond, Max: 4 * time.Millisecond, Total: 6 * time.Millisecond},
+		},
+	}, links.Links())
+}
@@ -58,6 +58,30 @@ type SamplingStoreFactory interface {
 	CreateSamplingStore() (samplingstore.Store, error)
 }
 
+// SpanMetricsStoreFactory is an additional interface that can be implemented by a factory to persist
+// the span metrics aggregated by the collector.
+type SpanMetricsStoreFactory interface {
+	// CreateSpanMetricsWriter creates a metricsstore.SpanMetricsWriter.
+	CreateSpanMetricsWriter() (metricsstore.SpanMetricsWriter, error)
+	// CreateSpanMetricsReader creates a metricsstore.SpanMetricsReader.
+	CreateSpanMetricsReader() (metricsstore.SpanMetricsReader, error)
+}
+
+// MetricsStoreFactory is an additional interface that can be implemented by a factory whose backend
+// also serves the metrics queries.
+type MetricsStoreFactory interface {
+	// CreateMetricsFactory creates a MetricsFactory reading the metrics from the same backend.
+	CreateMetricsFactory() (MetricsFactory, error)
+}
+
+// TenantIsolationFactory is an additional interface that can be implemented by a factory whose storage
+// keeps the data of each tenant apart, see pkg/tenancy. Multi-tenancy cannot be enabled with the other factories.
+type TenantIsolationFactory interface {
+	// ValidateTenantIsolation returns an error if the data of the tenants is not isolated with the
+	// configuration of the factory.
+	ValidateTenantIsolation() error
+}
+
 var (
 	// ErrArchiveStorageNotConfigured can be returned by the ArchiveFactory when the archive storage is not configured.
 	ErrArchiveStorageNotConfigured = errors.New("archive storage not configured")
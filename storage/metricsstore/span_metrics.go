@@ -0,0 +1,120 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsstore
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultLatencyBounds are the default upper bounds of the latency histogram of SpanMetrics.
+var DefaultLatencyBounds = []time.Duration{
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	6 * time.Millisecond,
+	8 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+	800 * time.Millisecond,
+	time.Second,
+	1400 * time.Millisecond,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+}
+
+// SpanMetrics holds the RED (rate, errors, duration) metrics aggregated from the spans
+// of a service, operation and span kind received within one time bucket.
+type SpanMetrics struct {
+	ServiceName   string
+	OperationName string
+	// SpanKind is the OTEL representation of the span kind, e.g. SPAN_KIND_SERVER.
+	SpanKind string
+	// Source identifies the collector that aggregated the metrics, so that the metrics
+	// of the same time bucket aggregated by several collectors are kept side by side.
+	Source string
+	// Timestamp is the start of the time bucket.
+	Timestamp time.Time
+	// Calls is the number of spans.
+	Calls int64
+	// Errors is the number of spans with the error tag.
+	Errors int64
+	// LatencyBounds are the upper bounds of the latency histogram buckets, in increasing order.
+	LatencyBounds []time.Duration
+	// LatencyCounts are the number of spans per latency histogram bucket.
+	// It has one more element than LatencyBounds, counting the spans above the last bound.
+	LatencyCounts []int64
+}
+
+// Observe records a span with the given duration in the metrics.
+func (m *SpanMetrics) Observe(duration time.Duration, isError bool) {
+	m.Calls++
+	if isError {
+		m.Errors++
+	}
+	i := 0
+	for i < len(m.LatencyBounds) && duration > m.LatencyBounds[i] {
+		i++
+	}
+	m.LatencyCounts[i]++
+}
+
+// SpanMetricsWriter persists span metrics aggregated by the collector.
+type SpanMetricsWriter interface {
+	// WriteSpanMetrics saves the metrics of time buckets. Writing the metrics of the same source, service,
+	// operation, span kind and time bucket again replaces the previous values.
+	WriteSpanMetrics(ctx context.Context, spanMetrics []*SpanMetrics) error
+}
+
+// SpanMetricsQuery selects the span metrics loaded by a SpanMetricsReader.
+type SpanMetricsQuery struct {
+	// StartTime and EndTime select the time buckets starting within [StartTime, EndTime).
+	StartTime time.Time
+	EndTime   time.Time
+	// ServiceNames select the metrics of these services, or of all the services if empty.
+	ServiceNames []string
+	// SpanKinds select the metrics of these span kinds, e.g. SPAN_KIND_SERVER, or of all the kinds if empty.
+	SpanKinds []string
+}
+
+// Matches returns true if the query selects the metrics.
+func (q *SpanMetricsQuery) Matches(m *SpanMetrics) bool {
+	if m.Timestamp.Before(q.StartTime) || !m.Timestamp.Before(q.EndTime) {
+		return false
+	}
+	return matchesAny(q.ServiceNames, m.ServiceName) && matchesAny(q.SpanKinds, m.SpanKind)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SpanMetricsReader loads span metrics aggregated by the collector.
+type SpanMetricsReader interface {
+	// GetSpanMetrics returns the metrics selected by the query, in the order of their time buckets.
+	GetSpanMetrics(ctx context.Context, query *SpanMetricsQuery) ([]*SpanMetrics, error)
+}
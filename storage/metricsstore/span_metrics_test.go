@@ -0,0 +1,55 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpanMetricsObserve(t *testing.T) {
+	m := &SpanMetrics{
+		LatencyBounds: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+		LatencyCounts: make([]int64, 3),
+	}
+	m.Observe(time.Millisecond, false)
+	m.Observe(5*time.Millisecond, true)
+	m.Observe(time.Second, false)
+	m.Observe(0, false)
+	assert.Equal(t, int64(4), m.Calls)
+	assert.Equal(t, int64(1), m.Errors)
+	assert.Equal(t, []int64{2, 1, 1}, m.LatencyCounts)
+}
+
+func TestSpanMetricsQueryMatches(t *testing.T) {
+	ts := time.Unix(1000, 0)
+	m := &SpanMetrics{ServiceName: "frontend", SpanKind: "SPAN_KIND_SERVER", Timestamp: ts}
+	tests := []struct {
+		query   SpanMetricsQuery
+		matches bool
+	}{
+		{query: SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute)}, matches: true},
+		{query: SpanMetricsQuery{StartTime: ts.Add(-time.Minute), EndTime: ts}, matches: false},
+		{query: SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute), ServiceNames: []string{"driver", "frontend"}}, matches: true},
+		{query: SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute), ServiceNames: []string{"driver"}}, matches: false},
+		{query: SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute), SpanKinds: []string{"SPAN_KIND_SERVER"}}, matches: true},
+		{query: SpanMetricsQuery{StartTime: ts, EndTime: ts.Add(time.Minute), SpanKinds: []string{"SPAN_KIND_CLIENT"}}, matches: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.matches, test.query.Matches(m), "%+v", test.query)
+	}
+}
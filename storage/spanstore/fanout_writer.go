@@ -0,0 +1,290 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uber/jaeger-lib/metrics"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/pkg/queue"
+)
+
+const (
+	// DefaultFanoutQueueSize is the default number of spans queued for a destination
+	DefaultFanoutQueueSize = 2000
+	// DefaultFanoutWorkers is the default number of workers writing the spans of a destination
+	DefaultFanoutWorkers = 10
+	// DefaultFanoutMaxRetries is the default number of times a failed write is retried
+	DefaultFanoutMaxRetries = 3
+	// DefaultFanoutRetryBackoff is the default delay before the first retry of a failed write
+	DefaultFanoutRetryBackoff = 100 * time.Millisecond
+	// DefaultFanoutMaxRetryBackoff is the default maximum delay between the retries of a failed write
+	DefaultFanoutMaxRetryBackoff = 5 * time.Second
+	// DefaultFanoutCloseTimeout is the default time given to the destinations to write their queued spans on close
+	DefaultFanoutCloseTimeout = 5 * time.Second
+)
+
+// ErrFanoutQueuesFull is returned by the FanoutWriter when the queues of all the destinations selected for a span are full.
+var ErrFanoutQueuesFull = errors.New("the queues of the span destinations are full")
+
+// FanoutDestination configures a destination of a FanoutWriter.
+type FanoutDestination struct {
+	// Name identifies the destination in the metrics and the logs
+	Name   string
+	Writer Writer
+	// Filter selects the spans written to the destination, all the spans if nil
+	Filter func(span *model.Span) bool
+	// QueueSize is the number of spans waiting to be written, beyond which the new spans are dropped
+	QueueSize int
+	// Workers is the number of concurrent writes to the destination
+	Workers int
+	// MaxRetries is the number of times a failed write is retried, the delay between the retries
+	// doubling from RetryBackoff up to MaxRetryBackoff
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// FanoutOptions contains the options for constructing a FanoutWriter.
+type FanoutOptions struct {
+	// CloseTimeout is the time given to the destinations to write their queued spans on close
+	CloseTimeout   time.Duration
+	MetricsFactory metrics.Factory
+	Logger         *zap.Logger
+}
+
+// FanoutDestinationStatus is the health of a destination of a FanoutWriter.
+type FanoutDestinationStatus struct {
+	Name string
+	// Healthy is false if the last span written to the destination failed after all the retries
+	Healthy bool
+	// LastError is the error of the last failed write, kept after the destination recovers
+	LastError   error
+	QueueLength int
+}
+
+type fanoutDestinationMetrics struct {
+	SpansWritten  metrics.Counter `metric:"spans_written"`
+	SpansFailed   metrics.Counter `metric:"spans_failed"`
+	SpansDropped  metrics.Counter `metric:"spans_dropped"`
+	SpansFiltered metrics.Counter `metric:"spans_filtered"`
+	Retries       metrics.Counter `metric:"retries"`
+	QueueLength   metrics.Gauge   `metric:"queue_length"`
+	Healthy       metrics.Gauge   `metric:"healthy"`
+}
+
+type fanoutItem struct {
+	ctx  context.Context
+	span *model.Span
+}
+
+// detachedContext keeps the values of a context, like the tenant, without its deadline and
+// cancellation, as the spans are written after WriteSpan returns.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+type fanoutDestination struct {
+	FanoutDestination
+	queue   *queue.BoundedQueue
+	metrics fanoutDestinationMetrics
+	healthy *atomic.Bool
+
+	lock      sync.Mutex
+	lastError error
+}
+
+// FanoutWriter is a span Writer that writes spans into several underlying span Writers asynchronously.
+// Each destination has its own queue, workers, retries and filter, so that a slow or failing destination
+// neither blocks nor fails the writes to the other ones.
+type FanoutWriter struct {
+	destinations []*fanoutDestination
+	closeTimeout time.Duration
+	logger       *zap.Logger
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewFanoutWriter creates a FanoutWriter and starts the workers of its destinations.
+func NewFanoutWriter(destinations []FanoutDestination, options FanoutOptions) *FanoutWriter {
+	if options.CloseTimeout <= 0 {
+		options.CloseTimeout = DefaultFanoutCloseTimeout
+	}
+	if options.MetricsFactory == nil {
+		options.MetricsFactory = metrics.NullFactory
+	}
+	if options.Logger == nil {
+		options.Logger = zap.NewNop()
+	}
+	w := &FanoutWriter{
+		closeTimeout: options.CloseTimeout,
+		logger:       options.Logger,
+		done:         make(chan struct{}),
+	}
+	for _, d := range destinations {
+		w.destinations = append(w.destinations, w.startDestination(d, options.MetricsFactory))
+	}
+	return w
+}
+
+func (w *FanoutWriter) startDestination(d FanoutDestination, metricsFactory metrics.Factory) *fanoutDestination {
+	if d.QueueSize <= 0 {
+		d.QueueSize = DefaultFanoutQueueSize
+	}
+	if d.Workers <= 0 {
+		d.Workers = DefaultFanoutWorkers
+	}
+	if d.MaxRetries < 0 {
+		d.MaxRetries = 0
+	}
+	if d.RetryBackoff <= 0 {
+		d.RetryBackoff = DefaultFanoutRetryBackoff
+	}
+	if d.MaxRetryBackoff < d.RetryBackoff {
+		d.MaxRetryBackoff = d.RetryBackoff
+	}
+	dest := &fanoutDestination{
+		FanoutDestination: d,
+		healthy:           atomic.NewBool(true),
+	}
+	metrics.Init(&dest.metrics, metricsFactory.Namespace(metrics.NSOptions{Tags: map[string]string{"destination": d.Name}}), nil)
+	dest.metrics.Healthy.Update(1)
+	dest.queue = queue.NewBoundedQueue(d.QueueSize, func(item interface{}) {
+		dest.metrics.SpansDropped.Inc(1)
+	})
+	dest.queue.StartConsumers(d.Workers, func(item interface{}) {
+		w.write(dest, item.(*fanoutItem))
+	})
+	dest.queue.StartLengthReporting(time.Second, dest.metrics.QueueLength)
+	return dest
+}
+
+// WriteSpan enqueues the span for each destination selecting it. It returns ErrFanoutQueuesFull
+// only if the span was dropped by all of them, the failures of the writes being reported by
+// the metrics and the status of the destinations.
+func (w *FanoutWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	item := &fanoutItem{ctx: detachedContext{ctx}, span: span}
+	selected, dropped := 0, 0
+	for _, d := range w.destinations {
+		if d.Filter != nil && !d.Filter(span) {
+			d.metrics.SpansFiltered.Inc(1)
+			continue
+		}
+		selected++
+		if !d.queue.Produce(item) {
+			dropped++
+		}
+	}
+	if selected > 0 && dropped == selected {
+		return ErrFanoutQueuesFull
+	}
+	return nil
+}
+
+func (w *FanoutWriter) write(d *fanoutDestination, item *fanoutItem) {
+	backoff := d.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := d.Writer.WriteSpan(item.ctx, item.span)
+		if err == nil {
+			d.metrics.SpansWritten.Inc(1)
+			d.setHealthy(nil)
+			return
+		}
+		if attempt == d.MaxRetries {
+			d.metrics.SpansFailed.Inc(1)
+			if d.setHealthy(err) {
+				w.logger.Warn("Span destination is unhealthy", zap.String("destination", d.Name), zap.Error(err))
+			}
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-w.done:
+			d.metrics.SpansFailed.Inc(1)
+			return
+		}
+		d.metrics.Retries.Inc(1)
+		if backoff *= 2; backoff > d.MaxRetryBackoff {
+			backoff = d.MaxRetryBackoff
+		}
+	}
+}
+
+// setHealthy records the result of a write, and returns true if it made the destination unhealthy.
+func (d *fanoutDestination) setHealthy(err error) bool {
+	if err == nil {
+		if !d.healthy.Swap(true) {
+			d.metrics.Healthy.Update(1)
+		}
+		return false
+	}
+	d.lock.Lock()
+	d.lastError = err
+	d.lock.Unlock()
+	if d.healthy.Swap(false) {
+		d.metrics.Healthy.Update(0)
+		return true
+	}
+	return false
+}
+
+// Status returns the health of the destinations.
+func (w *FanoutWriter) Status() []FanoutDestinationStatus {
+	status := make([]FanoutDestinationStatus, 0, len(w.destinations))
+	for _, d := range w.destinations {
+		d.lock.Lock()
+		lastError := d.lastError
+		d.lock.Unlock()
+		status = append(status, FanoutDestinationStatus{
+			Name:        d.Name,
+			Healthy:     d.healthy.Load(),
+			LastError:   lastError,
+			QueueLength: d.queue.Size(),
+		})
+	}
+	return status
+}
+
+// Close waits up to the close timeout for the queued spans to be written, then stops the workers.
+// The underlying writers are not closed, as they are owned by their storage factories.
+func (w *FanoutWriter) Close() error {
+	w.closeOnce.Do(func() {
+		deadline := time.Now().Add(w.closeTimeout)
+		for _, d := range w.destinations {
+			for d.queue.Size() > 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+		close(w.done)
+		for _, d := range w.destinations {
+			if size := d.queue.Size(); size > 0 {
+				w.logger.Warn("Discarding the spans not written to the destination", zap.String("destination", d.Name), zap.Int("spans", size))
+			}
+			d.queue.Stop()
+		}
+	})
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-lib/metrics/metricstest"
+
+	"github.com/jaegertracing/jaeger/model"
+	. "github.com/jaegertracing/jaeger/storage/spanstore"
+)
+
+type tenantKey struct{}
+
+// recordingWriter fails the first failures writes, and blocks the writes while blocked is locked.
+type recordingWriter struct {
+	blocked sync.RWMutex
+
+	lock     sync.Mutex
+	failures int
+	spans    []*model.Span
+	tenants  []interface{}
+}
+
+func (w *recordingWriter) WriteSpan(ctx context.Context, span *model.Span) error {
+	w.blocked.RLock()
+	defer w.blocked.RUnlock()
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errIWillAlwaysFail
+	}
+	w.spans = append(w.spans, span)
+	w.tenants = append(w.tenants, ctx.Value(tenantKey{}))
+	return nil
+}
+
+func (w *recordingWriter) written() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return len(w.spans)
+}
+
+func TestFanoutWriterWritesToAllDestinations(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	es, kafka := &recordingWriter{}, &recordingWriter{}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "es", Writer: es},
+		{Name: "kafka", Writer: kafka},
+	}, FanoutOptions{MetricsFactory: mf})
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, w.WriteSpan(ctx, &model.Span{SpanID: model.NewSpanID(uint64(i))}))
+	}
+	cancel()
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, 10, es.written())
+	assert.Equal(t, 10, kafka.written())
+	assert.Equal(t, "acme", es.tenants[0], "the context values are kept after the write is canceled")
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_written", Tags: map[string]string{"destination": "es"}, Value: 10},
+		metricstest.ExpectedMetric{Name: "spans_written", Tags: map[string]string{"destination": "kafka"}, Value: 10},
+	)
+}
+
+func TestFanoutWriterFilter(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	all, errorsOnly := &recordingWriter{}, &recordingWriter{}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "all", Writer: all},
+		{Name: "errors", Writer: errorsOnly, Filter: func(span *model.Span) bool {
+			_, ok := model.KeyValues(span.Tags).FindByKey("error")
+			return ok
+		}},
+	}, FanoutOptions{MetricsFactory: mf})
+
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{Tags: []model.KeyValue{model.Bool("error", true)}}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, 2, all.written())
+	assert.Equal(t, 1, errorsOnly.written())
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_filtered", Tags: map[string]string{"destination": "errors"}, Value: 1},
+		metricstest.ExpectedMetric{Name: "spans_written", Tags: map[string]string{"destination": "errors"}, Value: 1},
+	)
+}
+
+func TestFanoutWriterSlowDestination(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	slow, fast := &recordingWriter{}, &recordingWriter{}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "slow", Writer: slow, QueueSize: 1, Workers: 1},
+		{Name: "fast", Writer: fast},
+	}, FanoutOptions{MetricsFactory: mf, CloseTimeout: time.Millisecond})
+
+	slow.blocked.Lock()
+	for i := 0; i < 10; i++ {
+		require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	}
+	assert.Eventually(t, func() bool { return fast.written() == 10 }, time.Second, time.Millisecond,
+		"the blocked destination does not delay the other one")
+	assert.Equal(t, 0, slow.written())
+
+	counters, _ := mf.Snapshot()
+	assert.GreaterOrEqual(t, counters["spans_dropped|destination=slow"], int64(8))
+	assert.Zero(t, counters["spans_dropped|destination=fast"])
+
+	slow.blocked.Unlock()
+	require.NoError(t, w.Close())
+}
+
+func TestFanoutWriterQueuesFull(t *testing.T) {
+	blocked := &recordingWriter{}
+	blocked.blocked.Lock()
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "blocked", Writer: blocked, QueueSize: 1, Workers: 1},
+		{Name: "none", Writer: &recordingWriter{}, Filter: func(*model.Span) bool { return false }},
+	}, FanoutOptions{CloseTimeout: time.Millisecond})
+
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = w.WriteSpan(context.Background(), &model.Span{})
+	}
+	assert.Equal(t, ErrFanoutQueuesFull, err)
+
+	blocked.blocked.Unlock()
+	require.NoError(t, w.Close())
+}
+
+func TestFanoutWriterRetries(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	flaky := &recordingWriter{failures: 2}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "flaky", Writer: flaky, Workers: 1, MaxRetries: 2, RetryBackoff: time.Millisecond},
+	}, FanoutOptions{MetricsFactory: mf})
+
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, 1, flaky.written())
+	assert.True(t, w.Status()[0].Healthy)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "retries", Tags: map[string]string{"destination": "flaky"}, Value: 2},
+		metricstest.ExpectedMetric{Name: "spans_written", Tags: map[string]string{"destination": "flaky"}, Value: 1},
+	)
+}
+
+func TestFanoutWriterHealth(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	failing, ok := &recordingWriter{failures: 1}, &recordingWriter{}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "failing", Writer: failing, Workers: 1, MaxRetries: -1},
+		{Name: "ok", Writer: ok},
+	}, FanoutOptions{MetricsFactory: mf})
+	defer w.Close()
+
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	assert.Eventually(t, func() bool { return !w.Status()[0].Healthy }, time.Second, time.Millisecond)
+	status := w.Status()
+	assert.Equal(t, "failing", status[0].Name)
+	assert.True(t, errors.Is(status[0].LastError, errIWillAlwaysFail))
+	assert.True(t, status[1].Healthy)
+	assert.NoError(t, status[1].LastError)
+	mf.AssertGaugeMetrics(t,
+		metricstest.ExpectedMetric{Name: "healthy", Tags: map[string]string{"destination": "failing"}, Value: 0},
+		metricstest.ExpectedMetric{Name: "healthy", Tags: map[string]string{"destination": "ok"}, Value: 1},
+	)
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_failed", Tags: map[string]string{"destination": "failing"}, Value: 1},
+	)
+
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	assert.Eventually(t, func() bool { return w.Status()[0].Healthy }, time.Second, time.Millisecond,
+		"the destination recovers with the next successful write")
+	assert.Error(t, w.Status()[0].LastError)
+}
+
+func TestFanoutWriterCloseInterruptsRetries(t *testing.T) {
+	mf := metricstest.NewFactory(time.Hour)
+	failing := &recordingWriter{failures: 100}
+	w := NewFanoutWriter([]FanoutDestination{
+		{Name: "failing", Writer: failing, Workers: 1, MaxRetries: 10, RetryBackoff: time.Hour},
+	}, FanoutOptions{MetricsFactory: mf, CloseTimeout: time.Millisecond})
+
+	require.NoError(t, w.WriteSpan(context.Background(), &model.Span{}))
+	assert.Eventually(t, func() bool {
+		failing.lock.Lock()
+		defer failing.lock.Unlock()
+		return failing.failures < 100
+	}, time.Second, time.Millisecond, "the first write has failed")
+	start := time.Now()
+	require.NoError(t, w.Close())
+	assert.Less(t, time.Since(start), time.Minute)
+	require.NoError(t, w.Close(), "can be closed twice")
+	mf.AssertCounterMetrics(t,
+		metricstest.ExpectedMetric{Name: "spans_failed", Tags: map[string]string{"destination": "failing"}, Value: 1},
+	)
+}
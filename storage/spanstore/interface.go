@@ -21,11 +21,18 @@ import (
 	"time"
 
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
 )
 
 var (
 	// ErrTraceNotFound is returned by Reader's GetTrace if no data is found for given trace ID.
 	ErrTraceNotFound = errors.New("trace not found")
+
+	// ErrFindSpansNotSupported is returned by FindSpans when the storage backend does not support span search.
+	ErrFindSpansNotSupported = errors.New("span search is not supported by the storage backend")
+
+	// ErrSpanStatsNotSupported is returned by GetSpanStats when the storage backend does not support span statistics.
+	ErrSpanStatsNotSupported = errors.New("span statistics are not supported by the storage backend")
 )
 
 // Writer writes spans to storage.
@@ -51,7 +58,8 @@ type Reader interface {
 	// FindTraces returns all traces matching query parameters. There's currently
 	// an implementation-dependent abiguity whether all query filters (such as
 	// multiple tags) must apply to the same span within a trace, or can be satisfied
-	// by different spans.
+	// by different spans. The structured Query, when present, has explicit same-span
+	// and any-span semantics, see the querylang package.
 	//
 	// If no matching traces are found, the function returns (nil, nil).
 	FindTraces(ctx context.Context, query *TraceQueryParameters) ([]*model.Trace, error)
@@ -63,6 +71,52 @@ type Reader interface {
 	FindTraceIDs(ctx context.Context, query *TraceQueryParameters) ([]model.TraceID, error)
 }
 
+// SpanFinder is an optional capability of a Reader that searches individual spans
+// instead of traces. Readers that support it implement this interface.
+type SpanFinder interface {
+	// FindSpans returns the spans matching query parameters, sorted as requested.
+	// Unlike FindTraces, all filters apply to the returned span itself.
+	//
+	// If no matching spans are found, the function returns (nil, nil).
+	FindSpans(ctx context.Context, query *SpanQueryParameters) ([]*model.Span, error)
+}
+
+// SpanStatsReader is an optional capability of a Reader that aggregates the durations
+// and errors of the spans within a time window. Readers that support it implement this interface.
+type SpanStatsReader interface {
+	// GetSpanStats returns the statistics of the spans matching the query parameters,
+	// one per group of spans, sorted by service name, operation name and tag value.
+	//
+	// If no matching spans are found, the function returns (nil, nil).
+	GetSpanStats(ctx context.Context, query *SpanStatsQueryParameters) ([]SpanStats, error)
+}
+
+// SpanSortField is the field that the results of a span query are sorted by.
+type SpanSortField int
+
+const (
+	// SortByStartTime sorts spans by their start time.
+	SortByStartTime SpanSortField = iota
+	// SortByDuration sorts spans by their duration.
+	SortByDuration
+)
+
+// SpanQueryParameters contains parameters of a span query.
+type SpanQueryParameters struct {
+	ServiceName   string
+	OperationName string
+	Tags          map[string]string
+	StartTimeMin  time.Time
+	StartTimeMax  time.Time
+	DurationMin   time.Duration
+	DurationMax   time.Duration
+	NumSpans      int
+	SortBy        SpanSortField
+	// Ascending sorts the spans from the lowest value, e.g. the oldest or the fastest spans first.
+	// By default the spans are sorted from the highest value, e.g. the latest or the slowest spans first.
+	Ascending bool
+}
+
 // TraceQueryParameters contains parameters of a trace query.
 type TraceQueryParameters struct {
 	ServiceName   string
@@ -73,6 +127,9 @@ type TraceQueryParameters struct {
 	DurationMin   time.Duration
 	DurationMax   time.Duration
 	NumTraces     int
+	// Query is an optional structured query, combined with the other filters with an AND.
+	// It is evaluated against the spans that started within [StartTimeMin, StartTimeMax].
+	Query *querylang.Query
 }
 
 // OperationQueryParameters contains parameters of query operations, empty spanKind means get operations for all kinds of span.
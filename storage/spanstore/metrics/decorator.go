@@ -33,6 +33,8 @@ type ReadMetricsDecorator struct {
 	getTraceMetrics      *queryMetrics
 	getServicesMetrics   *queryMetrics
 	getOperationsMetrics *queryMetrics
+	findSpansMetrics     *queryMetrics
+	getSpanStatsMetrics  *queryMetrics
 }
 
 type queryMetrics struct {
@@ -63,6 +65,8 @@ func NewReadMetricsDecorator(spanReader spanstore.Reader, metricsFactory metrics
 		getTraceMetrics:      buildQueryMetrics("get_trace", metricsFactory),
 		getServicesMetrics:   buildQueryMetrics("get_services", metricsFactory),
 		getOperationsMetrics: buildQueryMetrics("get_operations", metricsFactory),
+		findSpansMetrics:     buildQueryMetrics("find_spans", metricsFactory),
+		getSpanStatsMetrics:  buildQueryMetrics("get_span_stats", metricsFactory),
 	}
 }
 
@@ -115,3 +119,29 @@ func (m *ReadMetricsDecorator) GetOperations(
 	m.getOperationsMetrics.emit(err, time.Since(start), len(retMe))
 	return retMe, err
 }
+
+// FindSpans implements spanstore.SpanFinder#FindSpans if the underlying reader supports it,
+// otherwise it returns spanstore.ErrFindSpansNotSupported.
+func (m *ReadMetricsDecorator) FindSpans(ctx context.Context, query *spanstore.SpanQueryParameters) ([]*model.Span, error) {
+	finder, ok := m.spanReader.(spanstore.SpanFinder)
+	if !ok {
+		return nil, spanstore.ErrFindSpansNotSupported
+	}
+	start := time.Now()
+	retMe, err := finder.FindSpans(ctx, query)
+	m.findSpansMetrics.emit(err, time.Since(start), len(retMe))
+	return retMe, err
+}
+
+// GetSpanStats implements spanstore.SpanStatsReader#GetSpanStats if the underlying reader supports it,
+// otherwise it returns spanstore.ErrSpanStatsNotSupported.
+func (m *ReadMetricsDecorator) GetSpanStats(ctx context.Context, query *spanstore.SpanStatsQueryParameters) ([]spanstore.SpanStats, error) {
+	statsReader, ok := m.spanReader.(spanstore.SpanStatsReader)
+	if !ok {
+		return nil, spanstore.ErrSpanStatsNotSupported
+	}
+	start := time.Now()
+	retMe, err := statsReader.GetSpanStats(ctx, query)
+	m.getSpanStatsMetrics.emit(err, time.Since(start), len(retMe))
+	return retMe, err
+}
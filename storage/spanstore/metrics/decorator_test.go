@@ -142,3 +142,61 @@ func TestFailingUnderlyingCalls(t *testing.T) {
 
 	checkExpectedExistingAndNonExistentCounters(t, counters, expecteds, gauges, existingKeys, nonExistentKeys)
 }
+
+type spanFinderReader struct {
+	*mocks.Reader
+	*mocks.SpanFinder
+}
+
+func TestFindSpans(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	finder := &mocks.SpanFinder{}
+	mrs := NewReadMetricsDecorator(spanFinderReader{Reader: &mocks.Reader{}, SpanFinder: finder}, mf)
+	query := &spanstore.SpanQueryParameters{ServiceName: "something"}
+	finder.On("FindSpans", context.Background(), query).Return([]*model.Span{{}}, nil).Once()
+	spans, err := mrs.FindSpans(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, spans, 1)
+	finder.On("FindSpans", context.Background(), query).Return(nil, errors.New("Failure")).Once()
+	_, err = mrs.FindSpans(context.Background(), query)
+	assert.EqualError(t, err, "Failure")
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=find_spans|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=find_spans|result=err"])
+}
+
+func TestFindSpansNotSupported(t *testing.T) {
+	mrs := NewReadMetricsDecorator(&mocks.Reader{}, metricstest.NewFactory(0))
+	_, err := mrs.FindSpans(context.Background(), &spanstore.SpanQueryParameters{})
+	assert.Equal(t, spanstore.ErrFindSpansNotSupported, err)
+}
+
+type spanStatsReader struct {
+	*mocks.Reader
+	*mocks.SpanStatsReader
+}
+
+func TestGetSpanStats(t *testing.T) {
+	mf := metricstest.NewFactory(0)
+
+	statsReader := &mocks.SpanStatsReader{}
+	mrs := NewReadMetricsDecorator(spanStatsReader{Reader: &mocks.Reader{}, SpanStatsReader: statsReader}, mf)
+	query := &spanstore.SpanStatsQueryParameters{ServiceName: "something"}
+	statsReader.On("GetSpanStats", context.Background(), query).Return([]spanstore.SpanStats{{}}, nil).Once()
+	stats, err := mrs.GetSpanStats(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	statsReader.On("GetSpanStats", context.Background(), query).Return(nil, errors.New("Failure")).Once()
+	_, err = mrs.GetSpanStats(context.Background(), query)
+	assert.EqualError(t, err, "Failure")
+
+	counters, _ := mf.Snapshot()
+	assert.EqualValues(t, 1, counters["requests|operation=get_span_stats|result=ok"])
+	assert.EqualValues(t, 1, counters["requests|operation=get_span_stats|result=err"])
+
+	mrs = NewReadMetricsDecorator(&mocks.Reader{}, mf)
+	_, err = mrs.GetSpanStats(context.Background(), query)
+	assert.Equal(t, spanstore.ErrSpanStatsNotSupported, err)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// PushDownQuery returns the parameters to search the indexes of a backend that cannot evaluate
+// the structured query natively. The returned parameters have no Query; the conditions implied by it
+// are moved to the other filters when those are not already set, and NumTraces is multiplied by
+// oversampling to leave room for the traces discarded by FilterTraces.
+func PushDownQuery(query *TraceQueryParameters, oversampling int) *TraceQueryParameters {
+	pushed := *query
+	if query.Query == nil {
+		return &pushed
+	}
+	pushed.Query = nil
+	pushed.NumTraces *= oversampling
+	// The other filters must be satisfied by a single span, so the query conditions cannot be
+	// added to them without changing the semantics.
+	if query.ServiceName != "" || query.OperationName != "" || len(query.Tags) > 0 ||
+		query.DurationMin != 0 || query.DurationMax != 0 {
+		return &pushed
+	}
+	pushdown := query.Query.Pushdown()
+	pushed.ServiceName = pushdown.ServiceName
+	pushed.OperationName = pushdown.OperationName
+	pushed.Tags = pushdown.Tags
+	pushed.DurationMin = pushdown.DurationMin
+	pushed.DurationMax = pushdown.DurationMax
+	return &pushed
+}
+
+// MatchesQuery returns true if the trace satisfies the structured query of the parameters,
+// evaluated against the spans that started within [StartTimeMin, StartTimeMax].
+func MatchesQuery(trace *model.Trace, query *TraceQueryParameters) bool {
+	if query.Query == nil {
+		return true
+	}
+	spans := make([]*model.Span, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
+			continue
+		}
+		if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
+			continue
+		}
+		spans = append(spans, span)
+	}
+	return query.Query.MatchSpans(spans)
+}
+
+// FilterTraces returns the traces satisfying the structured query of the parameters, up to NumTraces of them.
+func FilterTraces(traces []*model.Trace, query *TraceQueryParameters) []*model.Trace {
+	var matching []*model.Trace
+	for _, trace := range traces {
+		if query.NumTraces > 0 && len(matching) >= query.NumTraces {
+			break
+		}
+		if MatchesQuery(trace, query) {
+			matching = append(matching, trace)
+		}
+	}
+	return matching
+}
+
+// TraceIDs returns the IDs of the traces, for the backends that implement FindTraceIDs on top of FindTraces.
+func TraceIDs(traces []*model.Trace) []model.TraceID {
+	traceIDs := make([]model.TraceID, 0, len(traces))
+	for _, trace := range traces {
+		if len(trace.Spans) > 0 {
+			traceIDs = append(traceIDs, trace.Spans[0].TraceID)
+		}
+	}
+	return traceIDs
+}
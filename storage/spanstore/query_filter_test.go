@@ -0,0 +1,97 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/storage/spanstore/querylang"
+)
+
+func mustParse(t *testing.T, text string) *querylang.Query {
+	q, err := querylang.Parse(text)
+	require.NoError(t, err)
+	return q
+}
+
+func TestPushDownQuery(t *testing.T) {
+	q := mustParse(t, "{service=foo AND operation=bar} AND tag.error=true")
+	tests := []struct {
+		name     string
+		query    TraceQueryParameters
+		expected TraceQueryParameters
+	}{
+		{
+			name:     "no query",
+			query:    TraceQueryParameters{ServiceName: "foo", NumTraces: 10},
+			expected: TraceQueryParameters{ServiceName: "foo", NumTraces: 10},
+		},
+		{
+			name:     "query",
+			query:    TraceQueryParameters{Query: q, NumTraces: 10},
+			expected: TraceQueryParameters{ServiceName: "foo", OperationName: "bar", NumTraces: 30},
+		},
+		{
+			name:     "query with other filters",
+			query:    TraceQueryParameters{Query: q, ServiceName: "baz", NumTraces: 10},
+			expected: TraceQueryParameters{ServiceName: "baz", NumTraces: 30},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, &test.expected, PushDownQuery(&test.query, 3))
+		})
+	}
+}
+
+func TestFilterTraces(t *testing.T) {
+	start := time.Unix(1000, 0)
+	trace := func(service string, offset time.Duration) *model.Trace {
+		return &model.Trace{Spans: []*model.Span{
+			{StartTime: start.Add(offset), Process: model.NewProcess(service, nil)},
+		}}
+	}
+	traces := []*model.Trace{
+		trace("foo", 0),
+		trace("bar", 0),
+		trace("foo", time.Hour),
+		trace("foo", time.Minute),
+	}
+	query := &TraceQueryParameters{
+		Query:        mustParse(t, "service=foo"),
+		StartTimeMin: start,
+		StartTimeMax: start.Add(time.Minute),
+	}
+	assert.Equal(t, []*model.Trace{traces[0], traces[3]}, FilterTraces(traces, query))
+
+	query.NumTraces = 1
+	assert.Equal(t, []*model.Trace{traces[0]}, FilterTraces(traces, query))
+
+	assert.Equal(t, traces, FilterTraces(traces, &TraceQueryParameters{}))
+}
+
+func TestTraceIDs(t *testing.T) {
+	traces := []*model.Trace{
+		{Spans: []*model.Span{{TraceID: model.NewTraceID(0, 1)}, {TraceID: model.NewTraceID(0, 1)}}},
+		{},
+		{Spans: []*model.Span{{TraceID: model.NewTraceID(0, 2)}}},
+	}
+	assert.Equal(t, []model.TraceID{model.NewTraceID(0, 1), model.NewTraceID(0, 2)}, TraceIDs(traces))
+}
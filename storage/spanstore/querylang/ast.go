@@ -0,0 +1,178 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// FieldKind identifies which part of a span a comparison applies to.
+type FieldKind int
+
+const (
+	// ServiceField is the service name of the span process
+	ServiceField FieldKind = iota
+	// OperationField is the operation name of the span
+	OperationField
+	// DurationField is the duration of the span
+	DurationField
+	// SpanTagField is a tag of the span
+	SpanTagField
+	// ProcessTagField is a tag of the span process
+	ProcessTagField
+	// TagField is a span tag, process tag or log field
+	TagField
+)
+
+// Operator is a comparison operator.
+type Operator string
+
+const (
+	// Equal matches values equal to the operand
+	Equal Operator = "="
+	// NotEqual matches values not equal to the operand
+	NotEqual Operator = "!="
+	// Greater matches values greater than the operand
+	Greater Operator = ">"
+	// GreaterOrEqual matches values greater than or equal to the operand
+	GreaterOrEqual Operator = ">="
+	// Less matches values less than the operand
+	Less Operator = "<"
+	// LessOrEqual matches values less than or equal to the operand
+	LessOrEqual Operator = "<="
+	// Regex matches values matching the regular expression
+	Regex Operator = "=~"
+	// NotRegex matches values not matching the regular expression
+	NotRegex Operator = "!~"
+	// Prefix matches values starting with the operand
+	Prefix Operator = "^="
+)
+
+// Expression is a node of the query syntax tree.
+type Expression interface {
+	// String returns the expression in the query syntax
+	String() string
+
+	// matchTrace evaluates the expression with the any-span semantics
+	matchTrace(spans []*model.Span) bool
+	// matchSpan evaluates the expression against a single span
+	matchSpan(span *model.Span) bool
+}
+
+// And is satisfied when all of its operands are.
+type And struct {
+	Operands []Expression
+}
+
+// Or is satisfied when any of its operands is.
+type Or struct {
+	Operands []Expression
+}
+
+// Not negates its operand.
+type Not struct {
+	Operand Expression
+}
+
+// SameSpan requires its operand to be satisfied by a single span.
+type SameSpan struct {
+	Operand Expression
+}
+
+// Field is the left-hand side of a comparison.
+type Field struct {
+	Kind FieldKind
+	// Key is the tag key, for the tag fields
+	Key string
+}
+
+// Comparison compares a field of a span with a literal value.
+type Comparison struct {
+	Field    Field
+	Operator Operator
+	Value    string
+
+	number   float64
+	duration time.Duration
+	regex    *regexp.Regexp
+}
+
+// Query is a parsed trace query.
+type Query struct {
+	Root Expression
+}
+
+// String returns the query in its canonical form, which parses back to the same query.
+func (q *Query) String() string {
+	return q.Root.String()
+}
+
+func (e *And) String() string {
+	return joinOperands(e.Operands, " AND ")
+}
+
+func (e *Or) String() string {
+	return joinOperands(e.Operands, " OR ")
+}
+
+func (e *Not) String() string {
+	return "NOT " + operandString(e.Operand)
+}
+
+func (e *SameSpan) String() string {
+	return "{" + e.Operand.String() + "}"
+}
+
+func (e *Comparison) String() string {
+	return e.Field.String() + string(e.Operator) + quoteValue(e.Value)
+}
+
+func (f Field) String() string {
+	switch f.Kind {
+	case ServiceField:
+		return "service"
+	case OperationField:
+		return "operation"
+	case DurationField:
+		return "duration"
+	case SpanTagField:
+		return "span." + f.Key
+	case ProcessTagField:
+		return "process." + f.Key
+	default:
+		return "tag." + f.Key
+	}
+}
+
+func joinOperands(operands []Expression, sep string) string {
+	parts := make([]string, len(operands))
+	for i, operand := range operands {
+		parts[i] = operandString(operand)
+	}
+	return strings.Join(parts, sep)
+}
+
+// operandString wraps the boolean operators in parenthesis, so that the precedence is explicit.
+func operandString(e Expression) string {
+	switch e.(type) {
+	case *And, *Or:
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querylang implements the structured trace search language, e.g.
+//
+//	service=frontend AND span.http.status_code>=500 AND (tag.error=true OR duration>1s)
+//
+// Syntax:
+//
+//	query      ::= or
+//	or         ::= and { 'OR' and }
+//	and        ::= unary { 'AND' unary }
+//	unary      ::= 'NOT' unary | '(' or ')' | '{' or '}' | comparison
+//	comparison ::= field operator value
+//	field      ::= 'service' | 'operation' | 'duration' | 'span.' key | 'process.' key | 'tag.' key
+//	operator   ::= '=' | '!=' | '>' | '>=' | '<' | '<=' | '=~' | '!~' | '^='
+//	value      ::= word | '"' quoted string '"'
+//
+// Queries are limited to MaxQueryLength bytes and MaxDepth nested NOT operators, parentheses and braces.
+// Keywords are case-insensitive. The span.<key> field matches the span tags, process.<key> the process
+// tags, and tag.<key> any of the span tags, process tags or log fields. Durations are written as Go
+// durations (e.g. 1.5s). Numeric operators compare tag values as numbers, =~ and !~ match the whole
+// value against a regular expression, and ^= matches a prefix.
+//
+// Each comparison is satisfied by a trace when any of its spans satisfies it, so the conditions
+// of `service=a AND operation=b` may be satisfied by different spans. An expression in braces,
+// e.g. `{service=a AND operation=b}`, must be satisfied by a single span. NOT negates at the level
+// it appears: `NOT tag.error=true` selects traces without error spans, while `{NOT tag.error=true}`
+// selects traces with at least one span without the error tag. != and !~ are the negations
+// of = and =~ for a single span, so they also match spans that do not have the tag at all.
+package querylang
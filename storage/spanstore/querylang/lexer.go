@@ -0,0 +1,137 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBrace
+	tokenRightBrace
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.typ {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return strconv.Quote(t.value)
+	}
+	return fmt.Sprintf("'%s'", t.value)
+}
+
+// isKeyword returns true if the token is an unquoted keyword, e.g. AND.
+func (t token) isKeyword(keyword string) bool {
+	return t.typ == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+// operators are sorted so that the longest operators are tried first
+var operators = []Operator{GreaterOrEqual, LessOrEqual, NotEqual, Regex, NotRegex, Prefix, Equal, Greater, Less}
+
+var delimiters = map[rune]tokenType{
+	'(': tokenLeftParen,
+	')': tokenRightParen,
+	'{': tokenLeftBrace,
+	'}': tokenRightBrace,
+}
+
+const specialChars = `(){}=!<>~^"`
+
+func isWordChar(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(specialChars, r)
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(text); {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		typ, isDelimiter := delimiters[r]
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case isDelimiter:
+			tokens = append(tokens, token{typ: typ, value: string(r), pos: pos})
+			pos++
+		case r == '"':
+			end := pos + 1
+			for ; end < len(text) && text[end] != '"'; end++ {
+				if text[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("unterminated string at position %d", pos)
+			}
+			value, err := strconv.Unquote(text[pos : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d: %w", pos, err)
+			}
+			tokens = append(tokens, token{typ: tokenString, value: value, pos: pos})
+			pos = end + 1
+		default:
+			if op, ok := matchOperator(text[pos:]); ok {
+				tokens = append(tokens, token{typ: tokenOperator, value: string(op), pos: pos})
+				pos += len(op)
+				continue
+			}
+			end := strings.IndexFunc(text[pos:], func(r rune) bool { return !isWordChar(r) })
+			if end == 0 {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, pos)
+			}
+			if end < 0 {
+				end = len(text) - pos
+			}
+			tokens = append(tokens, token{typ: tokenWord, value: text[pos : pos+end], pos: pos})
+			pos += end
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(text)}), nil
+}
+
+func matchOperator(text string) (Operator, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(text, string(op)) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// quoteValue returns the value as it must be written in a query.
+func quoteValue(value string) string {
+	if value == "" || isReserved(value) || strings.IndexFunc(value, func(r rune) bool { return !isWordChar(r) }) >= 0 {
+		return strconv.Quote(value)
+	}
+	return value
+}
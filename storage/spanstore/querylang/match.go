@@ -0,0 +1,193 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// Matches returns true if the trace satisfies the query.
+func (q *Query) Matches(trace *model.Trace) bool {
+	return q.MatchSpans(trace.Spans)
+}
+
+// MatchSpans returns true if the spans, considered as a single trace, satisfy the query.
+func (q *Query) MatchSpans(spans []*model.Span) bool {
+	return q.Root.matchTrace(spans)
+}
+
+func (e *And) matchTrace(spans []*model.Span) bool {
+	for _, operand := range e.Operands {
+		if !operand.matchTrace(spans) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *And) matchSpan(span *model.Span) bool {
+	for _, operand := range e.Operands {
+		if !operand.matchSpan(span) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Or) matchTrace(spans []*model.Span) bool {
+	for _, operand := range e.Operands {
+		if operand.matchTrace(spans) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Or) matchSpan(span *model.Span) bool {
+	for _, operand := range e.Operands {
+		if operand.matchSpan(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Not) matchTrace(spans []*model.Span) bool {
+	return !e.Operand.matchTrace(spans)
+}
+
+func (e *Not) matchSpan(span *model.Span) bool {
+	return !e.Operand.matchSpan(span)
+}
+
+func (e *SameSpan) matchTrace(spans []*model.Span) bool {
+	return anySpan(spans, e.Operand.matchSpan)
+}
+
+func (e *SameSpan) matchSpan(span *model.Span) bool {
+	return e.Operand.matchSpan(span)
+}
+
+func (e *Comparison) matchTrace(spans []*model.Span) bool {
+	return anySpan(spans, e.matchSpan)
+}
+
+func anySpan(spans []*model.Span, match func(*model.Span) bool) bool {
+	for _, span := range spans {
+		if match(span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Comparison) matchSpan(span *model.Span) bool {
+	// the negative operators are evaluated as the negation of the positive ones,
+	// so that they also match spans without the tag
+	switch e.Operator {
+	case NotEqual:
+		return !e.matchField(span, Equal)
+	case NotRegex:
+		return !e.matchField(span, Regex)
+	}
+	return e.matchField(span, e.Operator)
+}
+
+func (e *Comparison) matchField(span *model.Span, op Operator) bool {
+	switch e.Field.Kind {
+	case ServiceField:
+		return span.Process != nil && e.matchString(op, span.Process.ServiceName)
+	case OperationField:
+		return e.matchString(op, span.OperationName)
+	case DurationField:
+		return compare(op, float64(span.Duration), float64(e.duration))
+	}
+	if e.Field.Kind != ProcessTagField && e.matchTags(op, span.Tags) {
+		return true
+	}
+	if e.Field.Kind != SpanTagField && span.Process != nil && e.matchTags(op, span.Process.Tags) {
+		return true
+	}
+	if e.Field.Kind == TagField {
+		for _, log := range span.Logs {
+			if e.matchTags(op, log.Fields) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Comparison) matchTags(op Operator, tags []model.KeyValue) bool {
+	for _, tag := range tags {
+		if tag.Key == e.Field.Key && e.matchTag(op, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Comparison) matchTag(op Operator, tag model.KeyValue) bool {
+	switch op {
+	case Greater, GreaterOrEqual, Less, LessOrEqual:
+		value, ok := tagNumber(tag)
+		return ok && compare(op, value, e.number)
+	}
+	return e.matchString(op, tag.AsString())
+}
+
+func (e *Comparison) matchString(op Operator, value string) bool {
+	switch op {
+	case Equal:
+		return value == e.Value
+	case Regex:
+		return e.regex.MatchString(value)
+	case Prefix:
+		return strings.HasPrefix(value, e.Value)
+	}
+	return false
+}
+
+func tagNumber(tag model.KeyValue) (float64, bool) {
+	switch tag.VType {
+	case model.Int64Type:
+		return float64(tag.Int64()), true
+	case model.Float64Type:
+		return tag.Float64(), true
+	case model.StringType:
+		value, err := strconv.ParseFloat(tag.VStr, 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+func compare(op Operator, left, right float64) bool {
+	switch op {
+	case Equal:
+		return left == right
+	case Greater:
+		return left > right
+	case GreaterOrEqual:
+		return left >= right
+	case Less:
+		return left < right
+	case LessOrEqual:
+		return left <= right
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func makeTrace() *model.Trace {
+	return &model.Trace{
+		Spans: []*model.Span{
+			{
+				OperationName: "GET /api/orders",
+				Duration:      2 * time.Second,
+				Process: model.NewProcess("frontend", []model.KeyValue{
+					model.String("hostname", "host-1"),
+				}),
+				Tags: []model.KeyValue{
+					model.Int64("http.status_code", 200),
+					model.String("span.kind", "server"),
+				},
+			},
+			{
+				OperationName: "SELECT",
+				Duration:      500 * time.Millisecond,
+				Process:       model.NewProcess("mysql", nil),
+				Tags: []model.KeyValue{
+					model.Bool("error", true),
+					model.Float64("rows", 12.5),
+					model.String("retries", "3"),
+				},
+				Logs: []model.Log{
+					{Fields: []model.KeyValue{model.String("event", "timeout")}},
+				},
+			},
+		},
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		query   string
+		matches bool
+	}{
+		{query: "service=frontend", matches: true},
+		{query: "service=backend", matches: false},
+		{query: "service!=frontend", matches: true}, // the mysql span
+		{query: "service^=front", matches: true},
+		{query: "service=~front.*", matches: true},
+		{query: "service=~front", matches: false}, // regex must match the whole value
+		{query: `service!~"frontend|mysql"`, matches: false},
+		{query: "operation=SELECT", matches: true},
+		{query: "duration>1s", matches: true},
+		{query: "duration>2s", matches: false},
+		{query: "duration>=2s", matches: true},
+		{query: "duration<500ms", matches: false},
+		{query: "duration<=500ms", matches: true},
+		{query: "duration=500ms", matches: true},
+		{query: "duration!=500ms", matches: true},
+		{query: "span.http.status_code>=500", matches: false},
+		{query: "span.http.status_code<300", matches: true},
+		{query: "span.http.status_code=200", matches: true},
+		{query: "span.rows>12", matches: true},
+		{query: "span.retries>=3", matches: true},
+		{query: "span.error>0", matches: false}, // bool tags are not numbers
+		{query: "span.span.kind=server", matches: true},
+		{query: "span.hostname=host-1", matches: false},
+		{query: "process.hostname=host-1", matches: true},
+		{query: "tag.hostname=host-1", matches: true},
+		{query: "span.event=timeout", matches: false},
+		{query: "tag.event=timeout", matches: true},
+		{query: "tag.error=true", matches: true},
+		{query: "tag.missing=x", matches: false},
+		{query: "tag.missing!=x", matches: true},
+		// any-span vs same-span semantics
+		{query: "service=frontend AND tag.error=true", matches: true},
+		{query: "{service=frontend AND tag.error=true}", matches: false},
+		{query: "{service=mysql AND tag.error=true}", matches: true},
+		{query: "{service=frontend AND (tag.error=true OR duration>1s)}", matches: true},
+		{query: "{service=frontend} AND {{tag.error=true}}", matches: true},
+		// negation
+		{query: "NOT tag.error=true", matches: false},
+		{query: "{NOT tag.error=true}", matches: true},
+		{query: "NOT service=backend", matches: true},
+		{query: "NOT {service=mysql AND duration>1s}", matches: true},
+		{query: "service=backend OR operation^=GET", matches: true},
+		{query: "service=frontend AND span.http.status_code>=500 AND (tag.error=true OR duration>1s)", matches: false},
+	}
+	trace := makeTrace()
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.matches, q.Matches(trace))
+		})
+	}
+}
+
+func TestMatchesSpanWithoutProcess(t *testing.T) {
+	q, err := Parse("service=foo OR process.hostname=bar")
+	require.NoError(t, err)
+	assert.False(t, q.Matches(&model.Trace{Spans: []*model.Span{{}}}))
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	keywordAnd = "AND"
+	keywordOr  = "OR"
+	keywordNot = "NOT"
+
+	// MaxQueryLength is the maximum length in bytes of a query.
+	MaxQueryLength = 16 * 1024
+	// MaxDepth is the maximum nesting depth of the NOT operators, parentheses and braces of a query,
+	// which bounds the recursion of the parser.
+	MaxDepth = 64
+)
+
+var errEmptyQuery = errors.New("empty query")
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+// Parse parses a query written in the trace query language.
+func Parse(text string) (*Query, error) {
+	if len(text) > MaxQueryLength {
+		return nil, fmt.Errorf("query of %d bytes exceeds the maximum length of %d bytes", len(text), MaxQueryLength)
+	}
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, errEmptyQuery
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, unexpected(t)
+	}
+	return &Query{Root: root}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	operands, err := p.parseOperands(keywordOr, p.parseAnd)
+	if err != nil || len(operands) == 1 {
+		return firstOperand(operands), err
+	}
+	return &Or{Operands: operands}, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	operands, err := p.parseOperands(keywordAnd, p.parseUnary)
+	if err != nil || len(operands) == 1 {
+		return firstOperand(operands), err
+	}
+	return &And{Operands: operands}, nil
+}
+
+// parseOperands parses a list of operands separated by the keyword.
+func (p *parser) parseOperands(keyword string, parseOperand func() (Expression, error)) ([]Expression, error) {
+	var operands []Expression
+	for {
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.peek().isKeyword(keyword) {
+			return operands, nil
+		}
+		p.next()
+	}
+}
+
+func firstOperand(operands []Expression) Expression {
+	if len(operands) == 0 {
+		return nil
+	}
+	return operands[0]
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	t := p.peek()
+	if t.isKeyword(keywordNot) || t.typ == tokenLeftParen || t.typ == tokenLeftBrace {
+		if p.depth == MaxDepth {
+			return nil, fmt.Errorf("query nested deeper than %d levels at position %d", MaxDepth, t.pos)
+		}
+		p.depth++
+		defer func() { p.depth-- }()
+	}
+	switch {
+	case t.isKeyword(keywordNot):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	case t.typ == tokenLeftParen:
+		p.next()
+		return p.parseGroup(tokenRightParen, func(e Expression) Expression { return e })
+	case t.typ == tokenLeftBrace:
+		p.next()
+		return p.parseGroup(tokenRightBrace, func(e Expression) Expression { return &SameSpan{Operand: e} })
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseGroup(closing tokenType, wrap func(Expression) Expression) (Expression, error) {
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.typ != closing {
+		return nil, unexpected(t)
+	}
+	return wrap(e), nil
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	fieldToken := p.next()
+	if fieldToken.typ != tokenWord || isReserved(fieldToken.value) {
+		return nil, unexpected(fieldToken)
+	}
+	field, err := parseField(fieldToken.value)
+	if err != nil {
+		return nil, fmt.Errorf("%w at position %d", err, fieldToken.pos)
+	}
+	opToken := p.next()
+	if opToken.typ != tokenOperator {
+		return nil, unexpected(opToken)
+	}
+	valueToken := p.next()
+	if valueToken.typ != tokenString && (valueToken.typ != tokenWord || isReserved(valueToken.value)) {
+		return nil, unexpected(valueToken)
+	}
+	c, err := NewComparison(field, Operator(opToken.value), valueToken.value)
+	if err != nil {
+		return nil, fmt.Errorf("%w at position %d", err, fieldToken.pos)
+	}
+	return c, nil
+}
+
+func parseField(name string) (Field, error) {
+	switch name {
+	case "service":
+		return Field{Kind: ServiceField}, nil
+	case "operation":
+		return Field{Kind: OperationField}, nil
+	case "duration":
+		return Field{Kind: DurationField}, nil
+	}
+	for prefix, kind := range map[string]FieldKind{"span.": SpanTagField, "process.": ProcessTagField, "tag.": TagField} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return Field{Kind: kind, Key: name[len(prefix):]}, nil
+		}
+	}
+	return Field{}, fmt.Errorf("unknown field '%s'", name)
+}
+
+// NewComparison creates a comparison, validating that the operator and the value are valid for the field.
+func NewComparison(field Field, op Operator, value string) (*Comparison, error) {
+	c := &Comparison{Field: field, Operator: op, Value: value}
+	var err error
+	switch op {
+	case Equal, NotEqual:
+		if field.Kind == DurationField {
+			c.duration, err = time.ParseDuration(value)
+		}
+	case Greater, GreaterOrEqual, Less, LessOrEqual:
+		switch field.Kind {
+		case ServiceField, OperationField:
+			return nil, fmt.Errorf("operator %s is not supported for field '%s'", op, field)
+		case DurationField:
+			c.duration, err = time.ParseDuration(value)
+		default:
+			c.number, err = strconv.ParseFloat(value, 64)
+		}
+	case Regex, NotRegex, Prefix:
+		if field.Kind == DurationField {
+			return nil, fmt.Errorf("operator %s is not supported for field '%s'", op, field)
+		}
+		if op != Prefix {
+			c.regex, err = regexp.Compile("^(?:" + value + ")$")
+		}
+	default:
+		return nil, fmt.Errorf("unknown operator '%s'", op)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for field '%s': %w", field, err)
+	}
+	return c, nil
+}
+
+func isReserved(word string) bool {
+	return strings.EqualFold(word, keywordAnd) || strings.EqualFold(word, keywordOr) || strings.EqualFold(word, keywordNot)
+}
+
+func unexpected(t token) error {
+	return fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
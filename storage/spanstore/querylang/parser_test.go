@@ -0,0 +1,153 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query     string
+		canonical string
+	}{
+		{query: "service=foo", canonical: "service=foo"},
+		{query: `  service = "foo bar" `, canonical: `service="foo bar"`},
+		{query: "operation^=GET", canonical: "operation^=GET"},
+		{query: `span.http.url=~"/api/.*"`, canonical: `span.http.url=~/api/.*`},
+		{query: `span.http.url=~"/api/(v1|v2)"`, canonical: `span.http.url=~"/api/(v1|v2)"`},
+		{query: "process.hostname!~host-[0-9]+", canonical: "process.hostname!~host-[0-9]+"},
+		{query: "duration>=1.5s", canonical: "duration>=1.5s"},
+		{query: "tag.error!=true", canonical: "tag.error!=true"},
+		{query: `tag.x="and"`, canonical: `tag.x="and"`},
+		{query: `tag.x=""`, canonical: `tag.x=""`},
+		{query: `tag.x="a\"b"`, canonical: `tag.x="a\"b"`},
+		{
+			query:     "service=foo AND span.http.status_code>=500 AND (tag.error=true OR duration>1s)",
+			canonical: "service=foo AND span.http.status_code>=500 AND (tag.error=true OR duration>1s)",
+		},
+		{
+			query:     "service=a or service=b and operation=c",
+			canonical: "service=a OR (service=b AND operation=c)",
+		},
+		{
+			query:     "((service=a))",
+			canonical: "service=a",
+		},
+		{
+			query:     "not {service=a AND NOT operation=b} AND service=c",
+			canonical: "NOT {service=a AND NOT operation=b} AND service=c",
+		},
+		{
+			query:     "NOT (service=a OR service=b)",
+			canonical: "NOT (service=a OR service=b)",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.canonical, q.String())
+
+			reparsed, err := Parse(q.String())
+			require.NoError(t, err)
+			assert.Equal(t, q.String(), reparsed.String())
+		})
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	q, err := Parse("service=a AND {span.k>1 OR NOT tag.e=true}")
+	require.NoError(t, err)
+	and, ok := q.Root.(*And)
+	require.True(t, ok)
+	require.Len(t, and.Operands, 2)
+	assert.Equal(t, &Comparison{Field: Field{Kind: ServiceField}, Operator: Equal, Value: "a"}, and.Operands[0])
+	sameSpan, ok := and.Operands[1].(*SameSpan)
+	require.True(t, ok)
+	or, ok := sameSpan.Operand.(*Or)
+	require.True(t, ok)
+	require.Len(t, or.Operands, 2)
+	assert.Equal(t, Field{Kind: SpanTagField, Key: "k"}, or.Operands[0].(*Comparison).Field)
+	assert.Equal(t, 1.0, or.Operands[0].(*Comparison).number)
+	not, ok := or.Operands[1].(*Not)
+	require.True(t, ok)
+	assert.Equal(t, Field{Kind: TagField, Key: "e"}, not.Operand.(*Comparison).Field)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{query: "", err: "empty query"},
+		{query: "   ", err: "empty query"},
+		{query: "service", err: "unexpected end of query at position 7"},
+		{query: "service=", err: "unexpected end of query at position 8"},
+		{query: "service=a AND", err: "unexpected end of query at position 13"},
+		{query: "service=a service=b", err: "unexpected 'service' at position 10"},
+		{query: "(service=a", err: "unexpected end of query at position 10"},
+		{query: "{service=a)", err: "unexpected ')' at position 10"},
+		{query: "service=AND", err: "unexpected 'AND' at position 8"},
+		{query: "AND=a", err: "unexpected 'AND' at position 0"},
+		{query: "foo=a", err: "unknown field 'foo' at position 0"},
+		{query: "span.=a", err: "unknown field 'span.' at position 0"},
+		{query: `service="a`, err: "unterminated string at position 8"},
+		{query: `service="\q"`, err: "invalid string at position 8: invalid syntax"},
+		{query: "service=a ~ b", err: "unexpected character '~' at position 10"},
+		{query: "service>a", err: "operator > is not supported for field 'service' at position 0"},
+		{query: "duration=~1s", err: "operator =~ is not supported for field 'duration' at position 0"},
+		{query: "duration>1", err: `invalid value for field 'duration': time: missing unit in duration "1" at position 0`},
+		{query: "span.code>abc", err: `invalid value for field 'span.code': strconv.ParseFloat: parsing "abc": invalid syntax at position 0`},
+		{query: `tag.x=~"("`, err: "invalid value for field 'tag.x': error parsing regexp: missing closing ): `^(?:()$` at position 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := Parse(test.query)
+			require.Error(t, err)
+			assert.Equal(t, test.err, err.Error())
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "service=a" + strings.Repeat(")", depth)
+	}
+	_, err := Parse(nested(MaxDepth))
+	require.NoError(t, err)
+	_, err = Parse("NOT " + strings.Repeat("{NOT ", MaxDepth/2-1) + "service=a" + strings.Repeat("}", MaxDepth/2-1))
+	require.NoError(t, err)
+
+	_, err = Parse(nested(MaxDepth + 1))
+	assert.EqualError(t, err, "query nested deeper than 64 levels at position 64")
+	_, err = Parse(strings.Repeat("NOT ", MaxDepth+1) + "service=a")
+	assert.EqualError(t, err, "query nested deeper than 64 levels at position 256")
+
+	// a query nested deep enough to overflow the stack without the limits
+	_, err = Parse(nested(1 << 20))
+	assert.EqualError(t, err, "query of 2097161 bytes exceeds the maximum length of 16384 bytes")
+	_, err = Parse(nested(MaxQueryLength/2 - 5))
+	assert.EqualError(t, err, "query nested deeper than 64 levels at position 64")
+}
+
+func TestNewComparisonUnknownOperator(t *testing.T) {
+	_, err := NewComparison(Field{Kind: ServiceField}, Operator("<>"), "a")
+	assert.EqualError(t, err, "unknown operator '<>'")
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"time"
+)
+
+// Pushdown holds conditions that can be answered by the span indexes of the storage backends.
+// All conditions apply to the same span.
+type Pushdown struct {
+	ServiceName   string
+	OperationName string
+	Tags          map[string]string
+	DurationMin   time.Duration
+	DurationMax   time.Duration
+}
+
+// Pushdown returns the most selective set of conditions such that every trace matching the query
+// contains a span satisfying all of them. Searching the indexes with these conditions returns a superset
+// of the matching traces, which must then be filtered with Matches.
+//
+// Operation and tag conditions are only returned together with a service name, because none of
+// the backends index them independently of the service.
+func (q *Query) Pushdown() Pushdown {
+	var best Pushdown
+	for _, conjunct := range conjuncts(q.Root) {
+		var candidate Pushdown
+		switch e := conjunct.(type) {
+		case *SameSpan:
+			for _, c := range spanConjuncts(e.Operand) {
+				if comparison, ok := c.(*Comparison); ok {
+					candidate.add(comparison)
+				}
+			}
+		case *Comparison:
+			candidate.add(e)
+		}
+		if candidate.ServiceName == "" {
+			candidate.OperationName = ""
+			candidate.Tags = nil
+		}
+		if candidate.selectivity() > best.selectivity() {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// conjuncts returns the operands that must all be satisfied for e to be satisfied.
+func conjuncts(e Expression) []Expression {
+	if and, ok := e.(*And); ok {
+		var result []Expression
+		for _, operand := range and.Operands {
+			result = append(result, conjuncts(operand)...)
+		}
+		return result
+	}
+	return []Expression{e}
+}
+
+// spanConjuncts is like conjuncts for an expression evaluated against a single span,
+// where nested braces have no effect.
+func spanConjuncts(e Expression) []Expression {
+	var result []Expression
+	for _, c := range conjuncts(e) {
+		if sameSpan, ok := c.(*SameSpan); ok {
+			result = append(result, spanConjuncts(sameSpan.Operand)...)
+		} else {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (p *Pushdown) add(c *Comparison) {
+	switch c.Field.Kind {
+	case ServiceField:
+		if c.Operator == Equal && p.ServiceName == "" {
+			p.ServiceName = c.Value
+		}
+	case OperationField:
+		if c.Operator == Equal && p.OperationName == "" {
+			p.OperationName = c.Value
+		}
+	case DurationField:
+		if c.Operator == Equal || c.Operator == Greater || c.Operator == GreaterOrEqual {
+			if c.duration > p.DurationMin {
+				p.DurationMin = c.duration
+			}
+		}
+		if c.Operator == Equal || c.Operator == Less || c.Operator == LessOrEqual {
+			if p.DurationMax == 0 || c.duration < p.DurationMax {
+				p.DurationMax = c.duration
+			}
+		}
+	default:
+		// the backends match the tags against span tags, process tags and log fields,
+		// which is a superset of any of the tag fields
+		if c.Operator == Equal {
+			if p.Tags == nil {
+				p.Tags = make(map[string]string)
+			}
+			if _, ok := p.Tags[c.Field.Key]; !ok {
+				p.Tags[c.Field.Key] = c.Value
+			}
+		}
+	}
+}
+
+func (p *Pushdown) selectivity() int {
+	selectivity := len(p.Tags)
+	if p.ServiceName != "" {
+		// service is required by most of the indexes, so it outweighs all other conditions
+		selectivity += 100
+	}
+	if p.OperationName != "" {
+		selectivity++
+	}
+	if p.DurationMin != 0 || p.DurationMax != 0 {
+		selectivity++
+	}
+	return selectivity
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querylang
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushdown(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Pushdown
+	}{
+		{
+			query:    "service=foo",
+			expected: Pushdown{ServiceName: "foo"},
+		},
+		{
+			// different spans may satisfy the conditions, only one of them can be pushed down
+			query:    "operation=bar AND service=foo AND tag.error=true",
+			expected: Pushdown{ServiceName: "foo"},
+		},
+		{
+			query: "{service=foo AND operation=bar AND span.http.status_code=500 AND duration>1s AND duration<=1m}",
+			expected: Pushdown{
+				ServiceName:   "foo",
+				OperationName: "bar",
+				Tags:          map[string]string{"http.status_code": "500"},
+				DurationMin:   time.Second,
+				DurationMax:   time.Minute,
+			},
+		},
+		{
+			query: "service=foo AND {service=foo AND process.ip=1.2.3.4} AND tag.x=y",
+			expected: Pushdown{
+				ServiceName: "foo",
+				Tags:        map[string]string{"ip": "1.2.3.4"},
+			},
+		},
+		{
+			query:    "{{service=foo} AND duration=1s AND duration>2s}",
+			expected: Pushdown{ServiceName: "foo", DurationMin: 2 * time.Second, DurationMax: time.Second},
+		},
+		{
+			// operation and tags cannot be looked up without the service
+			query:    "{operation=bar AND tag.x=y AND duration>1s}",
+			expected: Pushdown{DurationMin: time.Second},
+		},
+		{
+			query:    "service=foo OR service=bar",
+			expected: Pushdown{},
+		},
+		{
+			query:    "NOT service=foo",
+			expected: Pushdown{},
+		},
+		{
+			query:    "{service!=foo AND service^=f AND operation=~b.* AND tag.x>1 AND tag.y=z}",
+			expected: Pushdown{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, q.Pushdown())
+		})
+	}
+}
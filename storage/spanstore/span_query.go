@@ -0,0 +1,109 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"sort"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// ToTraceQueryParameters returns the parameters to find the traces containing the spans of the query,
+// for backends that search spans through their trace indexes. The number of traces is not limited.
+func (p *SpanQueryParameters) ToTraceQueryParameters() *TraceQueryParameters {
+	return &TraceQueryParameters{
+		ServiceName:   p.ServiceName,
+		OperationName: p.OperationName,
+		Tags:          p.Tags,
+		StartTimeMin:  p.StartTimeMin,
+		StartTimeMax:  p.StartTimeMax,
+		DurationMin:   p.DurationMin,
+		DurationMax:   p.DurationMax,
+	}
+}
+
+// MatchesSpan returns true if the span satisfies all the filters of the query. Tags are matched
+// against the tags of the span, the tags of its process and the fields of its logs.
+func MatchesSpan(span *model.Span, query *SpanQueryParameters) bool {
+	if query.ServiceName != "" && (span.Process == nil || query.ServiceName != span.Process.ServiceName) {
+		return false
+	}
+	if query.OperationName != "" && query.OperationName != span.OperationName {
+		return false
+	}
+	if query.DurationMin != 0 && span.Duration < query.DurationMin {
+		return false
+	}
+	if query.DurationMax != 0 && span.Duration > query.DurationMax {
+		return false
+	}
+	if !query.StartTimeMin.IsZero() && span.StartTime.Before(query.StartTimeMin) {
+		return false
+	}
+	if !query.StartTimeMax.IsZero() && span.StartTime.After(query.StartTimeMax) {
+		return false
+	}
+	for key, value := range query.Tags {
+		if !hasTag(span, key, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(span *model.Span, key, value string) bool {
+	if containsTag(span.Tags, key, value) {
+		return true
+	}
+	if span.Process != nil && containsTag(span.Process.Tags, key, value) {
+		return true
+	}
+	for _, log := range span.Logs {
+		if containsTag(log.Fields, key, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTag does not use KeyValues.FindByKey because there can be multiple tags with the same key.
+func containsTag(kvs model.KeyValues, key, value string) bool {
+	for _, kv := range kvs {
+		if kv.Key == key && kv.AsString() == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SortSpans sorts the spans as requested by the query and returns at most NumSpans of them,
+// or all of them if NumSpans is not set.
+func SortSpans(spans []*model.Span, query *SpanQueryParameters) []*model.Span {
+	less := func(i, j int) bool {
+		if query.SortBy == SortByDuration && spans[i].Duration != spans[j].Duration {
+			return spans[i].Duration < spans[j].Duration
+		}
+		return spans[i].StartTime.Before(spans[j].StartTime)
+	}
+	if query.Ascending {
+		sort.SliceStable(spans, less)
+	} else {
+		sort.SliceStable(spans, func(i, j int) bool { return less(j, i) })
+	}
+	if query.NumSpans > 0 && len(spans) > query.NumSpans {
+		spans = spans[:query.NumSpans]
+	}
+	return spans
+}
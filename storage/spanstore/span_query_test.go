@@ -0,0 +1,92 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestMatchesSpan(t *testing.T) {
+	baseTime := time.Unix(1000, 0)
+	span := &model.Span{
+		OperationName: "GET",
+		StartTime:     baseTime,
+		Duration:      time.Second,
+		Tags:          model.KeyValues{model.Int64("http.status_code", 500)},
+		Logs:          []model.Log{{Fields: model.KeyValues{model.String("event", "retry")}}},
+		Process:       model.NewProcess("frontend", model.KeyValues{model.String("hostname", "host-1")}),
+	}
+	tests := []struct {
+		name     string
+		query    SpanQueryParameters
+		expected bool
+	}{
+		{name: "no filters", expected: true},
+		{name: "service", query: SpanQueryParameters{ServiceName: "frontend"}, expected: true},
+		{name: "other service", query: SpanQueryParameters{ServiceName: "driver"}},
+		{name: "operation", query: SpanQueryParameters{OperationName: "GET"}, expected: true},
+		{name: "other operation", query: SpanQueryParameters{OperationName: "POST"}},
+		{name: "duration", query: SpanQueryParameters{DurationMin: time.Second, DurationMax: time.Second}, expected: true},
+		{name: "too fast", query: SpanQueryParameters{DurationMin: 2 * time.Second}},
+		{name: "too slow", query: SpanQueryParameters{DurationMax: time.Millisecond}},
+		{name: "time range", query: SpanQueryParameters{StartTimeMin: baseTime, StartTimeMax: baseTime}, expected: true},
+		{name: "too early", query: SpanQueryParameters{StartTimeMin: baseTime.Add(time.Second)}},
+		{name: "too late", query: SpanQueryParameters{StartTimeMax: baseTime.Add(-time.Second)}},
+		{
+			name: "span, process and log tags",
+			query: SpanQueryParameters{Tags: map[string]string{
+				"http.status_code": "500",
+				"hostname":         "host-1",
+				"event":            "retry",
+			}},
+			expected: true,
+		},
+		{name: "other tag value", query: SpanQueryParameters{Tags: map[string]string{"http.status_code": "200"}}},
+		{name: "missing tag", query: SpanQueryParameters{Tags: map[string]string{"error": "true"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, MatchesSpan(span, &test.query))
+		})
+	}
+}
+
+func TestSortSpans(t *testing.T) {
+	baseTime := time.Unix(1000, 0)
+	a := &model.Span{StartTime: baseTime, Duration: 3 * time.Second}
+	b := &model.Span{StartTime: baseTime.Add(time.Second), Duration: time.Second}
+	c := &model.Span{StartTime: baseTime.Add(2 * time.Second), Duration: 2 * time.Second}
+	tests := []struct {
+		name     string
+		query    SpanQueryParameters
+		expected []*model.Span
+	}{
+		{name: "latest first", expected: []*model.Span{c, b, a}},
+		{name: "oldest first", query: SpanQueryParameters{Ascending: true}, expected: []*model.Span{a, b, c}},
+		{name: "slowest first", query: SpanQueryParameters{SortBy: SortByDuration}, expected: []*model.Span{a, c, b}},
+		{name: "fastest first", query: SpanQueryParameters{SortBy: SortByDuration, Ascending: true}, expected: []*model.Span{b, c, a}},
+		{name: "limit", query: SpanQueryParameters{SortBy: SortByDuration, NumSpans: 2}, expected: []*model.Span{a, c}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, SortSpans([]*model.Span{a, b, c}, &test.query))
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+// DefaultDurationBuckets are the upper bounds of the duration histogram buckets
+// used when the query does not define them.
+var DefaultDurationBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// SpanStatsQueryParameters contains parameters of a span statistics query.
+type SpanStatsQueryParameters struct {
+	// ServiceName and OperationName restrict the statistics to a service or an operation, if set.
+	ServiceName   string
+	OperationName string
+	StartTimeMin  time.Time
+	StartTimeMax  time.Time
+	// GroupByOperation computes the statistics per operation instead of per service.
+	GroupByOperation bool
+	// GroupByTag computes the statistics per value of the span tag with this key, if set.
+	// The spans without this tag are not counted.
+	GroupByTag string
+	// Buckets are the increasing upper bounds of the duration histogram, DefaultDurationBuckets if not set.
+	Buckets []time.Duration
+}
+
+// DurationBuckets returns the upper bounds of the duration histogram of the query.
+func (p *SpanStatsQueryParameters) DurationBuckets() []time.Duration {
+	if len(p.Buckets) == 0 {
+		return DefaultDurationBuckets
+	}
+	return p.Buckets
+}
+
+// SpanStats are the statistics of a group of spans.
+type SpanStats struct {
+	ServiceName string
+	// OperationName is set when the statistics are grouped by operation.
+	OperationName string
+	// TagValue is set when the statistics are grouped by tag.
+	TagValue   string
+	Count      int64
+	ErrorCount int64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	// Histogram has one bucket per upper bound of the query, and a last bucket without upper bound.
+	Histogram []HistogramBucket
+}
+
+// HistogramBucket is the number of spans with a duration within [LowerBound, UpperBound).
+// The UpperBound of the last bucket of a histogram is zero, meaning that it is unbounded.
+type HistogramBucket struct {
+	LowerBound time.Duration
+	UpperBound time.Duration
+	Count      int64
+}
+
+// NewHistogram returns a histogram with empty buckets for the given upper bounds.
+func NewHistogram(upperBounds []time.Duration) []HistogramBucket {
+	histogram := make([]HistogramBucket, len(upperBounds)+1)
+	var lowerBound time.Duration
+	for i, upperBound := range upperBounds {
+		histogram[i] = HistogramBucket{LowerBound: lowerBound, UpperBound: upperBound}
+		lowerBound = upperBound
+	}
+	histogram[len(upperBounds)].LowerBound = lowerBound
+	return histogram
+}
+
+// SortSpanStats sorts the statistics by service name, operation name and tag value.
+func SortSpanStats(stats []SpanStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ServiceName != stats[j].ServiceName {
+			return stats[i].ServiceName < stats[j].ServiceName
+		}
+		if stats[i].OperationName != stats[j].OperationName {
+			return stats[i].OperationName < stats[j].OperationName
+		}
+		return stats[i].TagValue < stats[j].TagValue
+	})
+}
+
+// IsErrorSpan returns true if the span has the error tag set to true.
+func IsErrorSpan(span *model.Span) bool {
+	tag, ok := model.KeyValues(span.Tags).FindByKey("error")
+	return ok && tag.AsString() == "true"
+}
+
+type spanStatsKey struct {
+	serviceName   string
+	operationName string
+	tagValue      string
+}
+
+type spanStatsGroup struct {
+	durations []time.Duration
+	errors    int64
+}
+
+// SpanStatsAggregator computes span statistics by scanning the spans, for backends
+// that cannot aggregate them natively. The percentiles are exact.
+type SpanStatsAggregator struct {
+	query  *SpanStatsQueryParameters
+	groups map[spanStatsKey]*spanStatsGroup
+}
+
+// NewSpanStatsAggregator returns a SpanStatsAggregator for the query.
+func NewSpanStatsAggregator(query *SpanStatsQueryParameters) *SpanStatsAggregator {
+	return &SpanStatsAggregator{
+		query:  query,
+		groups: make(map[spanStatsKey]*spanStatsGroup),
+	}
+}
+
+// Add counts the span if it matches the query.
+func (a *SpanStatsAggregator) Add(span *model.Span) {
+	key, ok := a.key(span)
+	if !ok {
+		return
+	}
+	group, ok := a.groups[key]
+	if !ok {
+		group = &spanStatsGroup{}
+		a.groups[key] = group
+	}
+	group.durations = append(group.durations, span.Duration)
+	if IsErrorSpan(span) {
+		group.errors++
+	}
+}
+
+func (a *SpanStatsAggregator) key(span *model.Span) (spanStatsKey, bool) {
+	var key spanStatsKey
+	if span.Process != nil {
+		key.serviceName = span.Process.ServiceName
+	}
+	if a.query.ServiceName != "" && a.query.ServiceName != key.serviceName {
+		return key, false
+	}
+	if a.query.OperationName != "" && a.query.OperationName != span.OperationName {
+		return key, false
+	}
+	if !a.query.StartTimeMin.IsZero() && span.StartTime.Before(a.query.StartTimeMin) {
+		return key, false
+	}
+	if !a.query.StartTimeMax.IsZero() && span.StartTime.After(a.query.StartTimeMax) {
+		return key, false
+	}
+	if a.query.GroupByOperation {
+		key.operationName = span.OperationName
+	}
+	if a.query.GroupByTag != "" {
+		tag, ok := model.KeyValues(span.Tags).FindByKey(a.query.GroupByTag)
+		if !ok {
+			return key, false
+		}
+		key.tagValue = tag.AsString()
+	}
+	return key, true
+}
+
+// Stats returns the statistics of the spans added so far, sorted by service name, operation name and tag value.
+func (a *SpanStatsAggregator) Stats() []SpanStats {
+	if len(a.groups) == 0 {
+		return nil
+	}
+	buckets := a.query.DurationBuckets()
+	stats := make([]SpanStats, 0, len(a.groups))
+	for key, group := range a.groups {
+		durations := group.durations
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		histogram := NewHistogram(buckets)
+		for _, d := range durations {
+			// the first bucket with an upper bound above the duration, or the last bucket
+			i := sort.Search(len(buckets), func(i int) bool { return d < buckets[i] })
+			histogram[i].Count++
+		}
+		stats = append(stats, SpanStats{
+			ServiceName:   key.serviceName,
+			OperationName: key.operationName,
+			TagValue:      key.tagValue,
+			Count:         int64(len(durations)),
+			ErrorCount:    group.errors,
+			P50:           percentile(durations, 0.5),
+			P90:           percentile(durations, 0.9),
+			P99:           percentile(durations, 0.99),
+			Histogram:     histogram,
+		})
+	}
+	SortSpanStats(stats)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations, for p in [0, 1].
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 The Jaeger Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jaegertracing/jaeger/model"
+)
+
+func TestSpanStatsAggregator(t *testing.T) {
+	baseTime := time.Unix(1000, 0)
+	span := func(service, operation string, duration time.Duration, tags ...model.KeyValue) *model.Span {
+		return &model.Span{
+			OperationName: operation,
+			StartTime:     baseTime,
+			Duration:      duration,
+			Tags:          tags,
+			Process:       model.NewProcess(service, nil),
+		}
+	}
+	spans := []*model.Span{
+		span("frontend", "GET", 2*time.Millisecond, model.String("http.method", "GET")),
+		span("frontend", "GET", 20*time.Millisecond, model.String("http.method", "GET"), model.Bool("error", true)),
+		span("frontend", "POST", 200*time.Millisecond, model.String("http.method", "POST")),
+		span("frontend", "POST", 2*time.Second),
+		span("driver", "find", 5*time.Millisecond, model.String("error", "true")),
+	}
+	late := span("driver", "find", time.Hour)
+	late.StartTime = baseTime.Add(time.Hour)
+	spans = append(spans, late)
+
+	aggregate := func(query *SpanStatsQueryParameters) []SpanStats {
+		aggregator := NewSpanStatsAggregator(query)
+		for _, span := range spans {
+			aggregator.Add(span)
+		}
+		return aggregator.Stats()
+	}
+
+	buckets := []time.Duration{10 * time.Millisecond, time.Second}
+	stats := aggregate(&SpanStatsQueryParameters{StartTimeMax: baseTime, Buckets: buckets})
+	require.Len(t, stats, 2)
+	assert.Equal(t, SpanStats{
+		ServiceName: "driver",
+		Count:       1,
+		ErrorCount:  1,
+		P50:         5 * time.Millisecond,
+		P90:         5 * time.Millisecond,
+		P99:         5 * time.Millisecond,
+		Histogram: []HistogramBucket{
+			{UpperBound: 10 * time.Millisecond, Count: 1},
+			{LowerBound: 10 * time.Millisecond, UpperBound: time.Second},
+			{LowerBound: time.Second},
+		},
+	}, stats[0])
+	assert.Equal(t, SpanStats{
+		ServiceName: "frontend",
+		Count:       4,
+		ErrorCount:  1,
+		P50:         20 * time.Millisecond,
+		P90:         2 * time.Second,
+		P99:         2 * time.Second,
+		Histogram: []HistogramBucket{
+			{UpperBound: 10 * time.Millisecond, Count: 1},
+			{LowerBound: 10 * time.Millisecond, UpperBound: time.Second, Count: 2},
+			{LowerBound: time.Second, Count: 1},
+		},
+	}, stats[1])
+
+	stats = aggregate(&SpanStatsQueryParameters{ServiceName: "frontend", GroupByOperation: true})
+	require.Len(t, stats, 2)
+	assert.Equal(t, "GET", stats[0].OperationName)
+	assert.EqualValues(t, 2, stats[0].Count)
+	assert.Equal(t, "POST", stats[1].OperationName)
+	assert.Len(t, stats[1].Histogram, len(DefaultDurationBuckets)+1)
+
+	stats = aggregate(&SpanStatsQueryParameters{OperationName: "GET", GroupByTag: "error"})
+	require.Len(t, stats, 1)
+	assert.Equal(t, "true", stats[0].TagValue)
+	assert.Empty(t, stats[0].OperationName)
+
+	stats = aggregate(&SpanStatsQueryParameters{GroupByOperation: true, GroupByTag: "http.method"})
+	require.Len(t, stats, 2)
+	assert.Equal(t, SpanStats{ServiceName: "frontend", OperationName: "GET", TagValue: "GET"}, SpanStats{
+		ServiceName:   stats[0].ServiceName,
+		OperationName: stats[0].OperationName,
+		TagValue:      stats[0].TagValue,
+	})
+	assert.Equal(t, "POST", stats[1].TagValue)
+
+	assert.Nil(t, aggregate(&SpanStatsQueryParameters{ServiceName: "unknown"}))
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]time.Duration, 100)
+	for i := range values {
+		values[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(1), percentile(values, 0))
+	assert.Equal(t, time.Duration(50), percentile(values, 0.5))
+	assert.Equal(t, time.Duration(99), percentile(values, 0.99))
+	assert.Equal(t, time.Duration(100), percentile(values, 1))
+}